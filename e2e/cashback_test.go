@@ -0,0 +1,193 @@
+//go:build e2e
+
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/e2e"
+)
+
+// These scenarios follow cashback from the API through mint-consumer, to the
+// summary it keeps and the tokens minted on the simulated chain.
+
+func TestApprovedCashbackReachesMintConsumer(t *testing.T) {
+	m := newMerchant(t, 5)
+	u := newUser(t)
+	wallet := verifyWallet(t, u)
+	p := newPurchase(t, u, m, 200)
+
+	c := calculate(t, p)
+	if c.Status != "approved" {
+		t.Fatalf("status %q, want approved", c.Status)
+	}
+	if c.WalletAddress != wallet.Address() {
+		t.Fatalf("paid out to %q, want %q", c.WalletAddress, wallet.Address())
+	}
+
+	eventually(t, 10*time.Second, "cashback in mint-consumer summary", func() bool {
+		s := userSummary(t, u.ID)
+		return s.ApprovedCount == 1 && s.ApprovedAmount == c.Amount
+	})
+
+	// Calculating again returns the same cashback and publishes nothing.
+	if again := calculate(t, p); again.ID != c.ID {
+		t.Fatalf("recalculated cashback %s, want %s", again.ID, c.ID)
+	}
+	never(t, time.Second, "cashback counted twice", func() bool {
+		return userSummary(t, u.ID).ApprovedCount != 1
+	})
+}
+
+func TestApprovedCashbackIsMinted(t *testing.T) {
+	m := newMerchant(t, 5)
+	u := newUser(t)
+	wallet := verifyWallet(t, u)
+	p := newPurchase(t, u, m, 200)
+	c := calculate(t, p)
+
+	want := tokenUnits(c.Amount)
+	eventually(t, 10*time.Second, "tokens minted to the payout wallet", func() bool {
+		return platform.Chain.Balance(wallet.Address()).Cmp(want) == 0
+	})
+	r := mintRequestOf(t, c.ID)
+	if r.Status != "completed" || r.RetryCount != 0 || !platform.Chain.Succeeded(r.TransactionHash) {
+		t.Fatalf("mint request is %+v, want completed by a mined transaction", r)
+	}
+	eventually(t, 10*time.Second, "cashback marked minted", func() bool {
+		return cashbackStatus(t, c.ID) == "minted"
+	})
+
+	// Calculating again mints nothing more.
+	calculate(t, p)
+	never(t, time.Second, "cashback minted twice", func() bool {
+		return platform.Chain.Balance(wallet.Address()).Cmp(want) != 0
+	})
+}
+
+func TestMintIsRetriedAfterAChainFailure(t *testing.T) {
+	m := newMerchant(t, 5)
+	u := newUser(t)
+	wallet := verifyWallet(t, u)
+	p := newPurchase(t, u, m, 100)
+
+	platform.Chain.Fail("connection reset")
+	t.Cleanup(platform.Chain.Recover)
+	c := calculate(t, p)
+
+	eventually(t, 10*time.Second, "mint failed while the chain is down", func() bool {
+		r := mintRequestOf(t, c.ID)
+		return r.Status == "failed" && r.RetryCount >= 1
+	})
+	if r := mintRequestOf(t, c.ID); r.ErrorCode != "NOT_BROADCAST" {
+		t.Fatalf("mint failed with %q, want NOT_BROADCAST", r.ErrorCode)
+	}
+	if balance := platform.Chain.Balance(wallet.Address()); balance.Sign() != 0 {
+		t.Fatalf("balance %s while the chain was down", balance)
+	}
+	if status := cashbackStatus(t, c.ID); status != "approved" {
+		t.Fatalf("cashback is %q while its mint is retried, want approved", status)
+	}
+
+	platform.Chain.Recover()
+	want := tokenUnits(c.Amount)
+	eventually(t, 10*time.Second, "mint retried once the chain recovers", func() bool {
+		return platform.Chain.Balance(wallet.Address()).Cmp(want) == 0
+	})
+	if r := mintRequestOf(t, c.ID); r.Status != "completed" || r.RetryCount < 1 {
+		t.Fatalf("mint request is %+v, want completed after a retry", r)
+	}
+	eventually(t, 10*time.Second, "cashback marked minted", func() bool {
+		return cashbackStatus(t, c.ID) == "minted"
+	})
+}
+
+func TestHeldCashbackIsReleasedOnWalletVerification(t *testing.T) {
+	m := newMerchant(t, 10)
+	u := newUser(t)
+	p := newPurchase(t, u, m, 50)
+
+	c := calculate(t, p)
+	if c.Status != "pending" {
+		t.Fatalf("status %q, want pending until a wallet is verified", c.Status)
+	}
+	never(t, time.Second, "held cashback reached mint-consumer", func() bool {
+		return userSummary(t, u.ID).ApprovedCount != 0
+	})
+
+	verifyWallet(t, u)
+
+	eventually(t, 10*time.Second, "released cashback in mint-consumer summary", func() bool {
+		s := userSummary(t, u.ID)
+		return s.ApprovedCount == 1 && s.ApprovedAmount == c.Amount
+	})
+}
+
+func TestOutboxGivesUpWhileNATSIsDown(t *testing.T) {
+	m := newMerchant(t, 5)
+	u := newUser(t)
+	verifyWallet(t, u)
+	p := newPurchase(t, u, m, 100)
+
+	platform.NATS.Stop()
+	t.Cleanup(func() {
+		if err := platform.NATS.Restart(); err != nil {
+			t.Fatal(err)
+		}
+		eventually(t, 30*time.Second, "cashback service ready again", func() bool {
+			status, err := e2e.Readiness(context.Background(), platform.CashbackHealth)
+			return err == nil && status == http.StatusOK
+		})
+	})
+
+	// The cashback is committed with its event in the outbox, so the request
+	// succeeds without NATS.
+	c := calculate(t, p)
+	if c.Status != "approved" {
+		t.Fatalf("status %q, want approved", c.Status)
+	}
+
+	var retries, maxRetries int
+	var failed bool
+	eventually(t, 60*time.Second, "outbox event marked failed", func() bool {
+		err := platform.CashbackDB.QueryRow(`
+			SELECT retry_count, max_retries, failed FROM outbox_events
+			WHERE event_type = 'cashback.approved'
+			  AND convert_from(payload, 'UTF8')::jsonb->>'cashback_id' = $1`, c.ID,
+		).Scan(&retries, &maxRetries, &failed)
+		return err == nil && failed
+	})
+	if retries != maxRetries {
+		t.Fatalf("gave up after %d retries, want %d", retries, maxRetries)
+	}
+}
+
+func TestChainOutageFailsAdapterReadiness(t *testing.T) {
+	ctx := context.Background()
+	readiness := func(want int) func() bool {
+		return func() bool {
+			status, err := e2e.Readiness(ctx, platform.AdapterHealth)
+			return err == nil && status == want
+		}
+	}
+
+	eventually(t, 5*time.Second, "adapter ready", readiness(http.StatusOK))
+	calls := platform.Chain.Calls("eth_blockNumber")
+	if calls == 0 {
+		t.Fatal("adapter never asked the chain for its block number")
+	}
+
+	platform.Chain.Fail("header not found")
+	t.Cleanup(platform.Chain.Recover)
+	eventually(t, 5*time.Second, "adapter not ready while the chain fails", readiness(http.StatusServiceUnavailable))
+
+	platform.Chain.Recover()
+	platform.Chain.Mine(1)
+	eventually(t, 5*time.Second, "adapter ready once the chain recovers", readiness(http.StatusOK))
+	if platform.Chain.Calls("eth_blockNumber") <= calls {
+		t.Fatal("adapter did not check the chain again")
+	}
+}
@@ -0,0 +1,480 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// Chain is an in-memory chain node speaking the subset of Ethereum JSON-RPC
+// the adapter uses, with one ERC-20 token deployed at TokenAddress.
+//
+// Like a development node it mines every transaction it accepts into a block
+// of its own; Mine adds empty blocks. Only the minter may mint the token.
+// Fail makes every call fail until Recover, to simulate an unreachable or
+// broken node.
+type Chain struct {
+	server *httptest.Server
+	minter *secp256k1.PrivateKey
+
+	mu           sync.Mutex
+	block        uint64
+	failure      *rpcError
+	calls        map[string]int
+	nonces       map[string]uint64
+	balances     map[string]*big.Int
+	transactions map[string]*minedTransaction
+}
+
+type (
+	rpcRequest struct {
+		JSONRPC string            `json:"jsonrpc"`
+		ID      json.RawMessage   `json:"id"`
+		Method  string            `json:"method"`
+		Params  []json.RawMessage `json:"params"`
+	}
+
+	rpcResponse struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  any             `json:"result"`
+		Error   *rpcError       `json:"error,omitempty"`
+	}
+
+	rpcError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	// minedTransaction is a transaction the chain accepted, and its receipt.
+	minedTransaction struct {
+		hash      string
+		from      string
+		nonce     uint64
+		block     uint64
+		succeeded bool
+	}
+)
+
+const (
+	// ChainID is the chain ID the simulated node reports.
+	ChainID = 31337
+	// TokenAddress is where the token contract is deployed.
+	TokenAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+
+	// gasPrice is the gas price the node suggests, 1 gwei.
+	gasPrice = 1_000_000_000
+	// gasUsed is what every transaction is charged.
+	gasUsed = 21_000
+)
+
+// Token function selectors.
+var (
+	mintSelector      = ethereum.Keccak256([]byte("mint(address,uint256)"))[:4]
+	transferSelector  = ethereum.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	balanceOfSelector = ethereum.Keccak256([]byte("balanceOf(address)"))[:4]
+)
+
+// NewChain starts a node at block 1 with a fresh minter key. Close stops it.
+func NewChain() (*Chain, error) {
+	minter, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	c := &Chain{
+		minter:       minter,
+		block:        1,
+		calls:        make(map[string]int),
+		nonces:       make(map[string]uint64),
+		balances:     make(map[string]*big.Int),
+		transactions: make(map[string]*minedTransaction),
+	}
+	c.server = httptest.NewServer(http.HandlerFunc(c.serve))
+	return c, nil
+}
+
+// URL is the JSON-RPC endpoint, for CHAIN_RPC_URL.
+func (c *Chain) URL() string {
+	return c.server.URL
+}
+
+// MinterKey is the hex private key of the account allowed to mint, for
+// CHAIN_MINTER_KEY.
+func (c *Chain) MinterKey() string {
+	return hex.EncodeToString(c.minter.Serialize())
+}
+
+// Close stops the node.
+func (c *Chain) Close() {
+	c.server.Close()
+}
+
+// Mine advances the chain by n blocks.
+func (c *Chain) Mine(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.block += n
+}
+
+// Fail makes every call return a JSON-RPC error with message until Recover.
+func (c *Chain) Fail(message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failure = &rpcError{Code: -32000, Message: message}
+}
+
+// Recover undoes Fail.
+func (c *Chain) Recover() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failure = nil
+}
+
+// Calls returns how many times method was called, failed calls included.
+func (c *Chain) Calls(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+// Balance returns the token balance of address.
+func (c *Chain) Balance(address string) *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return new(big.Int).Set(c.balance(address))
+}
+
+// Succeeded reports whether the transaction with hash was mined and did not
+// revert.
+func (c *Chain) Succeeded(hash string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx, ok := c.transactions[strings.ToLower(hash)]
+	return ok && tx.succeeded
+}
+
+func (c *Chain) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON-RPC request", http.StatusBadRequest)
+		return
+	}
+
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	resp.Result, resp.Error = c.call(req.Method, req.Params)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (c *Chain) call(method string, params []json.RawMessage) (any, *rpcError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls[method]++
+	if c.failure != nil {
+		return nil, c.failure
+	}
+
+	switch method {
+	case "eth_blockNumber":
+		return quantity(c.block), nil
+	case "eth_chainId":
+		return quantity(ChainID), nil
+	case "eth_gasPrice":
+		return quantity(gasPrice), nil
+	case "eth_getTransactionCount":
+		var address string
+		if err := param(params, 0, &address); err != nil {
+			return nil, err
+		}
+		return quantity(c.nonces[strings.ToLower(address)]), nil
+	case "eth_call":
+		var call struct{ To, Data string }
+		if err := param(params, 0, &call); err != nil {
+			return nil, err
+		}
+		return c.ethCall(call.To, call.Data)
+	case "eth_sendRawTransaction":
+		var raw string
+		if err := param(params, 0, &raw); err != nil {
+			return nil, err
+		}
+		return c.sendRawTransaction(raw)
+	case "eth_getTransactionByHash":
+		var hash string
+		if err := param(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		tx, ok := c.transactions[strings.ToLower(hash)]
+		if !ok {
+			return nil, nil
+		}
+		return map[string]string{
+			"hash":        tx.hash,
+			"from":        tx.from,
+			"nonce":       quantity(tx.nonce),
+			"blockNumber": quantity(tx.block),
+		}, nil
+	case "eth_getTransactionReceipt":
+		var hash string
+		if err := param(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		tx, ok := c.transactions[strings.ToLower(hash)]
+		if !ok {
+			return nil, nil
+		}
+		status := "0x0"
+		if tx.succeeded {
+			status = "0x1"
+		}
+		return map[string]string{
+			"transactionHash": tx.hash,
+			"blockNumber":     quantity(tx.block),
+			"gasUsed":         quantity(gasUsed),
+			"status":          status,
+		}, nil
+	default:
+		return nil, &rpcError{Code: -32601, Message: "the method " + method + " does not exist"}
+	}
+}
+
+// ethCall answers balanceOf on the token; other calls revert.
+func (c *Chain) ethCall(to, data string) (any, *rpcError) {
+	input, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if err != nil || !strings.EqualFold(to, TokenAddress) || len(input) != 36 || !bytes.Equal(input[:4], balanceOfSelector) {
+		return nil, &rpcError{Code: 3, Message: "execution reverted"}
+	}
+	owner := "0x" + hex.EncodeToString(input[16:36])
+	balance := make([]byte, 32)
+	c.balance(owner).FillBytes(balance)
+	return "0x" + hex.EncodeToString(balance), nil
+}
+
+// sendRawTransaction checks a signed legacy transaction and mines it into a
+// new block. Transactions that fail the token's checks are mined reverted.
+func (c *Chain) sendRawTransaction(raw string) (any, *rpcError) {
+	encoded, err := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+	if err != nil {
+		return nil, invalidTransaction(err)
+	}
+	tx, err := decodeTransaction(encoded)
+	if err != nil {
+		return nil, invalidTransaction(err)
+	}
+
+	hash := "0x" + hex.EncodeToString(ethereum.Keccak256(encoded))
+	if _, ok := c.transactions[hash]; ok {
+		return nil, &rpcError{Code: -32000, Message: "already known"}
+	}
+	if want := c.nonces[tx.from]; tx.nonce != want {
+		return nil, &rpcError{Code: -32000, Message: fmt.Sprintf("invalid nonce: have %d, want %d", tx.nonce, want)}
+	}
+
+	c.nonces[tx.from]++
+	c.block++
+	c.transactions[hash] = &minedTransaction{
+		hash:      hash,
+		from:      tx.from,
+		nonce:     tx.nonce,
+		block:     c.block,
+		succeeded: c.execute(tx),
+	}
+	return hash, nil
+}
+
+// execute applies a call to the token and reports whether it succeeded.
+func (c *Chain) execute(tx transaction) bool {
+	if !strings.EqualFold(tx.to, TokenAddress) {
+		return true
+	}
+	if len(tx.data) != 68 {
+		return false
+	}
+	to := "0x" + hex.EncodeToString(tx.data[16:36])
+	amount := new(big.Int).SetBytes(tx.data[36:68])
+
+	switch {
+	case bytes.Equal(tx.data[:4], mintSelector):
+		if tx.from != strings.ToLower(addressOf(c.minter.PubKey())) {
+			return false
+		}
+	case bytes.Equal(tx.data[:4], transferSelector):
+		from := c.balance(tx.from)
+		if from.Cmp(amount) < 0 {
+			return false
+		}
+		from.Sub(from, amount)
+	default:
+		return false
+	}
+	balance := c.balance(to)
+	balance.Add(balance, amount)
+	return true
+}
+
+// balance returns the balance of address, which the caller may change.
+func (c *Chain) balance(address string) *big.Int {
+	key := strings.ToLower(address)
+	if _, ok := c.balances[key]; !ok {
+		c.balances[key] = new(big.Int)
+	}
+	return c.balances[key]
+}
+
+// transaction is a decoded legacy transaction. Addresses are lower case.
+type transaction struct {
+	from  string
+	nonce uint64
+	to    string
+	data  []byte
+}
+
+// decodeTransaction decodes an EIP-155 signed legacy transaction and recovers
+// its sender.
+func decodeTransaction(raw []byte) (transaction, error) {
+	item, rest, err := decodeRLP(raw)
+	if err != nil {
+		return transaction{}, err
+	}
+	fields, ok := item.([]any)
+	if len(rest) > 0 || !ok || len(fields) != 9 {
+		return transaction{}, errors.New("not a legacy transaction")
+	}
+	values := make([][]byte, len(fields))
+	for i, field := range fields {
+		if values[i], ok = field.([]byte); !ok {
+			return transaction{}, errors.New("not a legacy transaction")
+		}
+	}
+	if len(values[3]) != 20 {
+		return transaction{}, errors.New("contract creation is not supported")
+	}
+
+	// v is 35 + 2 * chain ID + recovery id.
+	v := new(big.Int).SetBytes(values[6]).Uint64()
+	if v < 35 || (v-35)/2 != ChainID {
+		return transaction{}, errors.New("invalid chain id")
+	}
+	compact := make([]byte, 65)
+	compact[0] = byte(27 + (v-35)%2)
+	new(big.Int).SetBytes(values[7]).FillBytes(compact[1:33])
+	new(big.Int).SetBytes(values[8]).FillBytes(compact[33:65])
+
+	unsigned := append(append([]any{}, fields[:6]...), big.NewInt(ChainID).Bytes(), []byte{}, []byte{})
+	pubKey, _, err := ecdsa.RecoverCompact(compact, ethereum.Keccak256(encodeRLP(unsigned)))
+	if err != nil {
+		return transaction{}, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	return transaction{
+		from:  strings.ToLower(addressOf(pubKey)),
+		nonce: new(big.Int).SetBytes(values[0]).Uint64(),
+		to:    "0x" + hex.EncodeToString(values[3]),
+		data:  values[5],
+	}, nil
+}
+
+// decodeRLP decodes the first item of b, a []byte string or a []any list,
+// and returns it with the bytes that follow.
+func decodeRLP(b []byte) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.New("rlp: unexpected end of input")
+	}
+	prefix := b[0]
+	if prefix < 0x80 {
+		return b[:1], b[1:], nil
+	}
+
+	offset, isList := byte(0x80), prefix >= 0xc0
+	if isList {
+		offset = 0xc0
+	}
+	size, start := int(prefix-offset), 1
+	if size > 55 {
+		lengthSize := size - 55
+		if len(b) < 1+lengthSize || lengthSize > 4 {
+			return nil, nil, errors.New("rlp: invalid length")
+		}
+		size = int(new(big.Int).SetBytes(b[1 : 1+lengthSize]).Int64())
+		start += lengthSize
+	}
+	if len(b) < start+size {
+		return nil, nil, errors.New("rlp: unexpected end of input")
+	}
+	payload, rest := b[start:start+size], b[start+size:]
+	if !isList {
+		return payload, rest, nil
+	}
+
+	var items []any
+	for len(payload) > 0 {
+		item, next, err := decodeRLP(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+		payload = next
+	}
+	return items, rest, nil
+}
+
+// encodeRLP encodes a []byte string or a []any list of them.
+func encodeRLP(item any) []byte {
+	header := func(offset byte, size int) []byte {
+		if size < 56 {
+			return []byte{offset + byte(size)}
+		}
+		length := big.NewInt(int64(size)).Bytes()
+		return append([]byte{offset + 55 + byte(len(length))}, length...)
+	}
+
+	if list, ok := item.([]any); ok {
+		var payload []byte
+		for _, element := range list {
+			payload = append(payload, encodeRLP(element)...)
+		}
+		return append(header(0xc0, len(payload)), payload...)
+	}
+	b := item.([]byte)
+	if len(b) == 1 && b[0] < 0x80 {
+		return b
+	}
+	return append(header(0x80, len(b)), b...)
+}
+
+func addressOf(key *secp256k1.PublicKey) string {
+	hash := ethereum.Keccak256(key.SerializeUncompressed()[1:])
+	return ethereum.ChecksumAddress("0x" + hex.EncodeToString(hash[12:]))
+}
+
+func param(params []json.RawMessage, i int, v any) *rpcError {
+	if len(params) <= i || json.Unmarshal(params[i], v) != nil {
+		return &rpcError{Code: -32602, Message: fmt.Sprintf("invalid argument %d", i)}
+	}
+	return nil
+}
+
+func invalidTransaction(err error) *rpcError {
+	return &rpcError{Code: -32000, Message: "invalid transaction: " + err.Error()}
+}
+
+func quantity(n uint64) string {
+	return fmt.Sprintf("0x%x", n)
+}
@@ -0,0 +1,50 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Post sends body as JSON to path under the API and decodes a successful
+// response into out, which may be nil. It returns the status code; non-2xx
+// responses are not errors.
+func (h *Harness) Post(ctx context.Context, path string, body, out any) (int, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+	return h.do(ctx, http.MethodPost, path, bytes.NewReader(payload), out)
+}
+
+// Get fetches path under the API and decodes a successful response into out.
+func (h *Harness) Get(ctx context.Context, path string, out any) (int, error) {
+	return h.do(ctx, http.MethodGet, path, nil, out)
+}
+
+func (h *Harness) do(ctx context.Context, method, path string, body io.Reader, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, h.API+path, body)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 || out == nil {
+		return resp.StatusCode, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,317 @@
+// Package e2e boots cashback-service-api, mint-consumer and blockchain-adapter
+// in one process, against an embedded NATS JetStream server, a throwaway
+// Postgres and a simulated chain, so scenarios can drive the public API and
+// assert on what every service did.
+//
+// The scenarios are behind the e2e build tag: go test -tags e2e ./...
+package e2e
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	adapter "github.com/cashback-platform/services/blockchain-adapter/service"
+	cashback "github.com/cashback-platform/services/cashback-service-api/service"
+	mint "github.com/cashback-platform/services/mint-consumer/service"
+	"go.uber.org/fx"
+)
+
+// readyTimeout bounds how long Start waits for every service to report ready.
+const readyTimeout = 30 * time.Second
+
+// Harness is a running platform. Stop tears it down.
+type Harness struct {
+	Chain    *Chain
+	NATS     *NATS
+	Postgres *Postgres
+
+	// API is the base URL of the cashback API, ending in /api/v1.
+	API string
+	// CashbackHealth, MintHealth and AdapterHealth are the base URLs /livez
+	// and /readyz of each service are served under.
+	CashbackHealth string
+	MintHealth     string
+	AdapterHealth  string
+
+	// The databases of each service, for assertions.
+	CashbackDB *sql.DB
+	MintDB     *sql.DB
+	AdapterDB  *sql.DB
+
+	dir  string
+	apps []*fx.App
+}
+
+// service is one of the platform services and the environment it runs with.
+// The services read their configuration from the environment, which is
+// process-wide, so each is built with its own environment in place.
+type service struct {
+	name     string
+	database string
+	db       **sql.DB
+	module   fx.Option
+	migrate  func(context.Context) (int, error)
+	env      map[string]string
+}
+
+// Start boots the platform: the chain, NATS and Postgres first, then the
+// adapter, the cashback service, which creates the streams, and mint-consumer.
+// It returns once every service reports ready.
+//
+// Services log at E2E_LOG_LEVEL, error by default.
+func Start(ctx context.Context) (_ *Harness, err error) {
+	dir, err := os.MkdirTemp("", "cashback-e2e-")
+	if err != nil {
+		return nil, err
+	}
+	h := &Harness{dir: dir}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, h.Stop(context.Background()))
+		}
+	}()
+
+	if h.Chain, err = NewChain(); err != nil {
+		return nil, err
+	}
+	if h.NATS, err = StartNATS(filepath.Join(dir, "nats")); err != nil {
+		return nil, err
+	}
+	if h.Postgres, err = StartPostgres(filepath.Join(dir, "postgres"), io.Discard); err != nil {
+		return nil, err
+	}
+
+	ports, err := freePorts(6)
+	if err != nil {
+		return nil, err
+	}
+	adapterGRPC, adapterMetrics, adapterHealth, apiPort, mintMetrics, mintHealth := ports[0], ports[1], ports[2], ports[3], ports[4], ports[5]
+
+	h.API = fmt.Sprintf("http://127.0.0.1:%d/api/v1", apiPort)
+	h.CashbackHealth = fmt.Sprintf("http://127.0.0.1:%d", apiPort)
+	h.MintHealth = fmt.Sprintf("http://127.0.0.1:%d", mintHealth)
+	h.AdapterHealth = fmt.Sprintf("http://127.0.0.1:%d", adapterHealth)
+
+	logLevel := os.Getenv("E2E_LOG_LEVEL")
+	if logLevel == "" {
+		logLevel = "error"
+	}
+	common := map[string]string{
+		"APP_ENV":                         "test",
+		"LOG_LEVEL":                       logLevel,
+		"TRACING_EXPORTER":                "none",
+		"DATABASE_HOST":                   "127.0.0.1",
+		"DATABASE_PORT":                   strconv.FormatUint(uint64(h.Postgres.Port()), 10),
+		"DATABASE_USER":                   postgresUser,
+		"DATABASE_PASSWORD":               postgresPassword,
+		"DATABASE_SSLMODE":                "disable",
+		"NATS_URL":                        h.NATS.URL(),
+		"BLOCKCHAIN_ADAPTER_GRPC_ADDRESS": fmt.Sprintf("127.0.0.1:%d", adapterGRPC),
+	}
+
+	services := []service{
+		{
+			name:     "blockchain-adapter",
+			database: "blockchain_adapter",
+			db:       &h.AdapterDB,
+			module:   adapter.Module,
+			migrate:  adapter.Migrate,
+			env: map[string]string{
+				"APP_NAME":              "blockchain-adapter",
+				"GRPC_PORT":             strconv.Itoa(adapterGRPC),
+				"METRICS_PORT":          strconv.Itoa(adapterMetrics),
+				"HEALTH_PORT":           strconv.Itoa(adapterHealth),
+				"CHAIN_RPC_URL":         h.Chain.URL(),
+				"CHAIN_TOKEN_ADDRESS":   TokenAddress,
+				"CHAIN_MINTER_KEY":      h.Chain.MinterKey(),
+				"CHAIN_RECEIPT_TIMEOUT": "2s",
+				"CUSTODY_HD_SEED":       "",
+			},
+		},
+		{
+			name:     "cashback-service-api",
+			database: "cashback_service",
+			db:       &h.CashbackDB,
+			module:   cashback.Module,
+			migrate:  cashback.Migrate,
+			env: map[string]string{
+				"SERVER_PORT":            strconv.Itoa(apiPort),
+				"AUTH_ENABLED":           "false",
+				"RATE_LIMIT_ENABLED":     "false",
+				"RECONCILIATION_ENABLED": "false",
+			},
+		},
+		{
+			name:     "mint-consumer",
+			database: "mint_consumer",
+			db:       &h.MintDB,
+			module:   mint.Module,
+			migrate:  mint.Migrate,
+			env: map[string]string{
+				"APP_NAME":            "mint-consumer",
+				"METRICS_PORT":        strconv.Itoa(mintMetrics),
+				"HEALTH_PORT":         strconv.Itoa(mintHealth),
+				"MINT_RETRY_INTERVAL": "200ms",
+				"MINT_RETRY_BACKOFF":  "500ms",
+			},
+		},
+	}
+
+	for _, svc := range services {
+		if err := h.boot(ctx, svc, common); err != nil {
+			return nil, fmt.Errorf("%s: %w", svc.name, err)
+		}
+	}
+
+	for _, url := range []string{h.AdapterHealth, h.CashbackHealth, h.MintHealth} {
+		if err := waitReady(ctx, url); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// boot creates and migrates the database of svc, then builds and starts it.
+func (h *Harness) boot(ctx context.Context, svc service, common map[string]string) error {
+	db, err := h.Postgres.CreateDatabase(ctx, svc.database)
+	if err != nil {
+		return err
+	}
+	*svc.db = db
+
+	env := map[string]string{"DATABASE_NAME": svc.database}
+	for k, v := range common {
+		env[k] = v
+	}
+	for k, v := range svc.env {
+		env[k] = v
+	}
+
+	return withEnv(env, func() error {
+		if _, err := svc.migrate(ctx); err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+		app := fx.New(svc.module, fx.NopLogger)
+		if err := app.Err(); err != nil {
+			return err
+		}
+		if err := app.Start(ctx); err != nil {
+			return err
+		}
+		h.apps = append(h.apps, app)
+		return nil
+	})
+}
+
+// Stop stops the services in reverse order, then NATS, Postgres and the chain,
+// and removes their files.
+func (h *Harness) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(h.apps) - 1; i >= 0; i-- {
+		errs = append(errs, h.apps[i].Stop(ctx))
+	}
+	h.apps = nil
+
+	if h.NATS != nil {
+		h.NATS.Stop()
+	}
+	for _, db := range []*sql.DB{h.CashbackDB, h.MintDB, h.AdapterDB} {
+		if db != nil {
+			errs = append(errs, db.Close())
+		}
+	}
+	if h.Postgres != nil {
+		errs = append(errs, h.Postgres.Stop())
+	}
+	if h.Chain != nil {
+		h.Chain.Close()
+	}
+	errs = append(errs, os.RemoveAll(h.dir))
+	return errors.Join(errs...)
+}
+
+// Readiness returns the status code of /readyz under baseURL.
+func Readiness(ctx context.Context, baseURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/readyz", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func waitReady(ctx context.Context, baseURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	for {
+		status, err := Readiness(ctx, baseURL)
+		if err == nil && status == http.StatusOK {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not ready after %s (status %d, error %v)", baseURL, readyTimeout, status, err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// withEnv runs fn with env set, then restores the previous environment.
+func withEnv(env map[string]string, fn func() error) error {
+	previous := make(map[string]*string, len(env))
+	for k, v := range env {
+		if old, ok := os.LookupEnv(k); ok {
+			previous[k] = &old
+		} else {
+			previous[k] = nil
+		}
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k, old := range previous {
+			if old == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *old)
+			}
+		}
+	}()
+	return fn()
+}
+
+func freePort() (int, error) {
+	ports, err := freePorts(1)
+	if err != nil {
+		return 0, err
+	}
+	return ports[0], nil
+}
+
+// freePorts returns n distinct ports nothing listens on. The listeners are
+// held until all ports are picked, so the same port is not returned twice.
+func freePorts(n int) ([]int, error) {
+	ports := make([]int, 0, n)
+	for range n {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		defer l.Close()
+		ports = append(ports, l.Addr().(*net.TCPAddr).Port)
+	}
+	return ports, nil
+}
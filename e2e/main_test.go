@@ -0,0 +1,232 @@
+//go:build e2e
+
+package e2e_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/e2e"
+	"github.com/google/uuid"
+)
+
+// platform is shared by every scenario: booting Postgres and three services
+// takes seconds. Scenarios create their own users and merchants, and run one
+// at a time because some of them take a dependency down.
+var platform *e2e.Harness
+
+func TestMain(m *testing.M) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	h, err := e2e.Start(ctx)
+	cancel()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "e2e: start:", err)
+		os.Exit(1)
+	}
+	platform = h
+
+	code := m.Run()
+	if err := h.Stop(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, "e2e: stop:", err)
+	}
+	os.Exit(code)
+}
+
+type (
+	merchant struct {
+		ID string `json:"id"`
+	}
+
+	user struct {
+		ID             string `json:"id"`
+		WalletAddress  string `json:"wallet_address"`
+		WalletVerified bool   `json:"wallet_verified"`
+	}
+
+	challenge struct {
+		Message string `json:"message"`
+	}
+
+	purchase struct {
+		ID string `json:"id"`
+	}
+
+	cashback struct {
+		ID            string  `json:"id"`
+		UserID        string  `json:"user_id"`
+		Amount        float64 `json:"amount"`
+		Status        string  `json:"status"`
+		WalletAddress string  `json:"wallet_address"`
+	}
+
+	// summary is a row of user_cashback_summaries in mint-consumer.
+	summary struct {
+		ApprovedCount  int
+		ApprovedAmount float64
+	}
+
+	// mintRequest is a row of mint_requests in mint-consumer.
+	mintRequest struct {
+		Status          string
+		RetryCount      int
+		ErrorCode       string
+		TransactionHash string
+	}
+)
+
+func post(t *testing.T, path string, body, out any, want int) {
+	t.Helper()
+	status, err := platform.Post(context.Background(), path, body, out)
+	if err != nil {
+		t.Fatalf("POST %s: %v", path, err)
+	}
+	if status != want {
+		t.Fatalf("POST %s: status %d, want %d", path, status, want)
+	}
+}
+
+func newMerchant(t *testing.T, cashbackPercent float64) merchant {
+	t.Helper()
+	var m merchant
+	post(t, "/merchants", map[string]any{
+		"name":             "Merchant " + uuid.NewString()[:8],
+		"category_code":    "5411",
+		"cashback_percent": cashbackPercent,
+		"funding_account":  "acct-" + uuid.NewString()[:8],
+	}, &m, http.StatusCreated)
+	return m
+}
+
+func newUser(t *testing.T) user {
+	t.Helper()
+	id := uuid.NewString()
+	var u user
+	post(t, "/users", map[string]any{
+		"external_id": "ext-" + id,
+		"email":       id + "@example.com",
+	}, &u, http.StatusCreated)
+	return u
+}
+
+// verifyWallet proves ownership of a new wallet for u, which makes it the
+// payout wallet.
+func verifyWallet(t *testing.T, u user) *e2e.Wallet {
+	t.Helper()
+	wallet, err := e2e.NewWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c challenge
+	post(t, "/users/"+u.ID+"/wallet/challenge", map[string]any{
+		"wallet_address": wallet.Address(),
+	}, &c, http.StatusCreated)
+
+	var verified user
+	post(t, "/users/"+u.ID+"/wallet/verify", map[string]any{
+		"message":   c.Message,
+		"signature": wallet.SignPersonal(c.Message),
+	}, &verified, http.StatusOK)
+	if !verified.WalletVerified || verified.WalletAddress != wallet.Address() {
+		t.Fatalf("wallet %s not verified: %+v", wallet.Address(), verified)
+	}
+	return wallet
+}
+
+func newPurchase(t *testing.T, u user, m merchant, amount float64) purchase {
+	t.Helper()
+	var p purchase
+	post(t, "/purchases", map[string]any{
+		"user_id":     u.ID,
+		"merchant_id": m.ID,
+		"amount":      amount,
+	}, &p, http.StatusCreated)
+	return p
+}
+
+func calculate(t *testing.T, p purchase) cashback {
+	t.Helper()
+	var c cashback
+	post(t, "/cashback/calculate", map[string]any{"purchase_id": p.ID}, &c, http.StatusCreated)
+	return c
+}
+
+// userSummary reads the cashback summary mint-consumer keeps for userID. It
+// returns the zero summary when mint-consumer has seen no cashback of theirs.
+func userSummary(t *testing.T, userID string) summary {
+	t.Helper()
+	var s summary
+	err := platform.MintDB.QueryRow(
+		`SELECT approved_count, approved_amount FROM user_cashback_summaries WHERE user_id = $1`, userID,
+	).Scan(&s.ApprovedCount, &s.ApprovedAmount)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// mintRequestOf reads the mint request mint-consumer keeps for cashbackID. It
+// returns the zero request when there is none yet.
+func mintRequestOf(t *testing.T, cashbackID string) mintRequest {
+	t.Helper()
+	var r mintRequest
+	err := platform.MintDB.QueryRow(`
+		SELECT status, retry_count, COALESCE(error_code, ''), COALESCE(transaction_hash, '')
+		FROM mint_requests WHERE cashback_id = $1`, cashbackID,
+	).Scan(&r.Status, &r.RetryCount, &r.ErrorCode, &r.TransactionHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// cashbackStatus reads the status of cashbackID in the ledger.
+func cashbackStatus(t *testing.T, cashbackID string) string {
+	t.Helper()
+	var status string
+	err := platform.CashbackDB.QueryRow(`SELECT status FROM cashback_ledger WHERE id = $1`, cashbackID).Scan(&status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return status
+}
+
+// tokenUnits converts a cashback amount to the token units minted for it,
+// at the token's 18 decimals.
+func tokenUnits(amount float64) *big.Int {
+	units, _ := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	units.Mul(units, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)))
+	return new(big.Int).Quo(units.Num(), units.Denom())
+}
+
+// eventually polls condition until it holds or timeout passes.
+func eventually(t *testing.T, timeout time.Duration, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s: not after %s", what, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// never checks that condition does not hold for the whole of d.
+func never(t *testing.T, d time.Duration, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(d)
+	for time.Now().Before(deadline) {
+		if condition() {
+			t.Fatalf("%s", what)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
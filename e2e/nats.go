@@ -0,0 +1,74 @@
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// NATS is an embedded JetStream server. Stop and Restart simulate an outage:
+// the server comes back on the same port with the streams and consumers it
+// had.
+type NATS struct {
+	opts   *server.Options
+	server *server.Server
+}
+
+// StartNATS starts a JetStream server on a free port, storing streams in dir.
+func StartNATS(dir string) (*NATS, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+	n := &NATS{opts: &server.Options{
+		Host:      "127.0.0.1",
+		Port:      port,
+		JetStream: true,
+		StoreDir:  dir,
+		NoLog:     true,
+		NoSigs:    true,
+	}}
+	if err := n.start(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// URL is the client URL, for NATS_URL.
+func (n *NATS) URL() string {
+	return fmt.Sprintf("nats://%s:%d", n.opts.Host, n.opts.Port)
+}
+
+// Stop shuts the server down. Clients keep trying to reconnect.
+func (n *NATS) Stop() {
+	if n.server == nil {
+		return
+	}
+	n.server.Shutdown()
+	n.server.WaitForShutdown()
+	n.server = nil
+}
+
+// Restart starts the server again after Stop.
+func (n *NATS) Restart() error {
+	if n.server != nil {
+		return errors.New("nats: server is running")
+	}
+	return n.start()
+}
+
+func (n *NATS) start() error {
+	s, err := server.NewServer(n.opts)
+	if err != nil {
+		return fmt.Errorf("nats: %w", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(10 * time.Second) {
+		s.Shutdown()
+		return errors.New("nats: server not ready after 10s")
+	}
+	n.server = s
+	return nil
+}
@@ -0,0 +1,83 @@
+package e2e
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	postgresUser     = "postgres"
+	postgresPassword = "postgres"
+)
+
+// Postgres is a throwaway Postgres server. It runs the binaries in
+// E2E_POSTGRES_BINARIES, the directory holding bin/initdb and bin/pg_ctl of a
+// local install, when set. Otherwise they are downloaded once, from
+// E2E_POSTGRES_REPOSITORY or Maven Central, and cached.
+type Postgres struct {
+	server *embeddedpostgres.EmbeddedPostgres
+	port   uint32
+}
+
+// StartPostgres initialises a cluster in dir and starts it on a free port.
+func StartPostgres(dir string, logs io.Writer) (*Postgres, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := embeddedpostgres.DefaultConfig().
+		Port(uint32(port)).
+		Username(postgresUser).
+		Password(postgresPassword).
+		RuntimePath(filepath.Join(dir, "runtime")).
+		DataPath(filepath.Join(dir, "data")).
+		Logger(logs)
+	if binaries := os.Getenv("E2E_POSTGRES_BINARIES"); binaries != "" {
+		cfg = cfg.BinariesPath(binaries)
+	}
+	if repository := os.Getenv("E2E_POSTGRES_REPOSITORY"); repository != "" {
+		cfg = cfg.BinaryRepositoryURL(repository)
+	}
+
+	p := &Postgres{server: embeddedpostgres.NewDatabase(cfg), port: uint32(port)}
+	if err := p.server.Start(); err != nil {
+		return nil, fmt.Errorf("postgres: %w", err)
+	}
+	return p, nil
+}
+
+// Port is the port the server listens on, for DATABASE_PORT.
+func (p *Postgres) Port() uint32 {
+	return p.port
+}
+
+// CreateDatabase creates an empty database and connects to it.
+func (p *Postgres) CreateDatabase(ctx context.Context, name string) (*sql.DB, error) {
+	admin, err := sql.Open("pgx", p.dsn("postgres"))
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+
+	if _, err := admin.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %q", name)); err != nil {
+		return nil, fmt.Errorf("postgres: create database %s: %w", name, err)
+	}
+	return sql.Open("pgx", p.dsn(name))
+}
+
+// Stop shuts the server down.
+func (p *Postgres) Stop() error {
+	return p.server.Stop()
+}
+
+func (p *Postgres) dsn(database string) string {
+	return fmt.Sprintf("postgres://%s:%s@127.0.0.1:%d/%s?sslmode=disable", postgresUser, postgresPassword, p.port, database)
+}
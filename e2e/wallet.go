@@ -0,0 +1,39 @@
+package e2e
+
+import (
+	"encoding/hex"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// Wallet is a self-custody wallet, to prove ownership of an address the way a
+// browser wallet would.
+type Wallet struct {
+	key     *secp256k1.PrivateKey
+	address string
+}
+
+// NewWallet generates a wallet with a fresh key.
+func NewWallet() (*Wallet, error) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{key: key, address: addressOf(key.PubKey())}, nil
+}
+
+// Address is the checksummed address of the wallet.
+func (w *Wallet) Address() string {
+	return w.address
+}
+
+// SignPersonal signs message as personal_sign does, returning the hex-encoded
+// R || S || V.
+func (w *Wallet) SignPersonal(message string) string {
+	// The compact form is <27 + recovery id> || R || S.
+	compact := ecdsa.SignCompact(w.key, ethereum.PersonalMessageHash(message), false)
+	sig := append(compact[1:], compact[0])
+	return "0x" + hex.EncodeToString(sig)
+}
@@ -0,0 +1,179 @@
+// Package health answers the liveness and readiness probes of every service.
+//
+// Liveness says the process is up and nothing more; it never looks at
+// dependencies, so an outage of Postgres or NATS does not get every replica
+// restarted. Readiness runs every registered check and fails when one does,
+// or once shutdown has begun.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+)
+
+const (
+	LivePath  = "/livez"
+	ReadyPath = "/readyz"
+
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusDraining = "draining"
+)
+
+type (
+	// Check reports whether a dependency is usable; nil means it is.
+	Check func(ctx context.Context) error
+
+	// Checker runs the readiness checks.
+	Checker struct {
+		timeout  time.Duration
+		log      *slog.Logger
+		draining atomic.Bool
+
+		mu     sync.RWMutex
+		checks map[string]Check
+	}
+
+	// Report is the body of a readiness response.
+	Report struct {
+		Status string                 `json:"status"`
+		Checks map[string]CheckResult `json:"checks"`
+	}
+
+	// CheckResult is the outcome of one check.
+	CheckResult struct {
+		Status     string `json:"status"`
+		Error      string `json:"error,omitempty"`
+		DurationMS int64  `json:"duration_ms"`
+	}
+)
+
+// NewChecker returns a checker bounding each check by timeout.
+func NewChecker(timeout time.Duration, log *slog.Logger) *Checker {
+	return &Checker{
+		timeout: timeout,
+		log:     log,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Add registers check under name, replacing any check of that name.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Drain makes readiness fail from now on, whatever the checks say.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// DrainFor makes readiness fail, then waits delay, or until ctx is done, so
+// load balancers stop routing to the service before it stops serving.
+func (c *Checker) DrainFor(ctx context.Context, delay time.Duration) {
+	c.Drain()
+	c.log.Info("readiness failing, draining", "delay", delay.String())
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// Run runs every check concurrently, each bounded by the checker's timeout.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mu.RUnlock()
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.run(ctx, checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFailing
+		}
+	}
+	if c.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{Status: StatusOK, DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusFailing
+		result.Error = logger.Redact(err.Error())
+	}
+	return result
+}
+
+// Live answers the liveness probe.
+func (*Checker) Live(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// Ready answers the readiness probe: 200 when every check passed, 503
+// otherwise, with the result of each check either way.
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	if report.Status == StatusFailing {
+		c.log.WarnContext(r.Context(), "not ready", "checks", report.Checks)
+	}
+	writeJSON(w, status, report)
+}
+
+// Handler serves the liveness probe on LivePath and the readiness probe on
+// ReadyPath, for services without an HTTP API of their own.
+func (c *Checker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivePath, c.Live)
+	mux.HandleFunc(ReadyPath, c.Ready)
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,124 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/pkg/health"
+)
+
+func newChecker(timeout time.Duration) *health.Checker {
+	return health.NewChecker(timeout, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func passing(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("connection refused") }
+
+// probe serves path from the checker's handler and decodes the report.
+func probe(t *testing.T, checker *health.Checker, path string) (int, health.Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	checker.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report health.Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, report
+}
+
+func TestReadiness(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		checks map[string]health.Check
+		want   int
+		status string
+	}{
+		{"no checks", nil, http.StatusOK, health.StatusOK},
+		{"every check passes", map[string]health.Check{"postgres": passing, "nats": passing},
+			http.StatusOK, health.StatusOK},
+		{"a dependency check fails", map[string]health.Check{"postgres": passing, "nats": failing},
+			http.StatusServiceUnavailable, health.StatusFailing},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := newChecker(time.Second)
+			for name, check := range tc.checks {
+				checker.Add(name, check)
+			}
+
+			code, report := probe(t, checker, health.ReadyPath)
+			if code != tc.want || report.Status != tc.status {
+				t.Fatalf("%d %q, want %d %q", code, report.Status, tc.want, tc.status)
+			}
+			if len(report.Checks) != len(tc.checks) {
+				t.Fatalf("reported %d checks, want %d", len(report.Checks), len(tc.checks))
+			}
+		})
+	}
+}
+
+func TestReadinessReportsEachCheck(t *testing.T) {
+	checker := newChecker(time.Second)
+	checker.Add("postgres", passing)
+	checker.Add("nats", failing)
+
+	_, report := probe(t, checker, health.ReadyPath)
+	if result := report.Checks["postgres"]; result.Status != health.StatusOK || result.Error != "" {
+		t.Fatalf("postgres: %+v", result)
+	}
+	if result := report.Checks["nats"]; result.Status != health.StatusFailing || result.Error != "connection refused" {
+		t.Fatalf("nats: %+v", result)
+	}
+}
+
+func TestChecksAreBoundedByTheTimeout(t *testing.T) {
+	checker := newChecker(20 * time.Millisecond)
+	checker.Add("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	report := checker.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("checks ran for %s", elapsed)
+	}
+	if result := report.Checks["stuck"]; result.Status != health.StatusFailing ||
+		!strings.Contains(result.Error, context.DeadlineExceeded.Error()) {
+		t.Fatalf("stuck: %+v", result)
+	}
+}
+
+func TestLivenessIgnoresDependencies(t *testing.T) {
+	checker := newChecker(time.Second)
+	checker.Add("postgres", failing)
+	checker.Drain()
+
+	if code, report := probe(t, checker, health.LivePath); code != http.StatusOK || report.Status != health.StatusOK {
+		t.Fatalf("%d %q, want 200 %q", code, report.Status, health.StatusOK)
+	}
+}
+
+func TestDrainingFailsReadiness(t *testing.T) {
+	checker := newChecker(time.Second)
+	checker.Add("postgres", passing)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// The cancelled context ends the wait at once.
+	checker.DrainFor(ctx, time.Hour)
+
+	code, report := probe(t, checker, health.ReadyPath)
+	if code != http.StatusServiceUnavailable || report.Status != health.StatusDraining {
+		t.Fatalf("%d %q, want 503 %q", code, report.Status, health.StatusDraining)
+	}
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDKey is both the log attribute and the baggage member holding the
+// request ID.
+const requestIDKey = "request_id"
+
+type attrsKey struct{}
+
+// contextHandler adds the trace, the request ID and the attributes attached to
+// the context to every record.
+type contextHandler struct {
+	slog.Handler
+}
+
+// WithRequestID attaches the ID of the HTTP request being served. It is kept
+// in the baggage rather than as a plain attribute, so it is propagated with
+// the trace: events published while serving the request, and whatever the
+// consumers of those events log, carry it as well.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	member, err := baggage.NewMember(requestIDKey, url.PathEscape(id))
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// WithEventID attaches the ID of the event being published or handled.
+func WithEventID(ctx context.Context, id string) context.Context {
+	return WithAttrs(ctx, slog.String("event_id", id))
+}
+
+// WithCashbackID attaches the ID of the cashback being processed.
+func WithCashbackID(ctx context.Context, id string) context.Context {
+	return WithAttrs(ctx, slog.String("cashback_id", id))
+}
+
+// WithAttrs attaches attrs to ctx; records logged with the returned context
+// include them. Attributes attached later win over earlier ones of the same key.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	for _, attr := range existing {
+		if !containsKey(attrs, attr.Key) {
+			merged = append(merged, attr)
+		}
+	}
+	return context.WithValue(ctx, attrsKey{}, append(merged, attrs...))
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(
+				slog.String("trace_id", sc.TraceID().String()),
+				slog.String("span_id", sc.SpanID().String()),
+			)
+		}
+		if id := baggage.FromContext(ctx).Member(requestIDKey).Value(); id != "" {
+			r.AddAttrs(slog.String(requestIDKey, id))
+		}
+		if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+			r.AddAttrs(attrs...)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+func containsKey(attrs []slog.Attr, key string) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+// Package logger is the structured logger shared by the services: JSON
+// records, enriched from the context and with PII redacted.
+//
+// Records logged with a context carry the trace and span ID of its span and
+// the IDs attached with WithRequestID, WithEventID and WithCashbackID, so the
+// *Context variants should be preferred wherever a context is at hand.
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var logger = New(os.Stdout, slog.LevelInfo, "")
+
+// New returns a logger writing JSON to w. service, when set, is added to every
+// record.
+func New(w io.Writer, level slog.Leveler, service string) *slog.Logger {
+	var handler slog.Handler = slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	})
+	handler = contextHandler{handler}
+
+	l := slog.New(handler)
+	if service != "" {
+		l = l.With(slog.String("service", service))
+	}
+	return l
+}
+
+// ParseLevel reads a level name: debug, info, warn or error.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.TrimSpace(name)))
+	return level, err
+}
+
+// Set makes l the logger behind the package functions and the slog and log
+// defaults, so third-party output ends up in the same stream.
+func Set(l *slog.Logger) {
+	logger = l
+	slog.SetDefault(l)
+}
+
+// Init installs the default logger at info level.
+func Init() {
+	Set(New(os.Stdout, slog.LevelInfo, ""))
+}
+
+func Info(msg string, args ...any) {
+	logger.Info(msg, args...)
+}
+
+func Error(msg string, args ...any) {
+	logger.Error(msg, args...)
+}
+
+func Debug(msg string, args ...any) {
+	logger.Debug(msg, args...)
+}
+
+func Warn(msg string, args ...any) {
+	logger.Warn(msg, args...)
+}
+
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	logger.InfoContext(ctx, msg, args...)
+}
+
+func ErrorContext(ctx context.Context, msg string, args ...any) {
+	logger.ErrorContext(ctx, msg, args...)
+}
+
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	logger.DebugContext(ctx, msg, args...)
+}
+
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	logger.WarnContext(ctx, msg, args...)
+}
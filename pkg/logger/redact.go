@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+)
+
+var (
+	emailPattern  = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,})`)
+	walletPattern = regexp.MustCompile(`\b0x([0-9a-fA-F]{4})[0-9a-fA-F]{32}([0-9a-fA-F]{4})\b`)
+)
+
+// Redact masks the email addresses and wallet addresses in s: the local part
+// of an email keeps its first character, a wallet its first and last four
+// hex digits. Transaction hashes are longer than an address and left alone.
+func Redact(s string) string {
+	s = emailPattern.ReplaceAllString(s, "$1***@$2")
+	return walletPattern.ReplaceAllString(s, "0x$1…$2")
+}
+
+// redactAttr applies Redact to every value logged, the message included.
+// slog.LogValuer values are resolved first. Errors are logged as their
+// redacted message; structs, maps and the other values the JSON handler would
+// marshal are marshaled here and redacted as JSON, so they keep their shape.
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(Redact(attr.Value.String()))
+	case slog.KindAny:
+		attr.Value = redactAny(attr.Value.Any())
+	}
+	return attr
+}
+
+func redactAny(v any) slog.Value {
+	switch v := v.(type) {
+	case nil:
+		return slog.AnyValue(nil)
+	case error:
+		return slog.StringValue(Redact(v.Error()))
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return slog.StringValue(Redact(fmt.Sprintf("%+v", v)))
+	}
+	return slog.AnyValue(json.RawMessage(Redact(string(b))))
+}
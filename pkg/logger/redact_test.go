@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+const (
+	wallet       = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	maskedWallet = "0x5aAe…eAed"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "empty", in: "", want: ""},
+		{name: "nothing to mask", in: "cashback approved", want: "cashback approved"},
+		{name: "email", in: "jane.doe@example.com", want: "j***@example.com"},
+		{name: "single character local part", in: "j@example.co.uk", want: "j***@example.co.uk"},
+		{name: "email in a sentence", in: "user jane+promo@mail.example.com signed up", want: "user j***@mail.example.com signed up"},
+		{name: "several emails", in: "a@x.io, bob@y.org", want: "a***@x.io, b***@y.org"},
+		{name: "not an email", in: "user@localhost", want: "user@localhost"},
+		{name: "wallet", in: wallet, want: maskedWallet},
+		{name: "lowercase wallet", in: "to 0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", want: "to 0x5aae…eaed"},
+		{name: "wallet in a path", in: "/wallets/" + wallet + "/balance", want: "/wallets/" + maskedWallet + "/balance"},
+		{
+			name: "transaction hash is left alone",
+			in:   "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+			want: "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+		},
+		{name: "too short for a wallet", in: "0x5aAeb6053F3E94C9", want: "0x5aAeb6053F3E94C9"},
+		{name: "email and wallet", in: "jane@example.com paid to " + wallet, want: "j***@example.com paid to " + maskedWallet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.in); got != tt.want {
+				t.Fatalf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+type profile struct {
+	Email  string `json:"email"`
+	Wallet string `json:"wallet"`
+	Tier   string `json:"tier"`
+}
+
+type valuer struct{ email string }
+
+func (v valuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("email", v.email), slog.Int("purchases", 3))
+}
+
+type stringValuer struct{ wallet string }
+
+func (v stringValuer) LogValue() slog.Value {
+	return slog.StringValue("wallet " + v.wallet)
+}
+
+type opaque struct{ Ch chan int }
+
+func (o opaque) String() string { return "opaque jane@example.com" }
+
+func TestNewRedactsEveryKindOfValue(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{name: "string", attr: slog.String("v", "jane@example.com"), want: `"j***@example.com"`},
+		{name: "error", attr: slog.Any("v", fmt.Errorf("mint to %s: %w", wallet, errors.New("reverted"))), want: `"mint to ` + maskedWallet + `: reverted"`},
+		{name: "struct", attr: slog.Any("v", profile{Email: "jane@example.com", Wallet: wallet, Tier: "gold"}),
+			want: `{"email":"j***@example.com","wallet":"` + maskedWallet + `","tier":"gold"}`},
+		{name: "pointer to struct", attr: slog.Any("v", &profile{Email: "jane@example.com"}),
+			want: `{"email":"j***@example.com","wallet":"","tier":""}`},
+		{name: "map", attr: slog.Any("v", map[string]string{"to": wallet}), want: `{"to":"` + maskedWallet + `"}`},
+		{name: "slice", attr: slog.Any("v", []string{"jane@example.com", "ok"}), want: `["j***@example.com","ok"]`},
+		{name: "log valuer resolving to a group", attr: slog.Any("v", valuer{email: "jane@example.com"}),
+			want: `{"email":"j***@example.com","purchases":3}`},
+		{name: "log valuer resolving to a string", attr: slog.Any("v", stringValuer{wallet: wallet}), want: `"wallet ` + maskedWallet + `"`},
+		{name: "group", attr: slog.Group("v", slog.String("email", "jane@example.com")), want: `{"email":"j***@example.com"}`},
+		{name: "value JSON cannot encode", attr: slog.Any("v", opaque{Ch: make(chan int)}), want: `"opaque j***@example.com"`},
+		{name: "number", attr: slog.Int("v", 42), want: `42`},
+		{name: "nil", attr: slog.Any("v", nil), want: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			New(&buf, slog.LevelInfo, "test").LogAttrs(t.Context(), slog.LevelInfo, "hello", tt.attr)
+
+			var record map[string]json.RawMessage
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("record %s is not JSON: %v", buf.String(), err)
+			}
+			if got := string(record["v"]); got != tt.want {
+				t.Fatalf("logged %s, want %s", got, tt.want)
+			}
+			if strings.Contains(buf.String(), "jane@example.com") || strings.Contains(buf.String(), wallet) {
+				t.Fatalf("record leaks PII: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestNewRedactsTheMessage(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, slog.LevelInfo, "test").Info("welcome jane@example.com")
+
+	if !strings.Contains(buf.String(), `"msg":"welcome j***@example.com"`) {
+		t.Fatalf("message is not redacted: %s", buf.String())
+	}
+}
@@ -0,0 +1,288 @@
+// Package migrate applies versioned SQL migrations to a service's Postgres
+// database and checks, on startup, that the schema is the one the build was
+// written for.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// lockKey identifies the session advisory lock held while migrating. Advisory
+// locks are scoped to a database, and every service has a database of its
+// own, so one fixed key serves them all.
+const lockKey = 8_127_402_211
+
+var (
+	ErrSchemaTooNew  = errors.New("database schema is newer than this build")
+	ErrSchemaPending = errors.New("database schema has pending migrations")
+	// ErrSchemaUnversioned reports tables in a database without migration
+	// history, such as one created before migrations were versioned. Its
+	// shape is unknown, so the first migration is not run over it.
+	ErrSchemaUnversioned = errors.New("database has tables but no migration history")
+)
+
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type (
+	// Migration is one versioned schema change, read from the files
+	// <version>_<name>.up.sql and <version>_<name>.down.sql.
+	Migration struct {
+		Version int64
+		Name    string
+		Up      string
+		Down    string
+	}
+
+	// Migrator applies a service's migrations and records them in
+	// schema_migrations, one row per applied version.
+	Migrator struct {
+		db         *gorm.DB
+		migrations []Migration
+	}
+)
+
+// New returns a migrator for the migrations in the migrations directory of
+// fsys, usually the files the service embeds.
+func New(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads the migrations in the migrations directory of fsys, sorted by
+// version. Every version needs both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range files {
+		match := migrationName.FindStringSubmatch(path.Base(file))
+		if match == nil {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>.(up|down).sql", file)
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migration %s: invalid version", file)
+		}
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Latest is the version the schema has once every migration is applied.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the highest applied version, 0 on an empty database.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	return currentVersion(m.db.WithContext(ctx))
+}
+
+// Check refuses a schema this build was not written for: one with migrations
+// it does not know, or one still missing some of its migrations.
+func (m *Migrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := m.checkKnown(version); err != nil {
+		return err
+	}
+	if err := checkVersioned(m.db.WithContext(ctx), version); err != nil {
+		return err
+	}
+	if version < m.Latest() {
+		return fmt.Errorf("%w: schema is at version %d, this build needs %d; run `migrate up`", ErrSchemaPending, version, m.Latest())
+	}
+	return nil
+}
+
+// Up applies every pending migration and returns how many ran. Each migration
+// runs in its own transaction.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		version, err := currentVersion(conn)
+		if err != nil {
+			return err
+		}
+		if err := m.checkKnown(version); err != nil {
+			return err
+		}
+		if err := checkVersioned(conn, version); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				return tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", migration.Version, migration.Name).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations and returns how many ran.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		for ; reverted < steps; reverted++ {
+			version, err := currentVersion(conn)
+			if err != nil {
+				return err
+			}
+			if version == 0 {
+				return nil
+			}
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("%w: no down migration for version %d", ErrSchemaTooNew, version)
+			}
+
+			err = conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// locked runs fn on a single connection holding the migration advisory lock,
+// so replicas started together migrate one after the other.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		// Unlock even when ctx is done: the connection goes back to the pool
+		// and would keep the lock otherwise.
+		defer conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", lockKey)
+
+		err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`).Error
+		if err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) checkKnown(version int64) error {
+	if _, known := m.find(version); version != 0 && !known {
+		return fmt.Errorf("%w: schema is at version %d, this build knows up to %d", ErrSchemaTooNew, version, m.Latest())
+	}
+	return nil
+}
+
+// checkVersioned refuses to migrate a database from version 0 unless its
+// schema is empty: tables without history were not made by these migrations,
+// and running the first one over them would record a version the schema may
+// not have.
+func checkVersioned(db *gorm.DB, version int64) error {
+	if version != 0 {
+		return nil
+	}
+	var tables []string
+	err := db.Raw(`SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name <> 'schema_migrations'
+		ORDER BY table_name`).Scan(&tables).Error
+	if err != nil {
+		return err
+	}
+	if len(tables) > 0 {
+		return fmt.Errorf("%w: found %s", ErrSchemaUnversioned, strings.Join(tables, ", "))
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+func currentVersion(db *gorm.DB) (int64, error) {
+	var exists bool
+	if err := db.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error; err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version int64
+	err := db.Raw("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version).Error
+	return version, err
+}
@@ -0,0 +1,361 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cashback-platform/pkg/migrate"
+	"github.com/cashback-platform/pkg/postgrestest"
+	"gorm.io/gorm"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "sorted by version",
+			files: map[string]string{
+				"migrations/10_add_index.up.sql":   "up 10",
+				"migrations/10_add_index.down.sql": "down 10",
+				"migrations/2_add_column.up.sql":   "up 2",
+				"migrations/2_add_column.down.sql": "down 2",
+				"migrations/0001_init.up.sql":      "up 1",
+				"migrations/0001_init.down.sql":    "down 1",
+				"migrations/README.md":             "ignored",
+			},
+			want: []string{"1_init", "2_add_column", "10_add_index"},
+		},
+		{name: "no migrations", files: map[string]string{}, want: []string{}},
+		{
+			name:    "missing direction",
+			files:   map[string]string{"migrations/1_init.sql": ""},
+			wantErr: "must be named <version>_<name>.(up|down).sql",
+		},
+		{
+			name:    "missing version",
+			files:   map[string]string{"migrations/init.up.sql": ""},
+			wantErr: "must be named <version>_<name>.(up|down).sql",
+		},
+		{
+			name:    "dash instead of underscore",
+			files:   map[string]string{"migrations/1-init.up.sql": ""},
+			wantErr: "must be named <version>_<name>.(up|down).sql",
+		},
+		{
+			name:    "unknown direction",
+			files:   map[string]string{"migrations/1_init.sideways.sql": ""},
+			wantErr: "must be named <version>_<name>.(up|down).sql",
+		},
+		{
+			name:    "version zero",
+			files:   map[string]string{"migrations/0_init.up.sql": "", "migrations/0_init.down.sql": ""},
+			wantErr: "invalid version",
+		},
+		{
+			name:    "version out of range",
+			files:   map[string]string{"migrations/99999999999999999999_init.up.sql": ""},
+			wantErr: "invalid version",
+		},
+		{
+			name:    "no down file",
+			files:   map[string]string{"migrations/1_init.up.sql": "up"},
+			wantErr: "needs both an up and a down file",
+		},
+		{
+			name:    "no up file",
+			files:   map[string]string{"migrations/1_init.down.sql": "down"},
+			wantErr: "needs both an up and a down file",
+		},
+		{
+			name:    "empty up file",
+			files:   map[string]string{"migrations/1_init.up.sql": "", "migrations/1_init.down.sql": "down"},
+			wantErr: "needs both an up and a down file",
+		},
+		{
+			name:    "two names for one version",
+			files:   map[string]string{"migrations/1_init.up.sql": "up", "migrations/1_setup.down.sql": "down"},
+			wantErr: "has two names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, content := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			migrations, err := migrate.Load(fsys)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, len(migrations))
+			for i, m := range migrations {
+				got[i] = fmt.Sprintf("%d_%s", m.Version, m.Name)
+				if m.Up != fmt.Sprintf("up %d", m.Version) || m.Down != fmt.Sprintf("down %d", m.Version) {
+					t.Fatalf("migration %s has up %q and down %q", got[i], m.Up, m.Down)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("loaded %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// The tests below run against the Postgres at TEST_DATABASE_URL and are
+// skipped without it.
+
+func TestUpAndDownRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	migrator := newMigrator(t, db, 3)
+
+	assertVersion(t, migrator, 0)
+	if err := migrator.Check(ctx); !errors.Is(err, migrate.ErrSchemaPending) {
+		t.Fatalf("check on an empty database = %v, want %v", err, migrate.ErrSchemaPending)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied != 3 {
+		t.Fatalf("applied %d migrations, want 3", applied)
+	}
+	assertVersion(t, migrator, 3)
+	assertTables(t, db, 3)
+	if err := migrator.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if applied, err := migrator.Up(ctx); err != nil || applied != 0 {
+		t.Fatalf("second up applied %d, error %v; want nothing to do", applied, err)
+	}
+
+	reverted, err := migrator.Down(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverted != 1 {
+		t.Fatalf("reverted %d migrations, want 1", reverted)
+	}
+	assertVersion(t, migrator, 2)
+	assertTables(t, db, 2)
+
+	// Asking for more steps than were applied stops at an empty schema.
+	reverted, err = migrator.Down(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverted != 2 {
+		t.Fatalf("reverted %d migrations, want 2", reverted)
+	}
+	assertVersion(t, migrator, 0)
+	assertTables(t, db, 0)
+
+	if applied, err := migrator.Up(ctx); err != nil || applied != 3 {
+		t.Fatalf("up after down applied %d, error %v; want 3", applied, err)
+	}
+	assertTables(t, db, 3)
+}
+
+func TestCheckRefusesPendingMigrations(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	if _, err := newMigrator(t, db, 2).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newMigrator(t, db, 3).Check(ctx); !errors.Is(err, migrate.ErrSchemaPending) {
+		t.Fatalf("error = %v, want %v", err, migrate.ErrSchemaPending)
+	}
+}
+
+func TestRefusesSchemasNewerThanTheBuild(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	if _, err := newMigrator(t, db, 3).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	old := newMigrator(t, db, 2)
+
+	if err := old.Check(ctx); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("check: error = %v, want %v", err, migrate.ErrSchemaTooNew)
+	}
+	if _, err := old.Up(ctx); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("up: error = %v, want %v", err, migrate.ErrSchemaTooNew)
+	}
+	if _, err := old.Down(ctx, 1); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("down: error = %v, want %v", err, migrate.ErrSchemaTooNew)
+	}
+	assertVersion(t, old, 3)
+	assertTables(t, db, 3)
+}
+
+func TestRefusesUnknownSchemaVersions(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	if _, err := newMigrator(t, db, 2).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// A build that skips version 2 does not know the schema, though its
+	// latest version is higher.
+	fsys := migrations(3)
+	delete(fsys, "migrations/2_create_table_2.up.sql")
+	delete(fsys, "migrations/2_create_table_2.down.sql")
+	other, err := migrate.New(db, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := other.Check(ctx); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("check: error = %v, want %v", err, migrate.ErrSchemaTooNew)
+	}
+	if _, err := other.Up(ctx); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("up: error = %v, want %v", err, migrate.ErrSchemaTooNew)
+	}
+	assertVersion(t, other, 2)
+}
+
+func TestRefusesUnversionedSchemas(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	// A table made before migrations were versioned, perhaps not in the
+	// shape the first migration gives it.
+	if err := db.Exec("CREATE TABLE table_1 (id TEXT)").Error; err != nil {
+		t.Fatal(err)
+	}
+	migrator := newMigrator(t, db, 3)
+
+	if err := migrator.Check(ctx); !errors.Is(err, migrate.ErrSchemaUnversioned) {
+		t.Fatalf("check: error = %v, want %v", err, migrate.ErrSchemaUnversioned)
+	}
+	if _, err := migrator.Up(ctx); !errors.Is(err, migrate.ErrSchemaUnversioned) || !strings.Contains(err.Error(), "table_1") {
+		t.Fatalf("up: error = %v, want %v naming table_1", err, migrate.ErrSchemaUnversioned)
+	}
+	assertVersion(t, migrator, 0)
+	assertTables(t, db, 1)
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	fsys := migrations(2)
+	fsys["migrations/3_broken.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE table_3 (id BIGINT); SELECT no_such_function()")}
+	fsys["migrations/3_broken.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE table_3")}
+	migrator, err := migrate.New(db, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err == nil || !strings.Contains(err.Error(), "migration 3_broken") {
+		t.Fatalf("error = %v, want migration 3_broken to fail", err)
+	}
+	if applied != 2 {
+		t.Fatalf("applied %d migrations, want the 2 before the broken one", applied)
+	}
+	assertVersion(t, migrator, 2)
+	assertTables(t, db, 2)
+}
+
+func TestConcurrentUpAppliesEachMigrationOnce(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+
+	const n = 5
+	applied := make(chan int, n)
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count, err := newMigrator(t, db, 3).Up(ctx)
+			if err != nil {
+				t.Error(err)
+			}
+			applied <- count
+		}()
+	}
+	wg.Wait()
+	close(applied)
+
+	total := 0
+	for count := range applied {
+		total += count
+	}
+	if total != 3 {
+		t.Fatalf("applied %d migrations in total, want 3", total)
+	}
+	assertVersion(t, newMigrator(t, db, 3), 3)
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	return postgrestest.Open(t, postgrestest.Schema(t))
+}
+
+// migrations returns n migrations, the ith creating table_i.
+func migrations(n int) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for i := 1; i <= n; i++ {
+		name := fmt.Sprintf("migrations/%d_create_table_%d", i, i)
+		fsys[name+".up.sql"] = &fstest.MapFile{Data: []byte(fmt.Sprintf("CREATE TABLE table_%d (id BIGINT PRIMARY KEY)", i))}
+		fsys[name+".down.sql"] = &fstest.MapFile{Data: []byte(fmt.Sprintf("DROP TABLE table_%d", i))}
+	}
+	return fsys
+}
+
+func newMigrator(t *testing.T, db *gorm.DB, n int) *migrate.Migrator {
+	t.Helper()
+	migrator, err := migrate.New(db, migrations(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrator
+}
+
+func assertVersion(t *testing.T, migrator *migrate.Migrator, want int64) {
+	t.Helper()
+	version, err := migrator.Version(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != want {
+		t.Fatalf("version = %d, want %d", version, want)
+	}
+}
+
+// assertTables checks that table_1 to table_n exist, and table_n+1 to table_3
+// do not.
+func assertTables(t *testing.T, db *gorm.DB, n int) {
+	t.Helper()
+	for i := 1; i <= 3; i++ {
+		var exists bool
+		err := db.Raw("SELECT to_regclass(?) IS NOT NULL", fmt.Sprintf("table_%d", i)).Scan(&exists).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != (i <= n) {
+			t.Fatalf("table_%d exists = %t, want %t", i, exists, i <= n)
+		}
+	}
+}
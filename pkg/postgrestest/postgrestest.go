@@ -0,0 +1,70 @@
+// Package postgrestest gives tests a Postgres schema of their own in the
+// database at TEST_DATABASE_URL.
+package postgrestest
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Schema creates an empty schema and returns TEST_DATABASE_URL with its
+// search_path set to it, for the test to connect with. The schema is dropped
+// when t ends. t is skipped when TEST_DATABASE_URL is not set.
+func Schema(t testing.TB) string {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	u, err := url.Parse(dsn)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		t.Fatalf("TEST_DATABASE_URL must be a postgres:// URL")
+	}
+
+	admin := Open(t, dsn)
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+			t.Error(err)
+		}
+	})
+
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// Open connects to dsn without logging and closes the connection when t ends.
+func Open(t testing.TB, dsn string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Close(t, db) })
+	return db
+}
+
+// Close closes the connections of db.
+func Close(t testing.TB, db *gorm.DB) {
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Close()
+	}
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,56 @@
+package tracing_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cashback-platform/pkg/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetupRejectsUnknownExporter(t *testing.T) {
+	if _, err := tracing.Setup("test", tracing.Config{Exporter: "jaeger"}); err == nil {
+		t.Fatal("expected an error for an unknown exporter")
+	}
+}
+
+func TestSetupWithoutExporter(t *testing.T) {
+	shutdown, err := tracing.Setup("test", tracing.Config{Exporter: tracing.ExporterNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileExporterFlushesOnShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := tracing.Setup("test-service", tracing.Config{
+		Exporter:    tracing.ExporterFile,
+		File:        path,
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "handle request")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"handle request", "test-service"} {
+		if !strings.Contains(string(spans), want) {
+			t.Fatalf("trace file is missing %q:\n%s", want, spans)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/database"
+	"github.com/cashback-platform/services/blockchain-adapter/service"
+)
+
+const migrateUsage = "usage: blockchain-adapter migrate [up | down [steps] | version]"
+
+// migrate runs the migrate subcommand:
+//
+//	migrate up          apply every pending migration
+//	migrate down [N]    revert the last N migrations (default 1)
+//	migrate version     print the current and latest schema versions
+func migrate(args []string) error {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	log := service.NewLogger(cfg)
+	db, err := database.Connect(cfg, log)
+	if err != nil {
+		return err
+	}
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch {
+	case command == "up" && len(args) <= 1:
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info("migrations applied", "count", applied, "version", migrator.Latest())
+	case command == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive integer\n%s", migrateUsage)
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info("migrations reverted", "count", reverted, "version", version)
+	case command == "version" && len(args) == 1:
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("current: %d\nlatest:  %d\n", version, migrator.Latest())
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
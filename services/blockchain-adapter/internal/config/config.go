@@ -1,14 +1,25 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 type (
 	Config struct {
 		App      AppConfig
+		Log      LogConfig
 		GRPC     GRPCConfig
 		Database DatabaseConfig
+		Custody  CustodyConfig
+		Chain    ChainConfig
+		Metrics  MetricsConfig
+		Tracing  TracingConfig
+		Health   HealthConfig
 	}
 
 	AppConfig struct {
@@ -16,6 +27,11 @@ type (
 		Env  string
 	}
 
+	// LogConfig sets the level below which records are dropped.
+	LogConfig struct {
+		Level slog.Level
+	}
+
 	GRPCConfig struct {
 		Port string
 	}
@@ -28,6 +44,52 @@ type (
 		Name     string
 		SSLMode  string
 	}
+
+	// CustodyConfig holds the HD seed custodial wallets are derived from.
+	// Custodial wallets are disabled while Seed is empty.
+	CustodyConfig struct {
+		Seed    string
+		Account uint32
+	}
+
+	// ChainConfig points at the JSON-RPC endpoint of the chain node and the
+	// token contract minted on it. An empty URL skips the readiness check.
+	// Minting is disabled while TokenAddress or MinterKey is empty, custodial
+	// transfers while TokenAddress is empty. MinterKey is the hex private key
+	// of an account holding the token's minter role.
+	// Mints wait up to ReceiptTimeout for their receipt before they are
+	// reported as submitted rather than confirmed.
+	ChainConfig struct {
+		RPCURL         string
+		TokenAddress   string
+		MinterKey      string
+		GasLimit       uint64
+		ReceiptTimeout time.Duration
+	}
+
+	// MetricsConfig sets the port /metrics is served on.
+	MetricsConfig struct {
+		Port string
+	}
+
+	// TracingConfig selects where spans are exported: none, otlp, stdout or
+	// file.
+	TracingConfig struct {
+		Exporter     string
+		OTLPEndpoint string
+		OTLPInsecure bool
+		File         string
+		SampleRatio  float64
+	}
+
+	// HealthConfig sets the port /livez and /readyz are served on, how long
+	// each readiness check may take, and how long readiness fails before the
+	// adapter stops on shutdown.
+	HealthConfig struct {
+		Port          string
+		CheckTimeout  time.Duration
+		ShutdownDelay time.Duration
+	}
 )
 
 func NewConfig() (*Config, error) {
@@ -42,6 +104,7 @@ func NewConfig() (*Config, error) {
 	// Defaults
 	viper.SetDefault("APP_NAME", "blockchain-adapter")
 	viper.SetDefault("APP_ENV", "development")
+	viper.SetDefault("LOG_LEVEL", "info")
 	viper.SetDefault("GRPC_PORT", "50051")
 	viper.SetDefault("DATABASE_HOST", "localhost")
 	viper.SetDefault("DATABASE_PORT", "5432")
@@ -49,14 +112,40 @@ func NewConfig() (*Config, error) {
 	viper.SetDefault("DATABASE_PASSWORD", "postgres")
 	viper.SetDefault("DATABASE_NAME", "blockchain_adapter_db")
 	viper.SetDefault("DATABASE_SSLMODE", "disable")
+	viper.SetDefault("CUSTODY_HD_SEED", "")
+	viper.SetDefault("CUSTODY_HD_ACCOUNT", 0)
+	viper.SetDefault("CHAIN_RPC_URL", "")
+	viper.SetDefault("CHAIN_TOKEN_ADDRESS", "")
+	viper.SetDefault("CHAIN_MINTER_KEY", "")
+	viper.SetDefault("CHAIN_GAS_LIMIT", 200000)
+	viper.SetDefault("CHAIN_RECEIPT_TIMEOUT", "30s")
+	viper.SetDefault("METRICS_PORT", "9092")
+
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_OTLP_ENDPOINT", "localhost:4317")
+	viper.SetDefault("TRACING_OTLP_INSECURE", true)
+	viper.SetDefault("TRACING_FILE", "traces.json")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+
+	viper.SetDefault("HEALTH_PORT", "8082")
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	viper.SetDefault("HEALTH_SHUTDOWN_DELAY", "0s")
 
 	_ = viper.ReadInConfig()
 
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(strings.TrimSpace(viper.GetString("LOG_LEVEL")))); err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL: %w", err)
+	}
+
 	return &Config{
 		App: AppConfig{
 			Name: viper.GetString("APP_NAME"),
 			Env:  viper.GetString("APP_ENV"),
 		},
+		Log: LogConfig{
+			Level: logLevel,
+		},
 		GRPC: GRPCConfig{
 			Port: viper.GetString("GRPC_PORT"),
 		},
@@ -68,5 +157,31 @@ func NewConfig() (*Config, error) {
 			Name:     viper.GetString("DATABASE_NAME"),
 			SSLMode:  viper.GetString("DATABASE_SSLMODE"),
 		},
+		Custody: CustodyConfig{
+			Seed:    viper.GetString("CUSTODY_HD_SEED"),
+			Account: viper.GetUint32("CUSTODY_HD_ACCOUNT"),
+		},
+		Chain: ChainConfig{
+			RPCURL:         viper.GetString("CHAIN_RPC_URL"),
+			TokenAddress:   viper.GetString("CHAIN_TOKEN_ADDRESS"),
+			MinterKey:      viper.GetString("CHAIN_MINTER_KEY"),
+			GasLimit:       viper.GetUint64("CHAIN_GAS_LIMIT"),
+			ReceiptTimeout: viper.GetDuration("CHAIN_RECEIPT_TIMEOUT"),
+		},
+		Metrics: MetricsConfig{
+			Port: viper.GetString("METRICS_PORT"),
+		},
+		Tracing: TracingConfig{
+			Exporter:     viper.GetString("TRACING_EXPORTER"),
+			OTLPEndpoint: viper.GetString("TRACING_OTLP_ENDPOINT"),
+			OTLPInsecure: viper.GetBool("TRACING_OTLP_INSECURE"),
+			File:         viper.GetString("TRACING_FILE"),
+			SampleRatio:  viper.GetFloat64("TRACING_SAMPLE_RATIO"),
+		},
+		Health: HealthConfig{
+			Port:          viper.GetString("HEALTH_PORT"),
+			CheckTimeout:  viper.GetDuration("HEALTH_CHECK_TIMEOUT"),
+			ShutdownDelay: viper.GetDuration("HEALTH_SHUTDOWN_DELAY"),
+		},
 	}, nil
 }
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrCustodialWalletNotFound = errors.New("custodial wallet not found")
+
+// CustodialWallet is a platform-held wallet derived for a user without a
+// self-custody address. DerivationIndex is the BIP-44 address index on the
+// custody account; indexes are allocated sequentially so the wallets can be
+// recovered from the seed alone.
+type CustodialWallet struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OwnerID         string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	DerivationIndex int64     `gorm:"uniqueIndex;not null"`
+	Address         string    `gorm:"type:varchar(42);uniqueIndex;not null"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (CustodialWallet) TableName() string {
+	return "custodial_wallets"
+}
@@ -1,11 +1,17 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrTransactionNotFound  = errors.New("blockchain transaction not found")
+	ErrDuplicateTransaction = errors.New("blockchain transaction with this ID or idempotency key already exists")
+)
+
 const (
 	TransactionStatusPending   TransactionStatus = "pending"
 	TransactionStatusSubmitted TransactionStatus = "submitted"
@@ -17,10 +23,12 @@ type (
 	// TransactionStatus represents the status of a blockchain transaction
 	TransactionStatus string
 
-	// BlockchainTransaction represents a blockchain transaction record
+	// BlockchainTransaction represents a blockchain transaction record.
+	// FromAddress is empty for mints and holds the custodial wallet for transfers.
 	BlockchainTransaction struct {
 		ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 		IdempotencyKey  uuid.UUID `gorm:"type:uuid;uniqueIndex;not null"`
+		FromAddress     string    `gorm:"type:varchar(42);not null;default:''"`
 		WalletAddress   string    `gorm:"type:varchar(42);not null"`
 		TokenAmount     string    `gorm:"type:varchar(78);not null"`
 		TransactionHash string    `gorm:"type:varchar(66)"`
@@ -31,9 +39,12 @@ type (
 		ErrorCode       string            `gorm:"type:varchar(100)"`
 		ErrorMessage    string            `gorm:"type:text"`
 		Nonce           int64
-		CreatedAt       time.Time `gorm:"autoCreateTime"`
-		UpdatedAt       time.Time `gorm:"autoUpdateTime"`
-		ConfirmedAt     *time.Time
+		// TraceContext holds the W3C trace context of the call that recorded
+		// the transaction, as JSON.
+		TraceContext []byte    `gorm:"type:jsonb"`
+		CreatedAt    time.Time `gorm:"autoCreateTime"`
+		UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+		ConfirmedAt  *time.Time
 	}
 )
 
@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/cashback-platform/pkg/health"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// healthServer answers the standard grpc.health.v1 Check from the readiness
+// checks, so gRPC probes see what /readyz reports. Only the server as a whole
+// (the empty service name) is known; Watch is not implemented.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	checker *health.Checker
+}
+
+func (s healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() != "" {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	if s.checker.Run(ctx).Status != health.StatusOK {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
@@ -2,94 +2,74 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 
+	"github.com/cashback-platform/pkg/health"
+	tokenpb "github.com/cashback-platform/proto/token"
 	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/hdwallet"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/metrics"
 	"github.com/cashback-platform/services/blockchain-adapter/internal/usecase"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/fx"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 // TokenServer implements the gRPC TokenService
-type (
-	TokenServer struct {
-		tokenUsecase *usecase.TokenUsecase
-	}
-
-	// MintTokenRequest represents a request to mint tokens
-	MintTokenRequest struct {
-		IdempotencyKey string
-		WalletAddress  string
-		TokenAmount    string
-	}
-
-	// MintTokenResponse represents the response from a mint operation
-	MintTokenResponse struct {
-		Success         bool
-		TransactionHash string
-		BlockNumber     int64
-		Status          string
-		Error           *MintError
-	}
-
-	// MintError represents an error in mint operation
-	MintError struct {
-		Code      string
-		Message   string
-		Retryable bool
-	}
-
-	// GetBalanceRequest represents a request to get balance
-	GetBalanceRequest struct {
-		WalletAddress string
-	}
-
-	// GetBalanceResponse represents the response from a balance query
-	GetBalanceResponse struct {
-		WalletAddress string
-		Balance       string
-		BlockNumber   int64
-	}
+type TokenServer struct {
+	tokenpb.UnimplementedTokenServiceServer
+	tokenUsecase   *usecase.TokenUsecase
+	custodyUsecase *usecase.CustodyUsecase
+}
 
-	// GetTransactionRequest represents a request to get transaction status
-	GetTransactionRequest struct {
-		TransactionHash string
+var (
+	mintStatuses = map[string]tokenpb.MintStatus{
+		string(domain.TransactionStatusPending):   tokenpb.MintStatus_MINT_STATUS_PENDING,
+		string(domain.TransactionStatusSubmitted): tokenpb.MintStatus_MINT_STATUS_SUBMITTED,
+		string(domain.TransactionStatusConfirmed): tokenpb.MintStatus_MINT_STATUS_CONFIRMED,
+		string(domain.TransactionStatusFailed):    tokenpb.MintStatus_MINT_STATUS_FAILED,
 	}
 
-	// GetTransactionResponse represents the response from a transaction query
-	GetTransactionResponse struct {
-		TransactionHash string
-		Status          string
-		BlockNumber     int64
-		Confirmations   int64
-		GasUsed         int64
-		Success         bool
+	transactionStatuses = map[string]tokenpb.TransactionStatus{
+		string(domain.TransactionStatusPending):   tokenpb.TransactionStatus_TRANSACTION_STATUS_PENDING,
+		string(domain.TransactionStatusSubmitted): tokenpb.TransactionStatus_TRANSACTION_STATUS_PENDING,
+		string(domain.TransactionStatusConfirmed): tokenpb.TransactionStatus_TRANSACTION_STATUS_CONFIRMED,
+		string(domain.TransactionStatusFailed):    tokenpb.TransactionStatus_TRANSACTION_STATUS_FAILED,
+		usecase.TransactionStatusNotFound:         tokenpb.TransactionStatus_TRANSACTION_STATUS_NOT_FOUND,
 	}
 )
 
-func NewTokenServer(tokenUsecase *usecase.TokenUsecase) *TokenServer {
-	return &TokenServer{tokenUsecase: tokenUsecase}
+func NewTokenServer(tokenUsecase *usecase.TokenUsecase, custodyUsecase *usecase.CustodyUsecase) *TokenServer {
+	return &TokenServer{
+		tokenUsecase:   tokenUsecase,
+		custodyUsecase: custodyUsecase,
+	}
 }
 
 // MintToken handles the MintToken gRPC call
-func (s *TokenServer) MintToken(ctx context.Context, req *MintTokenRequest) (*MintTokenResponse, error) {
-	result, err := s.tokenUsecase.MintToken(ctx, req.IdempotencyKey, req.WalletAddress, req.TokenAmount)
+func (s *TokenServer) MintToken(ctx context.Context, req *tokenpb.MintTokenRequest) (*tokenpb.MintTokenResponse, error) {
+	result, err := s.tokenUsecase.MintToken(ctx, req.GetIdempotencyKey(), req.GetWalletAddress(), req.GetTokenAmount())
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 
-	response := &MintTokenResponse{
+	response := &tokenpb.MintTokenResponse{
 		Success:         result.Success,
 		TransactionHash: result.TransactionHash,
 		BlockNumber:     result.BlockNumber,
-		Status:          result.Status,
+		Status:          mintStatuses[result.Status],
 	}
 
 	if !result.Success {
-		response.Error = &MintError{
+		response.Error = &tokenpb.MintError{
 			Code:      result.ErrorCode,
 			Message:   result.ErrorMessage,
 			Retryable: result.Retryable,
@@ -100,13 +80,13 @@ func (s *TokenServer) MintToken(ctx context.Context, req *MintTokenRequest) (*Mi
 }
 
 // GetBalance handles the GetBalance gRPC call
-func (s *TokenServer) GetBalance(ctx context.Context, req *GetBalanceRequest) (*GetBalanceResponse, error) {
-	result, err := s.tokenUsecase.GetBalance(ctx, req.WalletAddress)
+func (s *TokenServer) GetBalance(ctx context.Context, req *tokenpb.GetBalanceRequest) (*tokenpb.GetBalanceResponse, error) {
+	result, err := s.tokenUsecase.GetBalance(ctx, req.GetWalletAddress())
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 
-	return &GetBalanceResponse{
+	return &tokenpb.GetBalanceResponse{
 		WalletAddress: result.WalletAddress,
 		Balance:       result.Balance,
 		BlockNumber:   result.BlockNumber,
@@ -114,15 +94,15 @@ func (s *TokenServer) GetBalance(ctx context.Context, req *GetBalanceRequest) (*
 }
 
 // GetTransaction handles the GetTransaction gRPC call
-func (s *TokenServer) GetTransaction(ctx context.Context, req *GetTransactionRequest) (*GetTransactionResponse, error) {
-	result, err := s.tokenUsecase.GetTransaction(ctx, req.TransactionHash)
+func (s *TokenServer) GetTransaction(ctx context.Context, req *tokenpb.GetTransactionRequest) (*tokenpb.GetTransactionResponse, error) {
+	result, err := s.tokenUsecase.GetTransaction(ctx, req.GetTransactionHash())
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 
-	return &GetTransactionResponse{
+	return &tokenpb.GetTransactionResponse{
 		TransactionHash: result.TransactionHash,
-		Status:          result.Status,
+		Status:          transactionStatuses[result.Status],
 		BlockNumber:     result.BlockNumber,
 		Confirmations:   result.Confirmations,
 		GasUsed:         result.GasUsed,
@@ -130,8 +110,67 @@ func (s *TokenServer) GetTransaction(ctx context.Context, req *GetTransactionReq
 	}, nil
 }
 
-func StartServer(lc fx.Lifecycle, _ *TokenServer, cfg *config.Config) {
-	server := grpc.NewServer()
+// DeriveCustodialAddress handles the DeriveCustodialAddress gRPC call
+func (s *TokenServer) DeriveCustodialAddress(ctx context.Context, req *tokenpb.DeriveCustodialAddressRequest) (*tokenpb.DeriveCustodialAddressResponse, error) {
+	result, err := s.custodyUsecase.DeriveCustodialAddress(ctx, req.GetOwnerId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &tokenpb.DeriveCustodialAddressResponse{
+		OwnerId:        result.OwnerID,
+		WalletAddress:  result.WalletAddress,
+		DerivationPath: result.DerivationPath,
+	}, nil
+}
+
+// TransferToken handles the TransferToken gRPC call
+func (s *TokenServer) TransferToken(ctx context.Context, req *tokenpb.TransferTokenRequest) (*tokenpb.TransferTokenResponse, error) {
+	result, err := s.custodyUsecase.TransferToken(ctx, req.GetIdempotencyKey(), req.GetOwnerId(), req.GetToAddress(), req.GetTokenAmount())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &tokenpb.TransferTokenResponse{
+		Success:         result.Success,
+		TransactionHash: result.TransactionHash,
+		FromAddress:     result.FromAddress,
+		Status:          transactionStatuses[result.Status],
+	}
+
+	if !result.Success {
+		response.Error = &tokenpb.MintError{
+			Code:      result.ErrorCode,
+			Message:   result.ErrorMessage,
+			Retryable: result.Retryable,
+		}
+	}
+
+	return response, nil
+}
+
+// statusError gives usecase errors the status code callers act on. Anything
+// else is Unknown, as gRPC reports plain errors.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, hdwallet.ErrInvalidAddress), errors.Is(err, usecase.ErrOwnerRequired):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrMintingDisabled), errors.Is(err, usecase.ErrCustodyDisabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
+
+func StartServer(lc fx.Lifecycle, tokenServer *TokenServer, cfg *config.Config, m *metrics.GRPCServer, checker *health.Checker, log *slog.Logger) {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(m.UnaryInterceptor(), loggingInterceptor(log)),
+		// Continues the caller's trace from the request metadata.
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
+
+	tokenpb.RegisterTokenServiceServer(server, tokenServer)
+	healthpb.RegisterHealthServer(server, healthServer{checker: checker})
 
 	// Register reflection for debugging
 	reflection.Register(server)
@@ -144,16 +183,16 @@ func StartServer(lc fx.Lifecycle, _ *TokenServer, cfg *config.Config) {
 			}
 
 			go func() {
-				log.Printf("gRPC server starting on port %s", cfg.GRPC.Port)
+				log.Info("gRPC server starting", "port", cfg.GRPC.Port)
 				if err := server.Serve(listener); err != nil {
-					log.Printf("gRPC server error: %v", err)
+					log.Error("gRPC server failed", "error", err)
 				}
 			}()
 
 			return nil
 		},
 		OnStop: func(_ context.Context) error {
-			log.Println("Shutting down gRPC server...")
+			log.Info("shutting down gRPC server")
 			server.GracefulStop()
 			return nil
 		},
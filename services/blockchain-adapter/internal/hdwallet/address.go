@@ -0,0 +1,55 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// AddressLength is the length of a 0x-prefixed hex address.
+const AddressLength = 42
+
+var ErrInvalidAddress = errors.New("address must be 0x followed by 40 hex characters")
+
+// ValidateAddress checks that address is a 0x-prefixed 20-byte hex address.
+func ValidateAddress(address string) error {
+	if len(address) != AddressLength || !strings.HasPrefix(address, "0x") {
+		return ErrInvalidAddress
+	}
+	if _, err := hex.DecodeString(address[2:]); err != nil {
+		return ErrInvalidAddress
+	}
+	return nil
+}
+
+// ChecksumAddress returns the EIP-55 mixed-case encoding of a hex address.
+func ChecksumAddress(address string) string {
+	digits := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	hash := Keccak256([]byte(digits))
+
+	out := []byte(digits)
+	for i, c := range out {
+		if c < 'a' || c > 'f' {
+			continue
+		}
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// Keccak256 returns the legacy Keccak-256 digest used throughout Ethereum.
+func Keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,173 @@
+// Package hdwallet derives Ethereum accounts from a BIP-32 seed following the
+// BIP-44 layout m/44'/60'/account'/0/index. It backs the custodial wallets the
+// platform holds for users without a self-custody address.
+package hdwallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	// HardenedOffset is added to an index to derive a hardened child.
+	HardenedOffset uint32 = 0x80000000
+
+	// PurposeBIP44 and CoinTypeEthereum are the fixed hardened levels of
+	// Ethereum BIP-44 paths.
+	PurposeBIP44     uint32 = 44
+	CoinTypeEthereum uint32 = 60
+
+	minSeedLength = 16
+	maxSeedLength = 64
+)
+
+// masterKeySalt is the HMAC key BIP-32 uses to derive the master key.
+var masterKeySalt = []byte("Bitcoin seed")
+
+var (
+	ErrInvalidSeed = errors.New("HD seed must be 16 to 64 bytes of hex")
+	ErrInvalidKey  = errors.New("derived key is invalid")
+)
+
+type (
+	// Key is an extended private key: a secp256k1 private key and its chain code.
+	Key struct {
+		key       [32]byte
+		chainCode [32]byte
+	}
+
+	// Wallet derives the external chain addresses of one BIP-44 Ethereum account.
+	Wallet struct {
+		account uint32
+		chain   Key
+	}
+)
+
+// NewMasterKey derives the BIP-32 master key from a seed.
+func NewMasterKey(seed []byte) (Key, error) {
+	if len(seed) < minSeedLength || len(seed) > maxSeedLength {
+		return Key{}, ErrInvalidSeed
+	}
+
+	mac := hmac.New(sha512.New, masterKeySalt)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	var scalar secp256k1.ModNScalar
+	if overflow := scalar.SetByteSlice(sum[:32]); overflow || scalar.IsZero() {
+		return Key{}, ErrInvalidKey
+	}
+
+	var master Key
+	copy(master.key[:], sum[:32])
+	copy(master.chainCode[:], sum[32:])
+	return master, nil
+}
+
+// Child derives the child key at index. Indexes at or above HardenedOffset
+// derive hardened children.
+func (k Key) Child(index uint32) (Key, error) {
+	data := make([]byte, 0, 37)
+	if index >= HardenedOffset {
+		data = append(data, 0x00)
+		data = append(data, k.key[:]...)
+	} else {
+		data = append(data, k.PrivateKey().PubKey().SerializeCompressed()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode[:])
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	var tweak, parent secp256k1.ModNScalar
+	if overflow := tweak.SetByteSlice(sum[:32]); overflow {
+		return Key{}, ErrInvalidKey
+	}
+	parent.SetBytes(&k.key)
+
+	child := tweak.Add(&parent)
+	if child.IsZero() {
+		return Key{}, ErrInvalidKey
+	}
+
+	var derived Key
+	derived.key = child.Bytes()
+	copy(derived.chainCode[:], sum[32:])
+	return derived, nil
+}
+
+// Derive walks path from k, one child index per level.
+func (k Key) Derive(path ...uint32) (Key, error) {
+	var err error
+	for _, index := range path {
+		if k, err = k.Child(index); err != nil {
+			return Key{}, err
+		}
+	}
+	return k, nil
+}
+
+// PrivateKey returns the secp256k1 private key.
+func (k Key) PrivateKey() *secp256k1.PrivateKey {
+	return secp256k1.PrivKeyFromBytes(k.key[:])
+}
+
+// Address returns the EIP-55 checksummed Ethereum address of the key.
+func (k Key) Address() string {
+	pub := k.PrivateKey().PubKey().SerializeUncompressed()
+	return ChecksumAddress("0x" + hex.EncodeToString(Keccak256(pub[1:])[12:]))
+}
+
+// NewWallet derives the external chain of BIP-44 account from a hex-encoded seed.
+func NewWallet(seedHex string, account uint32) (*Wallet, error) {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return nil, ErrInvalidSeed
+	}
+
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := master.Derive(
+		PurposeBIP44+HardenedOffset,
+		CoinTypeEthereum+HardenedOffset,
+		account+HardenedOffset,
+		0,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{account: account, chain: chain}, nil
+}
+
+// Key returns the key at index on the account's external chain.
+func (w *Wallet) Key(index uint32) (Key, error) {
+	if index >= HardenedOffset {
+		return Key{}, fmt.Errorf("address index %d out of range", index)
+	}
+	return w.chain.Child(index)
+}
+
+// Address returns the address at index on the account's external chain.
+func (w *Wallet) Address(index uint32) (string, error) {
+	key, err := w.Key(index)
+	if err != nil {
+		return "", err
+	}
+	return key.Address(), nil
+}
+
+// Path returns the BIP-44 derivation path of the address at index.
+func (w *Wallet) Path(index uint32) string {
+	return fmt.Sprintf("m/%d'/%d'/%d'/0/%d", PurposeBIP44, CoinTypeEthereum, w.account, index)
+}
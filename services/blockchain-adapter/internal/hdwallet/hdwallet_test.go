@@ -0,0 +1,124 @@
+package hdwallet_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/hdwallet"
+)
+
+const h = hdwallet.HardenedOffset
+
+// The private keys of BIP-32 test vector 1.
+func TestDeriveMatchesBIP32TestVector1(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := hdwallet.NewMasterKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		path []uint32
+		want string
+	}{
+		{nil, "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{[]uint32{0 + h}, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{[]uint32{0 + h, 1}, "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{[]uint32{0 + h, 1, 2 + h}, "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{[]uint32{0 + h, 1, 2 + h, 2}, "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{[]uint32{0 + h, 1, 2 + h, 2, 1000000000}, "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	} {
+		key, err := master.Derive(tc.path...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key.PrivateKey().Serialize()); got != tc.want {
+			t.Errorf("key at %v = %s, want %s", tc.path, got, tc.want)
+		}
+	}
+}
+
+// The seed of the mnemonic "abandon abandon ... about" with no passphrase,
+// whose first Ethereum account is published by most wallets.
+const abandonSeed = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc1" +
+	"9a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+
+func TestWalletDerivesEthereumAddresses(t *testing.T) {
+	wallet, err := hdwallet.NewWallet(abandonSeed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address, err := wallet.Address(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"; address != want {
+		t.Fatalf("address 0 = %s, want %s", address, want)
+	}
+	if got, want := wallet.Path(7), "m/44'/60'/0'/0/7"; got != want {
+		t.Fatalf("path = %s, want %s", got, want)
+	}
+
+	key, err := wallet.Key(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Address() != address {
+		t.Fatalf("key address %s, want %s", key.Address(), address)
+	}
+
+	other, err := wallet.Address(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == address {
+		t.Fatal("two indexes derive the same address")
+	}
+}
+
+func TestWalletAccountsAreSeparate(t *testing.T) {
+	first, err := hdwallet.NewWallet(abandonSeed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := hdwallet.NewWallet(abandonSeed, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := first.Address(0)
+	b, _ := second.Address(0)
+	if a == b {
+		t.Fatal("two accounts derive the same address")
+	}
+	if got, want := second.Path(0), "m/44'/60'/1'/0/0"; got != want {
+		t.Fatalf("path = %s, want %s", got, want)
+	}
+}
+
+func TestWalletRejectsInvalidInput(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		seed string
+	}{
+		{"not hex", "zz"},
+		{"too short", "000102030405060708090a0b0c0d0e"},
+		{"too long", abandonSeed + "00"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := hdwallet.NewWallet(tc.seed, 0); !errors.Is(err, hdwallet.ErrInvalidSeed) {
+				t.Fatalf("error = %v, want %v", err, hdwallet.ErrInvalidSeed)
+			}
+		})
+	}
+
+	wallet, err := hdwallet.NewWallet(abandonSeed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.Address(h); err == nil {
+		t.Fatal("derived a hardened address index")
+	}
+}
@@ -0,0 +1,54 @@
+package chain
+
+import (
+	"math/big"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/hdwallet"
+)
+
+// Selectors of the token contract functions the adapter calls. mint is the
+// OpenZeppelin ERC20 extension restricted to the minter role.
+var (
+	mintSelector      = selector("mint(address,uint256)")
+	transferSelector  = selector("transfer(address,uint256)")
+	balanceOfSelector = selector("balanceOf(address)")
+)
+
+// MintCall is the call data of mint(to, amount).
+func MintCall(to string, amount *big.Int) ([]byte, error) {
+	return addressAmountCall(mintSelector, to, amount)
+}
+
+// TransferCall is the call data of transfer(to, amount).
+func TransferCall(to string, amount *big.Int) ([]byte, error) {
+	return addressAmountCall(transferSelector, to, amount)
+}
+
+// BalanceOfCall is the call data of balanceOf(owner).
+func BalanceOfCall(owner string) ([]byte, error) {
+	address, err := decodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, balanceOfSelector...), word(address)...), nil
+}
+
+func addressAmountCall(selector []byte, to string, amount *big.Int) ([]byte, error) {
+	address, err := decodeAddress(to)
+	if err != nil {
+		return nil, err
+	}
+	data := append(append([]byte{}, selector...), word(address)...)
+	return append(data, word(amount.Bytes())...), nil
+}
+
+// word left-pads b to a 32-byte ABI word.
+func word(b []byte) []byte {
+	padded := make([]byte, 32)
+	copy(padded[32-len(b):], b)
+	return padded
+}
+
+func selector(signature string) []byte {
+	return hdwallet.Keccak256([]byte(signature))[:4]
+}
@@ -0,0 +1,212 @@
+// Package chain talks to the chain node over JSON-RPC.
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+)
+
+// Node is the JSON-RPC endpoint at CHAIN_RPC_URL.
+type Node struct {
+	url    string
+	client *http.Client
+}
+
+// Receipt is the outcome of a mined transaction.
+type Receipt struct {
+	BlockNumber uint64
+	GasUsed     uint64
+	// Succeeded is false when the transaction reverted.
+	Succeeded bool
+}
+
+type (
+	rpcRequest struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      int    `json:"id"`
+		Method  string `json:"method"`
+		Params  []any  `json:"params"`
+	}
+
+	rpcResponse struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+)
+
+func NewNode(cfg *config.Config) *Node {
+	return &Node{url: cfg.Chain.RPCURL, client: http.DefaultClient}
+}
+
+// Configured reports whether CHAIN_RPC_URL is set.
+func (n *Node) Configured() bool {
+	return n.url != ""
+}
+
+// BlockNumber returns the number of the latest block the node knows of.
+func (n *Node) BlockNumber(ctx context.Context) (uint64, error) {
+	return n.quantity(ctx, "eth_blockNumber")
+}
+
+// Check fails unless the node answers eth_blockNumber.
+func (n *Node) Check(ctx context.Context) error {
+	_, err := n.BlockNumber(ctx)
+	return err
+}
+
+// ChainID returns the ID transactions must be signed for.
+func (n *Node) ChainID(ctx context.Context) (*big.Int, error) {
+	return n.bigQuantity(ctx, "eth_chainId")
+}
+
+// GasPrice returns the gas price the node suggests, in wei.
+func (n *Node) GasPrice(ctx context.Context) (*big.Int, error) {
+	return n.bigQuantity(ctx, "eth_gasPrice")
+}
+
+// PendingNonce returns the nonce of the next transaction from address,
+// counting the transactions the node holds but has not mined yet.
+func (n *Node) PendingNonce(ctx context.Context, address string) (uint64, error) {
+	return n.quantity(ctx, "eth_getTransactionCount", address, "pending")
+}
+
+// SendRawTransaction broadcasts a signed transaction and returns its hash.
+func (n *Node) SendRawTransaction(ctx context.Context, raw []byte) (string, error) {
+	var hash string
+	if err := n.call(ctx, "eth_sendRawTransaction", &hash, "0x"+hex.EncodeToString(raw)); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// TransactionKnown reports whether the node has the transaction, mined or not.
+func (n *Node) TransactionKnown(ctx context.Context, hash string) (bool, error) {
+	var tx json.RawMessage
+	if err := n.call(ctx, "eth_getTransactionByHash", &tx, hash); err != nil {
+		return false, err
+	}
+	return string(tx) != "null", nil
+}
+
+// TransactionReceipt returns the receipt of a mined transaction, or nil if
+// the transaction is unknown or not mined yet.
+func (n *Node) TransactionReceipt(ctx context.Context, hash string) (*Receipt, error) {
+	var raw *struct {
+		BlockNumber string `json:"blockNumber"`
+		GasUsed     string `json:"gasUsed"`
+		Status      string `json:"status"`
+	}
+	if err := n.call(ctx, "eth_getTransactionReceipt", &raw, hash); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	var receipt Receipt
+	var err error
+	if receipt.BlockNumber, err = parseQuantity(raw.BlockNumber); err != nil {
+		return nil, fmt.Errorf("eth_getTransactionReceipt: invalid block number: %w", err)
+	}
+	if receipt.GasUsed, err = parseQuantity(raw.GasUsed); err != nil {
+		return nil, fmt.Errorf("eth_getTransactionReceipt: invalid gas used: %w", err)
+	}
+	status, err := parseQuantity(raw.Status)
+	if err != nil {
+		return nil, fmt.Errorf("eth_getTransactionReceipt: invalid status: %w", err)
+	}
+	receipt.Succeeded = status == 1
+	return &receipt, nil
+}
+
+// CallContract runs a read-only call of data on the contract at to, against
+// the state at block.
+func (n *Node) CallContract(ctx context.Context, to string, data []byte, block uint64) ([]byte, error) {
+	call := map[string]string{"to": to, "data": "0x" + hex.EncodeToString(data)}
+	var result string
+	if err := n.call(ctx, "eth_call", &result, call, fmt.Sprintf("0x%x", block)); err != nil {
+		return nil, err
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("eth_call: invalid result %q", result)
+	}
+	return b, nil
+}
+
+func (n *Node) quantity(ctx context.Context, method string, params ...any) (uint64, error) {
+	var result string
+	if err := n.call(ctx, method, &result, params...); err != nil {
+		return 0, err
+	}
+	number, err := parseQuantity(result)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid result %q", method, result)
+	}
+	return number, nil
+}
+
+func (n *Node) bigQuantity(ctx context.Context, method string, params ...any) (*big.Int, error) {
+	var result string
+	if err := n.call(ctx, method, &result, params...); err != nil {
+		return nil, err
+	}
+	number, ok := new(big.Int).SetString(strings.TrimPrefix(result, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid result %q", method, result)
+	}
+	return number, nil
+}
+
+func parseQuantity(s string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+}
+
+func (n *Node) call(ctx context.Context, method string, result any, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: node returned %s", method, resp.Status)
+	}
+
+	var decoded rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if decoded.Error != nil {
+		return fmt.Errorf("%s: %s (%d)", method, decoded.Error.Message, decoded.Error.Code)
+	}
+	if len(decoded.Result) == 0 {
+		return errors.New(method + ": empty result")
+	}
+	return json.Unmarshal(decoded.Result, result)
+}
@@ -0,0 +1,48 @@
+package chain
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// encodeRLP encodes item in Ethereum's recursive length prefix encoding.
+// Strings are []byte, lists are []any; unsigned integers are encoded as
+// big-endian strings with no leading zeros, so zero is the empty string.
+func encodeRLP(item any) []byte {
+	switch v := item.(type) {
+	case []byte:
+		if len(v) == 1 && v[0] < 0x80 {
+			return v
+		}
+		return append(rlpHeader(0x80, len(v)), v...)
+	case uint64:
+		return encodeRLP(trimLeadingZeros(binary.BigEndian.AppendUint64(nil, v)))
+	case *big.Int:
+		return encodeRLP(v.Bytes())
+	case []any:
+		var payload []byte
+		for _, element := range v {
+			payload = append(payload, encodeRLP(element)...)
+		}
+		return append(rlpHeader(0xc0, len(payload)), payload...)
+	default:
+		panic("rlp: unsupported type")
+	}
+}
+
+// rlpHeader is the prefix of a string (offset 0x80) or list (offset 0xc0)
+// whose payload is size bytes long.
+func rlpHeader(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+	length := trimLeadingZeros(binary.BigEndian.AppendUint64(nil, uint64(size)))
+	return append([]byte{offset + 55 + byte(len(length))}, length...)
+}
+
+func trimLeadingZeros(b []byte) []byte {
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
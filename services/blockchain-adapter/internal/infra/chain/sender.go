@@ -0,0 +1,72 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// ErrNotBroadcast wraps the node errors that kept a transaction from being
+// broadcast. The transaction may be retried with a new nonce.
+var ErrNotBroadcast = errors.New("transaction not broadcast")
+
+// Sender signs contract calls and broadcasts them to the node.
+//
+// Nonces are the node's pending transaction count, so Send holds a lock from
+// reading the nonce until the node has the transaction: the adapter must be
+// the only process sending from its keys.
+type Sender struct {
+	node     *Node
+	gasLimit uint64
+
+	mu      sync.Mutex
+	chainID *big.Int
+}
+
+func NewSender(cfg *config.Config, node *Node) *Sender {
+	return &Sender{node: node, gasLimit: cfg.Chain.GasLimit}
+}
+
+// Send signs a call of data on the contract at to with key and broadcasts it.
+// record is called with the signed transaction before it is broadcast, so a
+// transaction is never on chain without the adapter knowing its hash; an
+// error from record stops the broadcast and is returned as is. Node errors
+// wrap ErrNotBroadcast.
+func (s *Sender) Send(ctx context.Context, key *secp256k1.PrivateKey, to string, data []byte, record func(SignedTransaction) error) (SignedTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.chainID == nil {
+		chainID, err := s.node.ChainID(ctx)
+		if err != nil {
+			return SignedTransaction{}, fmt.Errorf("%w: %w", ErrNotBroadcast, err)
+		}
+		s.chainID = chainID
+	}
+	nonce, err := s.node.PendingNonce(ctx, AddressOf(key))
+	if err != nil {
+		return SignedTransaction{}, fmt.Errorf("%w: %w", ErrNotBroadcast, err)
+	}
+	gasPrice, err := s.node.GasPrice(ctx)
+	if err != nil {
+		return SignedTransaction{}, fmt.Errorf("%w: %w", ErrNotBroadcast, err)
+	}
+
+	tx := Transaction{Nonce: nonce, GasPrice: gasPrice, Gas: s.gasLimit, To: to, Data: data}
+	signed, err := tx.Sign(key, s.chainID)
+	if err != nil {
+		return SignedTransaction{}, err
+	}
+	if err := record(signed); err != nil {
+		return SignedTransaction{}, err
+	}
+	if _, err := s.node.SendRawTransaction(ctx, signed.Raw); err != nil {
+		return signed, fmt.Errorf("%w: %w", ErrNotBroadcast, err)
+	}
+	return signed, nil
+}
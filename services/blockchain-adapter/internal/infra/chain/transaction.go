@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/hdwallet"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+type (
+	// Transaction is a legacy (pre EIP-1559) Ethereum transaction.
+	Transaction struct {
+		Nonce    uint64
+		GasPrice *big.Int
+		Gas      uint64
+		// To is the 0x-prefixed address called.
+		To    string
+		Value *big.Int
+		Data  []byte
+	}
+
+	// SignedTransaction is a transaction ready for eth_sendRawTransaction.
+	SignedTransaction struct {
+		Transaction
+		// Raw is the RLP encoding of the signed transaction.
+		Raw []byte
+		// Hash is the 0x-prefixed transaction hash, the Keccak-256 of Raw.
+		Hash string
+	}
+)
+
+// Sign signs tx with key for chainID as EIP-155 specifies, so it cannot be
+// replayed on another chain.
+func (tx Transaction) Sign(key *secp256k1.PrivateKey, chainID *big.Int) (SignedTransaction, error) {
+	to, err := decodeAddress(tx.To)
+	if err != nil {
+		return SignedTransaction{}, err
+	}
+	value := tx.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	gasPrice := tx.GasPrice
+	if gasPrice == nil {
+		gasPrice = new(big.Int)
+	}
+	fields := []any{tx.Nonce, gasPrice, tx.Gas, to, value, tx.Data}
+
+	// The signed hash covers the chain ID and two empty fields; the
+	// signature replaces them in the encoded transaction.
+	hash := hdwallet.Keccak256(encodeRLP(append(fields, chainID, uint64(0), uint64(0))))
+
+	// The compact signature is <27 + recovery id> || R || S.
+	compact := ecdsa.SignCompact(key, hash, false)
+	v := new(big.Int).Mul(chainID, big.NewInt(2))
+	v.Add(v, big.NewInt(int64(compact[0]-27)+35))
+	r := new(big.Int).SetBytes(compact[1:33])
+	s := new(big.Int).SetBytes(compact[33:65])
+
+	raw := encodeRLP(append(fields, v, r, s))
+	return SignedTransaction{
+		Transaction: tx,
+		Raw:         raw,
+		Hash:        "0x" + hex.EncodeToString(hdwallet.Keccak256(raw)),
+	}, nil
+}
+
+// AddressOf returns the checksummed address of key.
+func AddressOf(key *secp256k1.PrivateKey) string {
+	hash := hdwallet.Keccak256(key.PubKey().SerializeUncompressed()[1:])
+	return hdwallet.ChecksumAddress("0x" + hex.EncodeToString(hash[12:]))
+}
+
+// ParsePrivateKey parses a hex-encoded secp256k1 private key, with or without
+// a 0x prefix.
+func ParsePrivateKey(s string) (*secp256k1.PrivateKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(b) != 32 {
+		return nil, errors.New("private key must be 32 hex-encoded bytes")
+	}
+	key := secp256k1.PrivKeyFromBytes(b)
+	if key.Key.IsZero() {
+		return nil, errors.New("private key must not be zero")
+	}
+	return key, nil
+}
+
+func decodeAddress(address string) ([]byte, error) {
+	if err := hdwallet.ValidateAddress(address); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(address[2:])
+}
@@ -0,0 +1,124 @@
+package chain_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/chain"
+)
+
+func TestSignMatchesTheEIP155Example(t *testing.T) {
+	// The worked example of EIP-155.
+	key, err := chain.ParsePrivateKey("0x4646464646464646464646464646464646464646464646464646464646464646")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := chain.Transaction{
+		Nonce:    9,
+		GasPrice: big.NewInt(20_000_000_000),
+		Gas:      21000,
+		To:       "0x3535353535353535353535353535353535353535",
+		Value:    new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil),
+	}
+
+	signed, err := tx.Sign(key, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a7640000" +
+		"8025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276" +
+		"a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	if got := hex.EncodeToString(signed.Raw); got != want {
+		t.Fatalf("raw transaction\n got %s\nwant %s", got, want)
+	}
+	if want := "0x33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788"; signed.Hash != want {
+		t.Fatalf("hash %s, want %s", signed.Hash, want)
+	}
+	if want := "0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"; chain.AddressOf(key) != want {
+		t.Fatalf("address %s, want %s", chain.AddressOf(key), want)
+	}
+}
+
+func TestSignEncodesTheChainIDInV(t *testing.T) {
+	key, err := chain.ParsePrivateKey("4646464646464646464646464646464646464646464646464646464646464646")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := chain.Transaction{Gas: 21000, To: "0x3535353535353535353535353535353535353535"}
+
+	mainnet, err := tx.Sign(key, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := tx.Sign(key, big.NewInt(31337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mainnet.Hash == local.Hash {
+		t.Fatal("the same transaction signed for two chains has one hash")
+	}
+}
+
+func TestSignRejectsInvalidRecipients(t *testing.T) {
+	key, err := chain.ParsePrivateKey("4646464646464646464646464646464646464646464646464646464646464646")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, to := range []string{"", "0x35", "3535353535353535353535353535353535353535", "0xzz35353535353535353535353535353535353535"} {
+		if _, err := (chain.Transaction{To: to}).Sign(key, big.NewInt(1)); err == nil {
+			t.Errorf("signed a transaction to %q", to)
+		}
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	for _, tc := range []struct {
+		key   string
+		valid bool
+	}{
+		{"0x4646464646464646464646464646464646464646464646464646464646464646", true},
+		{"4646464646464646464646464646464646464646464646464646464646464646", true},
+		{"0x46", false},
+		{"0x" + "00000000000000000000000000000000000000000000000000000000000000000", false},
+		{"0x0000000000000000000000000000000000000000000000000000000000000000", false},
+		{"not hex", false},
+	} {
+		_, err := chain.ParsePrivateKey(tc.key)
+		if (err == nil) != tc.valid {
+			t.Errorf("ParsePrivateKey(%q) error = %v, want valid %t", tc.key, err, tc.valid)
+		}
+	}
+}
+
+func TestCallData(t *testing.T) {
+	const holder = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	amount := big.NewInt(1_000_000)
+	padded := "0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+	paddedAmount := "00000000000000000000000000000000000000000000000000000000000f4240"
+
+	for _, tc := range []struct {
+		name string
+		call func() ([]byte, error)
+		want string
+	}{
+		{"mint", func() ([]byte, error) { return chain.MintCall(holder, amount) }, "40c10f19" + padded + paddedAmount},
+		{"transfer", func() ([]byte, error) { return chain.TransferCall(holder, amount) }, "a9059cbb" + padded + paddedAmount},
+		{"balanceOf", func() ([]byte, error) { return chain.BalanceOfCall(holder) }, "70a08231" + padded},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(data); got != tc.want {
+				t.Fatalf("call data\n got %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := chain.MintCall("0x5aAe", amount); err == nil {
+		t.Fatal("encoded a mint to an invalid address")
+	}
+}
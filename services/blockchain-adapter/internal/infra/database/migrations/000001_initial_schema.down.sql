@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS custodial_wallets;
+DROP TABLE IF EXISTS wallet_nonces;
+DROP TABLE IF EXISTS blockchain_transactions;
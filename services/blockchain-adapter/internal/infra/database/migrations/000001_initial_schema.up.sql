@@ -0,0 +1,43 @@
+-- Baseline schema, matching what AutoMigrate created before versioned
+-- migrations. IF NOT EXISTS lets those databases adopt this version as is.
+
+CREATE TABLE IF NOT EXISTS blockchain_transactions (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    idempotency_key  UUID NOT NULL,
+    from_address     VARCHAR(42) NOT NULL DEFAULT '',
+    wallet_address   VARCHAR(42) NOT NULL,
+    token_amount     VARCHAR(78) NOT NULL,
+    transaction_hash VARCHAR(66),
+    block_number     BIGINT,
+    gas_used         BIGINT,
+    gas_price        VARCHAR(78),
+    status           VARCHAR(50) NOT NULL DEFAULT 'pending',
+    error_code       VARCHAR(100),
+    error_message    TEXT,
+    nonce            BIGINT,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ,
+    confirmed_at     TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_blockchain_transactions_idempotency_key ON blockchain_transactions (idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_blockchain_transactions_status ON blockchain_transactions (status);
+
+CREATE TABLE IF NOT EXISTS wallet_nonces (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    wallet_address VARCHAR(42) NOT NULL,
+    current_nonce  BIGINT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_nonces_wallet_address ON wallet_nonces (wallet_address);
+
+CREATE TABLE IF NOT EXISTS custodial_wallets (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id         VARCHAR(64) NOT NULL,
+    derivation_index BIGINT NOT NULL,
+    address          VARCHAR(42) NOT NULL,
+    created_at       TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custodial_wallets_owner_id ON custodial_wallets (owner_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custodial_wallets_derivation_index ON custodial_wallets (derivation_index);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custodial_wallets_address ON custodial_wallets (address);
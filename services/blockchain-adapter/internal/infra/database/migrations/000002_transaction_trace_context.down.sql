@@ -0,0 +1 @@
+ALTER TABLE blockchain_transactions DROP COLUMN IF EXISTS trace_context;
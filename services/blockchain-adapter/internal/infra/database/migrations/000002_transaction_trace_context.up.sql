@@ -0,0 +1,3 @@
+-- W3C trace context of the call that recorded the transaction, so its
+-- confirmation joins the same trace.
+ALTER TABLE blockchain_transactions ADD COLUMN IF NOT EXISTS trace_context JSONB;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS wallet_nonces (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    wallet_address VARCHAR(42) NOT NULL,
+    current_nonce  BIGINT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_nonces_wallet_address ON wallet_nonces (wallet_address);
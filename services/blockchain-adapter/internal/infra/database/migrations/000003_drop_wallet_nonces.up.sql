@@ -0,0 +1,3 @@
+-- Nonces come from the node's pending transaction count; nothing reads or
+-- writes this table any more.
+DROP TABLE IF EXISTS wallet_nonces;
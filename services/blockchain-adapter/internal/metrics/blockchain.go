@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Transaction kinds, told apart by whether the tokens leave a custodial wallet.
+const (
+	KindMint     = "mint"
+	KindTransfer = "transfer"
+)
+
+var weiPerGwei = big.NewFloat(1e9)
+
+type (
+	// Blockchain records what on-chain transactions cost and how long they
+	// take to confirm.
+	Blockchain struct {
+		finished     *prometheus.CounterVec
+		gasUsed      *prometheus.CounterVec
+		fees         *prometheus.CounterVec
+		confirmation *prometheus.HistogramVec
+	}
+
+	// instrumentedTransactions observes transactions as they are marked
+	// confirmed or failed, whichever code path does it.
+	instrumentedTransactions struct {
+		repository.TransactionRepository
+		metrics *Blockchain
+		log     *slog.Logger
+	}
+)
+
+func NewBlockchain(reg *Registry) *Blockchain {
+	m := &Blockchain{
+		finished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "transactions_total",
+			Help:      "Transactions that reached a final status, by kind (mint, transfer) and status (confirmed, failed).",
+		}, []string{"kind", "status"}),
+		gasUsed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "gas_used_total",
+			Help:      "Gas used by confirmed transactions, by kind.",
+		}, []string{"kind"}),
+		fees: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "fees_gwei_total",
+			Help:      "Fees paid for confirmed transactions (gas used times gas price), in gwei, by kind.",
+		}, []string{"kind"}),
+		confirmation: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "blockchain",
+			Name:      "confirmation_seconds",
+			Help:      "Time from recording a transaction until it was confirmed, by kind.",
+			Buckets:   []float64{1, 2.5, 5, 10, 15, 30, 60, 120, 300, 600, 1800},
+		}, []string{"kind"}),
+	}
+	reg.MustRegister(m.finished, m.gasUsed, m.fees, m.confirmation)
+	return m
+}
+
+// InstrumentTransactions decorates the transaction repository so confirmed
+// and failed transactions are recorded.
+func InstrumentTransactions(repo repository.TransactionRepository, m *Blockchain, log *slog.Logger) repository.TransactionRepository {
+	return &instrumentedTransactions{TransactionRepository: repo, metrics: m, log: log}
+}
+
+func (r *instrumentedTransactions) MarkConfirmed(ctx context.Context, id uuid.UUID, blockNumber int64, gasUsed int64) error {
+	if err := r.TransactionRepository.MarkConfirmed(ctx, id, blockNumber, gasUsed); err != nil {
+		return err
+	}
+
+	tx, err := r.GetByID(ctx, id)
+	if err != nil {
+		r.log.ErrorContext(ctx, "failed to load confirmed transaction for metrics", "transaction_id", id, "error", err)
+		return nil
+	}
+	r.metrics.confirmed(tx)
+	return nil
+}
+
+func (r *instrumentedTransactions) MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string) error {
+	if err := r.TransactionRepository.MarkFailed(ctx, id, errorCode, errorMessage); err != nil {
+		return err
+	}
+
+	tx, err := r.GetByID(ctx, id)
+	if err != nil {
+		r.log.ErrorContext(ctx, "failed to load failed transaction for metrics", "transaction_id", id, "error", err)
+		return nil
+	}
+	r.metrics.finished.WithLabelValues(kindOf(tx), string(domain.TransactionStatusFailed)).Inc()
+	return nil
+}
+
+func (m *Blockchain) confirmed(tx *domain.BlockchainTransaction) {
+	kind := kindOf(tx)
+	m.finished.WithLabelValues(kind, string(domain.TransactionStatusConfirmed)).Inc()
+	m.gasUsed.WithLabelValues(kind).Add(float64(tx.GasUsed))
+
+	if price, ok := new(big.Int).SetString(tx.GasPrice, 10); ok {
+		fee := new(big.Float).SetInt(price.Mul(price, big.NewInt(tx.GasUsed)))
+		gwei, _ := fee.Quo(fee, weiPerGwei).Float64()
+		m.fees.WithLabelValues(kind).Add(gwei)
+	}
+
+	if tx.ConfirmedAt != nil {
+		m.confirmation.WithLabelValues(kind).Observe(tx.ConfirmedAt.Sub(tx.CreatedAt).Seconds())
+	}
+}
+
+func kindOf(tx *domain.BlockchainTransaction) string {
+	if tx.FromAddress == "" {
+		return KindMint
+	}
+	return KindTransfer
+}
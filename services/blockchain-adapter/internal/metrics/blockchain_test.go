@@ -0,0 +1,78 @@
+package metrics_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/metrics"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestInstrumentedTransactions(t *testing.T) {
+	ctx := context.Background()
+	reg := metrics.NewRegistry()
+	repo := metrics.InstrumentTransactions(
+		repository.NewMemoryTransactionRepository(),
+		metrics.NewBlockchain(reg),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	mint := createTransaction(t, repo, "")
+	transfer := createTransaction(t, repo, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	if err := repo.MarkConfirmed(ctx, mint.ID, 12, 21_000); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MarkFailed(ctx, transfer.ID, "REVERTED", "execution reverted"); err != nil {
+		t.Fatal(err)
+	}
+
+	body := scrape(t, reg)
+	for _, want := range []string{
+		`cashback_blockchain_transactions_total{kind="mint",service="blockchain-adapter",status="confirmed"} 1`,
+		`cashback_blockchain_transactions_total{kind="transfer",service="blockchain-adapter",status="failed"} 1`,
+		`cashback_blockchain_gas_used_total{kind="mint",service="blockchain-adapter"} 21000`,
+		// 21000 gas at 2 gwei.
+		`cashback_blockchain_fees_gwei_total{kind="mint",service="blockchain-adapter"} 42000`,
+		`cashback_blockchain_confirmation_seconds_count{kind="mint",service="blockchain-adapter"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if strings.Contains(body, `kind="transfer",service="blockchain-adapter",status="confirmed"`) {
+		t.Error("the failed transfer was counted as confirmed")
+	}
+}
+
+func createTransaction(t *testing.T, repo repository.TransactionRepository, from string) *domain.BlockchainTransaction {
+	t.Helper()
+	tx := &domain.BlockchainTransaction{
+		IdempotencyKey: uuid.New(),
+		FromAddress:    from,
+		WalletAddress:  "0x0000000000000000000000000000000000000001",
+		TokenAmount:    "1000",
+		GasPrice:       "2000000000",
+	}
+	if err := repo.Create(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// scrape returns the registry's metrics in the text exposition format.
+func scrape(t *testing.T, reg *metrics.Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape returned %d", rec.Code)
+	}
+	return rec.Body.String()
+}
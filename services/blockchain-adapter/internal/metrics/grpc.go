@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer records the calls served over gRPC.
+type GRPCServer struct {
+	handled  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewGRPCServer(reg *Registry) *GRPCServer {
+	m := &GRPCServer{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_server",
+			Name:      "handled_total",
+			Help:      "gRPC calls completed, by service, method and status code.",
+		}, []string{"grpc_service", "grpc_method", "grpc_code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_server",
+			Name:      "handling_seconds",
+			Help:      "Time to handle gRPC calls, by service and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"grpc_service", "grpc_method"}),
+	}
+	reg.MustRegister(m.handled, m.duration)
+	return m
+}
+
+func (m *GRPCServer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		service, name := splitMethod(info.FullMethod)
+		m.handled.WithLabelValues(service, name, status.Code(err).String()).Inc()
+		m.duration.WithLabelValues(service, name).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// splitMethod splits "/package.Service/Method" into service and method.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", fullMethod
+	}
+	return service, method
+}
@@ -0,0 +1,76 @@
+// Package metrics exposes the service's Prometheus metrics on Path.
+//
+// Every service of the platform names its metrics the same way:
+// cashback_<layer>_<name>, with the emitting service in the service label, so
+// dashboards and alerts work across services. Keep new metrics to that scheme.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/fx"
+)
+
+const (
+	Path        = "/metrics"
+	namespace   = "cashback"
+	serviceName = "blockchain-adapter"
+)
+
+// Registry collects the metrics served on Path.
+type Registry struct {
+	prometheus.Registerer
+	gatherer prometheus.Gatherer
+}
+
+func NewRegistry() *Registry {
+	reg := prometheus.NewRegistry()
+	r := &Registry{
+		Registerer: prometheus.WrapRegistererWith(prometheus.Labels{"service": serviceName}, reg),
+		gatherer:   reg,
+	}
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return r
+}
+
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.gatherer, promhttp.HandlerOpts{})
+}
+
+// StartServer serves Path on METRICS_PORT, next to the gRPC port.
+func StartServer(lc fx.Lifecycle, reg *Registry, cfg *config.Config, log *slog.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle(Path, reg.Handler())
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.Metrics.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				log.Info("metrics server starting", "port", cfg.Metrics.Port)
+				if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Error("metrics server failed", "error", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("shutting down metrics server")
+			return server.Shutdown(ctx)
+		},
+	})
+}
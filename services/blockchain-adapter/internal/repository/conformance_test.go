@@ -0,0 +1,291 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/database/databasetest"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	"github.com/google/uuid"
+)
+
+// The same suite runs against the in-memory repositories and the Postgres
+// ones, so the two cannot drift apart. The Postgres runs need
+// TEST_DATABASE_URL and are skipped without it.
+
+func TestMemoryTransactionRepository(t *testing.T) {
+	testTransactionRepository(t, func(t *testing.T) repository.TransactionRepository {
+		return repository.NewMemoryTransactionRepository()
+	})
+}
+
+func TestPostgresTransactionRepository(t *testing.T) {
+	testTransactionRepository(t, func(t *testing.T) repository.TransactionRepository {
+		return repository.NewTransactionRepository(databasetest.New(t))
+	})
+}
+
+func TestMemoryCustodialWalletRepository(t *testing.T) {
+	testCustodialWalletRepository(t, func(t *testing.T) repository.CustodialWalletRepository {
+		return repository.NewMemoryCustodialWalletRepository()
+	})
+}
+
+func TestPostgresCustodialWalletRepository(t *testing.T) {
+	testCustodialWalletRepository(t, func(t *testing.T) repository.CustodialWalletRepository {
+		return repository.NewCustodialWalletRepository(databasetest.New(t))
+	})
+}
+
+const wallet = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+func testTransactionRepository(t *testing.T, newRepo func(t *testing.T) repository.TransactionRepository) {
+	ctx := context.Background()
+
+	t.Run("create fills in defaults", func(t *testing.T) {
+		repo := newRepo(t)
+
+		tx := newTransaction()
+		if err := repo.Create(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		if tx.ID == uuid.Nil || tx.Status != domain.TransactionStatusPending || tx.CreatedAt.IsZero() {
+			t.Fatalf("created transaction has ID %s, status %q, created at %s", tx.ID, tx.Status, tx.CreatedAt)
+		}
+
+		got := mustGetTransaction(t, repo, tx.ID)
+		if got.IdempotencyKey != tx.IdempotencyKey || got.WalletAddress != tx.WalletAddress ||
+			got.TokenAmount != tx.TokenAmount || got.Status != domain.TransactionStatusPending {
+			t.Fatalf("stored %+v, want %+v", got, tx)
+		}
+	})
+
+	t.Run("missing transactions are not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetByID(ctx, uuid.New())
+		assertError(t, err, domain.ErrTransactionNotFound)
+		_, err = repo.GetByIdempotencyKey(ctx, uuid.New())
+		assertError(t, err, domain.ErrTransactionNotFound)
+		_, err = repo.GetByTransactionHash(ctx, "0xmissing")
+		assertError(t, err, domain.ErrTransactionNotFound)
+	})
+
+	t.Run("idempotency keys are unique", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := newTransaction()
+		if err := repo.Create(ctx, first); err != nil {
+			t.Fatal(err)
+		}
+		second := newTransaction()
+		second.IdempotencyKey = first.IdempotencyKey
+		assertError(t, repo.Create(ctx, second), domain.ErrDuplicateTransaction)
+
+		got, err := repo.GetByIdempotencyKey(ctx, first.IdempotencyKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != first.ID {
+			t.Fatalf("idempotency key maps to %s, want %s", got.ID, first.ID)
+		}
+	})
+
+	t.Run("IDs are unique", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := newTransaction()
+		if err := repo.Create(ctx, first); err != nil {
+			t.Fatal(err)
+		}
+		second := newTransaction()
+		second.ID = first.ID
+		assertError(t, repo.Create(ctx, second), domain.ErrDuplicateTransaction)
+	})
+
+	t.Run("update saves every field", func(t *testing.T) {
+		repo := newRepo(t)
+
+		tx := newTransaction()
+		if err := repo.Create(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		tx.TransactionHash = "0x" + uuid.NewString()
+		tx.Status = domain.TransactionStatusSubmitted
+		tx.Nonce = 7
+		if err := repo.Update(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repo.GetByTransactionHash(ctx, tx.TransactionHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != tx.ID || got.Status != domain.TransactionStatusSubmitted || got.Nonce != 7 {
+			t.Fatalf("updated transaction is %+v", got)
+		}
+	})
+
+	t.Run("update refuses a taken idempotency key", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first, second := newTransaction(), newTransaction()
+		for _, tx := range []*domain.BlockchainTransaction{first, second} {
+			if err := repo.Create(ctx, tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		second.IdempotencyKey = first.IdempotencyKey
+		assertError(t, repo.Update(ctx, second), domain.ErrDuplicateTransaction)
+	})
+
+	t.Run("status changes", func(t *testing.T) {
+		repo := newRepo(t)
+
+		confirmed, failed := newTransaction(), newTransaction()
+		for _, tx := range []*domain.BlockchainTransaction{confirmed, failed} {
+			if err := repo.Create(ctx, tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := repo.UpdateStatus(ctx, confirmed.ID, domain.TransactionStatusSubmitted); err != nil {
+			t.Fatal(err)
+		}
+		if got := mustGetTransaction(t, repo, confirmed.ID); got.Status != domain.TransactionStatusSubmitted {
+			t.Fatalf("status = %q, want submitted", got.Status)
+		}
+
+		if err := repo.MarkConfirmed(ctx, confirmed.ID, 42, 21000); err != nil {
+			t.Fatal(err)
+		}
+		got := mustGetTransaction(t, repo, confirmed.ID)
+		if got.Status != domain.TransactionStatusConfirmed || got.BlockNumber != 42 || got.GasUsed != 21000 || got.ConfirmedAt == nil {
+			t.Fatalf("confirmed transaction is %+v", got)
+		}
+
+		if err := repo.MarkFailed(ctx, failed.ID, "REVERTED", "execution reverted"); err != nil {
+			t.Fatal(err)
+		}
+		got = mustGetTransaction(t, repo, failed.ID)
+		if got.Status != domain.TransactionStatusFailed || got.ErrorCode != "REVERTED" || got.ErrorMessage != "execution reverted" {
+			t.Fatalf("failed transaction is %+v", got)
+		}
+	})
+
+	t.Run("status changes to missing transactions do nothing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		id := uuid.New()
+		if err := repo.UpdateStatus(ctx, id, domain.TransactionStatusSubmitted); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.MarkConfirmed(ctx, id, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.MarkFailed(ctx, id, "REVERTED", ""); err != nil {
+			t.Fatal(err)
+		}
+		_, err := repo.GetByID(ctx, id)
+		assertError(t, err, domain.ErrTransactionNotFound)
+	})
+
+	t.Run("reads return copies", func(t *testing.T) {
+		repo := newRepo(t)
+
+		tx := newTransaction()
+		if err := repo.Create(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		tx.Status = domain.TransactionStatusFailed
+
+		got := mustGetTransaction(t, repo, tx.ID)
+		got.Status = domain.TransactionStatusConfirmed
+		if got := mustGetTransaction(t, repo, tx.ID); got.Status != domain.TransactionStatusPending {
+			t.Fatalf("status = %q after changing copies, want pending", got.Status)
+		}
+	})
+}
+
+func testCustodialWalletRepository(t *testing.T, newRepo func(t *testing.T) repository.CustodialWalletRepository) {
+	ctx := context.Background()
+	derive := func(index uint32) (string, error) {
+		return fmt.Sprintf("0x%040x", index+1), nil
+	}
+
+	t.Run("indexes are allocated in order", func(t *testing.T) {
+		repo := newRepo(t)
+		for want, owner := range []string{"alice", "bob", "carol"} {
+			w, err := repo.Create(ctx, owner, derive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w.DerivationIndex != int64(want) || w.OwnerID != owner {
+				t.Fatalf("wallet %+v, want index %d for %s", w, want, owner)
+			}
+		}
+	})
+
+	t.Run("owners keep their wallet", func(t *testing.T) {
+		repo := newRepo(t)
+		created, err := repo.Create(ctx, "alice", derive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := repo.Create(ctx, "alice", func(uint32) (string, error) {
+			t.Fatal("derived a second address for the same owner")
+			return "", nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := repo.GetByOwnerID(ctx, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.Address != created.Address || got.Address != created.Address {
+			t.Fatalf("addresses %s and %s, want %s", again.Address, got.Address, created.Address)
+		}
+	})
+
+	t.Run("missing wallets are not found", func(t *testing.T) {
+		_, err := newRepo(t).GetByOwnerID(ctx, "nobody")
+		assertError(t, err, domain.ErrCustodialWalletNotFound)
+	})
+
+	t.Run("failed derivations store nothing", func(t *testing.T) {
+		repo := newRepo(t)
+		failure := errors.New("derivation failed")
+		_, err := repo.Create(ctx, "alice", func(uint32) (string, error) { return "", failure })
+		assertError(t, err, failure)
+		_, err = repo.GetByOwnerID(ctx, "alice")
+		assertError(t, err, domain.ErrCustodialWalletNotFound)
+	})
+}
+
+func newTransaction() *domain.BlockchainTransaction {
+	return &domain.BlockchainTransaction{
+		IdempotencyKey: uuid.New(),
+		WalletAddress:  wallet,
+		TokenAmount:    "1000000000000000000",
+	}
+}
+
+func mustGetTransaction(t *testing.T, repo repository.TransactionRepository, id uuid.UUID) *domain.BlockchainTransaction {
+	t.Helper()
+	tx, err := repo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func assertError(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+}
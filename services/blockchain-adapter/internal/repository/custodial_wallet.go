@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// DeriveAddressFunc returns the address at a derivation index.
+	DeriveAddressFunc func(index uint32) (string, error)
+
+	CustodialWalletRepository interface {
+		GetByOwnerID(ctx context.Context, ownerID string) (*domain.CustodialWallet, error)
+		Create(ctx context.Context, ownerID string, derive DeriveAddressFunc) (*domain.CustodialWallet, error)
+	}
+
+	custodialWalletRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewCustodialWalletRepository(db *gorm.DB) CustodialWalletRepository {
+	return &custodialWalletRepository{db: db}
+}
+
+func (r *custodialWalletRepository) GetByOwnerID(ctx context.Context, ownerID string) (*domain.CustodialWallet, error) {
+	var wallet domain.CustodialWallet
+	if err := r.db.WithContext(ctx).Where("owner_id = ?", ownerID).First(&wallet).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCustodialWalletNotFound
+		}
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// Create allocates the next derivation index to ownerID and stores the
+// address derived at it. The table lock keeps indexes gap-free, so every
+// custodial wallet stays within the gap limit of standard wallet recovery.
+// If ownerID already has a wallet, that wallet is returned instead.
+func (r *custodialWalletRepository) Create(ctx context.Context, ownerID string, derive DeriveAddressFunc) (*domain.CustodialWallet, error) {
+	var wallet domain.CustodialWallet
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE custodial_wallets IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("owner_id = ?", ownerID).Limit(1).Find(&wallet)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+
+		var next int64
+		if err := tx.Model(&domain.CustodialWallet{}).
+			Select("COALESCE(MAX(derivation_index) + 1, 0)").
+			Scan(&next).Error; err != nil {
+			return err
+		}
+
+		address, err := derive(uint32(next))
+		if err != nil {
+			return err
+		}
+
+		wallet = domain.CustodialWallet{
+			ID:              uuid.New(),
+			OwnerID:         ownerID,
+			DerivationIndex: next,
+			Address:         address,
+		}
+		return tx.Create(&wallet).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
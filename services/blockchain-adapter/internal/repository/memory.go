@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	memoryTransactionRepository struct {
+		mu           sync.RWMutex
+		transactions map[uuid.UUID]*domain.BlockchainTransaction
+	}
+
+	memoryCustodialWalletRepository struct {
+		mu      sync.Mutex
+		wallets []domain.CustodialWallet
+	}
+)
+
+// NewMemoryTransactionRepository returns a TransactionRepository that keeps
+// transactions in memory, for tests that do not need a database. Like the
+// table, it fills in the ID, status and timestamps left empty and refuses a
+// second transaction with the same ID or idempotency key.
+func NewMemoryTransactionRepository() TransactionRepository {
+	return &memoryTransactionRepository{transactions: make(map[uuid.UUID]*domain.BlockchainTransaction)}
+}
+
+func (r *memoryTransactionRepository) Create(_ context.Context, tx *domain.BlockchainTransaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.transactions[tx.ID]; ok {
+		return domain.ErrDuplicateTransaction
+	}
+	return r.insert(tx)
+}
+
+func (r *memoryTransactionRepository) GetByID(_ context.Context, id uuid.UUID) (*domain.BlockchainTransaction, error) {
+	return r.first(func(tx *domain.BlockchainTransaction) bool { return tx.ID == id })
+}
+
+func (r *memoryTransactionRepository) GetByIdempotencyKey(_ context.Context, key uuid.UUID) (*domain.BlockchainTransaction, error) {
+	return r.first(func(tx *domain.BlockchainTransaction) bool { return tx.IdempotencyKey == key })
+}
+
+func (r *memoryTransactionRepository) GetByTransactionHash(_ context.Context, hash string) (*domain.BlockchainTransaction, error) {
+	return r.first(func(tx *domain.BlockchainTransaction) bool { return tx.TransactionHash == hash })
+}
+
+// Update saves tx whole, inserting it when there is no transaction with its
+// ID, as GORM's Save does.
+func (r *memoryTransactionRepository) Update(_ context.Context, tx *domain.BlockchainTransaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.transactions[tx.ID]; !ok {
+		return r.insert(tx)
+	}
+	if r.keyTaken(tx) {
+		return domain.ErrDuplicateTransaction
+	}
+	tx.UpdatedAt = time.Now()
+	r.transactions[tx.ID] = cloneTransaction(tx)
+	return nil
+}
+
+func (r *memoryTransactionRepository) UpdateStatus(_ context.Context, id uuid.UUID, status domain.TransactionStatus) error {
+	r.update(id, func(tx *domain.BlockchainTransaction) {
+		tx.Status = status
+	})
+	return nil
+}
+
+func (r *memoryTransactionRepository) MarkConfirmed(_ context.Context, id uuid.UUID, blockNumber int64, gasUsed int64) error {
+	now := time.Now().UTC()
+	r.update(id, func(tx *domain.BlockchainTransaction) {
+		tx.Status = domain.TransactionStatusConfirmed
+		tx.BlockNumber = blockNumber
+		tx.GasUsed = gasUsed
+		tx.ConfirmedAt = &now
+	})
+	return nil
+}
+
+func (r *memoryTransactionRepository) MarkFailed(_ context.Context, id uuid.UUID, errorCode, errorMessage string) error {
+	r.update(id, func(tx *domain.BlockchainTransaction) {
+		tx.Status = domain.TransactionStatusFailed
+		tx.ErrorCode = errorCode
+		tx.ErrorMessage = errorMessage
+	})
+	return nil
+}
+
+// insert stores a copy of tx, filling in what the column defaults would.
+// The caller holds the lock and has checked the ID is free.
+func (r *memoryTransactionRepository) insert(tx *domain.BlockchainTransaction) error {
+	if r.keyTaken(tx) {
+		return domain.ErrDuplicateTransaction
+	}
+
+	if tx.ID == uuid.Nil {
+		tx.ID = uuid.New()
+	}
+	if tx.Status == "" {
+		tx.Status = domain.TransactionStatusPending
+	}
+	now := time.Now()
+	if tx.CreatedAt.IsZero() {
+		tx.CreatedAt = now
+	}
+	if tx.UpdatedAt.IsZero() {
+		tx.UpdatedAt = now
+	}
+	r.transactions[tx.ID] = cloneTransaction(tx)
+	return nil
+}
+
+// keyTaken reports whether a transaction other than tx has its idempotency key.
+func (r *memoryTransactionRepository) keyTaken(tx *domain.BlockchainTransaction) bool {
+	for _, other := range r.transactions {
+		if other.ID != tx.ID && other.IdempotencyKey == tx.IdempotencyKey {
+			return true
+		}
+	}
+	return false
+}
+
+// first returns a copy of the matching transaction with the lowest ID, the
+// one Postgres returns for First.
+func (r *memoryTransactionRepository) first(match func(*domain.BlockchainTransaction) bool) (*domain.BlockchainTransaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *domain.BlockchainTransaction
+	for _, tx := range r.transactions {
+		if match(tx) && (found == nil || bytes.Compare(tx.ID[:], found.ID[:]) < 0) {
+			found = tx
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrTransactionNotFound
+	}
+	return cloneTransaction(found), nil
+}
+
+// update applies fn to the transaction with id, if there is one.
+func (r *memoryTransactionRepository) update(id uuid.UUID, fn func(*domain.BlockchainTransaction)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tx, ok := r.transactions[id]; ok {
+		fn(tx)
+		tx.UpdatedAt = time.Now()
+	}
+}
+
+func cloneTransaction(tx *domain.BlockchainTransaction) *domain.BlockchainTransaction {
+	clone := *tx
+	clone.TraceContext = bytes.Clone(tx.TraceContext)
+	if tx.ConfirmedAt != nil {
+		confirmedAt := *tx.ConfirmedAt
+		clone.ConfirmedAt = &confirmedAt
+	}
+	return &clone
+}
+
+// NewMemoryCustodialWalletRepository returns a CustodialWalletRepository that
+// keeps wallets in memory, for tests that do not need a database. Like the
+// table, it allocates derivation indexes without gaps.
+func NewMemoryCustodialWalletRepository() CustodialWalletRepository {
+	return &memoryCustodialWalletRepository{}
+}
+
+func (r *memoryCustodialWalletRepository) GetByOwnerID(_ context.Context, ownerID string) (*domain.CustodialWallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, wallet := range r.wallets {
+		if wallet.OwnerID == ownerID {
+			return &wallet, nil
+		}
+	}
+	return nil, domain.ErrCustodialWalletNotFound
+}
+
+func (r *memoryCustodialWalletRepository) Create(_ context.Context, ownerID string, derive DeriveAddressFunc) (*domain.CustodialWallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, wallet := range r.wallets {
+		if wallet.OwnerID == ownerID {
+			return &wallet, nil
+		}
+	}
+
+	next := int64(len(r.wallets))
+	address, err := derive(uint32(next))
+	if err != nil {
+		return nil, err
+	}
+
+	wallet := domain.CustodialWallet{
+		ID:              uuid.New(),
+		OwnerID:         ownerID,
+		DerivationIndex: next,
+		Address:         address,
+		CreatedAt:       time.Now(),
+	}
+	r.wallets = append(r.wallets, wallet)
+	return &wallet, nil
+}
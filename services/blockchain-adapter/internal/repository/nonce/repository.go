@@ -1,11 +0,0 @@
-package nonce
-
-import "gorm.io/gorm"
-
-type Repository struct {
-	db *gorm.DB
-}
-
-func NewRepository(db *gorm.DB) *Repository {
-	return &Repository{db: db}
-}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
@@ -31,13 +32,13 @@ func NewTransactionRepository(db *gorm.DB) TransactionRepository {
 }
 
 func (r *transactionRepository) Create(ctx context.Context, tx *domain.BlockchainTransaction) error {
-	return r.db.WithContext(ctx).Create(tx).Error
+	return transactionError(r.db.WithContext(ctx).Create(tx).Error)
 }
 
 func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BlockchainTransaction, error) {
 	var tx domain.BlockchainTransaction
 	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&tx).Error; err != nil {
-		return nil, err
+		return nil, transactionError(err)
 	}
 	return &tx, nil
 }
@@ -45,7 +46,7 @@ func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 func (r *transactionRepository) GetByIdempotencyKey(ctx context.Context, key uuid.UUID) (*domain.BlockchainTransaction, error) {
 	var tx domain.BlockchainTransaction
 	if err := r.db.WithContext(ctx).Where("idempotency_key = ?", key).First(&tx).Error; err != nil {
-		return nil, err
+		return nil, transactionError(err)
 	}
 	return &tx, nil
 }
@@ -53,13 +54,13 @@ func (r *transactionRepository) GetByIdempotencyKey(ctx context.Context, key uui
 func (r *transactionRepository) GetByTransactionHash(ctx context.Context, hash string) (*domain.BlockchainTransaction, error) {
 	var tx domain.BlockchainTransaction
 	if err := r.db.WithContext(ctx).Where("transaction_hash = ?", hash).First(&tx).Error; err != nil {
-		return nil, err
+		return nil, transactionError(err)
 	}
 	return &tx, nil
 }
 
 func (r *transactionRepository) Update(ctx context.Context, tx *domain.BlockchainTransaction) error {
-	return r.db.WithContext(ctx).Save(tx).Error
+	return transactionError(r.db.WithContext(ctx).Save(tx).Error)
 }
 
 func (r *transactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.TransactionStatus) error {
@@ -83,3 +84,16 @@ func (r *transactionRepository) MarkFailed(ctx context.Context, id uuid.UUID, er
 		"error_message": errorMessage,
 	}).Error
 }
+
+// transactionError maps the errors GORM reports for a missing or conflicting
+// row to their domain errors.
+func transactionError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.ErrTransactionNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return domain.ErrDuplicateTransaction
+	default:
+		return err
+	}
+}
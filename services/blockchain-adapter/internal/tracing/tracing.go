@@ -0,0 +1,28 @@
+// Package tracing sets up OpenTelemetry tracing for the service. gRPC calls
+// continue the trace of the caller, read from the request metadata.
+package tracing
+
+import (
+	"context"
+
+	"github.com/cashback-platform/pkg/tracing"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"go.uber.org/fx"
+)
+
+const serviceName = "blockchain-adapter"
+
+// Setup installs the shared tracer setup for TRACING_EXPORTER and flushes the
+// spans still batched when the service stops.
+func Setup(lc fx.Lifecycle, cfg *config.Config) error {
+	shutdown, err := tracing.Setup(serviceName, tracing.Config(cfg.Tracing))
+	if err != nil {
+		return err
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return shutdown(ctx)
+		},
+	})
+	return nil
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/cashback-platform/services/blockchain-adapter/internal/tracing")
+
+// tracedTransactions stores the trace context with every transaction and,
+// once the transaction is confirmed or failed, adds a span covering its time
+// on chain to that trace. Confirmation happens long after the call that sent
+// the transaction returned, so this is what ties it to the cashback.
+type tracedTransactions struct {
+	repository.TransactionRepository
+	log *slog.Logger
+}
+
+// TraceTransactions decorates the transaction repository with tracing.
+func TraceTransactions(repo repository.TransactionRepository, log *slog.Logger) repository.TransactionRepository {
+	return &tracedTransactions{TransactionRepository: repo, log: log}
+}
+
+func (r *tracedTransactions) Create(ctx context.Context, tx *domain.BlockchainTransaction) error {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) > 0 && tx.TraceContext == nil {
+		traceContext, err := json.Marshal(carrier)
+		if err != nil {
+			return err
+		}
+		tx.TraceContext = traceContext
+	}
+	return r.TransactionRepository.Create(ctx, tx)
+}
+
+func (r *tracedTransactions) MarkConfirmed(ctx context.Context, id uuid.UUID, blockNumber int64, gasUsed int64) error {
+	if err := r.TransactionRepository.MarkConfirmed(ctx, id, blockNumber, gasUsed); err != nil {
+		return err
+	}
+	r.recordOnChain(ctx, id, nil)
+	return nil
+}
+
+func (r *tracedTransactions) MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string) error {
+	if err := r.TransactionRepository.MarkFailed(ctx, id, errorCode, errorMessage); err != nil {
+		return err
+	}
+	r.recordOnChain(ctx, id, &onChainError{code: errorCode, message: errorMessage})
+	return nil
+}
+
+type onChainError struct {
+	code    string
+	message string
+}
+
+func (r *tracedTransactions) recordOnChain(ctx context.Context, id uuid.UUID, failure *onChainError) {
+	tx, err := r.GetByID(ctx, id)
+	if err != nil {
+		r.log.ErrorContext(ctx, "failed to load transaction for tracing", "transaction_id", id, "error", err)
+		return
+	}
+
+	var carrier propagation.MapCarrier
+	if len(tx.TraceContext) > 0 {
+		if err := json.Unmarshal(tx.TraceContext, &carrier); err != nil {
+			r.log.ErrorContext(ctx, "failed to read trace context of transaction", "transaction_id", id, "error", err)
+		}
+	}
+	// A span of its own, in the stored trace rather than the caller's.
+	parent := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+	_, span := tracer.Start(parent, "transaction on chain",
+		trace.WithTimestamp(tx.CreatedAt),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithAttributes(
+			attribute.String("blockchain.transaction.id", tx.ID.String()),
+			attribute.String("blockchain.transaction.hash", tx.TransactionHash),
+			attribute.Int64("blockchain.block_number", tx.BlockNumber),
+			attribute.Int64("blockchain.gas_used", tx.GasUsed),
+		),
+	)
+
+	end := time.Now()
+	if failure != nil {
+		span.SetAttributes(attribute.String("blockchain.error_code", failure.code))
+		span.SetStatus(codes.Error, failure.message)
+	} else if tx.ConfirmedAt != nil {
+		end = *tx.ConfirmedAt
+	}
+	span.End(trace.WithTimestamp(end))
+}
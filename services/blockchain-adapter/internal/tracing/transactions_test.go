@@ -0,0 +1,120 @@
+package tracing_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var provider = sdktrace.NewTracerProvider()
+
+// The tracer of the decorator is taken from the global provider when the
+// package loads, so the provider is installed once for all tests.
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	os.Exit(m.Run())
+}
+
+// record returns a recorder of the spans ended during the test.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	rec := tracetest.NewSpanRecorder()
+	provider.RegisterSpanProcessor(rec)
+	t.Cleanup(func() { provider.UnregisterSpanProcessor(rec) })
+	return rec
+}
+
+func TestTracedTransactions(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		finish func(ctx context.Context, repo repository.TransactionRepository, tx *domain.BlockchainTransaction) error
+		status codes.Code
+	}{
+		{"confirmed", func(ctx context.Context, repo repository.TransactionRepository, tx *domain.BlockchainTransaction) error {
+			return repo.MarkConfirmed(ctx, tx.ID, 12, 21_000)
+		}, codes.Unset},
+		{"failed", func(ctx context.Context, repo repository.TransactionRepository, tx *domain.BlockchainTransaction) error {
+			return repo.MarkFailed(ctx, tx.ID, "REVERTED", "execution reverted")
+		}, codes.Error},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := record(t)
+			repo := tracing.TraceTransactions(
+				repository.NewMemoryTransactionRepository(),
+				slog.New(slog.NewTextHandler(io.Discard, nil)),
+			)
+
+			// The call that sends the transaction.
+			sendCtx, send := otel.Tracer("test").Start(context.Background(), "MintTokens")
+			tx := &domain.BlockchainTransaction{
+				IdempotencyKey: uuid.New(),
+				WalletAddress:  "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+				TokenAmount:    "1000",
+			}
+			if err := repo.Create(sendCtx, tx); err != nil {
+				t.Fatal(err)
+			}
+			send.End()
+			if len(tx.TraceContext) == 0 {
+				t.Fatal("the trace context was not stored with the transaction")
+			}
+
+			// The receipt poller, in a trace of its own.
+			pollCtx, poll := otel.Tracer("test").Start(context.Background(), "poll receipts")
+			if err := tc.finish(pollCtx, repo, tx); err != nil {
+				t.Fatal(err)
+			}
+			poll.End()
+
+			onChain := findSpan(t, rec, "transaction on chain")
+			if onChain.SpanContext().TraceID() != send.SpanContext().TraceID() {
+				t.Fatal("the span is not in the trace of the call that sent the transaction")
+			}
+			if onChain.Parent().SpanID() != send.SpanContext().SpanID() {
+				t.Fatal("the span is not a child of the call that sent the transaction")
+			}
+			if links := onChain.Links(); len(links) != 1 || links[0].SpanContext.SpanID() != poll.SpanContext().SpanID() {
+				t.Fatalf("links = %+v, want the poller's span", links)
+			}
+			if onChain.Status().Code != tc.status {
+				t.Fatalf("status = %v, want %v", onChain.Status().Code, tc.status)
+			}
+			if !hasAttribute(onChain, attribute.String("blockchain.transaction.id", tx.ID.String())) {
+				t.Fatalf("attributes = %v", onChain.Attributes())
+			}
+		})
+	}
+}
+
+func findSpan(t *testing.T, rec *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range rec.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("no %q span was recorded", name)
+	return nil
+}
+
+func hasAttribute(span sdktrace.ReadOnlySpan, want attribute.KeyValue) bool {
+	for _, attr := range span.Attributes() {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
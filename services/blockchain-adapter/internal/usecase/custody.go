@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/hdwallet"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/chain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Error codes returned in TransferResult for requests that cannot succeed.
+const (
+	ErrorCodeInvalidArgument         = "INVALID_ARGUMENT"
+	ErrorCodeCustodialWalletNotFound = "CUSTODIAL_WALLET_NOT_FOUND"
+	ErrorCodeTransfersDisabled       = "TRANSFERS_DISABLED"
+)
+
+var (
+	ErrCustodyDisabled   = errors.New("custodial wallets are disabled: CUSTODY_HD_SEED is not set")
+	ErrOwnerRequired     = errors.New("owner id is required")
+	ErrSeedMismatch      = errors.New("custody seed does not derive the stored custodial wallet")
+	ErrTransfersDisabled = errors.New("custodial transfers are disabled: CHAIN_TOKEN_ADDRESS is not set")
+)
+
+type (
+	// CustodyUsecase manages the custodial wallets derived from the platform HD
+	// seed and transfers the token out of them.
+	CustodyUsecase struct {
+		keys         *hdwallet.Wallet
+		node         *chain.Node
+		sender       *chain.Sender
+		wallets      repository.CustodialWalletRepository
+		transactions repository.TransactionRepository
+		token        string
+		log          *slog.Logger
+	}
+
+	CustodialAddressResult struct {
+		OwnerID        string
+		WalletAddress  string
+		DerivationPath string
+	}
+
+	TransferResult struct {
+		Success         bool
+		TransactionHash string
+		FromAddress     string
+		Status          string
+		ErrorCode       string
+		ErrorMessage    string
+		Retryable       bool
+	}
+)
+
+func NewCustodyUsecase(
+	cfg *config.Config,
+	node *chain.Node,
+	sender *chain.Sender,
+	wallets repository.CustodialWalletRepository,
+	transactions repository.TransactionRepository,
+	log *slog.Logger,
+) (*CustodyUsecase, error) {
+	u := &CustodyUsecase{
+		node:         node,
+		sender:       sender,
+		wallets:      wallets,
+		transactions: transactions,
+		log:          log,
+	}
+
+	if cfg.Custody.Seed == "" {
+		return u, nil
+	}
+
+	keys, err := hdwallet.NewWallet(cfg.Custody.Seed, cfg.Custody.Account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load custody seed: %w", err)
+	}
+	u.keys = keys
+
+	if cfg.Chain.TokenAddress != "" {
+		if err := hdwallet.ValidateAddress(cfg.Chain.TokenAddress); err != nil {
+			return nil, fmt.Errorf("CHAIN_TOKEN_ADDRESS: %w", err)
+		}
+		u.token = hdwallet.ChecksumAddress(cfg.Chain.TokenAddress)
+	}
+
+	return u, nil
+}
+
+// DeriveCustodialAddress returns the custodial wallet of ownerID, deriving
+// it at the next free address index on first use. Repeated calls for the
+// same owner always return the same address.
+func (u *CustodyUsecase) DeriveCustodialAddress(ctx context.Context, ownerID string) (*CustodialAddressResult, error) {
+	if u.keys == nil {
+		return nil, ErrCustodyDisabled
+	}
+	if ownerID == "" {
+		return nil, ErrOwnerRequired
+	}
+
+	wallet, err := u.wallets.GetByOwnerID(ctx, ownerID)
+	if errors.Is(err, domain.ErrCustodialWalletNotFound) {
+		wallet, err = u.wallets.Create(ctx, ownerID, u.keys.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &CustodialAddressResult{
+		OwnerID:        wallet.OwnerID,
+		WalletAddress:  wallet.Address,
+		DerivationPath: u.keys.Path(uint32(wallet.DerivationIndex)),
+	}, nil
+}
+
+// TransferToken moves tokenAmount from the custodial wallet of ownerID to
+// toAddress: it calls transfer(address,uint256) on the token in an EIP-155
+// transaction signed with the key derived for that wallet, and broadcasts it
+// without waiting for the receipt. Requests are idempotent on idempotencyKey:
+// repeating one returns the transfer already sent, and only sends a new
+// transaction when the last one never reached the node.
+func (u *CustodyUsecase) TransferToken(ctx context.Context, idempotencyKey, ownerID, toAddress, tokenAmount string) (*TransferResult, error) {
+	if u.keys == nil {
+		return nil, ErrCustodyDisabled
+	}
+
+	key, err := uuid.Parse(idempotencyKey)
+	if err != nil {
+		return invalidTransfer("invalid idempotency key"), nil
+	}
+	if err := hdwallet.ValidateAddress(toAddress); err != nil {
+		return invalidTransfer(err.Error()), nil
+	}
+	amount, ok := new(big.Int).SetString(tokenAmount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return invalidTransfer("token amount must be a positive integer"), nil
+	}
+	if u.token == "" {
+		return &TransferResult{
+			Status:       string(domain.TransactionStatusFailed),
+			ErrorCode:    ErrorCodeTransfersDisabled,
+			ErrorMessage: ErrTransfersDisabled.Error(),
+			Retryable:    true,
+		}, nil
+	}
+	toAddress = hdwallet.ChecksumAddress(toAddress)
+
+	wallet, err := u.wallets.GetByOwnerID(ctx, ownerID)
+	if errors.Is(err, domain.ErrCustodialWalletNotFound) {
+		return &TransferResult{
+			Status:       string(domain.TransactionStatusFailed),
+			ErrorCode:    ErrorCodeCustodialWalletNotFound,
+			ErrorMessage: err.Error(),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := u.transactions.GetByIdempotencyKey(ctx, key)
+	switch {
+	case errors.Is(err, domain.ErrTransactionNotFound):
+		tx = &domain.BlockchainTransaction{
+			ID:             uuid.New(),
+			IdempotencyKey: key,
+			FromAddress:    wallet.Address,
+			WalletAddress:  toAddress,
+			TokenAmount:    amount.String(),
+			Status:         domain.TransactionStatusPending,
+		}
+		if err := u.transactions.Create(ctx, tx); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case tx.FromAddress != wallet.Address || tx.WalletAddress != toAddress || tx.TokenAmount != amount.String():
+		return &TransferResult{
+			Status:       string(domain.TransactionStatusFailed),
+			ErrorCode:    ErrorCodeIdempotencyMismatch,
+			ErrorMessage: "idempotency key was used for a transfer of another amount or between other wallets",
+		}, nil
+	default:
+		sent, err := u.sent(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if sent {
+			return transferResultFrom(tx), nil
+		}
+	}
+
+	signer, err := u.keys.Key(uint32(wallet.DerivationIndex))
+	if err != nil {
+		return nil, err
+	}
+	if signer.Address() != wallet.Address {
+		return nil, ErrSeedMismatch
+	}
+
+	data, err := chain.TransferCall(toAddress, amount)
+	if err != nil {
+		return nil, err
+	}
+	_, err = u.sender.Send(ctx, signer.PrivateKey(), u.token, data, func(signed chain.SignedTransaction) error {
+		tx.TransactionHash = signed.Hash
+		tx.Nonce = int64(signed.Nonce)
+		tx.GasPrice = signed.GasPrice.String()
+		tx.Status = domain.TransactionStatusSubmitted
+		tx.ErrorCode, tx.ErrorMessage = "", ""
+		return u.transactions.Update(ctx, tx)
+	})
+	if errors.Is(err, chain.ErrNotBroadcast) {
+		u.log.WarnContext(ctx, "transfer not broadcast", "transaction_id", tx.ID, "error", err)
+		if err := u.transactions.MarkFailed(ctx, tx.ID, ErrorCodeNotBroadcast, err.Error()); err != nil {
+			return nil, err
+		}
+		tx.Status = domain.TransactionStatusFailed
+		tx.ErrorCode, tx.ErrorMessage = ErrorCodeNotBroadcast, err.Error()
+		return transferResultFrom(tx), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return transferResultFrom(tx), nil
+}
+
+// sent reports whether tx, a transfer requested before, needs no new
+// transaction: anything but a transfer that never reached the node.
+func (u *CustodyUsecase) sent(ctx context.Context, tx *domain.BlockchainTransaction) (bool, error) {
+	switch {
+	case tx.TransactionHash == "":
+		return false, nil
+	case tx.Status != domain.TransactionStatusFailed || tx.ErrorCode != ErrorCodeNotBroadcast:
+		return true, nil
+	}
+
+	// The broadcast may have reached the node even though it reported an
+	// error. Sending again would move the tokens twice.
+	known, err := u.node.TransactionKnown(ctx, tx.TransactionHash)
+	if err != nil || !known {
+		return false, err
+	}
+	tx.Status = domain.TransactionStatusSubmitted
+	tx.ErrorCode, tx.ErrorMessage = "", ""
+	return true, u.transactions.Update(ctx, tx)
+}
+
+func transferResultFrom(tx *domain.BlockchainTransaction) *TransferResult {
+	return &TransferResult{
+		Success:         tx.Status != domain.TransactionStatusFailed,
+		TransactionHash: tx.TransactionHash,
+		FromAddress:     tx.FromAddress,
+		Status:          string(tx.Status),
+		ErrorCode:       tx.ErrorCode,
+		ErrorMessage:    tx.ErrorMessage,
+		Retryable:       tx.ErrorCode == ErrorCodeNotBroadcast,
+	}
+}
+
+func invalidTransfer(message string) *TransferResult {
+	return &TransferResult{
+		Status:       string(domain.TransactionStatusFailed),
+		ErrorCode:    ErrorCodeInvalidArgument,
+		ErrorMessage: message,
+	}
+}
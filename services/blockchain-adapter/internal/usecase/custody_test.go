@@ -0,0 +1,300 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/hdwallet"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/chain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/usecase"
+	"github.com/google/uuid"
+)
+
+const (
+	// The seed of the mnemonic "abandon abandon ... about".
+	seed = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc1" +
+		"9a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+	token     = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	recipient = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	chainID   = 31337
+	gasPrice  = 1_000_000_000
+	gasLimit  = 100_000
+)
+
+// node is a JSON-RPC node that accepts every transaction it is sent, unless
+// rejecting is set.
+type node struct {
+	mu        sync.Mutex
+	raw       []string
+	known     map[string]bool
+	rejecting bool
+}
+
+func (n *node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var result any
+	switch req.Method {
+	case "eth_chainId":
+		result = hexQuantity(chainID)
+	case "eth_gasPrice":
+		result = hexQuantity(gasPrice)
+	case "eth_getTransactionCount":
+		result = hexQuantity(uint64(len(n.raw)))
+	case "eth_sendRawTransaction":
+		if n.rejecting {
+			writeRPC(w, nil, "connection reset")
+			return
+		}
+		var raw string
+		_ = json.Unmarshal(req.Params[0], &raw)
+		n.raw = append(n.raw, raw)
+		b, _ := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+		hash := "0x" + hex.EncodeToString(hdwallet.Keccak256(b))
+		n.known[hash] = true
+		result = hash
+	case "eth_getTransactionByHash":
+		var hash string
+		_ = json.Unmarshal(req.Params[0], &hash)
+		if n.known[hash] {
+			result = map[string]string{"hash": hash}
+		}
+	default:
+		writeRPC(w, nil, "method not found: "+req.Method)
+		return
+	}
+	writeRPC(w, result, "")
+}
+
+func (n *node) reject(rejecting bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.rejecting = rejecting
+}
+
+func (n *node) sent() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.raw...)
+}
+
+func writeRPC(w http.ResponseWriter, result any, message string) {
+	resp := map[string]any{"jsonrpc": "2.0", "id": 1}
+	if message != "" {
+		resp["error"] = map[string]any{"code": -32000, "message": message}
+	} else {
+		resp["result"] = result
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func hexQuantity(n uint64) string {
+	return "0x" + new(big.Int).SetUint64(n).Text(16)
+}
+
+type custodyFixture struct {
+	node         *node
+	transactions repository.TransactionRepository
+	usecase      *usecase.CustodyUsecase
+}
+
+func newCustodyFixture(t *testing.T, tokenAddress string) *custodyFixture {
+	t.Helper()
+	f := &custodyFixture{
+		node:         &node{known: make(map[string]bool)},
+		transactions: repository.NewMemoryTransactionRepository(),
+	}
+	server := httptest.NewServer(f.node)
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{
+		Custody: config.CustodyConfig{Seed: seed},
+		Chain:   config.ChainConfig{RPCURL: server.URL, TokenAddress: tokenAddress, GasLimit: gasLimit},
+	}
+	n := chain.NewNode(cfg)
+	u, err := usecase.NewCustodyUsecase(cfg, n, chain.NewSender(cfg, n),
+		repository.NewMemoryCustodialWalletRepository(), f.transactions,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.usecase = u
+	return f
+}
+
+func (f *custodyFixture) derive(t *testing.T, ownerID string) string {
+	t.Helper()
+	result, err := f.usecase.DeriveCustodialAddress(context.Background(), ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.WalletAddress
+}
+
+func (f *custodyFixture) transfer(t *testing.T, key uuid.UUID, ownerID, amount string) *usecase.TransferResult {
+	t.Helper()
+	result, err := f.usecase.TransferToken(context.Background(), key.String(), ownerID, recipient, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestDeriveCustodialAddressAllocatesIndexesPerOwner(t *testing.T) {
+	f := newCustodyFixture(t, token)
+	wallet, err := hdwallet.NewWallet(seed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addresses := map[string]string{}
+	for index, owner := range []string{"alice", "bob"} {
+		result, err := f.usecase.DeriveCustodialAddress(context.Background(), owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := wallet.Address(uint32(index))
+		if result.WalletAddress != want || result.DerivationPath != wallet.Path(uint32(index)) {
+			t.Fatalf("%s got %s at %s, want %s at %s", owner, result.WalletAddress, result.DerivationPath, want, wallet.Path(uint32(index)))
+		}
+		addresses[owner] = result.WalletAddress
+	}
+	if again := f.derive(t, "alice"); again != addresses["alice"] {
+		t.Fatal("alice's address changed")
+	}
+	if _, err := f.usecase.DeriveCustodialAddress(context.Background(), ""); err != usecase.ErrOwnerRequired {
+		t.Fatalf("error = %v, want %v", err, usecase.ErrOwnerRequired)
+	}
+}
+
+func TestTransferTokenBroadcastsASignedTransfer(t *testing.T) {
+	f := newCustodyFixture(t, token)
+	from := f.derive(t, "alice")
+
+	result := f.transfer(t, uuid.New(), "alice", "1500")
+	if !result.Success || result.Status != string(domain.TransactionStatusSubmitted) || result.FromAddress != from {
+		t.Fatalf("result %+v", result)
+	}
+
+	// The adapter's transaction must be the one signed by alice's key.
+	wallet, _ := hdwallet.NewWallet(seed, 0)
+	key, _ := wallet.Key(0)
+	data, err := chain.TransferCall(recipient, big.NewInt(1500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := chain.Transaction{
+		GasPrice: big.NewInt(gasPrice),
+		Gas:      gasLimit,
+		To:       token,
+		Data:     data,
+	}.Sign(key.PrivateKey(), big.NewInt(chainID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := f.node.sent()
+	if len(sent) != 1 || sent[0] != "0x"+hex.EncodeToString(want.Raw) {
+		t.Fatalf("broadcast %v, want %x", sent, want.Raw)
+	}
+	if result.TransactionHash != want.Hash {
+		t.Fatalf("hash %s, want %s", result.TransactionHash, want.Hash)
+	}
+}
+
+func TestTransferTokenIsIdempotent(t *testing.T) {
+	f := newCustodyFixture(t, token)
+	f.derive(t, "alice")
+	key := uuid.New()
+
+	first := f.transfer(t, key, "alice", "1500")
+	again := f.transfer(t, key, "alice", "1500")
+	if again.TransactionHash != first.TransactionHash || len(f.node.sent()) != 1 {
+		t.Fatalf("repeat sent %d transactions, hashes %s and %s", len(f.node.sent()), first.TransactionHash, again.TransactionHash)
+	}
+
+	reused := f.transfer(t, key, "alice", "2000")
+	if reused.Success || reused.ErrorCode != usecase.ErrorCodeIdempotencyMismatch {
+		t.Fatalf("reused key result %+v", reused)
+	}
+	if second := f.transfer(t, uuid.New(), "alice", "1500"); second.TransactionHash == first.TransactionHash {
+		t.Fatal("a new key reused the transfer")
+	}
+}
+
+func TestTransferTokenResendsTransfersThatWereNotBroadcast(t *testing.T) {
+	f := newCustodyFixture(t, token)
+	f.derive(t, "alice")
+	key := uuid.New()
+
+	f.node.reject(true)
+	failed := f.transfer(t, key, "alice", "1500")
+	if failed.Success || failed.ErrorCode != usecase.ErrorCodeNotBroadcast || !failed.Retryable {
+		t.Fatalf("result %+v, want a retryable %s", failed, usecase.ErrorCodeNotBroadcast)
+	}
+
+	f.node.reject(false)
+	retried := f.transfer(t, key, "alice", "1500")
+	if !retried.Success || len(f.node.sent()) != 1 {
+		t.Fatalf("retry %+v sent %d transactions", retried, len(f.node.sent()))
+	}
+
+	tx, err := f.transactions.GetByIdempotencyKey(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Status != domain.TransactionStatusSubmitted || tx.TransactionHash != retried.TransactionHash {
+		t.Fatalf("stored transaction %+v", tx)
+	}
+}
+
+func TestTransferTokenRefusesWhatItCannotSend(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		token     string
+		owner     string
+		amount    string
+		code      string
+		retryable bool
+	}{
+		{"no token", "", "alice", "1500", usecase.ErrorCodeTransfersDisabled, true},
+		{"no wallet", token, "bob", "1500", usecase.ErrorCodeCustodialWalletNotFound, false},
+		{"zero amount", token, "alice", "0", usecase.ErrorCodeInvalidArgument, false},
+		{"fractional amount", token, "alice", "1.5", usecase.ErrorCodeInvalidArgument, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newCustodyFixture(t, tc.token)
+			f.derive(t, "alice")
+
+			result := f.transfer(t, uuid.New(), tc.owner, tc.amount)
+			if result.Success || result.ErrorCode != tc.code || result.Retryable != tc.retryable {
+				t.Fatalf("result %+v, want %s", result, tc.code)
+			}
+			if sent := f.node.sent(); len(sent) != 0 {
+				t.Fatalf("broadcast %d transactions", len(sent))
+			}
+		})
+	}
+}
@@ -1,9 +1,52 @@
 package usecase
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/domain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/hdwallet"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/chain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/google/uuid"
+)
+
+// Error codes returned in MintResult for mints that did not succeed.
+const (
+	ErrorCodeMintingDisabled     = "MINTING_DISABLED"
+	ErrorCodeIdempotencyMismatch = "IDEMPOTENCY_KEY_REUSED"
+	ErrorCodeNotBroadcast        = "NOT_BROADCAST"
+	ErrorCodeReverted            = "TRANSACTION_REVERTED"
+)
+
+// TransactionStatusNotFound is the status of a transaction neither the chain
+// nor the adapter knows.
+const TransactionStatusNotFound = "not_found"
+
+// receiptPollInterval is how often a mint's receipt is looked for while
+// waiting for it.
+const receiptPollInterval = 250 * time.Millisecond
+
+var ErrMintingDisabled = errors.New("minting is disabled: CHAIN_TOKEN_ADDRESS or CHAIN_MINTER_KEY is not set")
 
 type (
-	TokenUsecase struct{}
+	// TokenUsecase mints the token and reads balances and transactions from
+	// the chain.
+	TokenUsecase struct {
+		node           *chain.Node
+		sender         *chain.Sender
+		transactions   repository.TransactionRepository
+		token          string
+		minter         *secp256k1.PrivateKey
+		receiptTimeout time.Duration
+		log            *slog.Logger
+	}
 
 	MintResult struct {
 		Success         bool
@@ -31,21 +74,287 @@ type (
 	}
 )
 
-func NewTokenUsecase() *TokenUsecase {
-	return &TokenUsecase{}
+func NewTokenUsecase(
+	cfg *config.Config,
+	node *chain.Node,
+	sender *chain.Sender,
+	transactions repository.TransactionRepository,
+	log *slog.Logger,
+) (*TokenUsecase, error) {
+	u := &TokenUsecase{
+		node:           node,
+		sender:         sender,
+		transactions:   transactions,
+		receiptTimeout: cfg.Chain.ReceiptTimeout,
+		log:            log,
+	}
+
+	if cfg.Chain.TokenAddress == "" || cfg.Chain.MinterKey == "" {
+		return u, nil
+	}
+	if err := hdwallet.ValidateAddress(cfg.Chain.TokenAddress); err != nil {
+		return nil, fmt.Errorf("CHAIN_TOKEN_ADDRESS: %w", err)
+	}
+	minter, err := chain.ParsePrivateKey(cfg.Chain.MinterKey)
+	if err != nil {
+		return nil, fmt.Errorf("CHAIN_MINTER_KEY: %w", err)
+	}
+	u.token = hdwallet.ChecksumAddress(cfg.Chain.TokenAddress)
+	u.minter = minter
+
+	return u, nil
 }
 
-func (TokenUsecase) MintToken(_ context.Context, _, _, _ string) (*MintResult, error) {
-	// TODO: Implementar lógica de mint
-	return &MintResult{Success: false}, nil
+// MintToken mints tokenAmount to walletAddress and waits for the mint to be
+// mined. Requests are idempotent on idempotencyKey: repeating one returns the
+// mint already sent, checking the chain again if it was not confirmed yet,
+// and only sends a new transaction if the last one never reached the chain
+// or reverted.
+func (u *TokenUsecase) MintToken(ctx context.Context, idempotencyKey, walletAddress, tokenAmount string) (*MintResult, error) {
+	key, err := uuid.Parse(idempotencyKey)
+	if err != nil {
+		return invalidMint("invalid idempotency key"), nil
+	}
+	if err := hdwallet.ValidateAddress(walletAddress); err != nil {
+		return invalidMint(err.Error()), nil
+	}
+	amount, ok := new(big.Int).SetString(tokenAmount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return invalidMint("token amount must be a positive integer"), nil
+	}
+	if u.minter == nil {
+		return &MintResult{
+			Status:       string(domain.TransactionStatusFailed),
+			ErrorCode:    ErrorCodeMintingDisabled,
+			ErrorMessage: ErrMintingDisabled.Error(),
+			Retryable:    true,
+		}, nil
+	}
+	walletAddress = hdwallet.ChecksumAddress(walletAddress)
+
+	tx, err := u.transactions.GetByIdempotencyKey(ctx, key)
+	switch {
+	case errors.Is(err, domain.ErrTransactionNotFound):
+		tx = &domain.BlockchainTransaction{
+			ID:             uuid.New(),
+			IdempotencyKey: key,
+			WalletAddress:  walletAddress,
+			TokenAmount:    amount.String(),
+			Status:         domain.TransactionStatusPending,
+		}
+		if err := u.transactions.Create(ctx, tx); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case tx.WalletAddress != walletAddress || tx.TokenAmount != amount.String():
+		return &MintResult{
+			Status:       string(domain.TransactionStatusFailed),
+			ErrorCode:    ErrorCodeIdempotencyMismatch,
+			ErrorMessage: "idempotency key was used for a mint of another amount or wallet",
+		}, nil
+	default:
+		done, err := u.sent(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return mintResultFrom(tx), nil
+		}
+	}
+
+	data, err := chain.MintCall(tx.WalletAddress, amount)
+	if err != nil {
+		return nil, err
+	}
+	_, err = u.sender.Send(ctx, u.minter, u.token, data, func(signed chain.SignedTransaction) error {
+		tx.TransactionHash = signed.Hash
+		tx.Nonce = int64(signed.Nonce)
+		tx.GasPrice = signed.GasPrice.String()
+		tx.Status = domain.TransactionStatusSubmitted
+		tx.ErrorCode, tx.ErrorMessage = "", ""
+		return u.transactions.Update(ctx, tx)
+	})
+	if errors.Is(err, chain.ErrNotBroadcast) {
+		u.log.WarnContext(ctx, "mint not broadcast", "transaction_id", tx.ID, "error", err)
+		if err := u.fail(ctx, tx, ErrorCodeNotBroadcast, err.Error()); err != nil {
+			return nil, err
+		}
+		return mintResultFrom(tx), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.awaitReceipt(ctx, tx); err != nil {
+		return nil, err
+	}
+	return mintResultFrom(tx), nil
 }
 
-func (TokenUsecase) GetBalance(_ context.Context, _ string) (*BalanceResult, error) {
-	// TODO: Implementar lógica de obtenção de saldo
-	return &BalanceResult{}, nil
+// sent reports whether tx, a mint requested before, needs no new
+// transaction: it is confirmed, or its transaction reached the node, in which
+// case its receipt is awaited again.
+func (u *TokenUsecase) sent(ctx context.Context, tx *domain.BlockchainTransaction) (bool, error) {
+	switch {
+	case tx.Status == domain.TransactionStatusConfirmed:
+		return true, nil
+	case tx.TransactionHash == "" || tx.ErrorCode == ErrorCodeReverted:
+		return false, nil
+	}
+
+	// The broadcast may have reached the node even though it reported an
+	// error, or the adapter stopped before it could tell.
+	known, err := u.node.TransactionKnown(ctx, tx.TransactionHash)
+	if err != nil {
+		return true, u.fail(ctx, tx, ErrorCodeNotBroadcast, err.Error())
+	}
+	if !known {
+		return false, nil
+	}
+	if tx.Status == domain.TransactionStatusFailed {
+		tx.Status = domain.TransactionStatusSubmitted
+		tx.ErrorCode, tx.ErrorMessage = "", ""
+		if err := u.transactions.Update(ctx, tx); err != nil {
+			return true, err
+		}
+	}
+	return true, u.awaitReceipt(ctx, tx)
 }
 
-func (TokenUsecase) GetTransaction(_ context.Context, _ string) (*TransactionResult, error) {
-	// TODO: Implementar lógica de obtenção de transação
-	return &TransactionResult{}, nil
+// awaitReceipt polls for the receipt of tx for up to the receipt timeout and
+// records the outcome. tx stays submitted if it is not mined by then.
+func (u *TokenUsecase) awaitReceipt(ctx context.Context, tx *domain.BlockchainTransaction) error {
+	ctx, cancel := context.WithTimeout(ctx, u.receiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := u.node.TransactionReceipt(ctx, tx.TransactionHash)
+		if err != nil && ctx.Err() == nil {
+			u.log.WarnContext(ctx, "failed to read mint receipt", "transaction_hash", tx.TransactionHash, "error", err)
+		}
+		if receipt != nil {
+			return u.settle(context.WithoutCancel(ctx), tx, receipt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// settle records the receipt of tx.
+func (u *TokenUsecase) settle(ctx context.Context, tx *domain.BlockchainTransaction, receipt *chain.Receipt) error {
+	tx.BlockNumber = int64(receipt.BlockNumber)
+	tx.GasUsed = int64(receipt.GasUsed)
+	if !receipt.Succeeded {
+		return u.fail(ctx, tx, ErrorCodeReverted, fmt.Sprintf("reverted in block %d", receipt.BlockNumber))
+	}
+	if err := u.transactions.MarkConfirmed(ctx, tx.ID, tx.BlockNumber, tx.GasUsed); err != nil {
+		return err
+	}
+	tx.Status = domain.TransactionStatusConfirmed
+	return nil
+}
+
+func (u *TokenUsecase) fail(ctx context.Context, tx *domain.BlockchainTransaction, code, message string) error {
+	if err := u.transactions.MarkFailed(ctx, tx.ID, code, message); err != nil {
+		return err
+	}
+	tx.Status = domain.TransactionStatusFailed
+	tx.ErrorCode, tx.ErrorMessage = code, message
+	return nil
+}
+
+// GetBalance returns the token balance of walletAddress at the latest block.
+func (u *TokenUsecase) GetBalance(ctx context.Context, walletAddress string) (*BalanceResult, error) {
+	if u.token == "" {
+		return nil, ErrMintingDisabled
+	}
+	data, err := chain.BalanceOfCall(walletAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := u.node.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := u.node.CallContract(ctx, u.token, data, block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BalanceResult{
+		WalletAddress: hdwallet.ChecksumAddress(walletAddress),
+		Balance:       new(big.Int).SetBytes(result).String(),
+		BlockNumber:   int64(block),
+	}, nil
+}
+
+// GetTransaction returns the status of a transaction on chain. Transactions
+// the adapter sent that are not mined yet are pending.
+func (u *TokenUsecase) GetTransaction(ctx context.Context, transactionHash string) (*TransactionResult, error) {
+	receipt, err := u.node.TransactionReceipt(ctx, transactionHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt == nil {
+		result := &TransactionResult{TransactionHash: transactionHash, Status: TransactionStatusNotFound}
+		tx, err := u.transactions.GetByTransactionHash(ctx, transactionHash)
+		switch {
+		case errors.Is(err, domain.ErrTransactionNotFound):
+		case err != nil:
+			return nil, err
+		case tx.Status == domain.TransactionStatusFailed:
+			result.Status = string(domain.TransactionStatusFailed)
+		default:
+			result.Status = string(domain.TransactionStatusPending)
+		}
+		return result, nil
+	}
+
+	latest, err := u.node.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := domain.TransactionStatusConfirmed
+	if !receipt.Succeeded {
+		status = domain.TransactionStatusFailed
+	}
+	return &TransactionResult{
+		TransactionHash: transactionHash,
+		Status:          string(status),
+		BlockNumber:     int64(receipt.BlockNumber),
+		Confirmations:   int64(latest) - int64(receipt.BlockNumber) + 1,
+		GasUsed:         int64(receipt.GasUsed),
+		Success:         receipt.Succeeded,
+	}, nil
+}
+
+func mintResultFrom(tx *domain.BlockchainTransaction) *MintResult {
+	return &MintResult{
+		Success:         tx.Status != domain.TransactionStatusFailed,
+		TransactionHash: tx.TransactionHash,
+		BlockNumber:     tx.BlockNumber,
+		Status:          string(tx.Status),
+		ErrorCode:       tx.ErrorCode,
+		ErrorMessage:    tx.ErrorMessage,
+		// A mint that never reached the chain can be sent again; one that
+		// reverted would revert again.
+		Retryable: tx.ErrorCode == ErrorCodeNotBroadcast,
+	}
+}
+
+func invalidMint(message string) *MintResult {
+	return &MintResult{
+		Status:       string(domain.TransactionStatusFailed),
+		ErrorCode:    ErrorCodeInvalidArgument,
+		ErrorMessage: message,
+	}
 }
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cashback-platform/pkg/health"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/chain"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// newHealthChecker bounds each readiness check by HEALTH_CHECK_TIMEOUT.
+func newHealthChecker(cfg *config.Config, log *slog.Logger) *health.Checker {
+	return health.NewChecker(cfg.Health.CheckTimeout, log)
+}
+
+// registerHealthChecks adds a readiness check for each dependency of the
+// adapter. The chain node is only checked when CHAIN_RPC_URL is set.
+func registerHealthChecks(checker *health.Checker, db *gorm.DB, node *chain.Node) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	checker.Add("postgres", sqlDB.PingContext)
+	if node.Configured() {
+		checker.Add("chain_node", node.Check)
+	}
+	return nil
+}
+
+// startHealthServer serves the probes on HEALTH_PORT for HTTP probes; the gRPC
+// server answers grpc.health.v1 from the same checks. On shutdown, readiness
+// fails first and stays failing for HEALTH_SHUTDOWN_DELAY before the server
+// stops. Stop hooks run in reverse, so this must be the last invoke for that
+// to happen before the gRPC server stops.
+func startHealthServer(lc fx.Lifecycle, checker *health.Checker, cfg *config.Config, log *slog.Logger) {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.Health.Port),
+		Handler:           checker.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				log.Info("health server starting", "port", cfg.Health.Port)
+				if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Error("health server failed", "error", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			checker.DrainFor(ctx, cfg.Health.ShutdownDelay)
+			return server.Shutdown(ctx)
+		},
+	})
+}
@@ -0,0 +1,92 @@
+// Package service assembles blockchain-adapter. The binary runs it on its own;
+// the end-to-end harness boots it in-process next to the other services.
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cashback-platform/services/blockchain-adapter/internal/config"
+	grpcserver "github.com/cashback-platform/services/blockchain-adapter/internal/grpc"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/chain"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/infra/database"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/metrics"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/repository"
+	repoTransaction "github.com/cashback-platform/services/blockchain-adapter/internal/repository/transaction"
+	"github.com/cashback-platform/services/blockchain-adapter/internal/tracing"
+	usecaseToken "github.com/cashback-platform/services/blockchain-adapter/internal/usecase"
+	"go.uber.org/fx"
+)
+
+// Module is the whole adapter. Configuration is read from the environment
+// while the app is built, by fx.New.
+var Module = fx.Options(
+	// Configuration
+	fx.Provide(config.NewConfig),
+
+	// Logging
+	fx.Provide(NewLogger),
+
+	// Tracing
+	fx.Invoke(tracing.Setup),
+
+	// Metrics
+	fx.Provide(metrics.NewRegistry),
+	fx.Provide(metrics.NewGRPCServer),
+	fx.Provide(metrics.NewBlockchain),
+	fx.Invoke(metrics.StartServer),
+
+	// Infrastructure
+	fx.Provide(database.NewPostgresDB),
+	fx.Provide(chain.NewNode),
+	fx.Provide(chain.NewSender),
+
+	// Repositories
+	fx.Provide(repoTransaction.NewRepository),
+	fx.Provide(repository.NewTransactionRepository),
+	// fx allows one decorator per type in a scope, so metrics and tracing
+	// wrap the repository together, tracing outermost.
+	fx.Decorate(func(repo repository.TransactionRepository, m *metrics.Blockchain, log *slog.Logger) repository.TransactionRepository {
+		return tracing.TraceTransactions(metrics.InstrumentTransactions(repo, m, log), log)
+	}),
+	fx.Provide(repository.NewCustodialWalletRepository),
+
+	// Usecases
+	fx.Provide(usecaseToken.NewTokenUsecase),
+	fx.Provide(usecaseToken.NewCustodyUsecase),
+
+	// gRPC Server
+	fx.Provide(grpcserver.NewTokenServer),
+
+	// Start server
+	fx.Invoke(grpcserver.StartServer),
+
+	// Health, last so readiness fails before anything stops
+	fx.Provide(newHealthChecker),
+	fx.Invoke(registerHealthChecks),
+	fx.Invoke(startHealthServer),
+)
+
+// Migrate applies every pending migration to the database in the environment
+// and returns how many it applied.
+func Migrate(ctx context.Context) (int, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return 0, err
+	}
+	db, err := database.Connect(cfg, NewLogger(cfg))
+	if err != nil {
+		return 0, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, err
+	}
+	defer sqlDB.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return 0, err
+	}
+	return migrator.Up(ctx)
+}
@@ -0,0 +1,45 @@
+package modules
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/handler/createcampaign"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/handler/findcampaign"
+	campaignrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/repository"
+	createcampaignuc "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/usecase/createcampaign"
+	findcampaignuc "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/usecase/findcampaign"
+
+	"go.uber.org/fx"
+)
+
+var (
+	campaignFactories = fx.Provide(
+		campaignrepo.New,
+		createcampaignuc.New,
+		findcampaignuc.New,
+		createcampaign.NewHandler,
+		findcampaign.NewHandler,
+	)
+
+	campaignDependencies = fx.Provide(
+		func(repo campaignrepo.Repository) createcampaignuc.Repository {
+			return repo
+		},
+		func(repo campaignrepo.Repository) findcampaignuc.Repository {
+			return repo
+		},
+	)
+
+	campaignInvokes = fx.Invoke(
+		func(params RouterParams, h createcampaign.Handler) {
+			createcampaign.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h findcampaign.Handler) {
+			findcampaign.RegisterEndpoint(params.APIRouter, h)
+		},
+	)
+
+	Campaign = fx.Options(
+		campaignFactories,
+		campaignDependencies,
+		campaignInvokes,
+	)
+)
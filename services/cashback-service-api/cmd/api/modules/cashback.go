@@ -1,16 +1,33 @@
 package modules
 
 import (
+	"context"
+	"log/slog"
+	"time"
+
+	campaignrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/consumer"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/handler/calculatecashback"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/handler/findusercashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/job"
 	cashbackrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/repository"
 	calculatecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	expirecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/expirecashback"
 	findusercashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/findusercashback"
+	reconcilecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/reconcilecashback"
+	releasecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/releasecashback"
+	settlecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/settlecashback"
+	merchantrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/repository"
 	purchaserepo "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/repository"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
 	userrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/user/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/grpc"
 	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging"
 
 	"go.uber.org/fx"
+	"gorm.io/gorm"
 )
 
 var (
@@ -18,6 +35,13 @@ var (
 		cashbackrepo.New,
 		calculatecashbackuc.New,
 		findusercashbackuc.New,
+		expirecashbackuc.New,
+		releasecashbackuc.New,
+		reconcilecashbackuc.New,
+		settlecashbackuc.New,
+		newMintRepository,
+		consumer.NewMintConsumer,
+		job.NewReconcileCashbackJob,
 		calculatecashback.NewHandler,
 		findusercashback.NewHandler,
 	)
@@ -32,12 +56,80 @@ var (
 		func(repo userrepo.Repository) calculatecashbackuc.UserRepository {
 			return repo
 		},
+		func(repo merchantrepo.Repository) calculatecashbackuc.MerchantRepository {
+			return repo
+		},
+		func(repo campaignrepo.Repository) calculatecashbackuc.CampaignRepository {
+			return repo
+		},
 		func(pub messaging.EventPublisher) calculatecashbackuc.OutboxPublisher {
 			return pub
 		},
+		func(cfg config.Referral) calculatecashbackuc.ReferralPolicy {
+			return calculatecashbackuc.ReferralPolicy{
+				ReferrerBonus:     cfg.ReferrerBonus,
+				RefereeBonus:      cfg.RefereeBonus,
+				MinPurchaseAmount: cfg.MinPurchaseAmount,
+			}
+		},
+		func(cfg config.Tier) userdomain.TierMultipliers {
+			return userdomain.TierMultipliers{
+				Silver: cfg.SilverMultiplier,
+				Gold:   cfg.GoldMultiplier,
+			}
+		},
 		func(repo cashbackrepo.Repository) findusercashbackuc.Repository {
 			return repo
 		},
+		func(repo cashbackrepo.Repository) expirecashbackuc.Repository {
+			return repo
+		},
+		func(repo campaignrepo.Repository) expirecashbackuc.CampaignRepository {
+			return repo
+		},
+		func(pub messaging.EventPublisher) expirecashbackuc.EventPublisher {
+			return pub
+		},
+		func(repo cashbackrepo.Repository) releasecashbackuc.Repository {
+			return repo
+		},
+		func(repo merchantrepo.Repository) releasecashbackuc.MerchantRepository {
+			return repo
+		},
+		func(pub messaging.EventPublisher) releasecashbackuc.OutboxPublisher {
+			return pub
+		},
+		func(cfg config.Expiry) expirecashbackuc.Policy {
+			return expirecashbackuc.Policy{
+				TTL:         time.Duration(cfg.TTLDays) * 24 * time.Hour,
+				WarningLead: time.Duration(cfg.WarningDays) * 24 * time.Hour,
+				BatchSize:   cfg.BatchSize,
+			}
+		},
+		func(uc expirecashbackuc.UseCase, cfg config.Expiry, log *slog.Logger) *job.ExpireCashbackJob {
+			return job.NewExpireCashbackJob(uc, cfg.Interval, log)
+		},
+		func(repo cashbackrepo.Repository) settlecashbackuc.Repository {
+			return repo
+		},
+		func(repo cashbackrepo.Repository) reconcilecashbackuc.Repository {
+			return repo
+		},
+		func(repo cashbackrepo.MintRepository) reconcilecashbackuc.MintRepository {
+			return repo
+		},
+		func(client *grpc.BlockchainAdapterClient) reconcilecashbackuc.BalanceReader {
+			return client
+		},
+		func(pub messaging.EventPublisher) reconcilecashbackuc.EventPublisher {
+			return pub
+		},
+		func(cfg config.Reconciliation) reconcilecashbackuc.Policy {
+			return reconcilecashbackuc.Policy{
+				Grace:         cfg.Grace,
+				TokenDecimals: cfg.TokenDecimals,
+			}
+		},
 	)
 
 	cashbackInvokes = fx.Invoke(
@@ -47,6 +139,9 @@ var (
 		func(params RouterParams, h findusercashback.Handler) {
 			findusercashback.RegisterEndpoint(params.APIRouter, h)
 		},
+		job.StartExpireCashbackJob,
+		job.StartReconcileCashbackJob,
+		consumer.StartMintConsumer,
 	)
 
 	Cashback = fx.Options(
@@ -55,3 +150,31 @@ var (
 		cashbackInvokes,
 	)
 )
+
+// newMintRepository connects to mint-consumer's database when reconciliation
+// is enabled. Otherwise the repository has no connection and reads fail.
+func newMintRepository(lc fx.Lifecycle, cfg config.Reconciliation, log *slog.Logger) (cashbackrepo.MintRepository, error) {
+	if !cfg.Enabled {
+		return cashbackrepo.NewMintRepository(nil), nil
+	}
+
+	db, err := database.Open(cfg.MintDatabaseURL, log)
+	if err != nil {
+		log.Error("failed to connect to mint database", "error", err)
+		return cashbackrepo.MintRepository{}, err
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return closeDatabase(db)
+		},
+	})
+	return cashbackrepo.NewMintRepository(db), nil
+}
+
+func closeDatabase(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,55 @@
+package modules
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/createmerchant"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/findmerchant"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/updatemerchantstatus"
+	merchantrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/repository"
+	createmerchantuc "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/createmerchant"
+	findmerchantuc "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/findmerchant"
+	updatemerchantstatusuc "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/updatemerchantstatus"
+
+	"go.uber.org/fx"
+)
+
+var (
+	merchantFactories = fx.Provide(
+		merchantrepo.New,
+		createmerchantuc.New,
+		findmerchantuc.New,
+		updatemerchantstatusuc.New,
+		createmerchant.NewHandler,
+		findmerchant.NewHandler,
+		updatemerchantstatus.NewHandler,
+	)
+
+	merchantDependencies = fx.Provide(
+		func(repo merchantrepo.Repository) createmerchantuc.Repository {
+			return repo
+		},
+		func(repo merchantrepo.Repository) findmerchantuc.Repository {
+			return repo
+		},
+		func(repo merchantrepo.Repository) updatemerchantstatusuc.Repository {
+			return repo
+		},
+	)
+
+	merchantInvokes = fx.Invoke(
+		func(params RouterParams, h createmerchant.Handler) {
+			createmerchant.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h findmerchant.Handler) {
+			findmerchant.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h updatemerchantstatus.Handler) {
+			updatemerchantstatus.RegisterEndpoint(params.APIRouter, h)
+		},
+	)
+
+	Merchant = fx.Options(
+		merchantFactories,
+		merchantDependencies,
+		merchantInvokes,
+	)
+)
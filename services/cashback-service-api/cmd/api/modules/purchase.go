@@ -1,11 +1,17 @@
 package modules
 
 import (
+	merchantrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/repository"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/createpurchase"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/findpurchase"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/listuserpurchases"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/refundpurchase"
 	purchaserepo "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/repository"
 	createpurchaseuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/createpurchase"
 	findpurchaseuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/findpurchase"
+	listuserpurchasesuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/listuserpurchases"
+	refundpurchaseuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/refundpurchase"
+	updatetieruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updatetier"
 
 	"go.uber.org/fx"
 )
@@ -15,17 +21,36 @@ var (
 		purchaserepo.New,
 		createpurchaseuc.New,
 		findpurchaseuc.New,
+		refundpurchaseuc.New,
+		listuserpurchasesuc.New,
 		createpurchase.NewHandler,
 		findpurchase.NewHandler,
+		refundpurchase.NewHandler,
+		listuserpurchases.NewHandler,
 	)
 
 	purchaseDependencies = fx.Provide(
 		func(repo purchaserepo.Repository) createpurchaseuc.Repository {
 			return repo
 		},
+		func(repo merchantrepo.Repository) createpurchaseuc.MerchantRepository {
+			return repo
+		},
 		func(repo purchaserepo.Repository) findpurchaseuc.Repository {
 			return repo
 		},
+		func(repo purchaserepo.Repository) refundpurchaseuc.Repository {
+			return repo
+		},
+		func(repo purchaserepo.Repository) listuserpurchasesuc.Repository {
+			return repo
+		},
+		func(uc updatetieruc.UseCase) createpurchaseuc.TierUpdater {
+			return uc
+		},
+		func(uc updatetieruc.UseCase) refundpurchaseuc.TierUpdater {
+			return uc
+		},
 	)
 
 	purchaseInvokes = fx.Invoke(
@@ -35,6 +60,12 @@ var (
 		func(params RouterParams, h findpurchase.Handler) {
 			findpurchase.RegisterEndpoint(params.APIRouter, h)
 		},
+		func(params RouterParams, h refundpurchase.Handler) {
+			refundpurchase.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h listuserpurchases.Handler) {
+			listuserpurchases.RegisterEndpoint(params.APIRouter, h)
+		},
 	)
 
 	Purchase = fx.Options(
@@ -1,11 +1,40 @@
 package modules
 
 import (
+	"log/slog"
+	"time"
+
+	releasecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/releasecashback"
+	purchaserepo "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/claimcustodialwallet"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/createuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/deactivateuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/eraseuser"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/finduser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/listwallets"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/requestwalletchallenge"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/setpayoutwallet"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/updateuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/verifywallet"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/job"
 	userrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/user/repository"
+	claimcustodialwalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/claimcustodialwallet"
 	createuseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/createuser"
+	deactivateuseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/deactivateuser"
+	decaytiersuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/decaytiers"
+	eraseuseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/eraseuser"
 	finduseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/finduser"
+	listwalletsuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/listwallets"
+	requestwalletchallengeuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/requestwalletchallenge"
+	setpayoutwalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/setpayoutwallet"
+	updatetieruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updatetier"
+	updateuseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updateuser"
+	verifywalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/verifywallet"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/grpc"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging"
 
 	"go.uber.org/fx"
 )
@@ -15,17 +44,134 @@ var (
 		userrepo.New,
 		createuseruc.New,
 		finduseruc.New,
+		updateuseruc.New,
+		deactivateuseruc.New,
+		eraseuseruc.New,
+		updatetieruc.New,
+		decaytiersuc.New,
+		requestwalletchallengeuc.New,
+		verifywalletuc.New,
+		listwalletsuc.New,
+		setpayoutwalletuc.New,
+		claimcustodialwalletuc.New,
 		createuser.NewHandler,
 		finduser.NewHandler,
+		updateuser.NewHandler,
+		deactivateuser.NewHandler,
+		eraseuser.NewHandler,
+		requestwalletchallenge.NewHandler,
+		verifywallet.NewHandler,
+		listwallets.NewHandler,
+		setpayoutwallet.NewHandler,
+		claimcustodialwallet.NewHandler,
 	)
 
 	userDependencies = fx.Provide(
 		func(repo userrepo.Repository) createuseruc.Repository {
 			return repo
 		},
+		func(client *grpc.BlockchainAdapterClient) createuseruc.CustodialWallets {
+			return client
+		},
+		func(cfg config.Custodial) createuseruc.Policy {
+			return createuseruc.Policy{CustodialFallback: cfg.Enabled}
+		},
 		func(repo userrepo.Repository) finduseruc.Repository {
 			return repo
 		},
+		func(repo userrepo.Repository) updateuseruc.Repository {
+			return repo
+		},
+		func(repo userrepo.Repository) deactivateuseruc.Repository {
+			return repo
+		},
+		func(pub messaging.EventPublisher) deactivateuseruc.EventPublisher {
+			return pub
+		},
+		func(repo userrepo.Repository) eraseuseruc.Repository {
+			return repo
+		},
+		func(pub messaging.EventPublisher) eraseuseruc.EventPublisher {
+			return pub
+		},
+		func(repo userrepo.Repository) updatetieruc.Repository {
+			return repo
+		},
+		func(repo purchaserepo.Repository) updatetieruc.PurchaseRepository {
+			return repo
+		},
+		func(pub messaging.EventPublisher) updatetieruc.EventPublisher {
+			return pub
+		},
+		func(t database.Transactor) updatetieruc.Transactor {
+			return t
+		},
+		func(repo userrepo.Repository) requestwalletchallengeuc.Repository {
+			return repo
+		},
+		func(cfg config.SIWE) requestwalletchallengeuc.Policy {
+			return requestwalletchallengeuc.Policy{
+				Domain:    cfg.Domain,
+				URI:       cfg.URI,
+				Statement: cfg.Statement,
+				ChainID:   cfg.ChainID,
+				TTL:       cfg.ChallengeTTL,
+			}
+		},
+		func(repo userrepo.Repository) verifywalletuc.Repository {
+			return repo
+		},
+		func(uc releasecashbackuc.UseCase) verifywalletuc.CashbackReleaser {
+			return uc
+		},
+		func(repo userrepo.Repository) listwalletsuc.Repository {
+			return repo
+		},
+		func(repo userrepo.Repository) setpayoutwalletuc.Repository {
+			return repo
+		},
+		func(pub messaging.EventPublisher) setpayoutwalletuc.EventPublisher {
+			return pub
+		},
+		func(cfg config.Wallet) setpayoutwalletuc.Policy {
+			return setpayoutwalletuc.Policy{Cooldown: cfg.ChangeCooldown}
+		},
+		func(cfg config.Wallet) claimcustodialwalletuc.Policy {
+			return claimcustodialwalletuc.Policy{Cooldown: cfg.ChangeCooldown}
+		},
+		func(repo userrepo.Repository) claimcustodialwalletuc.Repository {
+			return repo
+		},
+		func(client *grpc.BlockchainAdapterClient) claimcustodialwalletuc.CustodialWallets {
+			return client
+		},
+		func(pub messaging.EventPublisher) claimcustodialwalletuc.EventPublisher {
+			return pub
+		},
+		func(cfg config.Tier) updatetieruc.Policy {
+			return updatetieruc.Policy{
+				Thresholds: domain.TierThresholds{
+					Silver: cfg.SilverThreshold,
+					Gold:   cfg.GoldThreshold,
+				},
+				Window: time.Duration(cfg.WindowDays) * 24 * time.Hour,
+			}
+		},
+		func(repo userrepo.Repository) decaytiersuc.Repository {
+			return repo
+		},
+		func(uc updatetieruc.UseCase) decaytiersuc.TierUpdater {
+			return uc
+		},
+		func(cfg config.Tier) decaytiersuc.Policy {
+			return decaytiersuc.Policy{
+				StaleAfter: cfg.RecomputeAfter,
+				BatchSize:  cfg.RecomputeBatchSize,
+			}
+		},
+		func(uc decaytiersuc.UseCase, cfg config.Tier, log *slog.Logger) *job.DecayTiersJob {
+			return job.NewDecayTiersJob(uc, cfg.RecomputeInterval, log)
+		},
 	)
 
 	userInvokes = fx.Invoke(
@@ -35,6 +181,31 @@ var (
 		func(params RouterParams, h finduser.Handler) {
 			finduser.RegisterEndpoint(params.APIRouter, h)
 		},
+		func(params RouterParams, h updateuser.Handler) {
+			updateuser.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h deactivateuser.Handler) {
+			deactivateuser.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h eraseuser.Handler) {
+			eraseuser.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h requestwalletchallenge.Handler) {
+			requestwalletchallenge.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h verifywallet.Handler) {
+			verifywallet.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h listwallets.Handler) {
+			listwallets.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h setpayoutwallet.Handler) {
+			setpayoutwallet.RegisterEndpoint(params.APIRouter, h)
+		},
+		func(params RouterParams, h claimcustodialwallet.Handler) {
+			claimcustodialwallet.RegisterEndpoint(params.APIRouter, h)
+		},
+		job.StartDecayTiersJob,
 	)
 
 	User = fx.Options(
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	cashbackrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/repository"
+	calculatecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	reconcilecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/reconcilecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/nats"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ops"
+	"github.com/google/uuid"
+)
+
+// command is a subcommand. run is invoked by fx, so its parameters are the
+// dependencies it needs; it always takes the *invocation.
+type command struct {
+	args    string
+	summary string
+	minArgs int
+	maxArgs int // -1 for no limit
+	run     any
+}
+
+var commands = map[string]command{
+	"cashback": {
+		args:    "<cashback-id>",
+		summary: "show a cashback with its mint requests and blockchain transactions",
+		minArgs: 1, maxArgs: 1,
+		run: lookupCashback,
+	},
+	"retry-mint": {
+		args:    "<mint-request-id>",
+		summary: "make a failed mint request due for retry, granting one more attempt if needed",
+		minArgs: 1, maxArgs: 1,
+		run: retryMint,
+	},
+	"reemit": {
+		args:    "<cashback-id>",
+		summary: "publish cashback.approved again for an approved cashback",
+		minArgs: 1, maxArgs: 1,
+		run: reemitApproved,
+	},
+	"consumers": {
+		args:    "[stream...]",
+		summary: "show the state of the JetStream consumers of the service's streams",
+		minArgs: 0, maxArgs: -1,
+		run: inspectConsumers,
+	},
+	"reconcile": {
+		args:    "",
+		summary: "reconcile the ledger with mint requests and on-chain balances",
+		minArgs: 0, maxArgs: 0,
+		run: reconcile,
+	},
+}
+
+type (
+	// cashbackView is a ledger entry as cashbackctl prints it.
+	cashbackView struct {
+		ID             uuid.UUID  `json:"id"`
+		UserID         uuid.UUID  `json:"user_id"`
+		PurchaseID     uuid.UUID  `json:"purchase_id"`
+		MerchantID     uuid.UUID  `json:"merchant_id"`
+		Type           string     `json:"type"`
+		Status         string     `json:"status"`
+		Amount         float64    `json:"amount"`
+		BaseAmount     float64    `json:"base_amount"`
+		CampaignID     *uuid.UUID `json:"campaign_id,omitempty"`
+		CampaignAmount float64    `json:"campaign_amount"`
+		WalletAddress  string     `json:"wallet_address"`
+		CreatedAt      time.Time  `json:"created_at"`
+		UpdatedAt      time.Time  `json:"updated_at"`
+	}
+
+	// cashbackLookup is a cashback as each service sees it. A database that
+	// could not be read is reported in Errors, not fatal.
+	cashbackLookup struct {
+		Cashback     cashbackView      `json:"cashback"`
+		MintRequests []ops.MintRequest `json:"mint_requests"`
+		Transactions []ops.Transaction `json:"transactions"`
+		Errors       map[string]string `json:"errors,omitempty"`
+	}
+
+	reemitResult struct {
+		EventType string                                    `json:"event_type"`
+		Payload   calculatecashbackuc.CashbackApprovedEvent `json:"payload"`
+		DryRun    bool                                      `json:"dry_run"`
+	}
+)
+
+func lookupCashback(inv *invocation, cashbacks cashbackrepo.Repository, mints ops.MintStore, chain ops.ChainStore) error {
+	id, err := inv.uuidArg(0, "cashback ID")
+	if err != nil {
+		return err
+	}
+
+	cashback, err := cashbacks.FindByID(inv.ctx, id)
+	if err != nil {
+		return fmt.Errorf("cashback %s: %w", id, err)
+	}
+
+	lookup := cashbackLookup{Cashback: newCashbackView(cashback), Errors: map[string]string{}}
+	lookup.MintRequests, err = mints.FindByCashbackID(inv.ctx, id)
+	if err != nil {
+		lookup.Errors["mint_requests"] = err.Error()
+	}
+
+	keys := make([]uuid.UUID, len(lookup.MintRequests))
+	for i, m := range lookup.MintRequests {
+		keys[i] = m.IdempotencyKey
+	}
+	lookup.Transactions, err = chain.FindTransactions(inv.ctx, keys)
+	if err != nil {
+		lookup.Errors["transactions"] = err.Error()
+	}
+
+	return inv.print(lookup, func(w io.Writer) {
+		c := lookup.Cashback
+		fmt.Fprintf(w, "cashback\t%s\n", c.ID)
+		fmt.Fprintf(w, "status\t%s\n", c.Status)
+		fmt.Fprintf(w, "type\t%s\n", c.Type)
+		fmt.Fprintf(w, "amount\t%g (base %g, campaign %g)\n", c.Amount, c.BaseAmount, c.CampaignAmount)
+		fmt.Fprintf(w, "user\t%s\n", c.UserID)
+		fmt.Fprintf(w, "wallet\t%s\n", c.WalletAddress)
+		fmt.Fprintf(w, "created\t%s\n", c.CreatedAt.UTC().Format(time.RFC3339))
+		fmt.Fprintln(w)
+
+		fmt.Fprintln(w, "MINT REQUEST\tSTATUS\tRETRIES\tTOKEN AMOUNT\tTX HASH\tERROR")
+		for _, m := range lookup.MintRequests {
+			fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%s\t%s\n", m.ID, m.Status, m.RetryCount, m.MaxRetries, m.TokenAmount, m.TransactionHash, m.ErrorCode)
+		}
+		fmt.Fprintln(w)
+
+		fmt.Fprintln(w, "TRANSACTION\tSTATUS\tNONCE\tTX HASH\tBLOCK\tERROR")
+		for _, t := range lookup.Transactions {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%s\n", t.ID, t.Status, t.Nonce, t.TransactionHash, t.BlockNumber, t.ErrorCode)
+		}
+		for source, message := range lookup.Errors {
+			fmt.Fprintf(w, "\n%s: %s\n", source, message)
+		}
+	})
+}
+
+func retryMint(inv *invocation, mints ops.MintStore) error {
+	id, err := inv.uuidArg(0, "mint request ID")
+	if err != nil {
+		return err
+	}
+
+	request, err := mints.Retry(inv.ctx, id, inv.dryRun)
+	if err != nil {
+		return err
+	}
+
+	return inv.print(request, func(w io.Writer) {
+		fmt.Fprintf(w, "mint request\t%s\n", request.ID)
+		fmt.Fprintf(w, "cashback\t%s\n", request.CashbackID)
+		fmt.Fprintf(w, "retries\t%d/%d\n", request.RetryCount, request.MaxRetries)
+		fmt.Fprintf(w, "next retry\t%s\n", request.NextRetryAt.Format(time.RFC3339))
+		fmt.Fprintf(w, "last error\t%s %s\n", request.ErrorCode, request.ErrorMessage)
+		inv.dryRunNote(w)
+	})
+}
+
+func reemitApproved(inv *invocation, cashbacks cashbackrepo.Repository, publisher messaging.EventPublisher) error {
+	id, err := inv.uuidArg(0, "cashback ID")
+	if err != nil {
+		return err
+	}
+
+	cashback, err := cashbacks.FindByID(inv.ctx, id)
+	if err != nil {
+		return fmt.Errorf("cashback %s: %w", id, err)
+	}
+	// Anything past approved is minted, expired or failed for good; its mint
+	// request, if any, is retried with retry-mint instead.
+	if cashback.Status != domain.StatusApproved {
+		return fmt.Errorf("cashback %s is %s, only approved cashback can be re-emitted", id, cashback.Status)
+	}
+
+	result := reemitResult{
+		EventType: calculatecashbackuc.EventTypeCashbackApproved,
+		Payload:   calculatecashbackuc.NewCashbackApprovedEvent(cashback),
+		DryRun:    inv.dryRun,
+	}
+	if !inv.dryRun {
+		if err := publisher.Publish(inv.ctx, result.EventType, result.Payload); err != nil {
+			return fmt.Errorf("failed to add event to outbox: %w", err)
+		}
+	}
+
+	return inv.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "%s added to the outbox for cashback %s\n", result.EventType, id)
+		inv.dryRunNote(w)
+	})
+}
+
+func inspectConsumers(inv *invocation, client *nats.NATSClient) error {
+	streams := inv.args
+	if len(streams) == 0 {
+		streams = nats.StreamNames()
+	}
+
+	states, err := client.ConsumerStates(inv.ctx, streams...)
+	if err != nil {
+		return err
+	}
+
+	return inv.print(states, func(w io.Writer) {
+		fmt.Fprintln(w, "STREAM\tCONSUMER\tFILTER\tDELIVERED\tACK FLOOR\tPENDING\tACK PENDING\tREDELIVERED\tLAST ACTIVE")
+		for _, s := range states {
+			lastActive := "-"
+			if s.LastActive != nil {
+				lastActive = s.LastActive.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n",
+				s.Stream, s.Name, s.FilterSubject, s.Delivered, s.AckFloor, s.NumPending, s.NumAckPending, s.NumRedelivered, lastActive)
+		}
+	})
+}
+
+func reconcile(inv *invocation, useCase reconcilecashbackuc.UseCase) error {
+	report, err := useCase.Execute(inv.ctx)
+	if err != nil {
+		if errors.Is(err, cashbackrepo.ErrMintDatabaseNotConfigured) {
+			return fmt.Errorf("%w: set CASHBACKCTL_MINT_DATABASE_URL or RECONCILIATION_MINT_DATABASE_URL", err)
+		}
+		return err
+	}
+
+	if inv.json {
+		return report.WriteJSON(inv.stdout)
+	}
+	return inv.print(report, func(w io.Writer) {
+		fmt.Fprintf(w, "run\t%s\n", report.RunID)
+		fmt.Fprintf(w, "checked\t%d cashbacks, %d mint requests, %d wallets\n", report.Cashbacks, report.Mints, len(report.Wallets))
+		fmt.Fprintf(w, "discrepancies\t%d\n", len(report.Discrepancies))
+		if len(report.Discrepancies) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "KIND\tCASHBACK\tWALLET\tEXPECTED\tACTUAL\tDETAIL")
+			for _, d := range report.Discrepancies {
+				cashbackID := "-"
+				if d.CashbackID != uuid.Nil {
+					cashbackID = d.CashbackID.String()
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", d.Kind, cashbackID, d.WalletAddress, d.Expected, d.Actual, d.Detail)
+			}
+		}
+		if inv.dryRun {
+			fmt.Fprintln(w, "dry run: no discrepancy events were published")
+		}
+	})
+}
+
+func newCashbackView(c domain.Cashback) cashbackView {
+	return cashbackView{
+		ID:             c.ID,
+		UserID:         c.UserID,
+		PurchaseID:     c.PurchaseID,
+		MerchantID:     c.MerchantID,
+		Type:           c.Type,
+		Status:         c.Status,
+		Amount:         c.Amount,
+		BaseAmount:     c.BaseAmount,
+		CampaignID:     c.CampaignID,
+		CampaignAmount: c.CampaignAmount,
+		WalletAddress:  c.WalletAddress,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
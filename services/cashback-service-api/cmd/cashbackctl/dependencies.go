@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/cashback-platform/pkg/logger"
+	cashbackrepo "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/repository"
+	reconcilecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/reconcilecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/bootstrap"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/grpc"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging/outbox"
+	outboxrepo "github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging/outbox/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/nats"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ops"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+type (
+	// mintDatabase and chainDatabase tell the databases of mint-consumer and
+	// blockchain-adapter apart from the service's own. db is nil when the
+	// database is not configured.
+	mintDatabase  struct{ db *gorm.DB }
+	chainDatabase struct{ db *gorm.DB }
+)
+
+// dependencies are the service's own modules and repositories, minus the
+// HTTP server and background workers.
+var dependencies = fx.Options(
+	bootstrap.Config,
+	bootstrap.Database,
+	bootstrap.Metrics,
+	fx.Provide(
+		newLogger,
+		nats.NewNATSClient,
+		grpc.NewBlockchainAdapterClient,
+		cashbackrepo.New,
+		outboxrepo.New,
+		outbox.NewOutboxPublisher,
+		func(op *outbox.OutboxPublisher) messaging.EventPublisher {
+			return op
+		},
+		newMintDatabase,
+		newChainDatabase,
+		func(d mintDatabase) ops.MintStore {
+			return ops.NewMintStore(d.db)
+		},
+		func(d chainDatabase) ops.ChainStore {
+			return ops.NewChainStore(d.db)
+		},
+		reconcilecashbackuc.New,
+		func(repo cashbackrepo.Repository) reconcilecashbackuc.Repository {
+			return repo
+		},
+		func(d mintDatabase) reconcilecashbackuc.MintRepository {
+			return cashbackrepo.NewMintRepository(d.db)
+		},
+		func(client *grpc.BlockchainAdapterClient) reconcilecashbackuc.BalanceReader {
+			return client
+		},
+		// A dry run still finds every discrepancy but publishes none.
+		func(inv *invocation, pub messaging.EventPublisher) reconcilecashbackuc.EventPublisher {
+			if inv.dryRun {
+				return messaging.NewNoopPublisher()
+			}
+			return pub
+		},
+		func(cfg config.Reconciliation) reconcilecashbackuc.Policy {
+			return reconcilecashbackuc.Policy{
+				Grace:         cfg.Grace,
+				TokenDecimals: cfg.TokenDecimals,
+			}
+		},
+	),
+)
+
+// newLogger logs to stderr, keeping stdout for command output.
+func newLogger(cfg config.Log) *slog.Logger {
+	l := logger.New(os.Stderr, cfg.Level, "cashbackctl")
+	logger.Set(l)
+	return l
+}
+
+func newMintDatabase(lc fx.Lifecycle, cfg config.Ctl, log *slog.Logger) (mintDatabase, error) {
+	db, err := openDatabase(lc, cfg.MintDatabaseURL, log)
+	return mintDatabase{db: db}, err
+}
+
+func newChainDatabase(lc fx.Lifecycle, cfg config.Ctl, log *slog.Logger) (chainDatabase, error) {
+	db, err := openDatabase(lc, cfg.BlockchainDatabaseURL, log)
+	return chainDatabase{db: db}, err
+}
+
+func openDatabase(lc fx.Lifecycle, dsn string, log *slog.Logger) (*gorm.DB, error) {
+	if dsn == "" {
+		return nil, nil
+	}
+
+	db, err := database.Open(dsn, log)
+	if err != nil {
+		return nil, err
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		},
+	})
+	return db, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+)
+
+// invocation is what a command runs with: its arguments, flags and output.
+type invocation struct {
+	ctx    context.Context
+	args   []string
+	json   bool
+	dryRun bool
+	stdout io.Writer
+}
+
+// print writes v as JSON with --json, or calls text with a tabwriter
+// otherwise.
+func (inv *invocation) print(v any, text func(w io.Writer)) error {
+	if inv.json {
+		encoder := json.NewEncoder(inv.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(inv.stdout, 0, 4, 2, ' ', 0)
+	text(w)
+	return w.Flush()
+}
+
+func (inv *invocation) uuidArg(i int, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(inv.args[i])
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s %q: %w", name, inv.args[i], err)
+	}
+	return id, nil
+}
+
+// dryRunNote marks text output that describes a change not made.
+func (inv *invocation) dryRunNote(w io.Writer) {
+	if inv.dryRun {
+		fmt.Fprintln(w, "dry run: nothing was changed")
+	}
+}
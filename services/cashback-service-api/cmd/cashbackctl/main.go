@@ -0,0 +1,112 @@
+// Command cashbackctl runs operational tasks against the platform: looking up
+// a cashback across the three services, retrying mints, re-emitting events,
+// inspecting JetStream consumers and reconciling.
+//
+// It reads the same environment as the cashback service, plus
+// CASHBACKCTL_MINT_DATABASE_URL and CASHBACKCTL_BLOCKCHAIN_DATABASE_URL for the
+// databases of mint-consumer and blockchain-adapter.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cashback-platform/pkg/logger"
+	"go.uber.org/fx"
+)
+
+// errUsage reports bad arguments; the usage has already been printed.
+var errUsage = errors.New("usage")
+
+func main() {
+	logger.Init()
+
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "cashbackctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr)
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+		printUsage(stderr)
+		return errUsage
+	}
+
+	inv := &invocation{ctx: context.Background(), stdout: stdout}
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.BoolVar(&inv.json, "json", false, "print JSON instead of text")
+	flags.BoolVar(&inv.dryRun, "dry-run", false, "show what would change without changing it")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: cashbackctl %s [--json] [--dry-run] %s\n\n%s\n", name, cmd.args, cmd.summary)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	inv.args = flags.Args()
+	if len(inv.args) < cmd.minArgs || (cmd.maxArgs >= 0 && len(inv.args) > cmd.maxArgs) {
+		flags.Usage()
+		return errUsage
+	}
+
+	// The command runs as an invoke, so only the dependencies it asks for are
+	// built: looking up a cashback does not connect to NATS.
+	app := fx.New(
+		fx.NopLogger,
+		dependencies,
+		fx.Supply(inv),
+		fx.Invoke(cmd.run),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	// Starting and stopping runs the stop hooks that close connections.
+	if err := app.Start(inv.ctx); err != nil {
+		return err
+	}
+	return app.Stop(inv.ctx)
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	width := 0
+	for name, cmd := range commands {
+		names = append(names, name)
+		width = max(width, len(name)+1+len(cmd.args))
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: cashbackctl <command> [--json] [--dry-run] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, name := range names {
+		cmd := commands[name]
+		usage := strings.TrimSpace(name + " " + cmd.args)
+		fmt.Fprintf(w, "  %-*s  %s\n", width, usage, cmd.summary)
+	}
+}
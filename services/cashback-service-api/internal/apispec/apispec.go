@@ -0,0 +1,174 @@
+// Package apispec publishes the OpenAPI document of the API, built from the
+// Operation each handler package declares next to its route.
+package apispec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cashback-platform/pkg/logger"
+	createcampaignhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/handler/createcampaign"
+	findcampaignhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/handler/findcampaign"
+	calculatecashbackhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/handler/calculatecashback"
+	findusercashbackhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/handler/findusercashback"
+	createmerchanthandler "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/createmerchant"
+	findmerchanthandler "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/findmerchant"
+	updatemerchantstatushandler "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/updatemerchantstatus"
+	createpurchasehandler "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/createpurchase"
+	findpurchasehandler "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/findpurchase"
+	listuserpurchaseshandler "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/listuserpurchases"
+	refundpurchasehandler "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/refundpurchase"
+	claimcustodialwallethandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/claimcustodialwallet"
+	createuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/createuser"
+	deactivateuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/deactivateuser"
+	eraseuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/eraseuser"
+	finduserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/finduser"
+	listwalletshandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/listwallets"
+	requestwalletchallengehandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/requestwalletchallenge"
+	setpayoutwallethandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/setpayoutwallet"
+	updateuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/updateuser"
+	verifywallethandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/verifywallet"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/fx"
+)
+
+// Path is where the document is served, relative to the API router.
+const Path = "/openapi.json"
+
+// Operations lists every documented endpoint.
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		createuserhandler.Operation,
+		finduserhandler.Operation,
+		updateuserhandler.Operation,
+		deactivateuserhandler.Operation,
+		eraseuserhandler.Operation,
+		requestwalletchallengehandler.Operation,
+		verifywallethandler.Operation,
+		listwalletshandler.Operation,
+		setpayoutwallethandler.Operation,
+		claimcustodialwallethandler.Operation,
+		createmerchanthandler.Operation,
+		findmerchanthandler.Operation,
+		updatemerchantstatushandler.Operation,
+		createcampaignhandler.Operation,
+		findcampaignhandler.Operation,
+		createpurchasehandler.Operation,
+		findpurchasehandler.Operation,
+		listuserpurchaseshandler.Operation,
+		refundpurchasehandler.Operation,
+		calculatecashbackhandler.Operation,
+		findusercashbackhandler.Operation,
+	}
+}
+
+func New() (*openapi.Document, error) {
+	doc, err := openapi.New(
+		openapi.Info{
+			Title:       "Cashback Service API",
+			Version:     "1.0.0",
+			Description: "Purchases, cashback rules and users of the Web3 Cashback Platform.",
+		},
+		[]openapi.Server{{URL: "/api/v1"}},
+		Operations(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Components.SecuritySchemes = map[string]openapi.SecurityScheme{
+		"apiKey": {Type: "apiKey", In: "header", Name: auth.APIKeyHeader},
+		"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+	}
+	doc.Security = []map[string][]string{{"apiKey": {}}, {"bearer": {}}}
+	return doc, nil
+}
+
+// Marshal renders doc the way it is served and published.
+func Marshal(doc *openapi.Document) ([]byte, error) {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Handler serves doc.
+func Handler(doc *openapi.Document) (http.HandlerFunc, error) {
+	body, err := Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}, nil
+}
+
+type CheckRoutesParams struct {
+	fx.In
+
+	Router chi.Router `name:"api"`
+	Doc    *openapi.Document
+}
+
+// CheckRoutes fails startup when a registered route is not documented, or a
+// documented operation has no route. It must run after the modules
+// registered their routes.
+func CheckRoutes(p CheckRoutesParams) error {
+	if err := Compare(p.Router, p.Doc); err != nil {
+		return err
+	}
+	logger.Info("OpenAPI document matches routes", "operations", len(p.Doc.Operations()))
+	return nil
+}
+
+// Compare reports the differences between the routes of router and the
+// operations of doc.
+func Compare(router chi.Routes, doc *openapi.Document) error {
+	routes := make(map[string]bool)
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route != Path {
+			routes[method+" "+route] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var undocumented, unrouted []string
+	documented := make(map[string]bool)
+	for _, op := range doc.Operations() {
+		documented[op] = true
+		if !routes[op] {
+			unrouted = append(unrouted, op)
+		}
+	}
+	for route := range routes {
+		if !documented[route] {
+			undocumented = append(undocumented, route)
+		}
+	}
+	sort.Strings(undocumented)
+
+	var problems []string
+	if len(undocumented) > 0 {
+		problems = append(problems, "routes without operation: "+strings.Join(undocumented, ", "))
+	}
+	if len(unrouted) > 0 {
+		problems = append(problems, "operations without route: "+strings.Join(unrouted, ", "))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("openapi document does not match routes: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
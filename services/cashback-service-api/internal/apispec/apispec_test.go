@@ -0,0 +1,96 @@
+package apispec
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	createcampaignhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/handler/createcampaign"
+	findcampaignhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/handler/findcampaign"
+	calculatecashbackhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/handler/calculatecashback"
+	findusercashbackhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/handler/findusercashback"
+	createmerchanthandler "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/createmerchant"
+	findmerchanthandler "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/findmerchant"
+	updatemerchantstatushandler "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/handler/updatemerchantstatus"
+	createpurchasehandler "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/createpurchase"
+	findpurchasehandler "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/findpurchase"
+	listuserpurchaseshandler "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/listuserpurchases"
+	refundpurchasehandler "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/handler/refundpurchase"
+	claimcustodialwallethandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/claimcustodialwallet"
+	createuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/createuser"
+	deactivateuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/deactivateuser"
+	eraseuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/eraseuser"
+	finduserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/finduser"
+	listwalletshandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/listwallets"
+	requestwalletchallengehandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/requestwalletchallenge"
+	setpayoutwallethandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/setpayoutwallet"
+	updateuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/updateuser"
+	verifywallethandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/verifywallet"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// publishedSpec is the document partners integrate against.
+const publishedSpec = "../../api/openapi.json"
+
+var update = flag.Bool("update", false, "rewrite the published OpenAPI document")
+
+func TestPublishedSpecMatchesDTOs(t *testing.T) {
+	doc, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := os.WriteFile(publishedSpec, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(publishedSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("handler DTOs no longer match %s; review the change and run make openapi", publishedSpec)
+	}
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	doc, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := chi.NewRouter()
+	createcampaignhandler.RegisterEndpoint(r, createcampaignhandler.Handler{})
+	findcampaignhandler.RegisterEndpoint(r, findcampaignhandler.Handler{})
+	calculatecashbackhandler.RegisterEndpoint(r, calculatecashbackhandler.Handler{})
+	findusercashbackhandler.RegisterEndpoint(r, findusercashbackhandler.Handler{})
+	createmerchanthandler.RegisterEndpoint(r, createmerchanthandler.Handler{})
+	findmerchanthandler.RegisterEndpoint(r, findmerchanthandler.Handler{})
+	updatemerchantstatushandler.RegisterEndpoint(r, updatemerchantstatushandler.Handler{})
+	createpurchasehandler.RegisterEndpoint(r, createpurchasehandler.Handler{})
+	findpurchasehandler.RegisterEndpoint(r, findpurchasehandler.Handler{})
+	listuserpurchaseshandler.RegisterEndpoint(r, listuserpurchaseshandler.Handler{})
+	refundpurchasehandler.RegisterEndpoint(r, refundpurchasehandler.Handler{})
+	claimcustodialwallethandler.RegisterEndpoint(r, claimcustodialwallethandler.Handler{})
+	createuserhandler.RegisterEndpoint(r, createuserhandler.Handler{})
+	deactivateuserhandler.RegisterEndpoint(r, deactivateuserhandler.Handler{})
+	eraseuserhandler.RegisterEndpoint(r, eraseuserhandler.Handler{})
+	finduserhandler.RegisterEndpoint(r, finduserhandler.Handler{})
+	listwalletshandler.RegisterEndpoint(r, listwalletshandler.Handler{})
+	requestwalletchallengehandler.RegisterEndpoint(r, requestwalletchallengehandler.Handler{})
+	setpayoutwallethandler.RegisterEndpoint(r, setpayoutwallethandler.Handler{})
+	updateuserhandler.RegisterEndpoint(r, updateuserhandler.Handler{})
+	verifywallethandler.RegisterEndpoint(r, verifywallethandler.Handler{})
+
+	if err := Compare(r, doc); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,177 @@
+// Package domain contains the core business entities and rules for promotional campaigns.
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	merchantdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+// Sentinel errors for campaign domain validation.
+var (
+	ErrInvalidName         = errors.New("invalid campaign name")
+	ErrInvalidPeriod       = errors.New("invalid campaign period")
+	ErrInvalidMultiplier   = errors.New("invalid boost multiplier")
+	ErrInvalidBonus        = errors.New("invalid fixed bonus")
+	ErrNoBoost             = errors.New("campaign must define a multiplier or a fixed bonus")
+	ErrInvalidBudget       = errors.New("invalid campaign budget")
+	ErrInvalidCategoryCode = errors.New("invalid merchant category code")
+	ErrInvalidPredicate    = errors.New("invalid eligibility predicate")
+	ErrCampaignNotFound    = errors.New("campaign not found")
+	ErrBudgetExhausted     = errors.New("campaign budget exhausted")
+)
+
+type (
+	// Eligibility holds the optional predicates a purchase must satisfy
+	// for a campaign to apply. Zero values mean "no restriction".
+	Eligibility struct {
+		MerchantID   *uuid.UUID
+		CategoryCode string
+		// NewUserDays restricts the campaign to users registered at most
+		// this many days before the purchase.
+		NewUserDays int
+		// MaxPurchaseCount restricts the campaign to the user's first N purchases.
+		MaxPurchaseCount int
+		// MinTier restricts the campaign to users at this loyalty tier or above.
+		MinTier string
+	}
+
+	// Campaign represents a time-boxed promotion that boosts the base cashback rate.
+	// The boost is funded from the campaign budget, not by the merchant.
+	Campaign struct {
+		ID          uuid.UUID
+		Name        string
+		Eligibility Eligibility
+		// Multiplier is applied to the base cashback (2 doubles it, 1 leaves it unchanged).
+		Multiplier  float64
+		BonusAmount float64
+		Budget      float64
+		Spent       float64
+		StartsAt    time.Time
+		EndsAt      time.Time
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+	}
+
+	// PurchaseContext describes the purchase being evaluated against a campaign.
+	PurchaseContext struct {
+		MerchantID     uuid.UUID
+		CategoryCode   string
+		UserCreatedAt  time.Time
+		PriorPurchases int
+		UserTier       string
+		PurchasedAt    time.Time
+	}
+)
+
+// NewCampaign creates a new campaign instance with validation.
+// A multiplier of zero is treated as 1 (no multiplier boost).
+func NewCampaign(
+	name string,
+	eligibility Eligibility,
+	multiplier, bonusAmount, budget float64,
+	startsAt, endsAt time.Time,
+) (Campaign, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Campaign{}, ErrInvalidName
+	}
+	if startsAt.IsZero() || !endsAt.After(startsAt) {
+		return Campaign{}, ErrInvalidPeriod
+	}
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	if multiplier < 1 {
+		return Campaign{}, ErrInvalidMultiplier
+	}
+	if bonusAmount < 0 {
+		return Campaign{}, ErrInvalidBonus
+	}
+	if multiplier == 1 && bonusAmount == 0 {
+		return Campaign{}, ErrNoBoost
+	}
+	if budget <= 0 {
+		return Campaign{}, ErrInvalidBudget
+	}
+	if err := eligibility.validate(); err != nil {
+		return Campaign{}, err
+	}
+
+	now := time.Now().UTC()
+	return Campaign{
+		ID:          uuid.New(),
+		Name:        name,
+		Eligibility: eligibility,
+		Multiplier:  multiplier,
+		BonusAmount: bonusAmount,
+		Budget:      budget,
+		StartsAt:    startsAt.UTC(),
+		EndsAt:      endsAt.UTC(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+func (e Eligibility) validate() error {
+	if e.CategoryCode != "" && !merchantdomain.IsValidCategoryCode(e.CategoryCode) {
+		return ErrInvalidCategoryCode
+	}
+	if e.MerchantID != nil && *e.MerchantID == uuid.Nil {
+		return ErrInvalidPredicate
+	}
+	if e.NewUserDays < 0 || e.MaxPurchaseCount < 0 {
+		return ErrInvalidPredicate
+	}
+	if e.MinTier != "" && !userdomain.IsValidTier(e.MinTier) {
+		return ErrInvalidPredicate
+	}
+	return nil
+}
+
+// IsRunning reports whether the campaign is live at the given time.
+func (c Campaign) IsRunning(at time.Time) bool {
+	return !at.Before(c.StartsAt) && at.Before(c.EndsAt)
+}
+
+// RemainingBudget returns the budget still available to fund boosts.
+func (c Campaign) RemainingBudget() float64 {
+	return math.Max(c.Budget-c.Spent, 0)
+}
+
+// IsEligible reports whether the purchase satisfies every campaign predicate.
+func (c Campaign) IsEligible(p PurchaseContext) bool {
+	if !c.IsRunning(p.PurchasedAt) {
+		return false
+	}
+
+	e := c.Eligibility
+	if e.MerchantID != nil && *e.MerchantID != p.MerchantID {
+		return false
+	}
+	if e.CategoryCode != "" && e.CategoryCode != p.CategoryCode {
+		return false
+	}
+	if e.NewUserDays > 0 && p.PurchasedAt.Sub(p.UserCreatedAt) > time.Duration(e.NewUserDays)*24*time.Hour {
+		return false
+	}
+	if e.MaxPurchaseCount > 0 && p.PriorPurchases >= e.MaxPurchaseCount {
+		return false
+	}
+	if e.MinTier != "" && !userdomain.TierAtLeast(p.UserTier, e.MinTier) {
+		return false
+	}
+	return true
+}
+
+// Boost returns the extra cashback the campaign adds on top of baseAmount,
+// capped by the remaining budget.
+func (c Campaign) Boost(baseAmount float64) float64 {
+	boost := baseAmount*(c.Multiplier-1) + c.BonusAmount
+	return math.Min(boost, c.RemainingBudget())
+}
@@ -0,0 +1,112 @@
+package createcampaign
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/usecase/createcampaign"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/google/uuid"
+)
+
+type (
+	EligibilityPayload struct {
+		MerchantID       string `json:"merchant_id,omitempty" format:"uuid"`
+		CategoryCode     string `json:"category_code,omitempty"`
+		NewUserDays      int    `json:"new_user_days,omitempty"`
+		MaxPurchaseCount int    `json:"max_purchase_count,omitempty"`
+		MinTier          string `json:"min_tier,omitempty"`
+	}
+
+	InputPayload struct {
+		Name        string             `json:"name"`
+		Eligibility EligibilityPayload `json:"eligibility,omitempty"`
+		Multiplier  float64            `json:"multiplier,omitempty"`
+		BonusAmount float64            `json:"bonus_amount,omitempty"`
+		Budget      float64            `json:"budget"`
+		StartsAt    time.Time          `json:"starts_at"`
+		EndsAt      time.Time          `json:"ends_at"`
+	}
+
+	OutputPayload struct {
+		ID              string             `json:"id"`
+		Name            string             `json:"name"`
+		Eligibility     EligibilityPayload `json:"eligibility"`
+		Multiplier      float64            `json:"multiplier"`
+		BonusAmount     float64            `json:"bonus_amount"`
+		Budget          float64            `json:"budget"`
+		RemainingBudget float64            `json:"remaining_budget"`
+		StartsAt        string             `json:"starts_at"`
+		EndsAt          string             `json:"ends_at"`
+		CreatedAt       string             `json:"created_at"`
+	}
+)
+
+func (p InputPayload) Validate() error {
+	var v errorhandler.ValidationError
+	if strings.TrimSpace(p.Name) == "" {
+		v.Add("name", domain.ErrInvalidName)
+	}
+	if p.StartsAt.IsZero() || !p.EndsAt.After(p.StartsAt) {
+		v.Add("ends_at", domain.ErrInvalidPeriod)
+	}
+	if p.Budget <= 0 {
+		v.Add("budget", domain.ErrInvalidBudget)
+	}
+	if p.Eligibility.MerchantID != "" {
+		if _, err := uuid.Parse(p.Eligibility.MerchantID); err != nil {
+			v.Add("eligibility.merchant_id", domain.ErrInvalidPredicate)
+		}
+	}
+	return v.Err()
+}
+
+func (p InputPayload) ToInput() createcampaign.Input {
+	var merchantID *uuid.UUID
+	if p.Eligibility.MerchantID != "" {
+		id := uuid.MustParse(p.Eligibility.MerchantID)
+		merchantID = &id
+	}
+
+	return createcampaign.Input{
+		Name: p.Name,
+		Eligibility: domain.Eligibility{
+			MerchantID:       merchantID,
+			CategoryCode:     p.Eligibility.CategoryCode,
+			NewUserDays:      p.Eligibility.NewUserDays,
+			MaxPurchaseCount: p.Eligibility.MaxPurchaseCount,
+			MinTier:          p.Eligibility.MinTier,
+		},
+		Multiplier:  p.Multiplier,
+		BonusAmount: p.BonusAmount,
+		Budget:      p.Budget,
+		StartsAt:    p.StartsAt,
+		EndsAt:      p.EndsAt,
+	}
+}
+
+func ToOutputPayload(campaign domain.Campaign) OutputPayload {
+	eligibility := EligibilityPayload{
+		CategoryCode:     campaign.Eligibility.CategoryCode,
+		NewUserDays:      campaign.Eligibility.NewUserDays,
+		MaxPurchaseCount: campaign.Eligibility.MaxPurchaseCount,
+		MinTier:          campaign.Eligibility.MinTier,
+	}
+	if campaign.Eligibility.MerchantID != nil {
+		eligibility.MerchantID = campaign.Eligibility.MerchantID.String()
+	}
+
+	return OutputPayload{
+		ID:              campaign.ID.String(),
+		Name:            campaign.Name,
+		Eligibility:     eligibility,
+		Multiplier:      campaign.Multiplier,
+		BonusAmount:     campaign.BonusAmount,
+		Budget:          campaign.Budget,
+		RemainingBudget: campaign.RemainingBudget(),
+		StartsAt:        campaign.StartsAt.Format("2006-01-02T15:04:05Z07:00"),
+		EndsAt:          campaign.EndsAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:       campaign.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
@@ -0,0 +1,53 @@
+package findcampaign
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+)
+
+type (
+	EligibilityPayload struct {
+		MerchantID       string `json:"merchant_id,omitempty"`
+		CategoryCode     string `json:"category_code,omitempty"`
+		NewUserDays      int    `json:"new_user_days,omitempty"`
+		MaxPurchaseCount int    `json:"max_purchase_count,omitempty"`
+	}
+
+	OutputPayload struct {
+		ID              string             `json:"id"`
+		Name            string             `json:"name"`
+		Eligibility     EligibilityPayload `json:"eligibility"`
+		Multiplier      float64            `json:"multiplier"`
+		BonusAmount     float64            `json:"bonus_amount"`
+		Budget          float64            `json:"budget"`
+		Spent           float64            `json:"spent"`
+		RemainingBudget float64            `json:"remaining_budget"`
+		StartsAt        string             `json:"starts_at"`
+		EndsAt          string             `json:"ends_at"`
+		CreatedAt       string             `json:"created_at"`
+	}
+)
+
+func ToOutputPayload(campaign domain.Campaign) OutputPayload {
+	eligibility := EligibilityPayload{
+		CategoryCode:     campaign.Eligibility.CategoryCode,
+		NewUserDays:      campaign.Eligibility.NewUserDays,
+		MaxPurchaseCount: campaign.Eligibility.MaxPurchaseCount,
+	}
+	if campaign.Eligibility.MerchantID != nil {
+		eligibility.MerchantID = campaign.Eligibility.MerchantID.String()
+	}
+
+	return OutputPayload{
+		ID:              campaign.ID.String(),
+		Name:            campaign.Name,
+		Eligibility:     eligibility,
+		Multiplier:      campaign.Multiplier,
+		BonusAmount:     campaign.BonusAmount,
+		Budget:          campaign.Budget,
+		Spent:           campaign.Spent,
+		RemainingBudget: campaign.RemainingBudget(),
+		StartsAt:        campaign.StartsAt.Format("2006-01-02T15:04:05Z07:00"),
+		EndsAt:          campaign.EndsAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:       campaign.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
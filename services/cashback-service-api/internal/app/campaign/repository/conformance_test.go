@@ -0,0 +1,195 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/usecase/createcampaign"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/usecase/findcampaign"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database/databasetest"
+	"github.com/google/uuid"
+)
+
+// The same suite runs against the in-memory repository and the Postgres one,
+// so the two cannot drift apart. The Postgres run needs TEST_DATABASE_URL and
+// is skipped without it.
+
+type campaignRepository interface {
+	createcampaign.Repository
+	findcampaign.Repository
+	calculatecashback.CampaignRepository
+}
+
+func TestMemory(t *testing.T) {
+	testRepository(t, func(t *testing.T) campaignRepository {
+		return repository.NewMemory()
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	testRepository(t, func(t *testing.T) campaignRepository {
+		return repository.New(databasetest.New(t))
+	})
+}
+
+func testRepository(t *testing.T, newRepo func(t *testing.T) campaignRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("created campaigns are found by ID", func(t *testing.T) {
+		repo := newRepo(t)
+
+		merchantID := uuid.New()
+		campaign := newCampaign(t, now.Add(-time.Hour), now.Add(time.Hour), 100)
+		campaign.Eligibility = domain.Eligibility{MerchantID: &merchantID, CategoryCode: "5411", NewUserDays: 30, MinTier: "gold"}
+
+		created, err := repo.Create(ctx, campaign)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByID(ctx, created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Name != campaign.Name || found.Multiplier != campaign.Multiplier || found.Budget != campaign.Budget ||
+			!found.StartsAt.Equal(campaign.StartsAt) || !found.EndsAt.Equal(campaign.EndsAt) ||
+			found.Eligibility.MerchantID == nil || *found.Eligibility.MerchantID != merchantID ||
+			found.Eligibility.CategoryCode != "5411" || found.Eligibility.NewUserDays != 30 || found.Eligibility.MinTier != "gold" {
+			t.Fatalf("found %+v, want %+v", found, campaign)
+		}
+	})
+
+	t.Run("missing campaigns are not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindByID(ctx, uuid.New())
+		if !errors.Is(err, domain.ErrCampaignNotFound) {
+			t.Fatalf("error = %v, want %v", err, domain.ErrCampaignNotFound)
+		}
+	})
+
+	t.Run("running campaigns are live and have budget left", func(t *testing.T) {
+		repo := newRepo(t)
+
+		running := mustCreate(t, repo, newCampaign(t, now.Add(-time.Hour), now.Add(time.Hour), 100))
+		startsNow := mustCreate(t, repo, newCampaign(t, now, now.Add(time.Hour), 100))
+		mustCreate(t, repo, newCampaign(t, now.Add(-2*time.Hour), now, 100))
+		mustCreate(t, repo, newCampaign(t, now.Add(time.Minute), now.Add(time.Hour), 100))
+		spent := mustCreate(t, repo, newCampaign(t, now.Add(-time.Hour), now.Add(time.Hour), 10))
+		if err := repo.ReserveBudget(ctx, spent.ID, 10); err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := repo.FindRunning(ctx, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := map[uuid.UUID]bool{}
+		for _, c := range found {
+			ids[c.ID] = true
+		}
+		if len(found) != 2 || !ids[running.ID] || !ids[startsNow.ID] {
+			t.Fatalf("running campaigns = %v, want %s and %s", ids, running.ID, startsNow.ID)
+		}
+	})
+
+	t.Run("budget is reserved up to the budget and released", func(t *testing.T) {
+		repo := newRepo(t)
+
+		campaign := mustCreate(t, repo, newCampaign(t, now.Add(-time.Hour), now.Add(time.Hour), 10))
+		if err := repo.ReserveBudget(ctx, campaign.ID, 6); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.ReserveBudget(ctx, campaign.ID, 6); !errors.Is(err, domain.ErrBudgetExhausted) {
+			t.Fatalf("error = %v, want %v", err, domain.ErrBudgetExhausted)
+		}
+		if err := repo.ReserveBudget(ctx, campaign.ID, 4); err != nil {
+			t.Fatal(err)
+		}
+		assertSpent(t, repo, campaign.ID, 10)
+
+		if err := repo.ReleaseBudget(ctx, campaign.ID, 4); err != nil {
+			t.Fatal(err)
+		}
+		assertSpent(t, repo, campaign.ID, 6)
+
+		// Releasing more than was spent leaves nothing spent, not a negative amount.
+		if err := repo.ReleaseBudget(ctx, campaign.ID, 100); err != nil {
+			t.Fatal(err)
+		}
+		assertSpent(t, repo, campaign.ID, 0)
+
+		if err := repo.ReserveBudget(ctx, uuid.New(), 1); !errors.Is(err, domain.ErrBudgetExhausted) {
+			t.Fatalf("missing campaign: error = %v, want %v", err, domain.ErrBudgetExhausted)
+		}
+	})
+
+	t.Run("concurrent reservations never overspend", func(t *testing.T) {
+		repo := newRepo(t)
+
+		campaign := mustCreate(t, repo, newCampaign(t, now.Add(-time.Hour), now.Add(time.Hour), 5))
+		const n = 10
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			reserved int
+		)
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := repo.ReserveBudget(ctx, campaign.ID, 1)
+				if err != nil && !errors.Is(err, domain.ErrBudgetExhausted) {
+					t.Error(err)
+					return
+				}
+				if err == nil {
+					mu.Lock()
+					reserved++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if reserved != 5 {
+			t.Fatalf("reserved %d times, want 5", reserved)
+		}
+		assertSpent(t, repo, campaign.ID, 5)
+	})
+}
+
+func newCampaign(t *testing.T, startsAt, endsAt time.Time, budget float64) domain.Campaign {
+	t.Helper()
+	campaign, err := domain.NewCampaign("Double Days", domain.Eligibility{}, 2, 0, budget, startsAt, endsAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return campaign
+}
+
+func mustCreate(t *testing.T, repo campaignRepository, campaign domain.Campaign) domain.Campaign {
+	t.Helper()
+	created, err := repo.Create(context.Background(), campaign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func assertSpent(t *testing.T, repo campaignRepository, id uuid.UUID, want float64) {
+	t.Helper()
+	campaign, err := repo.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if campaign.Spent != want {
+		t.Fatalf("spent = %v, want %v", campaign.Spent, want)
+	}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Memory keeps campaigns in memory, for tests of the usecases that create
+// campaigns and spend their budget without a database. Like the campaigns
+// table, it fills in the ID, multiplier and timestamps left empty, refuses a
+// second campaign with the same ID and reserves budget atomically.
+type Memory struct {
+	mu        sync.RWMutex
+	campaigns map[uuid.UUID]domain.Campaign
+}
+
+// NewMemory creates an empty in-memory campaign repository.
+func NewMemory() *Memory {
+	return &Memory{campaigns: make(map[uuid.UUID]domain.Campaign)}
+}
+
+func (m *Memory) Create(_ context.Context, campaign domain.Campaign) (domain.Campaign, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if campaign.ID == uuid.Nil {
+		campaign.ID = uuid.New()
+	}
+	if campaign.Multiplier == 0 {
+		campaign.Multiplier = 1
+	}
+	if _, ok := m.campaigns[campaign.ID]; ok {
+		return domain.Campaign{}, gorm.ErrDuplicatedKey
+	}
+
+	now := time.Now()
+	if campaign.CreatedAt.IsZero() {
+		campaign.CreatedAt = now
+	}
+	if campaign.UpdatedAt.IsZero() {
+		campaign.UpdatedAt = now
+	}
+	m.campaigns[campaign.ID] = cloneCampaign(campaign)
+	return cloneCampaign(campaign), nil
+}
+
+func (m *Memory) FindByID(_ context.Context, id uuid.UUID) (domain.Campaign, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	campaign, ok := m.campaigns[id]
+	if !ok {
+		return domain.Campaign{}, domain.ErrCampaignNotFound
+	}
+	return cloneCampaign(campaign), nil
+}
+
+// FindRunning returns campaigns live at the given time that still have budget
+// left, oldest first.
+func (m *Memory) FindRunning(_ context.Context, at time.Time) ([]domain.Campaign, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := []domain.Campaign{}
+	for _, campaign := range m.campaigns {
+		if campaign.IsRunning(at) && campaign.Spent < campaign.Budget {
+			result = append(result, cloneCampaign(campaign))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// ReserveBudget moves amount from the remaining budget to spent.
+// Returns domain.ErrBudgetExhausted if the campaign cannot cover the amount.
+func (m *Memory) ReserveBudget(_ context.Context, id uuid.UUID, amount float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	campaign, ok := m.campaigns[id]
+	if !ok || campaign.Spent+amount > campaign.Budget {
+		return domain.ErrBudgetExhausted
+	}
+	campaign.Spent += amount
+	m.campaigns[id] = campaign
+	return nil
+}
+
+// ReleaseBudget returns a previously reserved amount to the campaign budget.
+func (m *Memory) ReleaseBudget(_ context.Context, id uuid.UUID, amount float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if campaign, ok := m.campaigns[id]; ok {
+		campaign.Spent = math.Max(campaign.Spent-amount, 0)
+		m.campaigns[id] = campaign
+	}
+	return nil
+}
+
+func cloneCampaign(campaign domain.Campaign) domain.Campaign {
+	if campaign.Eligibility.MerchantID != nil {
+		merchantID := *campaign.Eligibility.MerchantID
+		campaign.Eligibility.MerchantID = &merchantID
+	}
+	return campaign
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/google/uuid"
+)
+
+// campaignModel represents the database model for campaigns
+type campaignModel struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name             string     `gorm:"not null"`
+	MerchantID       *uuid.UUID `gorm:"type:uuid;index"`
+	CategoryCode     string     `gorm:"type:varchar(4)"`
+	NewUserDays      int        `gorm:"not null;default:0"`
+	MaxPurchaseCount int        `gorm:"not null;default:0"`
+	MinTier          string     `gorm:"type:varchar(20)"`
+	Multiplier       float64    `gorm:"not null;default:1"`
+	BonusAmount      float64    `gorm:"not null;default:0"`
+	Budget           float64    `gorm:"not null"`
+	Spent            float64    `gorm:"not null;default:0"`
+	StartsAt         time.Time  `gorm:"not null;index"`
+	EndsAt           time.Time  `gorm:"not null;index"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (campaignModel) TableName() string {
+	return "campaigns"
+}
+
+// toDomain converts database model to domain entity
+func (m campaignModel) toDomain() domain.Campaign {
+	return domain.Campaign{
+		ID:   m.ID,
+		Name: m.Name,
+		Eligibility: domain.Eligibility{
+			MerchantID:       m.MerchantID,
+			CategoryCode:     m.CategoryCode,
+			NewUserDays:      m.NewUserDays,
+			MaxPurchaseCount: m.MaxPurchaseCount,
+			MinTier:          m.MinTier,
+		},
+		Multiplier:  m.Multiplier,
+		BonusAmount: m.BonusAmount,
+		Budget:      m.Budget,
+		Spent:       m.Spent,
+		StartsAt:    m.StartsAt,
+		EndsAt:      m.EndsAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// fromDomain converts domain entity to database model
+func fromDomain(campaign domain.Campaign) campaignModel {
+	return campaignModel{
+		ID:               campaign.ID,
+		Name:             campaign.Name,
+		MerchantID:       campaign.Eligibility.MerchantID,
+		CategoryCode:     campaign.Eligibility.CategoryCode,
+		NewUserDays:      campaign.Eligibility.NewUserDays,
+		MaxPurchaseCount: campaign.Eligibility.MaxPurchaseCount,
+		MinTier:          campaign.Eligibility.MinTier,
+		Multiplier:       campaign.Multiplier,
+		BonusAmount:      campaign.BonusAmount,
+		Budget:           campaign.Budget,
+		Spent:            campaign.Spent,
+		StartsAt:         campaign.StartsAt,
+		EndsAt:           campaign.EndsAt,
+		CreatedAt:        campaign.CreatedAt,
+		UpdatedAt:        campaign.UpdatedAt,
+	}
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r Repository) FindByID(ctx context.Context, id uuid.UUID) (domain.Campaign, error) {
+	var campaign campaignModel
+
+	err := r.db.WithContext(ctx).First(&campaign, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Campaign{}, domain.ErrCampaignNotFound
+		}
+		return domain.Campaign{}, err
+	}
+
+	return campaign.toDomain(), nil
+}
+
+// FindRunning returns campaigns live at the given time that still have budget left.
+func (r Repository) FindRunning(ctx context.Context, at time.Time) ([]domain.Campaign, error) {
+	var campaigns []campaignModel
+
+	err := r.db.WithContext(ctx).
+		Where("starts_at <= ? AND ends_at > ? AND spent < budget", at, at).
+		Order("created_at ASC").
+		Find(&campaigns).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Campaign, len(campaigns))
+	for i, c := range campaigns {
+		result[i] = c.toDomain()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,19 @@
+// Package repository implements data persistence for campaign entities.
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// Repository handles campaign data persistence operations.
+// It provides methods for reading campaigns and reserving their budget.
+type Repository struct {
+	db *gorm.DB
+}
+
+// New creates a new campaign repository instance.
+func New(db *gorm.DB) Repository {
+	return Repository{
+		db: db,
+	}
+}
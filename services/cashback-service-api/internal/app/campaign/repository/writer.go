@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r Repository) Create(ctx context.Context, campaign domain.Campaign) (domain.Campaign, error) {
+	model := fromDomain(campaign)
+
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return domain.Campaign{}, err
+	}
+
+	return model.toDomain(), nil
+}
+
+// ReserveBudget atomically moves amount from the remaining budget to spent.
+// Returns domain.ErrBudgetExhausted if the campaign cannot cover the amount.
+func (r Repository) ReserveBudget(ctx context.Context, id uuid.UUID, amount float64) error {
+	result := r.db.WithContext(ctx).
+		Model(&campaignModel{}).
+		Where("id = ? AND spent + ? <= budget", id, amount).
+		Update("spent", gorm.Expr("spent + ?", amount))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrBudgetExhausted
+	}
+	return nil
+}
+
+// ReleaseBudget returns a previously reserved amount to the campaign budget.
+func (r Repository) ReleaseBudget(ctx context.Context, id uuid.UUID, amount float64) error {
+	return r.db.WithContext(ctx).
+		Model(&campaignModel{}).
+		Where("id = ?", id).
+		Update("spent", gorm.Expr("GREATEST(spent - ?, 0)", amount)).Error
+}
@@ -0,0 +1,52 @@
+package createcampaign
+
+import (
+	"context"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+)
+
+type (
+	Repository interface {
+		Create(ctx context.Context, campaign domain.Campaign) (domain.Campaign, error)
+	}
+
+	// Input holds the campaign definition submitted by marketing.
+	Input struct {
+		Name        string
+		Eligibility domain.Eligibility
+		Multiplier  float64
+		BonusAmount float64
+		Budget      float64
+		StartsAt    time.Time
+		EndsAt      time.Time
+	}
+
+	UseCase struct {
+		repository Repository
+	}
+)
+
+func New(repository Repository) UseCase {
+	return UseCase{
+		repository: repository,
+	}
+}
+
+func (u UseCase) Execute(ctx context.Context, input Input) (domain.Campaign, error) {
+	campaign, err := domain.NewCampaign(
+		input.Name,
+		input.Eligibility,
+		input.Multiplier,
+		input.BonusAmount,
+		input.Budget,
+		input.StartsAt,
+		input.EndsAt,
+	)
+	if err != nil {
+		return domain.Campaign{}, err
+	}
+
+	return u.repository.Create(ctx, campaign)
+}
@@ -0,0 +1,68 @@
+package createcampaign_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/usecase/createcampaign"
+	"github.com/google/uuid"
+)
+
+func TestExecute(t *testing.T) {
+	start := time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+	end := start.Add(72 * time.Hour)
+	merchantID := uuid.New()
+	valid := createcampaign.Input{Name: "Black Friday", Multiplier: 2, Budget: 1000, StartsAt: start, EndsAt: end}
+
+	tests := []struct {
+		name    string
+		change  func(*createcampaign.Input)
+		wantErr error
+	}{
+		{name: "multiplier", change: func(*createcampaign.Input) {}},
+		{name: "fixed bonus only", change: func(in *createcampaign.Input) { in.Multiplier, in.BonusAmount = 0, 5 }},
+		{name: "restricted to a merchant and tier", change: func(in *createcampaign.Input) {
+			in.Eligibility = domain.Eligibility{MerchantID: &merchantID, MinTier: "silver", MaxPurchaseCount: 1}
+		}},
+		{name: "blank name", change: func(in *createcampaign.Input) { in.Name = " " }, wantErr: domain.ErrInvalidName},
+		{name: "no start", change: func(in *createcampaign.Input) { in.StartsAt = time.Time{} }, wantErr: domain.ErrInvalidPeriod},
+		{name: "ends when it starts", change: func(in *createcampaign.Input) { in.EndsAt = start }, wantErr: domain.ErrInvalidPeriod},
+		{name: "shrinking multiplier", change: func(in *createcampaign.Input) { in.Multiplier = 0.5 }, wantErr: domain.ErrInvalidMultiplier},
+		{name: "negative bonus", change: func(in *createcampaign.Input) { in.BonusAmount = -1 }, wantErr: domain.ErrInvalidBonus},
+		{name: "no boost", change: func(in *createcampaign.Input) { in.Multiplier = 1 }, wantErr: domain.ErrNoBoost},
+		{name: "no budget", change: func(in *createcampaign.Input) { in.Budget = 0 }, wantErr: domain.ErrInvalidBudget},
+		{name: "bad category code", change: func(in *createcampaign.Input) { in.Eligibility.CategoryCode = "shop" }, wantErr: domain.ErrInvalidCategoryCode},
+		{name: "nil merchant", change: func(in *createcampaign.Input) { in.Eligibility.MerchantID = &uuid.Nil }, wantErr: domain.ErrInvalidPredicate},
+		{name: "unknown tier", change: func(in *createcampaign.Input) { in.Eligibility.MinTier = "diamond" }, wantErr: domain.ErrInvalidPredicate},
+		{name: "negative new user days", change: func(in *createcampaign.Input) { in.Eligibility.NewUserDays = -1 }, wantErr: domain.ErrInvalidPredicate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := repository.NewMemory()
+			input := valid
+			tt.change(&input)
+
+			campaign, err := createcampaign.New(repo).Execute(ctx, input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			found, err := repo.FindByID(ctx, campaign.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.Name != input.Name || found.Spent != 0 || !found.StartsAt.Equal(start) || found.Multiplier < 1 {
+				t.Fatalf("stored %+v", found)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package findcampaign_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/usecase/findcampaign"
+	"github.com/google/uuid"
+)
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemory()
+	start := time.Now().UTC()
+	campaign, err := domain.NewCampaign("Double Days", domain.Eligibility{}, 2, 0, 100, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(ctx, campaign); err != nil {
+		t.Fatal(err)
+	}
+	usecase := findcampaign.New(repo)
+
+	found, err := usecase.Execute(ctx, campaign.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != campaign.ID || found.Name != campaign.Name {
+		t.Fatalf("found %+v, want %+v", found, campaign)
+	}
+
+	if _, err := usecase.Execute(ctx, uuid.New()); !errors.Is(err, domain.ErrCampaignNotFound) {
+		t.Fatalf("error = %v, want %v", err, domain.ErrCampaignNotFound)
+	}
+}
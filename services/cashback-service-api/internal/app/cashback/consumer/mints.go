@@ -0,0 +1,176 @@
+// Package consumer contains the NATS consumers of the cashback context.
+package consumer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/settlecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/nats"
+	"github.com/cashback-platform/services/cashback-service-api/internal/tracing"
+	natsgo "github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+// TokenStream holds the events the Mint Consumer publishes.
+const TokenStream = "TOKEN_EVENTS"
+
+var tracer = otel.Tracer("github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/consumer")
+
+// MintConsumer settles cashback from the Mint Consumer's token.minted and
+// token.mint.failed events. Replicas share its durables, so each event is
+// handled once.
+type MintConsumer struct {
+	useCase    settlecashback.UseCase
+	natsClient *nats.NATSClient
+	log        *slog.Logger
+	done       chan struct{}
+	subs       []*natsgo.Subscription
+}
+
+func NewMintConsumer(useCase settlecashback.UseCase, natsClient *nats.NATSClient, log *slog.Logger) *MintConsumer {
+	return &MintConsumer{
+		useCase:    useCase,
+		natsClient: natsClient,
+		log:        log,
+		done:       make(chan struct{}),
+	}
+}
+
+func (c *MintConsumer) Start(ctx context.Context) error {
+	for _, s := range []struct {
+		subject string
+		durable string
+		handle  func(ctx context.Context, data []byte) error
+	}{
+		{settlecashback.SubjectTokenMinted, "cashback-service-api-minted", c.useCase.ProcessTokenMinted},
+		{settlecashback.SubjectTokenMintFailed, "cashback-service-api-mint-failed", c.useCase.ProcessTokenMintFailed},
+	} {
+		sub, err := c.subscribe(s.subject, s.durable)
+		if err != nil {
+			return err
+		}
+		c.subs = append(c.subs, sub)
+		go c.processMessages(ctx, sub, s.durable, s.handle)
+	}
+
+	c.log.Info("listening for events", "subjects", []string{settlecashback.SubjectTokenMinted, settlecashback.SubjectTokenMintFailed})
+	return nil
+}
+
+func (c *MintConsumer) subscribe(subject, durable string) (*natsgo.Subscription, error) {
+	js := c.natsClient.JetStream()
+
+	_, err := js.AddConsumer(TokenStream, &natsgo.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: subject,
+		DeliverPolicy: natsgo.DeliverAllPolicy,
+		AckPolicy:     natsgo.AckExplicitPolicy,
+		MaxDeliver:    5,
+		AckWait:       30 * time.Second,
+	})
+	if err != nil && err != natsgo.ErrConsumerNameAlreadyInUse {
+		c.log.Warn("failed to create consumer", "consumer", durable, "error", err)
+	}
+
+	return js.PullSubscribe(subject, durable)
+}
+
+func (c *MintConsumer) processMessages(
+	ctx context.Context,
+	sub *natsgo.Subscription,
+	durable string,
+	handle func(ctx context.Context, data []byte) error,
+) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.done:
+			return
+		default:
+			msgs, err := sub.Fetch(10, natsgo.MaxWait(time.Second))
+			if err != nil {
+				if err != natsgo.ErrTimeout {
+					c.log.ErrorContext(ctx, "failed to fetch messages", "consumer", durable, "error", err)
+				}
+				continue
+			}
+
+			for _, msg := range msgs {
+				c.handleMessage(ctx, msg, durable, handle)
+			}
+		}
+	}
+}
+
+func (c *MintConsumer) handleMessage(
+	ctx context.Context,
+	msg *natsgo.Msg,
+	durable string,
+	handle func(ctx context.Context, data []byte) error,
+) {
+	// Continue the trace the Mint Consumer put in the message headers.
+	ctx = otel.GetTextMapPropagator().Extract(ctx, tracing.HeaderCarrier(msg.Header))
+	if id := msg.Header.Get(natsgo.MsgIdHdr); id != "" {
+		ctx = logger.WithEventID(ctx, id)
+	}
+	ctx, span := tracer.Start(ctx, msg.Subject+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingDestinationName(msg.Subject),
+			attribute.String("messaging.nats.consumer", durable),
+		),
+	)
+	defer span.End()
+
+	if err := handle(ctx, msg.Data); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "handling failed")
+		c.log.ErrorContext(ctx, "failed to process message", "subject", msg.Subject, "error", err)
+		if err := msg.Nak(); err != nil {
+			c.log.ErrorContext(ctx, "failed to nak message", "error", err)
+		}
+		return
+	}
+	if err := msg.Ack(); err != nil {
+		c.log.ErrorContext(ctx, "failed to ack message", "error", err)
+	}
+}
+
+func (c *MintConsumer) Stop() {
+	close(c.done)
+	for _, sub := range c.subs {
+		if err := sub.Unsubscribe(); err != nil {
+			c.log.Error("failed to unsubscribe", "subject", sub.Subject, "error", err)
+		}
+	}
+}
+
+func StartMintConsumer(lc fx.Lifecycle, consumer *MintConsumer) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			if err := consumer.Start(ctx); err != nil {
+				return err
+			}
+			consumer.log.Info("mint consumer started")
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			consumer.Stop()
+			consumer.log.Info("mint consumer stopped")
+			return nil
+		},
+	})
+}
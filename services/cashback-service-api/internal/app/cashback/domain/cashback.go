@@ -3,6 +3,7 @@ package domain
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,26 +15,48 @@ const (
 	StatusApproved = "approved"
 	StatusMinted   = "minted"
 	StatusFailed   = "failed"
+	StatusExpired  = "expired"
+)
+
+// Cashback types distinguish purchase cashback from program bonuses.
+const (
+	TypePurchase = "purchase"
+	TypeReferral = "referral"
 )
 
 // Sentinel errors for cashback domain validation.
 var (
 	ErrInvalidUserID     = errors.New("invalid user ID")
 	ErrInvalidPurchaseID = errors.New("invalid purchase ID")
+	ErrInvalidMerchantID = errors.New("invalid merchant ID")
 	ErrInvalidAmount     = errors.New("invalid cashback amount")
 	ErrInvalidPercentage = errors.New("invalid cashback percentage")
 	ErrCashbackNotFound  = errors.New("cashback not found")
+	ErrDuplicateCashback = errors.New("cashback of this type already exists for the purchase")
+	ErrNotExpirable      = errors.New("minted or expired cashback cannot expire")
 )
 
 // Cashback represents a cashback transaction in the system.
-// It tracks the cashback amount, status, and relationships to users and purchases.
+// It tracks the cashback amount, status, and relationships to users, purchases
+// and the merchant funding it. Amount is the sum of BaseAmount, funded by the
+// merchant, and CampaignAmount, funded by the campaign identified by CampaignID.
+// WalletAddress is the payout wallet captured at approval; it never changes
+// afterwards, so cashback in flight keeps its original destination.
+// ExpiryWarnedAt records when the user was warned that the cashback is about to expire.
 type Cashback struct {
 	ID              uuid.UUID
 	UserID          uuid.UUID
 	PurchaseID      uuid.UUID
+	MerchantID      uuid.UUID
+	Type            string
 	Amount          float64
+	BaseAmount      float64
+	CampaignID      *uuid.UUID
+	CampaignAmount  float64
 	CashbackPercent float64
 	Status          string
+	WalletAddress   string
+	ExpiryWarnedAt  *time.Time
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -41,13 +64,16 @@ type Cashback struct {
 // NewCashback creates a new cashback instance with validation.
 // It calculates the cashback amount based on the purchase amount and percentage.
 // Returns an error if any validation fails.
-func NewCashback(userID, purchaseID uuid.UUID, purchaseAmount, cashbackPercent float64) (Cashback, error) {
+func NewCashback(userID, purchaseID, merchantID uuid.UUID, purchaseAmount, cashbackPercent float64) (Cashback, error) {
 	if userID == uuid.Nil {
 		return Cashback{}, ErrInvalidUserID
 	}
 	if purchaseID == uuid.Nil {
 		return Cashback{}, ErrInvalidPurchaseID
 	}
+	if merchantID == uuid.Nil {
+		return Cashback{}, ErrInvalidMerchantID
+	}
 	if purchaseAmount <= 0 {
 		return Cashback{}, ErrInvalidAmount
 	}
@@ -62,7 +88,10 @@ func NewCashback(userID, purchaseID uuid.UUID, purchaseAmount, cashbackPercent f
 		ID:              uuid.New(),
 		UserID:          userID,
 		PurchaseID:      purchaseID,
+		MerchantID:      merchantID,
+		Type:            TypePurchase,
 		Amount:          cashbackAmount,
+		BaseAmount:      cashbackAmount,
 		CashbackPercent: cashbackPercent,
 		Status:          StatusPending,
 		CreatedAt:       now,
@@ -70,10 +99,48 @@ func NewCashback(userID, purchaseID uuid.UUID, purchaseAmount, cashbackPercent f
 	}, nil
 }
 
-// Approve transitions the cashback to approved status.
-// This indicates the cashback is ready to be minted as tokens.
-func (c *Cashback) Approve() {
+// NewReferralCashback creates a referral bonus entry for a user.
+// The bonus is funded by the platform, so it has no merchant, and it
+// references the referee's qualifying purchase for traceability.
+func NewReferralCashback(userID, purchaseID uuid.UUID, amount float64) (Cashback, error) {
+	if userID == uuid.Nil {
+		return Cashback{}, ErrInvalidUserID
+	}
+	if purchaseID == uuid.Nil {
+		return Cashback{}, ErrInvalidPurchaseID
+	}
+	if amount <= 0 {
+		return Cashback{}, ErrInvalidAmount
+	}
+
+	now := time.Now().UTC()
+	return Cashback{
+		ID:         uuid.New(),
+		UserID:     userID,
+		PurchaseID: purchaseID,
+		Type:       TypeReferral,
+		Amount:     amount,
+		BaseAmount: amount,
+		Status:     StatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// ApplyCampaign adds a campaign-funded boost on top of the base amount.
+// Any previously applied campaign is replaced.
+func (c *Cashback) ApplyCampaign(campaignID uuid.UUID, amount float64) {
+	c.CampaignID = &campaignID
+	c.CampaignAmount = amount
+	c.Amount = c.BaseAmount + amount
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// Approve transitions the cashback to approved status and records the wallet
+// it will be minted to. This indicates the cashback is ready to be minted as tokens.
+func (c *Cashback) Approve(walletAddress string) {
 	c.Status = StatusApproved
+	c.WalletAddress = walletAddress
 	c.UpdatedAt = time.Now().UTC()
 }
 
@@ -85,8 +152,41 @@ func (c *Cashback) MarkAsMinted() {
 }
 
 // MarkAsFailed transitions the cashback to failed status.
-// This indicates the minting process failed and may require manual intervention.
+// This indicates the Mint Consumer gave up minting it; only a manual retry
+// mints it now.
 func (c *Cashback) MarkAsFailed() {
 	c.Status = StatusFailed
 	c.UpdatedAt = time.Now().UTC()
 }
+
+// ExpirableStatuses lists the statuses of cashback that may expire: held until
+// the user verifies a wallet, approved but never minted, or failed to mint.
+// Approved cashback normally becomes minted or failed long before the TTL; one
+// still approved by then lost its mint, and expiring it stops the Mint
+// Consumer retrying.
+var ExpirableStatuses = []string{StatusPending, StatusApproved, StatusFailed}
+
+// MintableStatuses lists the statuses a mint can settle: approved cashback and
+// failed cashback minted again by a manual retry.
+var MintableStatuses = []string{StatusApproved, StatusFailed}
+
+// IsExpirable reports whether the cashback may expire.
+func (c Cashback) IsExpirable() bool {
+	return slices.Contains(ExpirableStatuses, c.Status)
+}
+
+// IsHeld reports whether the cashback is waiting for the user to verify a wallet.
+func (c Cashback) IsHeld() bool {
+	return c.Status == StatusPending
+}
+
+// Expire transitions held, approved or failed cashback to expired status.
+// Expired cashback is never minted.
+func (c *Cashback) Expire() error {
+	if !c.IsExpirable() {
+		return ErrNotExpirable
+	}
+	c.Status = StatusExpired
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
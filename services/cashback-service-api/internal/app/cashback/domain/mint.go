@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Mint is a completed mint of cashback tokens as recorded by mint-consumer.
+// TokenAmount is in the token's smallest unit (wei), as a decimal string.
+type Mint struct {
+	ID            uuid.UUID
+	CashbackID    uuid.UUID
+	UserID        uuid.UUID
+	WalletAddress string
+	TokenAmount   string
+	CompletedAt   *time.Time
+}
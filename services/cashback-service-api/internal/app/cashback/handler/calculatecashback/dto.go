@@ -2,39 +2,59 @@ package calculatecashback
 
 import (
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/validator"
+	"github.com/google/uuid"
 )
 
 type (
 	InputPayload struct {
-		PurchaseID string `json:"purchase_id"`
+		PurchaseID string `json:"purchase_id" format:"uuid"`
 	}
 
 	OutputPayload struct {
 		ID              string  `json:"id"`
 		UserID          string  `json:"user_id"`
 		PurchaseID      string  `json:"purchase_id"`
+		MerchantID      string  `json:"merchant_id,omitempty"`
+		Type            string  `json:"type"`
 		Amount          float64 `json:"amount"`
+		BaseAmount      float64 `json:"base_amount"`
+		CampaignID      string  `json:"campaign_id,omitempty"`
+		CampaignAmount  float64 `json:"campaign_amount"`
 		CashbackPercent float64 `json:"cashback_percent"`
 		Status          string  `json:"status"`
+		WalletAddress   string  `json:"wallet_address,omitempty"`
 		CreatedAt       string  `json:"created_at"`
 	}
 )
 
 func (p InputPayload) Validate() error {
 	if p.PurchaseID == "" {
-		return domain.ErrInvalidPurchaseID
+		return errorhandler.Invalid("purchase_id", validator.ErrRequired)
 	}
 	return nil
 }
 
 func ToOutputPayload(cashback domain.Cashback) OutputPayload {
-	return OutputPayload{
+	output := OutputPayload{
 		ID:              cashback.ID.String(),
 		UserID:          cashback.UserID.String(),
 		PurchaseID:      cashback.PurchaseID.String(),
+		Type:            cashback.Type,
 		Amount:          cashback.Amount,
+		BaseAmount:      cashback.BaseAmount,
+		CampaignAmount:  cashback.CampaignAmount,
 		CashbackPercent: cashback.CashbackPercent,
 		Status:          cashback.Status,
+		WalletAddress:   cashback.WalletAddress,
 		CreatedAt:       cashback.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
+	if cashback.MerchantID != uuid.Nil {
+		output.MerchantID = cashback.MerchantID.String()
+	}
+	if cashback.CampaignID != nil {
+		output.CampaignID = cashback.CampaignID.String()
+	}
+	return output
 }
@@ -5,43 +5,69 @@ import (
 	"net/http"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	findpurchaseuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/findpurchase"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
 	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 const Path = "/cashback/calculate"
 
+var Operation = openapi.Operation{
+	ID:        "calculateCashback",
+	Method:    http.MethodPost,
+	Path:      Path,
+	Summary:   "Calculate cashback for a purchase",
+	Tag:       "cashback",
+	Request:   InputPayload{},
+	Responses: map[int]any{http.StatusCreated: OutputPayload{}},
+}
+
 type Handler struct {
-	useCase calculatecashback.UseCase
+	useCase   calculatecashback.UseCase
+	purchases findpurchaseuc.UseCase
 }
 
-func NewHandler(useCase calculatecashback.UseCase) Handler {
+func NewHandler(useCase calculatecashback.UseCase, purchases findpurchaseuc.UseCase) Handler {
 	return Handler{
-		useCase: useCase,
+		useCase:   useCase,
+		purchases: purchases,
 	}
 }
 
 func RegisterEndpoint(r chi.Router, h Handler) {
-	r.Post(Path, h.Handle)
+	r.With(auth.Require(auth.RolePartner, auth.RoleAdmin)).Post(Path, h.Handle)
 }
 
 func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var payload InputPayload
 	if err := httpjson.ReadJSON(r, &payload); err != nil {
-		errorhandler.RenderWithCode(w, http.StatusBadRequest, "invalid payload")
+		errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
 		return
 	}
 
 	if err := payload.Validate(); err != nil {
-		errorhandler.Render(w, err)
+		errorhandler.Render(w, r, err)
 		return
 	}
 
 	purchaseID, err := uuid.Parse(payload.PurchaseID)
 	if err != nil {
-		errorhandler.Render(w, calculatecashback.ErrInvalidPurchaseID)
+		errorhandler.Render(w, r, errorhandler.Invalid("purchase_id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	// Partners calculate cashback for purchases made at their own merchant only
+	purchase, err := h.purchases.Execute(r.Context(), purchaseID)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+	if !auth.ActsForMerchant(r.Context(), purchase.MerchantID.String()) {
+		errorhandler.Render(w, r, errorhandler.ErrForbidden)
 		return
 	}
 
@@ -52,7 +78,7 @@ func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		errorhandler.Render(w, err)
+		errorhandler.Render(w, r, err)
 		return
 	}
 
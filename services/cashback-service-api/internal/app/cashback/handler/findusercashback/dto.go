@@ -1,49 +1,94 @@
 package findusercashback
 
 import (
+	"net/http"
+
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/findusercashback"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
+	"github.com/google/uuid"
 )
 
 type (
 	CashbackItem struct {
 		ID              string  `json:"id"`
 		PurchaseID      string  `json:"purchase_id"`
+		MerchantID      string  `json:"merchant_id,omitempty"`
+		Type            string  `json:"type"`
 		Amount          float64 `json:"amount"`
+		BaseAmount      float64 `json:"base_amount"`
+		CampaignID      string  `json:"campaign_id,omitempty"`
+		CampaignAmount  float64 `json:"campaign_amount"`
 		CashbackPercent float64 `json:"cashback_percent"`
 		Status          string  `json:"status"`
+		WalletAddress   string  `json:"wallet_address,omitempty"`
 		CreatedAt       string  `json:"created_at"`
 	}
 
 	OutputPayload struct {
-		UserID         string         `json:"user_id"`
-		Cashbacks      []CashbackItem `json:"cashbacks"`
-		TotalMinted    float64        `json:"total_minted"`
-		TotalCashbacks int            `json:"total_cashbacks"`
+		UserID         string            `json:"user_id"`
+		Cashbacks      []CashbackItem    `json:"cashbacks"`
+		Page           httpjson.PageInfo `json:"page"`
+		TotalMinted    float64           `json:"total_minted"`
+		TotalCashbacks int               `json:"total_cashbacks"`
 	}
 )
 
-func ToOutputPayload(summary findusercashback.UserCashbackSummary) OutputPayload {
-	cashbacks := make([]CashbackItem, len(summary.Cashbacks))
-	for i, c := range summary.Cashbacks {
-		cashbacks[i] = toCashbackItem(c)
+// ParseFilter reads the status, merchant_id, from and to query parameters.
+func ParseFilter(r *http.Request) (domain.Filter, error) {
+	filter := domain.Filter{Statuses: httpjson.ParseListParam(r, "status")}
+
+	if v := r.URL.Query().Get("merchant_id"); v != "" {
+		merchantID, err := uuid.Parse(v)
+		if err != nil {
+			return domain.Filter{}, errorhandler.Invalid("merchant_id", domain.ErrInvalidMerchantID)
+		}
+		filter.MerchantID = merchantID
+	}
+
+	var err error
+	if filter.From, err = httpjson.ParseTimeParam(r, "from"); err != nil {
+		return domain.Filter{}, err
+	}
+	if filter.To, err = httpjson.ParseTimeParam(r, "to"); err != nil {
+		return domain.Filter{}, err
 	}
 
+	return filter, nil
+}
+
+func ToOutputPayload(summary findusercashback.UserCashbackSummary) OutputPayload {
+	page := pagination.Map(summary.Cashbacks, toCashbackItem)
+
 	return OutputPayload{
 		UserID:         summary.UserID.String(),
-		Cashbacks:      cashbacks,
+		Cashbacks:      page.Items,
+		Page:           httpjson.NewPageInfo(page.Next),
 		TotalMinted:    summary.TotalMinted,
 		TotalCashbacks: summary.TotalCashbacks,
 	}
 }
 
 func toCashbackItem(c domain.Cashback) CashbackItem {
-	return CashbackItem{
+	item := CashbackItem{
 		ID:              c.ID.String(),
 		PurchaseID:      c.PurchaseID.String(),
+		Type:            c.Type,
 		Amount:          c.Amount,
+		BaseAmount:      c.BaseAmount,
+		CampaignAmount:  c.CampaignAmount,
 		CashbackPercent: c.CashbackPercent,
 		Status:          c.Status,
+		WalletAddress:   c.WalletAddress,
 		CreatedAt:       c.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
+	if c.MerchantID != uuid.Nil {
+		item.MerchantID = c.MerchantID.String()
+	}
+	if c.CampaignID != nil {
+		item.CampaignID = c.CampaignID.String()
+	}
+	return item
 }
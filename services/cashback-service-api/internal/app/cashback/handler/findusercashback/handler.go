@@ -4,13 +4,31 @@ import (
 	"net/http"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/findusercashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 const Path = "/users/{user_id}/cashback"
 
+var Operation = openapi.Operation{
+	ID:      "listUserCashback",
+	Method:  http.MethodGet,
+	Path:    Path,
+	Summary: "List a user's cashback",
+	Tag:     "cashback",
+	Query: httpjson.PageParameters(
+		openapi.Query("status", "Only these statuses: pending, approved, minted, failed, expired", "string", ""),
+		openapi.Query("merchant_id", "Only this merchant", "string", "uuid"),
+		openapi.Query("from", "Created at or after", "string", "date-time"),
+		openapi.Query("to", "Created before", "string", "date-time"),
+	),
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
 type Handler struct {
 	useCase findusercashback.UseCase
 }
@@ -22,20 +40,32 @@ func NewHandler(useCase findusercashback.UseCase) Handler {
 }
 
 func RegisterEndpoint(r chi.Router, h Handler) {
-	r.Get(Path, h.Handle)
+	r.With(auth.RequireOwner("user_id", auth.RoleAdmin)).Get(Path, h.Handle)
 }
 
 func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	userIDStr := chi.URLParam(r, "user_id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		http.Error(w, "invalid user id", http.StatusBadRequest)
+		errorhandler.Render(w, r, errorhandler.Invalid("user_id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	filter, err := ParseFilter(r)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	page, err := httpjson.ParsePageRequest(r)
+	if err != nil {
+		errorhandler.Render(w, r, err)
 		return
 	}
 
-	summary, err := h.useCase.Execute(r.Context(), userID)
+	summary, err := h.useCase.Execute(r.Context(), userID, filter, page)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		errorhandler.Render(w, r, err)
 		return
 	}
 
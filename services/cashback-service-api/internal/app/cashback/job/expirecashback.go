@@ -0,0 +1,76 @@
+// Package job contains the scheduled background jobs of the cashback context.
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/expirecashback"
+	"go.uber.org/fx"
+)
+
+// ExpireCashbackJob periodically expires cashback that stayed unminted.
+type ExpireCashbackJob struct {
+	useCase  expirecashback.UseCase
+	interval time.Duration
+	log      *slog.Logger
+	done     chan struct{}
+}
+
+func NewExpireCashbackJob(useCase expirecashback.UseCase, interval time.Duration, log *slog.Logger) *ExpireCashbackJob {
+	return &ExpireCashbackJob{
+		useCase:  useCase,
+		interval: interval,
+		log:      log,
+		done:     make(chan struct{}),
+	}
+}
+
+func (j *ExpireCashbackJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *ExpireCashbackJob) Stop() {
+	close(j.done)
+}
+
+func (j *ExpireCashbackJob) run(ctx context.Context) {
+	result, err := j.useCase.Execute(ctx)
+	if err != nil {
+		j.log.ErrorContext(ctx, "cashback expiry failed", "error", err)
+	}
+	if result.Warned > 0 || result.Expired > 0 {
+		j.log.InfoContext(ctx, "cashback expiry run", "warned", result.Warned, "expired", result.Expired)
+	}
+}
+
+func StartExpireCashbackJob(lc fx.Lifecycle, job *ExpireCashbackJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go job.Start(ctx)
+			job.log.Info("cashback expiry job started")
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			job.Stop()
+			job.log.Info("cashback expiry job stopped")
+			return nil
+		},
+	})
+}
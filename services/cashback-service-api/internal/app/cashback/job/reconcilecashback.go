@@ -0,0 +1,133 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/reconcilecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"go.uber.org/fx"
+)
+
+// ReconcileCashbackJob periodically reconciles the ledger with mint requests
+// and on-chain balances, writing a report file for every run.
+type ReconcileCashbackJob struct {
+	useCase   reconcilecashback.UseCase
+	interval  time.Duration
+	reportDir string
+	format    string
+	log       *slog.Logger
+	done      chan struct{}
+}
+
+func NewReconcileCashbackJob(useCase reconcilecashback.UseCase, cfg config.Reconciliation, log *slog.Logger) *ReconcileCashbackJob {
+	return &ReconcileCashbackJob{
+		useCase:   useCase,
+		interval:  cfg.Interval,
+		reportDir: cfg.ReportDir,
+		format:    cfg.ReportFormat,
+		log:       log,
+		done:      make(chan struct{}),
+	}
+}
+
+func (j *ReconcileCashbackJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *ReconcileCashbackJob) Stop() {
+	close(j.done)
+}
+
+func (j *ReconcileCashbackJob) run(ctx context.Context) {
+	report, err := j.useCase.Execute(ctx)
+	if err != nil {
+		j.log.ErrorContext(ctx, "cashback reconciliation failed", "error", err)
+		return
+	}
+
+	path, err := j.writeReport(report)
+	if err != nil {
+		j.log.ErrorContext(ctx, "failed to write reconciliation report", "run_id", report.RunID, "error", err)
+	}
+
+	counts := report.Count()
+	j.log.InfoContext(ctx, "cashback reconciliation run",
+		"run_id", report.RunID,
+		"cashbacks", report.Cashbacks,
+		"mints", report.Mints,
+		"wallets", len(report.Wallets),
+		"discrepancies", len(report.Discrepancies),
+		reconcilecashback.KindApprovedNotMinted, counts[reconcilecashback.KindApprovedNotMinted],
+		reconcilecashback.KindMintNotRecorded, counts[reconcilecashback.KindMintNotRecorded],
+		reconcilecashback.KindBalanceMismatch, counts[reconcilecashback.KindBalanceMismatch],
+		"report", path,
+	)
+}
+
+// writeReport writes report to a new file in the report directory and
+// returns its path.
+func (j *ReconcileCashbackJob) writeReport(report reconcilecashback.Report) (string, error) {
+	if err := os.MkdirAll(j.reportDir, 0o755); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("reconciliation-%s.%s", report.StartedAt.Format("20060102T150405Z"), j.format)
+	path := filepath.Join(j.reportDir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	if j.format == config.ReportFormatCSV {
+		err = report.WriteCSV(f)
+	} else {
+		err = report.WriteJSON(f)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// StartReconcileCashbackJob starts the job unless reconciliation is disabled.
+func StartReconcileCashbackJob(lc fx.Lifecycle, job *ReconcileCashbackJob, cfg config.Reconciliation) {
+	if !cfg.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go job.Start(ctx)
+			job.log.Info("cashback reconciliation job started", "interval", job.interval.String())
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			job.Stop()
+			job.log.Info("cashback reconciliation job stopped")
+			return nil
+		},
+	})
+}
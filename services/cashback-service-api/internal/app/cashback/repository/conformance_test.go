@@ -0,0 +1,352 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/expirecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/reconcilecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/settlecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database/databasetest"
+	"github.com/google/uuid"
+)
+
+// The same suite runs against the in-memory repository and the Postgres one,
+// so the two cannot drift apart. The Postgres run needs TEST_DATABASE_URL and
+// is skipped without it.
+
+type cashbackRepository interface {
+	calculatecashback.Repository
+	expirecashback.Repository
+	settlecashback.Repository
+	reconcilecashback.Repository
+}
+
+func TestMemory(t *testing.T) {
+	testRepository(t, func(t *testing.T) cashbackRepository {
+		return repository.NewMemory()
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	testRepository(t, func(t *testing.T) cashbackRepository {
+		return repository.New(databasetest.New(t))
+	})
+}
+
+func testRepository(t *testing.T, newRepo func(t *testing.T) cashbackRepository) {
+	ctx := context.Background()
+
+	t.Run("create fills in defaults", func(t *testing.T) {
+		repo := newRepo(t)
+
+		campaignID := uuid.New()
+		cashback := newCashback(uuid.New(), uuid.New(), "")
+		cashback.CampaignID = &campaignID
+		cashback.CampaignAmount = 1.5
+		cashback.WalletAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+		created, err := repo.Create(ctx, cashback)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.ID == uuid.Nil || created.Type != domain.TypePurchase ||
+			created.Status != domain.StatusPending || created.CreatedAt.IsZero() {
+			t.Fatalf("created cashback has ID %s, type %q, status %q, created at %s",
+				created.ID, created.Type, created.Status, created.CreatedAt)
+		}
+
+		found := mustFind(t, repo, cashback.PurchaseID)
+		if found.ID != created.ID || found.UserID != cashback.UserID || found.MerchantID != cashback.MerchantID ||
+			found.Amount != cashback.Amount || found.BaseAmount != cashback.BaseAmount ||
+			found.CampaignID == nil || *found.CampaignID != campaignID || found.CampaignAmount != cashback.CampaignAmount ||
+			found.CashbackPercent != cashback.CashbackPercent || found.WalletAddress != cashback.WalletAddress {
+			t.Fatalf("found %+v, want %+v", found, created)
+		}
+	})
+
+	t.Run("missing cashback is not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindByPurchaseID(ctx, uuid.New())
+		assertError(t, err, domain.ErrCashbackNotFound)
+	})
+
+	t.Run("only purchase cashback is found by purchase", func(t *testing.T) {
+		repo := newRepo(t)
+
+		userID, purchaseID := uuid.New(), uuid.New()
+		if _, err := repo.Create(ctx, newCashback(userID, purchaseID, domain.TypeReferral)); err != nil {
+			t.Fatal(err)
+		}
+		_, err := repo.FindByPurchaseID(ctx, purchaseID)
+		assertError(t, err, domain.ErrCashbackNotFound)
+
+		created, err := repo.Create(ctx, newCashback(userID, purchaseID, domain.TypePurchase))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found := mustFind(t, repo, purchaseID); found.ID != created.ID {
+			t.Fatalf("found cashback %s, want %s", found.ID, created.ID)
+		}
+	})
+
+	t.Run("one cashback per user, purchase and type", func(t *testing.T) {
+		repo := newRepo(t)
+
+		userID, purchaseID := uuid.New(), uuid.New()
+		first, err := repo.Create(ctx, newCashback(userID, purchaseID, domain.TypePurchase))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = repo.Create(ctx, newCashback(userID, purchaseID, domain.TypePurchase))
+		assertError(t, err, domain.ErrDuplicateCashback)
+
+		sameID := newCashback(uuid.New(), uuid.New(), domain.TypePurchase)
+		sameID.ID = first.ID
+		_, err = repo.Create(ctx, sameID)
+		assertError(t, err, domain.ErrDuplicateCashback)
+
+		// The referral bonus and another user's cashback are distinct.
+		if _, err := repo.Create(ctx, newCashback(userID, purchaseID, domain.TypeReferral)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Create(ctx, newCashback(uuid.New(), purchaseID, domain.TypePurchase)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("create all stores every cashback or none", func(t *testing.T) {
+		repo := newRepo(t)
+
+		purchaseID := uuid.New()
+		referrer, referee := uuid.New(), uuid.New()
+		created, err := repo.CreateAll(ctx, []domain.Cashback{
+			newCashback(referrer, purchaseID, domain.TypeReferral),
+			newCashback(referee, purchaseID, domain.TypeReferral),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(created) != 2 || created[0].ID == uuid.Nil || created[0].UserID != referrer || created[1].UserID != referee {
+			t.Fatalf("created %+v", created)
+		}
+
+		// The second entry repeats the first, so neither is stored.
+		other := uuid.New()
+		_, err = repo.CreateAll(ctx, []domain.Cashback{
+			newCashback(other, purchaseID, domain.TypeReferral),
+			newCashback(referee, purchaseID, domain.TypeReferral),
+		})
+		assertError(t, err, domain.ErrDuplicateCashback)
+		if _, err := repo.Create(ctx, newCashback(other, purchaseID, domain.TypeReferral)); err != nil {
+			t.Fatalf("a refused batch stored its first entry: %v", err)
+		}
+	})
+
+	t.Run("shared purchases resolve to the lowest ID", func(t *testing.T) {
+		repo := newRepo(t)
+
+		purchaseID := uuid.New()
+		var want uuid.UUID
+		for range 3 {
+			created, err := repo.Create(ctx, newCashback(uuid.New(), purchaseID, domain.TypePurchase))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want == uuid.Nil || created.ID.String() < want.String() {
+				want = created.ID
+			}
+		}
+		if found := mustFind(t, repo, purchaseID); found.ID != want {
+			t.Fatalf("found cashback %s, want %s", found.ID, want)
+		}
+	})
+
+	t.Run("concurrent creates for a purchase record it once", func(t *testing.T) {
+		repo := newRepo(t)
+
+		userID, purchaseID := uuid.New(), uuid.New()
+		const n = 10
+		errs := make(chan error, n)
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.Create(ctx, newCashback(userID, purchaseID, domain.TypePurchase))
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		created := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				created++
+			case !errors.Is(err, domain.ErrDuplicateCashback):
+				t.Fatal(err)
+			}
+		}
+		if created != 1 {
+			t.Fatalf("created %d cashback, want 1", created)
+		}
+	})
+
+	t.Run("only unminted cashback is found to expire", func(t *testing.T) {
+		repo := newRepo(t)
+
+		old := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+		byStatus := make(map[string]domain.Cashback)
+		for i, status := range domain.Statuses {
+			cashback := newCashback(uuid.New(), uuid.New(), domain.TypePurchase)
+			cashback.Status = status
+			cashback.CreatedAt = old.Add(time.Duration(i) * time.Minute)
+			byStatus[status] = mustCreate(t, repo, cashback)
+		}
+		recent := newCashback(uuid.New(), uuid.New(), domain.TypePurchase)
+		recent.Status = domain.StatusPending
+		mustCreate(t, repo, recent)
+
+		before := time.Now().UTC().Add(-time.Hour)
+		found, err := repo.FindExpirableBefore(ctx, before, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, found, byStatus[domain.StatusPending].ID, byStatus[domain.StatusApproved].ID, byStatus[domain.StatusFailed].ID)
+
+		if ok, err := repo.MarkExpiryWarned(ctx, byStatus[domain.StatusPending].ID); err != nil || !ok {
+			t.Fatalf("MarkExpiryWarned = %t, %v", ok, err)
+		}
+		if ok, err := repo.MarkExpiryWarned(ctx, byStatus[domain.StatusPending].ID); err != nil || ok {
+			t.Fatalf("second MarkExpiryWarned = %t, %v", ok, err)
+		}
+		unwarned, err := repo.FindUnwarnedBefore(ctx, before, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, unwarned, byStatus[domain.StatusApproved].ID, byStatus[domain.StatusFailed].ID)
+
+		limited, err := repo.FindExpirableBefore(ctx, before, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, limited, byStatus[domain.StatusPending].ID)
+	})
+
+	t.Run("reconciliation lists cashback approved to a wallet", func(t *testing.T) {
+		repo := newRepo(t)
+
+		held := mustCreate(t, repo, newCashback(uuid.New(), uuid.New(), domain.TypePurchase))
+		approved := newCashback(uuid.New(), uuid.New(), domain.TypePurchase)
+		approved.Status = domain.StatusMinted
+		approved.WalletAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+		approved = mustCreate(t, repo, approved)
+
+		listed, err := repo.ListForReconciliation(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listed) != 1 || listed[0].ID != approved.ID || listed[0].WalletAddress != approved.WalletAddress {
+			t.Fatalf("listed %+v, want only %s and not the held %s", listed, approved.ID, held.ID)
+		}
+	})
+
+	t.Run("status transitions only apply from their source statuses", func(t *testing.T) {
+		repo := newRepo(t)
+
+		for _, tc := range []struct {
+			name       string
+			transition func(context.Context, uuid.UUID) (bool, error)
+			from       []string
+			to         string
+		}{
+			{"expire", repo.Expire, domain.ExpirableStatuses, domain.StatusExpired},
+			{"mark minted", repo.MarkMinted, domain.MintableStatuses, domain.StatusMinted},
+			{"mark mint failed", repo.MarkMintFailed, []string{domain.StatusApproved}, domain.StatusFailed},
+		} {
+			for _, status := range domain.Statuses {
+				cashback := newCashback(uuid.New(), uuid.New(), domain.TypePurchase)
+				cashback.Status = status
+				created := mustCreate(t, repo, cashback)
+
+				ok, err := tc.transition(ctx, created.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, wantStatus := false, status
+				if slices.Contains(tc.from, status) {
+					want, wantStatus = true, tc.to
+				}
+				found, err := repo.FindByID(ctx, created.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok != want || found.Status != wantStatus {
+					t.Errorf("%s from %s = %t, status %q; want %t, status %q", tc.name, status, ok, found.Status, want, wantStatus)
+				}
+			}
+
+			if ok, err := tc.transition(ctx, uuid.New()); err != nil || ok {
+				t.Errorf("%s of missing cashback = %t, %v", tc.name, ok, err)
+			}
+		}
+	})
+}
+
+func newCashback(userID, purchaseID uuid.UUID, cashbackType string) domain.Cashback {
+	return domain.Cashback{
+		UserID:          userID,
+		PurchaseID:      purchaseID,
+		MerchantID:      uuid.New(),
+		Type:            cashbackType,
+		Amount:          12.5,
+		BaseAmount:      11,
+		CashbackPercent: 5.5,
+	}
+}
+
+func mustCreate(t *testing.T, repo cashbackRepository, cashback domain.Cashback) domain.Cashback {
+	t.Helper()
+	created, err := repo.Create(context.Background(), cashback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func mustFind(t *testing.T, repo cashbackRepository, purchaseID uuid.UUID) domain.Cashback {
+	t.Helper()
+	cashback, err := repo.FindByPurchaseID(context.Background(), purchaseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cashback
+}
+
+func assertIDs(t *testing.T, cashbacks []domain.Cashback, want ...uuid.UUID) {
+	t.Helper()
+	got := make([]uuid.UUID, len(cashbacks))
+	for i, c := range cashbacks {
+		got[i] = c.ID
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("found cashback %v, want %v", got, want)
+	}
+}
+
+func assertError(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/google/uuid"
+)
+
+// Memory keeps cashback in memory, for tests of the usecases that create, look
+// up, expire, settle and reconcile cashback without a database. Like cashback_ledger, it fills in the
+// ID, type, status and timestamps left empty and refuses a second cashback
+// with the same ID, or the same user, purchase and type.
+type Memory struct {
+	mu       sync.RWMutex
+	cashback map[uuid.UUID]domain.Cashback
+}
+
+// NewMemory creates an empty in-memory cashback repository.
+func NewMemory() *Memory {
+	return &Memory{cashback: make(map[uuid.UUID]domain.Cashback)}
+}
+
+func (m *Memory) Create(_ context.Context, cashback domain.Cashback) (domain.Cashback, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	created, err := m.prepare(cashback, nil)
+	if err != nil {
+		return domain.Cashback{}, err
+	}
+	m.cashback[created.ID] = cloneCashback(created)
+	return created, nil
+}
+
+// CreateAll records every cashback or, when one is refused, none of them.
+func (m *Memory) CreateAll(_ context.Context, cashback []domain.Cashback) ([]domain.Cashback, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	created := make([]domain.Cashback, 0, len(cashback))
+	for _, c := range cashback {
+		c, err := m.prepare(c, created)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, c)
+	}
+	for _, c := range created {
+		m.cashback[c.ID] = cloneCashback(c)
+	}
+	return created, nil
+}
+
+// prepare fills in the defaults of cashback and checks it against the stored
+// cashback and pending, which are about to be stored with it.
+func (m *Memory) prepare(cashback domain.Cashback, pending []domain.Cashback) (domain.Cashback, error) {
+	if cashback.ID == uuid.Nil {
+		cashback.ID = uuid.New()
+	}
+	if cashback.Type == "" {
+		cashback.Type = domain.TypePurchase
+	}
+	if cashback.Status == "" {
+		cashback.Status = domain.StatusPending
+	}
+
+	for _, other := range m.cashback {
+		if duplicates(other, cashback) {
+			return domain.Cashback{}, domain.ErrDuplicateCashback
+		}
+	}
+	for _, other := range pending {
+		if duplicates(other, cashback) {
+			return domain.Cashback{}, domain.ErrDuplicateCashback
+		}
+	}
+
+	now := time.Now()
+	if cashback.CreatedAt.IsZero() {
+		cashback.CreatedAt = now
+	}
+	if cashback.UpdatedAt.IsZero() {
+		cashback.UpdatedAt = now
+	}
+	return cloneCashback(cashback), nil
+}
+
+func duplicates(a, b domain.Cashback) bool {
+	return a.ID == b.ID || (a.UserID == b.UserID && a.PurchaseID == b.PurchaseID && a.Type == b.Type)
+}
+
+// FindByPurchaseID returns the purchase cashback of purchaseID. Should several
+// users share the purchase, it is the one with the lowest ID, as in Postgres.
+func (m *Memory) FindByPurchaseID(_ context.Context, purchaseID uuid.UUID) (domain.Cashback, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		found domain.Cashback
+		ok    bool
+	)
+	for _, cashback := range m.cashback {
+		if cashback.PurchaseID != purchaseID || cashback.Type != domain.TypePurchase {
+			continue
+		}
+		if !ok || bytes.Compare(cashback.ID[:], found.ID[:]) < 0 {
+			found, ok = cashback, true
+		}
+	}
+	if !ok {
+		return domain.Cashback{}, domain.ErrCashbackNotFound
+	}
+	return cloneCashback(found), nil
+}
+
+func (m *Memory) FindByID(_ context.Context, id uuid.UUID) (domain.Cashback, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cashback, ok := m.cashback[id]
+	if !ok {
+		return domain.Cashback{}, domain.ErrCashbackNotFound
+	}
+	return cloneCashback(cashback), nil
+}
+
+// FindExpirableBefore returns unminted cashback created before the given
+// time, oldest first.
+func (m *Memory) FindExpirableBefore(_ context.Context, before time.Time, limit int) ([]domain.Cashback, error) {
+	return m.findExpirable(before, limit, func(domain.Cashback) bool { return true }), nil
+}
+
+// FindUnwarnedBefore returns expirable cashback created before the given time
+// whose owner has not been warned about its expiry yet, oldest first.
+func (m *Memory) FindUnwarnedBefore(_ context.Context, before time.Time, limit int) ([]domain.Cashback, error) {
+	return m.findExpirable(before, limit, func(c domain.Cashback) bool { return c.ExpiryWarnedAt == nil }), nil
+}
+
+func (m *Memory) findExpirable(before time.Time, limit int, match func(domain.Cashback) bool) []domain.Cashback {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var found []domain.Cashback
+	for _, cashback := range m.cashback {
+		if cashback.IsExpirable() && cashback.CreatedAt.Before(before) && match(cashback) {
+			found = append(found, cloneCashback(cashback))
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].CreatedAt.Before(found[j].CreatedAt) })
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+// ListForReconciliation returns every cashback that was approved to a wallet,
+// whatever its status since.
+func (m *Memory) ListForReconciliation(_ context.Context) ([]domain.Cashback, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var found []domain.Cashback
+	for _, cashback := range m.cashback {
+		if cashback.WalletAddress != "" {
+			found = append(found, cloneCashback(cashback))
+		}
+	}
+	return found, nil
+}
+
+// Expire moves held, approved or failed cashback to expired status.
+func (m *Memory) Expire(_ context.Context, id uuid.UUID) (bool, error) {
+	return m.transition(id, domain.ExpirableStatuses, domain.StatusExpired), nil
+}
+
+// MarkMinted moves approved or failed cashback to minted status.
+func (m *Memory) MarkMinted(_ context.Context, id uuid.UUID) (bool, error) {
+	return m.transition(id, domain.MintableStatuses, domain.StatusMinted), nil
+}
+
+// MarkMintFailed moves approved cashback to failed status.
+func (m *Memory) MarkMintFailed(_ context.Context, id uuid.UUID) (bool, error) {
+	return m.transition(id, []string{domain.StatusApproved}, domain.StatusFailed), nil
+}
+
+// MarkExpiryWarned records that the owner was warned about the upcoming expiry.
+func (m *Memory) MarkExpiryWarned(_ context.Context, id uuid.UUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cashback, ok := m.cashback[id]
+	if !ok || cashback.ExpiryWarnedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	cashback.ExpiryWarnedAt = &now
+	m.cashback[id] = cashback
+	return true, nil
+}
+
+func (m *Memory) transition(id uuid.UUID, from []string, to string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cashback, ok := m.cashback[id]
+	if !ok || !slices.Contains(from, cashback.Status) {
+		return false
+	}
+	cashback.Status = to
+	cashback.UpdatedAt = time.Now()
+	m.cashback[id] = cashback
+	return true
+}
+
+func cloneCashback(cashback domain.Cashback) domain.Cashback {
+	if cashback.CampaignID != nil {
+		campaignID := *cashback.CampaignID
+		cashback.CampaignID = &campaignID
+	}
+	if cashback.ExpiryWarnedAt != nil {
+		warnedAt := *cashback.ExpiryWarnedAt
+		cashback.ExpiryWarnedAt = &warnedAt
+	}
+	return cashback
+}
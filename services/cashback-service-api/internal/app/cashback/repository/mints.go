@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// mintStatusCompleted is mint-consumer's status of a confirmed mint.
+const mintStatusCompleted = "completed"
+
+// ErrMintDatabaseNotConfigured is returned when mints are read without
+// RECONCILIATION_MINT_DATABASE_URL.
+var ErrMintDatabaseNotConfigured = errors.New("mint database not configured")
+
+// MintRepository reads the mint requests of mint-consumer, from its own
+// database. Only reconciliation uses it, and only reads.
+type MintRepository struct {
+	db *gorm.DB
+}
+
+// mintRequestModel maps the columns of mint-consumer's mint_requests table
+// that reconciliation needs.
+type mintRequestModel struct {
+	ID            uuid.UUID
+	CashbackID    uuid.UUID
+	UserID        uuid.UUID
+	WalletAddress string
+	TokenAmount   string
+	CompletedAt   *time.Time
+}
+
+func (mintRequestModel) TableName() string {
+	return "mint_requests"
+}
+
+// NewMintRepository returns a repository reading from db, which may be nil
+// when no mint database is configured.
+func NewMintRepository(db *gorm.DB) MintRepository {
+	return MintRepository{db: db}
+}
+
+// ListCompletedMints returns every mint request mint-consumer completed.
+func (r MintRepository) ListCompletedMints(ctx context.Context) ([]domain.Mint, error) {
+	if r.db == nil {
+		return nil, ErrMintDatabaseNotConfigured
+	}
+
+	var result []domain.Mint
+	var batch []mintRequestModel
+
+	err := r.db.WithContext(ctx).
+		Where("status = ?", mintStatusCompleted).
+		FindInBatches(&batch, reconciliationBatchSize, func(*gorm.DB, int) error {
+			for _, m := range batch {
+				result = append(result, domain.Mint{
+					ID:            m.ID,
+					CashbackID:    m.CashbackID,
+					UserID:        m.UserID,
+					WalletAddress: m.WalletAddress,
+					TokenAmount:   m.TokenAmount,
+					CompletedAt:   m.CompletedAt,
+				})
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
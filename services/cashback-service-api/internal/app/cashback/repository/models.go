@@ -8,13 +8,20 @@ import (
 )
 
 type cashbackModel struct {
-	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID          uuid.UUID `gorm:"type:uuid;not null;index"`
-	PurchaseID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
-	Amount          float64   `gorm:"not null"`
-	CashbackPercent float64   `gorm:"not null"`
-	Status          string    `gorm:"not null;default:'pending';index"`
-	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_cashback_ledger_purchase_user_type;index:idx_cashback_ledger_user_created,priority:1"`
+	PurchaseID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_cashback_ledger_purchase_user_type"`
+	MerchantID      *uuid.UUID `gorm:"type:uuid;index"`
+	Type            string     `gorm:"type:varchar(20);not null;default:'purchase';uniqueIndex:idx_cashback_ledger_purchase_user_type"`
+	Amount          float64    `gorm:"not null"`
+	BaseAmount      float64    `gorm:"not null"`
+	CampaignID      *uuid.UUID `gorm:"type:uuid;index"`
+	CampaignAmount  float64    `gorm:"not null;default:0"`
+	CashbackPercent float64    `gorm:"not null"`
+	Status          string     `gorm:"not null;default:'pending';index"`
+	WalletAddress   string     `gorm:"type:varchar(42)"`
+	ExpiryWarnedAt  *time.Time
+	CreatedAt       time.Time `gorm:"autoCreateTime;index:idx_cashback_ledger_user_created,priority:2"`
 	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
 }
 
@@ -27,9 +34,16 @@ func (m cashbackModel) toDomain() domain.Cashback {
 		ID:              m.ID,
 		UserID:          m.UserID,
 		PurchaseID:      m.PurchaseID,
+		MerchantID:      uuidOrNil(m.MerchantID),
+		Type:            m.Type,
 		Amount:          m.Amount,
+		BaseAmount:      m.BaseAmount,
+		CampaignID:      m.CampaignID,
+		CampaignAmount:  m.CampaignAmount,
 		CashbackPercent: m.CashbackPercent,
 		Status:          m.Status,
+		WalletAddress:   m.WalletAddress,
+		ExpiryWarnedAt:  m.ExpiryWarnedAt,
 		CreatedAt:       m.CreatedAt,
 		UpdatedAt:       m.UpdatedAt,
 	}
@@ -40,10 +54,31 @@ func fromDomain(cashback domain.Cashback) cashbackModel {
 		ID:              cashback.ID,
 		UserID:          cashback.UserID,
 		PurchaseID:      cashback.PurchaseID,
+		MerchantID:      nilIfZero(cashback.MerchantID),
+		Type:            cashback.Type,
 		Amount:          cashback.Amount,
+		BaseAmount:      cashback.BaseAmount,
+		CampaignID:      cashback.CampaignID,
+		CampaignAmount:  cashback.CampaignAmount,
 		CashbackPercent: cashback.CashbackPercent,
 		Status:          cashback.Status,
+		WalletAddress:   cashback.WalletAddress,
+		ExpiryWarnedAt:  cashback.ExpiryWarnedAt,
 		CreatedAt:       cashback.CreatedAt,
 		UpdatedAt:       cashback.UpdatedAt,
 	}
 }
+
+func uuidOrNil(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
+
+func nilIfZero(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
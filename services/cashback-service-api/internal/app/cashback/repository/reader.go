@@ -3,12 +3,19 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// reconciliationBatchSize is how many ledger rows ListForReconciliation loads
+// per query.
+const reconciliationBatchSize = 1000
+
 func (r Repository) FindByID(ctx context.Context, id uuid.UUID) (domain.Cashback, error) {
 	var cashback cashbackModel
 
@@ -23,15 +30,14 @@ func (r Repository) FindByID(ctx context.Context, id uuid.UUID) (domain.Cashback
 	return cashback.toDomain(), nil
 }
 
-func (r Repository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Cashback, error) {
+// ListByUserID returns a page of the user's cashback matching filter.
+func (r Repository) ListByUserID(ctx context.Context, userID uuid.UUID, filter domain.Filter, page pagination.Request) (pagination.Page[domain.Cashback], error) {
 	var cashbacks []cashbackModel
 
-	err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
-		Order("created_at DESC").
+	err := database.Paginate(r.filtered(ctx, userID, filter), page).
 		Find(&cashbacks).Error
 	if err != nil {
-		return nil, err
+		return pagination.Page[domain.Cashback]{}, err
 	}
 
 	result := make([]domain.Cashback, len(cashbacks))
@@ -39,14 +45,43 @@ func (r Repository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domai
 		result[i] = c.toDomain()
 	}
 
-	return result, nil
+	return pagination.NewPage(result, page.Limit, func(c domain.Cashback) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	}), nil
+}
+
+// CountByUserID counts the user's cashback matching filter.
+func (r Repository) CountByUserID(ctx context.Context, userID uuid.UUID, filter domain.Filter) (int, error) {
+	var count int64
+	err := r.filtered(ctx, userID, filter).Count(&count).Error
+	return int(count), err
+}
+
+func (r Repository) filtered(ctx context.Context, userID uuid.UUID, filter domain.Filter) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Model(&cashbackModel{}).
+		Where("user_id = ?", userID)
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.MerchantID != uuid.Nil {
+		query = query.Where("merchant_id = ?", filter.MerchantID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	return query
 }
 
 func (r Repository) FindByPurchaseID(ctx context.Context, purchaseID uuid.UUID) (domain.Cashback, error) {
 	var cashback cashbackModel
 
 	err := r.db.WithContext(ctx).
-		Where("purchase_id = ?", purchaseID).
+		Where("purchase_id = ? AND type = ?", purchaseID, domain.TypePurchase).
 		First(&cashback).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -68,3 +103,77 @@ func (r Repository) TotalByUserID(ctx context.Context, userID uuid.UUID) (float6
 
 	return total, err
 }
+
+// FindExpirableBefore returns unminted cashback created before the given
+// time, oldest first.
+func (r Repository) FindExpirableBefore(ctx context.Context, before time.Time, limit int) ([]domain.Cashback, error) {
+	return r.findExpirable(ctx, r.db.Where("created_at < ?", before), limit)
+}
+
+// FindUnwarnedBefore returns expirable cashback created before the given time
+// whose owner has not been warned about its expiry yet, oldest first.
+func (r Repository) FindUnwarnedBefore(ctx context.Context, before time.Time, limit int) ([]domain.Cashback, error) {
+	return r.findExpirable(ctx, r.db.Where("created_at < ? AND expiry_warned_at IS NULL", before), limit)
+}
+
+func (r Repository) findExpirable(ctx context.Context, scope *gorm.DB, limit int) ([]domain.Cashback, error) {
+	var cashbacks []cashbackModel
+
+	err := r.db.WithContext(ctx).
+		Where(scope).
+		Where("status IN ?", domain.ExpirableStatuses).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&cashbacks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Cashback, len(cashbacks))
+	for i, c := range cashbacks {
+		result[i] = c.toDomain()
+	}
+
+	return result, nil
+}
+
+// FindHeldByUserID returns the user's cashback held until a wallet is verified.
+func (r Repository) FindHeldByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Cashback, error) {
+	var cashbacks []cashbackModel
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, domain.StatusPending).
+		Order("created_at ASC").
+		Find(&cashbacks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Cashback, len(cashbacks))
+	for i, c := range cashbacks {
+		result[i] = c.toDomain()
+	}
+
+	return result, nil
+}
+
+// ListForReconciliation returns every cashback that was approved to a wallet,
+// whatever its status since, loading them in batches.
+func (r Repository) ListForReconciliation(ctx context.Context) ([]domain.Cashback, error) {
+	var result []domain.Cashback
+	var batch []cashbackModel
+
+	err := r.db.WithContext(ctx).
+		Where("wallet_address <> ''").
+		FindInBatches(&batch, reconciliationBatchSize, func(*gorm.DB, int) error {
+			for _, c := range batch {
+				result = append(result, c.toDomain())
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -2,21 +2,112 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 func (r Repository) Create(ctx context.Context, cashback domain.Cashback) (domain.Cashback, error) {
 	model := fromDomain(cashback)
 
 	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domain.Cashback{}, domain.ErrDuplicateCashback
+		}
 		return domain.Cashback{}, err
 	}
 
 	return model.toDomain(), nil
 }
 
+// CreateAll records every cashback in a single insert, so either all of them
+// are stored or, when one is refused, none is.
+func (r Repository) CreateAll(ctx context.Context, cashback []domain.Cashback) ([]domain.Cashback, error) {
+	if len(cashback) == 0 {
+		return nil, nil
+	}
+	models := make([]cashbackModel, len(cashback))
+	for i, c := range cashback {
+		models[i] = fromDomain(c)
+	}
+
+	if err := r.db.WithContext(ctx).Create(&models).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, domain.ErrDuplicateCashback
+		}
+		return nil, err
+	}
+
+	created := make([]domain.Cashback, len(models))
+	for i, model := range models {
+		created[i] = model.toDomain()
+	}
+	return created, nil
+}
+
 func (r Repository) Update(ctx context.Context, cashback domain.Cashback) error {
 	model := fromDomain(cashback)
 	return r.db.WithContext(ctx).Save(&model).Error
 }
+
+// Expire moves held, approved or failed cashback to expired status.
+// Returns false if the cashback was minted or expired concurrently.
+func (r Repository) Expire(ctx context.Context, id uuid.UUID) (bool, error) {
+	return r.transition(ctx, id, domain.ExpirableStatuses, domain.StatusExpired)
+}
+
+// MarkMinted moves approved or failed cashback to minted status.
+// Returns false if it is in any other status, including already minted.
+func (r Repository) MarkMinted(ctx context.Context, id uuid.UUID) (bool, error) {
+	return r.transition(ctx, id, domain.MintableStatuses, domain.StatusMinted)
+}
+
+// MarkMintFailed moves approved cashback to failed status.
+// Returns false if it is in any other status.
+func (r Repository) MarkMintFailed(ctx context.Context, id uuid.UUID) (bool, error) {
+	return r.transition(ctx, id, []string{domain.StatusApproved}, domain.StatusFailed)
+}
+
+func (r Repository) transition(ctx context.Context, id uuid.UUID, from []string, to string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&cashbackModel{}).
+		Where("id = ? AND status IN ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkExpiryWarned records that the owner was warned about the upcoming expiry.
+// Returns false if the warning was already recorded.
+func (r Repository) MarkExpiryWarned(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&cashbackModel{}).
+		Where("id = ? AND expiry_warned_at IS NULL", id).
+		Update("expiry_warned_at", time.Now().UTC())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ApproveHeld approves cashback that was held until the user verified a wallet,
+// recording the wallet it will be minted to.
+// Returns false if it was released or expired concurrently.
+func (r Repository) ApproveHeld(ctx context.Context, id uuid.UUID, walletAddress string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&cashbackModel{}).
+		Where("id = ? AND status = ?", id, domain.StatusPending).
+		Updates(map[string]any{
+			"status":         domain.StatusApproved,
+			"wallet_address": walletAddress,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
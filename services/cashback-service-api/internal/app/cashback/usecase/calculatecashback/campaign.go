@@ -0,0 +1,99 @@
+package calculatecashback
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	campaigndomain "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	merchantdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	purchasedomain "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+)
+
+// applyBestCampaign boosts the cashback with the eligible campaign offering
+// the largest bonus. The bonus is reserved from the campaign budget; when a
+// concurrent request exhausts it first, the next best campaign is tried.
+func (u UseCase) applyBestCampaign(
+	ctx context.Context,
+	cashback *domain.Cashback,
+	purchase purchasedomain.Purchase,
+	user userdomain.User,
+	merchant merchantdomain.Merchant,
+) error {
+	campaigns, err := u.campaignRepository.FindRunning(ctx, purchase.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if len(campaigns) == 0 {
+		return nil
+	}
+
+	priorPurchases, err := u.purchaseRepository.CountByUserIDBefore(ctx, user.ID, purchase.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	candidates := eligibleCampaigns(campaigns, campaigndomain.PurchaseContext{
+		MerchantID:     merchant.ID,
+		CategoryCode:   merchant.CategoryCode,
+		UserCreatedAt:  user.CreatedAt,
+		PriorPurchases: priorPurchases,
+		UserTier:       user.Tier,
+		PurchasedAt:    purchase.CreatedAt,
+	}, cashback.BaseAmount)
+
+	for _, c := range candidates {
+		err := u.campaignRepository.ReserveBudget(ctx, c.campaign.ID, c.boost)
+		if errors.Is(err, campaigndomain.ErrBudgetExhausted) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		cashback.ApplyCampaign(c.campaign.ID, c.boost)
+		return nil
+	}
+
+	return nil
+}
+
+// releaseCampaignBudget returns the campaign boost when the cashback could not be persisted.
+func (u UseCase) releaseCampaignBudget(ctx context.Context, cashback domain.Cashback) {
+	if cashback.CampaignID == nil {
+		return
+	}
+	if err := u.campaignRepository.ReleaseBudget(ctx, *cashback.CampaignID, cashback.CampaignAmount); err != nil {
+		u.log.ErrorContext(ctx, "failed to release campaign budget", "campaign_id", *cashback.CampaignID, "error", err)
+	}
+}
+
+type campaignCandidate struct {
+	campaign campaigndomain.Campaign
+	boost    float64
+}
+
+// eligibleCampaigns returns the eligible campaigns sorted by boost, best first.
+func eligibleCampaigns(
+	campaigns []campaigndomain.Campaign,
+	purchase campaigndomain.PurchaseContext,
+	baseAmount float64,
+) []campaignCandidate {
+	candidates := make([]campaignCandidate, 0, len(campaigns))
+	for _, c := range campaigns {
+		if !c.IsEligible(purchase) {
+			continue
+		}
+		if boost := c.Boost(baseAmount); boost > 0 {
+			candidates = append(candidates, campaignCandidate{campaign: c, boost: boost})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].boost > candidates[j].boost
+	})
+
+	return candidates
+}
@@ -0,0 +1,13 @@
+package calculatecashback
+
+import "errors"
+
+var (
+	ErrPurchaseNotFound      = errors.New("purchase not found")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrUserNotActive         = errors.New("user is not active")
+	ErrMerchantNotFound      = errors.New("merchant not found")
+	ErrMerchantNotActive     = errors.New("merchant is not active")
+	ErrCashbackAlreadyExists = errors.New("cashback already exists for this purchase")
+	ErrFailedToPublishEvent  = errors.New("cashback created but event publishing failed")
+)
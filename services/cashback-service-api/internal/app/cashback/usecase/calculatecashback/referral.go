@@ -0,0 +1,137 @@
+package calculatecashback
+
+import (
+	"context"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	purchasedomain "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+)
+
+// ReferralPolicy configures the bonus paid when a referred user's first
+// qualifying purchase earns cashback.
+type ReferralPolicy struct {
+	ReferrerBonus     float64
+	RefereeBonus      float64
+	MinPurchaseAmount float64
+}
+
+// rewardReferral issues the referral bonus ledger entries for the referrer and
+// the referee. Failures are logged and never fail the purchase cashback itself.
+func (u UseCase) rewardReferral(ctx context.Context, referee userdomain.User, purchase purchasedomain.Purchase) {
+	if !referee.IsReferred() || referee.ReferralRewardedAt != nil {
+		return
+	}
+	if purchase.Amount < u.referralPolicy.MinPurchaseAmount {
+		return
+	}
+
+	referrer, err := u.userRepository.FindByID(ctx, *referee.ReferredBy)
+	if err != nil {
+		u.log.WarnContext(ctx, "referral bonus skipped: referrer lookup failed", "user_id", referee.ID, "error", err)
+		return
+	}
+
+	if !referrer.IsActive() {
+		u.log.InfoContext(ctx, "referral bonus skipped: referrer is not active", "user_id", referee.ID, "referrer_id", referrer.ID)
+		return
+	}
+
+	// Wallets may have changed since signup, so re-check for self-referral
+	// against every wallet either user has verified since.
+	referrerWallets, err := u.userRepository.FindWalletsByUserID(ctx, referrer.ID)
+	if err != nil {
+		u.log.WarnContext(ctx, "referral bonus skipped: referrer wallet lookup failed", "user_id", referee.ID, "error", err)
+		return
+	}
+	refereeWallets, err := u.userRepository.FindWalletsByUserID(ctx, referee.ID)
+	if err != nil {
+		u.log.WarnContext(ctx, "referral bonus skipped: wallet lookup failed", "user_id", referee.ID, "error", err)
+		return
+	}
+	if referrer.SharesWalletWith(referrerWallets, referee, refereeWallets) {
+		u.log.WarnContext(ctx, "referral bonus rejected: user shares wallet with referrer", "user_id", referee.ID, "referrer_id", referrer.ID)
+		return
+	}
+
+	bonuses, err := u.buildReferralBonuses(purchase, referrer, referee)
+	if err != nil {
+		u.log.ErrorContext(ctx, "failed to build referral bonus", "user_id", referee.ID, "error", err)
+		return
+	}
+	if len(bonuses) == 0 {
+		return
+	}
+
+	claimed, err := u.userRepository.ClaimReferralReward(ctx, referee.ID)
+	if err != nil {
+		u.log.ErrorContext(ctx, "referral bonus skipped: claiming the reward failed", "user_id", referee.ID, "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// Both bonuses are stored together, so a failure leaves neither and the
+	// claim can be released for the next qualifying purchase to pay them
+	bonuses, err = u.repository.CreateAll(ctx, bonuses)
+	if err != nil {
+		u.log.ErrorContext(ctx, "failed to persist referral bonus", "user_id", referee.ID, "error", err)
+		if err := u.userRepository.ReleaseReferralReward(ctx, referee.ID); err != nil {
+			u.log.ErrorContext(ctx, "failed to release referral reward claim", "user_id", referee.ID, "error", err)
+		}
+		return
+	}
+
+	for _, bonus := range bonuses {
+		u.publishReferralBonus(ctx, bonus)
+	}
+}
+
+// buildReferralBonuses returns the bonus ledger entries of the referrer and the
+// referee, skipping those the policy sets to zero.
+func (u UseCase) buildReferralBonuses(
+	purchase purchasedomain.Purchase,
+	referrer userdomain.User,
+	referee userdomain.User,
+) ([]domain.Cashback, error) {
+	var bonuses []domain.Cashback
+	for _, award := range []struct {
+		user   userdomain.User
+		amount float64
+	}{
+		{referrer, u.referralPolicy.ReferrerBonus},
+		{referee, u.referralPolicy.RefereeBonus},
+	} {
+		if award.amount <= 0 {
+			continue
+		}
+		bonus, err := domain.NewReferralCashback(award.user.ID, purchase.ID, award.amount)
+		if err != nil {
+			return nil, err
+		}
+		if award.user.HasVerifiedWallet() {
+			bonus.Approve(award.user.WalletAddress)
+		}
+		bonuses = append(bonuses, bonus)
+	}
+	return bonuses, nil
+}
+
+func (u UseCase) publishReferralBonus(ctx context.Context, bonus domain.Cashback) {
+	ctx = logger.WithCashbackID(ctx, bonus.ID.String())
+
+	if bonus.Status != domain.StatusApproved {
+		u.log.InfoContext(ctx, "referral bonus held until a wallet is verified", "user_id", bonus.UserID)
+		return
+	}
+
+	event := NewCashbackApprovedEvent(bonus)
+	if err := u.outboxPublisher.Publish(ctx, EventTypeCashbackApproved, event); err != nil {
+		u.log.ErrorContext(ctx, "failed to publish referral bonus", "error", err)
+		return
+	}
+
+	u.log.InfoContext(ctx, "referral bonus approved", "user_id", bonus.UserID, "amount", bonus.Amount)
+}
@@ -3,16 +3,19 @@ package calculatecashback
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
+	"time"
 
+	"github.com/cashback-platform/pkg/logger"
+	campaigndomain "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	merchantdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
 	purchasedomain "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
 	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
 	"github.com/google/uuid"
 )
 
 const (
-	DefaultCashbackPercent    = 5.0 // 5% default cashback
 	EventTypeCashbackApproved = "cashback.approved"
 )
 
@@ -20,17 +23,34 @@ type (
 	// Repository interface for cashback operations
 	Repository interface {
 		Create(ctx context.Context, cashback domain.Cashback) (domain.Cashback, error)
+		CreateAll(ctx context.Context, cashback []domain.Cashback) ([]domain.Cashback, error)
 		FindByPurchaseID(ctx context.Context, purchaseID uuid.UUID) (domain.Cashback, error)
 	}
 
 	// PurchaseRepository interface for purchase operations
 	PurchaseRepository interface {
 		FindByID(ctx context.Context, id uuid.UUID) (purchasedomain.Purchase, error)
+		CountByUserIDBefore(ctx context.Context, userID uuid.UUID, before time.Time) (int, error)
 	}
 
 	// UserRepository interface for user operations
 	UserRepository interface {
 		FindByID(ctx context.Context, id uuid.UUID) (userdomain.User, error)
+		FindWalletsByUserID(ctx context.Context, userID uuid.UUID) ([]userdomain.Wallet, error)
+		ClaimReferralReward(ctx context.Context, userID uuid.UUID) (bool, error)
+		ReleaseReferralReward(ctx context.Context, userID uuid.UUID) error
+	}
+
+	// MerchantRepository interface for merchant operations
+	MerchantRepository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (merchantdomain.Merchant, error)
+	}
+
+	// CampaignRepository interface for campaign lookups and budget reservation
+	CampaignRepository interface {
+		FindRunning(ctx context.Context, at time.Time) ([]campaigndomain.Campaign, error)
+		ReserveBudget(ctx context.Context, id uuid.UUID, amount float64) error
+		ReleaseBudget(ctx context.Context, id uuid.UUID, amount float64) error
 	}
 
 	// OutboxPublisher publishes events to the outbox
@@ -43,7 +63,12 @@ type (
 		repository         Repository
 		purchaseRepository PurchaseRepository
 		userRepository     UserRepository
+		merchantRepository MerchantRepository
+		campaignRepository CampaignRepository
 		outboxPublisher    OutboxPublisher
+		referralPolicy     ReferralPolicy
+		tierMultipliers    userdomain.TierMultipliers
+		log                *slog.Logger
 	}
 
 	// CashbackApprovedEvent represents the event published when cashback is approved
@@ -52,7 +77,13 @@ type (
 		UserID          string  `json:"user_id"`
 		WalletAddress   string  `json:"wallet_address"`
 		PurchaseID      string  `json:"purchase_id"`
+		CashbackType    string  `json:"cashback_type"`
+		MerchantID      string  `json:"merchant_id,omitempty"`
+		FundingAccount  string  `json:"funding_account,omitempty"`
 		Amount          float64 `json:"amount"`
+		BaseAmount      float64 `json:"base_amount"`
+		CampaignID      string  `json:"campaign_id,omitempty"`
+		CampaignAmount  float64 `json:"campaign_amount"`
 		CashbackPercent float64 `json:"cashback_percent"`
 	}
 )
@@ -61,13 +92,23 @@ func New(
 	repository Repository,
 	purchaseRepository PurchaseRepository,
 	userRepository UserRepository,
+	merchantRepository MerchantRepository,
+	campaignRepository CampaignRepository,
 	outboxPublisher OutboxPublisher,
+	referralPolicy ReferralPolicy,
+	tierMultipliers userdomain.TierMultipliers,
+	log *slog.Logger,
 ) UseCase {
 	return UseCase{
 		repository:         repository,
 		purchaseRepository: purchaseRepository,
 		userRepository:     userRepository,
+		merchantRepository: merchantRepository,
+		campaignRepository: campaignRepository,
 		outboxPublisher:    outboxPublisher,
+		referralPolicy:     referralPolicy,
+		tierMultipliers:    tierMultipliers,
+		log:                log,
 	}
 }
 
@@ -75,7 +116,7 @@ func New(
 func (u UseCase) Execute(ctx context.Context, purchaseID uuid.UUID) (domain.Cashback, error) {
 	existingCashback, err := u.repository.FindByPurchaseID(ctx, purchaseID)
 	if err == nil {
-		log.Printf("Cashback already exists for purchase %s", purchaseID)
+		u.log.DebugContext(ctx, "cashback already exists for purchase", "purchase_id", purchaseID)
 		return existingCashback, ErrCashbackAlreadyExists
 	}
 	if !errors.Is(err, domain.ErrCashbackNotFound) {
@@ -93,43 +134,101 @@ func (u UseCase) Execute(ctx context.Context, purchaseID uuid.UUID) (domain.Cash
 		return domain.Cashback{}, ErrUserNotFound
 	}
 
-	// Calculate cashback
+	// Deactivated and erased users no longer earn cashback
+	if !user.IsActive() {
+		return domain.Cashback{}, ErrUserNotActive
+	}
+
+	// The merchant funds the cashback, so its rate is the base rate
+	merchant, err := u.merchantRepository.FindByID(ctx, purchase.MerchantID)
+	if err != nil {
+		if errors.Is(err, merchantdomain.ErrMerchantNotFound) {
+			return domain.Cashback{}, ErrMerchantNotFound
+		}
+		return domain.Cashback{}, err
+	}
+
+	if !merchant.IsActive() {
+		return domain.Cashback{}, ErrMerchantNotActive
+	}
+
+	// Calculate cashback at the merchant's rate, scaled by the user's tier
 	cashback, err := domain.NewCashback(
 		purchase.UserID,
 		purchase.ID,
+		merchant.ID,
 		purchase.Amount,
-		DefaultCashbackPercent,
+		u.tierMultipliers.Rate(user.Tier, merchant.CashbackPercent),
 	)
 	if err != nil {
 		return domain.Cashback{}, err
 	}
 
-	// Approve cashback immediately (business rule: auto-approve)
-	cashback.Approve()
+	// Boost with the best eligible campaign (reserves campaign budget)
+	if err := u.applyBestCampaign(ctx, &cashback, purchase, user, merchant); err != nil {
+		return domain.Cashback{}, err
+	}
+
+	// Approve cashback immediately (business rule: auto-approve) to the user's
+	// current payout wallet, unless the user has no verified wallet: it is then
+	// held as pending until they prove one
+	if user.HasVerifiedWallet() {
+		cashback.Approve(user.WalletAddress)
+	}
 
 	// Persist cashback
-	cashback, err = u.repository.Create(ctx, cashback)
+	created, err := u.repository.Create(ctx, cashback)
 	if err != nil {
+		u.releaseCampaignBudget(ctx, cashback)
+		if errors.Is(err, domain.ErrDuplicateCashback) {
+			// A concurrent calculation for the same purchase got there first
+			return domain.Cashback{}, ErrCashbackAlreadyExists
+		}
 		return domain.Cashback{}, err
 	}
+	cashback = created
+	cashbackCtx := logger.WithCashbackID(ctx, cashback.ID.String())
+
+	if cashback.Status == domain.StatusApproved {
+		// Publish cashback.approved event for async minting
+		event := NewCashbackApprovedEvent(cashback)
+		event.FundingAccount = merchant.FundingAccount
+
+		if err := u.outboxPublisher.Publish(cashbackCtx, EventTypeCashbackApproved, event); err != nil {
+			u.log.ErrorContext(cashbackCtx, "failed to publish cashback.approved event", "error", err)
+			return cashback, ErrFailedToPublishEvent
+		}
+
+		u.log.InfoContext(cashbackCtx, "cashback approved", "user_id", cashback.UserID, "amount", cashback.Amount)
+	} else {
+		u.log.InfoContext(cashbackCtx, "cashback held until a wallet is verified", "user_id", cashback.UserID)
+	}
 
-	// Publish cashback.approved event for async minting
+	// The referee's first qualifying purchase triggers the referral bonus
+	u.rewardReferral(ctx, user, purchase)
+
+	return cashback, nil
+}
+
+// NewCashbackApprovedEvent builds the cashback.approved payload for an approved ledger entry.
+// The destination is the wallet captured on the entry at approval time.
+func NewCashbackApprovedEvent(cashback domain.Cashback) CashbackApprovedEvent {
 	event := CashbackApprovedEvent{
 		CashbackID:      cashback.ID.String(),
 		UserID:          cashback.UserID.String(),
-		WalletAddress:   user.WalletAddress,
+		WalletAddress:   cashback.WalletAddress,
 		PurchaseID:      cashback.PurchaseID.String(),
+		CashbackType:    cashback.Type,
 		Amount:          cashback.Amount,
+		BaseAmount:      cashback.BaseAmount,
+		CampaignAmount:  cashback.CampaignAmount,
 		CashbackPercent: cashback.CashbackPercent,
 	}
-
-	if err := u.outboxPublisher.Publish(ctx, EventTypeCashbackApproved, event); err != nil {
-		log.Printf("Failed to publish cashback.approved event: %v", err)
-		return cashback, ErrFailedToPublishEvent
+	if cashback.MerchantID != uuid.Nil {
+		event.MerchantID = cashback.MerchantID.String()
 	}
-
-	log.Printf("Cashback approved: %s for user %s, amount: %.2f",
-		cashback.ID, cashback.UserID, cashback.Amount)
-
-	return cashback, nil
+	if cashback.CampaignID != nil {
+		event.CampaignID = cashback.CampaignID.String()
+	}
+	return event
 }
@@ -0,0 +1,450 @@
+package calculatecashback_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	campaigndomain "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	campaignrepository "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	merchantdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	merchantrepository "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/repository"
+	purchasedomain "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	purchaserepository "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/repository"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	userrepository "github.com/cashback-platform/services/cashback-service-api/internal/app/user/repository"
+	"github.com/google/uuid"
+)
+
+const wallet = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+var multipliers = userdomain.TierMultipliers{Silver: 1.25, Gold: 1.5}
+
+type fixture struct {
+	cashback  *cashbackRepository
+	purchases *purchaserepository.Memory
+	users     *userrepository.Memory
+	merchants *merchantrepository.Memory
+	campaigns *campaignrepository.Memory
+	outbox    *outbox
+	usecase   calculatecashback.UseCase
+}
+
+func newFixture(policy calculatecashback.ReferralPolicy) *fixture {
+	f := &fixture{
+		cashback:  &cashbackRepository{Memory: repository.NewMemory()},
+		purchases: purchaserepository.NewMemory(),
+		users:     userrepository.NewMemory(),
+		merchants: merchantrepository.NewMemory(),
+		campaigns: campaignrepository.NewMemory(),
+		outbox:    &outbox{},
+	}
+	f.usecase = calculatecashback.New(f.cashback, f.purchases, f.users, f.merchants, f.campaigns, f.outbox, policy, multipliers,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+func (f *fixture) user(t *testing.T, change func(*userdomain.User)) userdomain.User {
+	t.Helper()
+	id := uuid.NewString()
+	verifiedAt := time.Now().UTC()
+	user := userdomain.User{
+		ExternalID:       "ext-" + id,
+		Email:            id + "@example.com",
+		WalletAddress:    wallet,
+		WalletVerifiedAt: &verifiedAt,
+		ReferralCode:     id[:8],
+	}
+	if change != nil {
+		change(&user)
+	}
+	user, err := f.users.Create(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func (f *fixture) merchant(t *testing.T, percent float64) merchantdomain.Merchant {
+	t.Helper()
+	merchant, err := merchantdomain.NewMerchant("Corner Shop", "5411", percent, "acct-funding")
+	if err != nil {
+		t.Fatal(err)
+	}
+	merchant, err = f.merchants.Create(context.Background(), merchant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return merchant
+}
+
+func (f *fixture) purchase(t *testing.T, user userdomain.User, merchant merchantdomain.Merchant, amount float64) purchasedomain.Purchase {
+	t.Helper()
+	purchase, err := f.purchases.Create(context.Background(), purchasedomain.NewPurchase(user.ID, amount, merchant.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return purchase
+}
+
+func (f *fixture) campaign(t *testing.T, multiplier, bonus, budget float64) campaigndomain.Campaign {
+	t.Helper()
+	now := time.Now().UTC()
+	campaign, err := campaigndomain.NewCampaign("Boost", campaigndomain.Eligibility{}, multiplier, bonus, budget,
+		now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	campaign, err = f.campaigns.Create(context.Background(), campaign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return campaign
+}
+
+func TestExecuteApprovesCashbackAtTheMerchantRate(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(calculatecashback.ReferralPolicy{})
+	user := f.user(t, nil)
+	merchant := f.merchant(t, 5)
+	purchase := f.purchase(t, user, merchant, 200)
+
+	cashback, err := f.usecase.Execute(ctx, purchase.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cashback.Amount != 10 || cashback.Status != domain.StatusApproved || cashback.WalletAddress != wallet {
+		t.Fatalf("cashback = %+v, want 10 approved to %s", cashback, wallet)
+	}
+
+	events := f.outbox.published(calculatecashback.EventTypeCashbackApproved)
+	if len(events) != 1 {
+		t.Fatalf("published %d cashback.approved events, want 1", len(events))
+	}
+	if event := events[0]; event.CashbackID != cashback.ID.String() || event.FundingAccount != "acct-funding" || event.Amount != 10 {
+		t.Fatalf("event = %+v", event)
+	}
+
+	// A second calculation for the purchase returns the existing cashback.
+	again, err := f.usecase.Execute(ctx, purchase.ID)
+	if !errors.Is(err, calculatecashback.ErrCashbackAlreadyExists) {
+		t.Fatalf("error = %v, want %v", err, calculatecashback.ErrCashbackAlreadyExists)
+	}
+	if again.ID != cashback.ID {
+		t.Fatalf("returned cashback %s, want %s", again.ID, cashback.ID)
+	}
+}
+
+func TestExecuteScalesTheMerchantRateByTier(t *testing.T) {
+	for _, tc := range []struct {
+		tier    string
+		percent float64
+		amount  float64
+	}{
+		{userdomain.TierBronze, 5, 10},
+		{userdomain.TierSilver, 6.25, 12.5},
+		{userdomain.TierGold, 7.5, 15},
+	} {
+		t.Run(tc.tier, func(t *testing.T) {
+			f := newFixture(calculatecashback.ReferralPolicy{})
+			user := f.user(t, func(u *userdomain.User) { u.Tier = tc.tier })
+			merchant := f.merchant(t, 5)
+			purchase := f.purchase(t, user, merchant, 200)
+
+			cashback, err := f.usecase.Execute(context.Background(), purchase.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cashback.CashbackPercent != tc.percent || cashback.Amount != tc.amount || cashback.BaseAmount != tc.amount {
+				t.Fatalf("cashback = %+v, want %v at %v%%", cashback, tc.amount, tc.percent)
+			}
+		})
+	}
+}
+
+func TestExecuteHoldsCashbackWithoutAVerifiedWallet(t *testing.T) {
+	f := newFixture(calculatecashback.ReferralPolicy{})
+	user := f.user(t, func(u *userdomain.User) { u.WalletAddress, u.WalletVerifiedAt = "", nil })
+	merchant := f.merchant(t, 5)
+	purchase := f.purchase(t, user, merchant, 100)
+
+	cashback, err := f.usecase.Execute(context.Background(), purchase.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cashback.Status != domain.StatusPending || cashback.WalletAddress != "" {
+		t.Fatalf("cashback = %+v, want pending without a wallet", cashback)
+	}
+	if n := len(f.outbox.published(calculatecashback.EventTypeCashbackApproved)); n != 0 {
+		t.Fatalf("published %d cashback.approved events, want none", n)
+	}
+}
+
+func TestExecuteRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, f *fixture) uuid.UUID
+		wantErr error
+	}{
+		{
+			name:    "unknown purchase",
+			setup:   func(t *testing.T, f *fixture) uuid.UUID { return uuid.New() },
+			wantErr: calculatecashback.ErrPurchaseNotFound,
+		},
+		{
+			name: "unknown user",
+			setup: func(t *testing.T, f *fixture) uuid.UUID {
+				return f.purchase(t, userdomain.User{ID: uuid.New()}, f.merchant(t, 5), 100).ID
+			},
+			wantErr: calculatecashback.ErrUserNotFound,
+		},
+		{
+			name: "deactivated user",
+			setup: func(t *testing.T, f *fixture) uuid.UUID {
+				user := f.user(t, func(u *userdomain.User) { u.Deactivate() })
+				return f.purchase(t, user, f.merchant(t, 5), 100).ID
+			},
+			wantErr: calculatecashback.ErrUserNotActive,
+		},
+		{
+			name: "unknown merchant",
+			setup: func(t *testing.T, f *fixture) uuid.UUID {
+				return f.purchase(t, f.user(t, nil), merchantdomain.Merchant{ID: uuid.New()}, 100).ID
+			},
+			wantErr: calculatecashback.ErrMerchantNotFound,
+		},
+		{
+			name: "suspended merchant",
+			setup: func(t *testing.T, f *fixture) uuid.UUID {
+				merchant := f.merchant(t, 5)
+				merchant.Suspend()
+				if err := f.merchants.Update(context.Background(), merchant); err != nil {
+					t.Fatal(err)
+				}
+				return f.purchase(t, f.user(t, nil), merchant, 100).ID
+			},
+			wantErr: calculatecashback.ErrMerchantNotActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(calculatecashback.ReferralPolicy{})
+			purchaseID := tt.setup(t, f)
+
+			_, err := f.usecase.Execute(context.Background(), purchaseID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := f.cashback.FindByPurchaseID(context.Background(), purchaseID); !errors.Is(err, domain.ErrCashbackNotFound) {
+				t.Fatalf("cashback was recorded: %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteAppliesTheBestCampaignWithBudget(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(calculatecashback.ReferralPolicy{})
+	user := f.user(t, nil)
+	merchant := f.merchant(t, 10)
+
+	// On 100 at 10%, the tripling campaign adds 20 and the bonus campaign 5.
+	triple := f.campaign(t, 3, 0, 30)
+	bonus := f.campaign(t, 1, 5, 100)
+
+	first, err := f.usecase.Execute(ctx, f.purchase(t, user, merchant, 100).ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.CampaignID == nil || *first.CampaignID != triple.ID || first.Amount != 30 {
+		t.Fatalf("first cashback = %+v, want 30 boosted by %s", first, triple.ID)
+	}
+
+	// 10 of the tripling budget is left, which still beats the bonus campaign.
+	second, err := f.usecase.Execute(ctx, f.purchase(t, user, merchant, 100).ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.CampaignID == nil || *second.CampaignID != triple.ID || second.CampaignAmount != 10 {
+		t.Fatalf("second cashback = %+v, want a boost of 10 by %s", second, triple.ID)
+	}
+
+	third, err := f.usecase.Execute(ctx, f.purchase(t, user, merchant, 100).ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.CampaignID == nil || *third.CampaignID != bonus.ID || third.Amount != 15 {
+		t.Fatalf("third cashback = %+v, want 15 boosted by %s", third, bonus.ID)
+	}
+
+	for id, want := range map[uuid.UUID]float64{triple.ID: 30, bonus.ID: 5} {
+		campaign, err := f.campaigns.FindByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if campaign.Spent != want {
+			t.Fatalf("campaign %s spent %v, want %v", id, campaign.Spent, want)
+		}
+	}
+}
+
+func TestExecuteRewardsTheFirstQualifyingReferralOnce(t *testing.T) {
+	ctx := context.Background()
+	policy := calculatecashback.ReferralPolicy{ReferrerBonus: 5, RefereeBonus: 3, MinPurchaseAmount: 50}
+	f := newFixture(policy)
+	referrer := f.user(t, func(u *userdomain.User) { u.WalletAddress = "0x0000000000000000000000000000000000000001" })
+	referee := f.user(t, func(u *userdomain.User) { u.ReferredBy = &referrer.ID })
+	merchant := f.merchant(t, 10)
+
+	// Too small to qualify.
+	if _, err := f.usecase.Execute(ctx, f.purchase(t, referee, merchant, 20).ID); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.outbox.published(calculatecashback.EventTypeCashbackApproved)); n != 1 {
+		t.Fatalf("published %d events, want the purchase cashback only", n)
+	}
+
+	for range 2 {
+		if _, err := f.usecase.Execute(ctx, f.purchase(t, referee, merchant, 100).ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bonuses := map[string]float64{}
+	for _, event := range f.outbox.published(calculatecashback.EventTypeCashbackApproved) {
+		if event.CashbackType == domain.TypeReferral {
+			bonuses[event.UserID] += event.Amount
+		}
+	}
+	if len(bonuses) != 2 || bonuses[referrer.ID.String()] != 5 || bonuses[referee.ID.String()] != 3 {
+		t.Fatalf("referral bonuses = %v, want 5 to the referrer and 3 to the referee", bonuses)
+	}
+}
+
+func TestExecuteReleasesTheReferralClaimWhenTheBonusIsNotStored(t *testing.T) {
+	ctx := context.Background()
+	policy := calculatecashback.ReferralPolicy{ReferrerBonus: 5, RefereeBonus: 3}
+	f := newFixture(policy)
+	referrer := f.user(t, func(u *userdomain.User) { u.WalletAddress = "0x0000000000000000000000000000000000000001" })
+	referee := f.user(t, func(u *userdomain.User) { u.ReferredBy = &referrer.ID })
+	merchant := f.merchant(t, 10)
+
+	f.cashback.failCreateAll = errors.New("connection reset")
+	if _, err := f.usecase.Execute(ctx, f.purchase(t, referee, merchant, 100).ID); err != nil {
+		t.Fatal(err)
+	}
+	found, err := f.users.FindByID(ctx, referee.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ReferralRewardedAt != nil {
+		t.Fatal("the referral reward stayed claimed without a bonus")
+	}
+
+	f.cashback.failCreateAll = nil
+	if _, err := f.usecase.Execute(ctx, f.purchase(t, referee, merchant, 100).ID); err != nil {
+		t.Fatal(err)
+	}
+	var bonuses int
+	for _, event := range f.outbox.published(calculatecashback.EventTypeCashbackApproved) {
+		if event.CashbackType == domain.TypeReferral {
+			bonuses++
+		}
+	}
+	if bonuses != 2 {
+		t.Fatalf("published %d referral bonuses, want 2", bonuses)
+	}
+}
+
+func TestExecuteRejectsSelfReferral(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(calculatecashback.ReferralPolicy{ReferrerBonus: 5, RefereeBonus: 3})
+	referrer := f.user(t, nil)
+	referee := f.user(t, func(u *userdomain.User) { u.ReferredBy = &referrer.ID })
+
+	if _, err := f.usecase.Execute(ctx, f.purchase(t, referee, f.merchant(t, 10), 100).ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range f.outbox.published(calculatecashback.EventTypeCashbackApproved) {
+		if event.CashbackType == domain.TypeReferral {
+			t.Fatalf("paid a referral bonus between users sharing a wallet: %+v", event)
+		}
+	}
+}
+
+func TestExecuteRejectsReferralsBetweenUsersSharingAVerifiedWallet(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(calculatecashback.ReferralPolicy{ReferrerBonus: 5, RefereeBonus: 3})
+	referrer := f.user(t, nil)
+	// The referee is paid out elsewhere but proved ownership of the
+	// referrer's wallet too.
+	referee := f.user(t, func(u *userdomain.User) {
+		u.ReferredBy = &referrer.ID
+		u.WalletAddress = "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+	})
+	if _, err := f.users.AddWallet(ctx, userdomain.NewWallet(referee.ID, wallet)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.usecase.Execute(ctx, f.purchase(t, referee, f.merchant(t, 10), 100).ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range f.outbox.published(calculatecashback.EventTypeCashbackApproved) {
+		if event.CashbackType == domain.TypeReferral {
+			t.Fatalf("paid a referral bonus between users sharing a verified wallet: %+v", event)
+		}
+	}
+}
+
+// cashbackRepository is the in-memory repository, failing CreateAll while
+// failCreateAll is set.
+type cashbackRepository struct {
+	*repository.Memory
+	failCreateAll error
+}
+
+func (c *cashbackRepository) CreateAll(ctx context.Context, cashback []domain.Cashback) ([]domain.Cashback, error) {
+	if c.failCreateAll != nil {
+		return nil, c.failCreateAll
+	}
+	return c.Memory.CreateAll(ctx, cashback)
+}
+
+// outbox records the events published, as the transactional outbox would.
+type outbox struct {
+	mu     sync.Mutex
+	events []published
+}
+
+type published struct {
+	eventType string
+	payload   any
+}
+
+func (o *outbox) Publish(_ context.Context, eventType string, payload any) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, published{eventType: eventType, payload: payload})
+	return nil
+}
+
+func (o *outbox) published(eventType string) []calculatecashback.CashbackApprovedEvent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var events []calculatecashback.CashbackApprovedEvent
+	for _, e := range o.events {
+		if e.eventType == eventType {
+			events = append(events, e.payload.(calculatecashback.CashbackApprovedEvent))
+		}
+	}
+	return events
+}
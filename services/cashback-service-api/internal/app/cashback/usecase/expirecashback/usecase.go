@@ -0,0 +1,218 @@
+package expirecashback
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypeCashbackExpiring = "cashback.expiring"
+	EventTypeCashbackExpired  = "cashback.expired"
+)
+
+type (
+	// Repository interface for expirable cashback lookups and transitions
+	Repository interface {
+		FindExpirableBefore(ctx context.Context, before time.Time, limit int) ([]domain.Cashback, error)
+		FindUnwarnedBefore(ctx context.Context, before time.Time, limit int) ([]domain.Cashback, error)
+		Expire(ctx context.Context, id uuid.UUID) (bool, error)
+		MarkExpiryWarned(ctx context.Context, id uuid.UUID) (bool, error)
+	}
+
+	// CampaignRepository interface for releasing campaign budget
+	CampaignRepository interface {
+		ReleaseBudget(ctx context.Context, id uuid.UUID, amount float64) error
+	}
+
+	// EventPublisher publishes events to the outbox
+	EventPublisher interface {
+		Publish(ctx context.Context, eventType string, payload any) error
+	}
+
+	// Policy configures when unminted cashback expires.
+	Policy struct {
+		// TTL is how long cashback may stay held, approved or failed.
+		TTL time.Duration
+		// WarningLead is how long before expiry the user is warned.
+		WarningLead time.Duration
+		// BatchSize caps how many entries a single run processes per step.
+		BatchSize int
+	}
+
+	UseCase struct {
+		repository         Repository
+		campaignRepository CampaignRepository
+		eventPublisher     EventPublisher
+		policy             Policy
+		log                *slog.Logger
+	}
+
+	// Result summarizes a single expiry run.
+	Result struct {
+		Warned  int
+		Expired int
+	}
+
+	// CashbackExpiringEvent warns that unminted cashback is about to expire
+	CashbackExpiringEvent struct {
+		CashbackID string  `json:"cashback_id"`
+		UserID     string  `json:"user_id"`
+		Amount     float64 `json:"amount"`
+		Status     string  `json:"status"`
+		ExpiresAt  string  `json:"expires_at"`
+	}
+
+	// CashbackExpiredEvent represents the event published when cashback expires
+	CashbackExpiredEvent struct {
+		CashbackID     string  `json:"cashback_id"`
+		UserID         string  `json:"user_id"`
+		PurchaseID     string  `json:"purchase_id"`
+		CashbackType   string  `json:"cashback_type"`
+		Amount         float64 `json:"amount"`
+		CampaignID     string  `json:"campaign_id,omitempty"`
+		ReleasedBudget float64 `json:"released_budget"`
+		ExpiredAt      string  `json:"expired_at"`
+	}
+)
+
+func New(
+	repository Repository,
+	campaignRepository CampaignRepository,
+	eventPublisher EventPublisher,
+	policy Policy,
+	log *slog.Logger,
+) UseCase {
+	return UseCase{
+		repository:         repository,
+		campaignRepository: campaignRepository,
+		eventPublisher:     eventPublisher,
+		policy:             policy,
+		log:                log,
+	}
+}
+
+// Execute warns the owners of cashback that is about to expire and expires
+// cashback that stayed held, approved or failed longer than the TTL. Approved
+// cashback that old has no completed mint: its mint request was lost, or
+// failed without the failure reaching the ledger. Expiring it tells the Mint
+// Consumer to stop retrying; tokens minted regardless are reported by
+// reconciliation.
+func (u UseCase) Execute(ctx context.Context) (Result, error) {
+	now := time.Now().UTC()
+
+	warned, err := u.warnExpiring(ctx, now)
+	if err != nil {
+		return Result{}, err
+	}
+
+	expired, err := u.expireStale(ctx, now)
+	if err != nil {
+		return Result{Warned: warned}, err
+	}
+
+	return Result{Warned: warned, Expired: expired}, nil
+}
+
+func (u UseCase) warnExpiring(ctx context.Context, now time.Time) (int, error) {
+	if u.policy.WarningLead <= 0 {
+		return 0, nil
+	}
+
+	cashbacks, err := u.repository.FindUnwarnedBefore(ctx, now.Add(u.policy.WarningLead-u.policy.TTL), u.policy.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	warned := 0
+	for _, c := range cashbacks {
+		claimed, err := u.repository.MarkExpiryWarned(ctx, c.ID)
+		if err != nil {
+			return warned, err
+		}
+		if !claimed {
+			continue
+		}
+
+		event := CashbackExpiringEvent{
+			CashbackID: c.ID.String(),
+			UserID:     c.UserID.String(),
+			Amount:     c.Amount,
+			Status:     c.Status,
+			ExpiresAt:  c.CreatedAt.Add(u.policy.TTL).Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if err := u.eventPublisher.Publish(ctx, EventTypeCashbackExpiring, event); err != nil {
+			return warned, err
+		}
+		warned++
+	}
+
+	return warned, nil
+}
+
+func (u UseCase) expireStale(ctx context.Context, now time.Time) (int, error) {
+	cashbacks, err := u.repository.FindExpirableBefore(ctx, now.Add(-u.policy.TTL), u.policy.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, c := range cashbacks {
+		ok, err := u.expire(ctx, c)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// expire moves a single cashback to expired, returns its campaign boost to
+// the campaign budget and publishes cashback.expired. It returns false when
+// the cashback was minted or expired concurrently.
+func (u UseCase) expire(ctx context.Context, cashback domain.Cashback) (bool, error) {
+	ctx = logger.WithCashbackID(ctx, cashback.ID.String())
+	if err := cashback.Expire(); errors.Is(err, domain.ErrNotExpirable) {
+		return false, nil
+	}
+
+	ok, err := u.repository.Expire(ctx, cashback.ID)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	event := CashbackExpiredEvent{
+		CashbackID:   cashback.ID.String(),
+		UserID:       cashback.UserID.String(),
+		PurchaseID:   cashback.PurchaseID.String(),
+		CashbackType: cashback.Type,
+		Amount:       cashback.Amount,
+		ExpiredAt:    cashback.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if cashback.CampaignID != nil && cashback.CampaignAmount > 0 {
+		err := u.campaignRepository.ReleaseBudget(ctx, *cashback.CampaignID, cashback.CampaignAmount)
+		if err != nil {
+			u.log.ErrorContext(ctx, "failed to release campaign budget", "campaign_id", *cashback.CampaignID, "error", err)
+		} else {
+			event.CampaignID = cashback.CampaignID.String()
+			event.ReleasedBudget = cashback.CampaignAmount
+		}
+	}
+
+	if err := u.eventPublisher.Publish(ctx, EventTypeCashbackExpired, event); err != nil {
+		return true, err
+	}
+
+	u.log.InfoContext(ctx, "cashback expired", "user_id", cashback.UserID, "amount", cashback.Amount)
+
+	return true, nil
+}
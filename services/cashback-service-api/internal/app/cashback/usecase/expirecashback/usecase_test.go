@@ -0,0 +1,144 @@
+package expirecashback_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	campaignrepository "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/expirecashback"
+	"github.com/google/uuid"
+)
+
+const ttl = 90 * 24 * time.Hour
+
+type fixture struct {
+	cashback *repository.Memory
+	outbox   *outbox
+	usecase  expirecashback.UseCase
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		cashback: repository.NewMemory(),
+		outbox:   &outbox{},
+	}
+	f.usecase = expirecashback.New(f.cashback, campaignrepository.NewMemory(), f.outbox,
+		expirecashback.Policy{TTL: ttl, WarningLead: 7 * 24 * time.Hour, BatchSize: 100},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+func (f *fixture) cashbackIn(t *testing.T, status string, age time.Duration) domain.Cashback {
+	t.Helper()
+	cashback, err := f.cashback.Create(context.Background(), domain.Cashback{
+		UserID:     uuid.New(),
+		PurchaseID: uuid.New(),
+		MerchantID: uuid.New(),
+		Amount:     5,
+		BaseAmount: 5,
+		Status:     status,
+		CreatedAt:  time.Now().Add(-age),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cashback
+}
+
+func (f *fixture) status(t *testing.T, id uuid.UUID) string {
+	t.Helper()
+	cashback, err := f.cashback.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cashback.Status
+}
+
+func TestExpireOnlyExpiresUnmintedCashback(t *testing.T) {
+	f := newFixture()
+	stale := ttl + time.Hour
+	held := f.cashbackIn(t, domain.StatusPending, stale)
+	failed := f.cashbackIn(t, domain.StatusFailed, stale)
+	approved := f.cashbackIn(t, domain.StatusApproved, stale)
+	minted := f.cashbackIn(t, domain.StatusMinted, stale)
+	recent := f.cashbackIn(t, domain.StatusPending, ttl-time.Hour)
+
+	result, err := f.usecase.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Expired != 3 {
+		t.Fatalf("expired %d cashback, want 3", result.Expired)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		cashback domain.Cashback
+		want     string
+	}{
+		{"held", held, domain.StatusExpired},
+		{"failed", failed, domain.StatusExpired},
+		// Its mint never completed within the TTL.
+		{"approved", approved, domain.StatusExpired},
+		{"minted", minted, domain.StatusMinted},
+		{"recent", recent, domain.StatusPending},
+	} {
+		if got := f.status(t, tc.cashback.ID); got != tc.want {
+			t.Errorf("%s cashback is %q, want %q", tc.name, got, tc.want)
+		}
+	}
+	if got := len(f.outbox.published(expirecashback.EventTypeCashbackExpired)); got != 3 {
+		t.Fatalf("published %d cashback.expired events, want 3", got)
+	}
+}
+
+func TestExpireWarnsOnlyAboutCashbackThatCanExpire(t *testing.T) {
+	f := newFixture()
+	soon := ttl - 24*time.Hour
+	held := f.cashbackIn(t, domain.StatusPending, soon)
+	f.cashbackIn(t, domain.StatusMinted, soon)
+	f.cashbackIn(t, domain.StatusPending, time.Hour)
+
+	for range 2 {
+		if _, err := f.usecase.Execute(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	warned := f.outbox.published(expirecashback.EventTypeCashbackExpiring)
+	if len(warned) != 1 {
+		t.Fatalf("published %d cashback.expiring events, want 1", len(warned))
+	}
+	if event := warned[0].(expirecashback.CashbackExpiringEvent); event.CashbackID != held.ID.String() {
+		t.Fatalf("warned about cashback %s, want %s", event.CashbackID, held.ID)
+	}
+}
+
+type outbox struct {
+	events []published
+}
+
+type published struct {
+	eventType string
+	payload   any
+}
+
+func (o *outbox) Publish(_ context.Context, eventType string, payload any) error {
+	o.events = append(o.events, published{eventType: eventType, payload: payload})
+	return nil
+}
+
+func (o *outbox) published(eventType string) []any {
+	var payloads []any
+	for _, e := range o.events {
+		if e.eventType == eventType {
+			payloads = append(payloads, e.payload)
+		}
+	}
+	return payloads
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
 	"github.com/google/uuid"
 )
 
 type (
 	Repository interface {
-		FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Cashback, error)
+		ListByUserID(ctx context.Context, userID uuid.UUID, filter domain.Filter, page pagination.Request) (pagination.Page[domain.Cashback], error)
+		CountByUserID(ctx context.Context, userID uuid.UUID, filter domain.Filter) (int, error)
 		TotalByUserID(ctx context.Context, userID uuid.UUID) (float64, error)
 	}
 
@@ -17,9 +19,11 @@ type (
 		repository Repository
 	}
 
+	// UserCashbackSummary holds one page of the user's cashback. TotalCashbacks
+	// counts all cashback matching the filter, TotalMinted all minted cashback.
 	UserCashbackSummary struct {
 		UserID         uuid.UUID
-		Cashbacks      []domain.Cashback
+		Cashbacks      pagination.Page[domain.Cashback]
 		TotalMinted    float64
 		TotalCashbacks int
 	}
@@ -31,12 +35,20 @@ func New(repository Repository) UseCase {
 	}
 }
 
-func (u UseCase) Execute(ctx context.Context, userID uuid.UUID) (UserCashbackSummary, error) {
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, filter domain.Filter, page pagination.Request) (UserCashbackSummary, error) {
 	if userID == uuid.Nil {
 		return UserCashbackSummary{}, domain.ErrInvalidUserID
 	}
+	if err := filter.Validate(); err != nil {
+		return UserCashbackSummary{}, err
+	}
+
+	cashbacks, err := u.repository.ListByUserID(ctx, userID, filter, page)
+	if err != nil {
+		return UserCashbackSummary{}, err
+	}
 
-	cashbacks, err := u.repository.FindByUserID(ctx, userID)
+	totalCashbacks, err := u.repository.CountByUserID(ctx, userID, filter)
 	if err != nil {
 		return UserCashbackSummary{}, err
 	}
@@ -50,6 +62,6 @@ func (u UseCase) Execute(ctx context.Context, userID uuid.UUID) (UserCashbackSum
 		UserID:         userID,
 		Cashbacks:      cashbacks,
 		TotalMinted:    totalMinted,
-		TotalCashbacks: len(cashbacks),
+		TotalCashbacks: totalCashbacks,
 	}, nil
 }
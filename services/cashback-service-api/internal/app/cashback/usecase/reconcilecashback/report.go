@@ -0,0 +1,112 @@
+package reconcilecashback
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Report is the outcome of a reconciliation run.
+	Report struct {
+		RunID         uuid.UUID       `json:"run_id"`
+		StartedAt     time.Time       `json:"started_at"`
+		FinishedAt    time.Time       `json:"finished_at"`
+		Cashbacks     int             `json:"cashbacks"`
+		Mints         int             `json:"mints"`
+		Wallets       []WalletSummary `json:"wallets"`
+		Discrepancies []Discrepancy   `json:"discrepancies"`
+	}
+
+	// WalletSummary sets the three views of a wallet side by side, in wei:
+	// what the ledger marks minted, what mint requests completed and what the
+	// chain holds. Error is set when the balance could not be read.
+	WalletSummary struct {
+		WalletAddress string    `json:"wallet_address"`
+		UserID        uuid.UUID `json:"user_id"`
+		LedgerMinted  string    `json:"ledger_minted"`
+		Minted        string    `json:"minted"`
+		OnChain       string    `json:"on_chain,omitempty"`
+		Error         string    `json:"error,omitempty"`
+
+		ledgerMinted *big.Int
+		minted       *big.Int
+	}
+
+	// Discrepancy is a single finding. Expected and Actual are amounts in wei,
+	// or ledger statuses for KindMintNotRecorded.
+	Discrepancy struct {
+		Kind          string    `json:"kind"`
+		CashbackID    uuid.UUID `json:"cashback_id"`
+		UserID        uuid.UUID `json:"user_id"`
+		WalletAddress string    `json:"wallet_address"`
+		Expected      string    `json:"expected"`
+		Actual        string    `json:"actual"`
+		Detail        string    `json:"detail"`
+	}
+)
+
+var csvHeader = []string{"run_id", "kind", "cashback_id", "user_id", "wallet_address", "expected", "actual", "detail"}
+
+// Count returns the number of discrepancies of each kind.
+func (r Report) Count() map[string]int {
+	counts := make(map[string]int)
+	for _, d := range r.Discrepancies {
+		counts[d.Kind]++
+	}
+	return counts
+}
+
+// WriteJSON writes the whole report, wallets included.
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes one row per discrepancy.
+func (r Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, d := range r.Discrepancies {
+		err := writer.Write([]string{
+			r.RunID.String(),
+			d.Kind,
+			uuidString(d.CashbackID),
+			uuidString(d.UserID),
+			d.WalletAddress,
+			d.Expected,
+			d.Actual,
+			d.Detail,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (r *Report) add(d Discrepancy) {
+	r.Discrepancies = append(r.Discrepancies, d)
+}
+
+func (r *Report) sort() {
+	sort.SliceStable(r.Discrepancies, func(i, j int) bool {
+		a, b := r.Discrepancies[i], r.Discrepancies[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.WalletAddress != b.WalletAddress {
+			return a.WalletAddress < b.WalletAddress
+		}
+		return a.CashbackID.String() < b.CashbackID.String()
+	})
+}
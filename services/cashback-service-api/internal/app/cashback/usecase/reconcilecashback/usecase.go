@@ -0,0 +1,283 @@
+// Package reconcilecashback checks the cashback ledger against what was
+// actually minted. The ledger is a cache of on-chain state, not the source of
+// truth, so it is compared with the mint requests mint-consumer completed and
+// with the token balance of every wallet on chain.
+package reconcilecashback
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/google/uuid"
+)
+
+const EventTypeDiscrepancy = "reconciliation.discrepancy"
+
+// Kinds of discrepancy.
+const (
+	// KindApprovedNotMinted is cashback approved longer than the grace period
+	// ago that no completed mint request covers. Approved cashback is minted
+	// by mint-consumer and settled from its token.minted and token.mint.failed
+	// events, so cashback still approved after the grace period is stuck.
+	KindApprovedNotMinted = "approved_not_minted"
+	// KindMintNotRecorded is a completed mint request whose cashback is not
+	// minted in the ledger, or is not in the ledger at all.
+	KindMintNotRecorded = "mint_not_recorded"
+	// KindBalanceMismatch is a wallet whose on-chain balance differs from the
+	// tokens completed mint requests sent to it.
+	KindBalanceMismatch = "balance_mismatch"
+)
+
+type (
+	// Repository interface for reading the cashback ledger
+	Repository interface {
+		ListForReconciliation(ctx context.Context) ([]domain.Cashback, error)
+	}
+
+	// MintRepository interface for reading completed mints
+	MintRepository interface {
+		ListCompletedMints(ctx context.Context) ([]domain.Mint, error)
+	}
+
+	// BalanceReader interface for reading on-chain token balances, in wei
+	BalanceReader interface {
+		GetBalance(ctx context.Context, walletAddress string) (string, error)
+	}
+
+	// EventPublisher publishes events to the outbox
+	EventPublisher interface {
+		Publish(ctx context.Context, eventType string, payload any) error
+	}
+
+	// Policy configures a reconciliation run.
+	Policy struct {
+		// Grace is how long approved cashback may wait for its mint, from its
+		// approval, before it is reported.
+		Grace time.Duration
+		// TokenDecimals converts ledger amounts to the token's smallest unit.
+		TokenDecimals int
+	}
+
+	UseCase struct {
+		repository     Repository
+		mintRepository MintRepository
+		balances       BalanceReader
+		eventPublisher EventPublisher
+		policy         Policy
+		log            *slog.Logger
+	}
+
+	// DiscrepancyEvent is published for every discrepancy found
+	DiscrepancyEvent struct {
+		RunID         string `json:"run_id"`
+		Kind          string `json:"kind"`
+		CashbackID    string `json:"cashback_id,omitempty"`
+		UserID        string `json:"user_id,omitempty"`
+		WalletAddress string `json:"wallet_address"`
+		Expected      string `json:"expected"`
+		Actual        string `json:"actual"`
+		Detail        string `json:"detail"`
+		DetectedAt    string `json:"detected_at"`
+	}
+)
+
+func New(
+	repository Repository,
+	mintRepository MintRepository,
+	balances BalanceReader,
+	eventPublisher EventPublisher,
+	policy Policy,
+	log *slog.Logger,
+) UseCase {
+	return UseCase{
+		repository:     repository,
+		mintRepository: mintRepository,
+		balances:       balances,
+		eventPublisher: eventPublisher,
+		policy:         policy,
+		log:            log,
+	}
+}
+
+// Execute reconciles the whole ledger and publishes a discrepancy event for
+// every finding. Amounts in the report are in wei.
+func (u UseCase) Execute(ctx context.Context) (Report, error) {
+	report := Report{RunID: uuid.New(), StartedAt: time.Now().UTC()}
+	ctx = logger.WithAttrs(ctx, slog.String("run_id", report.RunID.String()))
+
+	cashbacks, err := u.repository.ListForReconciliation(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to load ledger: %w", err)
+	}
+	mints, err := u.mintRepository.ListCompletedMints(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to load mint requests: %w", err)
+	}
+	report.Cashbacks = len(cashbacks)
+	report.Mints = len(mints)
+
+	wallets := make(map[string]*WalletSummary)
+	wallet := func(address string, userID uuid.UUID) *WalletSummary {
+		key := strings.ToLower(address)
+		w, ok := wallets[key]
+		if !ok {
+			w = &WalletSummary{WalletAddress: address, UserID: userID, ledgerMinted: new(big.Int), minted: new(big.Int)}
+			wallets[key] = w
+		}
+		return w
+	}
+
+	mintsByCashback := make(map[uuid.UUID][]domain.Mint, len(mints))
+	for _, m := range mints {
+		mintsByCashback[m.CashbackID] = append(mintsByCashback[m.CashbackID], m)
+		amount, ok := new(big.Int).SetString(m.TokenAmount, 10)
+		if !ok {
+			u.log.WarnContext(ctx, "mint request has an invalid token amount", "mint_request_id", m.ID, "token_amount", m.TokenAmount)
+			continue
+		}
+		w := wallet(m.WalletAddress, m.UserID)
+		w.minted.Add(w.minted, amount)
+	}
+
+	ledger := make(map[uuid.UUID]domain.Cashback, len(cashbacks))
+	// Held cashback is approved long after it was created, so the grace
+	// period runs from the last change of status, the approval.
+	graceCutoff := report.StartedAt.Add(-u.policy.Grace)
+	for _, c := range cashbacks {
+		ledger[c.ID] = c
+		amount := u.toWei(c.Amount)
+
+		switch {
+		case c.Status == domain.StatusMinted:
+			w := wallet(c.WalletAddress, c.UserID)
+			w.ledgerMinted.Add(w.ledgerMinted, amount)
+		case c.Status == domain.StatusApproved && c.UpdatedAt.Before(graceCutoff) && len(mintsByCashback[c.ID]) == 0:
+			report.add(Discrepancy{
+				Kind:          KindApprovedNotMinted,
+				CashbackID:    c.ID,
+				UserID:        c.UserID,
+				WalletAddress: c.WalletAddress,
+				Expected:      amount.String(),
+				Actual:        "0",
+				Detail:        fmt.Sprintf("approved %s, no completed mint request", c.UpdatedAt.UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+
+	for cashbackID, cashbackMints := range mintsByCashback {
+		c, ok := ledger[cashbackID]
+		if ok && c.Status == domain.StatusMinted {
+			continue
+		}
+		actual, status := "missing", "missing from the ledger"
+		if ok {
+			actual, status = c.Status, "is "+c.Status+" in the ledger"
+		}
+		for _, m := range cashbackMints {
+			report.add(Discrepancy{
+				Kind:          KindMintNotRecorded,
+				CashbackID:    cashbackID,
+				UserID:        m.UserID,
+				WalletAddress: m.WalletAddress,
+				Expected:      domain.StatusMinted,
+				Actual:        actual,
+				Detail:        fmt.Sprintf("mint request %s completed, cashback %s", m.ID, status),
+			})
+		}
+	}
+
+	for _, w := range sortedWallets(wallets) {
+		w.LedgerMinted = w.ledgerMinted.String()
+		w.Minted = w.minted.String()
+
+		balance, err := u.balances.GetBalance(ctx, w.WalletAddress)
+		if err != nil {
+			u.log.WarnContext(ctx, "failed to read balance, wallet skipped", "wallet", w.WalletAddress, "error", err)
+			w.Error = err.Error()
+			report.Wallets = append(report.Wallets, *w)
+			continue
+		}
+		w.OnChain = balance
+		report.Wallets = append(report.Wallets, *w)
+
+		onChain, ok := new(big.Int).SetString(balance, 10)
+		if ok && onChain.Cmp(w.minted) == 0 {
+			continue
+		}
+		report.add(Discrepancy{
+			Kind:          KindBalanceMismatch,
+			UserID:        w.UserID,
+			WalletAddress: w.WalletAddress,
+			Expected:      w.Minted,
+			Actual:        balance,
+			Detail:        fmt.Sprintf("ledger minted %s, mint requests completed %s", w.LedgerMinted, w.Minted),
+		})
+	}
+
+	report.sort()
+	report.FinishedAt = time.Now().UTC()
+	u.publish(ctx, report)
+	return report, nil
+}
+
+// publish sends an event per discrepancy. A failed publish is logged and does
+// not fail the run: the report still records the discrepancy.
+func (u UseCase) publish(ctx context.Context, report Report) {
+	for _, d := range report.Discrepancies {
+		event := DiscrepancyEvent{
+			RunID:         report.RunID.String(),
+			Kind:          d.Kind,
+			CashbackID:    uuidString(d.CashbackID),
+			UserID:        uuidString(d.UserID),
+			WalletAddress: d.WalletAddress,
+			Expected:      d.Expected,
+			Actual:        d.Actual,
+			Detail:        d.Detail,
+			DetectedAt:    report.FinishedAt.Format(time.RFC3339),
+		}
+		if err := u.eventPublisher.Publish(ctx, EventTypeDiscrepancy, event); err != nil {
+			u.log.ErrorContext(ctx, "failed to publish discrepancy", "kind", d.Kind, "error", err)
+		}
+	}
+}
+
+// toWei converts a ledger amount to the token's smallest unit, dropping any
+// digits beyond TokenDecimals.
+func (u UseCase) toWei(amount float64) *big.Int {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	if !ok {
+		return new(big.Int)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(u.policy.TokenDecimals)), nil)
+	r.Mul(r, new(big.Rat).SetInt(scale))
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+func sortedWallets(wallets map[string]*WalletSummary) []*WalletSummary {
+	keys := make([]string, 0, len(wallets))
+	for key := range wallets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]*WalletSummary, len(keys))
+	for i, key := range keys {
+		result[i] = wallets[key]
+	}
+	return result
+}
+
+func uuidString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,231 @@
+package reconcilecashback_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/reconcilecashback"
+	"github.com/google/uuid"
+)
+
+const (
+	wallet = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	grace  = time.Hour
+)
+
+type fixture struct {
+	cashback *repository.Memory
+	mints    *mints
+	balances balances
+	outbox   *outbox
+	usecase  reconcilecashback.UseCase
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		cashback: repository.NewMemory(),
+		mints:    &mints{},
+		balances: balances{},
+		outbox:   &outbox{},
+	}
+	f.usecase = reconcilecashback.New(f.cashback, f.mints, f.balances, f.outbox,
+		reconcilecashback.Policy{Grace: grace, TokenDecimals: 2},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+// cashbackIn records cashback of 1.5 (150 units) to wallet, last changed age ago.
+func (f *fixture) cashbackIn(t *testing.T, status string, age time.Duration) domain.Cashback {
+	t.Helper()
+	changed := time.Now().Add(-age)
+	cashback, err := f.cashback.Create(context.Background(), domain.Cashback{
+		UserID:        uuid.New(),
+		PurchaseID:    uuid.New(),
+		MerchantID:    uuid.New(),
+		Amount:        1.5,
+		BaseAmount:    1.5,
+		Status:        status,
+		WalletAddress: wallet,
+		CreatedAt:     changed.Add(-30 * 24 * time.Hour),
+		UpdatedAt:     changed,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cashback
+}
+
+func (f *fixture) minted(cashback domain.Cashback, tokenAmount string) {
+	f.mints.completed = append(f.mints.completed, domain.Mint{
+		ID:            uuid.New(),
+		CashbackID:    cashback.ID,
+		UserID:        cashback.UserID,
+		WalletAddress: cashback.WalletAddress,
+		TokenAmount:   tokenAmount,
+	})
+}
+
+func (f *fixture) run(t *testing.T) reconcilecashback.Report {
+	t.Helper()
+	report, err := f.usecase.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.outbox.events) != len(report.Discrepancies) {
+		t.Fatalf("published %d events for %d discrepancies", len(f.outbox.events), len(report.Discrepancies))
+	}
+	return report
+}
+
+func TestReconcileReportsApprovedCashbackOutsideGrace(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		status   string
+		age      time.Duration
+		minted   bool
+		reported bool
+	}{
+		{"stuck", domain.StatusApproved, 2 * grace, false, true},
+		{"recently approved", domain.StatusApproved, grace / 2, false, false},
+		{"minted but not recorded", domain.StatusApproved, 2 * grace, true, false},
+		{"failed", domain.StatusFailed, 2 * grace, false, false},
+		{"expired", domain.StatusExpired, 2 * grace, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture()
+			c := f.cashbackIn(t, tc.status, tc.age)
+			if tc.minted {
+				f.minted(c, "150")
+			}
+			f.balances[wallet] = "0"
+			if tc.minted {
+				f.balances[wallet] = "150"
+			}
+
+			report := f.run(t)
+			found := report.Count()[reconcilecashback.KindApprovedNotMinted]
+			if (found == 1) != tc.reported || found > 1 {
+				t.Fatalf("%d approved_not_minted discrepancies, want reported %t", found, tc.reported)
+			}
+			if tc.reported {
+				d := report.Discrepancies[0]
+				if d.CashbackID != c.ID || d.Expected != "150" || d.Actual != "0" {
+					t.Fatalf("discrepancy %+v", d)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileReportsMintsNotRecordedInTheLedger(t *testing.T) {
+	f := newFixture()
+	recorded := f.cashbackIn(t, domain.StatusMinted, 2*grace)
+	f.minted(recorded, "150")
+	expired := f.cashbackIn(t, domain.StatusExpired, 2*grace)
+	f.minted(expired, "150")
+	f.minted(domain.Cashback{ID: uuid.New(), UserID: uuid.New(), WalletAddress: wallet}, "150")
+	f.balances[wallet] = "450"
+
+	report := f.run(t)
+	if got := report.Count(); len(got) != 1 || got[reconcilecashback.KindMintNotRecorded] != 2 {
+		t.Fatalf("discrepancies %v, want 2 mint_not_recorded", got)
+	}
+	actual := map[string]bool{}
+	for _, d := range report.Discrepancies {
+		if d.Expected != domain.StatusMinted {
+			t.Fatalf("discrepancy %+v expects %q", d, d.Expected)
+		}
+		actual[d.Actual] = true
+	}
+	if !actual[domain.StatusExpired] || !actual["missing"] {
+		t.Fatalf("actual statuses %v, want expired and missing", actual)
+	}
+}
+
+func TestReconcileComparesBalancesWithCompletedMints(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		balance  string
+		reported bool
+	}{
+		{"matching", "150", false},
+		{"short", "100", true},
+		{"unreadable", "not a number", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture()
+			c := f.cashbackIn(t, domain.StatusMinted, 2*grace)
+			f.minted(c, "150")
+			f.balances[strings.ToLower(wallet)] = tc.balance
+
+			report := f.run(t)
+			if got := report.Count()[reconcilecashback.KindBalanceMismatch]; (got == 1) != tc.reported || len(report.Discrepancies) != got {
+				t.Fatalf("discrepancies %v, want balance_mismatch reported %t", report.Count(), tc.reported)
+			}
+			w := report.Wallets[0]
+			if w.LedgerMinted != "150" || w.Minted != "150" || w.OnChain != tc.balance {
+				t.Fatalf("wallet summary %+v", w)
+			}
+		})
+	}
+}
+
+func TestReconcileSkipsWalletsWhoseBalanceCannotBeRead(t *testing.T) {
+	f := newFixture()
+	c := f.cashbackIn(t, domain.StatusMinted, 2*grace)
+	f.minted(c, "150")
+
+	report := f.run(t)
+	if len(report.Discrepancies) != 0 {
+		t.Fatalf("discrepancies %v for a wallet that could not be read", report.Count())
+	}
+	if w := report.Wallets[0]; w.Error == "" || w.OnChain != "" {
+		t.Fatalf("wallet summary %+v, want the read error", w)
+	}
+}
+
+func TestReconcileFailsWithoutMints(t *testing.T) {
+	f := newFixture()
+	f.mints.err = repository.ErrMintDatabaseNotConfigured
+	if _, err := f.usecase.Execute(context.Background()); !errors.Is(err, repository.ErrMintDatabaseNotConfigured) {
+		t.Fatalf("error = %v, want %v", err, repository.ErrMintDatabaseNotConfigured)
+	}
+}
+
+type mints struct {
+	completed []domain.Mint
+	err       error
+}
+
+func (m *mints) ListCompletedMints(context.Context) ([]domain.Mint, error) {
+	return m.completed, m.err
+}
+
+// balances holds the on-chain balance of each wallet, by lowercase or
+// checksummed address.
+type balances map[string]string
+
+func (b balances) GetBalance(_ context.Context, walletAddress string) (string, error) {
+	for _, address := range []string{walletAddress, strings.ToLower(walletAddress)} {
+		if balance, ok := b[address]; ok {
+			return balance, nil
+		}
+	}
+	return "", errors.New("adapter unavailable")
+}
+
+type outbox struct {
+	events []reconcilecashback.DiscrepancyEvent
+}
+
+func (o *outbox) Publish(_ context.Context, _ string, payload any) error {
+	o.events = append(o.events, payload.(reconcilecashback.DiscrepancyEvent))
+	return nil
+}
@@ -0,0 +1,86 @@
+package releasecashback
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	merchantdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	// Repository interface for held cashback
+	Repository interface {
+		FindHeldByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Cashback, error)
+		ApproveHeld(ctx context.Context, id uuid.UUID, walletAddress string) (bool, error)
+	}
+
+	// MerchantRepository interface for the funding account of merchant cashback
+	MerchantRepository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (merchantdomain.Merchant, error)
+	}
+
+	// OutboxPublisher publishes events to the outbox
+	OutboxPublisher interface {
+		Publish(ctx context.Context, eventType string, payload any) error
+	}
+
+	UseCase struct {
+		repository         Repository
+		merchantRepository MerchantRepository
+		outboxPublisher    OutboxPublisher
+		log                *slog.Logger
+	}
+)
+
+func New(repository Repository, merchantRepository MerchantRepository, outboxPublisher OutboxPublisher, log *slog.Logger) UseCase {
+	return UseCase{
+		repository:         repository,
+		merchantRepository: merchantRepository,
+		outboxPublisher:    outboxPublisher,
+		log:                log,
+	}
+}
+
+// Execute approves the cashback held while the user had no verified wallet and
+// publishes cashback.approved so it is minted to walletAddress. It returns the
+// number of released entries.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, walletAddress string) (int, error) {
+	held, err := u.repository.FindHeldByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, cashback := range held {
+		ctx := logger.WithCashbackID(ctx, cashback.ID.String())
+		approved, err := u.repository.ApproveHeld(ctx, cashback.ID, walletAddress)
+		if err != nil {
+			return released, err
+		}
+		if !approved {
+			continue
+		}
+		cashback.Approve(walletAddress)
+
+		event := calculatecashback.NewCashbackApprovedEvent(cashback)
+		if cashback.MerchantID != uuid.Nil {
+			merchant, err := u.merchantRepository.FindByID(ctx, cashback.MerchantID)
+			if err != nil {
+				u.log.WarnContext(ctx, "funding account lookup failed", "merchant_id", cashback.MerchantID, "error", err)
+			} else {
+				event.FundingAccount = merchant.FundingAccount
+			}
+		}
+
+		if err := u.outboxPublisher.Publish(ctx, calculatecashback.EventTypeCashbackApproved, event); err != nil {
+			return released, err
+		}
+		released++
+	}
+
+	return released, nil
+}
@@ -0,0 +1,129 @@
+// Package settlecashback records the outcome of the Mint Consumer's mints in
+// the ledger: approved cashback becomes minted when its tokens are minted, or
+// failed once the Mint Consumer stops retrying.
+package settlecashback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	SubjectTokenMinted     = "token.minted"
+	SubjectTokenMintFailed = "token.mint.failed"
+)
+
+type (
+	// Repository interface for settling cashback
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.Cashback, error)
+		MarkMinted(ctx context.Context, id uuid.UUID) (bool, error)
+		MarkMintFailed(ctx context.Context, id uuid.UUID) (bool, error)
+	}
+
+	UseCase struct {
+		repository Repository
+		log        *slog.Logger
+	}
+
+	// TokenMintedEvent holds the fields of the token.minted event published by
+	// the Mint Consumer
+	TokenMintedEvent struct {
+		Data struct {
+			MintRequestID   uuid.UUID `json:"mint_request_id"`
+			CashbackID      uuid.UUID `json:"cashback_id"`
+			TransactionHash string    `json:"transaction_hash"`
+		} `json:"data"`
+	}
+
+	// TokenMintFailedEvent holds the fields of the token.mint.failed event
+	// published by the Mint Consumer. NextRetryAt is empty once the Mint
+	// Consumer stopped retrying.
+	TokenMintFailedEvent struct {
+		Data struct {
+			MintRequestID uuid.UUID  `json:"mint_request_id"`
+			CashbackID    uuid.UUID  `json:"cashback_id"`
+			ErrorCode     string     `json:"error_code"`
+			NextRetryAt   *time.Time `json:"next_retry_at,omitempty"`
+		} `json:"data"`
+	}
+)
+
+func New(repository Repository, log *slog.Logger) UseCase {
+	return UseCase{
+		repository: repository,
+		log:        log,
+	}
+}
+
+// ProcessTokenMinted moves the minted cashback to minted. A redelivered event
+// finds it minted already. Tokens minted for cashback that expired meanwhile
+// are only logged; reconciliation reports them as mint_not_recorded.
+func (u UseCase) ProcessTokenMinted(ctx context.Context, data []byte) error {
+	var event TokenMintedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", SubjectTokenMinted, err)
+	}
+	ctx = logger.WithCashbackID(ctx, event.Data.CashbackID.String())
+
+	minted, err := u.repository.MarkMinted(ctx, event.Data.CashbackID)
+	if err != nil {
+		return err
+	}
+	if minted {
+		u.log.InfoContext(ctx, "cashback minted", "mint_request_id", event.Data.MintRequestID, "transaction_hash", event.Data.TransactionHash)
+		return nil
+	}
+
+	return u.unsettled(ctx, event.Data.CashbackID, domain.StatusMinted)
+}
+
+// ProcessTokenMintFailed moves approved cashback to failed once the Mint
+// Consumer stopped retrying its mint, so it can expire. Failed attempts that
+// will be retried leave it approved.
+func (u UseCase) ProcessTokenMintFailed(ctx context.Context, data []byte) error {
+	var event TokenMintFailedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", SubjectTokenMintFailed, err)
+	}
+	ctx = logger.WithCashbackID(ctx, event.Data.CashbackID.String())
+
+	if event.Data.NextRetryAt != nil {
+		return nil
+	}
+
+	failed, err := u.repository.MarkMintFailed(ctx, event.Data.CashbackID)
+	if err != nil {
+		return err
+	}
+	if failed {
+		u.log.WarnContext(ctx, "cashback mint failed", "mint_request_id", event.Data.MintRequestID, "error_code", event.Data.ErrorCode)
+		return nil
+	}
+
+	return u.unsettled(ctx, event.Data.CashbackID, domain.StatusFailed)
+}
+
+// unsettled logs why cashback could not be moved to status. Cashback already
+// in that status was settled by an earlier delivery of the event.
+func (u UseCase) unsettled(ctx context.Context, id uuid.UUID, status string) error {
+	cashback, err := u.repository.FindByID(ctx, id)
+	switch {
+	case errors.Is(err, domain.ErrCashbackNotFound):
+		u.log.WarnContext(ctx, "mint outcome for unknown cashback", "status", status)
+		return nil
+	case err != nil:
+		return err
+	case cashback.Status != status:
+		u.log.WarnContext(ctx, "mint outcome does not apply to cashback", "status", cashback.Status, "outcome", status)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package settlecashback_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/settlecashback"
+	"github.com/google/uuid"
+)
+
+type fixture struct {
+	cashback *repository.Memory
+	usecase  settlecashback.UseCase
+}
+
+func newFixture() *fixture {
+	f := &fixture{cashback: repository.NewMemory()}
+	f.usecase = settlecashback.New(f.cashback, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+func (f *fixture) cashbackIn(t *testing.T, status string) uuid.UUID {
+	t.Helper()
+	cashback, err := f.cashback.Create(context.Background(), domain.Cashback{
+		UserID:     uuid.New(),
+		PurchaseID: uuid.New(),
+		MerchantID: uuid.New(),
+		Amount:     5,
+		BaseAmount: 5,
+		Status:     status,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cashback.ID
+}
+
+func (f *fixture) status(t *testing.T, id uuid.UUID) string {
+	t.Helper()
+	cashback, err := f.cashback.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cashback.Status
+}
+
+func minted(t *testing.T, cashbackID uuid.UUID) []byte {
+	t.Helper()
+	var event settlecashback.TokenMintedEvent
+	event.Data.MintRequestID = uuid.New()
+	event.Data.CashbackID = cashbackID
+	event.Data.TransactionHash = "0xabc"
+	return encode(t, event)
+}
+
+func mintFailed(t *testing.T, cashbackID uuid.UUID, nextRetryAt *time.Time) []byte {
+	t.Helper()
+	var event settlecashback.TokenMintFailedEvent
+	event.Data.MintRequestID = uuid.New()
+	event.Data.CashbackID = cashbackID
+	event.Data.ErrorCode = "MINT_FAILED"
+	event.Data.NextRetryAt = nextRetryAt
+	return encode(t, event)
+}
+
+func encode(t *testing.T, event any) []byte {
+	t.Helper()
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestTokenMintedMarksCashbackMinted(t *testing.T) {
+	for _, tc := range []struct {
+		status string
+		want   string
+	}{
+		{domain.StatusApproved, domain.StatusMinted},
+		// A failed mint retried by hand.
+		{domain.StatusFailed, domain.StatusMinted},
+		{domain.StatusMinted, domain.StatusMinted},
+		// Expired meanwhile; reconciliation reports the mint.
+		{domain.StatusExpired, domain.StatusExpired},
+		{domain.StatusPending, domain.StatusPending},
+	} {
+		t.Run(tc.status, func(t *testing.T) {
+			f := newFixture()
+			id := f.cashbackIn(t, tc.status)
+
+			for range 2 {
+				if err := f.usecase.ProcessTokenMinted(context.Background(), minted(t, id)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := f.status(t, id); got != tc.want {
+				t.Fatalf("status = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTokenMintFailedMarksCashbackFailedOnceRetriesStop(t *testing.T) {
+	f := newFixture()
+	id := f.cashbackIn(t, domain.StatusApproved)
+
+	retryAt := time.Now().Add(time.Minute)
+	if err := f.usecase.ProcessTokenMintFailed(context.Background(), mintFailed(t, id, &retryAt)); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.status(t, id); got != domain.StatusApproved {
+		t.Fatalf("status after a retryable failure = %q, want approved", got)
+	}
+
+	if err := f.usecase.ProcessTokenMintFailed(context.Background(), mintFailed(t, id, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.status(t, id); got != domain.StatusFailed {
+		t.Fatalf("status after the last failure = %q, want failed", got)
+	}
+}
+
+func TestTokenMintFailedLeavesSettledCashbackAlone(t *testing.T) {
+	for _, status := range []string{domain.StatusMinted, domain.StatusExpired, domain.StatusFailed} {
+		t.Run(status, func(t *testing.T) {
+			f := newFixture()
+			id := f.cashbackIn(t, status)
+
+			if err := f.usecase.ProcessTokenMintFailed(context.Background(), mintFailed(t, id, nil)); err != nil {
+				t.Fatal(err)
+			}
+			if got := f.status(t, id); got != status {
+				t.Fatalf("status = %q, want %q", got, status)
+			}
+		})
+	}
+}
+
+func TestMintOutcomesForUnknownCashbackAreDropped(t *testing.T) {
+	f := newFixture()
+	if err := f.usecase.ProcessTokenMinted(context.Background(), minted(t, uuid.New())); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.usecase.ProcessTokenMintFailed(context.Background(), mintFailed(t, uuid.New(), nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.usecase.ProcessTokenMinted(context.Background(), []byte("{")); err == nil {
+		t.Fatal("decoded a malformed event")
+	}
+}
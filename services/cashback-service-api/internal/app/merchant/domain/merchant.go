@@ -0,0 +1,107 @@
+// Package domain contains the core business entities and rules for merchants.
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Merchant status values control whether a merchant can accept new purchases.
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+)
+
+// Sentinel errors for merchant domain validation.
+var (
+	ErrInvalidName           = errors.New("invalid merchant name")
+	ErrInvalidCategoryCode   = errors.New("invalid merchant category code")
+	ErrInvalidPercentage     = errors.New("invalid cashback percentage")
+	ErrInvalidFundingAccount = errors.New("invalid funding account")
+	ErrInvalidStatus         = errors.New("invalid merchant status")
+	ErrMerchantNotFound      = errors.New("merchant not found")
+)
+
+// Merchant represents a business where users earn cashback.
+// The merchant funds the cashback it offers from its funding account,
+// so its default rate is used as the base rate for every purchase.
+type Merchant struct {
+	ID              uuid.UUID
+	Name            string
+	CategoryCode    string
+	Status          string
+	CashbackPercent float64
+	FundingAccount  string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewMerchant creates a new active merchant with validation.
+// The category code must be a four digit ISO 18245 merchant category code (MCC).
+func NewMerchant(name, categoryCode string, cashbackPercent float64, fundingAccount string) (Merchant, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Merchant{}, ErrInvalidName
+	}
+	if !IsValidCategoryCode(categoryCode) {
+		return Merchant{}, ErrInvalidCategoryCode
+	}
+	if cashbackPercent <= 0 || cashbackPercent > 100 {
+		return Merchant{}, ErrInvalidPercentage
+	}
+	fundingAccount = strings.TrimSpace(fundingAccount)
+	if fundingAccount == "" {
+		return Merchant{}, ErrInvalidFundingAccount
+	}
+
+	now := time.Now().UTC()
+	return Merchant{
+		ID:              uuid.New(),
+		Name:            name,
+		CategoryCode:    categoryCode,
+		Status:          StatusActive,
+		CashbackPercent: cashbackPercent,
+		FundingAccount:  fundingAccount,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}, nil
+}
+
+// IsValidCategoryCode reports whether code is a four digit merchant category code.
+func IsValidCategoryCode(code string) bool {
+	if len(code) != 4 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidStatus reports whether status is a known merchant status.
+func IsValidStatus(status string) bool {
+	return status == StatusActive || status == StatusSuspended
+}
+
+// IsActive reports whether the merchant can accept new purchases.
+func (m Merchant) IsActive() bool {
+	return m.Status == StatusActive
+}
+
+// Activate transitions the merchant to active status.
+func (m *Merchant) Activate() {
+	m.Status = StatusActive
+	m.UpdatedAt = time.Now().UTC()
+}
+
+// Suspend transitions the merchant to suspended status.
+// Suspended merchants cannot receive purchases or fund cashback.
+func (m *Merchant) Suspend() {
+	m.Status = StatusSuspended
+	m.UpdatedAt = time.Now().UTC()
+}
@@ -0,0 +1,56 @@
+package createmerchant
+
+import (
+	"strings"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+)
+
+type (
+	InputPayload struct {
+		Name            string  `json:"name"`
+		CategoryCode    string  `json:"category_code"`
+		CashbackPercent float64 `json:"cashback_percent"`
+		FundingAccount  string  `json:"funding_account"`
+	}
+
+	OutputPayload struct {
+		ID              string  `json:"id"`
+		Name            string  `json:"name"`
+		CategoryCode    string  `json:"category_code"`
+		Status          string  `json:"status"`
+		CashbackPercent float64 `json:"cashback_percent"`
+		FundingAccount  string  `json:"funding_account"`
+		CreatedAt       string  `json:"created_at"`
+	}
+)
+
+func (p InputPayload) Validate() error {
+	var v errorhandler.ValidationError
+	if strings.TrimSpace(p.Name) == "" {
+		v.Add("name", domain.ErrInvalidName)
+	}
+	if !domain.IsValidCategoryCode(p.CategoryCode) {
+		v.Add("category_code", domain.ErrInvalidCategoryCode)
+	}
+	if p.CashbackPercent <= 0 || p.CashbackPercent > 100 {
+		v.Add("cashback_percent", domain.ErrInvalidPercentage)
+	}
+	if strings.TrimSpace(p.FundingAccount) == "" {
+		v.Add("funding_account", domain.ErrInvalidFundingAccount)
+	}
+	return v.Err()
+}
+
+func ToOutputPayload(merchant domain.Merchant) OutputPayload {
+	return OutputPayload{
+		ID:              merchant.ID.String(),
+		Name:            merchant.Name,
+		CategoryCode:    merchant.CategoryCode,
+		Status:          merchant.Status,
+		CashbackPercent: merchant.CashbackPercent,
+		FundingAccount:  merchant.FundingAccount,
+		CreatedAt:       merchant.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
@@ -0,0 +1,66 @@
+package createmerchant
+
+import (
+	"net/http"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/createmerchant"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/merchants"
+
+var Operation = openapi.Operation{
+	ID:        "createMerchant",
+	Method:    http.MethodPost,
+	Path:      Path,
+	Summary:   "Register a merchant",
+	Tag:       "merchants",
+	Request:   InputPayload{},
+	Responses: map[int]any{http.StatusCreated: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase createmerchant.UseCase
+}
+
+func NewHandler(useCase createmerchant.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.Require(auth.RoleAdmin)).Post(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	var payload InputPayload
+	if err := httpjson.ReadJSON(r, &payload); err != nil {
+		errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	merchant, err := h.useCase.Execute(
+		r.Context(),
+		payload.Name,
+		payload.CategoryCode,
+		payload.CashbackPercent,
+		payload.FundingAccount,
+	)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusCreated, ToOutputPayload(merchant))
+}
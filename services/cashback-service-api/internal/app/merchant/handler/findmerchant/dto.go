@@ -0,0 +1,27 @@
+package findmerchant
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+)
+
+type OutputPayload struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	CategoryCode    string  `json:"category_code"`
+	Status          string  `json:"status"`
+	CashbackPercent float64 `json:"cashback_percent"`
+	FundingAccount  string  `json:"funding_account"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+func ToOutputPayload(merchant domain.Merchant) OutputPayload {
+	return OutputPayload{
+		ID:              merchant.ID.String(),
+		Name:            merchant.Name,
+		CategoryCode:    merchant.CategoryCode,
+		Status:          merchant.Status,
+		CashbackPercent: merchant.CashbackPercent,
+		FundingAccount:  merchant.FundingAccount,
+		CreatedAt:       merchant.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
@@ -0,0 +1,55 @@
+package findmerchant
+
+import (
+	"net/http"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/findmerchant"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/merchants/{id}"
+
+var Operation = openapi.Operation{
+	ID:        "getMerchant",
+	Method:    http.MethodGet,
+	Path:      Path,
+	Summary:   "Get a merchant",
+	Tag:       "merchants",
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase findmerchant.UseCase
+}
+
+func NewHandler(useCase findmerchant.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.Require(auth.RolePartner, auth.RoleAdmin)).Get(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	merchant, err := h.useCase.Execute(r.Context(), id)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusOK, ToOutputPayload(merchant))
+}
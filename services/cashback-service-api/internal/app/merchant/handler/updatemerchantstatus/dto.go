@@ -0,0 +1,33 @@
+package updatemerchantstatus
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+)
+
+type (
+	InputPayload struct {
+		Status string `json:"status"`
+	}
+
+	OutputPayload struct {
+		ID        string `json:"id"`
+		Status    string `json:"status"`
+		UpdatedAt string `json:"updated_at"`
+	}
+)
+
+func (p InputPayload) Validate() error {
+	if !domain.IsValidStatus(p.Status) {
+		return errorhandler.Invalid("status", domain.ErrInvalidStatus)
+	}
+	return nil
+}
+
+func ToOutputPayload(merchant domain.Merchant) OutputPayload {
+	return OutputPayload{
+		ID:        merchant.ID.String(),
+		Status:    merchant.Status,
+		UpdatedAt: merchant.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
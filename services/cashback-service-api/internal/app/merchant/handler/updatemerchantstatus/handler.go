@@ -0,0 +1,67 @@
+package updatemerchantstatus
+
+import (
+	"net/http"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/updatemerchantstatus"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/merchants/{id}/status"
+
+var Operation = openapi.Operation{
+	ID:        "updateMerchantStatus",
+	Method:    http.MethodPut,
+	Path:      Path,
+	Summary:   "Change a merchant's status",
+	Tag:       "merchants",
+	Request:   InputPayload{},
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase updatemerchantstatus.UseCase
+}
+
+func NewHandler(useCase updatemerchantstatus.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.Require(auth.RoleAdmin)).Put(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	var payload InputPayload
+	if err := httpjson.ReadJSON(r, &payload); err != nil {
+		errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	merchant, err := h.useCase.Execute(r.Context(), id, payload.Status)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusOK, ToOutputPayload(merchant))
+}
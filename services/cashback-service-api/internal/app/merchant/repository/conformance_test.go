@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/createmerchant"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/updatemerchantstatus"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database/databasetest"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The same suite runs against the in-memory repository and the Postgres one,
+// so the two cannot drift apart. The Postgres run needs TEST_DATABASE_URL and
+// is skipped without it.
+
+type merchantRepository interface {
+	createmerchant.Repository
+	updatemerchantstatus.Repository
+}
+
+func TestMemory(t *testing.T) {
+	testRepository(t, func(t *testing.T) merchantRepository {
+		return repository.NewMemory()
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	testRepository(t, func(t *testing.T) merchantRepository {
+		return repository.New(databasetest.New(t))
+	})
+}
+
+func testRepository(t *testing.T, newRepo func(t *testing.T) merchantRepository) {
+	ctx := context.Background()
+
+	t.Run("created merchants are found by ID", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.Create(ctx, newMerchant(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByID(ctx, created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ID != created.ID || found.Name != created.Name || found.CategoryCode != created.CategoryCode ||
+			found.Status != domain.StatusActive || found.CashbackPercent != created.CashbackPercent ||
+			found.FundingAccount != created.FundingAccount {
+			t.Fatalf("found %+v, want %+v", found, created)
+		}
+	})
+
+	t.Run("missing merchants are not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindByID(ctx, uuid.New())
+		if !errors.Is(err, domain.ErrMerchantNotFound) {
+			t.Fatalf("error = %v, want %v", err, domain.ErrMerchantNotFound)
+		}
+	})
+
+	t.Run("IDs are unique", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first, err := repo.Create(ctx, newMerchant(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		merchant := newMerchant(t)
+		merchant.ID = first.ID
+		if _, err := repo.Create(ctx, merchant); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("error = %v, want %v", err, gorm.ErrDuplicatedKey)
+		}
+	})
+
+	t.Run("updates are saved", func(t *testing.T) {
+		repo := newRepo(t)
+
+		merchant, err := repo.Create(ctx, newMerchant(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		merchant.Suspend()
+		if err := repo.Update(ctx, merchant); err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByID(ctx, merchant.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Status != domain.StatusSuspended {
+			t.Fatalf("status = %q, want %q", found.Status, domain.StatusSuspended)
+		}
+	})
+}
+
+func newMerchant(t *testing.T) domain.Merchant {
+	t.Helper()
+	merchant, err := domain.NewMerchant("Corner Shop", "5411", 2.5, "acct-"+uuid.NewString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return merchant
+}
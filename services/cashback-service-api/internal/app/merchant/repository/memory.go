@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Memory keeps merchants in memory, for tests of the usecases that register
+// and look up merchants without a database. Like the merchants table, it fills
+// in the ID, status and timestamps left empty and refuses a second merchant
+// with the same ID.
+type Memory struct {
+	mu        sync.RWMutex
+	merchants map[uuid.UUID]domain.Merchant
+}
+
+// NewMemory creates an empty in-memory merchant repository.
+func NewMemory() *Memory {
+	return &Memory{merchants: make(map[uuid.UUID]domain.Merchant)}
+}
+
+func (m *Memory) Create(_ context.Context, merchant domain.Merchant) (domain.Merchant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if merchant.ID == uuid.Nil {
+		merchant.ID = uuid.New()
+	}
+	if merchant.Status == "" {
+		merchant.Status = domain.StatusActive
+	}
+	if _, ok := m.merchants[merchant.ID]; ok {
+		return domain.Merchant{}, gorm.ErrDuplicatedKey
+	}
+
+	now := time.Now()
+	if merchant.CreatedAt.IsZero() {
+		merchant.CreatedAt = now
+	}
+	if merchant.UpdatedAt.IsZero() {
+		merchant.UpdatedAt = now
+	}
+	m.merchants[merchant.ID] = merchant
+	return merchant, nil
+}
+
+func (m *Memory) FindByID(_ context.Context, id uuid.UUID) (domain.Merchant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	merchant, ok := m.merchants[id]
+	if !ok {
+		return domain.Merchant{}, domain.ErrMerchantNotFound
+	}
+	return merchant, nil
+}
+
+// Update saves merchant, inserting it when it does not exist yet, as Save does.
+func (m *Memory) Update(_ context.Context, merchant domain.Merchant) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	merchant.UpdatedAt = time.Now()
+	if merchant.CreatedAt.IsZero() {
+		merchant.CreatedAt = merchant.UpdatedAt
+	}
+	m.merchants[merchant.ID] = merchant
+	return nil
+}
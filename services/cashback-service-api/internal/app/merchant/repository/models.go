@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/google/uuid"
+)
+
+// merchantModel represents the database model for merchants
+type merchantModel struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name            string    `gorm:"not null"`
+	CategoryCode    string    `gorm:"type:varchar(4);not null;index"`
+	Status          string    `gorm:"not null;default:'active';index"`
+	CashbackPercent float64   `gorm:"not null"`
+	FundingAccount  string    `gorm:"not null"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+func (merchantModel) TableName() string {
+	return "merchants"
+}
+
+// toDomain converts database model to domain entity
+func (m merchantModel) toDomain() domain.Merchant {
+	return domain.Merchant{
+		ID:              m.ID,
+		Name:            m.Name,
+		CategoryCode:    m.CategoryCode,
+		Status:          m.Status,
+		CashbackPercent: m.CashbackPercent,
+		FundingAccount:  m.FundingAccount,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
+
+// fromDomain converts domain entity to database model
+func fromDomain(merchant domain.Merchant) merchantModel {
+	return merchantModel{
+		ID:              merchant.ID,
+		Name:            merchant.Name,
+		CategoryCode:    merchant.CategoryCode,
+		Status:          merchant.Status,
+		CashbackPercent: merchant.CashbackPercent,
+		FundingAccount:  merchant.FundingAccount,
+		CreatedAt:       merchant.CreatedAt,
+		UpdatedAt:       merchant.UpdatedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r Repository) FindByID(ctx context.Context, id uuid.UUID) (domain.Merchant, error) {
+	var merchant merchantModel
+
+	err := r.db.WithContext(ctx).First(&merchant, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Merchant{}, domain.ErrMerchantNotFound
+		}
+		return domain.Merchant{}, err
+	}
+
+	return merchant.toDomain(), nil
+}
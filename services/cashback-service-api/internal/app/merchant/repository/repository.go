@@ -0,0 +1,19 @@
+// Package repository implements data persistence for merchant entities.
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// Repository handles merchant data persistence operations.
+// It provides methods for both reading and writing merchant records.
+type Repository struct {
+	db *gorm.DB
+}
+
+// New creates a new merchant repository instance.
+func New(db *gorm.DB) Repository {
+	return Repository{
+		db: db,
+	}
+}
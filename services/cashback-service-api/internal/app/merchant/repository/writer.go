@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+)
+
+func (r Repository) Create(ctx context.Context, merchant domain.Merchant) (domain.Merchant, error) {
+	model := fromDomain(merchant)
+
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return domain.Merchant{}, err
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r Repository) Update(ctx context.Context, merchant domain.Merchant) error {
+	model := fromDomain(merchant)
+	return r.db.WithContext(ctx).Save(&model).Error
+}
@@ -0,0 +1,37 @@
+package createmerchant
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+)
+
+type (
+	Repository interface {
+		Create(ctx context.Context, merchant domain.Merchant) (domain.Merchant, error)
+	}
+
+	UseCase struct {
+		repository Repository
+	}
+)
+
+func New(repository Repository) UseCase {
+	return UseCase{
+		repository: repository,
+	}
+}
+
+func (u UseCase) Execute(
+	ctx context.Context,
+	name, categoryCode string,
+	cashbackPercent float64,
+	fundingAccount string,
+) (domain.Merchant, error) {
+	merchant, err := domain.NewMerchant(name, categoryCode, cashbackPercent, fundingAccount)
+	if err != nil {
+		return domain.Merchant{}, err
+	}
+
+	return u.repository.Create(ctx, merchant)
+}
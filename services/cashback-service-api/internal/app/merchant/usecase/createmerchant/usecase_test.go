@@ -0,0 +1,54 @@
+package createmerchant_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/createmerchant"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name           string
+		merchantName   string
+		categoryCode   string
+		percent        float64
+		fundingAccount string
+		wantErr        error
+	}{
+		{name: "valid", merchantName: " Corner Shop ", categoryCode: "5411", percent: 2.5, fundingAccount: "acct-1"},
+		{name: "whole purchase back", merchantName: "Corner Shop", categoryCode: "5411", percent: 100, fundingAccount: "acct-1"},
+		{name: "blank name", merchantName: "  ", categoryCode: "5411", percent: 2.5, fundingAccount: "acct-1", wantErr: domain.ErrInvalidName},
+		{name: "short category code", merchantName: "Corner Shop", categoryCode: "541", percent: 2.5, fundingAccount: "acct-1", wantErr: domain.ErrInvalidCategoryCode},
+		{name: "non-numeric category code", merchantName: "Corner Shop", categoryCode: "54a1", percent: 2.5, fundingAccount: "acct-1", wantErr: domain.ErrInvalidCategoryCode},
+		{name: "no cashback", merchantName: "Corner Shop", categoryCode: "5411", percent: 0, fundingAccount: "acct-1", wantErr: domain.ErrInvalidPercentage},
+		{name: "more than the purchase", merchantName: "Corner Shop", categoryCode: "5411", percent: 100.5, fundingAccount: "acct-1", wantErr: domain.ErrInvalidPercentage},
+		{name: "no funding account", merchantName: "Corner Shop", categoryCode: "5411", percent: 2.5, fundingAccount: " ", wantErr: domain.ErrInvalidFundingAccount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := repository.NewMemory()
+
+			merchant, err := createmerchant.New(repo).Execute(ctx, tt.merchantName, tt.categoryCode, tt.percent, tt.fundingAccount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			found, err := repo.FindByID(ctx, merchant.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.Name != "Corner Shop" || found.Status != domain.StatusActive || found.CashbackPercent != tt.percent {
+				t.Fatalf("stored %+v", found)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package findmerchant
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.Merchant, error)
+	}
+
+	UseCase struct {
+		repository Repository
+	}
+)
+
+func New(repository Repository) UseCase {
+	return UseCase{
+		repository: repository,
+	}
+}
+
+func (u UseCase) Execute(ctx context.Context, id uuid.UUID) (domain.Merchant, error) {
+	return u.repository.FindByID(ctx, id)
+}
@@ -0,0 +1,37 @@
+package findmerchant_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/findmerchant"
+	"github.com/google/uuid"
+)
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemory()
+	merchant, err := domain.NewMerchant("Corner Shop", "5411", 2.5, "acct-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(ctx, merchant); err != nil {
+		t.Fatal(err)
+	}
+	usecase := findmerchant.New(repo)
+
+	found, err := usecase.Execute(ctx, merchant.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != merchant.ID || found.Name != merchant.Name {
+		t.Fatalf("found %+v, want %+v", found, merchant)
+	}
+
+	if _, err := usecase.Execute(ctx, uuid.New()); !errors.Is(err, domain.ErrMerchantNotFound) {
+		t.Fatalf("error = %v, want %v", err, domain.ErrMerchantNotFound)
+	}
+}
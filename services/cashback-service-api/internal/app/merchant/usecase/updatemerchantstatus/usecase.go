@@ -0,0 +1,55 @@
+package updatemerchantstatus
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.Merchant, error)
+		Update(ctx context.Context, merchant domain.Merchant) error
+	}
+
+	UseCase struct {
+		repository Repository
+	}
+)
+
+func New(repository Repository) UseCase {
+	return UseCase{
+		repository: repository,
+	}
+}
+
+// Execute activates or suspends a merchant.
+// Suspending a merchant blocks new purchases and cashback funded by it.
+func (u UseCase) Execute(ctx context.Context, id uuid.UUID, status string) (domain.Merchant, error) {
+	if !domain.IsValidStatus(status) {
+		return domain.Merchant{}, domain.ErrInvalidStatus
+	}
+
+	merchant, err := u.repository.FindByID(ctx, id)
+	if err != nil {
+		return domain.Merchant{}, err
+	}
+
+	if merchant.Status == status {
+		return merchant, nil
+	}
+
+	switch status {
+	case domain.StatusActive:
+		merchant.Activate()
+	case domain.StatusSuspended:
+		merchant.Suspend()
+	}
+
+	if err := u.repository.Update(ctx, merchant); err != nil {
+		return domain.Merchant{}, err
+	}
+
+	return merchant, nil
+}
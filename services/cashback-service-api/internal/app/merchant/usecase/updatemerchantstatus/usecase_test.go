@@ -0,0 +1,68 @@
+package updatemerchantstatus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/usecase/updatemerchantstatus"
+	"github.com/google/uuid"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr error
+	}{
+		{name: "suspend", from: domain.StatusActive, to: domain.StatusSuspended},
+		{name: "reactivate", from: domain.StatusSuspended, to: domain.StatusActive},
+		{name: "already active", from: domain.StatusActive, to: domain.StatusActive},
+		{name: "already suspended", from: domain.StatusSuspended, to: domain.StatusSuspended},
+		{name: "unknown status", from: domain.StatusActive, to: "closed", wantErr: domain.ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := repository.NewMemory()
+			merchant, err := domain.NewMerchant("Corner Shop", "5411", 2.5, "acct-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			merchant.Status = tt.from
+			if _, err := repo.Create(ctx, merchant); err != nil {
+				t.Fatal(err)
+			}
+
+			updated, err := updatemerchantstatus.New(repo).Execute(ctx, merchant.ID, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			want := tt.to
+			if tt.wantErr != nil {
+				want = tt.from
+			} else if updated.Status != want {
+				t.Fatalf("returned status %q, want %q", updated.Status, want)
+			}
+			found, err := repo.FindByID(ctx, merchant.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.Status != want {
+				t.Fatalf("stored status %q, want %q", found.Status, want)
+			}
+		})
+	}
+}
+
+func TestExecuteUnknownMerchant(t *testing.T) {
+	_, err := updatemerchantstatus.New(repository.NewMemory()).Execute(context.Background(), uuid.New(), domain.StatusSuspended)
+	if !errors.Is(err, domain.ErrMerchantNotFound) {
+		t.Fatalf("error = %v, want %v", err, domain.ErrMerchantNotFound)
+	}
+}
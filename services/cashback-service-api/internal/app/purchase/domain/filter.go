@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses lists every purchase status.
+var Statuses = []string{StatusPending, StatusRefunded}
+
+var (
+	ErrInvalidStatusFilter = errors.New("invalid purchase status filter")
+	ErrInvalidDateRange    = errors.New("from must be before to")
+)
+
+// Filter narrows a listing of purchases. Zero fields match everything; From
+// is inclusive and To exclusive.
+type Filter struct {
+	Statuses   []string
+	MerchantID uuid.UUID
+	From       time.Time
+	To         time.Time
+}
+
+// Validate checks that the filter only names known statuses and that its
+// date range is not empty.
+func (f Filter) Validate() error {
+	for _, status := range f.Statuses {
+		if !slices.Contains(Statuses, status) {
+			return ErrInvalidStatusFilter
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
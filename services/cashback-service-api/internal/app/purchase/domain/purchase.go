@@ -8,11 +8,19 @@ import (
 	"github.com/google/uuid"
 )
 
+// Purchase statuses.
+const (
+	StatusPending  = "pending"
+	StatusRefunded = "refunded"
+)
+
 // Sentinel errors for purchase domain validation.
 var (
-	ErrInvalidAmount   = errors.New("invalid purchase amount")
-	ErrInvalidUserID   = errors.New("invalid user ID")
-	ErrInvalidMerchant = errors.New("invalid merchant ID")
+	ErrInvalidAmount    = errors.New("invalid purchase amount")
+	ErrInvalidUserID    = errors.New("invalid user ID")
+	ErrInvalidMerchant  = errors.New("invalid merchant ID")
+	ErrPurchaseNotFound = errors.New("purchase not found")
+	ErrAlreadyRefunded  = errors.New("purchase already refunded")
 )
 
 // Purchase represents a purchase transaction in the system.
@@ -21,7 +29,7 @@ type Purchase struct {
 	ID         uuid.UUID
 	UserID     uuid.UUID
 	Amount     float64
-	MerchantID string
+	MerchantID uuid.UUID
 	Status     string
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
@@ -29,15 +37,31 @@ type Purchase struct {
 
 // NewPurchase creates a new purchase instance.
 // Status is initialized as "pending" by default.
-func NewPurchase(userID uuid.UUID, amount float64, merchant string) Purchase {
+func NewPurchase(userID uuid.UUID, amount float64, merchantID uuid.UUID) Purchase {
 	now := time.Now().UTC()
 	return Purchase{
 		ID:         uuid.New(),
 		UserID:     userID,
 		Amount:     amount,
-		MerchantID: merchant,
-		Status:     "pending",
+		MerchantID: merchantID,
+		Status:     StatusPending,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
 }
+
+// IsRefunded reports whether the purchase has been refunded.
+func (p Purchase) IsRefunded() bool {
+	return p.Status == StatusRefunded
+}
+
+// Refund marks the purchase as refunded.
+// Refunded purchases no longer count towards the user's loyalty tier.
+func (p *Purchase) Refund() error {
+	if p.IsRefunded() {
+		return ErrAlreadyRefunded
+	}
+	p.Status = StatusRefunded
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
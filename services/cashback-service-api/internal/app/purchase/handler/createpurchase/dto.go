@@ -2,13 +2,14 @@ package createpurchase
 
 import (
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 )
 
 type (
 	InputPayload struct {
-		UserID   string  `json:"user_id"`
-		Amount   float64 `json:"amount"`
-		Merchant string  `json:"merchant"`
+		UserID     string  `json:"user_id" format:"uuid"`
+		Amount     float64 `json:"amount"`
+		MerchantID string  `json:"merchant_id" format:"uuid"`
 	}
 
 	OutputPayload struct {
@@ -22,16 +23,17 @@ type (
 )
 
 func (p InputPayload) Validate() error {
+	var v errorhandler.ValidationError
 	if p.UserID == "" {
-		return domain.ErrInvalidUserID
+		v.Add("user_id", domain.ErrInvalidUserID)
 	}
 	if p.Amount <= 0 {
-		return domain.ErrInvalidAmount
+		v.Add("amount", domain.ErrInvalidAmount)
 	}
-	if p.Merchant == "" {
-		return domain.ErrInvalidMerchant
+	if p.MerchantID == "" {
+		v.Add("merchant_id", domain.ErrInvalidMerchant)
 	}
-	return nil
+	return v.Err()
 }
 
 func ToOutputPayload(purchase domain.Purchase) OutputPayload {
@@ -39,7 +41,7 @@ func ToOutputPayload(purchase domain.Purchase) OutputPayload {
 		ID:         purchase.ID.String(),
 		UserID:     purchase.UserID.String(),
 		Amount:     purchase.Amount,
-		MerchantID: purchase.MerchantID,
+		MerchantID: purchase.MerchantID.String(),
 		Status:     purchase.Status,
 		CreatedAt:  purchase.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
@@ -4,7 +4,10 @@ import (
 	"net/http"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/createpurchase"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -12,6 +15,16 @@ import (
 
 const Path = "/purchases"
 
+var Operation = openapi.Operation{
+	ID:        "createPurchase",
+	Method:    http.MethodPost,
+	Path:      Path,
+	Summary:   "Record a purchase",
+	Tag:       "purchases",
+	Request:   InputPayload{},
+	Responses: map[int]any{http.StatusCreated: OutputPayload{}},
+}
+
 type Handler struct {
 	useCase createpurchase.UseCase
 }
@@ -23,30 +36,42 @@ func NewHandler(useCase createpurchase.UseCase) Handler {
 }
 
 func RegisterEndpoint(r chi.Router, h Handler) {
-	r.Post(Path, h.Handle)
+	r.With(auth.Require(auth.RolePartner, auth.RoleAdmin)).Post(Path, h.Handle)
 }
 
 func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var payload InputPayload
 	if err := httpjson.ReadJSON(r, &payload); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
 		return
 	}
 
 	if err := payload.Validate(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		errorhandler.Render(w, r, err)
 		return
 	}
 
 	userID, err := uuid.Parse(payload.UserID)
 	if err != nil {
-		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		errorhandler.Render(w, r, errorhandler.Invalid("user_id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	merchantID, err := uuid.Parse(payload.MerchantID)
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("merchant_id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	// Partners record purchases at their own merchant only
+	if !auth.ActsForMerchant(r.Context(), merchantID.String()) {
+		errorhandler.Render(w, r, errorhandler.ErrForbidden)
 		return
 	}
 
-	purchase, err := h.useCase.Execute(r.Context(), userID, payload.Amount, payload.Merchant)
+	purchase, err := h.useCase.Execute(r.Context(), userID, payload.Amount, merchantID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		errorhandler.Render(w, r, err)
 		return
 	}
 
@@ -18,7 +18,7 @@ func ToOutputPayload(purchase domain.Purchase) OutputPayload {
 		ID:         purchase.ID.String(),
 		UserID:     purchase.UserID.String(),
 		Amount:     purchase.Amount,
-		MerchantID: purchase.MerchantID,
+		MerchantID: purchase.MerchantID.String(),
 		Status:     purchase.Status,
 		CreatedAt:  purchase.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
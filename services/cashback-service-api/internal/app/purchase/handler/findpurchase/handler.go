@@ -4,7 +4,10 @@ import (
 	"net/http"
 
 	findpurchaseuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/findpurchase"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
 	"github.com/google/uuid"
 
 	"github.com/go-chi/chi/v5"
@@ -12,6 +15,15 @@ import (
 
 const Path = "/purchases/{id}"
 
+var Operation = openapi.Operation{
+	ID:        "getPurchase",
+	Method:    http.MethodGet,
+	Path:      Path,
+	Summary:   "Get a purchase",
+	Tag:       "purchases",
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
 type Handler struct {
 	useCase findpurchaseuc.UseCase
 }
@@ -23,24 +35,24 @@ func NewHandler(useCase findpurchaseuc.UseCase) Handler {
 }
 
 func RegisterEndpoint(r chi.Router, h Handler) {
-	r.Get(Path, h.Handle)
+	r.With(auth.Require(auth.RolePartner, auth.RoleAdmin)).Get(Path, h.Handle)
 }
 
 func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		http.Error(w, "invalid purchase id", http.StatusBadRequest)
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
 		return
 	}
 
 	purchase, err := h.useCase.Execute(r.Context(), id)
 	if err != nil {
-		if err.Error() == "purchase not found" {
-			http.Error(w, "purchase not found", http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		errorhandler.Render(w, r, err)
+		return
+	}
+	if !auth.ActsForMerchant(r.Context(), purchase.MerchantID.String()) {
+		errorhandler.Render(w, r, errorhandler.ErrForbidden)
 		return
 	}
 
@@ -0,0 +1,70 @@
+package listuserpurchases
+
+import (
+	"net/http"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+type (
+	PurchaseItem struct {
+		ID         string  `json:"id"`
+		Amount     float64 `json:"amount"`
+		MerchantID string  `json:"merchant_id"`
+		Status     string  `json:"status"`
+		CreatedAt  string  `json:"created_at"`
+	}
+
+	OutputPayload struct {
+		UserID    string            `json:"user_id"`
+		Purchases []PurchaseItem    `json:"purchases"`
+		Page      httpjson.PageInfo `json:"page"`
+	}
+)
+
+// ParseFilter reads the status, merchant_id, from and to query parameters.
+func ParseFilter(r *http.Request) (domain.Filter, error) {
+	filter := domain.Filter{Statuses: httpjson.ParseListParam(r, "status")}
+
+	if v := r.URL.Query().Get("merchant_id"); v != "" {
+		merchantID, err := uuid.Parse(v)
+		if err != nil {
+			return domain.Filter{}, errorhandler.Invalid("merchant_id", domain.ErrInvalidMerchant)
+		}
+		filter.MerchantID = merchantID
+	}
+
+	var err error
+	if filter.From, err = httpjson.ParseTimeParam(r, "from"); err != nil {
+		return domain.Filter{}, err
+	}
+	if filter.To, err = httpjson.ParseTimeParam(r, "to"); err != nil {
+		return domain.Filter{}, err
+	}
+
+	return filter, nil
+}
+
+func ToOutputPayload(userID uuid.UUID, purchases pagination.Page[domain.Purchase]) OutputPayload {
+	page := pagination.Map(purchases, toPurchaseItem)
+
+	return OutputPayload{
+		UserID:    userID.String(),
+		Purchases: page.Items,
+		Page:      httpjson.NewPageInfo(page.Next),
+	}
+}
+
+func toPurchaseItem(p domain.Purchase) PurchaseItem {
+	return PurchaseItem{
+		ID:         p.ID.String(),
+		Amount:     p.Amount,
+		MerchantID: p.MerchantID.String(),
+		Status:     p.Status,
+		CreatedAt:  p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
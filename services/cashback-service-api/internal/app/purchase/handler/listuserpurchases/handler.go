@@ -0,0 +1,73 @@
+package listuserpurchases
+
+import (
+	"net/http"
+
+	listuserpurchasesuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/listuserpurchases"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/users/{id}/purchases"
+
+var Operation = openapi.Operation{
+	ID:      "listUserPurchases",
+	Method:  http.MethodGet,
+	Path:    Path,
+	Summary: "List a user's purchases",
+	Tag:     "purchases",
+	Query: httpjson.PageParameters(
+		openapi.Query("status", "Only these statuses: pending, refunded", "string", ""),
+		openapi.Query("merchant_id", "Only this merchant", "string", "uuid"),
+		openapi.Query("from", "Created at or after", "string", "date-time"),
+		openapi.Query("to", "Created before", "string", "date-time"),
+	),
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase listuserpurchasesuc.UseCase
+}
+
+func NewHandler(useCase listuserpurchasesuc.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.RequireOwner("id", auth.RoleAdmin)).Get(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	filter, err := ParseFilter(r)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	page, err := httpjson.ParsePageRequest(r)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	purchases, err := h.useCase.Execute(r.Context(), userID, filter, page)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusOK, ToOutputPayload(userID, purchases))
+}
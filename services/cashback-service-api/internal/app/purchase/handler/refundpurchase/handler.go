@@ -0,0 +1,69 @@
+package refundpurchase
+
+import (
+	"net/http"
+
+	findpurchaseuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/findpurchase"
+	refundpurchaseuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/refundpurchase"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/purchases/{id}/refund"
+
+var Operation = openapi.Operation{
+	ID:        "refundPurchase",
+	Method:    http.MethodPost,
+	Path:      Path,
+	Summary:   "Refund a purchase",
+	Tag:       "purchases",
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase   refundpurchaseuc.UseCase
+	purchases findpurchaseuc.UseCase
+}
+
+func NewHandler(useCase refundpurchaseuc.UseCase, purchases findpurchaseuc.UseCase) Handler {
+	return Handler{
+		useCase:   useCase,
+		purchases: purchases,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.Require(auth.RolePartner, auth.RoleAdmin)).Post(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	// Partners refund purchases made at their own merchant only
+	purchase, err := h.purchases.Execute(r.Context(), id)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+	if !auth.ActsForMerchant(r.Context(), purchase.MerchantID.String()) {
+		errorhandler.Render(w, r, errorhandler.ErrForbidden)
+		return
+	}
+
+	purchase, err = h.useCase.Execute(r.Context(), id)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusOK, ToOutputPayload(purchase))
+}
@@ -0,0 +1,141 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/createpurchase"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/refundpurchase"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updatetier"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database/databasetest"
+	"github.com/google/uuid"
+)
+
+// The same suite runs against the in-memory repository and the Postgres one,
+// so the two cannot drift apart. The Postgres run needs TEST_DATABASE_URL and
+// is skipped without it.
+
+type purchaseRepository interface {
+	createpurchase.Repository
+	refundpurchase.Repository
+	calculatecashback.PurchaseRepository
+	updatetier.PurchaseRepository
+}
+
+func TestMemory(t *testing.T) {
+	testRepository(t, func(t *testing.T) purchaseRepository {
+		return repository.NewMemory()
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	testRepository(t, func(t *testing.T) purchaseRepository {
+		return repository.New(databasetest.New(t))
+	})
+}
+
+func testRepository(t *testing.T, newRepo func(t *testing.T) purchaseRepository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("created purchases are found by ID", func(t *testing.T) {
+		repo := newRepo(t)
+
+		purchase := domain.NewPurchase(uuid.New(), 42.5, uuid.New())
+		created, err := repo.Create(ctx, purchase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByID(ctx, created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.UserID != purchase.UserID || found.MerchantID != purchase.MerchantID ||
+			found.Amount != purchase.Amount || found.Status != domain.StatusPending {
+			t.Fatalf("found %+v, want %+v", found, purchase)
+		}
+	})
+
+	t.Run("missing purchases are not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindByID(ctx, uuid.New())
+		if !errors.Is(err, domain.ErrPurchaseNotFound) {
+			t.Fatalf("error = %v, want %v", err, domain.ErrPurchaseNotFound)
+		}
+	})
+
+	t.Run("refunds are saved", func(t *testing.T) {
+		repo := newRepo(t)
+
+		purchase, err := repo.Create(ctx, domain.NewPurchase(uuid.New(), 10, uuid.New()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := purchase.Refund(); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Update(ctx, purchase); err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByID(ctx, purchase.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found.IsRefunded() {
+			t.Fatalf("status = %q, want %q", found.Status, domain.StatusRefunded)
+		}
+	})
+
+	t.Run("purchases are counted and summed per user and time", func(t *testing.T) {
+		repo := newRepo(t)
+
+		userID := uuid.New()
+		create := func(userID uuid.UUID, amount float64, at time.Time, refunded bool) {
+			t.Helper()
+			purchase := domain.NewPurchase(userID, amount, uuid.New())
+			purchase.CreatedAt, purchase.UpdatedAt = at, at
+			if refunded {
+				purchase.Status = domain.StatusRefunded
+			}
+			if _, err := repo.Create(ctx, purchase); err != nil {
+				t.Fatal(err)
+			}
+		}
+		create(userID, 10, now.Add(-48*time.Hour), false)
+		create(userID, 20, now.Add(-time.Hour), false)
+		create(userID, 40, now.Add(-time.Hour), true)
+		create(userID, 80, now, false)
+		create(uuid.New(), 160, now.Add(-time.Hour), false)
+
+		count, err := repo.CountByUserIDBefore(ctx, userID, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Fatalf("count before now = %d, want 3", count)
+		}
+
+		// The window includes its start and leaves out refunded purchases.
+		total, err := repo.SumByUserIDSince(ctx, userID, now.Add(-time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 100 {
+			t.Fatalf("sum since an hour ago = %v, want 100", total)
+		}
+
+		total, err = repo.SumByUserIDSince(ctx, uuid.New(), now.Add(-time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 0 {
+			t.Fatalf("sum for a user without purchases = %v, want 0", total)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Memory keeps purchases in memory, for tests of the usecases that record
+// purchases and count them towards campaigns and tiers without a database.
+// Like the purchases table, it fills in the ID, status and timestamps left
+// empty and refuses a second purchase with the same ID.
+type Memory struct {
+	mu        sync.RWMutex
+	purchases map[uuid.UUID]domain.Purchase
+}
+
+// NewMemory creates an empty in-memory purchase repository.
+func NewMemory() *Memory {
+	return &Memory{purchases: make(map[uuid.UUID]domain.Purchase)}
+}
+
+func (m *Memory) Create(_ context.Context, purchase domain.Purchase) (domain.Purchase, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if purchase.ID == uuid.Nil {
+		purchase.ID = uuid.New()
+	}
+	if purchase.Status == "" {
+		purchase.Status = domain.StatusPending
+	}
+	if _, ok := m.purchases[purchase.ID]; ok {
+		return domain.Purchase{}, gorm.ErrDuplicatedKey
+	}
+
+	now := time.Now()
+	if purchase.CreatedAt.IsZero() {
+		purchase.CreatedAt = now
+	}
+	if purchase.UpdatedAt.IsZero() {
+		purchase.UpdatedAt = now
+	}
+	m.purchases[purchase.ID] = purchase
+	return purchase, nil
+}
+
+func (m *Memory) FindByID(_ context.Context, id uuid.UUID) (domain.Purchase, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	purchase, ok := m.purchases[id]
+	if !ok {
+		return domain.Purchase{}, domain.ErrPurchaseNotFound
+	}
+	return purchase, nil
+}
+
+// Update saves purchase, inserting it when it does not exist yet, as Save does.
+func (m *Memory) Update(_ context.Context, purchase domain.Purchase) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	purchase.UpdatedAt = time.Now()
+	if purchase.CreatedAt.IsZero() {
+		purchase.CreatedAt = purchase.UpdatedAt
+	}
+	m.purchases[purchase.ID] = purchase
+	return nil
+}
+
+// CountByUserIDBefore counts the user's purchases created before the given time.
+func (m *Memory) CountByUserIDBefore(_ context.Context, userID uuid.UUID, before time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, purchase := range m.purchases {
+		if purchase.UserID == userID && purchase.CreatedAt.Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SumByUserIDSince sums the amount of the user's purchases created since the
+// given time, excluding refunded purchases.
+func (m *Memory) SumByUserIDSince(_ context.Context, userID uuid.UUID, since time.Time) (float64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var total float64
+	for _, purchase := range m.purchases {
+		if purchase.UserID == userID && !purchase.CreatedAt.Before(since) && !purchase.IsRefunded() {
+			total += purchase.Amount
+		}
+	}
+	return total, nil
+}
@@ -10,11 +10,11 @@ import (
 // purchaseModel represents the database model for purchases
 type purchaseModel struct {
 	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index;index:idx_purchases_user_created,priority:1"`
 	Amount     float64   `gorm:"not null"`
-	MerchantID string    `gorm:"not null"`
+	MerchantID uuid.UUID `gorm:"type:uuid;not null;index"`
 	Status     string    `gorm:"not null;default:'pending'"`
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index:idx_purchases_user_created,priority:2"`
 	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
 }
 
@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -15,7 +18,7 @@ func (r Repository) FindByID(ctx context.Context, id uuid.UUID) (domain.Purchase
 	err := r.db.WithContext(ctx).First(&purchase, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return domain.Purchase{}, errors.New("purchase not found")
+			return domain.Purchase{}, domain.ErrPurchaseNotFound
 		}
 		return domain.Purchase{}, err
 	}
@@ -23,12 +26,14 @@ func (r Repository) FindByID(ctx context.Context, id uuid.UUID) (domain.Purchase
 	return purchase.toDomain(), nil
 }
 
-func (r Repository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Purchase, error) {
+// ListByUserID returns a page of the user's purchases matching filter.
+func (r Repository) ListByUserID(ctx context.Context, userID uuid.UUID, filter domain.Filter, page pagination.Request) (pagination.Page[domain.Purchase], error) {
 	var purchases []purchaseModel
 
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&purchases).Error
+	err := database.Paginate(r.filtered(ctx, userID, filter), page).
+		Find(&purchases).Error
 	if err != nil {
-		return nil, err
+		return pagination.Page[domain.Purchase]{}, err
 	}
 
 	result := make([]domain.Purchase, len(purchases))
@@ -36,5 +41,53 @@ func (r Repository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domai
 		result[i] = p.toDomain()
 	}
 
-	return result, nil
+	return pagination.NewPage(result, page.Limit, func(p domain.Purchase) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+	}), nil
+}
+
+func (r Repository) filtered(ctx context.Context, userID uuid.UUID, filter domain.Filter) *gorm.DB {
+	query := r.db.WithContext(ctx).
+		Model(&purchaseModel{}).
+		Where("user_id = ?", userID)
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.MerchantID != uuid.Nil {
+		query = query.Where("merchant_id = ?", filter.MerchantID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	return query
+}
+
+// CountByUserIDBefore counts the user's purchases created before the given time.
+func (r Repository) CountByUserIDBefore(ctx context.Context, userID uuid.UUID, before time.Time) (int, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&purchaseModel{}).
+		Where("user_id = ? AND created_at < ?", userID, before).
+		Count(&count).Error
+
+	return int(count), err
+}
+
+// SumByUserIDSince sums the amount of the user's purchases created since the
+// given time, excluding refunded purchases.
+func (r Repository) SumByUserIDSince(ctx context.Context, userID uuid.UUID, since time.Time) (float64, error) {
+	var total float64
+
+	err := r.db.WithContext(ctx).
+		Model(&purchaseModel{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND created_at >= ? AND status <> ?", userID, since, domain.StatusRefunded).
+		Scan(&total).Error
+
+	return total, err
 }
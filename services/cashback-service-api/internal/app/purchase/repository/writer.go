@@ -15,3 +15,8 @@ func (r Repository) Create(ctx context.Context, purchase domain.Purchase) (domai
 
 	return model.toDomain(), nil
 }
+
+func (r Repository) Update(ctx context.Context, purchase domain.Purchase) error {
+	model := fromDomain(purchase)
+	return r.db.WithContext(ctx).Save(&model).Error
+}
@@ -3,7 +3,9 @@ package createpurchase
 import "errors"
 
 var (
-	ErrInvalidAmount   = errors.New("invalid purchase amount")
-	ErrInvalidUserID   = errors.New("invalid user ID")
-	ErrInvalidMerchant = errors.New("invalid merchant ID")
+	ErrInvalidAmount     = errors.New("invalid purchase amount")
+	ErrInvalidUserID     = errors.New("invalid user ID")
+	ErrInvalidMerchant   = errors.New("invalid merchant ID")
+	ErrMerchantNotFound  = errors.New("merchant not found")
+	ErrMerchantNotActive = errors.New("merchant is not active")
 )
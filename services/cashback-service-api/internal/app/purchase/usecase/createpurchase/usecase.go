@@ -2,8 +2,12 @@ package createpurchase
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 
+	merchantdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
 	"github.com/google/uuid"
 )
 
@@ -12,18 +16,34 @@ type (
 		Create(ctx context.Context, purchase domain.Purchase) (domain.Purchase, error)
 	}
 
+	// MerchantRepository interface for merchant lookups
+	MerchantRepository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (merchantdomain.Merchant, error)
+	}
+
+	// TierUpdater recomputes the user's loyalty tier
+	TierUpdater interface {
+		Execute(ctx context.Context, userID uuid.UUID) (userdomain.User, error)
+	}
+
 	UseCase struct {
-		repository Repository
+		repository         Repository
+		merchantRepository MerchantRepository
+		tierUpdater        TierUpdater
+		log                *slog.Logger
 	}
 )
 
-func New(repository Repository) UseCase {
+func New(repository Repository, merchantRepository MerchantRepository, tierUpdater TierUpdater, log *slog.Logger) UseCase {
 	return UseCase{
-		repository: repository,
+		repository:         repository,
+		merchantRepository: merchantRepository,
+		tierUpdater:        tierUpdater,
+		log:                log,
 	}
 }
 
-func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, amount float64, merchant string) (domain.Purchase, error) {
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, amount float64, merchantID uuid.UUID) (domain.Purchase, error) {
 	if amount <= 0 {
 		return domain.Purchase{}, ErrInvalidAmount
 	}
@@ -32,10 +52,32 @@ func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, amount float64,
 		return domain.Purchase{}, ErrInvalidUserID
 	}
 
-	if merchant == "" {
+	if merchantID == uuid.Nil {
 		return domain.Purchase{}, ErrInvalidMerchant
 	}
 
-	purchase := domain.NewPurchase(userID, amount, merchant)
-	return u.repository.Create(ctx, purchase)
+	merchant, err := u.merchantRepository.FindByID(ctx, merchantID)
+	if err != nil {
+		if errors.Is(err, merchantdomain.ErrMerchantNotFound) {
+			return domain.Purchase{}, ErrMerchantNotFound
+		}
+		return domain.Purchase{}, err
+	}
+
+	if !merchant.IsActive() {
+		return domain.Purchase{}, ErrMerchantNotActive
+	}
+
+	purchase, err := u.repository.Create(ctx, domain.NewPurchase(userID, amount, merchant.ID))
+	if err != nil {
+		return domain.Purchase{}, err
+	}
+
+	// The purchase is already recorded; a failed tier update is caught up
+	// on the user's next purchase or refund.
+	if _, err := u.tierUpdater.Execute(ctx, purchase.UserID); err != nil {
+		u.log.ErrorContext(ctx, "failed to update tier", "user_id", purchase.UserID, "error", err)
+	}
+
+	return purchase, nil
 }
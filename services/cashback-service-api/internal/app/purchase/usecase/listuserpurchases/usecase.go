@@ -0,0 +1,36 @@
+package listuserpurchases
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+type (
+	Repository interface {
+		ListByUserID(ctx context.Context, userID uuid.UUID, filter domain.Filter, page pagination.Request) (pagination.Page[domain.Purchase], error)
+	}
+
+	UseCase struct {
+		repository Repository
+	}
+)
+
+func New(repository Repository) UseCase {
+	return UseCase{
+		repository: repository,
+	}
+}
+
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, filter domain.Filter, page pagination.Request) (pagination.Page[domain.Purchase], error) {
+	if userID == uuid.Nil {
+		return pagination.Page[domain.Purchase]{}, domain.ErrInvalidUserID
+	}
+	if err := filter.Validate(); err != nil {
+		return pagination.Page[domain.Purchase]{}, err
+	}
+
+	return u.repository.ListByUserID(ctx, userID, filter, page)
+}
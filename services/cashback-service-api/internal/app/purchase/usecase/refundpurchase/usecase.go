@@ -0,0 +1,59 @@
+package refundpurchase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.Purchase, error)
+		Update(ctx context.Context, purchase domain.Purchase) error
+	}
+
+	// TierUpdater recomputes the user's loyalty tier
+	TierUpdater interface {
+		Execute(ctx context.Context, userID uuid.UUID) (userdomain.User, error)
+	}
+
+	UseCase struct {
+		repository  Repository
+		tierUpdater TierUpdater
+		log         *slog.Logger
+	}
+)
+
+func New(repository Repository, tierUpdater TierUpdater, log *slog.Logger) UseCase {
+	return UseCase{
+		repository:  repository,
+		tierUpdater: tierUpdater,
+		log:         log,
+	}
+}
+
+// Execute marks a purchase as refunded and recomputes the user's loyalty tier,
+// since refunded purchases no longer count towards the rolling volume.
+func (u UseCase) Execute(ctx context.Context, id uuid.UUID) (domain.Purchase, error) {
+	purchase, err := u.repository.FindByID(ctx, id)
+	if err != nil {
+		return domain.Purchase{}, err
+	}
+
+	if err := purchase.Refund(); err != nil {
+		return domain.Purchase{}, err
+	}
+
+	if err := u.repository.Update(ctx, purchase); err != nil {
+		return domain.Purchase{}, err
+	}
+
+	if _, err := u.tierUpdater.Execute(ctx, purchase.UserID); err != nil {
+		u.log.ErrorContext(ctx, "failed to update tier", "user_id", purchase.UserID, "error", err)
+	}
+
+	return purchase, nil
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Account statuses derived from the deactivation and erasure timestamps.
+const (
+	StatusActive      = "active"
+	StatusDeactivated = "deactivated"
+	StatusErased      = "erased"
+)
+
+// erasedDomain is a reserved TLD (RFC 2606), so pseudonymized emails can
+// never reach a real mailbox.
+const erasedDomain = "erased.invalid"
+
+var (
+	ErrUserErased = errors.New("user data has been erased")
+)
+
+// IsActive reports whether the user still earns cashback.
+func (u User) IsActive() bool {
+	return u.DeactivatedAt == nil
+}
+
+// IsErased reports whether the user's personal data has been erased.
+func (u User) IsErased() bool {
+	return u.ErasedAt != nil
+}
+
+// Status returns the account status of the user.
+func (u User) Status() string {
+	switch {
+	case u.IsErased():
+		return StatusErased
+	case !u.IsActive():
+		return StatusDeactivated
+	default:
+		return StatusActive
+	}
+}
+
+// Deactivate soft-deletes the user: the account and its history are kept,
+// but no new cashback is calculated for it.
+func (u *User) Deactivate() {
+	if !u.IsActive() {
+		return
+	}
+	now := time.Now().UTC()
+	u.DeactivatedAt = &now
+	u.UpdatedAt = now
+}
+
+// Erase pseudonymizes the user's email and external ID for a GDPR erasure
+// request and deactivates the account. The user ID is kept, so cashback and
+// on-chain history stay linked to the account by UUID only.
+func (u *User) Erase() {
+	u.Deactivate()
+
+	now := time.Now().UTC()
+	u.Email = "erased-" + u.ID.String() + "@" + erasedDomain
+	u.ExternalID = "erased-" + u.ID.String()
+	u.ErasedAt = &now
+	u.UpdatedAt = now
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// ReferralCodeLength is the number of characters in a referral code.
+const ReferralCodeLength = 8
+
+// referralAlphabet is Crockford's base32 alphabet, which avoids the
+// ambiguous characters I, L, O and U so codes are easy to share verbally.
+const referralAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewReferralCode generates a random shareable referral code.
+func NewReferralCode() (string, error) {
+	buf := make([]byte, ReferralCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, ReferralCodeLength)
+	for i, b := range buf {
+		code[i] = referralAlphabet[int(b)%len(referralAlphabet)]
+	}
+	return string(code), nil
+}
+
+// NormalizeReferralCode canonicalizes user input for lookups.
+func NormalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// SharesWalletWith reports whether the users hold a wallet in common: the
+// payout, custodial or any verified wallet of one is also one of the other's.
+// wallets and otherWallets are the verified wallets of u and other. Referrals
+// between such users are treated as self-referrals.
+func (u User) SharesWalletWith(wallets []Wallet, other User, otherWallets []Wallet) bool {
+	held := u.addresses(wallets)
+	for address := range other.addresses(otherWallets) {
+		if held[address] {
+			return true
+		}
+	}
+	return false
+}
+
+// addresses returns the lowercased addresses of the user's payout and
+// custodial wallets and of wallets.
+func (u User) addresses(wallets []Wallet) map[string]bool {
+	addresses := make(map[string]bool, len(wallets)+2)
+	for _, address := range []string{u.WalletAddress, u.CustodialAddress} {
+		if address != "" {
+			addresses[strings.ToLower(address)] = true
+		}
+	}
+	for _, wallet := range wallets {
+		addresses[strings.ToLower(wallet.Address)] = true
+	}
+	return addresses
+}
+
+// IsReferred reports whether the user signed up with someone else's referral code.
+func (u User) IsReferred() bool {
+	return u.ReferredBy != nil
+}
@@ -0,0 +1,53 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+func TestSharesWalletWith(t *testing.T) {
+	const (
+		alice   = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+		bob     = "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+		carol   = "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"
+		custody = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	)
+	wallets := func(addresses ...string) []domain.Wallet {
+		result := make([]domain.Wallet, len(addresses))
+		for i, address := range addresses {
+			result[i] = domain.Wallet{ID: uuid.New(), Address: address}
+		}
+		return result
+	}
+	custodial := domain.User{ID: uuid.New()}
+	custodial.UseCustodialWallet(custody)
+	// A user who moved their payout off the custodial wallet still holds it.
+	movedOff := domain.User{ID: uuid.New(), WalletAddress: carol, CustodialAddress: custody}
+
+	for _, tc := range []struct {
+		name                  string
+		user, other           domain.User
+		wallets, otherWallets []domain.Wallet
+		want                  bool
+	}{
+		{"same payout wallet", domain.User{WalletAddress: alice}, domain.User{WalletAddress: alice}, nil, nil, true},
+		{"payout wallet in another case", domain.User{WalletAddress: alice}, domain.User{WalletAddress: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"}, nil, nil, true},
+		{"different payout wallets", domain.User{WalletAddress: alice}, domain.User{WalletAddress: bob}, nil, nil, false},
+		{"payout wallet verified by the other", domain.User{WalletAddress: alice}, domain.User{WalletAddress: bob}, nil, wallets(bob, alice), true},
+		{"verified wallet in common", domain.User{WalletAddress: alice}, domain.User{WalletAddress: bob}, wallets(alice, carol), wallets(bob, carol), true},
+		{"custodial wallet left behind", movedOff, custodial, nil, nil, true},
+		{"no wallets", domain.User{}, domain.User{}, nil, nil, false},
+		{"distinct verified wallets", domain.User{WalletAddress: alice}, domain.User{WalletAddress: bob}, wallets(alice), wallets(bob, carol), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.user.SharesWalletWith(tc.wallets, tc.other, tc.otherWallets); got != tc.want {
+				t.Fatalf("SharesWalletWith = %t, want %t", got, tc.want)
+			}
+			if got := tc.other.SharesWalletWith(tc.otherWallets, tc.user, tc.wallets); got != tc.want {
+				t.Fatalf("SharesWalletWith the other way round = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package domain
+
+import "time"
+
+// Loyalty tiers are derived from the user's rolling purchase volume.
+const (
+	TierBronze = "bronze"
+	TierSilver = "silver"
+	TierGold   = "gold"
+)
+
+// tierRank orders tiers so they can be compared.
+var tierRank = map[string]int{
+	TierBronze: 0,
+	TierSilver: 1,
+	TierGold:   2,
+}
+
+// TierThresholds holds the minimum rolling volume required for each tier.
+// Bronze is the default tier and has no threshold.
+type TierThresholds struct {
+	Silver float64
+	Gold   float64
+}
+
+// TierFor returns the tier matching the given rolling purchase volume.
+func (t TierThresholds) TierFor(volume float64) string {
+	switch {
+	case volume >= t.Gold:
+		return TierGold
+	case volume >= t.Silver:
+		return TierSilver
+	default:
+		return TierBronze
+	}
+}
+
+// TierMultipliers scales the merchant's cashback rate by the user's tier.
+// Bronze earns the merchant's rate as is.
+type TierMultipliers struct {
+	Silver float64
+	Gold   float64
+}
+
+// Rate returns percent scaled by the multiplier of tier, capped at 100.
+// Multipliers below 1 are ignored: a tier never lowers the rate.
+func (m TierMultipliers) Rate(tier string, percent float64) float64 {
+	multiplier := 1.0
+	switch tier {
+	case TierSilver:
+		multiplier = max(m.Silver, 1)
+	case TierGold:
+		multiplier = max(m.Gold, 1)
+	}
+	return min(percent*multiplier, 100)
+}
+
+// IsValidTier reports whether tier is a known loyalty tier.
+func IsValidTier(tier string) bool {
+	_, ok := tierRank[tier]
+	return ok
+}
+
+// TierAtLeast reports whether tier is equal to or above minimum.
+// An empty tier is treated as bronze.
+func TierAtLeast(tier, minimum string) bool {
+	if tier == "" {
+		tier = TierBronze
+	}
+	return tierRank[tier] >= tierRank[minimum]
+}
+
+// ChangeTier updates the user's tier and rolling volume.
+// It returns true if the tier changed.
+func (u *User) ChangeTier(tier string, rollingVolume float64) bool {
+	previous := u.Tier
+	now := time.Now().UTC()
+
+	u.Tier = tier
+	u.RollingVolume = rollingVolume
+	u.TierUpdatedAt = &now
+	u.UpdatedAt = now
+
+	return previous != tier
+}
@@ -0,0 +1,32 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+)
+
+func TestTierMultipliersRate(t *testing.T) {
+	multipliers := domain.TierMultipliers{Silver: 1.25, Gold: 2}
+
+	for _, tc := range []struct {
+		name        string
+		multipliers domain.TierMultipliers
+		tier        string
+		percent     float64
+		want        float64
+	}{
+		{"bronze earns the merchant rate", multipliers, domain.TierBronze, 4, 4},
+		{"an unset tier earns the merchant rate", multipliers, "", 4, 4},
+		{"silver", multipliers, domain.TierSilver, 4, 5},
+		{"gold", multipliers, domain.TierGold, 4, 8},
+		{"capped at 100", multipliers, domain.TierGold, 60, 100},
+		{"multipliers below 1 are ignored", domain.TierMultipliers{Silver: 0.5}, domain.TierSilver, 4, 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.multipliers.Rate(tc.tier, tc.percent); got != tc.want {
+				t.Fatalf("Rate(%q, %v) = %v, want %v", tc.tier, tc.percent, got, tc.want)
+			}
+		})
+	}
+}
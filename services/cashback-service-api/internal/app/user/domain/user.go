@@ -2,18 +2,45 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Sentinel errors for user domain lookups.
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrDuplicateUser = errors.New("a user with this external ID, email or referral code already exists")
+)
+
 // User represents a user in the system.
-// Each user has a unique external ID, email, and blockchain wallet address.
+// Each user has a unique external ID, email, and blockchain wallet address,
+// plus a shareable referral code. WalletVerifiedAt is set once the user has
+// proven ownership of the wallet by signing a challenge. WalletAddress is the
+// payout wallet, chosen among the user's verified wallets; PayoutChangedAt
+// records the last time it changed. CustodialAddress is the platform-held
+// wallet derived for users who signed up without one. ReferredBy points to the user whose code
+// was used at signup, and ReferralRewardedAt records when the referral bonus
+// for this user was paid out. Tier is the loyalty tier derived from
+// RollingVolume, the user's purchase volume over the tier window.
+// DeactivatedAt marks a soft-deleted account and ErasedAt a GDPR erasure.
 type User struct {
-	ID            uuid.UUID
-	ExternalID    string
-	Email         string
-	WalletAddress string
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID                 uuid.UUID
+	ExternalID         string
+	Email              string
+	WalletAddress      string
+	WalletVerifiedAt   *time.Time
+	PayoutChangedAt    *time.Time
+	CustodialAddress   string
+	ReferralCode       string
+	ReferredBy         *uuid.UUID
+	ReferralRewardedAt *time.Time
+	Tier               string
+	RollingVolume      float64
+	TierUpdatedAt      *time.Time
+	DeactivatedAt      *time.Time
+	ErasedAt           *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
 }
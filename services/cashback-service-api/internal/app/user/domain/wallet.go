@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NonceLength is the number of characters in a wallet challenge nonce.
+const NonceLength = 16
+
+// nonceAlphabet is alphanumeric, as required for EIP-4361 nonces.
+const nonceAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Sentinel errors for wallet lookups and payout wallet changes.
+var (
+	ErrChallengeNotFound = errors.New("wallet challenge not found")
+	ErrWalletNotFound    = errors.New("wallet not found")
+	ErrPayoutCooldown    = errors.New("payout wallet was changed too recently")
+	ErrWalletTooNew      = errors.New("wallet was verified too recently to receive payouts")
+)
+
+// Wallet is a wallet whose ownership the user has proven. A user may have
+// several; the payout wallet is mirrored on User.WalletAddress.
+type Wallet struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Address    string
+	VerifiedAt time.Time
+	CreatedAt  time.Time
+}
+
+// WalletChallenge is a one-time nonce challenge issued to prove ownership of
+// a wallet. Message is the exact EIP-4361 text the user must sign.
+type WalletChallenge struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Address   string
+	Nonce     string
+	Message   string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NewNonce generates a random EIP-4361 nonce.
+func NewNonce() (string, error) {
+	buf := make([]byte, NonceLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, NonceLength)
+	for i, b := range buf {
+		nonce[i] = nonceAlphabet[int(b)%len(nonceAlphabet)]
+	}
+	return string(nonce), nil
+}
+
+// IsExpired reports whether the challenge can no longer be answered.
+func (c WalletChallenge) IsExpired(at time.Time) bool {
+	return !at.Before(c.ExpiresAt)
+}
+
+// IsUsed reports whether the challenge was already answered.
+func (c WalletChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
+
+// HasVerifiedWallet reports whether the user proved ownership of the wallet
+// tokens are minted to. Cashback for users without one is held back.
+func (u User) HasVerifiedWallet() bool {
+	return u.WalletAddress != "" && u.WalletVerifiedAt != nil
+}
+
+// NewWallet records a wallet whose ownership the user has just proven.
+func NewWallet(userID uuid.UUID, address string) Wallet {
+	now := time.Now().UTC()
+	return Wallet{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Address:    address,
+		VerifiedAt: now,
+		CreatedAt:  now,
+	}
+}
+
+// UseCustodialWallet makes a platform-held wallet the payout wallet of a user
+// who signed up without one. The platform holds its key, so it counts as verified.
+func (u *User) UseCustodialWallet(address string) {
+	now := time.Now().UTC()
+	u.CustodialAddress = address
+	u.WalletAddress = address
+	u.WalletVerifiedAt = &now
+	u.PayoutChangedAt = &now
+}
+
+// IsCustodial reports whether cashback is paid out to the user's custodial wallet.
+func (u User) IsCustodial() bool {
+	return u.CustodialAddress != "" && u.WalletAddress == u.CustodialAddress
+}
+
+// CanSwitchPayoutTo checks the cooldown that deters account takeover: the
+// payout wallet may change at most once per cooldown, and only to a wallet
+// verified at least one cooldown ago. Users without a verified payout wallet
+// may pick any verified wallet immediately; custodial users may not, as a
+// stolen account would otherwise redirect their cashback at once.
+func (u User) CanSwitchPayoutTo(wallet Wallet, cooldown time.Duration, at time.Time) error {
+	if !u.HasVerifiedWallet() {
+		return nil
+	}
+	if u.PayoutChangedAt != nil && at.Before(u.PayoutChangedAt.Add(cooldown)) {
+		return ErrPayoutCooldown
+	}
+	if at.Before(wallet.VerifiedAt.Add(cooldown)) {
+		return ErrWalletTooNew
+	}
+	return nil
+}
+
+// CanClaimTo checks that the custodial balance may be moved to wallet: like a
+// payout wallet, it must have been verified at least one cooldown ago.
+func (u User) CanClaimTo(wallet Wallet, cooldown time.Duration, at time.Time) error {
+	if at.Before(wallet.VerifiedAt.Add(cooldown)) {
+		return ErrWalletTooNew
+	}
+	return nil
+}
+
+// SetPayoutWallet makes a verified wallet the one future cashback is minted to.
+func (u *User) SetPayoutWallet(wallet Wallet) {
+	now := time.Now().UTC()
+	verifiedAt := wallet.VerifiedAt
+	u.WalletAddress = wallet.Address
+	u.WalletVerifiedAt = &verifiedAt
+	u.PayoutChangedAt = &now
+	u.UpdatedAt = now
+}
+
+// OwnsWallet reports whether address is the user's wallet, ignoring checksum case.
+func (u User) OwnsWallet(address string) bool {
+	return u.WalletAddress != "" && strings.EqualFold(u.WalletAddress, address)
+}
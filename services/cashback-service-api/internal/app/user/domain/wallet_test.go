@@ -0,0 +1,75 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+const cooldown = 24 * time.Hour
+
+func TestCanSwitchPayoutTo(t *testing.T) {
+	now := time.Now().UTC()
+	recently := now.Add(-cooldown / 2)
+	long := now.Add(-2 * cooldown)
+
+	withoutWallet := domain.User{ID: uuid.New()}
+	custodial := domain.User{ID: uuid.New()}
+	custodial.UseCustodialWallet("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	custodial.PayoutChangedAt = &long
+	selfCustody := func(changedAt time.Time) domain.User {
+		return domain.User{
+			ID:               uuid.New(),
+			WalletAddress:    "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			WalletVerifiedAt: &long,
+			PayoutChangedAt:  &changedAt,
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		user     domain.User
+		verified time.Time
+		want     error
+	}{
+		{"no wallet yet, new wallet", withoutWallet, now, nil},
+		{"custodial, new wallet", custodial, recently, domain.ErrWalletTooNew},
+		{"custodial, wallet past the cooldown", custodial, long, nil},
+		{"self-custody, changed recently", selfCustody(recently), long, domain.ErrPayoutCooldown},
+		{"self-custody, new wallet", selfCustody(long), recently, domain.ErrWalletTooNew},
+		{"self-custody, both past the cooldown", selfCustody(long), long, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			wallet := domain.Wallet{Address: "0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B", VerifiedAt: tc.verified}
+			if err := tc.user.CanSwitchPayoutTo(wallet, cooldown, now); !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestCanClaimTo(t *testing.T) {
+	now := time.Now().UTC()
+	user := domain.User{ID: uuid.New()}
+	user.UseCustodialWallet("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+
+	for _, tc := range []struct {
+		name     string
+		verified time.Time
+		want     error
+	}{
+		{"verified just now", now, domain.ErrWalletTooNew},
+		{"verified just under the cooldown", now.Add(-cooldown + time.Second), domain.ErrWalletTooNew},
+		{"verified one cooldown ago", now.Add(-cooldown), nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			wallet := domain.Wallet{Address: "0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B", VerifiedAt: tc.verified}
+			if err := user.CanClaimTo(wallet, cooldown, now); !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
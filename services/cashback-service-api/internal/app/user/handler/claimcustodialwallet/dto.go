@@ -0,0 +1,40 @@
+package claimcustodialwallet
+
+import (
+	claimcustodialwalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/claimcustodialwallet"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/validator"
+)
+
+type (
+	InputPayload struct {
+		WalletAddress string `json:"wallet_address"`
+	}
+
+	OutputPayload struct {
+		UserID          string `json:"user_id"`
+		FromAddress     string `json:"from_address"`
+		ToAddress       string `json:"to_address"`
+		TokenAmount     string `json:"token_amount"`
+		TransactionHash string `json:"transaction_hash"`
+		ClaimedAt       string `json:"claimed_at"`
+	}
+)
+
+func (p InputPayload) Validate() error {
+	if p.WalletAddress == "" {
+		return errorhandler.Invalid("wallet_address", validator.ErrRequired)
+	}
+	return errorhandler.Invalid("wallet_address", validator.ValidateWalletAddress(p.WalletAddress))
+}
+
+func ToOutputPayload(claim claimcustodialwalletuc.Claim) OutputPayload {
+	return OutputPayload{
+		UserID:          claim.UserID.String(),
+		FromAddress:     claim.FromAddress,
+		ToAddress:       claim.ToAddress,
+		TokenAmount:     claim.TokenAmount,
+		TransactionHash: claim.TransactionHash,
+		ClaimedAt:       claim.ClaimedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
@@ -0,0 +1,68 @@
+package claimcustodialwallet
+
+import (
+	"net/http"
+
+	claimcustodialwalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/claimcustodialwallet"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/internal/idempotency"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/users/{id}/wallet/claim"
+
+var Operation = openapi.Operation{
+	ID:        "claimCustodialWallet",
+	Method:    http.MethodPost,
+	Path:      Path,
+	Summary:   "Move custodial funds to a self-custody wallet",
+	Tag:       "wallets",
+	Request:   InputPayload{},
+	Responses: map[int]any{http.StatusAccepted: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase claimcustodialwalletuc.UseCase
+}
+
+func NewHandler(useCase claimcustodialwalletuc.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.RequireOwner("id", auth.RoleAdmin)).Post(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	var payload InputPayload
+	if err := httpjson.ReadJSON(r, &payload); err != nil {
+		errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	claim, err := h.useCase.Execute(r.Context(), id, payload.WalletAddress, r.Header.Get(idempotency.Header))
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusAccepted, ToOutputPayload(claim))
+}
@@ -2,6 +2,7 @@ package createuser
 
 import (
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 	"github.com/cashback-platform/services/cashback-service-api/pkg/validator"
 )
 
@@ -9,34 +10,46 @@ type (
 	InputPayload struct {
 		ExternalID    string `json:"external_id"`
 		Email         string `json:"email"`
-		WalletAddress string `json:"wallet_address"`
+		WalletAddress string `json:"wallet_address,omitempty"`
+		ReferralCode  string `json:"referral_code,omitempty"`
 	}
 
 	OutputPayload struct {
-		ID            string `json:"id"`
-		ExternalID    string `json:"external_id"`
-		Email         string `json:"email"`
-		WalletAddress string `json:"wallet_address"`
-		CreatedAt     string `json:"created_at"`
+		ID             string `json:"id"`
+		ExternalID     string `json:"external_id"`
+		Email          string `json:"email"`
+		WalletAddress  string `json:"wallet_address"`
+		WalletVerified bool   `json:"wallet_verified"`
+		Custodial      bool   `json:"custodial"`
+		ReferralCode   string `json:"referral_code"`
+		CreatedAt      string `json:"created_at"`
 	}
 )
 
 func (p InputPayload) Validate() error {
+	var v errorhandler.ValidationError
 	if p.ExternalID == "" {
-		return validator.ErrRequired
+		v.Add("external_id", validator.ErrRequired)
 	}
-	if err := validator.ValidateEmail(p.Email); err != nil {
-		return err
+	v.Add("email", validator.ValidateEmail(p.Email))
+	// The wallet is optional at signup. A given wallet stays unverified until
+	// the user proves ownership through the wallet challenge; without one the
+	// user may be paid out to a custodial wallet instead.
+	if p.WalletAddress != "" {
+		v.Add("wallet_address", validator.ValidateWalletAddress(p.WalletAddress))
 	}
-	return validator.ValidateWalletAddress(p.WalletAddress)
+	return v.Err()
 }
 
 func ToOutputPayload(user domain.User) OutputPayload {
 	return OutputPayload{
-		ID:            user.ID.String(),
-		ExternalID:    user.ExternalID,
-		Email:         user.Email,
-		WalletAddress: user.WalletAddress,
-		CreatedAt:     user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:             user.ID.String(),
+		ExternalID:     user.ExternalID,
+		Email:          user.Email,
+		WalletAddress:  user.WalletAddress,
+		WalletVerified: user.HasVerifiedWallet(),
+		Custodial:      user.IsCustodial(),
+		ReferralCode:   user.ReferralCode,
+		CreatedAt:      user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
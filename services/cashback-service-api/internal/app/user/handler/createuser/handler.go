@@ -1,17 +1,29 @@
 package createuser
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/createuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
 
 	"github.com/go-chi/chi/v5"
 )
 
 const Path = "/users"
 
+var Operation = openapi.Operation{
+	ID:        "createUser",
+	Method:    http.MethodPost,
+	Path:      Path,
+	Summary:   "Register a user",
+	Tag:       "users",
+	Request:   InputPayload{},
+	Responses: map[int]any{http.StatusCreated: OutputPayload{}},
+}
+
 type Handler struct {
 	useCase createuser.UseCase
 }
@@ -23,28 +35,30 @@ func NewHandler(useCase createuser.UseCase) Handler {
 }
 
 func RegisterEndpoint(r chi.Router, h Handler) {
-	r.Post(Path, h.Handle)
+	r.With(auth.Require(auth.RolePartner, auth.RoleAdmin)).Post(Path, h.Handle)
 }
 
 func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	var payload InputPayload
 	if err := httpjson.ReadJSON(r, &payload); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
 		return
 	}
 
 	if err := payload.Validate(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		errorhandler.Render(w, r, err)
 		return
 	}
 
-	user, err := h.useCase.Execute(r.Context(), payload.ExternalID, payload.Email, payload.WalletAddress)
+	user, err := h.useCase.Execute(
+		r.Context(),
+		payload.ExternalID,
+		payload.Email,
+		payload.WalletAddress,
+		payload.ReferralCode,
+	)
 	if err != nil {
-		if errors.Is(err, createuser.ErrUserAlreadyExists) {
-			http.Error(w, err.Error(), http.StatusConflict)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		errorhandler.Render(w, r, err)
 		return
 	}
 
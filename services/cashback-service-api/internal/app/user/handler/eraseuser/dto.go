@@ -0,0 +1,22 @@
+package eraseuser
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+)
+
+type OutputPayload struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	ErasedAt string `json:"erased_at"`
+}
+
+func ToOutputPayload(user domain.User) OutputPayload {
+	output := OutputPayload{
+		ID:     user.ID.String(),
+		Status: user.Status(),
+	}
+	if user.ErasedAt != nil {
+		output.ErasedAt = user.ErasedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return output
+}
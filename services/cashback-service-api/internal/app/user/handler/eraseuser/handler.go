@@ -0,0 +1,55 @@
+package eraseuser
+
+import (
+	"net/http"
+
+	eraseuseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/eraseuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/users/{id}/erasure"
+
+var Operation = openapi.Operation{
+	ID:        "eraseUser",
+	Method:    http.MethodPost,
+	Path:      Path,
+	Summary:   "Erase a user's personal data",
+	Tag:       "users",
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase eraseuseruc.UseCase
+}
+
+func NewHandler(useCase eraseuseruc.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.RequireOwner("id", auth.RoleAdmin)).Post(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	user, err := h.useCase.Execute(r.Context(), id)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusOK, ToOutputPayload(user))
+}
@@ -5,19 +5,31 @@ import (
 )
 
 type OutputPayload struct {
-	ID            string `json:"id"`
-	ExternalID    string `json:"external_id"`
-	Email         string `json:"email"`
-	WalletAddress string `json:"wallet_address"`
-	CreatedAt     string `json:"created_at"`
+	ID             string  `json:"id"`
+	ExternalID     string  `json:"external_id"`
+	Email          string  `json:"email"`
+	WalletAddress  string  `json:"wallet_address"`
+	WalletVerified bool    `json:"wallet_verified"`
+	Custodial      bool    `json:"custodial"`
+	Status         string  `json:"status"`
+	ReferralCode   string  `json:"referral_code"`
+	Tier           string  `json:"tier"`
+	RollingVolume  float64 `json:"rolling_volume"`
+	CreatedAt      string  `json:"created_at"`
 }
 
 func ToOutputPayload(user domain.User) OutputPayload {
 	return OutputPayload{
-		ID:            user.ID.String(),
-		ExternalID:    user.ExternalID,
-		Email:         user.Email,
-		WalletAddress: user.WalletAddress,
-		CreatedAt:     user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:             user.ID.String(),
+		ExternalID:     user.ExternalID,
+		Email:          user.Email,
+		WalletAddress:  user.WalletAddress,
+		WalletVerified: user.HasVerifiedWallet(),
+		Custodial:      user.IsCustodial(),
+		Status:         user.Status(),
+		ReferralCode:   user.ReferralCode,
+		Tier:           user.Tier,
+		RollingVolume:  user.RollingVolume,
+		CreatedAt:      user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
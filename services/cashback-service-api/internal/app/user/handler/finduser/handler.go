@@ -4,7 +4,10 @@ import (
 	"net/http"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/finduser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
 	"github.com/google/uuid"
 
 	"github.com/go-chi/chi/v5"
@@ -12,6 +15,15 @@ import (
 
 const Path = "/users/{id}"
 
+var Operation = openapi.Operation{
+	ID:        "getUser",
+	Method:    http.MethodGet,
+	Path:      Path,
+	Summary:   "Get a user",
+	Tag:       "users",
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
 type Handler struct {
 	useCase finduser.UseCase
 }
@@ -23,24 +35,20 @@ func NewHandler(useCase finduser.UseCase) Handler {
 }
 
 func RegisterEndpoint(r chi.Router, h Handler) {
-	r.Get(Path, h.Handle)
+	r.With(auth.RequireOwner("id", auth.RoleAdmin)).Get(Path, h.Handle)
 }
 
 func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		http.Error(w, "invalid user id", http.StatusBadRequest)
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
 		return
 	}
 
 	user, err := h.useCase.Execute(r.Context(), id)
 	if err != nil {
-		if err.Error() == "user not found" {
-			http.Error(w, "user not found", http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		errorhandler.Render(w, r, err)
 		return
 	}
 
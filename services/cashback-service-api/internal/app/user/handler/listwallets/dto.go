@@ -0,0 +1,39 @@
+package listwallets
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+)
+
+type (
+	WalletItem struct {
+		Address    string `json:"address"`
+		Payout     bool   `json:"payout"`
+		VerifiedAt string `json:"verified_at"`
+	}
+
+	OutputPayload struct {
+		UserID       string       `json:"user_id"`
+		PayoutWallet string       `json:"payout_wallet"`
+		Wallets      []WalletItem `json:"wallets"`
+	}
+)
+
+func ToOutputPayload(user domain.User, wallets []domain.Wallet) OutputPayload {
+	output := OutputPayload{
+		UserID:  user.ID.String(),
+		Wallets: make([]WalletItem, len(wallets)),
+	}
+	if user.HasVerifiedWallet() {
+		output.PayoutWallet = user.WalletAddress
+	}
+
+	for i, w := range wallets {
+		output.Wallets[i] = WalletItem{
+			Address:    w.Address,
+			Payout:     w.Address == output.PayoutWallet,
+			VerifiedAt: w.VerifiedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	return output
+}
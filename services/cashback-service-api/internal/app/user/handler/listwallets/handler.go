@@ -0,0 +1,55 @@
+package listwallets
+
+import (
+	"net/http"
+
+	listwalletsuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/listwallets"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/users/{id}/wallets"
+
+var Operation = openapi.Operation{
+	ID:        "listWallets",
+	Method:    http.MethodGet,
+	Path:      Path,
+	Summary:   "List a user's wallets",
+	Tag:       "wallets",
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase listwalletsuc.UseCase
+}
+
+func NewHandler(useCase listwalletsuc.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.RequireOwner("id", auth.RoleAdmin)).Get(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	user, wallets, err := h.useCase.Execute(r.Context(), id)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusOK, ToOutputPayload(user, wallets))
+}
@@ -0,0 +1,33 @@
+package requestwalletchallenge
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/validator"
+)
+
+type (
+	InputPayload struct {
+		WalletAddress string `json:"wallet_address"`
+	}
+
+	OutputPayload struct {
+		WalletAddress string `json:"wallet_address"`
+		Nonce         string `json:"nonce"`
+		Message       string `json:"message"`
+		ExpiresAt     string `json:"expires_at"`
+	}
+)
+
+func (p InputPayload) Validate() error {
+	return errorhandler.Invalid("wallet_address", validator.ValidateWalletAddress(p.WalletAddress))
+}
+
+func ToOutputPayload(challenge domain.WalletChallenge) OutputPayload {
+	return OutputPayload{
+		WalletAddress: challenge.Address,
+		Nonce:         challenge.Nonce,
+		Message:       challenge.Message,
+		ExpiresAt:     challenge.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
@@ -0,0 +1,67 @@
+package requestwalletchallenge
+
+import (
+	"net/http"
+
+	requestwalletchallengeuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/requestwalletchallenge"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/users/{id}/wallet/challenge"
+
+var Operation = openapi.Operation{
+	ID:        "requestWalletChallenge",
+	Method:    http.MethodPost,
+	Path:      Path,
+	Summary:   "Request a wallet ownership challenge",
+	Tag:       "wallets",
+	Request:   InputPayload{},
+	Responses: map[int]any{http.StatusCreated: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase requestwalletchallengeuc.UseCase
+}
+
+func NewHandler(useCase requestwalletchallengeuc.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.RequireOwner("id", auth.RoleAdmin)).Post(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	var payload InputPayload
+	if err := httpjson.ReadJSON(r, &payload); err != nil {
+		errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	challenge, err := h.useCase.Execute(r.Context(), id, payload.WalletAddress)
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusCreated, ToOutputPayload(challenge))
+}
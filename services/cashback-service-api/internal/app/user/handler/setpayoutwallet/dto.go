@@ -0,0 +1,22 @@
+package setpayoutwallet
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+)
+
+type OutputPayload struct {
+	UserID          string `json:"user_id"`
+	PayoutWallet    string `json:"payout_wallet"`
+	PayoutChangedAt string `json:"payout_changed_at,omitempty"`
+}
+
+func ToOutputPayload(user domain.User) OutputPayload {
+	output := OutputPayload{
+		UserID:       user.ID.String(),
+		PayoutWallet: user.WalletAddress,
+	}
+	if user.PayoutChangedAt != nil {
+		output.PayoutChangedAt = user.PayoutChangedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return output
+}
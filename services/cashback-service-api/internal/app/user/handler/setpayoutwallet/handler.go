@@ -0,0 +1,55 @@
+package setpayoutwallet
+
+import (
+	"net/http"
+
+	setpayoutwalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/setpayoutwallet"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/google/uuid"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const Path = "/users/{id}/wallets/{address}/primary"
+
+var Operation = openapi.Operation{
+	ID:        "setPayoutWallet",
+	Method:    http.MethodPut,
+	Path:      Path,
+	Summary:   "Make a verified wallet the payout wallet",
+	Tag:       "wallets",
+	Responses: map[int]any{http.StatusOK: OutputPayload{}},
+}
+
+type Handler struct {
+	useCase setpayoutwalletuc.UseCase
+}
+
+func NewHandler(useCase setpayoutwalletuc.UseCase) Handler {
+	return Handler{
+		useCase: useCase,
+	}
+}
+
+func RegisterEndpoint(r chi.Router, h Handler) {
+	r.With(auth.RequireOwner("id", auth.RoleAdmin)).Put(Path, h.Handle)
+}
+
+func (h Handler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorhandler.Render(w, r, errorhandler.Invalid("id", errorhandler.ErrInvalidUUID))
+		return
+	}
+
+	user, err := h.useCase.Execute(r.Context(), id, chi.URLParam(r, "address"))
+	if err != nil {
+		errorhandler.Render(w, r, err)
+		return
+	}
+
+	httpjson.WriteJSON(w, http.StatusOK, ToOutputPayload(user))
+}
@@ -0,0 +1,58 @@
+package updateuser
+
+import (
+	"errors"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	updateuseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updateuser"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/validator"
+)
+
+var ErrNoChanges = errors.New("at least one of email or external_id is required")
+
+type (
+	InputPayload struct {
+		Email      *string `json:"email,omitempty"`
+		ExternalID *string `json:"external_id,omitempty"`
+	}
+
+	OutputPayload struct {
+		ID         string `json:"id"`
+		ExternalID string `json:"external_id"`
+		Email      string `json:"email"`
+		Status     string `json:"status"`
+		UpdatedAt  string `json:"updated_at"`
+	}
+)
+
+func (p InputPayload) Validate() error {
+	if p.Email == nil && p.ExternalID == nil {
+		return ErrNoChanges
+	}
+	var v errorhandler.ValidationError
+	if p.Email != nil {
+		v.Add("email", validator.ValidateEmail(*p.Email))
+	}
+	if p.ExternalID != nil && *p.ExternalID == "" {
+		v.Add("external_id", validator.ErrRequired)
+	}
+	return v.Err()
+}
+
+func (p InputPayload) ToChanges() updateuseruc.Changes {
+	return updateuseruc.Changes{
+		Email:      p.Email,
+		ExternalID: p.ExternalID,
+	}
+}
+
+func ToOutputPayload(user domain.User) OutputPayload {
+	return OutputPayload{
+		ID:         user.ID.String(),
+		ExternalID: user.ExternalID,
+		Email:      user.Email,
+		Status:     user.Status(),
+		UpdatedAt:  user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
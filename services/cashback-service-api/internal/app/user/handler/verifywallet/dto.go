@@ -0,0 +1,44 @@
+package verifywallet
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/validator"
+)
+
+type (
+	InputPayload struct {
+		Message   string `json:"message"`
+		Signature string `json:"signature"`
+	}
+
+	OutputPayload struct {
+		ID               string `json:"id"`
+		WalletAddress    string `json:"wallet_address"`
+		WalletVerified   bool   `json:"wallet_verified"`
+		WalletVerifiedAt string `json:"wallet_verified_at"`
+	}
+)
+
+func (p InputPayload) Validate() error {
+	var v errorhandler.ValidationError
+	if p.Message == "" {
+		v.Add("message", validator.ErrRequired)
+	}
+	if p.Signature == "" {
+		v.Add("signature", validator.ErrRequired)
+	}
+	return v.Err()
+}
+
+func ToOutputPayload(user domain.User) OutputPayload {
+	output := OutputPayload{
+		ID:             user.ID.String(),
+		WalletAddress:  user.WalletAddress,
+		WalletVerified: user.HasVerifiedWallet(),
+	}
+	if user.WalletVerifiedAt != nil {
+		output.WalletVerifiedAt = user.WalletVerifiedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return output
+}
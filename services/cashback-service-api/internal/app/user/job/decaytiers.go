@@ -0,0 +1,77 @@
+// Package job contains the scheduled background jobs of the user context.
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/decaytiers"
+	"go.uber.org/fx"
+)
+
+// DecayTiersJob periodically recomputes loyalty tiers whose purchases may
+// have left the rolling window.
+type DecayTiersJob struct {
+	useCase  decaytiers.UseCase
+	interval time.Duration
+	log      *slog.Logger
+	done     chan struct{}
+}
+
+func NewDecayTiersJob(useCase decaytiers.UseCase, interval time.Duration, log *slog.Logger) *DecayTiersJob {
+	return &DecayTiersJob{
+		useCase:  useCase,
+		interval: interval,
+		log:      log,
+		done:     make(chan struct{}),
+	}
+}
+
+func (j *DecayTiersJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *DecayTiersJob) Stop() {
+	close(j.done)
+}
+
+func (j *DecayTiersJob) run(ctx context.Context) {
+	result, err := j.useCase.Execute(ctx)
+	if err != nil {
+		j.log.ErrorContext(ctx, "tier decay failed", "error", err)
+	}
+	if result.Recomputed > 0 {
+		j.log.InfoContext(ctx, "tier decay run", "recomputed", result.Recomputed, "demoted", result.Demoted)
+	}
+}
+
+func StartDecayTiersJob(lc fx.Lifecycle, job *DecayTiersJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go job.Start(ctx)
+			job.log.Info("tier decay job started")
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			job.Stop()
+			job.log.Info("tier decay job stopped")
+			return nil
+		},
+	})
+}
@@ -0,0 +1,480 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/createuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/deactivateuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/decaytiers"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/eraseuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/requestwalletchallenge"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updatetier"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database/databasetest"
+	"github.com/google/uuid"
+)
+
+// The same suite runs against the in-memory repository and the Postgres one,
+// so the two cannot drift apart. The Postgres run needs TEST_DATABASE_URL and
+// is skipped without it.
+
+type userRepository interface {
+	createuser.Repository
+	calculatecashback.UserRepository
+	updatetier.Repository
+	decaytiers.Repository
+	deactivateuser.Repository
+	eraseuser.Repository
+	requestwalletchallenge.Repository
+	AddWallet(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error)
+	FindWallet(ctx context.Context, userID uuid.UUID, address string) (domain.Wallet, error)
+	FindChallengeByNonce(ctx context.Context, nonce string) (domain.WalletChallenge, error)
+}
+
+func TestMemory(t *testing.T) {
+	testRepository(t, func(t *testing.T) userRepository {
+		return repository.NewMemory()
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	testRepository(t, func(t *testing.T) userRepository {
+		return repository.New(databasetest.New(t))
+	})
+}
+
+func testRepository(t *testing.T, newRepo func(t *testing.T) userRepository) {
+	ctx := context.Background()
+
+	t.Run("create fills in defaults", func(t *testing.T) {
+		repo := newRepo(t)
+
+		referrer := uuid.New()
+		user := newUser()
+		user.Tier = ""
+		user.ReferredBy = &referrer
+
+		created, err := repo.Create(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.ID == uuid.Nil || created.Tier != domain.TierBronze || created.CreatedAt.IsZero() {
+			t.Fatalf("created user has ID %s, tier %q, created at %s", created.ID, created.Tier, created.CreatedAt)
+		}
+
+		found, err := repo.FindByExternalID(ctx, user.ExternalID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ID != created.ID || found.Email != user.Email || found.WalletAddress != user.WalletAddress ||
+			found.ReferralCode != user.ReferralCode || found.ReferredBy == nil || *found.ReferredBy != referrer ||
+			found.WalletVerifiedAt == nil || found.Tier != domain.TierBronze {
+			t.Fatalf("found %+v, want %+v", found, created)
+		}
+	})
+
+	t.Run("users are found by external ID, email and referral code", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, find := range map[string]func() (domain.User, error){
+			"external ID":   func() (domain.User, error) { return repo.FindByExternalID(ctx, created.ExternalID) },
+			"email":         func() (domain.User, error) { return repo.FindByEmail(ctx, created.Email) },
+			"referral code": func() (domain.User, error) { return repo.FindByReferralCode(ctx, created.ReferralCode) },
+		} {
+			found, err := find()
+			if err != nil {
+				t.Fatalf("by %s: %v", name, err)
+			}
+			if found.ID != created.ID {
+				t.Fatalf("by %s: found %s, want %s", name, found.ID, created.ID)
+			}
+		}
+	})
+
+	t.Run("users are found by ID", func(t *testing.T) {
+		repo := newRepo(t)
+
+		created, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByID(ctx, created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ID != created.ID || found.Email != created.Email {
+			t.Fatalf("found %+v, want %+v", found, created)
+		}
+	})
+
+	t.Run("missing users are not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindByID(ctx, uuid.New())
+		assertError(t, err, domain.ErrUserNotFound)
+
+		user := newUser()
+		_, err = repo.FindByExternalID(ctx, user.ExternalID)
+		assertError(t, err, domain.ErrUserNotFound)
+		_, err = repo.FindByEmail(ctx, user.Email)
+		assertError(t, err, domain.ErrUserNotFound)
+		_, err = repo.FindByReferralCode(ctx, user.ReferralCode)
+		assertError(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("lookups are exact", func(t *testing.T) {
+		repo := newRepo(t)
+
+		user := newUser()
+		user.Email = "Jane@Example.com"
+		if _, err := repo.Create(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+		_, err := repo.FindByEmail(ctx, "jane@example.com")
+		assertError(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("external IDs, emails and referral codes are unique", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, clash := range map[string]func(*domain.User){
+			"ID":            func(u *domain.User) { u.ID = first.ID },
+			"external ID":   func(u *domain.User) { u.ExternalID = first.ExternalID },
+			"email":         func(u *domain.User) { u.Email = first.Email },
+			"referral code": func(u *domain.User) { u.ReferralCode = first.ReferralCode },
+		} {
+			user := newUser()
+			clash(&user)
+			if _, err := repo.Create(ctx, user); !errors.Is(err, domain.ErrDuplicateUser) {
+				t.Fatalf("same %s: error = %v, want %v", name, err, domain.ErrDuplicateUser)
+			}
+		}
+
+		// The rejected users left nothing behind.
+		found, err := repo.FindByEmail(ctx, first.Email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ID != first.ID {
+			t.Fatalf("email maps to %s, want %s", found.ID, first.ID)
+		}
+	})
+
+	t.Run("a referral reward is claimed once", func(t *testing.T) {
+		repo := newRepo(t)
+
+		referrer, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		referee := newUser()
+		referee.ReferredBy = &referrer.ID
+		referee, err = repo.Create(ctx, referee)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, want := range []bool{true, false} {
+			claimed, err := repo.ClaimReferralReward(ctx, referee.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claimed != want {
+				t.Fatalf("claim %d = %t, want %t", i+1, claimed, want)
+			}
+		}
+		found, err := repo.FindByID(ctx, referee.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ReferralRewardedAt == nil {
+			t.Fatal("referral reward is not marked as paid")
+		}
+
+		// A released claim, for a bonus that could not be issued, is claimed again.
+		if err := repo.ReleaseReferralReward(ctx, referee.ID); err != nil {
+			t.Fatal(err)
+		}
+		if claimed, err := repo.ClaimReferralReward(ctx, referee.ID); err != nil || !claimed {
+			t.Fatalf("claim after release = %t, %v", claimed, err)
+		}
+
+		// Users who were not referred, or do not exist, have nothing to claim.
+		for _, id := range []uuid.UUID{referrer.ID, uuid.New()} {
+			claimed, err := repo.ClaimReferralReward(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claimed {
+				t.Fatalf("claimed a referral reward for %s", id)
+			}
+		}
+	})
+
+	t.Run("a user is deactivated once", func(t *testing.T) {
+		repo := newRepo(t)
+
+		user, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		user.Deactivate()
+		for i, want := range []bool{true, false} {
+			deactivated, err := repo.Deactivate(ctx, user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deactivated != want {
+				t.Fatalf("deactivation %d = %t, want %t", i+1, deactivated, want)
+			}
+		}
+		found, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.IsActive() {
+			t.Fatal("deactivation is not stored")
+		}
+	})
+
+	t.Run("erasing a user deletes their wallet challenges", func(t *testing.T) {
+		repo := newRepo(t)
+
+		user, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		challenge := newChallenge(t, repo, user)
+		kept := newChallenge(t, repo, other)
+
+		user.Erase()
+		for i, want := range []bool{true, false} {
+			erased, err := repo.Erase(ctx, user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if erased != want {
+				t.Fatalf("erasure %d = %t, want %t", i+1, erased, want)
+			}
+		}
+
+		found, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Email != user.Email || found.ExternalID != user.ExternalID || !found.IsErased() || found.IsActive() {
+			t.Fatalf("stored user %+v, want it erased", found)
+		}
+		_, err = repo.FindChallengeByNonce(ctx, challenge.Nonce)
+		assertError(t, err, domain.ErrChallengeNotFound)
+		if _, err := repo.FindChallengeByNonce(ctx, kept.Nonce); err != nil {
+			t.Fatalf("another user's challenge: %v", err)
+		}
+	})
+
+	t.Run("verified wallets are listed oldest first", func(t *testing.T) {
+		repo := newRepo(t)
+
+		user, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wallets, err := repo.FindWalletsByUserID(ctx, user.ID); err != nil || len(wallets) != 0 {
+			t.Fatalf("wallets before any was verified = %+v, %v", wallets, err)
+		}
+
+		verifiedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+		addresses := []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"}
+		for i, address := range addresses {
+			wallet := domain.NewWallet(user.ID, address)
+			wallet.VerifiedAt = verifiedAt
+			wallet.CreatedAt = verifiedAt.Add(time.Duration(i) * time.Minute)
+			if _, err := repo.AddWallet(ctx, wallet); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := repo.AddWallet(ctx, domain.NewWallet(other.ID, addresses[0])); err != nil {
+			t.Fatal(err)
+		}
+
+		// Verifying a known wallet again only refreshes it.
+		again, err := repo.AddWallet(ctx, domain.NewWallet(user.ID, addresses[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !again.VerifiedAt.After(verifiedAt) {
+			t.Fatalf("re-verified at %s, want after %s", again.VerifiedAt, verifiedAt)
+		}
+
+		wallets, err := repo.FindWalletsByUserID(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(wallets) != 2 || wallets[0].Address != addresses[0] || wallets[1].Address != addresses[1] || wallets[0].ID != again.ID {
+			t.Fatalf("wallets = %+v, want %v", wallets, addresses)
+		}
+
+		_, err = repo.FindWallet(ctx, other.ID, addresses[1])
+		assertError(t, err, domain.ErrWalletNotFound)
+	})
+
+	t.Run("tier updates are saved", func(t *testing.T) {
+		repo := newRepo(t)
+
+		user, err := repo.Create(ctx, newUser())
+		if err != nil {
+			t.Fatal(err)
+		}
+		user.ChangeTier(domain.TierSilver, 1500)
+		if err := repo.UpdateTier(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Tier != domain.TierSilver || found.RollingVolume != 1500 || found.TierUpdatedAt == nil {
+			t.Fatalf("found tier %q, volume %v, updated at %v", found.Tier, found.RollingVolume, found.TierUpdatedAt)
+		}
+	})
+
+	t.Run("stale tiers above bronze are found oldest first", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Now().UTC()
+
+		tiered := func(tier string, updatedAt time.Time, deactivated bool) uuid.UUID {
+			t.Helper()
+			user := newUser()
+			user.Tier = tier
+			user.TierUpdatedAt = &updatedAt
+			if deactivated {
+				user.DeactivatedAt = &now
+			}
+			created, err := repo.Create(ctx, user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return created.ID
+		}
+		oldest := tiered(domain.TierGold, now.Add(-72*time.Hour), false)
+		older := tiered(domain.TierSilver, now.Add(-48*time.Hour), false)
+		tiered(domain.TierSilver, now.Add(-time.Hour), false)
+		tiered(domain.TierBronze, now.Add(-72*time.Hour), false)
+		tiered(domain.TierGold, now.Add(-72*time.Hour), true)
+
+		before := now.Add(-24 * time.Hour)
+		stale, err := repo.FindStaleTiersBefore(ctx, before, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stale) != 2 || stale[0].ID != oldest || stale[1].ID != older {
+			t.Fatalf("found %d stale tiers, want %s then %s", len(stale), oldest, older)
+		}
+
+		first, err := repo.FindStaleTiersBefore(ctx, before, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(first) != 1 || first[0].ID != oldest {
+			t.Fatalf("limited lookup found %d users, want %s", len(first), oldest)
+		}
+	})
+
+	t.Run("concurrent signups with one email create one user", func(t *testing.T) {
+		repo := newRepo(t)
+
+		email := newUser().Email
+		const n = 10
+		errs := make(chan error, n)
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				user := newUser()
+				user.Email = email
+				_, err := repo.Create(ctx, user)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		created := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				created++
+			case !errors.Is(err, domain.ErrDuplicateUser):
+				t.Fatal(err)
+			}
+		}
+		if created != 1 {
+			t.Fatalf("created %d users, want 1", created)
+		}
+	})
+}
+
+func newUser() domain.User {
+	id := uuid.NewString()
+	verifiedAt := time.Now().UTC()
+	return domain.User{
+		ExternalID:       "ext-" + id,
+		Email:            id + "@example.com",
+		WalletAddress:    "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		WalletVerifiedAt: &verifiedAt,
+		ReferralCode:     id[:8],
+		Tier:             domain.TierBronze,
+	}
+}
+
+func newChallenge(t *testing.T, repo userRepository, user domain.User) domain.WalletChallenge {
+	t.Helper()
+	nonce, err := domain.NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	challenge, err := repo.CreateChallenge(context.Background(), domain.WalletChallenge{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Address:   user.WalletAddress,
+		Nonce:     nonce,
+		Message:   "example.com wants you to sign in with your Ethereum account",
+		ExpiresAt: now.Add(time.Minute),
+		CreatedAt: now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return challenge
+}
+
+func assertError(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+}
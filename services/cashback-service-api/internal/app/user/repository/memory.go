@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+// Memory keeps users, their wallet challenges and verified wallets in memory,
+// for tests of the usecases that register, look up, deactivate and erase users
+// without a database. Like the users table, it fills in the ID, tier and
+// timestamps left empty and refuses a second user with the same ID, external
+// ID, email or referral code.
+type Memory struct {
+	mu         sync.RWMutex
+	users      map[uuid.UUID]domain.User
+	challenges map[uuid.UUID]domain.WalletChallenge
+	wallets    []domain.Wallet
+}
+
+// NewMemory creates an empty in-memory user repository.
+func NewMemory() *Memory {
+	return &Memory{
+		users:      make(map[uuid.UUID]domain.User),
+		challenges: make(map[uuid.UUID]domain.WalletChallenge),
+	}
+}
+
+func (m *Memory) Create(_ context.Context, user domain.User) (domain.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	if user.Tier == "" {
+		user.Tier = domain.TierBronze
+	}
+
+	for _, other := range m.users {
+		if other.ID == user.ID || other.ExternalID == user.ExternalID ||
+			other.Email == user.Email || other.ReferralCode == user.ReferralCode {
+			return domain.User{}, domain.ErrDuplicateUser
+		}
+	}
+
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+	m.users[user.ID] = cloneUser(user)
+	return cloneUser(user), nil
+}
+
+func (m *Memory) FindByID(_ context.Context, id uuid.UUID) (domain.User, error) {
+	return m.find(func(user domain.User) bool { return user.ID == id })
+}
+
+func (m *Memory) FindByExternalID(_ context.Context, externalID string) (domain.User, error) {
+	return m.find(func(user domain.User) bool { return user.ExternalID == externalID })
+}
+
+func (m *Memory) FindByEmail(_ context.Context, email string) (domain.User, error) {
+	return m.find(func(user domain.User) bool { return user.Email == email })
+}
+
+func (m *Memory) FindByReferralCode(_ context.Context, code string) (domain.User, error) {
+	return m.find(func(user domain.User) bool { return user.ReferralCode == code })
+}
+
+// find returns the user matching, of which there is at most one: every
+// column looked up is unique.
+func (m *Memory) find(match func(domain.User) bool) (domain.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, user := range m.users {
+		if match(user) {
+			return cloneUser(user), nil
+		}
+	}
+	return domain.User{}, domain.ErrUserNotFound
+}
+
+// ClaimReferralReward marks the user's referral bonus as paid. It returns false
+// if the user was not referred or the bonus was already claimed.
+func (m *Memory) ClaimReferralReward(_ context.Context, userID uuid.UUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[userID]
+	if !ok || user.ReferredBy == nil || user.ReferralRewardedAt != nil {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	user.ReferralRewardedAt = &now
+	m.users[userID] = user
+	return true, nil
+}
+
+// ReleaseReferralReward undoes ClaimReferralReward.
+func (m *Memory) ReleaseReferralReward(_ context.Context, userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if user, ok := m.users[userID]; ok {
+		user.ReferralRewardedAt = nil
+		m.users[userID] = user
+	}
+	return nil
+}
+
+// FindStaleTiersBefore returns up to limit active users above bronze whose
+// tier was last computed before the given time, oldest first.
+func (m *Memory) FindStaleTiersBefore(_ context.Context, before time.Time, limit int) ([]domain.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var users []domain.User
+	for _, user := range m.users {
+		if user.Tier != domain.TierBronze && user.IsActive() &&
+			(user.TierUpdatedAt == nil || user.TierUpdatedAt.Before(before)) {
+			users = append(users, cloneUser(user))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		a, b := users[i].TierUpdatedAt, users[j].TierUpdatedAt
+		switch {
+		case a == nil || b == nil:
+			if a != b {
+				return a == nil
+			}
+		case !a.Equal(*b):
+			return a.Before(*b)
+		}
+		return bytes.Compare(users[i].ID[:], users[j].ID[:]) < 0
+	})
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+// UpdateTier saves the user's tier and rolling volume.
+func (m *Memory) UpdateTier(_ context.Context, user domain.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.users[user.ID]
+	if !ok {
+		return nil
+	}
+	stored.Tier = user.Tier
+	stored.RollingVolume = user.RollingVolume
+	stored.TierUpdatedAt = user.TierUpdatedAt
+	stored.UpdatedAt = user.UpdatedAt
+	m.users[user.ID] = cloneUser(stored)
+	return nil
+}
+
+// Deactivate stores the user's deactivation. It returns false if the user was
+// already deactivated.
+func (m *Memory) Deactivate(_ context.Context, user domain.User) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.users[user.ID]
+	if !ok || stored.DeactivatedAt != nil {
+		return false, nil
+	}
+	stored.DeactivatedAt = user.DeactivatedAt
+	stored.UpdatedAt = user.UpdatedAt
+	m.users[user.ID] = cloneUser(stored)
+	return true, nil
+}
+
+// Erase stores the pseudonymized user and deletes their wallet challenges. It
+// returns false if the user was already erased.
+func (m *Memory) Erase(_ context.Context, user domain.User) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, challenge := range m.challenges {
+		if challenge.UserID == user.ID {
+			delete(m.challenges, id)
+		}
+	}
+
+	stored, ok := m.users[user.ID]
+	if !ok || stored.ErasedAt != nil {
+		return false, nil
+	}
+	stored.Email = user.Email
+	stored.ExternalID = user.ExternalID
+	if stored.DeactivatedAt == nil {
+		stored.DeactivatedAt = user.DeactivatedAt
+	}
+	stored.ErasedAt = user.ErasedAt
+	stored.UpdatedAt = user.UpdatedAt
+	m.users[user.ID] = cloneUser(stored)
+	return true, nil
+}
+
+func (m *Memory) CreateChallenge(_ context.Context, challenge domain.WalletChallenge) (domain.WalletChallenge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if challenge.ID == uuid.Nil {
+		challenge.ID = uuid.New()
+	}
+	m.challenges[challenge.ID] = challenge
+	return challenge, nil
+}
+
+func (m *Memory) FindChallengeByNonce(_ context.Context, nonce string) (domain.WalletChallenge, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, challenge := range m.challenges {
+		if challenge.Nonce == nonce {
+			return challenge, nil
+		}
+	}
+	return domain.WalletChallenge{}, domain.ErrChallengeNotFound
+}
+
+// AddWallet stores a verified wallet. Re-verifying a known wallet refreshes
+// its verification time.
+func (m *Memory) AddWallet(_ context.Context, wallet domain.Wallet) (domain.Wallet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, known := range m.wallets {
+		if known.UserID == wallet.UserID && known.Address == wallet.Address {
+			m.wallets[i].VerifiedAt = wallet.VerifiedAt
+			return m.wallets[i], nil
+		}
+	}
+	if wallet.ID == uuid.Nil {
+		wallet.ID = uuid.New()
+	}
+	if wallet.CreatedAt.IsZero() {
+		wallet.CreatedAt = time.Now()
+	}
+	m.wallets = append(m.wallets, wallet)
+	return wallet, nil
+}
+
+func (m *Memory) FindWallet(_ context.Context, userID uuid.UUID, address string) (domain.Wallet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, wallet := range m.wallets {
+		if wallet.UserID == userID && wallet.Address == address {
+			return wallet, nil
+		}
+	}
+	return domain.Wallet{}, domain.ErrWalletNotFound
+}
+
+// FindWalletsByUserID returns the user's verified wallets, oldest first.
+func (m *Memory) FindWalletsByUserID(_ context.Context, userID uuid.UUID) ([]domain.Wallet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := []domain.Wallet{}
+	for _, wallet := range m.wallets {
+		if wallet.UserID == userID {
+			result = append(result, wallet)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+func cloneUser(user domain.User) domain.User {
+	for _, t := range []**time.Time{
+		&user.WalletVerifiedAt, &user.PayoutChangedAt, &user.ReferralRewardedAt,
+		&user.TierUpdatedAt, &user.DeactivatedAt, &user.ErasedAt,
+	} {
+		if *t != nil {
+			clone := **t
+			*t = &clone
+		}
+	}
+	if user.ReferredBy != nil {
+		referredBy := *user.ReferredBy
+		user.ReferredBy = &referredBy
+	}
+	return user
+}
@@ -9,12 +9,23 @@ import (
 
 // userModel represents the database model for users
 type userModel struct {
-	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	ExternalID    string    `gorm:"uniqueIndex;not null"`
-	Email         string    `gorm:"uniqueIndex;not null"`
-	WalletAddress string    `gorm:"not null"`
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+	ID                 uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ExternalID         string    `gorm:"uniqueIndex;not null"`
+	Email              string    `gorm:"uniqueIndex;not null"`
+	WalletAddress      string    `gorm:"type:varchar(42);not null;default:''"`
+	WalletVerifiedAt   *time.Time
+	PayoutChangedAt    *time.Time
+	CustodialAddress   string     `gorm:"type:varchar(42);not null;default:''"`
+	ReferralCode       string     `gorm:"type:varchar(16);uniqueIndex;not null"`
+	ReferredBy         *uuid.UUID `gorm:"type:uuid;index"`
+	ReferralRewardedAt *time.Time
+	Tier               string  `gorm:"type:varchar(20);not null;default:'bronze';index"`
+	RollingVolume      float64 `gorm:"not null;default:0"`
+	TierUpdatedAt      *time.Time
+	DeactivatedAt      *time.Time `gorm:"index"`
+	ErasedAt           *time.Time
+	CreatedAt          time.Time `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime"`
 }
 
 func (userModel) TableName() string {
@@ -24,23 +35,120 @@ func (userModel) TableName() string {
 // toDomain converts database model to domain entity
 func (m userModel) toDomain() domain.User {
 	return domain.User{
-		ID:            m.ID,
-		ExternalID:    m.ExternalID,
-		Email:         m.Email,
-		WalletAddress: m.WalletAddress,
-		CreatedAt:     m.CreatedAt,
-		UpdatedAt:     m.UpdatedAt,
+		ID:                 m.ID,
+		ExternalID:         m.ExternalID,
+		Email:              m.Email,
+		WalletAddress:      m.WalletAddress,
+		WalletVerifiedAt:   m.WalletVerifiedAt,
+		PayoutChangedAt:    m.PayoutChangedAt,
+		CustodialAddress:   m.CustodialAddress,
+		ReferralCode:       m.ReferralCode,
+		ReferredBy:         m.ReferredBy,
+		ReferralRewardedAt: m.ReferralRewardedAt,
+		Tier:               m.Tier,
+		RollingVolume:      m.RollingVolume,
+		TierUpdatedAt:      m.TierUpdatedAt,
+		DeactivatedAt:      m.DeactivatedAt,
+		ErasedAt:           m.ErasedAt,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
 	}
 }
 
 // fromDomain converts domain entity to database model
 func fromDomain(user domain.User) userModel {
 	return userModel{
-		ID:            user.ID,
-		ExternalID:    user.ExternalID,
-		Email:         user.Email,
-		WalletAddress: user.WalletAddress,
-		CreatedAt:     user.CreatedAt,
-		UpdatedAt:     user.UpdatedAt,
+		ID:                 user.ID,
+		ExternalID:         user.ExternalID,
+		Email:              user.Email,
+		WalletAddress:      user.WalletAddress,
+		WalletVerifiedAt:   user.WalletVerifiedAt,
+		PayoutChangedAt:    user.PayoutChangedAt,
+		CustodialAddress:   user.CustodialAddress,
+		ReferralCode:       user.ReferralCode,
+		ReferredBy:         user.ReferredBy,
+		ReferralRewardedAt: user.ReferralRewardedAt,
+		Tier:               user.Tier,
+		RollingVolume:      user.RollingVolume,
+		TierUpdatedAt:      user.TierUpdatedAt,
+		DeactivatedAt:      user.DeactivatedAt,
+		ErasedAt:           user.ErasedAt,
+		CreatedAt:          user.CreatedAt,
+		UpdatedAt:          user.UpdatedAt,
+	}
+}
+
+// walletChallengeModel represents the database model for wallet ownership challenges
+type walletChallengeModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Address   string    `gorm:"type:varchar(42);not null"`
+	Nonce     string    `gorm:"type:varchar(32);uniqueIndex;not null"`
+	Message   string    `gorm:"type:text;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (walletChallengeModel) TableName() string {
+	return "wallet_challenges"
+}
+
+func (m walletChallengeModel) toDomain() domain.WalletChallenge {
+	return domain.WalletChallenge{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Address:   m.Address,
+		Nonce:     m.Nonce,
+		Message:   m.Message,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func challengeFromDomain(challenge domain.WalletChallenge) walletChallengeModel {
+	return walletChallengeModel{
+		ID:        challenge.ID,
+		UserID:    challenge.UserID,
+		Address:   challenge.Address,
+		Nonce:     challenge.Nonce,
+		Message:   challenge.Message,
+		ExpiresAt: challenge.ExpiresAt,
+		UsedAt:    challenge.UsedAt,
+		CreatedAt: challenge.CreatedAt,
+	}
+}
+
+// walletModel represents the database model for a user's verified wallets
+type walletModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_wallets_user_address"`
+	Address    string    `gorm:"type:varchar(42);not null;uniqueIndex:idx_user_wallets_user_address"`
+	VerifiedAt time.Time `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (walletModel) TableName() string {
+	return "user_wallets"
+}
+
+func (m walletModel) toDomain() domain.Wallet {
+	return domain.Wallet{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Address:    m.Address,
+		VerifiedAt: m.VerifiedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func walletFromDomain(wallet domain.Wallet) walletModel {
+	return walletModel{
+		ID:         wallet.ID,
+		UserID:     wallet.UserID,
+		Address:    wallet.Address,
+		VerifiedAt: wallet.VerifiedAt,
+		CreatedAt:  wallet.CreatedAt,
 	}
 }
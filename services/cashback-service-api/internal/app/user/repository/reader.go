@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
 	"github.com/google/uuid"
@@ -15,7 +16,7 @@ func (r Repository) FindByID(ctx context.Context, id uuid.UUID) (domain.User, er
 	err := r.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return domain.User{}, errors.New("user not found")
+			return domain.User{}, domain.ErrUserNotFound
 		}
 		return domain.User{}, err
 	}
@@ -29,7 +30,7 @@ func (r Repository) FindByExternalID(ctx context.Context, externalID string) (do
 	err := r.db.WithContext(ctx).Where("external_id = ?", externalID).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return domain.User{}, errors.New("user not found")
+			return domain.User{}, domain.ErrUserNotFound
 		}
 		return domain.User{}, err
 	}
@@ -43,10 +44,91 @@ func (r Repository) FindByEmail(ctx context.Context, email string) (domain.User,
 	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return domain.User{}, errors.New("user not found")
+			return domain.User{}, domain.ErrUserNotFound
 		}
 		return domain.User{}, err
 	}
 
 	return user.toDomain(), nil
 }
+
+func (r Repository) FindByReferralCode(ctx context.Context, code string) (domain.User, error) {
+	var user userModel
+
+	err := r.db.WithContext(ctx).Where("referral_code = ?", code).First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, err
+	}
+
+	return user.toDomain(), nil
+}
+
+func (r Repository) FindChallengeByNonce(ctx context.Context, nonce string) (domain.WalletChallenge, error) {
+	var challenge walletChallengeModel
+
+	err := r.db.WithContext(ctx).Where("nonce = ?", nonce).First(&challenge).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.WalletChallenge{}, domain.ErrChallengeNotFound
+		}
+		return domain.WalletChallenge{}, err
+	}
+
+	return challenge.toDomain(), nil
+}
+
+func (r Repository) FindWallet(ctx context.Context, userID uuid.UUID, address string) (domain.Wallet, error) {
+	var wallet walletModel
+
+	err := r.db.WithContext(ctx).Where("user_id = ? AND address = ?", userID, address).First(&wallet).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Wallet{}, domain.ErrWalletNotFound
+		}
+		return domain.Wallet{}, err
+	}
+
+	return wallet.toDomain(), nil
+}
+
+func (r Repository) FindWalletsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Wallet, error) {
+	var wallets []walletModel
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&wallets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Wallet, len(wallets))
+	for i, w := range wallets {
+		result[i] = w.toDomain()
+	}
+
+	return result, nil
+}
+
+// FindStaleTiersBefore returns up to limit active users above bronze whose
+// tier was last computed before the given time, oldest first. Their rolling
+// volume may have dropped since, as purchases leave the window.
+func (r Repository) FindStaleTiersBefore(ctx context.Context, before time.Time, limit int) ([]domain.User, error) {
+	var users []userModel
+
+	err := r.db.WithContext(ctx).
+		Where("tier <> ? AND deactivated_at IS NULL AND (tier_updated_at IS NULL OR tier_updated_at < ?)", domain.TierBronze, before).
+		Order("tier_updated_at ASC NULLS FIRST, id ASC").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.User, len(users))
+	for i, u := range users {
+		result[i] = u.toDomain()
+	}
+
+	return result, nil
+}
@@ -2,16 +2,168 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (r Repository) Create(ctx context.Context, user domain.User) (domain.User, error) {
 	model := fromDomain(user)
 
 	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domain.User{}, domain.ErrDuplicateUser
+		}
 		return domain.User{}, err
 	}
 
 	return model.toDomain(), nil
 }
+
+// ClaimReferralReward marks the user's referral bonus as paid.
+// It returns false if the bonus was already claimed, so concurrent
+// cashback calculations cannot pay the bonus twice.
+func (r Repository) ClaimReferralReward(ctx context.Context, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&userModel{}).
+		Where("id = ? AND referred_by IS NOT NULL AND referral_rewarded_at IS NULL", userID).
+		Update("referral_rewarded_at", time.Now().UTC())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseReferralReward undoes ClaimReferralReward when the bonus could not be
+// issued, so the next qualifying purchase pays it instead.
+func (r Repository) ReleaseReferralReward(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&userModel{}).
+		Where("id = ?", userID).
+		Update("referral_rewarded_at", nil).Error
+}
+
+// UpdateTier saves the user's tier and rolling volume, within the transaction
+// of ctx if it has one.
+func (r Repository) UpdateTier(ctx context.Context, user domain.User) error {
+	return database.Conn(ctx, r.db).
+		Model(&userModel{}).
+		Where("id = ?", user.ID).
+		Updates(map[string]any{
+			"tier":            user.Tier,
+			"rolling_volume":  user.RollingVolume,
+			"tier_updated_at": user.TierUpdatedAt,
+			"updated_at":      user.UpdatedAt,
+		}).Error
+}
+
+func (r Repository) CreateChallenge(ctx context.Context, challenge domain.WalletChallenge) (domain.WalletChallenge, error) {
+	model := challengeFromDomain(challenge)
+
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return domain.WalletChallenge{}, err
+	}
+
+	return model.toDomain(), nil
+}
+
+// ConsumeChallenge marks the challenge as used so its nonce cannot be replayed.
+// Returns false if it was already used.
+func (r Repository) ConsumeChallenge(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&walletChallengeModel{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now().UTC())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// AddWallet stores a verified wallet. Re-verifying a known wallet refreshes
+// its verification time.
+func (r Repository) AddWallet(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error) {
+	model := walletFromDomain(wallet)
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "address"}},
+			DoUpdates: clause.AssignmentColumns([]string{"verified_at"}),
+		}).
+		Create(&model).Error
+	if err != nil {
+		return domain.Wallet{}, err
+	}
+
+	return r.FindWallet(ctx, wallet.UserID, wallet.Address)
+}
+
+func (r Repository) UpdatePayoutWallet(ctx context.Context, user domain.User) error {
+	return r.db.WithContext(ctx).
+		Model(&userModel{}).
+		Where("id = ?", user.ID).
+		Updates(map[string]any{
+			"wallet_address":     user.WalletAddress,
+			"wallet_verified_at": user.WalletVerifiedAt,
+			"payout_changed_at":  user.PayoutChangedAt,
+			"updated_at":         user.UpdatedAt,
+		}).Error
+}
+
+// UpdateProfile stores the user's email and external ID.
+func (r Repository) UpdateProfile(ctx context.Context, user domain.User) error {
+	return r.db.WithContext(ctx).
+		Model(&userModel{}).
+		Where("id = ?", user.ID).
+		Updates(map[string]any{
+			"email":       user.Email,
+			"external_id": user.ExternalID,
+			"updated_at":  user.UpdatedAt,
+		}).Error
+}
+
+// Deactivate soft-deletes the user. Returns false if the user was already deactivated.
+func (r Repository) Deactivate(ctx context.Context, user domain.User) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&userModel{}).
+		Where("id = ? AND deactivated_at IS NULL", user.ID).
+		Updates(map[string]any{
+			"deactivated_at": user.DeactivatedAt,
+			"updated_at":     user.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Erase stores the pseudonymized user and deletes their wallet challenges,
+// whose signed-in messages name the user's addresses. Returns false if the
+// user was already erased.
+func (r Repository) Erase(ctx context.Context, user domain.User) (bool, error) {
+	var erased bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&userModel{}).
+			Where("id = ? AND erased_at IS NULL", user.ID).
+			Updates(map[string]any{
+				"email":          user.Email,
+				"external_id":    user.ExternalID,
+				"deactivated_at": gorm.Expr("COALESCE(deactivated_at, ?)", user.DeactivatedAt),
+				"erased_at":      user.ErasedAt,
+				"updated_at":     user.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		erased = result.RowsAffected == 1
+
+		return tx.Where("user_id = ?", user.ID).Delete(&walletChallengeModel{}).Error
+	})
+	return erased, err
+}
@@ -0,0 +1,13 @@
+package claimcustodialwallet
+
+import "errors"
+
+var (
+	ErrInvalidAddress    = errors.New("invalid wallet address")
+	ErrNoCustodialWallet = errors.New("user has no custodial wallet")
+	ErrCustodialAddress  = errors.New("cannot claim to the custodial wallet itself")
+	ErrNothingToClaim    = errors.New("custodial wallet has no balance to claim")
+	ErrInvalidBalance    = errors.New("invalid custodial wallet balance")
+
+	ErrIdempotencyKeyRequired = errors.New("claims require an Idempotency-Key header")
+)
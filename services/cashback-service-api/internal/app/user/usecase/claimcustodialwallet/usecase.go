@@ -0,0 +1,159 @@
+package claimcustodialwallet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypeCustodialWalletClaimed = "user.custodial_wallet.claimed"
+)
+
+// transferKeyNamespace derives the idempotency key of a claim's transfer from
+// the user ID and the Idempotency-Key of the request, so a retried claim
+// returns the transfer already sent instead of sending another.
+var transferKeyNamespace = uuid.MustParse("3f0c2a4e-8d57-4b6f-9a21-5c7e0d9b14a8")
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		FindWallet(ctx context.Context, userID uuid.UUID, address string) (domain.Wallet, error)
+	}
+
+	// CustodialWallets reads and moves the tokens held in custodial wallets
+	CustodialWallets interface {
+		GetBalance(ctx context.Context, walletAddress string) (string, error)
+		TransferToken(ctx context.Context, idempotencyKey, ownerID, toAddress, tokenAmount string) (string, error)
+	}
+
+	// EventPublisher publishes events to the outbox
+	EventPublisher interface {
+		Publish(ctx context.Context, eventType string, payload any) error
+	}
+
+	// Policy configures how long a wallet must have been verified before the
+	// custodial balance can be claimed to it.
+	Policy struct {
+		Cooldown time.Duration
+	}
+
+	UseCase struct {
+		repository       Repository
+		custodialWallets CustodialWallets
+		eventPublisher   EventPublisher
+		policy           Policy
+		log              *slog.Logger
+	}
+
+	// Claim describes a transfer of the custodial balance to a user's own wallet
+	Claim struct {
+		UserID          uuid.UUID
+		FromAddress     string
+		ToAddress       string
+		TokenAmount     string
+		TransactionHash string
+		ClaimedAt       time.Time
+	}
+
+	// CustodialWalletClaimedEvent represents the event published when a custodial balance is claimed
+	CustodialWalletClaimedEvent struct {
+		UserID          string `json:"user_id"`
+		FromAddress     string `json:"from_address"`
+		ToAddress       string `json:"to_address"`
+		TokenAmount     string `json:"token_amount"`
+		TransactionHash string `json:"transaction_hash"`
+		ClaimedAt       string `json:"claimed_at"`
+	}
+)
+
+func New(repository Repository, custodialWallets CustodialWallets, eventPublisher EventPublisher, policy Policy, log *slog.Logger) UseCase {
+	return UseCase{
+		repository:       repository,
+		custodialWallets: custodialWallets,
+		eventPublisher:   eventPublisher,
+		policy:           policy,
+		log:              log,
+	}
+}
+
+// Execute exports the user's custodial balance by transferring all of it to
+// one of their verified wallets, once that wallet has been verified for the
+// cooldown. Claims are idempotent on idempotencyKey: repeating one returns
+// the transfer already sent. Cashback still in flight to the custodial wallet
+// can be claimed again once minted.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, address, idempotencyKey string) (Claim, error) {
+	if idempotencyKey == "" {
+		return Claim{}, ErrIdempotencyKeyRequired
+	}
+	if err := ethereum.ValidateAddress(address); err != nil {
+		return Claim{}, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	address = ethereum.ChecksumAddress(address)
+
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return Claim{}, err
+	}
+	if user.CustodialAddress == "" {
+		return Claim{}, ErrNoCustodialWallet
+	}
+	if address == user.CustodialAddress {
+		return Claim{}, ErrCustodialAddress
+	}
+
+	wallet, err := u.repository.FindWallet(ctx, user.ID, address)
+	if err != nil {
+		return Claim{}, err
+	}
+	if err := user.CanClaimTo(wallet, u.policy.Cooldown, time.Now().UTC()); err != nil {
+		return Claim{}, err
+	}
+
+	balance, err := u.custodialWallets.GetBalance(ctx, user.CustodialAddress)
+	if err != nil {
+		return Claim{}, err
+	}
+	amount, ok := new(big.Int).SetString(balance, 10)
+	if !ok {
+		return Claim{}, fmt.Errorf("%w: %q", ErrInvalidBalance, balance)
+	}
+	if amount.Sign() <= 0 {
+		return Claim{}, ErrNothingToClaim
+	}
+
+	transferKey := uuid.NewSHA1(transferKeyNamespace, []byte(user.ID.String()+"\n"+idempotencyKey))
+	txHash, err := u.custodialWallets.TransferToken(ctx, transferKey.String(), user.ID.String(), wallet.Address, amount.String())
+	if err != nil {
+		return Claim{}, err
+	}
+
+	claim := Claim{
+		UserID:          user.ID,
+		FromAddress:     user.CustodialAddress,
+		ToAddress:       wallet.Address,
+		TokenAmount:     amount.String(),
+		TransactionHash: txHash,
+		ClaimedAt:       time.Now().UTC(),
+	}
+
+	event := CustodialWalletClaimedEvent{
+		UserID:          claim.UserID.String(),
+		FromAddress:     claim.FromAddress,
+		ToAddress:       claim.ToAddress,
+		TokenAmount:     claim.TokenAmount,
+		TransactionHash: claim.TransactionHash,
+		ClaimedAt:       claim.ClaimedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if err := u.eventPublisher.Publish(ctx, EventTypeCustodialWalletClaimed, event); err != nil {
+		u.log.ErrorContext(ctx, "failed to publish custodial wallet claim", "user_id", user.ID, "error", err)
+	}
+
+	return claim, nil
+}
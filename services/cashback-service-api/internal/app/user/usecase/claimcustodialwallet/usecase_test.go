@@ -0,0 +1,194 @@
+package claimcustodialwallet_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/claimcustodialwallet"
+	"github.com/google/uuid"
+)
+
+const (
+	custodialAddress = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	ownAddress       = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	cooldown         = 24 * time.Hour
+)
+
+type fixture struct {
+	users     *users
+	custodial *custodialWallets
+	outbox    *outbox
+	usecase   claimcustodialwallet.UseCase
+	user      domain.User
+}
+
+// newFixture returns a custodial user holding balance, with ownAddress
+// verified age ago.
+func newFixture(balance string, age time.Duration) *fixture {
+	user := domain.User{ID: uuid.New()}
+	user.UseCustodialWallet(custodialAddress)
+
+	f := &fixture{
+		users: &users{
+			user:   user,
+			wallet: domain.Wallet{UserID: user.ID, Address: ownAddress, VerifiedAt: time.Now().UTC().Add(-age)},
+		},
+		custodial: &custodialWallets{balance: balance},
+		outbox:    &outbox{},
+		user:      user,
+	}
+	f.usecase = claimcustodialwallet.New(f.users, f.custodial, f.outbox,
+		claimcustodialwallet.Policy{Cooldown: cooldown},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+func (f *fixture) claim(key string) (claimcustodialwallet.Claim, error) {
+	return f.usecase.Execute(context.Background(), f.user.ID, ownAddress, key)
+}
+
+func TestClaimTransfersTheWholeBalance(t *testing.T) {
+	f := newFixture("1500", 2*cooldown)
+
+	claim, err := f.claim("claim-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.FromAddress != custodialAddress || claim.ToAddress != ownAddress || claim.TokenAmount != "1500" {
+		t.Fatalf("claim %+v", claim)
+	}
+	transfer := f.custodial.transfers[0]
+	if transfer.ownerID != f.user.ID.String() || transfer.to != ownAddress || transfer.amount != "1500" {
+		t.Fatalf("transfer %+v", transfer)
+	}
+	if len(f.outbox.events) != 1 {
+		t.Fatalf("published %d events, want 1", len(f.outbox.events))
+	}
+}
+
+func TestClaimDerivesTheTransferKeyFromTheRequest(t *testing.T) {
+	f := newFixture("1500", 2*cooldown)
+	other := newFixture("1500", 2*cooldown)
+
+	for _, key := range []string{"claim-1", "claim-1", "claim-2"} {
+		if _, err := f.claim(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := other.claim("claim-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := f.custodial.keys()
+	if keys[0] != keys[1] {
+		t.Fatalf("a retried claim used transfer keys %s and %s", keys[0], keys[1])
+	}
+	if keys[2] == keys[0] {
+		t.Fatal("a new Idempotency-Key reused the transfer key")
+	}
+	if other.custodial.keys()[0] == keys[0] {
+		t.Fatal("two users share a transfer key")
+	}
+	if _, err := uuid.Parse(keys[0]); err != nil {
+		t.Fatalf("transfer key %q is not a UUID", keys[0])
+	}
+}
+
+func TestClaimRefusals(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		balance string
+		age     time.Duration
+		key     string
+		address string
+		want    error
+	}{
+		{"no idempotency key", "1500", 2 * cooldown, "", ownAddress, claimcustodialwallet.ErrIdempotencyKeyRequired},
+		{"wallet verified within the cooldown", "1500", cooldown / 2, "claim-1", ownAddress, domain.ErrWalletTooNew},
+		{"empty balance", "0", 2 * cooldown, "claim-1", ownAddress, claimcustodialwallet.ErrNothingToClaim},
+		{"unreadable balance", "lots", 2 * cooldown, "claim-1", ownAddress, claimcustodialwallet.ErrInvalidBalance},
+		{"to the custodial wallet", "1500", 2 * cooldown, "claim-1", custodialAddress, claimcustodialwallet.ErrCustodialAddress},
+		{"invalid address", "1500", 2 * cooldown, "claim-1", "0x123", claimcustodialwallet.ErrInvalidAddress},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(tc.balance, tc.age)
+
+			_, err := f.usecase.Execute(context.Background(), f.user.ID, tc.address, tc.key)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want %v", err, tc.want)
+			}
+			if len(f.custodial.transfers) != 0 {
+				t.Fatalf("sent %d transfers", len(f.custodial.transfers))
+			}
+		})
+	}
+}
+
+func TestClaimWithoutCustodialWallet(t *testing.T) {
+	f := newFixture("1500", 2*cooldown)
+	f.users.user.CustodialAddress = ""
+
+	if _, err := f.claim("claim-1"); !errors.Is(err, claimcustodialwallet.ErrNoCustodialWallet) {
+		t.Fatalf("error = %v, want %v", err, claimcustodialwallet.ErrNoCustodialWallet)
+	}
+}
+
+// users holds one user and one verified wallet.
+type users struct {
+	user   domain.User
+	wallet domain.Wallet
+}
+
+func (u *users) FindByID(_ context.Context, id uuid.UUID) (domain.User, error) {
+	if id != u.user.ID {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return u.user, nil
+}
+
+func (u *users) FindWallet(_ context.Context, userID uuid.UUID, address string) (domain.Wallet, error) {
+	if userID != u.wallet.UserID || address != u.wallet.Address {
+		return domain.Wallet{}, domain.ErrWalletNotFound
+	}
+	return u.wallet, nil
+}
+
+type transfer struct {
+	key, ownerID, to, amount string
+}
+
+type custodialWallets struct {
+	balance   string
+	transfers []transfer
+}
+
+func (c *custodialWallets) GetBalance(context.Context, string) (string, error) {
+	return c.balance, nil
+}
+
+func (c *custodialWallets) TransferToken(_ context.Context, idempotencyKey, ownerID, toAddress, tokenAmount string) (string, error) {
+	c.transfers = append(c.transfers, transfer{idempotencyKey, ownerID, toAddress, tokenAmount})
+	return "0x" + idempotencyKey, nil
+}
+
+func (c *custodialWallets) keys() []string {
+	keys := make([]string, len(c.transfers))
+	for i, t := range c.transfers {
+		keys[i] = t.key
+	}
+	return keys
+}
+
+type outbox struct {
+	events []any
+}
+
+func (o *outbox) Publish(_ context.Context, _ string, payload any) error {
+	o.events = append(o.events, payload)
+	return nil
+}
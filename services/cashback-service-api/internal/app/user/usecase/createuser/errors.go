@@ -3,5 +3,9 @@ package createuser
 import "errors"
 
 var (
-	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrUserAlreadyExists   = errors.New("user already exists")
+	ErrInvalidReferralCode = errors.New("invalid referral code")
+	ErrSelfReferral        = errors.New("referral code belongs to the same wallet")
+
+	ErrCustodialWalletUnavailable = errors.New("custodial wallet unavailable")
 )
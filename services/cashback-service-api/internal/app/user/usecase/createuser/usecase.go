@@ -2,9 +2,12 @@ package createuser
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
 	"github.com/google/uuid"
 )
 
@@ -13,20 +16,40 @@ type (
 		Create(ctx context.Context, user domain.User) (domain.User, error)
 		FindByEmail(ctx context.Context, email string) (domain.User, error)
 		FindByExternalID(ctx context.Context, externalID string) (domain.User, error)
+		FindByReferralCode(ctx context.Context, code string) (domain.User, error)
+		FindWalletsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Wallet, error)
+	}
+
+	// CustodialWallets derives the platform-held wallet of a user
+	CustodialWallets interface {
+		DeriveCustodialAddress(ctx context.Context, ownerID string) (string, error)
+	}
+
+	// Policy configures the custodial fallback for users signing up without a wallet.
+	Policy struct {
+		CustodialFallback bool
 	}
 
 	UseCase struct {
-		repository Repository
+		repository       Repository
+		custodialWallets CustodialWallets
+		policy           Policy
 	}
 )
 
-func New(repository Repository) UseCase {
+func New(repository Repository, custodialWallets CustodialWallets, policy Policy) UseCase {
 	return UseCase{
-		repository: repository,
+		repository:       repository,
+		custodialWallets: custodialWallets,
+		policy:           policy,
 	}
 }
 
-func (u UseCase) Execute(ctx context.Context, externalID, email, walletAddress string) (domain.User, error) {
+// Execute registers a new user. referralCode is optional; when given it must
+// belong to an existing user who does not share the new user's wallet.
+// Users signing up without a wallet are paid out to a custodial wallet when
+// the custodial fallback is enabled, and have their cashback held otherwise.
+func (u UseCase) Execute(ctx context.Context, externalID, email, walletAddress, referralCode string) (domain.User, error) {
 	if existingUser, _ := u.repository.FindByEmail(ctx, email); existingUser.ID != uuid.Nil {
 		return domain.User{}, ErrUserAlreadyExists
 	}
@@ -35,14 +58,66 @@ func (u UseCase) Execute(ctx context.Context, externalID, email, walletAddress s
 		return domain.User{}, ErrUserAlreadyExists
 	}
 
+	if walletAddress != "" {
+		walletAddress = ethereum.ChecksumAddress(walletAddress)
+	}
+
+	code, err := domain.NewReferralCode()
+	if err != nil {
+		return domain.User{}, err
+	}
+
 	user := domain.User{
 		ID:            uuid.New(),
 		ExternalID:    externalID,
 		Email:         email,
 		WalletAddress: walletAddress,
+		ReferralCode:  code,
+		Tier:          domain.TierBronze,
 		CreatedAt:     time.Now().UTC(),
 		UpdatedAt:     time.Now().UTC(),
 	}
 
-	return u.repository.Create(ctx, user)
+	if walletAddress == "" && u.policy.CustodialFallback {
+		address, err := u.custodialWallets.DeriveCustodialAddress(ctx, user.ID.String())
+		if err != nil {
+			return domain.User{}, fmt.Errorf("%w: %w", ErrCustodialWalletUnavailable, err)
+		}
+		user.UseCustodialWallet(address)
+	}
+
+	if referralCode != "" {
+		referrer, err := u.findReferrer(ctx, referralCode)
+		if err != nil {
+			return domain.User{}, err
+		}
+		// The new user has no verified wallets yet, only the one they signed
+		// up with.
+		referrerWallets, err := u.repository.FindWalletsByUserID(ctx, referrer.ID)
+		if err != nil {
+			return domain.User{}, err
+		}
+		if referrer.SharesWalletWith(referrerWallets, user, nil) {
+			return domain.User{}, ErrSelfReferral
+		}
+		user.ReferredBy = &referrer.ID
+	}
+
+	created, err := u.repository.Create(ctx, user)
+	if errors.Is(err, domain.ErrDuplicateUser) {
+		// A concurrent signup took the email or external ID after the checks above
+		return domain.User{}, ErrUserAlreadyExists
+	}
+	return created, err
+}
+
+func (u UseCase) findReferrer(ctx context.Context, referralCode string) (domain.User, error) {
+	referrer, err := u.repository.FindByReferralCode(ctx, domain.NormalizeReferralCode(referralCode))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.User{}, ErrInvalidReferralCode
+		}
+		return domain.User{}, err
+	}
+	return referrer, nil
 }
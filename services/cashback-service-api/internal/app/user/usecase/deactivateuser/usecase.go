@@ -0,0 +1,78 @@
+package deactivateuser
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypeUserDeactivated = "user.deactivated"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		Deactivate(ctx context.Context, user domain.User) (bool, error)
+	}
+
+	// EventPublisher publishes events to the outbox
+	EventPublisher interface {
+		Publish(ctx context.Context, eventType string, payload any) error
+	}
+
+	UseCase struct {
+		repository     Repository
+		eventPublisher EventPublisher
+		log            *slog.Logger
+	}
+
+	// UserDeactivatedEvent represents the event published when a user is deactivated
+	UserDeactivatedEvent struct {
+		UserID        string `json:"user_id"`
+		DeactivatedAt string `json:"deactivated_at"`
+	}
+)
+
+func New(repository Repository, eventPublisher EventPublisher, log *slog.Logger) UseCase {
+	return UseCase{
+		repository:     repository,
+		eventPublisher: eventPublisher,
+		log:            log,
+	}
+}
+
+// Execute soft-deletes the user so no new cashback is calculated for them.
+// Cashback already approved is still minted. Deactivating a deactivated user
+// is a no-op.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !user.IsActive() {
+		return user, nil
+	}
+
+	user.Deactivate()
+	deactivated, err := u.repository.Deactivate(ctx, user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !deactivated {
+		// A concurrent request won the race; report its outcome
+		return u.repository.FindByID(ctx, userID)
+	}
+
+	event := UserDeactivatedEvent{
+		UserID:        user.ID.String(),
+		DeactivatedAt: user.DeactivatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if err := u.eventPublisher.Publish(ctx, EventTypeUserDeactivated, event); err != nil {
+		u.log.ErrorContext(ctx, "failed to publish user deactivation", "user_id", user.ID, "error", err)
+	}
+
+	return user, nil
+}
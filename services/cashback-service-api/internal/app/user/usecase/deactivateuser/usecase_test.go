@@ -0,0 +1,120 @@
+package deactivateuser_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/deactivateuser"
+	"github.com/google/uuid"
+)
+
+type fixture struct {
+	users   *repository.Memory
+	outbox  *outbox
+	usecase deactivateuser.UseCase
+	user    domain.User
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{users: repository.NewMemory(), outbox: &outbox{}}
+	f.usecase = deactivateuser.New(f.users, f.outbox, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	id := uuid.NewString()
+	user, err := f.users.Create(context.Background(), domain.User{
+		ExternalID:   "ext-" + id,
+		Email:        id + "@example.com",
+		ReferralCode: id[:8],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.user = user
+	return f
+}
+
+func TestDeactivateIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	deactivated, err := f.usecase.Execute(ctx, f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deactivated.IsActive() {
+		t.Fatal("user is still active")
+	}
+
+	again, err := f.usecase.Execute(ctx, f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.DeactivatedAt.Equal(*deactivated.DeactivatedAt) {
+		t.Fatalf("second deactivation at %v, want %v", again.DeactivatedAt, deactivated.DeactivatedAt)
+	}
+	if n := f.outbox.count(); n != 1 {
+		t.Fatalf("published %d user.deactivated events, want 1", n)
+	}
+}
+
+func TestConcurrentDeactivationsPublishOnce(t *testing.T) {
+	f := newFixture(t)
+
+	const requests = 8
+	results := make(chan domain.User, requests)
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := f.usecase.Execute(context.Background(), f.user.ID)
+			results <- user
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	stored, err := f.users.FindByID(context.Background(), f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for user := range results {
+		if !user.DeactivatedAt.Equal(*stored.DeactivatedAt) {
+			t.Fatalf("a request reported deactivation at %v, stored %v", user.DeactivatedAt, stored.DeactivatedAt)
+		}
+	}
+	if n := f.outbox.count(); n != 1 {
+		t.Fatalf("published %d user.deactivated events, want 1", n)
+	}
+}
+
+type outbox struct {
+	mu     sync.Mutex
+	events []any
+}
+
+func (o *outbox) Publish(_ context.Context, _ string, payload any) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, payload)
+	return nil
+}
+
+func (o *outbox) count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.events)
+}
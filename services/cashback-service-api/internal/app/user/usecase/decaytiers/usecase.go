@@ -0,0 +1,85 @@
+// Package decaytiers recomputes loyalty tiers that went stale: the rolling
+// volume only drops as purchases leave the window, which no purchase or
+// refund event reports.
+package decaytiers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	// Repository interface for stale tier lookups
+	Repository interface {
+		FindStaleTiersBefore(ctx context.Context, before time.Time, limit int) ([]domain.User, error)
+	}
+
+	// TierUpdater recomputes the tier of a single user
+	TierUpdater interface {
+		Execute(ctx context.Context, userID uuid.UUID) (domain.User, error)
+	}
+
+	// Policy configures when a tier is recomputed.
+	Policy struct {
+		// StaleAfter is how long a tier may go without being recomputed.
+		StaleAfter time.Duration
+		// BatchSize caps how many users are looked up at once.
+		BatchSize int
+	}
+
+	UseCase struct {
+		repository  Repository
+		tierUpdater TierUpdater
+		policy      Policy
+		log         *slog.Logger
+	}
+
+	// Result summarizes a single run.
+	Result struct {
+		Recomputed int
+		Demoted    int
+	}
+)
+
+func New(repository Repository, tierUpdater TierUpdater, policy Policy, log *slog.Logger) UseCase {
+	return UseCase{
+		repository:  repository,
+		tierUpdater: tierUpdater,
+		policy:      policy,
+		log:         log,
+	}
+}
+
+// Execute recomputes every tier above bronze last computed more than
+// StaleAfter ago. Recomputing refreshes the tier's timestamp, so each batch
+// moves on to the next users; users moved down publish user.tier.changed.
+func (u UseCase) Execute(ctx context.Context) (Result, error) {
+	before := time.Now().UTC().Add(-u.policy.StaleAfter)
+
+	var result Result
+	for {
+		users, err := u.repository.FindStaleTiersBefore(ctx, before, u.policy.BatchSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, user := range users {
+			updated, err := u.tierUpdater.Execute(ctx, user.ID)
+			if err != nil {
+				return result, err
+			}
+			result.Recomputed++
+			if !domain.TierAtLeast(updated.Tier, user.Tier) {
+				result.Demoted++
+			}
+		}
+
+		if len(users) < u.policy.BatchSize {
+			return result, nil
+		}
+	}
+}
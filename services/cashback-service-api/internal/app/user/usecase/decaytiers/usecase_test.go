@@ -0,0 +1,151 @@
+package decaytiers_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	purchasedomain "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	purchaserepository "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/decaytiers"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updatetier"
+	"github.com/google/uuid"
+)
+
+const (
+	window     = 90 * 24 * time.Hour
+	staleAfter = 24 * time.Hour
+)
+
+type fixture struct {
+	users     *repository.Memory
+	purchases *purchaserepository.Memory
+	outbox    *outbox
+	usecase   decaytiers.UseCase
+}
+
+func newFixture(batchSize int) *fixture {
+	f := &fixture{
+		users:     repository.NewMemory(),
+		purchases: purchaserepository.NewMemory(),
+		outbox:    &outbox{},
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tiers := updatetier.New(f.users, f.purchases, f.outbox, transactor{}, updatetier.Policy{
+		Thresholds: domain.TierThresholds{Silver: 1000, Gold: 5000},
+		Window:     window,
+	}, log)
+	f.usecase = decaytiers.New(f.users, tiers, decaytiers.Policy{StaleAfter: staleAfter, BatchSize: batchSize}, log)
+	return f
+}
+
+// user records a user at tier, computed age ago from a purchase of amount
+// made purchaseAge ago.
+func (f *fixture) user(t *testing.T, tier string, age time.Duration, amount float64, purchaseAge time.Duration) uuid.UUID {
+	t.Helper()
+	id := uuid.NewString()
+	updatedAt := time.Now().UTC().Add(-age)
+	user, err := f.users.Create(context.Background(), domain.User{
+		ExternalID:    "ext-" + id,
+		Email:         id + "@example.com",
+		ReferralCode:  id[:8],
+		Tier:          tier,
+		TierUpdatedAt: &updatedAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	purchase := purchasedomain.NewPurchase(user.ID, amount, uuid.New())
+	purchase.CreatedAt = time.Now().UTC().Add(-purchaseAge)
+	if _, err := f.purchases.Create(context.Background(), purchase); err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+func (f *fixture) tier(t *testing.T, id uuid.UUID) string {
+	t.Helper()
+	user, err := f.users.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user.Tier
+}
+
+func TestDecayDemotesUsersWhosePurchasesLeftTheWindow(t *testing.T) {
+	f := newFixture(100)
+	lapsed := f.user(t, domain.TierGold, 2*staleAfter, 6000, window+time.Hour)
+	partly := f.user(t, domain.TierGold, 2*staleAfter, 1500, window-time.Hour)
+	kept := f.user(t, domain.TierSilver, 2*staleAfter, 1500, window-time.Hour)
+	fresh := f.user(t, domain.TierGold, staleAfter/2, 6000, window+time.Hour)
+
+	result, err := f.usecase.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Recomputed != 3 || result.Demoted != 2 {
+		t.Fatalf("result %+v, want 3 recomputed and 2 demoted", result)
+	}
+
+	for _, tc := range []struct {
+		name string
+		id   uuid.UUID
+		want string
+	}{
+		{"lapsed", lapsed, domain.TierBronze},
+		{"partly lapsed", partly, domain.TierSilver},
+		{"kept", kept, domain.TierSilver},
+		// Recomputed on a later run, once its tier is stale.
+		{"fresh", fresh, domain.TierGold},
+	} {
+		if got := f.tier(t, tc.id); got != tc.want {
+			t.Errorf("%s user is %q, want %q", tc.name, got, tc.want)
+		}
+	}
+	if len(f.outbox.events) != 2 {
+		t.Fatalf("published %d tier changes, want 2", len(f.outbox.events))
+	}
+}
+
+func TestDecayWorksThroughEveryBatch(t *testing.T) {
+	f := newFixture(2)
+	for range 5 {
+		f.user(t, domain.TierSilver, 2*staleAfter, 2000, window+time.Hour)
+	}
+
+	result, err := f.usecase.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Recomputed != 5 || result.Demoted != 5 {
+		t.Fatalf("result %+v, want all 5 users demoted", result)
+	}
+
+	again, err := f.usecase.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Recomputed != 0 {
+		t.Fatalf("second run recomputed %d users, want 0", again.Recomputed)
+	}
+}
+
+type transactor struct{}
+
+func (transactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type outbox struct {
+	events []any
+}
+
+func (o *outbox) Publish(_ context.Context, _ string, payload any) error {
+	o.events = append(o.events, payload)
+	return nil
+}
@@ -0,0 +1,81 @@
+package eraseuser
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypeUserErased = "user.erased"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		Erase(ctx context.Context, user domain.User) (bool, error)
+	}
+
+	// EventPublisher publishes events to the outbox
+	EventPublisher interface {
+		Publish(ctx context.Context, eventType string, payload any) error
+	}
+
+	UseCase struct {
+		repository     Repository
+		eventPublisher EventPublisher
+		log            *slog.Logger
+	}
+
+	// UserErasedEvent represents the event published when a user's personal data is erased.
+	// It carries no personal data itself.
+	UserErasedEvent struct {
+		UserID   string `json:"user_id"`
+		ErasedAt string `json:"erased_at"`
+	}
+)
+
+func New(repository Repository, eventPublisher EventPublisher, log *slog.Logger) UseCase {
+	return UseCase{
+		repository:     repository,
+		eventPublisher: eventPublisher,
+		log:            log,
+	}
+}
+
+// Execute handles a GDPR erasure request: the user's email and external ID
+// are pseudonymized and the account is deactivated. Cashback rows and
+// on-chain history are kept for ledger integrity, linked by user ID only.
+// Other services are told through user.erased to scrub their own copies.
+// Erasing an erased user is a no-op.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.IsErased() {
+		return user, nil
+	}
+
+	user.Erase()
+	erased, err := u.repository.Erase(ctx, user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !erased {
+		// A concurrent request won the race; report its outcome
+		return u.repository.FindByID(ctx, userID)
+	}
+
+	event := UserErasedEvent{
+		UserID:   user.ID.String(),
+		ErasedAt: user.ErasedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if err := u.eventPublisher.Publish(ctx, EventTypeUserErased, event); err != nil {
+		u.log.ErrorContext(ctx, "failed to publish user erasure", "user_id", user.ID, "error", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,161 @@
+package eraseuser_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/eraseuser"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/requestwalletchallenge"
+	"github.com/google/uuid"
+)
+
+const wallet = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+type fixture struct {
+	users   *repository.Memory
+	outbox  *outbox
+	usecase eraseuser.UseCase
+	user    domain.User
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{users: repository.NewMemory(), outbox: &outbox{}}
+	f.usecase = eraseuser.New(f.users, f.outbox, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	id := uuid.NewString()
+	user, err := f.users.Create(context.Background(), domain.User{
+		ExternalID:   "ext-" + id,
+		Email:        id + "@example.com",
+		ReferralCode: id[:8],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.user = user
+	return f
+}
+
+func TestEraseIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	erased, err := f.usecase.Execute(ctx, f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !erased.IsErased() || erased.IsActive() || erased.Email == f.user.Email || erased.ExternalID == f.user.ExternalID {
+		t.Fatalf("erased user %+v", erased)
+	}
+
+	again, err := f.usecase.Execute(ctx, f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.ErasedAt.Equal(*erased.ErasedAt) || again.Email != erased.Email {
+		t.Fatalf("second erasure returned %+v, want %+v", again, erased)
+	}
+	if n := f.outbox.count(eraseuser.EventTypeUserErased); n != 1 {
+		t.Fatalf("published %d user.erased events, want 1", n)
+	}
+
+	if _, err := f.usecase.Execute(ctx, uuid.New()); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+}
+
+func TestConcurrentErasuresPublishOnce(t *testing.T) {
+	f := newFixture(t)
+
+	const requests = 8
+	results := make(chan domain.User, requests)
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := f.usecase.Execute(context.Background(), f.user.ID)
+			results <- user
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	stored, err := f.users.FindByID(context.Background(), f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for user := range results {
+		if !user.ErasedAt.Equal(*stored.ErasedAt) {
+			t.Fatalf("a request reported erasure at %v, stored %v", user.ErasedAt, stored.ErasedAt)
+		}
+	}
+	if n := f.outbox.count(eraseuser.EventTypeUserErased); n != 1 {
+		t.Fatalf("published %d user.erased events, want 1", n)
+	}
+}
+
+func TestErasureRemovesWalletChallenges(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	challenges := requestwalletchallenge.New(f.users, requestwalletchallenge.Policy{
+		Domain:  "cashback.example.com",
+		URI:     "https://cashback.example.com",
+		ChainID: 1,
+		TTL:     time.Minute,
+	})
+
+	challenge, err := challenges.Execute(ctx, f.user.ID, wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.usecase.Execute(ctx, f.user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.users.FindChallengeByNonce(ctx, challenge.Nonce); !errors.Is(err, domain.ErrChallengeNotFound) {
+		t.Fatalf("challenge lookup error = %v, want %v", err, domain.ErrChallengeNotFound)
+	}
+	if _, err := challenges.Execute(ctx, f.user.ID, wallet); !errors.Is(err, domain.ErrUserErased) {
+		t.Fatalf("new challenge error = %v, want %v", err, domain.ErrUserErased)
+	}
+}
+
+type outbox struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *outbox) Publish(_ context.Context, eventType string, _ any) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, eventType)
+	return nil
+}
+
+func (o *outbox) count(eventType string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := 0
+	for _, e := range o.events {
+		if e == eventType {
+			n++
+		}
+	}
+	return n
+}
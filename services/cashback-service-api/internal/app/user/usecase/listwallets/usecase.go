@@ -0,0 +1,40 @@
+package listwallets
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		FindWalletsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Wallet, error)
+	}
+
+	UseCase struct {
+		repository Repository
+	}
+)
+
+func New(repository Repository) UseCase {
+	return UseCase{
+		repository: repository,
+	}
+}
+
+// Execute returns the user together with their verified wallets.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID) (domain.User, []domain.Wallet, error) {
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	wallets, err := u.repository.FindWalletsByUserID(ctx, user.ID)
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	return user, wallets, nil
+}
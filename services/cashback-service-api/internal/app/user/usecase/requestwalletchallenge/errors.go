@@ -0,0 +1,7 @@
+package requestwalletchallenge
+
+import "errors"
+
+var (
+	ErrInvalidAddress = errors.New("invalid wallet address")
+)
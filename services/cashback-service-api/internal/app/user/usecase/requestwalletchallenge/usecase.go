@@ -0,0 +1,85 @@
+package requestwalletchallenge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/siwe"
+	"github.com/google/uuid"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		CreateChallenge(ctx context.Context, challenge domain.WalletChallenge) (domain.WalletChallenge, error)
+	}
+
+	// Policy describes the EIP-4361 messages issued by this service.
+	Policy struct {
+		Domain    string
+		URI       string
+		Statement string
+		ChainID   int
+		TTL       time.Duration
+	}
+
+	UseCase struct {
+		repository Repository
+		policy     Policy
+	}
+)
+
+func New(repository Repository, policy Policy) UseCase {
+	return UseCase{
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+// Execute issues a one-time EIP-4361 challenge the user must sign with the
+// wallet at address to prove they own it. Erased users get none, since the
+// message would store an address of theirs again.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, address string) (domain.WalletChallenge, error) {
+	if err := ethereum.ValidateAddress(address); err != nil {
+		return domain.WalletChallenge{}, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	address = ethereum.ChecksumAddress(address)
+
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return domain.WalletChallenge{}, err
+	}
+	if user.IsErased() {
+		return domain.WalletChallenge{}, domain.ErrUserErased
+	}
+
+	nonce, err := domain.NewNonce()
+	if err != nil {
+		return domain.WalletChallenge{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	message := siwe.Message{
+		Domain:         u.policy.Domain,
+		Address:        address,
+		Statement:      u.policy.Statement,
+		URI:            u.policy.URI,
+		ChainID:        u.policy.ChainID,
+		Nonce:          nonce,
+		IssuedAt:       now,
+		ExpirationTime: now.Add(u.policy.TTL),
+	}
+
+	return u.repository.CreateChallenge(ctx, domain.WalletChallenge{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Address:   address,
+		Nonce:     nonce,
+		Message:   message.String(),
+		ExpiresAt: message.ExpirationTime,
+		CreatedAt: now,
+	})
+}
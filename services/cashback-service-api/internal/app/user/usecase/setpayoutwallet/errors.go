@@ -0,0 +1,7 @@
+package setpayoutwallet
+
+import "errors"
+
+var (
+	ErrInvalidAddress = errors.New("invalid wallet address")
+)
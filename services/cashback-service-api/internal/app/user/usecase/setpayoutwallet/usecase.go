@@ -0,0 +1,104 @@
+package setpayoutwallet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypePayoutWalletChanged = "user.payout_wallet.changed"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		FindWallet(ctx context.Context, userID uuid.UUID, address string) (domain.Wallet, error)
+		UpdatePayoutWallet(ctx context.Context, user domain.User) error
+	}
+
+	// EventPublisher publishes events to the outbox
+	EventPublisher interface {
+		Publish(ctx context.Context, eventType string, payload any) error
+	}
+
+	// Policy configures the payout wallet change cooldown.
+	Policy struct {
+		Cooldown time.Duration
+	}
+
+	UseCase struct {
+		repository     Repository
+		eventPublisher EventPublisher
+		policy         Policy
+		log            *slog.Logger
+	}
+
+	// PayoutWalletChangedEvent represents the event published when the payout wallet changes
+	PayoutWalletChangedEvent struct {
+		UserID         string `json:"user_id"`
+		PreviousWallet string `json:"previous_wallet"`
+		WalletAddress  string `json:"wallet_address"`
+		ChangedAt      string `json:"changed_at"`
+	}
+)
+
+func New(repository Repository, eventPublisher EventPublisher, policy Policy, log *slog.Logger) UseCase {
+	return UseCase{
+		repository:     repository,
+		eventPublisher: eventPublisher,
+		policy:         policy,
+		log:            log,
+	}
+}
+
+// Execute makes one of the user's verified wallets the payout wallet.
+// Only cashback approved afterwards is minted to it; cashback already in
+// flight keeps the wallet captured at its approval.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, address string) (domain.User, error) {
+	if err := ethereum.ValidateAddress(address); err != nil {
+		return domain.User{}, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	address = ethereum.ChecksumAddress(address)
+
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	wallet, err := u.repository.FindWallet(ctx, user.ID, address)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if user.HasVerifiedWallet() && user.WalletAddress == wallet.Address {
+		return user, nil
+	}
+
+	if err := user.CanSwitchPayoutTo(wallet, u.policy.Cooldown, time.Now().UTC()); err != nil {
+		return domain.User{}, err
+	}
+
+	previous := user.WalletAddress
+	user.SetPayoutWallet(wallet)
+	if err := u.repository.UpdatePayoutWallet(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+
+	event := PayoutWalletChangedEvent{
+		UserID:         user.ID.String(),
+		PreviousWallet: previous,
+		WalletAddress:  user.WalletAddress,
+		ChangedAt:      user.PayoutChangedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if err := u.eventPublisher.Publish(ctx, EventTypePayoutWalletChanged, event); err != nil {
+		u.log.ErrorContext(ctx, "failed to publish payout wallet change", "user_id", user.ID, "error", err)
+	}
+
+	return user, nil
+}
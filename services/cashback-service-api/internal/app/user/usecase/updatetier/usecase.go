@@ -0,0 +1,132 @@
+package updatetier
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	EventTypeTierChanged = "user.tier.changed"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		UpdateTier(ctx context.Context, user domain.User) error
+	}
+
+	// PurchaseRepository interface for the user's rolling purchase volume
+	PurchaseRepository interface {
+		SumByUserIDSince(ctx context.Context, userID uuid.UUID, since time.Time) (float64, error)
+	}
+
+	// EventPublisher publishes events to the outbox
+	EventPublisher interface {
+		Publish(ctx context.Context, eventType string, payload any) error
+	}
+
+	// Transactor runs the tier update and its event in one transaction
+	Transactor interface {
+		InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	// Policy configures how loyalty tiers are computed.
+	Policy struct {
+		Thresholds domain.TierThresholds
+		// Window is how far back purchases count towards the rolling volume.
+		Window time.Duration
+	}
+
+	UseCase struct {
+		repository         Repository
+		purchaseRepository PurchaseRepository
+		eventPublisher     EventPublisher
+		transactor         Transactor
+		policy             Policy
+		log                *slog.Logger
+	}
+
+	// TierChangedEvent represents the event published when a user changes tier
+	TierChangedEvent struct {
+		UserID        string  `json:"user_id"`
+		PreviousTier  string  `json:"previous_tier"`
+		Tier          string  `json:"tier"`
+		RollingVolume float64 `json:"rolling_volume"`
+		WindowDays    int     `json:"window_days"`
+		ChangedAt     string  `json:"changed_at"`
+	}
+)
+
+func New(
+	repository Repository,
+	purchaseRepository PurchaseRepository,
+	eventPublisher EventPublisher,
+	transactor Transactor,
+	policy Policy,
+	log *slog.Logger,
+) UseCase {
+	return UseCase{
+		repository:         repository,
+		purchaseRepository: purchaseRepository,
+		eventPublisher:     eventPublisher,
+		transactor:         transactor,
+		policy:             policy,
+		log:                log,
+	}
+}
+
+// Execute recomputes the user's rolling purchase volume and loyalty tier.
+// It is called whenever one of the user's purchases is created or refunded.
+// The volume is summed again over the whole window rather than adjusted by the
+// purchase, since purchases also leave the window with nothing to report it;
+// the sum is a single query on the user's purchases. When the tier moves up or
+// down, user.tier.changed is written to the outbox in the same transaction as
+// the tier, so one is never stored without the other.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	since := time.Now().UTC().Add(-u.policy.Window)
+	volume, err := u.purchaseRepository.SumByUserIDSince(ctx, userID, since)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	previousTier := user.Tier
+	if !user.ChangeTier(u.policy.Thresholds.TierFor(volume), volume) {
+		return user, u.repository.UpdateTier(ctx, user)
+	}
+
+	event := TierChangedEvent{
+		UserID:        user.ID.String(),
+		PreviousTier:  previousTier,
+		Tier:          user.Tier,
+		RollingVolume: user.RollingVolume,
+		WindowDays:    int(u.policy.Window.Hours() / 24),
+		ChangedAt:     user.TierUpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	err = u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repository.UpdateTier(ctx, user); err != nil {
+			return err
+		}
+		return u.eventPublisher.Publish(ctx, EventTypeTierChanged, event)
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	u.log.InfoContext(ctx, "user tier changed",
+		"user_id", user.ID,
+		"from", previousTier,
+		"to", user.Tier,
+		"rolling_volume", user.RollingVolume,
+	)
+
+	return user, nil
+}
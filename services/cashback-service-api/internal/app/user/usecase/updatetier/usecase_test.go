@@ -0,0 +1,163 @@
+package updatetier_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	purchasedomain "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	purchaserepository "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updatetier"
+	"github.com/google/uuid"
+)
+
+const window = 90 * 24 * time.Hour
+
+var thresholds = domain.TierThresholds{Silver: 1000, Gold: 5000}
+
+type fixture struct {
+	users     *repository.Memory
+	purchases *purchaserepository.Memory
+	outbox    *outbox
+	usecase   updatetier.UseCase
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		users:     repository.NewMemory(),
+		purchases: purchaserepository.NewMemory(),
+		outbox:    &outbox{},
+	}
+	f.usecase = updatetier.New(f.users, f.purchases, f.outbox, transactor{},
+		updatetier.Policy{Thresholds: thresholds, Window: window},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+func (f *fixture) user(t *testing.T, tier string) domain.User {
+	t.Helper()
+	id := uuid.NewString()
+	user, err := f.users.Create(context.Background(), domain.User{
+		ExternalID:   "ext-" + id,
+		Email:        id + "@example.com",
+		ReferralCode: id[:8],
+		Tier:         tier,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func (f *fixture) purchase(t *testing.T, userID uuid.UUID, amount float64, age time.Duration, status string) {
+	t.Helper()
+	purchase := purchasedomain.NewPurchase(userID, amount, uuid.New())
+	purchase.CreatedAt = time.Now().UTC().Add(-age)
+	purchase.Status = status
+	if _, err := f.purchases.Create(context.Background(), purchase); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTierThresholdsAreInclusive(t *testing.T) {
+	for _, tc := range []struct {
+		volume float64
+		want   string
+	}{
+		{0, domain.TierBronze},
+		{999.99, domain.TierBronze},
+		{1000, domain.TierSilver},
+		{4999.99, domain.TierSilver},
+		{5000, domain.TierGold},
+	} {
+		if got := thresholds.TierFor(tc.volume); got != tc.want {
+			t.Errorf("TierFor(%v) = %q, want %q", tc.volume, got, tc.want)
+		}
+	}
+}
+
+func TestRollingVolumeCountsPurchasesInsideTheWindow(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		age    time.Duration
+		status string
+		want   string
+	}{
+		{"just inside the window", window - time.Minute, purchasedomain.StatusPending, domain.TierSilver},
+		{"just outside the window", window + time.Minute, purchasedomain.StatusPending, domain.TierBronze},
+		{"refunded", time.Hour, purchasedomain.StatusRefunded, domain.TierBronze},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture()
+			user := f.user(t, domain.TierBronze)
+			f.purchase(t, user.ID, 1000, tc.age, tc.status)
+
+			updated, err := f.usecase.Execute(context.Background(), user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.Tier != tc.want {
+				t.Fatalf("tier = %q, want %q", updated.Tier, tc.want)
+			}
+		})
+	}
+}
+
+func TestTierChangesArePublished(t *testing.T) {
+	f := newFixture()
+	user := f.user(t, domain.TierGold)
+	f.purchase(t, user.ID, 1200, time.Hour, purchasedomain.StatusPending)
+	f.purchase(t, user.ID, 4000, window+time.Hour, purchasedomain.StatusPending)
+
+	for range 2 {
+		if _, err := f.usecase.Execute(context.Background(), user.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(f.outbox.events) != 1 {
+		t.Fatalf("published %d events, want 1", len(f.outbox.events))
+	}
+	if f.outbox.outsideTransaction != 0 {
+		t.Fatalf("%d events published outside the tier's transaction", f.outbox.outsideTransaction)
+	}
+	event := f.outbox.events[0].(updatetier.TierChangedEvent)
+	if event.PreviousTier != domain.TierGold || event.Tier != domain.TierSilver || event.RollingVolume != 1200 || event.WindowDays != 90 {
+		t.Fatalf("event %+v", event)
+	}
+
+	stored, err := f.users.FindByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Tier != domain.TierSilver || stored.TierUpdatedAt == nil {
+		t.Fatalf("stored tier %q, updated at %v", stored.Tier, stored.TierUpdatedAt)
+	}
+}
+
+type inTransaction struct{}
+
+// transactor marks the context fn runs with, for the fakes to tell whether
+// they were called inside the transaction.
+type transactor struct{}
+
+func (transactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, inTransaction{}, true))
+}
+
+type outbox struct {
+	events             []any
+	outsideTransaction int
+}
+
+func (o *outbox) Publish(ctx context.Context, _ string, payload any) error {
+	if ctx.Value(inTransaction{}) == nil {
+		o.outsideTransaction++
+	}
+	o.events = append(o.events, payload)
+	return nil
+}
@@ -0,0 +1,7 @@
+package updateuser
+
+import "errors"
+
+var (
+	ErrUserAlreadyExists = errors.New("user already exists")
+)
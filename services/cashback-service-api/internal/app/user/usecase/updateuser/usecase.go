@@ -0,0 +1,79 @@
+package updateuser
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		FindByEmail(ctx context.Context, email string) (domain.User, error)
+		FindByExternalID(ctx context.Context, externalID string) (domain.User, error)
+		UpdateProfile(ctx context.Context, user domain.User) error
+	}
+
+	// Changes lists the profile fields to update. Nil fields are left unchanged.
+	Changes struct {
+		Email      *string
+		ExternalID *string
+	}
+
+	UseCase struct {
+		repository Repository
+	}
+)
+
+func New(repository Repository) UseCase {
+	return UseCase{
+		repository: repository,
+	}
+}
+
+// Execute updates the user's email and/or external ID. Both must stay unique
+// across users. Erased users cannot be updated.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, changes Changes) (domain.User, error) {
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.IsErased() {
+		return domain.User{}, domain.ErrUserErased
+	}
+
+	if changes.Email != nil && *changes.Email != user.Email {
+		if err := u.ensureUnused(u.repository.FindByEmail(ctx, *changes.Email)); err != nil {
+			return domain.User{}, err
+		}
+		user.Email = *changes.Email
+	}
+
+	if changes.ExternalID != nil && *changes.ExternalID != user.ExternalID {
+		if err := u.ensureUnused(u.repository.FindByExternalID(ctx, *changes.ExternalID)); err != nil {
+			return domain.User{}, err
+		}
+		user.ExternalID = *changes.ExternalID
+	}
+
+	user.UpdatedAt = time.Now().UTC()
+	if err := u.repository.UpdateProfile(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// ensureUnused checks the result of a lookup for another user holding the value.
+func (UseCase) ensureUnused(existing domain.User, err error) error {
+	if err == nil && existing.ID != uuid.Nil {
+		return ErrUserAlreadyExists
+	}
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return err
+	}
+	return nil
+}
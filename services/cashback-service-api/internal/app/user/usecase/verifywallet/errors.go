@@ -0,0 +1,13 @@
+package verifywallet
+
+import "errors"
+
+var (
+	ErrInvalidMessage    = errors.New("invalid sign-in message")
+	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrChallengeNotFound = errors.New("wallet challenge not found")
+	ErrChallengeExpired  = errors.New("wallet challenge expired")
+	ErrChallengeUsed     = errors.New("wallet challenge already used")
+	ErrMessageMismatch   = errors.New("signed message does not match the issued challenge")
+	ErrSignerMismatch    = errors.New("signature was not produced by the challenged wallet")
+)
@@ -0,0 +1,131 @@
+package verifywallet
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/siwe"
+	"github.com/google/uuid"
+)
+
+type (
+	Repository interface {
+		FindByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+		FindChallengeByNonce(ctx context.Context, nonce string) (domain.WalletChallenge, error)
+		ConsumeChallenge(ctx context.Context, id uuid.UUID) (bool, error)
+		AddWallet(ctx context.Context, wallet domain.Wallet) (domain.Wallet, error)
+		UpdatePayoutWallet(ctx context.Context, user domain.User) error
+	}
+
+	// CashbackReleaser approves the cashback held while the user had no verified wallet
+	CashbackReleaser interface {
+		Execute(ctx context.Context, userID uuid.UUID, walletAddress string) (int, error)
+	}
+
+	UseCase struct {
+		repository       Repository
+		cashbackReleaser CashbackReleaser
+		log              *slog.Logger
+	}
+)
+
+func New(repository Repository, cashbackReleaser CashbackReleaser, log *slog.Logger) UseCase {
+	return UseCase{
+		repository:       repository,
+		cashbackReleaser: cashbackReleaser,
+		log:              log,
+	}
+}
+
+// Execute verifies a signed EIP-4361 challenge and adds the proven wallet to
+// the user's verified wallets. If the user had no verified payout wallet yet,
+// the new wallet becomes the payout wallet and cashback held back until then
+// is released for minting; otherwise the payout wallet is left unchanged.
+// Custodial users switch to it like anyone else, once it is old enough.
+func (u UseCase) Execute(ctx context.Context, userID uuid.UUID, message, signature string) (domain.User, error) {
+	parsed, err := siwe.Parse(message)
+	if err != nil {
+		return domain.User{}, ErrInvalidMessage
+	}
+
+	challenge, err := u.findChallenge(ctx, userID, parsed.Nonce)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	// The challenge text is issued by the server, so it must come back verbatim
+	if message != challenge.Message {
+		return domain.User{}, ErrMessageMismatch
+	}
+
+	signer, err := ethereum.RecoverPersonalSigner(message, signature)
+	if err != nil {
+		return domain.User{}, ErrInvalidSignature
+	}
+	if signer != challenge.Address {
+		return domain.User{}, ErrSignerMismatch
+	}
+
+	consumed, err := u.repository.ConsumeChallenge(ctx, challenge.ID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !consumed {
+		return domain.User{}, ErrChallengeUsed
+	}
+
+	user, err := u.repository.FindByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	wallet, err := u.repository.AddWallet(ctx, domain.NewWallet(user.ID, challenge.Address))
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if user.HasVerifiedWallet() {
+		return user, nil
+	}
+
+	user.SetPayoutWallet(wallet)
+	if err := u.repository.UpdatePayoutWallet(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+
+	released, err := u.cashbackReleaser.Execute(ctx, user.ID, user.WalletAddress)
+	if err != nil {
+		u.log.ErrorContext(ctx, "failed to release held cashback", "user_id", user.ID, "error", err)
+	} else if released > 0 {
+		u.log.InfoContext(ctx, "released held cashback", "user_id", user.ID, "count", released)
+	}
+
+	return user, nil
+}
+
+func (u UseCase) findChallenge(ctx context.Context, userID uuid.UUID, nonce string) (domain.WalletChallenge, error) {
+	challenge, err := u.repository.FindChallengeByNonce(ctx, nonce)
+	if err != nil {
+		if errors.Is(err, domain.ErrChallengeNotFound) {
+			return domain.WalletChallenge{}, ErrChallengeNotFound
+		}
+		return domain.WalletChallenge{}, err
+	}
+
+	// Never reveal challenges issued to other users
+	if challenge.UserID != userID {
+		return domain.WalletChallenge{}, ErrChallengeNotFound
+	}
+	if challenge.IsUsed() {
+		return domain.WalletChallenge{}, ErrChallengeUsed
+	}
+	if challenge.IsExpired(time.Now().UTC()) {
+		return domain.WalletChallenge{}, ErrChallengeExpired
+	}
+
+	return challenge, nil
+}
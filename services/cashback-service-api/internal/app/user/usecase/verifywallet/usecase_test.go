@@ -0,0 +1,220 @@
+package verifywallet_test
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/requestwalletchallenge"
+	"github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/verifywallet"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/google/uuid"
+)
+
+// The addresses of private keys 1 and 2.
+const (
+	wallet      = "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"
+	otherWallet = "0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF"
+)
+
+type fixture struct {
+	users      *users
+	released   []string
+	challenges requestwalletchallenge.UseCase
+	usecase    verifywallet.UseCase
+	user       domain.User
+}
+
+func newFixture(ttl time.Duration) *fixture {
+	user := domain.User{ID: uuid.New()}
+	f := &fixture{
+		users: &users{user: user, challenges: map[string]domain.WalletChallenge{}},
+		user:  user,
+	}
+	f.challenges = requestwalletchallenge.New(f.users, requestwalletchallenge.Policy{
+		Domain:    "cashback.example.com",
+		URI:       "https://cashback.example.com",
+		Statement: "Link this wallet to your cashback account.",
+		ChainID:   1,
+		TTL:       ttl,
+	})
+	f.usecase = verifywallet.New(f.users, releaser{f}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+// challenge requests a challenge for address and returns its message.
+func (f *fixture) challenge(t *testing.T, userID uuid.UUID, address string) string {
+	t.Helper()
+	challenge, err := f.challenges.Execute(context.Background(), userID, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return challenge.Message
+}
+
+// sign signs message with private key n as personal_sign does.
+func sign(message string, n byte) string {
+	var scalar [32]byte
+	scalar[31] = n
+	compact := ecdsa.SignCompact(secp256k1.PrivKeyFromBytes(scalar[:]), ethereum.PersonalMessageHash(message), false)
+	return "0x" + hex.EncodeToString(append(compact[1:], compact[0]))
+}
+
+func TestVerifySetsTheFirstWalletAsPayoutWallet(t *testing.T) {
+	f := newFixture(time.Minute)
+	message := f.challenge(t, f.user.ID, strings.ToLower(wallet))
+
+	user, err := f.usecase.Execute(context.Background(), f.user.ID, message, sign(message, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.WalletAddress != wallet || !user.HasVerifiedWallet() {
+		t.Fatalf("payout wallet %q, verified at %v", user.WalletAddress, user.WalletVerifiedAt)
+	}
+	if len(f.users.wallets) != 1 || f.users.wallets[0].Address != wallet {
+		t.Fatalf("wallets %+v", f.users.wallets)
+	}
+	if len(f.released) != 1 || f.released[0] != wallet {
+		t.Fatalf("released held cashback to %v", f.released)
+	}
+}
+
+func TestVerifyKeepsAnExistingPayoutWallet(t *testing.T) {
+	f := newFixture(time.Minute)
+	first := f.challenge(t, f.user.ID, wallet)
+	if _, err := f.usecase.Execute(context.Background(), f.user.ID, first, sign(first, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	second := f.challenge(t, f.user.ID, otherWallet)
+	user, err := f.usecase.Execute(context.Background(), f.user.ID, second, sign(second, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.WalletAddress != wallet || len(f.users.wallets) != 2 || len(f.released) != 1 {
+		t.Fatalf("payout wallet %q, %d wallets, %d releases", user.WalletAddress, len(f.users.wallets), len(f.released))
+	}
+}
+
+func TestVerifyRejections(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// verify returns the message and signature sent for a challenge
+		// issued for wallet.
+		verify func(t *testing.T, f *fixture, message string) (string, string)
+		want   error
+	}{
+		{"not a sign-in message", func(t *testing.T, f *fixture, message string) (string, string) {
+			return "hello", sign("hello", 1)
+		}, verifywallet.ErrInvalidMessage},
+		{"unknown nonce", func(t *testing.T, f *fixture, message string) (string, string) {
+			m := strings.Replace(message, "Nonce: ", "Nonce: 0", 1)
+			return m, sign(m, 1)
+		}, verifywallet.ErrChallengeNotFound},
+		{"another user's challenge", func(t *testing.T, f *fixture, message string) (string, string) {
+			m := f.challenge(t, uuid.New(), wallet)
+			return m, sign(m, 1)
+		}, verifywallet.ErrChallengeNotFound},
+		{"altered message", func(t *testing.T, f *fixture, message string) (string, string) {
+			m := strings.Replace(message, "Chain ID: 1", "Chain ID: 5", 1)
+			return m, sign(m, 1)
+		}, verifywallet.ErrMessageMismatch},
+		{"malformed signature", func(t *testing.T, f *fixture, message string) (string, string) {
+			return message, "0x1234"
+		}, verifywallet.ErrInvalidSignature},
+		{"signed by another wallet", func(t *testing.T, f *fixture, message string) (string, string) {
+			return message, sign(message, 2)
+		}, verifywallet.ErrSignerMismatch},
+		{"replayed", func(t *testing.T, f *fixture, message string) (string, string) {
+			if _, err := f.usecase.Execute(context.Background(), f.user.ID, message, sign(message, 1)); err != nil {
+				t.Fatal(err)
+			}
+			return message, sign(message, 1)
+		}, verifywallet.ErrChallengeUsed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(time.Minute)
+			message, signature := tc.verify(t, f, f.challenge(t, f.user.ID, wallet))
+
+			if _, err := f.usecase.Execute(context.Background(), f.user.ID, message, signature); !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsExpiredChallenges(t *testing.T) {
+	f := newFixture(-time.Second)
+	message := f.challenge(t, f.user.ID, wallet)
+
+	if _, err := f.usecase.Execute(context.Background(), f.user.ID, message, sign(message, 1)); !errors.Is(err, verifywallet.ErrChallengeExpired) {
+		t.Fatalf("error = %v, want %v", err, verifywallet.ErrChallengeExpired)
+	}
+}
+
+// users holds one user, the challenges issued and the wallets verified.
+type users struct {
+	user       domain.User
+	challenges map[string]domain.WalletChallenge
+	wallets    []domain.Wallet
+}
+
+func (u *users) FindByID(_ context.Context, id uuid.UUID) (domain.User, error) {
+	if id != u.user.ID {
+		// Challenges for other users are issued as if they existed.
+		return domain.User{ID: id}, nil
+	}
+	return u.user, nil
+}
+
+func (u *users) CreateChallenge(_ context.Context, challenge domain.WalletChallenge) (domain.WalletChallenge, error) {
+	u.challenges[challenge.Nonce] = challenge
+	return challenge, nil
+}
+
+func (u *users) FindChallengeByNonce(_ context.Context, nonce string) (domain.WalletChallenge, error) {
+	challenge, ok := u.challenges[nonce]
+	if !ok {
+		return domain.WalletChallenge{}, domain.ErrChallengeNotFound
+	}
+	return challenge, nil
+}
+
+func (u *users) ConsumeChallenge(_ context.Context, id uuid.UUID) (bool, error) {
+	for nonce, challenge := range u.challenges {
+		if challenge.ID == id && challenge.UsedAt == nil {
+			now := time.Now().UTC()
+			challenge.UsedAt = &now
+			u.challenges[nonce] = challenge
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (u *users) AddWallet(_ context.Context, wallet domain.Wallet) (domain.Wallet, error) {
+	u.wallets = append(u.wallets, wallet)
+	return wallet, nil
+}
+
+func (u *users) UpdatePayoutWallet(_ context.Context, user domain.User) error {
+	u.user = user
+	return nil
+}
+
+type releaser struct {
+	f *fixture
+}
+
+func (r releaser) Execute(_ context.Context, _ uuid.UUID, walletAddress string) (int, error) {
+	r.f.released = append(r.f.released, walletAddress)
+	return 1, nil
+}
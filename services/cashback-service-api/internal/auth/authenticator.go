@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader carries partner API keys.
+const APIKeyHeader = "X-API-Key"
+
+var (
+	ErrInvalidAPIKey    = errors.New("invalid API key")
+	ErrInvalidToken     = errors.New("invalid bearer token")
+	ErrInvalidAPIKeyDef = errors.New("API keys must be configured as subject:role:sha256hex, with :merchant_id appended for partners")
+)
+
+type (
+	// Authenticator resolves the caller of a request from its API key or bearer token.
+	Authenticator struct {
+		enabled bool
+		apiKeys map[string]Principal
+		secret  []byte
+		jwks    map[string]crypto.PublicKey
+		parser  *jwt.Parser
+	}
+
+	// claims are the JWT claims the API relies on. The subject of a user
+	// token is the user ID; partner tokens name their merchant.
+	claims struct {
+		Role       Role   `json:"role"`
+		MerchantID string `json:"merchant_id"`
+		jwt.RegisteredClaims
+	}
+)
+
+// NewAuthenticator builds the authenticator from config. API keys are stored
+// as SHA-256 digests so plaintext keys never live in configuration.
+func NewAuthenticator(cfg config.Auth) (*Authenticator, error) {
+	a := &Authenticator{
+		enabled: cfg.Enabled,
+		apiKeys: make(map[string]Principal),
+		secret:  []byte(cfg.JWTSecret),
+	}
+
+	for _, def := range strings.Split(cfg.APIKeys, ",") {
+		if def = strings.TrimSpace(def); def == "" {
+			continue
+		}
+		principal, digest, err := parseAPIKeyDef(def)
+		if err != nil {
+			return nil, err
+		}
+		a.apiKeys[digest] = principal
+	}
+
+	var methods []string
+	if cfg.JWTSecret != "" {
+		methods = append(methods, "HS256")
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = keys
+		methods = append(methods, "RS256", "RS384", "RS512", "ES256", "ES384", "ES512")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.JWTIssuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		options = append(options, jwt.WithAudience(cfg.JWTAudience))
+	}
+	a.parser = jwt.NewParser(options...)
+
+	return a, nil
+}
+
+// Authenticate returns the caller of r. It returns false without an error
+// when the request carries no credentials, and an error when the credentials
+// it carries are invalid. With auth disabled every request acts as admin.
+func (a *Authenticator) Authenticate(r *http.Request) (Principal, bool, error) {
+	if !a.enabled {
+		return Principal{Subject: "anonymous", Role: RoleAdmin}, true, nil
+	}
+
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		principal, ok := a.apiKeys[digest(key)]
+		if !ok {
+			return Principal{}, false, ErrInvalidAPIKey
+		}
+		return principal, true, nil
+	}
+
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return Principal{}, false, nil
+	}
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found {
+		return Principal{}, false, ErrInvalidToken
+	}
+
+	principal, err := a.verifyToken(token)
+	if err != nil {
+		return Principal{}, false, err
+	}
+	return principal, true, nil
+}
+
+func (a *Authenticator) verifyToken(token string) (Principal, error) {
+	var c claims
+	if _, err := a.parser.ParseWithClaims(token, &c, a.keyFor); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if c.Subject == "" || !IsValidRole(c.Role) {
+		return Principal{}, fmt.Errorf("%w: missing subject or role", ErrInvalidToken)
+	}
+	if !validMerchant(c.Role, c.MerchantID) {
+		return Principal{}, fmt.Errorf("%w: partners need a merchant_id, other roles none", ErrInvalidToken)
+	}
+	return Principal{Subject: c.Subject, Role: c.Role, MerchantID: c.MerchantID, Method: MethodJWT}, nil
+}
+
+// keyFor picks the verification key for a token. HMAC tokens are only checked
+// against the static secret and asymmetric tokens only against the JWKS, so a
+// public key can never be used as an HMAC secret.
+func (a *Authenticator) keyFor(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if len(a.secret) == 0 {
+			return nil, errors.New("HMAC tokens are not accepted")
+		}
+		return a.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := a.jwks[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// parseAPIKeyDef parses subject:role:sha256hex, followed by :merchant_id for
+// partners, into the key's principal and digest.
+func parseAPIKeyDef(def string) (Principal, string, error) {
+	parts := strings.Split(def, ":")
+	if len(parts) != 3 && len(parts) != 4 {
+		return Principal{}, "", ErrInvalidAPIKeyDef
+	}
+
+	principal := Principal{Subject: parts[0], Role: Role(parts[1]), Method: MethodAPIKey}
+	if principal.Subject == "" || !IsValidRole(principal.Role) {
+		return Principal{}, "", ErrInvalidAPIKeyDef
+	}
+	if len(parts) == 4 {
+		principal.MerchantID = parts[3]
+	}
+	if !validMerchant(principal.Role, principal.MerchantID) {
+		return Principal{}, "", ErrInvalidAPIKeyDef
+	}
+
+	hash := strings.ToLower(parts[2])
+	if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+		return Principal{}, "", ErrInvalidAPIKeyDef
+	}
+	return principal, hash, nil
+}
+
+// validMerchant reports whether merchantID suits role: partners act for the
+// merchant with that ID, other roles for none.
+func validMerchant(role Role, merchantID string) bool {
+	if role != RolePartner {
+		return merchantID == ""
+	}
+	_, err := uuid.Parse(merchantID)
+	return err == nil
+}
+
+func digest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,321 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	secret   = "test-secret-with-enough-entropy"
+	issuer   = "https://auth.example.com"
+	audience = "cashback-api"
+	apiKey   = "pk_live_acme"
+	merchant = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+)
+
+type keys struct {
+	rsa   *rsa.PrivateKey
+	ec    *ecdsa.PrivateKey
+	other *rsa.PrivateKey
+}
+
+func newKeys(t *testing.T) keys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys{rsa: rsaKey, ec: ecKey, other: other}
+}
+
+// writeJWKS writes a JWKS file with the RSA key as "rsa-1" and the EC key as
+// "ec-1", plus an encryption key that must be skipped.
+func writeJWKS(t *testing.T, k keys) string {
+	t.Helper()
+	b64 := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(k.rsa.N), "e": b64(big.NewInt(int64(k.rsa.E)))},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(k.ec.X), "y": b64(k.ec.Y)},
+		{"kty": "oct", "kid": "enc-1", "use": "enc"},
+	}}
+	return writeFile(t, set)
+}
+
+func writeFile(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func digest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func newAuthenticator(t *testing.T, k keys) *auth.Authenticator {
+	t.Helper()
+	a, err := auth.NewAuthenticator(config.Auth{
+		Enabled:     true,
+		APIKeys:     "acme:partner:" + digest(apiKey) + ":" + merchant + ", ops:admin:" + digest("ops-key"),
+		JWTSecret:   secret,
+		JWKSFile:    writeJWKS(t, k),
+		JWTIssuer:   issuer,
+		JWTAudience: audience,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":  "2f1c6b8e-1d7a-4a57-9c1e-3b2f5a6d7e8f",
+		"role": "user",
+		"iss":  issuer,
+		"aud":  audience,
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func with(claims jwt.MapClaims, key string, value any) jwt.MapClaims {
+	changed := jwt.MapClaims{}
+	for k, v := range claims {
+		changed[k] = v
+	}
+	if value == nil {
+		delete(changed, key)
+	} else {
+		changed[key] = value
+	}
+	return changed
+}
+
+func TestAuthenticateAcceptsValidCredentials(t *testing.T) {
+	k := newKeys(t)
+	a := newAuthenticator(t, k)
+	claims := validClaims()
+	user := auth.Principal{Subject: claims["sub"].(string), Role: auth.RoleUser, Method: auth.MethodJWT}
+
+	for _, tc := range []struct {
+		name   string
+		header http.Header
+		want   auth.Principal
+	}{
+		{"partner API key", http.Header{auth.APIKeyHeader: {apiKey}},
+			auth.Principal{Subject: "acme", Role: auth.RolePartner, MerchantID: merchant, Method: auth.MethodAPIKey}},
+		{"admin API key", http.Header{auth.APIKeyHeader: {"ops-key"}},
+			auth.Principal{Subject: "ops", Role: auth.RoleAdmin, Method: auth.MethodAPIKey}},
+		{"HS256 token", bearer(sign(t, jwt.SigningMethodHS256, "", []byte(secret), claims)), user},
+		{"RS256 token from the JWKS", bearer(sign(t, jwt.SigningMethodRS256, "rsa-1", k.rsa, claims)), user},
+		{"ES256 token from the JWKS", bearer(sign(t, jwt.SigningMethodES256, "ec-1", k.ec, claims)), user},
+		{"admin token", bearer(sign(t, jwt.SigningMethodHS256, "", []byte(secret), with(claims, "role", "admin"))),
+			auth.Principal{Subject: user.Subject, Role: auth.RoleAdmin, Method: auth.MethodJWT}},
+		{"partner token", bearer(sign(t, jwt.SigningMethodHS256, "", []byte(secret),
+			with(with(claims, "role", "partner"), "merchant_id", merchant))),
+			auth.Principal{Subject: user.Subject, Role: auth.RolePartner, MerchantID: merchant, Method: auth.MethodJWT}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, ok, err := a.Authenticate(request(tc.header))
+			if err != nil || !ok {
+				t.Fatalf("authenticated = %t, error = %v", ok, err)
+			}
+			if principal != tc.want {
+				t.Fatalf("principal %+v, want %+v", principal, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsInvalidCredentials(t *testing.T) {
+	k := newKeys(t)
+	a := newAuthenticator(t, k)
+	claims := validClaims()
+	hs := func(c jwt.MapClaims) http.Header {
+		return bearer(sign(t, jwt.SigningMethodHS256, "", []byte(secret), c))
+	}
+	publicKey, err := json.Marshal(k.rsa.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		header http.Header
+		want   error
+	}{
+		{"unknown API key", http.Header{auth.APIKeyHeader: {"pk_live_guess"}}, auth.ErrInvalidAPIKey},
+		{"not a bearer token", http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}}, auth.ErrInvalidToken},
+		{"malformed token", bearer("not.a.token"), auth.ErrInvalidToken},
+		{"expired", hs(with(claims, "exp", time.Now().Add(-time.Minute).Unix())), auth.ErrInvalidToken},
+		{"no expiry", hs(with(claims, "exp", nil)), auth.ErrInvalidToken},
+		{"not yet valid", hs(with(claims, "nbf", time.Now().Add(time.Hour).Unix())), auth.ErrInvalidToken},
+		{"other issuer", hs(with(claims, "iss", "https://evil.example.com")), auth.ErrInvalidToken},
+		{"other audience", hs(with(claims, "aud", "another-api")), auth.ErrInvalidToken},
+		{"no subject", hs(with(claims, "sub", nil)), auth.ErrInvalidToken},
+		{"no role", hs(with(claims, "role", nil)), auth.ErrInvalidToken},
+		{"unknown role", hs(with(claims, "role", "root")), auth.ErrInvalidToken},
+		{"partner without a merchant", hs(with(claims, "role", "partner")), auth.ErrInvalidToken},
+		{"user with a merchant", hs(with(claims, "merchant_id", merchant)), auth.ErrInvalidToken},
+		{"wrong secret", bearer(sign(t, jwt.SigningMethodHS256, "", []byte("guessed"), claims)), auth.ErrInvalidToken},
+		{"unknown key id", bearer(sign(t, jwt.SigningMethodRS256, "rsa-2", k.rsa, claims)), auth.ErrInvalidToken},
+		{"no key id", bearer(sign(t, jwt.SigningMethodRS256, "", k.rsa, claims)), auth.ErrInvalidToken},
+		{"signed by another key", bearer(sign(t, jwt.SigningMethodRS256, "rsa-1", k.other, claims)), auth.ErrInvalidToken},
+		{"public key as HMAC secret", bearer(sign(t, jwt.SigningMethodHS256, "rsa-1", publicKey, claims)), auth.ErrInvalidToken},
+		{"unsigned", bearer(sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, claims)), auth.ErrInvalidToken},
+		{"disallowed algorithm", bearer(sign(t, jwt.SigningMethodPS256, "rsa-1", k.rsa, claims)), auth.ErrInvalidToken},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, ok, err := a.Authenticate(request(tc.header))
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want %v", err, tc.want)
+			}
+			if ok || principal != (auth.Principal{}) {
+				t.Fatalf("authenticated as %+v", principal)
+			}
+		})
+	}
+}
+
+func TestAuthenticateWithoutCredentials(t *testing.T) {
+	a := newAuthenticator(t, newKeys(t))
+
+	principal, ok, err := a.Authenticate(request(nil))
+	if err != nil || ok || principal != (auth.Principal{}) {
+		t.Fatalf("principal %+v, authenticated = %t, error = %v", principal, ok, err)
+	}
+}
+
+func TestHMACTokensNeedASecret(t *testing.T) {
+	k := newKeys(t)
+	a, err := auth.NewAuthenticator(config.Auth{Enabled: true, JWKSFile: writeJWKS(t, k)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := sign(t, jwt.SigningMethodHS256, "", []byte(""), validClaims())
+	if _, _, err := a.Authenticate(request(bearer(token))); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("error = %v, want %v", err, auth.ErrInvalidToken)
+	}
+}
+
+func TestDisabledAuthActsAsAdmin(t *testing.T) {
+	a, err := auth.NewAuthenticator(config.Auth{Enabled: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, ok, err := a.Authenticate(request(http.Header{auth.APIKeyHeader: {"anything"}}))
+	if err != nil || !ok || principal.Role != auth.RoleAdmin {
+		t.Fatalf("principal %+v, authenticated = %t, error = %v", principal, ok, err)
+	}
+}
+
+func TestNewAuthenticatorRejectsInvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  func(t *testing.T) config.Auth
+		want error
+	}{
+		{"API key without a role", func(*testing.T) config.Auth {
+			return config.Auth{APIKeys: "acme:" + digest(apiKey)}
+		}, auth.ErrInvalidAPIKeyDef},
+		{"API key with an unknown role", func(*testing.T) config.Auth {
+			return config.Auth{APIKeys: "acme:root:" + digest(apiKey)}
+		}, auth.ErrInvalidAPIKeyDef},
+		{"API key without a subject", func(*testing.T) config.Auth {
+			return config.Auth{APIKeys: ":partner:" + digest(apiKey) + ":" + merchant}
+		}, auth.ErrInvalidAPIKeyDef},
+		{"partner API key without a merchant", func(*testing.T) config.Auth {
+			return config.Auth{APIKeys: "acme:partner:" + digest(apiKey)}
+		}, auth.ErrInvalidAPIKeyDef},
+		{"partner API key with an invalid merchant", func(*testing.T) config.Auth {
+			return config.Auth{APIKeys: "acme:partner:" + digest(apiKey) + ":acme"}
+		}, auth.ErrInvalidAPIKeyDef},
+		{"admin API key with a merchant", func(*testing.T) config.Auth {
+			return config.Auth{APIKeys: "ops:admin:" + digest(apiKey) + ":" + merchant}
+		}, auth.ErrInvalidAPIKeyDef},
+		{"plaintext API key", func(*testing.T) config.Auth {
+			return config.Auth{APIKeys: "acme:partner:" + apiKey + ":" + merchant}
+		}, auth.ErrInvalidAPIKeyDef},
+		{"unsupported key type", func(t *testing.T) config.Auth {
+			return config.Auth{JWKSFile: writeFile(t, map[string]any{"keys": []map[string]string{{"kty": "OKP", "kid": "ed-1"}}})}
+		}, auth.ErrUnsupportedKey},
+		{"unsupported curve", func(t *testing.T) config.Auth {
+			return config.Auth{JWKSFile: writeFile(t, map[string]any{"keys": []map[string]string{{"kty": "EC", "kid": "ec-1", "crv": "secp256k1"}}})}
+		}, auth.ErrUnsupportedKey},
+		{"invalid key encoding", func(t *testing.T) config.Auth {
+			return config.Auth{JWKSFile: writeFile(t, map[string]any{"keys": []map[string]string{{"kty": "RSA", "kid": "rsa-1", "n": "!!", "e": "AQAB"}}})}
+		}, auth.ErrUnsupportedKey},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := auth.NewAuthenticator(tc.cfg(t)); !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	if _, err := auth.NewAuthenticator(config.Auth{JWKSFile: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Fatal("a missing JWKS file was accepted")
+	}
+}
+
+func request(header http.Header) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	return req
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Authenticate resolves the caller of each request and stores it in the
+// request context. Requests without credentials pass through anonymously and
+// are rejected by Require on protected routes; invalid credentials are
+// rejected here.
+func Authenticate(a *Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok, err := a.Authenticate(r)
+			if err != nil {
+				unauthorized(w, r)
+				return
+			}
+			if ok {
+				r = r.WithContext(WithPrincipal(r.Context(), principal))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Require allows callers acting as one of roles.
+func Require(roles ...Role) func(http.Handler) http.Handler {
+	return authorize(func(principal Principal, _ *http.Request) bool {
+		return principal.HasRole(roles...)
+	})
+}
+
+// RequireOwner allows callers acting as one of roles, and users whose ID
+// matches the URL parameter param, i.e. users acting on their own resources.
+func RequireOwner(param string, roles ...Role) func(http.Handler) http.Handler {
+	return authorize(func(principal Principal, r *http.Request) bool {
+		if principal.HasRole(roles...) {
+			return true
+		}
+		return principal.Role == RoleUser && principal.Subject == chi.URLParam(r, param)
+	})
+}
+
+func authorize(allowed func(Principal, *http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := FromContext(r.Context())
+			if !ok {
+				unauthorized(w, r)
+				return
+			}
+			if !allowed(principal, r) {
+				errorhandler.Render(w, r, errorhandler.ErrForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", `Bearer, ApiKey header="`+APIKeyHeader+`"`)
+	errorhandler.Render(w, r, errorhandler.ErrUnauthorized)
+}
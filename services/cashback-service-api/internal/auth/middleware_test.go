@@ -0,0 +1,131 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const ownerID = "2f1c6b8e-1d7a-4a57-9c1e-3b2f5a6d7e8f"
+
+func TestRoleGuards(t *testing.T) {
+	partner := &auth.Principal{Subject: "acme", Role: auth.RolePartner}
+	admin := &auth.Principal{Subject: "ops", Role: auth.RoleAdmin}
+	owner := &auth.Principal{Subject: ownerID, Role: auth.RoleUser}
+	otherUser := &auth.Principal{Subject: "9d3e8b21-5c4a-4f6e-8a7b-1c2d3e4f5a6b", Role: auth.RoleUser}
+	// A partner named like the user must not pass as its owner.
+	namesake := &auth.Principal{Subject: ownerID, Role: auth.RolePartner}
+
+	router := chi.NewRouter()
+	router.With(auth.Require(auth.RoleAdmin)).Get("/admin", ok)
+	router.With(auth.Require(auth.RolePartner, auth.RoleAdmin)).Get("/purchases", ok)
+	router.With(auth.RequireOwner("id", auth.RoleAdmin)).Get("/users/{id}", ok)
+
+	for _, tc := range []struct {
+		name      string
+		path      string
+		principal *auth.Principal
+		want      int
+	}{
+		{"anonymous", "/admin", nil, http.StatusUnauthorized},
+		{"admin on an admin route", "/admin", admin, http.StatusOK},
+		{"partner on an admin route", "/admin", partner, http.StatusForbidden},
+		{"user on an admin route", "/admin", owner, http.StatusForbidden},
+		{"partner on a partner route", "/purchases", partner, http.StatusOK},
+		{"admin on a partner route", "/purchases", admin, http.StatusOK},
+		{"user on a partner route", "/purchases", owner, http.StatusForbidden},
+		{"anonymous on an owned route", "/users/" + ownerID, nil, http.StatusUnauthorized},
+		{"owner", "/users/" + ownerID, owner, http.StatusOK},
+		{"another user", "/users/" + ownerID, otherUser, http.StatusForbidden},
+		{"partner named like the owner", "/users/" + ownerID, namesake, http.StatusForbidden},
+		{"admin on an owned route", "/users/" + ownerID, admin, http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tc.principal))
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Fatalf("status %d, want %d", rec.Code, tc.want)
+			}
+			if challenge := rec.Header().Get("WWW-Authenticate"); (tc.want == http.StatusUnauthorized) != (challenge != "") {
+				t.Fatalf("WWW-Authenticate %q on a %d", challenge, rec.Code)
+			}
+		})
+	}
+}
+
+func TestAuthenticateMiddleware(t *testing.T) {
+	a := newAuthenticator(t, newKeys(t))
+
+	var seen *auth.Principal
+	handler := auth.Authenticate(a)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := auth.FromContext(r.Context()); ok {
+			seen = &principal
+		}
+	}))
+
+	for _, tc := range []struct {
+		name    string
+		header  http.Header
+		want    int
+		subject string
+	}{
+		{"no credentials pass anonymously", nil, http.StatusOK, ""},
+		{"valid credentials set the principal", http.Header{auth.APIKeyHeader: {apiKey}}, http.StatusOK, "acme"},
+		{"invalid credentials are refused", http.Header{auth.APIKeyHeader: {"pk_live_guess"}}, http.StatusUnauthorized, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			seen = nil
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, request(tc.header))
+
+			if rec.Code != tc.want {
+				t.Fatalf("status %d, want %d", rec.Code, tc.want)
+			}
+			switch {
+			case tc.subject == "" && seen != nil:
+				t.Fatalf("principal %+v, want none", *seen)
+			case tc.subject != "" && (seen == nil || seen.Subject != tc.subject):
+				t.Fatalf("principal %v, want %s", seen, tc.subject)
+			}
+		})
+	}
+}
+
+func TestActsForMerchant(t *testing.T) {
+	const other = "1b4e28ba-2fa1-41d2-883f-0016d3cca427"
+
+	for _, tc := range []struct {
+		name      string
+		principal *auth.Principal
+		want      bool
+	}{
+		{"anonymous", nil, false},
+		{"admin", &auth.Principal{Subject: "ops", Role: auth.RoleAdmin}, true},
+		{"the merchant's partner", &auth.Principal{Subject: "acme", Role: auth.RolePartner, MerchantID: merchant}, true},
+		{"another merchant's partner", &auth.Principal{Subject: "globex", Role: auth.RolePartner, MerchantID: other}, false},
+		{"partner without a merchant", &auth.Principal{Subject: "acme", Role: auth.RolePartner}, false},
+		{"user", &auth.Principal{Subject: ownerID, Role: auth.RoleUser}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.principal != nil {
+				ctx = auth.WithPrincipal(ctx, *tc.principal)
+			}
+			if got := auth.ActsForMerchant(ctx, merchant); got != tc.want {
+				t.Fatalf("ActsForMerchant = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func ok(http.ResponseWriter, *http.Request) {}
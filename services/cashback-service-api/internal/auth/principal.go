@@ -0,0 +1,78 @@
+// Package auth authenticates API callers and authorizes them per route.
+// Partners integrate with API keys; users and admins present JWT bearer tokens.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Roles a caller can act as.
+const (
+	RolePartner Role = "partner"
+	RoleUser    Role = "user"
+	RoleAdmin   Role = "admin"
+)
+
+// Authentication methods recorded on the principal.
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+type (
+	// Role grants access to a set of routes.
+	Role string
+
+	// Principal is the authenticated caller. For RoleUser, Subject is the user
+	// ID; a RolePartner caller acts for the merchant MerchantID.
+	Principal struct {
+		Subject    string
+		Role       Role
+		MerchantID string
+		Method     string
+	}
+
+	principalKey struct{}
+)
+
+// IsValidRole reports whether role is a known role.
+func IsValidRole(role Role) bool {
+	return role == RolePartner || role == RoleUser || role == RoleAdmin
+}
+
+// HasRole reports whether the principal acts as one of roles.
+func (p Principal) HasRole(roles ...Role) bool {
+	return slices.Contains(roles, p.Role)
+}
+
+// ActsFor reports whether the principal may act on the purchases and cashback
+// of merchantID: admins for every merchant, partners for their own.
+func (p Principal) ActsFor(merchantID string) bool {
+	switch p.Role {
+	case RoleAdmin:
+		return true
+	case RolePartner:
+		return p.MerchantID != "" && p.MerchantID == merchantID
+	default:
+		return false
+	}
+}
+
+// ActsForMerchant reports whether the caller of ctx may act on the purchases
+// and cashback of merchantID.
+func ActsForMerchant(ctx context.Context, merchantID string) bool {
+	principal, ok := FromContext(ctx)
+	return ok && principal.ActsFor(merchantID)
+}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the authenticated caller, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
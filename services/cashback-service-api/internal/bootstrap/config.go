@@ -12,5 +12,19 @@ var Config = fx.Module("config",
 		config.LoadNATS,
 		config.LoadGRPC,
 		config.LoadServer,
+		config.LoadReferral,
+		config.LoadTier,
+		config.LoadExpiry,
+		config.LoadSIWE,
+		config.LoadWallet,
+		config.LoadCustodial,
+		config.LoadAuth,
+		config.LoadIdempotency,
+		config.LoadRateLimit,
+		config.LoadTracing,
+		config.LoadLog,
+		config.LoadHealth,
+		config.LoadReconciliation,
+		config.LoadCtl,
 	),
 )
@@ -1,9 +1,11 @@
 package bootstrap
 
 import (
+	"context"
+	"log/slog"
+
 	"github.com/cashback-platform/services/cashback-service-api/internal/config"
 	"github.com/cashback-platform/services/cashback-service-api/internal/database"
-	"github.com/cashback-platform/services/cashback-service-api/pkg/logger"
 
 	"go.uber.org/fx"
 	"gorm.io/gorm"
@@ -11,12 +13,25 @@ import (
 
 var Database = fx.Module("database",
 	fx.Provide(NewDatabase),
+	fx.Provide(database.NewTransactor),
 )
 
-func NewDatabase(cfg config.Database) (*gorm.DB, error) {
-	db, err := database.ConnectPostgres(cfg)
+// NewDatabase connects to Postgres and refuses to start unless the schema is
+// at the version this build was written for. Migrations are applied with the
+// migrate subcommand, not on startup.
+func NewDatabase(cfg config.Database, log *slog.Logger) (*gorm.DB, error) {
+	db, err := database.ConnectPostgres(cfg, log)
 	if err != nil {
-		logger.Error("failed to connect to database", "error", err)
+		log.Error("failed to connect to database", "error", err)
+		return nil, err
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Error("database schema check failed", "error", err)
 		return nil, err
 	}
 	return db, nil
@@ -0,0 +1,51 @@
+package bootstrap
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cashback-platform/pkg/health"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/grpc"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/nats"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// Health registers the readiness checks. StartDrain is invoked on its own,
+// last, in service.Module.
+var Health = fx.Module("health",
+	fx.Provide(newHealthChecker),
+	fx.Invoke(registerHealthChecks),
+)
+
+// newHealthChecker bounds each readiness check by HEALTH_CHECK_TIMEOUT.
+func newHealthChecker(cfg config.Health, log *slog.Logger) *health.Checker {
+	return health.NewChecker(cfg.CheckTimeout, log)
+}
+
+func registerHealthChecks(checker *health.Checker, db *gorm.DB, natsClient *nats.NATSClient, adapter *grpc.BlockchainAdapterClient) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	checker.Add("postgres", sqlDB.PingContext)
+	checker.Add("nats", natsClient.CheckConnection)
+	checker.Add("jetstream", natsClient.CheckStreams)
+	checker.Add("blockchain_adapter", adapter.CheckChannel)
+	return nil
+}
+
+// StartDrain fails readiness as soon as the service starts shutting down,
+// then waits HEALTH_SHUTDOWN_DELAY so load balancers stop routing to it
+// before the server stops accepting requests. Stop hooks run in reverse, so
+// it must be the last invoke for readiness to fail before anything stops.
+func StartDrain(lc fx.Lifecycle, checker *health.Checker, cfg config.Health) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			checker.DrainFor(ctx, cfg.ShutdownDelay)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,13 @@
+package bootstrap
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/idempotency"
+
+	"go.uber.org/fx"
+)
+
+var Idempotency = fx.Module("idempotency",
+	fx.Provide(idempotency.NewStore),
+	fx.Provide(idempotency.NewPurgeJob),
+	fx.Invoke(idempotency.StartPurgeJob),
+)
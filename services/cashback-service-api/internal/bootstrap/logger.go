@@ -1,7 +1,11 @@
 package bootstrap
 
 import (
-	"github.com/cashback-platform/services/cashback-service-api/pkg/logger"
+	"log/slog"
+	"os"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
 
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
@@ -12,7 +16,22 @@ func init() {
 }
 
 func Logger() fx.Option {
-	return fx.WithLogger(func() fxevent.Logger {
-		return &fxevent.ConsoleLogger{W: nil}
-	})
+	return fx.Options(
+		fx.WithLogger(func() fxevent.Logger {
+			return &fxevent.ConsoleLogger{W: os.Stderr}
+		}),
+		fx.Module("logger",
+			fx.Provide(NewLogger),
+			// Installs the configured logger before the other invokes run.
+			fx.Invoke(func(*slog.Logger) {}),
+		),
+	)
+}
+
+// NewLogger builds the service logger at LOG_LEVEL and makes it the default,
+// so code logging through the logger package uses it too.
+func NewLogger(cfg config.Log) *slog.Logger {
+	l := logger.New(os.Stdout, cfg.Level, serviceName)
+	logger.Set(l)
+	return l
 }
@@ -0,0 +1,14 @@
+package bootstrap
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/metrics"
+
+	"go.uber.org/fx"
+)
+
+var Metrics = fx.Module("metrics",
+	fx.Provide(metrics.NewRegistry),
+	fx.Provide(metrics.NewHTTP),
+	fx.Provide(metrics.NewGRPCClient),
+	fx.Provide(metrics.NewOutbox),
+)
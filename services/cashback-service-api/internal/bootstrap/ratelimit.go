@@ -0,0 +1,13 @@
+package bootstrap
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/ratelimit"
+
+	"go.uber.org/fx"
+)
+
+var RateLimit = fx.Module("ratelimit",
+	fx.Provide(ratelimit.NewLimiter),
+	fx.Provide(ratelimit.NewPurgeJob),
+	fx.Invoke(ratelimit.StartPurgeJob),
+)
@@ -1,17 +1,33 @@
 package bootstrap
 
 import (
+	"log/slog"
 	"net/http"
 
+	"github.com/cashback-platform/pkg/health"
+	"github.com/cashback-platform/services/cashback-service-api/internal/apispec"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/internal/idempotency"
+	"github.com/cashback-platform/services/cashback-service-api/internal/metrics"
 	"github.com/cashback-platform/services/cashback-service-api/internal/middleware"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ratelimit"
+	"github.com/cashback-platform/services/cashback-service-api/internal/tracing"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/fx"
 )
 
-const serviceName = "cashback-service-api"
+const (
+	serviceName = "cashback-service-api"
+	// healthPath predates the probes and answers like LivePath.
+	healthPath = "/health"
+)
 
 var Router = fx.Module("router",
+	fx.Provide(auth.NewAuthenticator),
+	fx.Provide(apispec.New),
 	fx.Provide(NewRouters),
 )
 
@@ -22,23 +38,49 @@ type RouterOut struct {
 	APIRouter  chi.Router `name:"api"`
 }
 
-func NewRouters() RouterOut {
-	mainRouter := chi.NewRouter()
+type RouterParams struct {
+	fx.In
+
+	Logger        *slog.Logger
+	Authenticator *auth.Authenticator
+	Keys          *idempotency.Store
+	Limiter       *ratelimit.Limiter
+	Doc           *openapi.Document
+	Registry      *metrics.Registry
+	HTTPMetrics   *metrics.HTTP
+	Health        *health.Checker
+}
+
+func NewRouters(p RouterParams) (RouterOut, error) {
+	spec, err := apispec.Handler(p.Doc)
+	if err != nil {
+		return RouterOut{}, err
+	}
 
-	mainRouter.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	mainRouter := chi.NewRouter()
+	mainRouter.Use(tracing.Middleware(healthPath, health.LivePath, health.ReadyPath, metrics.Path))
+	mainRouter.Use(p.HTTPMetrics.Middleware)
+	mainRouter.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		errorhandler.Render(w, r, errorhandler.ErrNotFound)
+	})
+	mainRouter.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		errorhandler.Render(w, r, errorhandler.ErrMethodNotAllowed)
 	})
 
+	mainRouter.Get(healthPath, p.Health.Live)
+	mainRouter.Get(health.LivePath, p.Health.Live)
+	mainRouter.Get(health.ReadyPath, p.Health.Ready)
+	mainRouter.Method(http.MethodGet, metrics.Path, p.Registry.Handler())
+
 	var apiRouter chi.Router
 	mainRouter.Route("/api/v1", func(r chi.Router) {
-		middleware.Setup(r, serviceName)
+		middleware.Setup(r, serviceName, p.Logger, p.Authenticator, p.Keys, p.Limiter, p.Doc)
+		r.Get(apispec.Path, spec)
 		apiRouter = r
 	})
 
 	return RouterOut{
 		MainRouter: mainRouter,
 		APIRouter:  apiRouter,
-	}
+	}, nil
 }
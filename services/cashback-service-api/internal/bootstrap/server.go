@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/cashback-platform/pkg/logger"
 	"github.com/cashback-platform/services/cashback-service-api/internal/config"
-	"github.com/cashback-platform/services/cashback-service-api/pkg/logger"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/fx"
@@ -16,13 +16,22 @@ var Server = fx.Module("server",
 	fx.Invoke(registerServer),
 )
 
-func registerServer(lc fx.Lifecycle, router *chi.Mux, cfg config.Server) {
+type ServerParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Router    *chi.Mux `name:"main"`
+	Config    config.Server
+}
+
+func registerServer(p ServerParams) {
+	cfg := p.Config
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.Port),
-		Handler: router,
+		Handler: p.Router,
 	}
 
-	lc.Append(fx.Hook{
+	p.Lifecycle.Append(fx.Hook{
 		OnStart: func(_ context.Context) error {
 			go func() {
 				logger.Info("Starting server", "port", cfg.Port)
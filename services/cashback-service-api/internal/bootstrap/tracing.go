@@ -0,0 +1,11 @@
+package bootstrap
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/internal/tracing"
+
+	"go.uber.org/fx"
+)
+
+var Tracing = fx.Module("tracing",
+	fx.Invoke(tracing.Setup),
+)
@@ -1,7 +1,12 @@
 package config
 
 import (
-	"github.com/cashback-platform/services/cashback-service-api/pkg/logger"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
 	"github.com/spf13/viper"
 )
 
@@ -26,6 +31,117 @@ type (
 	Server struct {
 		Port string
 	}
+
+	Referral struct {
+		ReferrerBonus     float64
+		RefereeBonus      float64
+		MinPurchaseAmount float64
+	}
+
+	Tier struct {
+		SilverThreshold  float64
+		GoldThreshold    float64
+		SilverMultiplier float64
+		GoldMultiplier   float64
+		WindowDays       int
+		// Tiers are recomputed once RecomputeAfter old, checked every
+		// RecomputeInterval, RecomputeBatchSize users at a time.
+		RecomputeAfter     time.Duration
+		RecomputeInterval  time.Duration
+		RecomputeBatchSize int
+	}
+
+	Expiry struct {
+		TTLDays     int
+		WarningDays int
+		Interval    time.Duration
+		BatchSize   int
+	}
+
+	SIWE struct {
+		Domain       string
+		URI          string
+		Statement    string
+		ChainID      int
+		ChallengeTTL time.Duration
+	}
+
+	Wallet struct {
+		ChangeCooldown time.Duration
+	}
+
+	Custodial struct {
+		Enabled bool
+	}
+
+	Auth struct {
+		Enabled     bool
+		APIKeys     string
+		JWTSecret   string
+		JWKSFile    string
+		JWTIssuer   string
+		JWTAudience string
+	}
+
+	Idempotency struct {
+		TTL         time.Duration
+		LockTimeout time.Duration
+		// Lease is how long a key stays claimed by a request that has not
+		// finished, e.g. because its process died.
+		Lease         time.Duration
+		PurgeInterval time.Duration
+	}
+
+	RateLimit struct {
+		Enabled        bool
+		Backend        string
+		Default        string
+		Routes         string
+		DailyQuota     int64
+		PartnerQuotas  string
+		PurgeInterval  time.Duration
+		TrustedProxies string
+	}
+
+	Log struct {
+		Level slog.Level
+	}
+
+	Tracing struct {
+		Exporter     string
+		OTLPEndpoint string
+		OTLPInsecure bool
+		File         string
+		SampleRatio  float64
+	}
+
+	Health struct {
+		CheckTimeout  time.Duration
+		ShutdownDelay time.Duration
+	}
+
+	Reconciliation struct {
+		Enabled         bool
+		Interval        time.Duration
+		Grace           time.Duration
+		MintDatabaseURL string
+		TokenDecimals   int
+		ReportDir       string
+		ReportFormat    string
+	}
+
+	// Ctl holds what cashbackctl needs beyond the service config: the
+	// databases of mint-consumer and blockchain-adapter.
+	Ctl struct {
+		MintDatabaseURL       string
+		BlockchainDatabaseURL string
+	}
+)
+
+// Reconciliation report formats.
+const (
+	ReportFormatJSON = "json"
+	ReportFormatCSV  = "csv"
 )
 
 func LoadDatabase() Database {
@@ -44,6 +160,62 @@ func LoadServer() Server {
 	return loadConfigWithPanic(loadServerConfig, "failed to load server config")
 }
 
+func LoadReferral() Referral {
+	return loadConfigWithPanic(loadReferralConfig, "failed to load referral config")
+}
+
+func LoadTier() Tier {
+	return loadConfigWithPanic(loadTierConfig, "failed to load tier config")
+}
+
+func LoadExpiry() Expiry {
+	return loadConfigWithPanic(loadExpiryConfig, "failed to load expiry config")
+}
+
+func LoadSIWE() SIWE {
+	return loadConfigWithPanic(loadSIWEConfig, "failed to load SIWE config")
+}
+
+func LoadWallet() Wallet {
+	return loadConfigWithPanic(loadWalletConfig, "failed to load wallet config")
+}
+
+func LoadCustodial() Custodial {
+	return loadConfigWithPanic(loadCustodialConfig, "failed to load custodial wallet config")
+}
+
+func LoadAuth() Auth {
+	return loadConfigWithPanic(loadAuthConfig, "failed to load auth config")
+}
+
+func LoadIdempotency() Idempotency {
+	return loadConfigWithPanic(loadIdempotencyConfig, "failed to load idempotency config")
+}
+
+func LoadRateLimit() RateLimit {
+	return loadConfigWithPanic(loadRateLimitConfig, "failed to load rate limit config")
+}
+
+func LoadLog() Log {
+	return loadConfigWithPanic(loadLogConfig, "failed to load log config")
+}
+
+func LoadTracing() Tracing {
+	return loadConfigWithPanic(loadTracingConfig, "failed to load tracing config")
+}
+
+func LoadHealth() Health {
+	return loadConfigWithPanic(loadHealthConfig, "failed to load health config")
+}
+
+func LoadReconciliation() Reconciliation {
+	return loadConfigWithPanic(loadReconciliationConfig, "failed to load reconciliation config")
+}
+
+func LoadCtl() Ctl {
+	return loadConfigWithPanic(loadCtlConfig, "failed to load cashbackctl config")
+}
+
 func loadDatabaseConfig() (Database, error) {
 	viper.SetDefault("DATABASE_HOST", "localhost")
 	viper.SetDefault("DATABASE_PORT", "5432")
@@ -81,6 +253,215 @@ func loadServerConfig() (Server, error) {
 	return Server{Port: viper.GetString("SERVER_PORT")}, nil
 }
 
+func loadReferralConfig() (Referral, error) {
+	viper.SetDefault("REFERRAL_REFERRER_BONUS", 5.0)
+	viper.SetDefault("REFERRAL_REFEREE_BONUS", 5.0)
+	viper.SetDefault("REFERRAL_MIN_PURCHASE_AMOUNT", 10.0)
+	viper.AutomaticEnv()
+	return Referral{
+		ReferrerBonus:     viper.GetFloat64("REFERRAL_REFERRER_BONUS"),
+		RefereeBonus:      viper.GetFloat64("REFERRAL_REFEREE_BONUS"),
+		MinPurchaseAmount: viper.GetFloat64("REFERRAL_MIN_PURCHASE_AMOUNT"),
+	}, nil
+}
+
+func loadTierConfig() (Tier, error) {
+	viper.SetDefault("TIER_SILVER_THRESHOLD", 1000.0)
+	viper.SetDefault("TIER_GOLD_THRESHOLD", 5000.0)
+	viper.SetDefault("TIER_SILVER_MULTIPLIER", 1.25)
+	viper.SetDefault("TIER_GOLD_MULTIPLIER", 1.5)
+	viper.SetDefault("TIER_WINDOW_DAYS", 90)
+	viper.SetDefault("TIER_RECOMPUTE_AFTER", "24h")
+	viper.SetDefault("TIER_RECOMPUTE_INTERVAL", "1h")
+	viper.SetDefault("TIER_RECOMPUTE_BATCH_SIZE", 100)
+	viper.AutomaticEnv()
+	return Tier{
+		SilverThreshold:    viper.GetFloat64("TIER_SILVER_THRESHOLD"),
+		GoldThreshold:      viper.GetFloat64("TIER_GOLD_THRESHOLD"),
+		SilverMultiplier:   viper.GetFloat64("TIER_SILVER_MULTIPLIER"),
+		GoldMultiplier:     viper.GetFloat64("TIER_GOLD_MULTIPLIER"),
+		WindowDays:         viper.GetInt("TIER_WINDOW_DAYS"),
+		RecomputeAfter:     viper.GetDuration("TIER_RECOMPUTE_AFTER"),
+		RecomputeInterval:  viper.GetDuration("TIER_RECOMPUTE_INTERVAL"),
+		RecomputeBatchSize: viper.GetInt("TIER_RECOMPUTE_BATCH_SIZE"),
+	}, nil
+}
+
+func loadExpiryConfig() (Expiry, error) {
+	viper.SetDefault("CASHBACK_EXPIRY_DAYS", 90)
+	viper.SetDefault("CASHBACK_EXPIRY_WARNING_DAYS", 7)
+	viper.SetDefault("CASHBACK_EXPIRY_INTERVAL", "1h")
+	viper.SetDefault("CASHBACK_EXPIRY_BATCH_SIZE", 100)
+	viper.AutomaticEnv()
+	return Expiry{
+		TTLDays:     viper.GetInt("CASHBACK_EXPIRY_DAYS"),
+		WarningDays: viper.GetInt("CASHBACK_EXPIRY_WARNING_DAYS"),
+		Interval:    viper.GetDuration("CASHBACK_EXPIRY_INTERVAL"),
+		BatchSize:   viper.GetInt("CASHBACK_EXPIRY_BATCH_SIZE"),
+	}, nil
+}
+
+func loadSIWEConfig() (SIWE, error) {
+	viper.SetDefault("SIWE_DOMAIN", "localhost:8080")
+	viper.SetDefault("SIWE_URI", "http://localhost:8080")
+	viper.SetDefault("SIWE_STATEMENT", "Link this wallet to your cashback account.")
+	viper.SetDefault("SIWE_CHAIN_ID", 1)
+	viper.SetDefault("SIWE_CHALLENGE_TTL", "10m")
+	viper.AutomaticEnv()
+	return SIWE{
+		Domain:       viper.GetString("SIWE_DOMAIN"),
+		URI:          viper.GetString("SIWE_URI"),
+		Statement:    viper.GetString("SIWE_STATEMENT"),
+		ChainID:      viper.GetInt("SIWE_CHAIN_ID"),
+		ChallengeTTL: viper.GetDuration("SIWE_CHALLENGE_TTL"),
+	}, nil
+}
+
+func loadWalletConfig() (Wallet, error) {
+	viper.SetDefault("WALLET_CHANGE_COOLDOWN", "24h")
+	viper.AutomaticEnv()
+	return Wallet{ChangeCooldown: viper.GetDuration("WALLET_CHANGE_COOLDOWN")}, nil
+}
+
+func loadCustodialConfig() (Custodial, error) {
+	viper.SetDefault("CUSTODIAL_WALLETS_ENABLED", false)
+	viper.AutomaticEnv()
+	return Custodial{Enabled: viper.GetBool("CUSTODIAL_WALLETS_ENABLED")}, nil
+}
+
+func loadAuthConfig() (Auth, error) {
+	viper.SetDefault("AUTH_ENABLED", true)
+	viper.SetDefault("AUTH_API_KEYS", "")
+	viper.SetDefault("AUTH_JWT_SECRET", "")
+	viper.SetDefault("AUTH_JWKS_FILE", "")
+	viper.SetDefault("AUTH_JWT_ISSUER", "")
+	viper.SetDefault("AUTH_JWT_AUDIENCE", "")
+	viper.AutomaticEnv()
+	return Auth{
+		Enabled:     viper.GetBool("AUTH_ENABLED"),
+		APIKeys:     viper.GetString("AUTH_API_KEYS"),
+		JWTSecret:   viper.GetString("AUTH_JWT_SECRET"),
+		JWKSFile:    viper.GetString("AUTH_JWKS_FILE"),
+		JWTIssuer:   viper.GetString("AUTH_JWT_ISSUER"),
+		JWTAudience: viper.GetString("AUTH_JWT_AUDIENCE"),
+	}, nil
+}
+
+func loadIdempotencyConfig() (Idempotency, error) {
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", "24h")
+	viper.SetDefault("IDEMPOTENCY_LOCK_TIMEOUT", "10s")
+	viper.SetDefault("IDEMPOTENCY_LEASE", "1m")
+	viper.SetDefault("IDEMPOTENCY_PURGE_INTERVAL", "1h")
+	viper.AutomaticEnv()
+	return Idempotency{
+		TTL:           viper.GetDuration("IDEMPOTENCY_KEY_TTL"),
+		LockTimeout:   viper.GetDuration("IDEMPOTENCY_LOCK_TIMEOUT"),
+		Lease:         viper.GetDuration("IDEMPOTENCY_LEASE"),
+		PurgeInterval: viper.GetDuration("IDEMPOTENCY_PURGE_INTERVAL"),
+	}, nil
+}
+
+func loadRateLimitConfig() (RateLimit, error) {
+	viper.SetDefault("RATE_LIMIT_ENABLED", true)
+	viper.SetDefault("RATE_LIMIT_BACKEND", "memory")
+	viper.SetDefault("RATE_LIMIT_DEFAULT", "100/1m")
+	viper.SetDefault("RATE_LIMIT_ROUTES", "POST /cashback/calculate=10/s")
+	viper.SetDefault("RATE_LIMIT_DAILY_QUOTA", 0)
+	viper.SetDefault("RATE_LIMIT_PARTNER_QUOTAS", "")
+	viper.SetDefault("RATE_LIMIT_PURGE_INTERVAL", "10m")
+	viper.SetDefault("RATE_LIMIT_TRUSTED_PROXIES", "")
+	viper.AutomaticEnv()
+	return RateLimit{
+		Enabled:        viper.GetBool("RATE_LIMIT_ENABLED"),
+		Backend:        viper.GetString("RATE_LIMIT_BACKEND"),
+		Default:        viper.GetString("RATE_LIMIT_DEFAULT"),
+		Routes:         viper.GetString("RATE_LIMIT_ROUTES"),
+		DailyQuota:     viper.GetInt64("RATE_LIMIT_DAILY_QUOTA"),
+		PartnerQuotas:  viper.GetString("RATE_LIMIT_PARTNER_QUOTAS"),
+		PurgeInterval:  viper.GetDuration("RATE_LIMIT_PURGE_INTERVAL"),
+		TrustedProxies: viper.GetString("RATE_LIMIT_TRUSTED_PROXIES"),
+	}, nil
+}
+
+func loadLogConfig() (Log, error) {
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.AutomaticEnv()
+	level, err := logger.ParseLevel(viper.GetString("LOG_LEVEL"))
+	if err != nil {
+		return Log{}, fmt.Errorf("LOG_LEVEL: %w", err)
+	}
+	return Log{Level: level}, nil
+}
+
+func loadTracingConfig() (Tracing, error) {
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_OTLP_ENDPOINT", "localhost:4317")
+	viper.SetDefault("TRACING_OTLP_INSECURE", true)
+	viper.SetDefault("TRACING_FILE", "traces.json")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	viper.AutomaticEnv()
+	return Tracing{
+		Exporter:     viper.GetString("TRACING_EXPORTER"),
+		OTLPEndpoint: viper.GetString("TRACING_OTLP_ENDPOINT"),
+		OTLPInsecure: viper.GetBool("TRACING_OTLP_INSECURE"),
+		File:         viper.GetString("TRACING_FILE"),
+		SampleRatio:  viper.GetFloat64("TRACING_SAMPLE_RATIO"),
+	}, nil
+}
+
+func loadHealthConfig() (Health, error) {
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	viper.SetDefault("HEALTH_SHUTDOWN_DELAY", "0s")
+	viper.AutomaticEnv()
+	return Health{
+		CheckTimeout:  viper.GetDuration("HEALTH_CHECK_TIMEOUT"),
+		ShutdownDelay: viper.GetDuration("HEALTH_SHUTDOWN_DELAY"),
+	}, nil
+}
+
+func loadReconciliationConfig() (Reconciliation, error) {
+	viper.SetDefault("RECONCILIATION_ENABLED", false)
+	viper.SetDefault("RECONCILIATION_INTERVAL", "24h")
+	viper.SetDefault("RECONCILIATION_GRACE", "1h")
+	viper.SetDefault("RECONCILIATION_MINT_DATABASE_URL", "")
+	viper.SetDefault("RECONCILIATION_TOKEN_DECIMALS", 18)
+	viper.SetDefault("RECONCILIATION_REPORT_DIR", "reports")
+	viper.SetDefault("RECONCILIATION_REPORT_FORMAT", ReportFormatJSON)
+	viper.AutomaticEnv()
+	cfg := Reconciliation{
+		Enabled:         viper.GetBool("RECONCILIATION_ENABLED"),
+		Interval:        viper.GetDuration("RECONCILIATION_INTERVAL"),
+		Grace:           viper.GetDuration("RECONCILIATION_GRACE"),
+		MintDatabaseURL: viper.GetString("RECONCILIATION_MINT_DATABASE_URL"),
+		TokenDecimals:   viper.GetInt("RECONCILIATION_TOKEN_DECIMALS"),
+		ReportDir:       viper.GetString("RECONCILIATION_REPORT_DIR"),
+		ReportFormat:    viper.GetString("RECONCILIATION_REPORT_FORMAT"),
+	}
+	if cfg.ReportFormat != ReportFormatJSON && cfg.ReportFormat != ReportFormatCSV {
+		return Reconciliation{}, fmt.Errorf("RECONCILIATION_REPORT_FORMAT: must be json or csv, got %q", cfg.ReportFormat)
+	}
+	if cfg.Enabled && cfg.MintDatabaseURL == "" {
+		return Reconciliation{}, errors.New("RECONCILIATION_MINT_DATABASE_URL is required when RECONCILIATION_ENABLED is true")
+	}
+	return cfg, nil
+}
+
+func loadCtlConfig() (Ctl, error) {
+	viper.SetDefault("RECONCILIATION_MINT_DATABASE_URL", "")
+	viper.SetDefault("CASHBACKCTL_BLOCKCHAIN_DATABASE_URL", "")
+	viper.AutomaticEnv()
+
+	// The mint database is the one reconciliation reads unless set apart.
+	mintURL := viper.GetString("CASHBACKCTL_MINT_DATABASE_URL")
+	if mintURL == "" {
+		mintURL = viper.GetString("RECONCILIATION_MINT_DATABASE_URL")
+	}
+	return Ctl{
+		MintDatabaseURL:       mintURL,
+		BlockchainDatabaseURL: viper.GetString("CASHBACKCTL_BLOCKCHAIN_DATABASE_URL"),
+	}, nil
+}
+
 func loadConfigWithPanic[T any](loader func() (T, error), errorMsg string) T {
 	config, err := loader()
 	if err != nil {
@@ -0,0 +1,37 @@
+// Package databasetest gives tests a Postgres schema of their own, migrated to
+// the latest version, in the database at TEST_DATABASE_URL.
+package databasetest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/cashback-platform/pkg/postgrestest"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"gorm.io/gorm"
+)
+
+// New creates a schema, applies every migration to it and returns a
+// connection using it. The schema is dropped when t ends. t is skipped when
+// TEST_DATABASE_URL is not set.
+func New(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := postgrestest.Schema(t)
+	db, err := database.Open(dsn, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { postgrestest.Close(t, db) })
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is the duration above which a query is logged as slow.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger writes GORM's output to the service logger: queries at debug,
+// slow and failed ones at warn. Statements go through the same redaction as
+// everything else, so values bound into them are masked.
+type gormLogger struct {
+	log *slog.Logger
+}
+
+func newGormLogger(log *slog.Logger) gormlogger.Interface {
+	return gormLogger{log: log}
+}
+
+// LogMode is a no-op: the level is LOG_LEVEL.
+func (l gormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l gormLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.log.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l gormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	l.log.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l gormLogger) Error(ctx context.Context, msg string, args ...any) {
+	l.log.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		l.log.WarnContext(ctx, "query failed", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds(), "error", err)
+	case elapsed > slowQueryThreshold:
+		sql, rows := fc()
+		l.log.WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	case l.log.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		l.log.DebugContext(ctx, "query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	}
+}
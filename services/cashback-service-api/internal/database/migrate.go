@@ -0,0 +1,16 @@
+package database
+
+import (
+	"embed"
+
+	"github.com/cashback-platform/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// NewMigrator returns a migrator for the migrations embedded in the binary.
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	return migrate.New(db, migrationFiles)
+}
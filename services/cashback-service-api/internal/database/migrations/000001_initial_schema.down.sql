@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS rate_limit_quotas;
+DROP TABLE IF EXISTS rate_limit_buckets;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS cashback_ledger;
+DROP TABLE IF EXISTS purchases;
+DROP TABLE IF EXISTS campaigns;
+DROP TABLE IF EXISTS merchants;
+DROP TABLE IF EXISTS user_wallets;
+DROP TABLE IF EXISTS wallet_challenges;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,163 @@
+-- Baseline schema. Tables and indexes use the names GORM gave them, and
+-- IF NOT EXISTS lets databases created before versioned migrations adopt
+-- this version as is.
+
+CREATE TABLE IF NOT EXISTS users (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    external_id          TEXT NOT NULL,
+    email                TEXT NOT NULL,
+    wallet_address       VARCHAR(42) NOT NULL DEFAULT '',
+    wallet_verified_at   TIMESTAMPTZ,
+    payout_changed_at    TIMESTAMPTZ,
+    custodial_address    VARCHAR(42) NOT NULL DEFAULT '',
+    referral_code        VARCHAR(16) NOT NULL,
+    referred_by          UUID,
+    referral_rewarded_at TIMESTAMPTZ,
+    tier                 VARCHAR(20) NOT NULL DEFAULT 'bronze',
+    rolling_volume       DECIMAL NOT NULL DEFAULT 0,
+    tier_updated_at      TIMESTAMPTZ,
+    deactivated_at       TIMESTAMPTZ,
+    erased_at            TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ,
+    updated_at           TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_external_id ON users (external_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_referral_code ON users (referral_code);
+CREATE INDEX IF NOT EXISTS idx_users_referred_by ON users (referred_by);
+CREATE INDEX IF NOT EXISTS idx_users_tier ON users (tier);
+CREATE INDEX IF NOT EXISTS idx_users_deactivated_at ON users (deactivated_at);
+
+CREATE TABLE IF NOT EXISTS wallet_challenges (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID NOT NULL,
+    address    VARCHAR(42) NOT NULL,
+    nonce      VARCHAR(32) NOT NULL,
+    message    TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_wallet_challenges_user_id ON wallet_challenges (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_challenges_nonce ON wallet_challenges (nonce);
+
+CREATE TABLE IF NOT EXISTS user_wallets (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL,
+    address     VARCHAR(42) NOT NULL,
+    verified_at TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_wallets_user_address ON user_wallets (user_id, address);
+
+CREATE TABLE IF NOT EXISTS merchants (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name             TEXT NOT NULL,
+    category_code    VARCHAR(4) NOT NULL,
+    status           TEXT NOT NULL DEFAULT 'active',
+    cashback_percent DECIMAL NOT NULL,
+    funding_account  TEXT NOT NULL,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_merchants_category_code ON merchants (category_code);
+CREATE INDEX IF NOT EXISTS idx_merchants_status ON merchants (status);
+
+CREATE TABLE IF NOT EXISTS campaigns (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name               TEXT NOT NULL,
+    merchant_id        UUID,
+    category_code      VARCHAR(4),
+    new_user_days      BIGINT NOT NULL DEFAULT 0,
+    max_purchase_count BIGINT NOT NULL DEFAULT 0,
+    min_tier           VARCHAR(20),
+    multiplier         DECIMAL NOT NULL DEFAULT 1,
+    bonus_amount       DECIMAL NOT NULL DEFAULT 0,
+    budget             DECIMAL NOT NULL,
+    spent              DECIMAL NOT NULL DEFAULT 0,
+    starts_at          TIMESTAMPTZ NOT NULL,
+    ends_at            TIMESTAMPTZ NOT NULL,
+    created_at         TIMESTAMPTZ,
+    updated_at         TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_campaigns_merchant_id ON campaigns (merchant_id);
+CREATE INDEX IF NOT EXISTS idx_campaigns_starts_at ON campaigns (starts_at);
+CREATE INDEX IF NOT EXISTS idx_campaigns_ends_at ON campaigns (ends_at);
+
+CREATE TABLE IF NOT EXISTS purchases (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL,
+    amount      DECIMAL NOT NULL,
+    merchant_id UUID NOT NULL,
+    status      TEXT NOT NULL DEFAULT 'pending',
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_purchases_user_id ON purchases (user_id);
+CREATE INDEX IF NOT EXISTS idx_purchases_merchant_id ON purchases (merchant_id);
+CREATE INDEX IF NOT EXISTS idx_purchases_user_created ON purchases (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS cashback_ledger (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id          UUID NOT NULL,
+    purchase_id      UUID NOT NULL,
+    merchant_id      UUID,
+    type             VARCHAR(20) NOT NULL DEFAULT 'purchase',
+    amount           DECIMAL NOT NULL,
+    base_amount      DECIMAL NOT NULL,
+    campaign_id      UUID,
+    campaign_amount  DECIMAL NOT NULL DEFAULT 0,
+    cashback_percent DECIMAL NOT NULL,
+    status           TEXT NOT NULL DEFAULT 'pending',
+    wallet_address   VARCHAR(42),
+    expiry_warned_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_cashback_ledger_user_id ON cashback_ledger (user_id);
+CREATE INDEX IF NOT EXISTS idx_cashback_ledger_merchant_id ON cashback_ledger (merchant_id);
+CREATE INDEX IF NOT EXISTS idx_cashback_ledger_campaign_id ON cashback_ledger (campaign_id);
+CREATE INDEX IF NOT EXISTS idx_cashback_ledger_status ON cashback_ledger (status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cashback_ledger_purchase_user_type ON cashback_ledger (user_id, purchase_id, type);
+CREATE INDEX IF NOT EXISTS idx_cashback_ledger_user_created ON cashback_ledger (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id          UUID PRIMARY KEY,
+    event_type  TEXT NOT NULL,
+    payload     BYTEA NOT NULL,
+    retry_count BIGINT DEFAULT 0,
+    max_retries BIGINT DEFAULT 3,
+    published   BOOLEAN DEFAULT false,
+    failed      BOOLEAN DEFAULT false,
+    error       TEXT,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (created_at) WHERE NOT published AND NOT failed;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope       TEXT NOT NULL,
+    key         TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    status_code BIGINT NOT NULL DEFAULT 0,
+    header      JSONB,
+    body        BYTEA,
+    created_at  TIMESTAMPTZ,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key        TEXT PRIMARY KEY,
+    tokens     DECIMAL NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
+
+CREATE TABLE IF NOT EXISTS rate_limit_quotas (
+    key  TEXT NOT NULL,
+    day  DATE NOT NULL,
+    used BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (key, day)
+);
@@ -0,0 +1,3 @@
+-- W3C trace context of the request that wrote the event, so the publisher can
+-- continue its trace.
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS trace_context JSONB;
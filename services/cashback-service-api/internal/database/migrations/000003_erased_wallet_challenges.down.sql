@@ -0,0 +1,2 @@
+-- Deleted challenges cannot be restored.
+SELECT 1;
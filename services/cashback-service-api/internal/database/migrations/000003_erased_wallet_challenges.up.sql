@@ -0,0 +1,4 @@
+-- Wallet challenges hold the signed-in message and address, so they are
+-- personal data; erasures before they were deleted with the user left some.
+DELETE FROM wallet_challenges
+WHERE user_id IN (SELECT id FROM users WHERE erased_at IS NOT NULL);
@@ -0,0 +1,22 @@
+package database
+
+import (
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
+
+	"gorm.io/gorm"
+)
+
+// Paginate restricts query to the page described by req, ordered by
+// (created_at, id). It fetches one record more than req.Limit so that
+// pagination.NewPage can tell whether another page follows.
+func Paginate(query *gorm.DB, req pagination.Request) *gorm.DB {
+	order, after := "created_at DESC, id DESC", "(created_at, id) < (?, ?)"
+	if req.Ascending {
+		order, after = "created_at ASC, id ASC", "(created_at, id) > (?, ?)"
+	}
+
+	if req.After != nil {
+		query = query.Where(after, req.After.CreatedAt, req.After.ID)
+	}
+	return query.Order(order).Limit(req.Limit + 1)
+}
@@ -2,16 +2,15 @@ package database
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/config"
-	"github.com/cashback-platform/services/cashback-service-api/pkg/logger"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	gormlogger "gorm.io/gorm/logger"
 )
 
-func ConnectPostgres(cfg config.Database) (*gorm.DB, error) {
+func ConnectPostgres(cfg config.Database, log *slog.Logger) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host,
@@ -22,13 +21,25 @@ func ConnectPostgres(cfg config.Database) (*gorm.DB, error) {
 		cfg.SSLMode,
 	)
 
+	db, err := Open(dsn, log)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("database connected")
+	return db, nil
+}
+
+// Open connects to the Postgres database at dsn, which is either a URL or a
+// list of key=value settings. Unique violations are reported as
+// gorm.ErrDuplicatedKey, for the repositories to map to domain errors.
+func Open(dsn string, log *slog.Logger) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormlogger.Default.LogMode(gormlogger.Info),
+		Logger:         newGormLogger(log),
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-
-	logger.Info("Database connected successfully")
 	return db, nil
 }
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transactor runs several repository writes in one database transaction.
+type Transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return Transactor{db: db}
+}
+
+// InTransaction runs fn in a transaction, committed if fn returns nil and
+// rolled back otherwise. Repositories that look up their connection with Conn
+// write through the transaction when given the context passed to fn.
+func (t Transactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction ctx was given by InTransaction, or db outside
+// of one.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
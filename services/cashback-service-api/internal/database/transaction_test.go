@@ -0,0 +1,50 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database/databasetest"
+	outboxrepository "github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging/outbox/repository"
+)
+
+func TestInTransaction(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int64
+	}{
+		{"commits when fn succeeds", nil, 2},
+		{"rolls back when fn fails", errFailed, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := databasetest.New(t)
+			outbox := outboxrepository.New(db)
+
+			err := database.NewTransactor(db).InTransaction(ctx, func(ctx context.Context) error {
+				for range 2 {
+					if err := outbox.Create(ctx, "test.event", []byte(`{}`), nil); err != nil {
+						return err
+					}
+				}
+				return tc.err
+			})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("error = %v, want %v", err, tc.err)
+			}
+
+			pending, err := outbox.CountPending(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pending != tc.want {
+				t.Fatalf("%d events stored, want %d", pending, tc.want)
+			}
+		})
+	}
+}
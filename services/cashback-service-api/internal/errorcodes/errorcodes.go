@@ -0,0 +1,188 @@
+// Package errorcodes is the registry of every error the API reports to
+// clients. Each error gets a stable, machine-readable code and an HTTP
+// status; errors not listed here are reported as INTERNAL_ERROR.
+//
+// Codes are part of the public API: never change or reuse one, add new ones.
+package errorcodes
+
+import (
+	"net/http"
+
+	campaigndomain "github.com/cashback-platform/services/cashback-service-api/internal/app/campaign/domain"
+	cashbackdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	calculatecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	merchantdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	purchasedomain "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/domain"
+	createpurchaseuc "github.com/cashback-platform/services/cashback-service-api/internal/app/purchase/usecase/createpurchase"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	updateuserhandler "github.com/cashback-platform/services/cashback-service-api/internal/app/user/handler/updateuser"
+	claimcustodialwalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/claimcustodialwallet"
+	createuseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/createuser"
+	requestwalletchallengeuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/requestwalletchallenge"
+	setpayoutwalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/setpayoutwallet"
+	updateuseruc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/updateuser"
+	verifywalletuc "github.com/cashback-platform/services/cashback-service-api/internal/app/user/usecase/verifywallet"
+	"github.com/cashback-platform/services/cashback-service-api/internal/idempotency"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ratelimit"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/validator"
+)
+
+// Register adds every API error to the errorhandler registry.
+func Register() {
+	registerRequestErrors()
+	registerUserErrors()
+	registerMerchantErrors()
+	registerCampaignErrors()
+	registerPurchaseErrors()
+	registerCashbackErrors()
+}
+
+func registerRequestErrors() {
+	r := errorhandler.Register
+
+	r("REQUIRED", http.StatusBadRequest, validator.ErrRequired)
+	r("INVALID_EMAIL", http.StatusBadRequest, validator.ErrInvalidEmail)
+	r("INVALID_WALLET_ADDRESS", http.StatusBadRequest,
+		validator.ErrInvalidWalletAddress,
+		requestwalletchallengeuc.ErrInvalidAddress,
+		setpayoutwalletuc.ErrInvalidAddress,
+		claimcustodialwalletuc.ErrInvalidAddress,
+	)
+
+	r("INVALID_TYPE", http.StatusBadRequest, openapi.ErrInvalidType)
+	r("INVALID_FORMAT", http.StatusBadRequest, openapi.ErrInvalidFormat)
+	r("UNKNOWN_FIELD", http.StatusBadRequest, openapi.ErrUnknownField)
+
+	r("INVALID_LIMIT", http.StatusBadRequest, httpjson.ErrInvalidLimit)
+	r("INVALID_CURSOR", http.StatusBadRequest, httpjson.ErrInvalidCursor)
+	r("INVALID_SORT", http.StatusBadRequest, httpjson.ErrInvalidSort)
+	r("INVALID_TIMESTAMP", http.StatusBadRequest, httpjson.ErrInvalidTime)
+	r("INVALID_STATUS_FILTER", http.StatusBadRequest,
+		purchasedomain.ErrInvalidStatusFilter,
+		cashbackdomain.ErrInvalidStatusFilter,
+	)
+	r("INVALID_DATE_RANGE", http.StatusBadRequest,
+		purchasedomain.ErrInvalidDateRange,
+		cashbackdomain.ErrInvalidDateRange,
+	)
+
+	r("IDEMPOTENCY_KEY_TOO_LONG", http.StatusBadRequest, idempotency.ErrKeyTooLong)
+	r("IDEMPOTENCY_KEY_REUSED", http.StatusConflict, idempotency.ErrKeyReused)
+	r("IDEMPOTENCY_KEY_IN_PROGRESS", http.StatusConflict, idempotency.ErrInProgress)
+	r("IDEMPOTENCY_KEY_REQUIRED", http.StatusBadRequest, claimcustodialwalletuc.ErrIdempotencyKeyRequired)
+
+	r("RATE_LIMITED", http.StatusTooManyRequests, ratelimit.ErrRateLimited)
+	r("QUOTA_EXCEEDED", http.StatusTooManyRequests, ratelimit.ErrQuotaExceeded)
+}
+
+func registerUserErrors() {
+	r := errorhandler.Register
+
+	r("USER_NOT_FOUND", http.StatusNotFound,
+		userdomain.ErrUserNotFound,
+		calculatecashbackuc.ErrUserNotFound,
+	)
+	r("USER_ERASED", http.StatusGone, userdomain.ErrUserErased)
+	r("USER_ALREADY_EXISTS", http.StatusConflict,
+		createuseruc.ErrUserAlreadyExists,
+		updateuseruc.ErrUserAlreadyExists,
+	)
+	r("NO_CHANGES", http.StatusBadRequest, updateuserhandler.ErrNoChanges)
+	r("INVALID_REFERRAL_CODE", http.StatusBadRequest, createuseruc.ErrInvalidReferralCode)
+	r("SELF_REFERRAL", http.StatusUnprocessableEntity, createuseruc.ErrSelfReferral)
+	r("CUSTODIAL_WALLET_UNAVAILABLE", http.StatusServiceUnavailable, createuseruc.ErrCustodialWalletUnavailable)
+
+	r("CHALLENGE_NOT_FOUND", http.StatusNotFound,
+		userdomain.ErrChallengeNotFound,
+		verifywalletuc.ErrChallengeNotFound,
+	)
+	r("CHALLENGE_EXPIRED", http.StatusGone, verifywalletuc.ErrChallengeExpired)
+	r("CHALLENGE_ALREADY_USED", http.StatusConflict, verifywalletuc.ErrChallengeUsed)
+	r("INVALID_SIWE_MESSAGE", http.StatusBadRequest, verifywalletuc.ErrInvalidMessage)
+	r("INVALID_SIGNATURE", http.StatusBadRequest, verifywalletuc.ErrInvalidSignature)
+	r("CHALLENGE_MESSAGE_MISMATCH", http.StatusUnauthorized, verifywalletuc.ErrMessageMismatch)
+	r("SIGNER_MISMATCH", http.StatusUnauthorized, verifywalletuc.ErrSignerMismatch)
+
+	r("WALLET_NOT_FOUND", http.StatusNotFound, userdomain.ErrWalletNotFound)
+	r("PAYOUT_WALLET_COOLDOWN", http.StatusConflict, userdomain.ErrPayoutCooldown)
+	r("WALLET_TOO_NEW", http.StatusConflict, userdomain.ErrWalletTooNew)
+
+	r("NO_CUSTODIAL_WALLET", http.StatusConflict, claimcustodialwalletuc.ErrNoCustodialWallet)
+	r("NOTHING_TO_CLAIM", http.StatusConflict, claimcustodialwalletuc.ErrNothingToClaim)
+	r("CLAIM_TO_CUSTODIAL_WALLET", http.StatusBadRequest, claimcustodialwalletuc.ErrCustodialAddress)
+}
+
+func registerMerchantErrors() {
+	r := errorhandler.Register
+
+	r("MERCHANT_NOT_FOUND", http.StatusNotFound,
+		merchantdomain.ErrMerchantNotFound,
+		createpurchaseuc.ErrMerchantNotFound,
+		calculatecashbackuc.ErrMerchantNotFound,
+	)
+	r("MERCHANT_NOT_ACTIVE", http.StatusUnprocessableEntity,
+		createpurchaseuc.ErrMerchantNotActive,
+		calculatecashbackuc.ErrMerchantNotActive,
+	)
+	r("INVALID_MERCHANT_NAME", http.StatusBadRequest, merchantdomain.ErrInvalidName)
+	r("INVALID_CATEGORY_CODE", http.StatusBadRequest,
+		merchantdomain.ErrInvalidCategoryCode,
+		campaigndomain.ErrInvalidCategoryCode,
+	)
+	r("INVALID_CASHBACK_PERCENT", http.StatusBadRequest, merchantdomain.ErrInvalidPercentage)
+	r("INVALID_FUNDING_ACCOUNT", http.StatusBadRequest, merchantdomain.ErrInvalidFundingAccount)
+	r("INVALID_MERCHANT_STATUS", http.StatusBadRequest, merchantdomain.ErrInvalidStatus)
+}
+
+func registerCampaignErrors() {
+	r := errorhandler.Register
+
+	r("CAMPAIGN_NOT_FOUND", http.StatusNotFound, campaigndomain.ErrCampaignNotFound)
+	r("CAMPAIGN_BUDGET_EXHAUSTED", http.StatusConflict, campaigndomain.ErrBudgetExhausted)
+	r("INVALID_CAMPAIGN_NAME", http.StatusBadRequest, campaigndomain.ErrInvalidName)
+	r("INVALID_CAMPAIGN_PERIOD", http.StatusBadRequest, campaigndomain.ErrInvalidPeriod)
+	r("INVALID_BOOST_MULTIPLIER", http.StatusBadRequest, campaigndomain.ErrInvalidMultiplier)
+	r("INVALID_FIXED_BONUS", http.StatusBadRequest, campaigndomain.ErrInvalidBonus)
+	r("NO_BOOST", http.StatusBadRequest, campaigndomain.ErrNoBoost)
+	r("INVALID_BUDGET", http.StatusBadRequest, campaigndomain.ErrInvalidBudget)
+	r("INVALID_ELIGIBILITY_PREDICATE", http.StatusBadRequest, campaigndomain.ErrInvalidPredicate)
+}
+
+func registerPurchaseErrors() {
+	r := errorhandler.Register
+
+	r("PURCHASE_NOT_FOUND", http.StatusNotFound,
+		purchasedomain.ErrPurchaseNotFound,
+		calculatecashbackuc.ErrPurchaseNotFound,
+	)
+	r("PURCHASE_ALREADY_REFUNDED", http.StatusConflict, purchasedomain.ErrAlreadyRefunded)
+	r("INVALID_AMOUNT", http.StatusBadRequest,
+		purchasedomain.ErrInvalidAmount,
+		createpurchaseuc.ErrInvalidAmount,
+	)
+	r("INVALID_USER_ID", http.StatusBadRequest,
+		purchasedomain.ErrInvalidUserID,
+		createpurchaseuc.ErrInvalidUserID,
+		cashbackdomain.ErrInvalidUserID,
+	)
+	r("INVALID_MERCHANT_ID", http.StatusBadRequest,
+		purchasedomain.ErrInvalidMerchant,
+		createpurchaseuc.ErrInvalidMerchant,
+		cashbackdomain.ErrInvalidMerchantID,
+	)
+}
+
+func registerCashbackErrors() {
+	r := errorhandler.Register
+
+	r("CASHBACK_NOT_FOUND", http.StatusNotFound, cashbackdomain.ErrCashbackNotFound)
+	r("CASHBACK_ALREADY_EXISTS", http.StatusConflict, calculatecashbackuc.ErrCashbackAlreadyExists)
+	r("USER_NOT_ACTIVE", http.StatusUnprocessableEntity, calculatecashbackuc.ErrUserNotActive)
+	r("INVALID_PURCHASE_ID", http.StatusBadRequest, cashbackdomain.ErrInvalidPurchaseID)
+	r("INVALID_CASHBACK_AMOUNT", http.StatusUnprocessableEntity, cashbackdomain.ErrInvalidAmount)
+	r("CASHBACK_PERCENT_OUT_OF_RANGE", http.StatusUnprocessableEntity, cashbackdomain.ErrInvalidPercentage)
+	r("CASHBACK_NOT_EXPIRABLE", http.StatusConflict, cashbackdomain.ErrNotExpirable)
+}
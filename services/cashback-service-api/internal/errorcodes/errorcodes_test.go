@@ -0,0 +1,77 @@
+package errorcodes_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"testing"
+
+	cashbackdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/domain"
+	calculatecashbackuc "github.com/cashback-platform/services/cashback-service-api/internal/app/cashback/usecase/calculatecashback"
+	merchantdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/merchant/domain"
+	userdomain "github.com/cashback-platform/services/cashback-service-api/internal/app/user/domain"
+	"github.com/cashback-platform/services/cashback-service-api/internal/errorcodes"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ratelimit"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+)
+
+func TestMain(m *testing.M) {
+	errorcodes.Register()
+	os.Exit(m.Run())
+}
+
+var codePattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+func TestRegistryIsConsistent(t *testing.T) {
+	statuses := map[string]int{}
+	registered := map[error]string{}
+
+	for _, e := range errorhandler.Entries() {
+		if e.Err == nil {
+			t.Errorf("%s is registered for a nil error", e.Code)
+			continue
+		}
+		if !codePattern.MatchString(e.Code) {
+			t.Errorf("code %q is not UPPER_SNAKE_CASE", e.Code)
+		}
+		if e.Status < http.StatusBadRequest || http.StatusText(e.Status) == "" {
+			t.Errorf("%s has status %d, want a 4xx or 5xx", e.Code, e.Status)
+		}
+		// Clients branch on the code, so it must always mean the same status.
+		if status, ok := statuses[e.Code]; ok && status != e.Status {
+			t.Errorf("%s is registered with %d and %d", e.Code, status, e.Status)
+		}
+		statuses[e.Code] = e.Status
+		// A second registration of the same error would never be reached.
+		if code, ok := registered[e.Err]; ok {
+			t.Errorf("%q is registered as %s and %s", e.Err, code, e.Code)
+		}
+		registered[e.Err] = e.Code
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		code   string
+		status int
+	}{
+		{userdomain.ErrUserNotFound, "USER_NOT_FOUND", http.StatusNotFound},
+		{calculatecashbackuc.ErrUserNotFound, "USER_NOT_FOUND", http.StatusNotFound},
+		{userdomain.ErrUserErased, "USER_ERASED", http.StatusGone},
+		{calculatecashbackuc.ErrCashbackAlreadyExists, "CASHBACK_ALREADY_EXISTS", http.StatusConflict},
+		{merchantdomain.ErrInvalidPercentage, "INVALID_CASHBACK_PERCENT", http.StatusBadRequest},
+		{cashbackdomain.ErrInvalidPercentage, "CASHBACK_PERCENT_OUT_OF_RANGE", http.StatusUnprocessableEntity},
+		{ratelimit.ErrRateLimited, "RATE_LIMITED", http.StatusTooManyRequests},
+		{errorhandler.ErrInvalidUUID, "INVALID_UUID", http.StatusBadRequest},
+	} {
+		t.Run(tc.code, func(t *testing.T) {
+			// Usecases wrap their errors with context.
+			entry, ok := errorhandler.Lookup(fmt.Errorf("execute: %w", tc.err))
+			if !ok || entry.Code != tc.code || entry.Status != tc.status {
+				t.Fatalf("entry = %+v, want %s %d", entry, tc.code, tc.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"go.uber.org/fx"
+)
+
+// PurgeJob periodically deletes expired idempotency keys. Expired keys are
+// already ignored on lookup; purging only keeps the table small.
+type PurgeJob struct {
+	store    *Store
+	interval time.Duration
+	log      *slog.Logger
+	done     chan struct{}
+}
+
+func NewPurgeJob(store *Store, cfg config.Idempotency, log *slog.Logger) *PurgeJob {
+	return &PurgeJob{
+		store:    store,
+		interval: cfg.PurgeInterval,
+		log:      log,
+		done:     make(chan struct{}),
+	}
+}
+
+func (j *PurgeJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *PurgeJob) Stop() {
+	close(j.done)
+}
+
+func (j *PurgeJob) run(ctx context.Context) {
+	purged, err := j.store.Purge(ctx)
+	if err != nil {
+		j.log.ErrorContext(ctx, "idempotency key purge failed", "error", err)
+		return
+	}
+	if purged > 0 {
+		j.log.InfoContext(ctx, "purged expired idempotency keys", "count", purged)
+	}
+}
+
+func StartPurgeJob(lc fx.Lifecycle, job *PurgeJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go job.Start(ctx)
+			job.log.Info("idempotency key purge job started")
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			job.Stop()
+			job.log.Info("idempotency key purge job stopped")
+			return nil
+		},
+	})
+}
@@ -0,0 +1,131 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ratelimit"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	// Header is the request header carrying the idempotency key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader marks responses replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+var (
+	ErrKeyTooLong = errors.New("Idempotency-Key must be at most 255 characters")
+	ErrKeyReused  = errors.New("Idempotency-Key was already used with a different request")
+)
+
+// Middleware executes authenticated POST requests carrying an Idempotency-Key
+// at most once per caller and key. Retries with the same method, path and
+// body get the first response replayed; reusing a key for a different
+// request returns 409. Server errors are not stored, so such requests can be
+// retried with the same key.
+func Middleware(store *Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			principal, authenticated := auth.FromContext(r.Context())
+			if r.Method != http.MethodPost || key == "" || !authenticated {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				errorhandler.Render(w, r, ErrKeyTooLong)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := fingerprint(r, body)
+
+			lock, stored, err := store.Acquire(r.Context(), principal.Subject, key, fingerprint)
+			switch {
+			case errors.Is(err, ErrInProgress):
+				w.Header().Set("Retry-After", "1")
+				errorhandler.Render(w, r, err)
+				return
+			case err != nil:
+				errorhandler.Render(w, r, err)
+				return
+			case stored != nil:
+				if stored.Fingerprint != fingerprint {
+					errorhandler.Render(w, r, ErrKeyReused)
+					return
+				}
+				replay(w, stored)
+				return
+			}
+			defer lock.Release()
+
+			var recorded bytes.Buffer
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&recorded)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError {
+				return
+			}
+			if err := lock.Complete(status, storedHeader(w.Header()), recorded.Bytes()); err != nil {
+				logger.ErrorContext(r.Context(), "failed to store response for idempotency key", "error", err)
+			}
+		})
+	}
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// perRequestHeaders describe the request that was served rather than its
+// response, so they are not stored: replays get their own.
+var perRequestHeaders = []string{
+	chimiddleware.RequestIDHeader,
+	ratelimit.LimitHeader,
+	ratelimit.RemainingHeader,
+	ratelimit.ResetHeader,
+	ratelimit.PolicyHeader,
+}
+
+func storedHeader(header http.Header) http.Header {
+	stored := header.Clone()
+	for _, name := range perRequestHeaders {
+		stored.Del(name)
+	}
+	return stored
+}
+
+func replay(w http.ResponseWriter, stored *Response) {
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(stored.StatusCode)
+	_, _ = w.Write(stored.Body)
+}
@@ -0,0 +1,312 @@
+package idempotency_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database/databasetest"
+	"github.com/cashback-platform/services/cashback-service-api/internal/errorcodes"
+	"github.com/cashback-platform/services/cashback-service-api/internal/idempotency"
+)
+
+// The store lives in Postgres, so most of these tests need TEST_DATABASE_URL
+// and are skipped without it.
+
+func TestMain(m *testing.M) {
+	errorcodes.Register()
+	os.Exit(m.Run())
+}
+
+var partner = auth.Principal{Subject: "acme", Role: auth.RolePartner}
+
+// server counts the requests it executes and answers with their number.
+type server struct {
+	executed atomic.Int32
+	status   int
+	delay    time.Duration
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	n := s.executed.Add(1)
+	time.Sleep(s.delay)
+	w.Header().Set("Location", "/purchases/"+strconv.Itoa(int(n)))
+	w.WriteHeader(s.status)
+	_, _ = w.Write([]byte(`{"n":` + strconv.Itoa(int(n)) + `}`))
+}
+
+func newHandler(t *testing.T, s *server, cfg config.Idempotency) http.Handler {
+	t.Helper()
+	store := idempotency.NewStore(databasetest.New(t), cfg)
+	return idempotency.Middleware(store)(s)
+}
+
+var defaults = config.Idempotency{TTL: time.Hour, LockTimeout: 5 * time.Second, Lease: time.Minute}
+
+func post(handler http.Handler, principal *auth.Principal, key, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotency.Header, key)
+	}
+	if principal != nil {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), *principal))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRetriesReplayTheFirstResponse(t *testing.T) {
+	s := &server{status: http.StatusCreated}
+	handler := newHandler(t, s, defaults)
+
+	first := post(handler, &partner, "key-1", "/purchases", `{"amount":10}`)
+	again := post(handler, &partner, "key-1", "/purchases", `{"amount":10}`)
+
+	if s.executed.Load() != 1 {
+		t.Fatalf("executed %d requests, want 1", s.executed.Load())
+	}
+	if again.Code != http.StatusCreated || again.Body.String() != first.Body.String() ||
+		again.Header().Get("Location") != first.Header().Get("Location") {
+		t.Fatalf("replayed %d %s, want %d %s", again.Code, again.Body, first.Code, first.Body)
+	}
+	if first.Header().Get(idempotency.ReplayedHeader) != "" || again.Header().Get(idempotency.ReplayedHeader) != "true" {
+		t.Fatal("only the replay must be marked as replayed")
+	}
+}
+
+func TestReusingAKeyForAnotherRequestConflicts(t *testing.T) {
+	for _, tc := range []struct {
+		name, path, body string
+	}{
+		{"other body", "/purchases", `{"amount":20}`},
+		{"other path", "/purchases?dry_run=true", `{"amount":10}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{status: http.StatusCreated}
+			handler := newHandler(t, s, defaults)
+
+			post(handler, &partner, "key-1", "/purchases", `{"amount":10}`)
+			rec := post(handler, &partner, "key-1", tc.path, tc.body)
+			if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+				t.Fatalf("status %d %s, want 409 IDEMPOTENCY_KEY_REUSED", rec.Code, rec.Body)
+			}
+			if s.executed.Load() != 1 {
+				t.Fatalf("executed %d requests, want 1", s.executed.Load())
+			}
+		})
+	}
+}
+
+func TestKeysAreScopedToTheCaller(t *testing.T) {
+	s := &server{status: http.StatusCreated}
+	handler := newHandler(t, s, defaults)
+	other := auth.Principal{Subject: "globex", Role: auth.RolePartner}
+
+	post(handler, &partner, "key-1", "/purchases", `{"amount":10}`)
+	rec := post(handler, &other, "key-1", "/purchases", `{"amount":10}`)
+	if rec.Header().Get(idempotency.ReplayedHeader) != "" || s.executed.Load() != 2 {
+		t.Fatalf("another caller got a replay; executed %d requests", s.executed.Load())
+	}
+}
+
+func TestOnlyResponsesBelow500AreStored(t *testing.T) {
+	for _, tc := range []struct {
+		status   int
+		executed int32
+	}{
+		{http.StatusUnprocessableEntity, 1},
+		{http.StatusInternalServerError, 2},
+		{http.StatusServiceUnavailable, 2},
+	} {
+		t.Run(strconv.Itoa(tc.status), func(t *testing.T) {
+			s := &server{status: tc.status}
+			handler := newHandler(t, s, defaults)
+
+			for range 2 {
+				post(handler, &partner, "key-1", "/purchases", `{}`)
+			}
+			if s.executed.Load() != tc.executed {
+				t.Fatalf("executed %d requests, want %d", s.executed.Load(), tc.executed)
+			}
+		})
+	}
+}
+
+func TestExpiredKeysExecuteAgain(t *testing.T) {
+	s := &server{status: http.StatusCreated}
+	handler := newHandler(t, s, config.Idempotency{TTL: time.Millisecond, LockTimeout: time.Second, Lease: time.Minute})
+
+	post(handler, &partner, "key-1", "/purchases", `{}`)
+	time.Sleep(10 * time.Millisecond)
+	rec := post(handler, &partner, "key-1", "/purchases", `{"amount":20}`)
+	if rec.Code != http.StatusCreated || s.executed.Load() != 2 {
+		t.Fatalf("status %d after %d executions, want a new 201", rec.Code, s.executed.Load())
+	}
+}
+
+func TestConcurrentRetriesExecuteOnce(t *testing.T) {
+	s := &server{status: http.StatusCreated, delay: 50 * time.Millisecond}
+	handler := newHandler(t, s, defaults)
+
+	const requests = 5
+	bodies := make(chan string, requests)
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bodies <- post(handler, &partner, "key-1", "/purchases", `{}`).Body.String()
+		}()
+	}
+	wg.Wait()
+	close(bodies)
+
+	for body := range bodies {
+		if body != `{"n":1}` {
+			t.Fatalf("response %s, want the first execution's", body)
+		}
+	}
+	if s.executed.Load() != 1 {
+		t.Fatalf("executed %d requests, want 1", s.executed.Load())
+	}
+}
+
+func TestConcurrentRetriesTimeOut(t *testing.T) {
+	s := &server{status: http.StatusCreated, delay: 500 * time.Millisecond}
+	handler := newHandler(t, s, config.Idempotency{TTL: time.Hour, LockTimeout: 50 * time.Millisecond, Lease: time.Minute})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		post(handler, &partner, "key-1", "/purchases", `{}`)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	rec := post(handler, &partner, "key-1", "/purchases", `{}`)
+	<-done
+	if rec.Code != http.StatusConflict || rec.Header().Get("Retry-After") != "1" {
+		t.Fatalf("status %d, Retry-After %q, want 409 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestPerRequestHeadersAreNotReplayed(t *testing.T) {
+	s := &server{status: http.StatusCreated}
+	handler := newHandler(t, s, defaults)
+
+	post(withHeaders(handler, map[string]string{
+		"X-Request-Id":        "req-1",
+		"RateLimit-Remaining": "9",
+	}), &partner, "key-1", "/purchases", `{}`)
+	again := post(handler, &partner, "key-1", "/purchases", `{}`)
+
+	if again.Header().Get("Location") == "" {
+		t.Fatal("the response's own headers must be replayed")
+	}
+	for _, name := range []string{"X-Request-Id", "RateLimit-Remaining"} {
+		if value := again.Header().Get(name); value != "" {
+			t.Fatalf("%s: %q was replayed", name, value)
+		}
+	}
+}
+
+func TestAbandonedKeysAreClaimedAgain(t *testing.T) {
+	ctx := context.Background()
+	store := idempotency.NewStore(databasetest.New(t),
+		config.Idempotency{TTL: time.Hour, LockTimeout: time.Second, Lease: 50 * time.Millisecond})
+
+	// The first holder never completes, as if its process died.
+	if lock, _, err := store.Acquire(ctx, "acme", "key-1", "f"); err != nil || lock == nil {
+		t.Fatalf("lock %v, error %v", lock, err)
+	}
+	// The retry waits for the lease to run out, well within the lock timeout.
+	lock, stored, err := store.Acquire(ctx, "acme", "key-1", "f")
+	if err != nil || lock == nil || stored != nil {
+		t.Fatalf("lock %v, stored %v, error %v; want the key claimed again once the lease ran out", lock, stored, err)
+	}
+}
+
+func TestHandlersCanUseTheDatabaseWhileHoldingAKey(t *testing.T) {
+	db := databasetest.New(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With one connection, a transaction held across the handler would
+	// leave it none.
+	sqlDB.SetMaxOpenConns(1)
+
+	handler := idempotency.Middleware(idempotency.NewStore(db, defaults))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+			defer cancel()
+			if err := db.WithContext(ctx).Exec("SELECT 1").Error; err != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	if rec := post(handler, &partner, "key-1", "/purchases", `{}`); rec.Code != http.StatusCreated {
+		t.Fatalf("status %d, want 201", rec.Code)
+	}
+}
+
+func TestRequestsWithoutAKeyArePassedThrough(t *testing.T) {
+	// None of these reach the store.
+	s := &server{status: http.StatusCreated}
+	handler := idempotency.Middleware(nil)(s)
+
+	for _, tc := range []struct {
+		name      string
+		method    string
+		key       string
+		principal *auth.Principal
+	}{
+		{"no key", http.MethodPost, "", &partner},
+		{"anonymous", http.MethodPost, "key-1", nil},
+		{"not a POST", http.MethodPut, "key-1", &partner},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := s.executed.Load()
+			for range 2 {
+				req := httptest.NewRequest(tc.method, "/purchases", strings.NewReader(`{}`))
+				if tc.key != "" {
+					req.Header.Set(idempotency.Header, tc.key)
+				}
+				if tc.principal != nil {
+					req = req.WithContext(auth.WithPrincipal(req.Context(), *tc.principal))
+				}
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+			}
+			if s.executed.Load()-before != 2 {
+				t.Fatalf("executed %d requests, want 2", s.executed.Load()-before)
+			}
+		})
+	}
+
+	rec := post(handler, &partner, strings.Repeat("k", 256), "/purchases", `{}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "IDEMPOTENCY_KEY_TOO_LONG") {
+		t.Fatalf("status %d %s, want 400 IDEMPOTENCY_KEY_TOO_LONG", rec.Code, rec.Body)
+	}
+}
+
+// withHeaders sets headers on every response before handler serves it, as the
+// middleware in front of the idempotency one does.
+func withHeaders(handler http.Handler, headers map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, value := range headers {
+			w.Header().Set(name, value)
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,24 @@
+package idempotency
+
+import (
+	"time"
+)
+
+// idempotencyKeyModel stores the response of a request made with an
+// Idempotency-Key. Keys are scoped to the authenticated caller, so two
+// partners can never replay each other's responses. A key without a status
+// code is still in progress.
+type idempotencyKeyModel struct {
+	Scope       string `gorm:"primaryKey"`
+	Key         string `gorm:"primaryKey"`
+	Fingerprint string `gorm:"not null"`
+	StatusCode  int    `gorm:"not null;default:0"`
+	Header      []byte `gorm:"type:jsonb"`
+	Body        []byte
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+func (idempotencyKeyModel) TableName() string {
+	return "idempotency_keys"
+}
@@ -0,0 +1,205 @@
+// Package idempotency makes POST requests safe to retry. A request sent with
+// an Idempotency-Key is executed once; retries with the same key and body get
+// the stored response back instead of being executed again.
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pollInterval is how often a request waiting on a key in progress checks
+// whether it finished.
+const pollInterval = 20 * time.Millisecond
+
+// ErrInProgress is returned when another request holding the same key did not
+// finish within the lock timeout.
+var ErrInProgress = errors.New("a request with this Idempotency-Key is still being processed")
+
+type (
+	// Store keeps idempotency keys and their responses in Postgres.
+	Store struct {
+		db          *gorm.DB
+		ttl         time.Duration
+		lockTimeout time.Duration
+		lease       time.Duration
+	}
+
+	// Response is a response recorded for an idempotency key.
+	Response struct {
+		Fingerprint string
+		StatusCode  int
+		Header      http.Header
+		Body        []byte
+	}
+
+	// Lock is held by the request executing under an idempotency key. The key
+	// is stored in progress, with no status code, until Complete records the
+	// response; concurrent requests with the same key wait for it meanwhile.
+	Lock struct {
+		db        *gorm.DB
+		ttl       time.Duration
+		scope     string
+		key       string
+		claimedAt time.Time
+		done      bool
+	}
+)
+
+func NewStore(db *gorm.DB, cfg config.Idempotency) *Store {
+	return &Store{
+		db:          db,
+		ttl:         cfg.TTL,
+		lockTimeout: cfg.LockTimeout,
+		lease:       cfg.Lease,
+	}
+}
+
+// Acquire claims key for the caller scope. It returns a Lock when the key is
+// new or expired, and the stored Response when it was used before. Requests
+// racing on the same key wait up to the lock timeout for the first to finish
+// and fail with ErrInProgress after that. No transaction is held meanwhile:
+// the claim is committed as soon as it is made.
+func (s *Store) Acquire(ctx context.Context, scope, key, fingerprint string) (*Lock, *Response, error) {
+	deadline := time.Now().Add(s.lockTimeout)
+	for {
+		lock, response, err := s.claim(ctx, scope, key, fingerprint)
+		if lock != nil || response != nil || err != nil {
+			return lock, response, err
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, nil, ErrInProgress
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// claim stores key in progress unless it is held: a key is free once it
+// expired, which for a key in progress is when its lease ran out. It returns
+// neither a Lock nor a Response while the key is in progress.
+func (s *Store) claim(ctx context.Context, scope, key, fingerprint string) (*Lock, *Response, error) {
+	// Postgres keeps microseconds; the claim time identifies the holder.
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	model := idempotencyKeyModel{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.lease),
+	}
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "scope"}, {Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"fingerprint": fingerprint,
+				"status_code": 0,
+				"header":      nil,
+				"body":        nil,
+				"created_at":  model.CreatedAt,
+				"expires_at":  model.ExpiresAt,
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "idempotency_keys.expires_at <= ?", Vars: []any{now}},
+			}},
+		}).
+		Create(&model)
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return &Lock{
+			// The key must be finished even if the request was cancelled.
+			db:        s.db.WithContext(context.WithoutCancel(ctx)),
+			ttl:       s.ttl,
+			scope:     scope,
+			key:       key,
+			claimedAt: now,
+		}, nil, nil
+	}
+
+	var stored idempotencyKeyModel
+	err := s.db.WithContext(ctx).Where("scope = ? AND key = ?", scope, key).First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && stored.StatusCode == 0) {
+		// Purged since, or still in progress: try again.
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := toResponse(&stored)
+	return nil, response, err
+}
+
+// Complete stores the response of the request and keeps it for the TTL. It
+// does nothing if the lease ran out and another request claimed the key.
+func (l *Lock) Complete(statusCode int, header http.Header, body []byte) error {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	if err := l.held().
+		Updates(map[string]any{
+			"status_code": statusCode,
+			"header":      headerJSON,
+			"body":        body,
+			"expires_at":  time.Now().Add(l.ttl),
+		}).Error; err != nil {
+		return err
+	}
+
+	l.done = true
+	return nil
+}
+
+// Release drops the key without storing a response, so the request can be
+// retried. It does nothing after Complete.
+func (l *Lock) Release() {
+	if l.done {
+		return
+	}
+	l.done = true
+	l.held().Delete(&idempotencyKeyModel{})
+}
+
+// held selects the key while this lock still holds it.
+func (l *Lock) held() *gorm.DB {
+	return l.db.Model(&idempotencyKeyModel{}).
+		Where("scope = ? AND key = ? AND status_code = 0 AND created_at = ?", l.scope, l.key, l.claimedAt)
+}
+
+// Purge deletes expired keys and returns how many were removed.
+func (s *Store) Purge(ctx context.Context) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("expires_at <= ?", time.Now()).
+		Delete(&idempotencyKeyModel{})
+	return result.RowsAffected, result.Error
+}
+
+func toResponse(m *idempotencyKeyModel) (*Response, error) {
+	var header http.Header
+	if len(m.Header) > 0 {
+		if err := json.Unmarshal(m.Header, &header); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Response{
+		Fingerprint: m.Fingerprint,
+		StatusCode:  m.StatusCode,
+		Header:      header,
+		Body:        m.Body,
+	}, nil
+}
@@ -1,29 +1,116 @@
 package grpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
+	tokenpb "github.com/cashback-platform/proto/token"
 	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-type BlockchainAdapterClient struct {
-	conn *grpc.ClientConn
-}
+// ErrTransferRejected is returned when the blockchain adapter refuses a transfer.
+var ErrTransferRejected = errors.New("transfer rejected by blockchain adapter")
+
+type (
+	// TransferResult represents the result of a custodial transfer
+	TransferResult struct {
+		Success         bool
+		TransactionHash string
+		FromAddress     string
+		ErrorCode       string
+		ErrorMessage    string
+	}
 
-func NewBlockchainAdapterClient(cfg *config.Config) (*BlockchainAdapterClient, error) {
+	BlockchainAdapterClient struct {
+		conn   *grpc.ClientConn
+		client tokenpb.TokenServiceClient
+		log    *slog.Logger
+	}
+)
+
+func NewBlockchainAdapterClient(cfg config.GRPC, m *metrics.GRPCClient, log *slog.Logger) (*BlockchainAdapterClient, error) {
 	conn, err := grpc.Dial(
-		cfg.GRPC.BlockchainAdapterAddress,
+		cfg.BlockchainAdapterAddress,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(m.UnaryInterceptor()),
+		// Sends the trace context in the call metadata.
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to blockchain adapter: %w", err)
 	}
 
-	log.Printf("Connected to blockchain adapter at %s", cfg.GRPC.BlockchainAdapterAddress)
-	return &BlockchainAdapterClient{conn: conn}, nil
+	log.Info("connected to blockchain adapter", "address", cfg.BlockchainAdapterAddress)
+	return &BlockchainAdapterClient{
+		conn:   conn,
+		client: tokenpb.NewTokenServiceClient(conn),
+		log:    log,
+	}, nil
+}
+
+// DeriveCustodialAddress returns the custodial wallet the adapter holds for ownerID.
+func (c *BlockchainAdapterClient) DeriveCustodialAddress(ctx context.Context, ownerID string) (string, error) {
+	c.log.DebugContext(ctx, "deriving custodial address", "owner_id", ownerID)
+	resp, err := c.client.DeriveCustodialAddress(ctx, &tokenpb.DeriveCustodialAddressRequest{OwnerId: ownerID})
+	if err != nil {
+		return "", fmt.Errorf("derive custodial address: %w", err)
+	}
+	return resp.GetWalletAddress(), nil
+}
+
+// GetBalance returns the token balance of walletAddress in wei.
+func (c *BlockchainAdapterClient) GetBalance(ctx context.Context, walletAddress string) (string, error) {
+	c.log.DebugContext(ctx, "getting balance", "wallet", walletAddress)
+	resp, err := c.client.GetBalance(ctx, &tokenpb.GetBalanceRequest{WalletAddress: walletAddress})
+	if err != nil {
+		return "", fmt.Errorf("get balance: %w", err)
+	}
+	return resp.GetBalance(), nil
+}
+
+// TransferToken moves tokenAmount out of the custodial wallet of ownerID and
+// returns the transaction hash. Rejected transfers return ErrTransferRejected.
+func (c *BlockchainAdapterClient) TransferToken(ctx context.Context, idempotencyKey, ownerID, toAddress, tokenAmount string) (string, error) {
+	result, err := c.transferToken(ctx, idempotencyKey, ownerID, toAddress, tokenAmount)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("%w: %s: %s", ErrTransferRejected, result.ErrorCode, result.ErrorMessage)
+	}
+	return result.TransactionHash, nil
+}
+
+func (c *BlockchainAdapterClient) transferToken(ctx context.Context, idempotencyKey, ownerID, toAddress, tokenAmount string) (*TransferResult, error) {
+	c.log.InfoContext(ctx, "transferring token",
+		"idempotency_key", idempotencyKey,
+		"owner_id", ownerID,
+		"to", toAddress,
+		"amount", tokenAmount,
+	)
+	resp, err := c.client.TransferToken(ctx, &tokenpb.TransferTokenRequest{
+		IdempotencyKey: idempotencyKey,
+		OwnerId:        ownerID,
+		ToAddress:      toAddress,
+		TokenAmount:    tokenAmount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("transfer token: %w", err)
+	}
+
+	return &TransferResult{
+		Success:         resp.GetSuccess(),
+		TransactionHash: resp.GetTransactionHash(),
+		FromAddress:     resp.GetFromAddress(),
+		ErrorCode:       resp.GetError().GetCode(),
+		ErrorMessage:    resp.GetError().GetMessage(),
+	}, nil
 }
 
 func (c *BlockchainAdapterClient) Connection() *grpc.ClientConn {
@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// CheckChannel fails unless the channel to the blockchain adapter is ready.
+// An idle channel is asked to connect, and the check waits for the outcome.
+func (c *BlockchainAdapterClient) CheckChannel(ctx context.Context) error {
+	c.conn.Connect()
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return fmt.Errorf("channel is %s", state)
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("channel is %s: %w", state, ctx.Err())
+		}
+	}
+}
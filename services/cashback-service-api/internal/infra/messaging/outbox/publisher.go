@@ -3,38 +3,56 @@ package outbox
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"time"
 
+	"github.com/cashback-platform/pkg/logger"
 	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging/outbox/repository"
 	"github.com/cashback-platform/services/cashback-service-api/internal/infra/nats"
+	"github.com/cashback-platform/services/cashback-service-api/internal/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 )
 
+var tracer = otel.Tracer("github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging/outbox")
+
 // OutboxPublisher implements the EventPublisher interface using the Outbox Pattern
 // It persists events before publishing to ensure reliable delivery
 type OutboxPublisher struct {
 	outboxRepo *repository.Repository
 	natsClient *nats.NATSClient
+	metrics    *metrics.Outbox
+	log        *slog.Logger
 	done       chan struct{}
 }
 
-func NewOutboxPublisher(outboxRepo *repository.Repository, natsClient *nats.NATSClient) *OutboxPublisher {
+func NewOutboxPublisher(outboxRepo *repository.Repository, natsClient *nats.NATSClient, m *metrics.Outbox, log *slog.Logger) *OutboxPublisher {
 	return &OutboxPublisher{
 		outboxRepo: outboxRepo,
 		natsClient: natsClient,
+		metrics:    m,
+		log:        log,
 		done:       make(chan struct{}),
 	}
 }
 
-// Publish adds an event to the outbox for async publishing
+// Publish adds an event to the outbox for async publishing, together with the
+// trace context of ctx.
 // Implements messaging.EventPublisher interface
 func (p *OutboxPublisher) Publish(ctx context.Context, eventType string, payload any) error {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
-	return p.outboxRepo.Create(ctx, eventType, payloadBytes)
+
+	traceContext := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, traceContext)
+	return p.outboxRepo.Create(ctx, eventType, payloadBytes, traceContext)
 }
 
 func (p *OutboxPublisher) Start(ctx context.Context) {
@@ -60,7 +78,7 @@ func (p *OutboxPublisher) Stop() {
 func (p *OutboxPublisher) processEvents(ctx context.Context) {
 	events, err := p.outboxRepo.Pending(ctx, 100)
 	if err != nil {
-		log.Printf("Error fetching pending events: %v", err)
+		p.log.ErrorContext(ctx, "failed to fetch pending outbox events", "error", err)
 		return
 	}
 
@@ -72,26 +90,45 @@ func (p *OutboxPublisher) processEvents(ctx context.Context) {
 func (p *OutboxPublisher) publishEvent(ctx context.Context, event repository.OutboxEvent) {
 	subject := event.EventType
 
-	if err := p.natsClient.Publish(subject, event.Payload); err != nil {
+	// The span continues the trace of the request that wrote the event.
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(event.TraceContext))
+	ctx = logger.WithEventID(ctx, event.ID.String())
+	ctx, span := tracer.Start(ctx, subject+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingDestinationName(subject),
+			attribute.String("outbox.event_id", event.ID.String()),
+			attribute.Int("outbox.retry_count", event.RetryCount),
+		),
+	)
+	defer span.End()
+
+	if err := p.natsClient.Publish(ctx, subject, event.ID.String(), event.Payload); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "publish failed")
 		p.handlePublishError(ctx, event, err)
 		return
 	}
 
+	p.metrics.Published(event.EventType, event.CreatedAt)
 	if err := p.outboxRepo.MarkAsPublished(ctx, event.ID); err != nil {
-		log.Printf("Error marking event %s as published: %v", event.ID, err)
+		p.log.ErrorContext(ctx, "failed to mark outbox event as published", "error", err)
 	}
 }
 
 func (p *OutboxPublisher) handlePublishError(ctx context.Context, event repository.OutboxEvent, publishErr error) {
-	log.Printf("Error publishing event %s: %v", event.ID, publishErr)
+	p.log.WarnContext(ctx, "failed to publish outbox event", "event_type", event.EventType, "retry_count", event.RetryCount, "error", publishErr)
 
 	if err := p.outboxRepo.IncrementRetry(ctx, event.ID); err != nil {
-		log.Printf("Error incrementing retry for event %s: %v", event.ID, err)
+		p.log.ErrorContext(ctx, "failed to count outbox publish retry", "error", err)
 	}
 
-	if event.RetryCount >= event.MaxRetries-1 {
+	gaveUp := event.RetryCount >= event.MaxRetries-1
+	p.metrics.PublishFailed(event.EventType, gaveUp)
+	if gaveUp {
 		if err := p.outboxRepo.MarkAsFailed(ctx, event.ID, publishErr.Error()); err != nil {
-			log.Printf("Error marking event %s as failed: %v", event.ID, err)
+			p.log.ErrorContext(ctx, "failed to mark outbox event as failed", "error", err)
 		}
 	}
 }
@@ -102,13 +139,13 @@ func StartOutboxPublisher(lc fx.Lifecycle, publisher *OutboxPublisher) {
 	lc.Append(fx.Hook{
 		OnStart: func(_ context.Context) error {
 			go publisher.Start(ctx)
-			log.Println("Outbox publisher started")
+			publisher.log.Info("outbox publisher started")
 			return nil
 		},
 		OnStop: func(_ context.Context) error {
 			cancel()
 			publisher.Stop()
-			log.Println("Outbox publisher stopped")
+			publisher.log.Info("outbox publisher stopped")
 			return nil
 		},
 	})
@@ -1,29 +1,42 @@
 package repository
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type outboxEvent struct {
-	ID         uuid.UUID
-	EventType  string
-	Payload    []byte
-	RetryCount int
-	MaxRetries int
-	Published  bool
-	Failed     bool
-	Error      string
+	ID           uuid.UUID
+	EventType    string
+	Payload      []byte
+	TraceContext map[string]string
+	RetryCount   int
+	MaxRetries   int
+	Published    bool
+	Failed       bool
+	Error        string
+	CreatedAt    time.Time
 }
 
 func toDomain(m *outboxModel) *outboxEvent {
+	// A trace context that does not parse only costs the link to the trace.
+	var traceContext map[string]string
+	if len(m.TraceContext) > 0 {
+		_ = json.Unmarshal(m.TraceContext, &traceContext)
+	}
+
 	return &outboxEvent{
-		ID:         m.ID,
-		EventType:  m.EventType,
-		Payload:    m.Payload,
-		RetryCount: m.RetryCount,
-		MaxRetries: m.MaxRetries,
-		Published:  m.Published,
-		Failed:     m.Failed,
-		Error:      m.Error,
+		ID:           m.ID,
+		EventType:    m.EventType,
+		Payload:      m.Payload,
+		TraceContext: traceContext,
+		RetryCount:   m.RetryCount,
+		MaxRetries:   m.MaxRetries,
+		Published:    m.Published,
+		Failed:       m.Failed,
+		Error:        m.Error,
+		CreatedAt:    m.CreatedAt,
 	}
 }
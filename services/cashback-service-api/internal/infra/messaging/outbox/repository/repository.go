@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -13,14 +16,18 @@ type (
 	}
 
 	OutboxEvent struct {
-		ID         uuid.UUID
-		EventType  string
-		Payload    []byte
-		RetryCount int
-		MaxRetries int
-		Published  bool
-		Failed     bool
-		Error      string
+		ID        uuid.UUID
+		EventType string
+		Payload   []byte
+		// TraceContext carries the trace of the request that wrote the
+		// event on to the published message.
+		TraceContext map[string]string
+		RetryCount   int
+		MaxRetries   int
+		Published    bool
+		Failed       bool
+		Error        string
+		CreatedAt    time.Time
 	}
 )
 
@@ -28,17 +35,28 @@ func New(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
-func (r *Repository) Create(ctx context.Context, eventType string, payload []byte) error {
+// Create stores an event for publishing, within the transaction of ctx if it
+// has one.
+func (r *Repository) Create(ctx context.Context, eventType string, payload []byte, traceContext map[string]string) error {
+	var traceJSON []byte
+	if len(traceContext) > 0 {
+		var err error
+		if traceJSON, err = json.Marshal(traceContext); err != nil {
+			return err
+		}
+	}
+
 	event := outboxModel{
-		ID:         uuid.New(),
-		EventType:  eventType,
-		Payload:    payload,
-		RetryCount: 0,
-		MaxRetries: 3,
-		Published:  false,
-		Failed:     false,
+		ID:           uuid.New(),
+		EventType:    eventType,
+		Payload:      payload,
+		TraceContext: traceJSON,
+		RetryCount:   0,
+		MaxRetries:   3,
+		Published:    false,
+		Failed:       false,
 	}
-	return r.db.WithContext(ctx).Create(&event).Error
+	return database.Conn(ctx, r.db).Create(&event).Error
 }
 
 func (r *Repository) Pending(ctx context.Context, limit int) ([]OutboxEvent, error) {
@@ -54,19 +72,31 @@ func (r *Repository) Pending(ctx context.Context, limit int) ([]OutboxEvent, err
 	for i, m := range models {
 		d := toDomain(&m)
 		events[i] = OutboxEvent{
-			ID:         d.ID,
-			EventType:  d.EventType,
-			Payload:    d.Payload,
-			RetryCount: d.RetryCount,
-			MaxRetries: d.MaxRetries,
-			Published:  d.Published,
-			Failed:     d.Failed,
-			Error:      d.Error,
+			ID:           d.ID,
+			EventType:    d.EventType,
+			Payload:      d.Payload,
+			TraceContext: d.TraceContext,
+			RetryCount:   d.RetryCount,
+			MaxRetries:   d.MaxRetries,
+			Published:    d.Published,
+			Failed:       d.Failed,
+			Error:        d.Error,
+			CreatedAt:    d.CreatedAt,
 		}
 	}
 	return events, nil
 }
 
+// CountPending returns how many events are waiting to be published.
+func (r *Repository) CountPending(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&outboxModel{}).
+		Where("published = ? AND failed = ?", false, false).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *Repository) IncrementRetry(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).
 		Model(&outboxModel{}).
@@ -1,12 +1,15 @@
 package nats
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/tracing"
 	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
 )
 
 type NATSClient struct {
@@ -14,8 +17,8 @@ type NATSClient struct {
 	js   nats.JetStreamContext
 }
 
-func NewNATSClient(cfg *config.Config) (*NATSClient, error) {
-	conn, err := nats.Connect(cfg.NATS.URL)
+func NewNATSClient(cfg config.NATS, log *slog.Logger) (*NATSClient, error) {
+	conn, err := nats.Connect(cfg.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
 	}
@@ -27,37 +30,46 @@ func NewNATSClient(cfg *config.Config) (*NATSClient, error) {
 	}
 
 	// Create streams if they don't exist
-	if err := createStreams(js); err != nil {
+	if err := createStreams(js, log); err != nil {
 		conn.Close()
 		return nil, err
 	}
 
-	log.Println("NATS connected successfully")
+	log.Info("NATS connected")
 	return &NATSClient{
 		conn: conn,
 		js:   js,
 	}, nil
 }
 
-func createStreams(js nats.JetStreamContext) error {
-	streams := []struct {
-		name     string
-		subjects []string
-	}{
-		{
-			name:     "PURCHASE_EVENTS",
-			subjects: []string{"purchase.>"},
-		},
-		{
-			name:     "CASHBACK_EVENTS",
-			subjects: []string{"cashback.>"},
-		},
-		{
-			name:     "TOKEN_EVENTS",
-			subjects: []string{"token.>"},
-		},
-	}
+// streams are the JetStream streams the service publishes to.
+var streams = []struct {
+	name     string
+	subjects []string
+}{
+	{
+		name:     "PURCHASE_EVENTS",
+		subjects: []string{"purchase.>"},
+	},
+	{
+		name:     "CASHBACK_EVENTS",
+		subjects: []string{"cashback.>"},
+	},
+	{
+		name:     "USER_EVENTS",
+		subjects: []string{"user.>"},
+	},
+	{
+		name:     "TOKEN_EVENTS",
+		subjects: []string{"token.>"},
+	},
+	{
+		name:     "RECONCILIATION_EVENTS",
+		subjects: []string{"reconciliation.>"},
+	},
+}
 
+func createStreams(js nats.JetStreamContext, log *slog.Logger) error {
 	for _, s := range streams {
 		_, err := js.StreamInfo(s.name)
 		if errors.Is(err, nats.ErrStreamNotFound) {
@@ -72,7 +84,7 @@ func createStreams(js nats.JetStreamContext) error {
 			if err != nil {
 				return fmt.Errorf("failed to create stream %s: %w", s.name, err)
 			}
-			log.Printf("Stream %s created", s.name)
+			log.Info("stream created", "stream", s.name)
 		} else if err != nil {
 			return fmt.Errorf("failed to get stream info for %s: %w", s.name, err)
 		}
@@ -81,8 +93,17 @@ func createStreams(js nats.JetStreamContext) error {
 	return nil
 }
 
-func (c *NATSClient) Publish(subject string, data []byte) error {
-	_, err := c.js.Publish(subject, data)
+// Publish sends data to subject with the trace context of ctx in the message
+// headers. msgID goes out as Nats-Msg-Id: JetStream drops a republish of the
+// same event within its duplicate window, and consumers log the ID as the
+// event_id of whatever they do with the message.
+func (c *NATSClient) Publish(ctx context.Context, subject, msgID string, data []byte) error {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	msg.Header.Set(nats.MsgIdHdr, msgID)
+	otel.GetTextMapPropagator().Inject(ctx, tracing.HeaderCarrier(msg.Header))
+
+	_, err := c.js.PublishMsg(msg)
 	return err
 }
 
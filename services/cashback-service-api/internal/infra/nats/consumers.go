@@ -0,0 +1,73 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// ConsumerState is where a durable consumer stands in its stream.
+type ConsumerState struct {
+	Stream         string     `json:"stream"`
+	Name           string     `json:"name"`
+	FilterSubject  string     `json:"filter_subject,omitempty"`
+	Delivered      uint64     `json:"delivered_seq"`
+	AckFloor       uint64     `json:"ack_floor_seq"`
+	NumPending     uint64     `json:"num_pending"`
+	NumAckPending  int        `json:"num_ack_pending"`
+	NumRedelivered int        `json:"num_redelivered"`
+	NumWaiting     int        `json:"num_waiting"`
+	LastActive     *time.Time `json:"last_active,omitempty"`
+}
+
+// StreamNames returns the streams the service publishes to.
+func StreamNames() []string {
+	names := make([]string, len(streams))
+	for i, s := range streams {
+		names[i] = s.name
+	}
+	return names
+}
+
+// ConsumerStates returns the state of every consumer of the given streams,
+// sorted by stream and name.
+func (c *NATSClient) ConsumerStates(ctx context.Context, streamNames ...string) ([]ConsumerState, error) {
+	var states []ConsumerState
+	for _, stream := range streamNames {
+		if _, err := c.js.StreamInfo(stream, nats.Context(ctx)); err != nil {
+			return nil, fmt.Errorf("stream %s: %w", stream, err)
+		}
+		for info := range c.js.Consumers(stream, nats.Context(ctx)) {
+			states = append(states, consumerState(info))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Stream != states[j].Stream {
+			return states[i].Stream < states[j].Stream
+		}
+		return states[i].Name < states[j].Name
+	})
+	return states, nil
+}
+
+func consumerState(info *nats.ConsumerInfo) ConsumerState {
+	return ConsumerState{
+		Stream:         info.Stream,
+		Name:           info.Name,
+		FilterSubject:  info.Config.FilterSubject,
+		Delivered:      info.Delivered.Stream,
+		AckFloor:       info.AckFloor.Stream,
+		NumPending:     info.NumPending,
+		NumAckPending:  info.NumAckPending,
+		NumRedelivered: info.NumRedelivered,
+		NumWaiting:     info.NumWaiting,
+		LastActive:     info.Delivered.Last,
+	}
+}
@@ -0,0 +1,27 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// CheckConnection fails unless the connection to the server is up. While the
+// client reconnects, publishes are buffered rather than sent.
+func (c *NATSClient) CheckConnection(_ context.Context) error {
+	if status := c.conn.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("connection is %s", status)
+	}
+	return nil
+}
+
+// CheckStreams fails unless every stream the service publishes to exists.
+func (c *NATSClient) CheckStreams(ctx context.Context) error {
+	for _, s := range streams {
+		if _, err := c.js.StreamInfo(s.name, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("stream %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
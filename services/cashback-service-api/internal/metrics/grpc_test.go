@@ -0,0 +1,32 @@
+package metrics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCClientRecordsCallsByCode(t *testing.T) {
+	reg := metrics.NewRegistry()
+	intercept := metrics.NewGRPCClient(reg).UnaryInterceptor()
+
+	for _, err := range []error{nil, status.Error(codes.Unavailable, "adapter down")} {
+		invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return err
+		}
+		got := intercept(context.Background(), "/token.TokenService/DeriveCustodialAddress", nil, nil, nil, invoker)
+		if got != err {
+			t.Fatalf("interceptor returned %v, want %v", got, err)
+		}
+	}
+
+	assertScraped(t, reg,
+		`cashback_grpc_client_handled_total{grpc_code="OK",grpc_method="DeriveCustodialAddress",grpc_service="token.TokenService",service="cashback-service-api"} 1`,
+		`cashback_grpc_client_handled_total{grpc_code="Unavailable",grpc_method="DeriveCustodialAddress",grpc_service="token.TokenService",service="cashback-service-api"} 1`,
+		`cashback_grpc_client_handling_seconds_count{grpc_method="DeriveCustodialAddress",grpc_service="token.TokenService",service="cashback-service-api"} 2`,
+	)
+}
@@ -0,0 +1,65 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/metrics"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestHTTPMiddlewareLabelsRequestsByRoute(t *testing.T) {
+	reg := metrics.NewRegistry()
+	router := chi.NewRouter()
+	router.Use(metrics.NewHTTP(reg).Middleware)
+	router.Get("/api/v1/users/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	router.Post("/api/v1/purchases", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/v1/users/1", nil),
+		httptest.NewRequest(http.MethodGet, "/api/v1/users/2", nil),
+		httptest.NewRequest(http.MethodPost, "/api/v1/purchases", nil),
+		httptest.NewRequest(http.MethodGet, "/wp-login.php", nil),
+		httptest.NewRequest("PROPFIND", "/api/v1/purchases", nil),
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assertScraped(t, reg,
+		`cashback_http_requests_total{code="404",method="GET",route="/api/v1/users/{id}",service="cashback-service-api"} 2`,
+		`cashback_http_requests_total{code="201",method="POST",route="/api/v1/purchases",service="cashback-service-api"} 1`,
+		`cashback_http_requests_total{code="404",method="GET",route="unmatched",service="cashback-service-api"} 1`,
+		`cashback_http_request_duration_seconds_count{method="GET",route="/api/v1/users/{id}",service="cashback-service-api"} 2`,
+		`cashback_http_requests_in_flight{service="cashback-service-api"} 0`,
+	)
+	if body := scrape(t, reg); strings.Contains(body, `method="PROPFIND"`) {
+		t.Fatal("an unknown method was used as a label")
+	}
+}
+
+func assertScraped(t *testing.T, reg *metrics.Registry, lines ...string) {
+	t.Helper()
+	body := scrape(t, reg)
+	for _, line := range lines {
+		if !strings.Contains(body, line) {
+			t.Errorf("missing %s", line)
+		}
+	}
+}
+
+// scrape returns the registry's metrics in the text exposition format.
+func scrape(t *testing.T, reg *metrics.Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape returned %d", rec.Code)
+	}
+	return rec.Body.String()
+}
@@ -0,0 +1,43 @@
+// Package metrics exposes the service's Prometheus metrics on Path.
+//
+// Every service of the platform names its metrics the same way:
+// cashback_<layer>_<name>, with the emitting service in the service label, so
+// dashboards and alerts work across services. Keep new metrics to that scheme.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	Path        = "/metrics"
+	namespace   = "cashback"
+	serviceName = "cashback-service-api"
+)
+
+// Registry collects the metrics served on Path.
+type Registry struct {
+	prometheus.Registerer
+	gatherer prometheus.Gatherer
+}
+
+func NewRegistry() *Registry {
+	reg := prometheus.NewRegistry()
+	r := &Registry{
+		Registerer: prometheus.WrapRegistererWith(prometheus.Labels{"service": serviceName}, reg),
+		gatherer:   reg,
+	}
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return r
+}
+
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.gatherer, promhttp.HandlerOpts{})
+}
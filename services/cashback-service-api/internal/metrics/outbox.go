@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging/outbox/repository"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Results of an outbox publish attempt.
+const (
+	OutboxPublished = "published"
+	OutboxRetried   = "retried"
+	OutboxFailed    = "failed"
+)
+
+// backlogTimeout bounds the count query run on every scrape.
+const backlogTimeout = 2 * time.Second
+
+type (
+	// Outbox records how far event publication lags behind the writes.
+	Outbox struct {
+		attempts *prometheus.CounterVec
+		latency  *prometheus.HistogramVec
+	}
+
+	// backlogCollector counts the pending events on every scrape. When the
+	// count fails the metric is left out rather than reported as 0.
+	backlogCollector struct {
+		repo *repository.Repository
+		log  *slog.Logger
+		desc *prometheus.Desc
+	}
+)
+
+func NewOutbox(reg *Registry, repo *repository.Repository, log *slog.Logger) *Outbox {
+	m := &Outbox{
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "publish_attempts_total",
+			Help:      "Outbox publish attempts, by event type and result (published, retried, failed).",
+		}, []string{"event_type", "result"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "publish_latency_seconds",
+			Help:      "Time from writing an event to the outbox until it was published, by event type.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+		}, []string{"event_type"}),
+	}
+
+	backlog := &backlogCollector{
+		repo: repo,
+		log:  log,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "outbox", "backlog_events"),
+			"Events in the outbox waiting to be published.",
+			nil, nil,
+		),
+	}
+
+	reg.MustRegister(m.attempts, m.latency, backlog)
+	return m
+}
+
+// Published records an event published createdAt after it was written.
+func (m *Outbox) Published(eventType string, createdAt time.Time) {
+	m.attempts.WithLabelValues(eventType, OutboxPublished).Inc()
+	m.latency.WithLabelValues(eventType).Observe(time.Since(createdAt).Seconds())
+}
+
+// PublishFailed records a failed attempt; gaveUp is set once the event will
+// not be retried again.
+func (m *Outbox) PublishFailed(eventType string, gaveUp bool) {
+	result := OutboxRetried
+	if gaveUp {
+		result = OutboxFailed
+	}
+	m.attempts.WithLabelValues(eventType, result).Inc()
+}
+
+func (c *backlogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *backlogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), backlogTimeout)
+	defer cancel()
+
+	pending, err := c.repo.CountPending(ctx)
+	if err != nil {
+		c.log.ErrorContext(ctx, "failed to count outbox backlog", "error", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(pending))
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestLogger attaches the request ID to everything logged while serving a
+// request and logs the request once it has been served. It must run after
+// chimiddleware.RequestID.
+func RequestLogger(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx := logger.WithRequestID(r.Context(), chimiddleware.GetReqID(r.Context()))
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			log.InfoContext(ctx, "request served",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"duration_ms", time.Since(start).Milliseconds(),
+			)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// EchoRequestID returns the request ID in the X-Request-Id response header, so
+// clients can quote it together with the request_id of an error.
+func EchoRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := chimiddleware.GetReqID(r.Context()); id != "" {
+			w.Header().Set(chimiddleware.RequestIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
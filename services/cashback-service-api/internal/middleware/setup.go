@@ -1,13 +1,24 @@
 package middleware
 
 import (
+	"log/slog"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/internal/idempotency"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ratelimit"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 )
 
-func Setup(router chi.Router, _ string) {
+func Setup(router chi.Router, _ string, log *slog.Logger, authenticator *auth.Authenticator, keys *idempotency.Store, limiter *ratelimit.Limiter, doc *openapi.Document) {
 	router.Use(chimiddleware.RequestID)
-	router.Use(chimiddleware.RealIP)
-	router.Use(chimiddleware.Logger)
+	router.Use(EchoRequestID)
+	router.Use(RequestLogger(log))
 	router.Use(chimiddleware.Recoverer)
+	router.Use(auth.Authenticate(authenticator))
+	router.Use(ratelimit.Middleware(limiter, router))
+	router.Use(openapi.Middleware(doc))
+	router.Use(idempotency.Middleware(keys))
 }
@@ -0,0 +1,61 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Transaction is a row of blockchain-adapter's blockchain_transactions
+// table. FromAddress is empty for mints.
+type Transaction struct {
+	ID              uuid.UUID  `json:"id"`
+	IdempotencyKey  uuid.UUID  `json:"idempotency_key"`
+	FromAddress     string     `json:"from_address,omitempty"`
+	WalletAddress   string     `json:"wallet_address"`
+	TokenAmount     string     `json:"token_amount"`
+	TransactionHash string     `json:"transaction_hash,omitempty"`
+	BlockNumber     int64      `json:"block_number,omitempty"`
+	Status          string     `json:"status"`
+	ErrorCode       string     `json:"error_code,omitempty"`
+	ErrorMessage    string     `json:"error_message,omitempty"`
+	Nonce           int64      `json:"nonce"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty"`
+}
+
+func (Transaction) TableName() string {
+	return "blockchain_transactions"
+}
+
+// ChainStore reads transactions from blockchain-adapter's database.
+type ChainStore struct {
+	db *gorm.DB
+}
+
+// NewChainStore returns a store on db, which is nil when no blockchain
+// database is configured.
+func NewChainStore(db *gorm.DB) ChainStore {
+	return ChainStore{db: db}
+}
+
+// FindTransactions returns the transactions recorded under the given
+// idempotency keys, oldest first.
+func (s ChainStore) FindTransactions(ctx context.Context, idempotencyKeys []uuid.UUID) ([]Transaction, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("blockchain %w", ErrNotConfigured)
+	}
+	if len(idempotencyKeys) == 0 {
+		return nil, nil
+	}
+
+	var transactions []Transaction
+	err := s.db.WithContext(ctx).
+		Where("idempotency_key IN ?", idempotencyKeys).
+		Order("created_at ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
@@ -0,0 +1,118 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// mintStatusFailed is the only status mint-consumer retries from.
+const mintStatusFailed = "failed"
+
+// MintRequest is a row of mint-consumer's mint_requests table.
+type MintRequest struct {
+	ID              uuid.UUID  `json:"id"`
+	CashbackID      uuid.UUID  `json:"cashback_id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	WalletAddress   string     `json:"wallet_address"`
+	TokenAmount     string     `json:"token_amount"`
+	IdempotencyKey  uuid.UUID  `json:"idempotency_key"`
+	Status          string     `json:"status"`
+	RetryCount      int        `json:"retry_count"`
+	MaxRetries      int        `json:"max_retries"`
+	TransactionHash string     `json:"transaction_hash,omitempty"`
+	BlockNumber     int64      `json:"block_number,omitempty"`
+	ErrorCode       string     `json:"error_code,omitempty"`
+	ErrorMessage    string     `json:"error_message,omitempty"`
+	NextRetryAt     *time.Time `json:"next_retry_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+func (MintRequest) TableName() string {
+	return "mint_requests"
+}
+
+// MintStore reads and retries mint requests in mint-consumer's database.
+type MintStore struct {
+	db *gorm.DB
+}
+
+// NewMintStore returns a store on db, which is nil when no mint database is
+// configured.
+func NewMintStore(db *gorm.DB) MintStore {
+	return MintStore{db: db}
+}
+
+// FindByCashbackID returns the mint requests of a cashback, oldest first.
+func (s MintStore) FindByCashbackID(ctx context.Context, cashbackID uuid.UUID) ([]MintRequest, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("mint %w", ErrNotConfigured)
+	}
+
+	var requests []MintRequest
+	err := s.db.WithContext(ctx).
+		Where("cashback_id = ?", cashbackID).
+		Order("created_at ASC").
+		Find(&requests).Error
+	return requests, err
+}
+
+func (s MintStore) Get(ctx context.Context, id uuid.UUID) (MintRequest, error) {
+	if s.db == nil {
+		return MintRequest{}, fmt.Errorf("mint %w", ErrNotConfigured)
+	}
+
+	var request MintRequest
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&request).Error; err != nil {
+		return MintRequest{}, fmt.Errorf("mint request %s: %w", id, notFound(err))
+	}
+	return request, nil
+}
+
+// Retry makes a failed mint request due for mint-consumer's next retry pass,
+// granting one more attempt when its retries are used up. The retry loop then
+// claims and mints it like any other due request. It returns the request as
+// it is after the retry, without writing it when dryRun is set.
+func (s MintStore) Retry(ctx context.Context, id uuid.UUID, dryRun bool) (MintRequest, error) {
+	request, err := s.Get(ctx, id)
+	if err != nil {
+		return MintRequest{}, err
+	}
+	if request.Status != mintStatusFailed {
+		return request, fmt.Errorf("%w: status is %s", ErrNotRetryable, request.Status)
+	}
+	// Cashback without a wallet or a mintable amount is failed for good.
+	if request.WalletAddress == "" || request.TokenAmount == "" {
+		return request, fmt.Errorf("%w: %s", ErrNotRetryable, request.ErrorCode)
+	}
+
+	now := time.Now().UTC()
+	request.NextRetryAt = &now
+	if request.RetryCount >= request.MaxRetries {
+		request.MaxRetries = request.RetryCount + 1
+	}
+	if dryRun {
+		return request, nil
+	}
+
+	// The request must still be the failed attempt that was read: one a
+	// consumer claimed, or failed again, in the meantime is left alone.
+	result := s.db.WithContext(ctx).Model(&MintRequest{}).
+		Where("id = ? AND status = ? AND retry_count = ?", id, mintStatusFailed, request.RetryCount).
+		Updates(map[string]any{
+			"next_retry_at": request.NextRetryAt,
+			"max_retries":   request.MaxRetries,
+		})
+	if result.Error != nil {
+		return MintRequest{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return MintRequest{}, fmt.Errorf("%w: status changed while retrying", ErrNotRetryable)
+	}
+	return s.Get(ctx, id)
+}
@@ -0,0 +1,24 @@
+// Package ops holds the operations cashbackctl runs against the databases of
+// mint-consumer and blockchain-adapter. Those services own their schemas, so
+// only the columns used here are mapped, and writes are limited to what the
+// owning service already does itself.
+package ops
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotConfigured = errors.New("database not configured")
+	ErrNotFound      = errors.New("not found")
+	ErrNotRetryable  = errors.New("mint request is not retryable")
+)
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"go.uber.org/fx"
+)
+
+// PurgeJob periodically deletes buckets that have been idle long enough to be
+// full again, and quota counters of past days. A missing bucket starts full,
+// so purging never changes a limit.
+type PurgeJob struct {
+	limiter  *Limiter
+	interval time.Duration
+	log      *slog.Logger
+	done     chan struct{}
+}
+
+func NewPurgeJob(limiter *Limiter, cfg config.RateLimit, log *slog.Logger) *PurgeJob {
+	return &PurgeJob{
+		limiter:  limiter,
+		interval: cfg.PurgeInterval,
+		log:      log,
+		done:     make(chan struct{}),
+	}
+}
+
+func (j *PurgeJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-j.done:
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *PurgeJob) Stop() {
+	close(j.done)
+}
+
+func (j *PurgeJob) run(ctx context.Context) {
+	idleSince := time.Now().Add(-j.limiter.rules.LongestPeriod())
+	purged, err := j.limiter.store.Purge(ctx, idleSince)
+	if err != nil {
+		j.log.ErrorContext(ctx, "rate limit purge failed", "error", err)
+		return
+	}
+	if purged > 0 {
+		j.log.InfoContext(ctx, "purged idle rate limit buckets and quota counters", "count", purged)
+	}
+}
+
+func StartPurgeJob(lc fx.Lifecycle, job *PurgeJob) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go job.Start(ctx)
+			job.log.Info("rate limit purge job started")
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			job.Stop()
+			job.log.Info("rate limit purge job stopped")
+			return nil
+		},
+	})
+}
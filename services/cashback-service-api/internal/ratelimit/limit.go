@@ -0,0 +1,206 @@
+// Package ratelimit throttles API callers with token buckets and caps the
+// number of requests partners can make per day.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+)
+
+type (
+	// Limit allows Requests per Period. Requests is also the burst size: an
+	// idle caller can spend all of them at once.
+	Limit struct {
+		Requests int
+		Period   time.Duration
+	}
+
+	// Result describes the state of a bucket after a request.
+	Result struct {
+		Allowed    bool
+		Limit      int
+		Remaining  int
+		Reset      time.Duration
+		RetryAfter time.Duration
+	}
+
+	// Rules are the limits and quotas parsed from configuration.
+	Rules struct {
+		Default        Limit
+		Routes         map[string]Limit
+		DailyQuota     int64
+		PartnerQuotas  map[string]int64
+		TrustedProxies []netip.Prefix
+	}
+
+	bucket struct {
+		tokens    float64
+		updatedAt time.Time
+	}
+)
+
+// ParseLimit parses "<requests>/<period>", e.g. "100/1m" or "10/s".
+func ParseLimit(s string) (Limit, error) {
+	requests, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q must be <requests>/<period>", s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("rate limit %q: requests must be a positive integer", s)
+	}
+
+	if period != "" && !strings.ContainsAny(period[:1], "0123456789") {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid period", s)
+	}
+
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// NewRules parses the rate limit configuration. Routes are given as
+// "METHOD /pattern=<limit>" pairs, partner quotas as "subject=<requests>" and
+// trusted proxies as addresses or CIDR ranges.
+func NewRules(cfg config.RateLimit) (*Rules, error) {
+	def, err := ParseLimit(cfg.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &Rules{
+		Default:       def,
+		Routes:        make(map[string]Limit),
+		DailyQuota:    cfg.DailyQuota,
+		PartnerQuotas: make(map[string]int64),
+	}
+
+	for _, def := range split(cfg.Routes) {
+		route, spec, ok := strings.Cut(def, "=")
+		method, pattern, hasPattern := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasPattern || !isMethod(method) {
+			return nil, fmt.Errorf("route limit %q must be METHOD /pattern=<requests>/<period>", def)
+		}
+		limit, err := ParseLimit(spec)
+		if err != nil {
+			return nil, err
+		}
+		rules.Routes[method+" "+strings.TrimSpace(pattern)] = limit
+	}
+
+	for _, def := range split(cfg.PartnerQuotas) {
+		subject, quota, ok := strings.Cut(def, "=")
+		n, err := strconv.ParseInt(strings.TrimSpace(quota), 10, 64)
+		if !ok || err != nil || n < 0 {
+			return nil, fmt.Errorf("partner quota %q must be subject=<requests>", def)
+		}
+		rules.PartnerQuotas[strings.TrimSpace(subject)] = n
+	}
+
+	for _, def := range split(cfg.TrustedProxies) {
+		prefix, err := parsePrefix(def)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q must be an address or CIDR range", def)
+		}
+		rules.TrustedProxies = append(rules.TrustedProxies, prefix)
+	}
+
+	return rules, nil
+}
+
+// LimitFor returns the limit of route ("METHOD /pattern") and the bucket
+// scope it is counted in. Routes without their own limit share the default.
+func (r *Rules) LimitFor(route string) (Limit, string) {
+	if limit, ok := r.Routes[route]; ok {
+		return limit, route
+	}
+	return r.Default, "*"
+}
+
+// QuotaFor returns the daily quota of a partner; 0 means unlimited.
+func (r *Rules) QuotaFor(subject string) int64 {
+	if quota, ok := r.PartnerQuotas[subject]; ok {
+		return quota
+	}
+	return r.DailyQuota
+}
+
+// LongestPeriod is the time after which every idle bucket is full again.
+func (r *Rules) LongestPeriod() time.Duration {
+	longest := r.Default.Period
+	for _, limit := range r.Routes {
+		longest = max(longest, limit.Period)
+	}
+	return longest
+}
+
+func (l Limit) perSecond() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func newBucket(l Limit, now time.Time) bucket {
+	return bucket{tokens: float64(l.Requests), updatedAt: now}
+}
+
+// take refills the bucket for the time elapsed since its last update and
+// spends one token if there is one.
+func (b bucket) take(l Limit, now time.Time) (bucket, Result) {
+	elapsed := max(now.Sub(b.updatedAt).Seconds(), 0)
+	tokens := math.Min(float64(l.Requests), b.tokens+elapsed*l.perSecond())
+
+	result := Result{Limit: l.Requests}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / l.perSecond())
+	}
+	result.Remaining = int(tokens)
+	result.Reset = seconds((float64(l.Requests) - tokens) / l.perSecond())
+
+	return bucket{tokens: tokens, updatedAt: now}, result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func split(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+func isMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
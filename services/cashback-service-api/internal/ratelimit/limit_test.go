@@ -0,0 +1,216 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ratelimit"
+)
+
+func TestParseLimit(t *testing.T) {
+	for _, tc := range []struct {
+		spec string
+		want ratelimit.Limit
+	}{
+		{"100/1m", ratelimit.Limit{Requests: 100, Period: time.Minute}},
+		{"10/s", ratelimit.Limit{Requests: 10, Period: time.Second}},
+		{"5/h", ratelimit.Limit{Requests: 5, Period: time.Hour}},
+		{" 3/500ms ", ratelimit.Limit{Requests: 3, Period: 500 * time.Millisecond}},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			got, err := ratelimit.ParseLimit(tc.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("limit = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	for _, spec := range []string{"", "100", "0/s", "-1/s", "ten/s", "10/", "10/fortnight", "10/0s", "10/-1m"} {
+		if _, err := ratelimit.ParseLimit(spec); err == nil {
+			t.Errorf("ParseLimit(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestNewRules(t *testing.T) {
+	rules, err := ratelimit.NewRules(config.RateLimit{
+		Default:       "100/1m",
+		Routes:        "POST /purchases=10/s, GET /users/{id}/cashbacks=30/1h",
+		DailyQuota:    1000,
+		PartnerQuotas: "acme=50000, globex=0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		route string
+		limit ratelimit.Limit
+		scope string
+	}{
+		{"POST /purchases", ratelimit.Limit{Requests: 10, Period: time.Second}, "POST /purchases"},
+		{"GET /users/{id}/cashbacks", ratelimit.Limit{Requests: 30, Period: time.Hour}, "GET /users/{id}/cashbacks"},
+		{"GET /purchases", ratelimit.Limit{Requests: 100, Period: time.Minute}, "*"},
+	} {
+		if limit, scope := rules.LimitFor(tc.route); limit != tc.limit || scope != tc.scope {
+			t.Errorf("LimitFor(%s) = %+v in %q, want %+v in %q", tc.route, limit, scope, tc.limit, tc.scope)
+		}
+	}
+
+	for subject, want := range map[string]int64{"acme": 50000, "globex": 0, "initech": 1000} {
+		if got := rules.QuotaFor(subject); got != want {
+			t.Errorf("QuotaFor(%s) = %d, want %d", subject, got, want)
+		}
+	}
+
+	if got := rules.LongestPeriod(); got != time.Hour {
+		t.Errorf("LongestPeriod() = %s, want 1h", got)
+	}
+}
+
+func TestNewRulesRejectsInvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  config.RateLimit
+	}{
+		{"default", config.RateLimit{Default: "fast"}},
+		{"route without method", config.RateLimit{Default: "1/s", Routes: "/purchases=1/s"}},
+		{"route with unknown method", config.RateLimit{Default: "1/s", Routes: "HEAD /purchases=1/s"}},
+		{"route without limit", config.RateLimit{Default: "1/s", Routes: "POST /purchases"}},
+		{"route limit", config.RateLimit{Default: "1/s", Routes: "POST /purchases=0/s"}},
+		{"quota without subject", config.RateLimit{Default: "1/s", PartnerQuotas: "50000"}},
+		{"negative quota", config.RateLimit{Default: "1/s", PartnerQuotas: "acme=-1"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ratelimit.NewRules(tc.cfg); err == nil {
+				t.Fatal("invalid config was accepted")
+			}
+		})
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	// 4 requests per 2s refill one token every 500ms.
+	limit := ratelimit.Limit{Requests: 4, Period: 2 * time.Second}
+	start := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	type take struct {
+		at         time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+		reset      time.Duration
+	}
+	for _, tc := range []struct {
+		name  string
+		takes []take
+	}{
+		{"a new caller can burst the whole limit", []take{
+			{0, true, 3, 0, 500 * time.Millisecond},
+			{0, true, 2, 0, time.Second},
+			{0, true, 1, 0, 1500 * time.Millisecond},
+			{0, true, 0, 0, 2 * time.Second},
+			{0, false, 0, 500 * time.Millisecond, 2 * time.Second},
+		}},
+		{"tokens refill over time", []take{
+			{0, true, 3, 0, 500 * time.Millisecond},
+			{0, true, 2, 0, time.Second},
+			{0, true, 1, 0, 1500 * time.Millisecond},
+			{0, true, 0, 0, 2 * time.Second},
+			{250 * time.Millisecond, false, 0, 250 * time.Millisecond, 1750 * time.Millisecond},
+			{500 * time.Millisecond, true, 0, 0, 2 * time.Second},
+			{time.Second, true, 0, 0, 2 * time.Second},
+		}},
+		{"an idle bucket refills up to the limit only", []take{
+			{0, true, 3, 0, 500 * time.Millisecond},
+			{time.Hour, true, 3, 0, 500 * time.Millisecond},
+		}},
+		{"a clock going backwards refills nothing", []take{
+			{time.Second, true, 3, 0, 500 * time.Millisecond},
+			{time.Second, true, 2, 0, time.Second},
+			{time.Second, true, 1, 0, 1500 * time.Millisecond},
+			{time.Second, true, 0, 0, 2 * time.Second},
+			{0, false, 0, 500 * time.Millisecond, 2 * time.Second},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := ratelimit.NewMemoryStore()
+			for i, want := range tc.takes {
+				got, err := store.Take(context.Background(), "caller", limit, start.Add(want.at))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Allowed != want.allowed || got.Limit != limit.Requests || got.Remaining != want.remaining ||
+					got.RetryAfter != want.retryAfter || got.Reset != want.reset {
+					t.Fatalf("take %d at %s = %+v, want %+v", i, want.at, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBucketsAreKeyed(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 1, Period: time.Minute}
+	store := ratelimit.NewMemoryStore()
+	now := time.Now()
+
+	for _, key := range []string{"acme", "globex"} {
+		if result, _ := store.Take(context.Background(), key, limit, now); !result.Allowed {
+			t.Fatalf("the first request of %s was denied", key)
+		}
+	}
+	if result, _ := store.Take(context.Background(), "acme", limit, now); result.Allowed {
+		t.Fatal("acme's second request was allowed")
+	}
+}
+
+func TestQuotaCountersStartOverEachDay(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	today := ratelimit.Day(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	for _, tc := range []struct {
+		day  time.Time
+		want int64
+	}{
+		{yesterday, 1},
+		{today, 1},
+		{today, 2},
+		{today, 3},
+	} {
+		if got, _ := store.Increment(context.Background(), "acme", tc.day); got != tc.want {
+			t.Fatalf("count on %s = %d, want %d", tc.day.Format(time.DateOnly), got, tc.want)
+		}
+	}
+
+	if day := ratelimit.Day(time.Date(2026, 3, 14, 23, 59, 0, 0, time.FixedZone("UTC-5", -5*3600))); !day.Equal(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("day = %s, want the UTC day", day)
+	}
+}
+
+func TestMemoryStorePurge(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 1, Period: time.Minute}
+	store := ratelimit.NewMemoryStore()
+	now := time.Now()
+
+	_, _ = store.Take(context.Background(), "idle", limit, now.Add(-time.Hour))
+	_, _ = store.Take(context.Background(), "active", limit, now)
+	_, _ = store.Increment(context.Background(), "acme", ratelimit.Day(now).AddDate(0, 0, -1))
+	_, _ = store.Increment(context.Background(), "globex", ratelimit.Day(now))
+
+	purged, err := store.Purge(context.Background(), now.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 2 {
+		t.Fatalf("purged %d, want the idle bucket and yesterday's counter", purged)
+	}
+	if result, _ := store.Take(context.Background(), "active", limit, now); result.Allowed {
+		t.Fatal("the active bucket was purged")
+	}
+}
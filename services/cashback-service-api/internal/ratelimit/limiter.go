@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"fmt"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"gorm.io/gorm"
+)
+
+// Limiter applies the configured rules using a Store.
+type Limiter struct {
+	enabled bool
+	rules   *Rules
+	store   Store
+}
+
+func NewLimiter(cfg config.RateLimit, db *gorm.DB) (*Limiter, error) {
+	rules, err := NewRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var store Store
+	switch cfg.Backend {
+	case BackendMemory:
+		store = NewMemoryStore()
+	case BackendPostgres:
+		store = NewPostgresStore(db)
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", cfg.Backend)
+	}
+
+	return &Limiter{
+		enabled: cfg.Enabled,
+		rules:   rules,
+		store:   store,
+	}, nil
+}
@@ -0,0 +1,163 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/internal/auth"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Response headers, as in the IETF RateLimit header fields draft.
+const (
+	LimitHeader     = "RateLimit-Limit"
+	RemainingHeader = "RateLimit-Remaining"
+	ResetHeader     = "RateLimit-Reset"
+	PolicyHeader    = "RateLimit-Policy"
+)
+
+var (
+	ErrRateLimited   = errors.New("rate limit exceeded")
+	ErrQuotaExceeded = errors.New("daily request quota exceeded")
+)
+
+// Middleware limits each caller per route. Callers are identified by their
+// principal, or by client IP when unauthenticated, which forwarding headers
+// only override for requests from a trusted proxy; routes are matched against
+// routes, the router the middleware is mounted on. Partners are also held to
+// their daily quota. When the store fails, requests are let through.
+func Middleware(limiter *Limiter, routes chi.Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !limiter.enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			principal, authenticated := auth.FromContext(r.Context())
+
+			caller := "ip:" + clientIP(r, limiter.rules.TrustedProxies)
+			if authenticated {
+				caller = string(principal.Role) + ":" + principal.Subject
+			}
+
+			limit, scope := limiter.rules.LimitFor(routeOf(routes, r))
+			result, err := limiter.store.Take(r.Context(), scope+"|"+caller, limit, now)
+			if err != nil {
+				logger.ErrorContext(r.Context(), "rate limit check failed, letting the request through", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setHeaders(w, limit, result)
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				errorhandler.Render(w, r, ErrRateLimited)
+				return
+			}
+
+			if authenticated && principal.Role == auth.RolePartner {
+				if quota := limiter.rules.QuotaFor(principal.Subject); quota > 0 {
+					used, err := limiter.store.Increment(r.Context(), caller, Day(now))
+					if err != nil {
+						logger.ErrorContext(r.Context(), "request quota count failed", "error", err)
+					} else if used > quota {
+						tomorrow := Day(now).Add(24 * time.Hour)
+						w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(tomorrow.Sub(now))))
+						errorhandler.Render(w, r, ErrQuotaExceeded)
+						return
+					}
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeOf returns "METHOD /pattern" of the route serving r, or "" when no
+// route matches.
+func routeOf(routes chi.Routes, r *http.Request) string {
+	path := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		path = rctx.RoutePath
+	}
+
+	rctx := chi.NewRouteContext()
+	if !routes.Match(rctx, r.Method, path) {
+		return ""
+	}
+	return r.Method + " " + rctx.RoutePattern()
+}
+
+// clientIP returns the address of the peer, unless it is a trusted proxy: the
+// client is then the last address in X-Forwarded-For that is not a trusted
+// proxy, or X-Real-IP without X-Forwarded-For. Headers from any other peer
+// are ignored, since clients can send them too.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	if !isTrusted(peer, trusted) {
+		return peer
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if _, err := netip.ParseAddr(hop); err != nil {
+				break
+			}
+			client = hop
+			if !isTrusted(hop, trusted) {
+				break
+			}
+		}
+		return client
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		if _, err := netip.ParseAddr(realIP); err == nil {
+			return realIP
+		}
+	}
+	return peer
+}
+
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func setHeaders(w http.ResponseWriter, limit Limit, result Result) {
+	w.Header().Set(LimitHeader, strconv.Itoa(result.Limit))
+	w.Header().Set(RemainingHeader, strconv.Itoa(result.Remaining))
+	w.Header().Set(ResetHeader, strconv.Itoa(ceilSeconds(result.Reset)))
+	w.Header().Set(PolicyHeader, fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,87 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func newRouter(t *testing.T, trustedProxies string) chi.Router {
+	t.Helper()
+	limiter, err := ratelimit.NewLimiter(config.RateLimit{
+		Enabled:        true,
+		Backend:        ratelimit.BackendMemory,
+		Default:        "2/1m",
+		TrustedProxies: trustedProxies,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := chi.NewRouter()
+	router.Use(ratelimit.Middleware(limiter, router))
+	router.Get("/ping", func(w http.ResponseWriter, _ *http.Request) {})
+	return router
+}
+
+func remaining(router http.Handler, remoteAddr string, header http.Header) string {
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = remoteAddr
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Header().Get(ratelimit.RemainingHeader)
+}
+
+func TestAnonymousCallersAreLimitedByClientIP(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		remote string
+		header http.Header
+		client string
+	}{
+		{"direct", "203.0.113.7:1234", nil, "203.0.113.7"},
+		{"forwarded by an untrusted peer", "203.0.113.7:1234",
+			http.Header{"X-Forwarded-For": {"198.51.100.1"}}, "203.0.113.7"},
+		{"real IP from an untrusted peer", "203.0.113.7:1234",
+			http.Header{"X-Real-Ip": {"198.51.100.1"}}, "203.0.113.7"},
+		{"forwarded by a trusted proxy", "10.0.0.2:443",
+			http.Header{"X-Forwarded-For": {"198.51.100.1"}}, "198.51.100.1"},
+		{"spoofed hop before the client", "10.0.0.2:443",
+			http.Header{"X-Forwarded-For": {"192.0.2.1, 198.51.100.1", "10.0.0.3"}}, "198.51.100.1"},
+		{"real IP from a trusted address", "192.168.1.1:80",
+			http.Header{"X-Real-Ip": {"198.51.100.9"}}, "198.51.100.9"},
+		{"unreadable forwarded address", "10.0.0.2:443",
+			http.Header{"X-Forwarded-For": {"unknown"}}, "10.0.0.2"},
+		{"only trusted hops", "10.0.0.2:443",
+			http.Header{"X-Forwarded-For": {"10.0.0.9"}}, "10.0.0.9"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newRouter(t, "10.0.0.0/8, 192.168.1.1")
+
+			if got := remaining(router, tc.remote, tc.header); got != "1" {
+				t.Fatalf("first request left %s tokens, want 1", got)
+			}
+			// The client's own next request spends from the same bucket.
+			if got := remaining(router, tc.client+":5555", nil); got != "0" {
+				t.Fatalf("request from %s left %s tokens, want 0", tc.client, got)
+			}
+		})
+	}
+}
+
+func TestTrustedProxiesMustBeAddressesOrRanges(t *testing.T) {
+	for _, proxies := range []string{"proxy.internal", "10.0.0.0/33"} {
+		_, err := ratelimit.NewRules(config.RateLimit{Default: "1/s", TrustedProxies: proxies})
+		if err == nil {
+			t.Errorf("trusted proxies %q were accepted", proxies)
+		}
+	}
+}
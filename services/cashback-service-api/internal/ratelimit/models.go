@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"time"
+)
+
+// bucketModel is the token bucket of one caller on one route, or on all
+// routes without their own limit.
+type bucketModel struct {
+	Key       string    `gorm:"primaryKey"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null;index;autoUpdateTime:false"`
+}
+
+func (bucketModel) TableName() string {
+	return "rate_limit_buckets"
+}
+
+// quotaModel counts the requests of a partner on one UTC day.
+type quotaModel struct {
+	Key  string    `gorm:"primaryKey"`
+	Day  time.Time `gorm:"primaryKey;type:date"`
+	Used int64     `gorm:"not null;default:0"`
+}
+
+func (quotaModel) TableName() string {
+	return "rate_limit_quotas"
+}
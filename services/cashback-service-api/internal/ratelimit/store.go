@@ -0,0 +1,228 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Backends a Store can be created for.
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+type (
+	// Store keeps token buckets and daily quota counters.
+	Store interface {
+		// Take spends a token from the bucket of key.
+		Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+		// Increment counts a request against the quota of key for day and
+		// returns the count including it.
+		Increment(ctx context.Context, key string, day time.Time) (int64, error)
+		// Purge deletes buckets not used since idleSince and counters of past
+		// days.
+		Purge(ctx context.Context, idleSince time.Time) (int64, error)
+	}
+
+	// MemoryStore keeps state in process. Each replica limits on its own.
+	MemoryStore struct {
+		mu      sync.Mutex
+		buckets map[string]bucket
+		quotas  map[string]quotaCounter
+	}
+
+	quotaCounter struct {
+		day  time.Time
+		used int64
+	}
+
+	// PostgresStore shares state between replicas. Every Take is a
+	// transaction locking the bucket row, so requests of one caller queue
+	// behind each other in Postgres; denials are remembered locally to keep
+	// a throttled caller's retries off the database.
+	PostgresStore struct {
+		db      *gorm.DB
+		denials *denials
+	}
+
+	// denials remembers until when buckets are known to be empty. Tokens
+	// only come back with time and other replicas can only spend them, so
+	// until then a bucket denies every request.
+	denials struct {
+		mu    sync.Mutex
+		empty map[string]denial
+	}
+
+	denial struct {
+		until time.Time
+		full  time.Time
+	}
+)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]bucket),
+		quotas:  make(map[string]quotaCounter),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = newBucket(limit, now)
+	}
+	next, result := b.take(limit, now)
+	s.buckets[key] = next
+	return result, nil
+}
+
+func (s *MemoryStore) Increment(_ context.Context, key string, day time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter := s.quotas[key]
+	if !counter.day.Equal(day) {
+		counter = quotaCounter{day: day}
+	}
+	counter.used++
+	s.quotas[key] = counter
+	return counter.used, nil
+}
+
+func (s *MemoryStore) Purge(_ context.Context, idleSince time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, b := range s.buckets {
+		if b.updatedAt.Before(idleSince) {
+			delete(s.buckets, key)
+			purged++
+		}
+	}
+	today := Day(time.Now())
+	for key, counter := range s.quotas {
+		if counter.day.Before(today) {
+			delete(s.quotas, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db, denials: newDenials()}
+}
+
+// Take locks the bucket row for the read-modify-write, so replicas spend
+// tokens one at a time. Two replicas creating the same bucket at once may
+// both start from a full bucket; that only affects a caller's first request.
+// A bucket this replica found empty is not read again until it has a token.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	if result, ok := s.denials.check(key, limit, now); ok {
+		return result, nil
+	}
+
+	var result Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		b := newBucket(limit, now)
+
+		var model bucketModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).Take(&model).Error
+		switch {
+		case err == nil:
+			b = bucket{tokens: model.Tokens, updatedAt: model.UpdatedAt}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		var next bucket
+		next, result = b.take(limit, now)
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tokens", "updated_at"}),
+		}).Create(&bucketModel{Key: key, Tokens: next.tokens, UpdatedAt: next.updatedAt}).Error
+	})
+	if err == nil {
+		s.denials.record(key, result, now)
+	}
+	return result, err
+}
+
+func (s *PostgresStore) Increment(ctx context.Context, key string, day time.Time) (int64, error) {
+	var used int64
+	err := s.db.WithContext(ctx).Raw(`
+		INSERT INTO rate_limit_quotas (key, day, used) VALUES (?, ?, 1)
+		ON CONFLICT (key, day) DO UPDATE SET used = rate_limit_quotas.used + 1
+		RETURNING used`, key, day).Scan(&used).Error
+	return used, err
+}
+
+func (s *PostgresStore) Purge(ctx context.Context, idleSince time.Time) (int64, error) {
+	s.denials.purge(time.Now())
+	db := s.db.WithContext(ctx)
+
+	buckets := db.Where("updated_at < ?", idleSince).Delete(&bucketModel{})
+	if buckets.Error != nil {
+		return 0, buckets.Error
+	}
+	quotas := db.Where("day < ?", Day(time.Now())).Delete(&quotaModel{})
+	return buckets.RowsAffected + quotas.RowsAffected, quotas.Error
+}
+
+func newDenials() *denials {
+	return &denials{empty: make(map[string]denial)}
+}
+
+// check returns the result of a request to key while its bucket is known to
+// be empty.
+func (d *denials) check(key string, limit Limit, now time.Time) (Result, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	denied, ok := d.empty[key]
+	if !ok || !now.Before(denied.until) {
+		return Result{}, false
+	}
+	return Result{
+		Limit:      limit.Requests,
+		Reset:      max(denied.full.Sub(now), 0),
+		RetryAfter: denied.until.Sub(now),
+	}, true
+}
+
+// record remembers the bucket of key as empty if result denied a request.
+func (d *denials) record(key string, result Result, now time.Time) {
+	if result.Allowed {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.empty[key] = denial{until: now.Add(result.RetryAfter), full: now.Add(result.Reset)}
+}
+
+func (d *denials) purge(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, denied := range d.empty {
+		if !now.Before(denied.until) {
+			delete(d.empty, key)
+		}
+	}
+}
+
+// Day is the UTC day quotas of t are counted in.
+func Day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDenialsAnswerUntilTheBucketHasAToken(t *testing.T) {
+	limit := Limit{Requests: 2, Period: time.Minute}
+	now := time.Now()
+	d := newDenials()
+
+	d.record("caller", Result{Allowed: true, Remaining: 0}, now)
+	if _, ok := d.check("caller", limit, now); ok {
+		t.Fatal("an allowed request marked the bucket empty")
+	}
+
+	d.record("caller", Result{RetryAfter: 30 * time.Second, Reset: time.Minute}, now)
+	result, ok := d.check("caller", limit, now.Add(10*time.Second))
+	if !ok {
+		t.Fatal("a denied bucket was not remembered")
+	}
+	if result.Allowed || result.Limit != 2 || result.RetryAfter != 20*time.Second || result.Reset != 50*time.Second {
+		t.Fatalf("result %+v", result)
+	}
+
+	if _, ok := d.check("caller", limit, now.Add(30*time.Second)); ok {
+		t.Fatal("the bucket was still denied once it had a token")
+	}
+	if _, ok := d.check("other", limit, now); ok {
+		t.Fatal("another caller was denied")
+	}
+
+	d.purge(now.Add(30 * time.Second))
+	if len(d.empty) != 0 {
+		t.Fatalf("%d denials left after the purge", len(d.empty))
+	}
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for every request, continuing the caller's
+// trace when it sends a traceparent header. Like the metrics middleware it
+// must wrap the root router: the span is named after the route chi matched.
+// Requests to the paths in skip are not traced.
+func Middleware(skip ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(r.Method + " " + rctx.RoutePattern())
+				span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+			}
+		})
+
+		return otelhttp.NewHandler(named, serviceName,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method
+			}),
+			otelhttp.WithFilter(func(r *http.Request) bool {
+				for _, path := range skip {
+					if r.URL.Path == path {
+						return false
+					}
+				}
+				return true
+			}),
+		)
+	}
+}
@@ -0,0 +1,91 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/internal/tracing"
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+var provider = sdktrace.NewTracerProvider()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	os.Exit(m.Run())
+}
+
+// record returns a recorder of the spans ended during the test.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	rec := tracetest.NewSpanRecorder()
+	provider.RegisterSpanProcessor(rec)
+	t.Cleanup(func() { provider.UnregisterSpanProcessor(rec) })
+	return rec
+}
+
+func newRouter() http.Handler {
+	router := chi.NewRouter()
+	router.Use(tracing.Middleware("/health"))
+	router.Get("/api/v1/users/{id}", func(http.ResponseWriter, *http.Request) {})
+	router.Get("/health", func(http.ResponseWriter, *http.Request) {})
+	return router
+}
+
+func TestMiddlewareNamesSpansAfterTheRoute(t *testing.T) {
+	rec := record(t)
+	newRouter().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil))
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	if name := spans[0].Name(); name != "GET /api/v1/users/{id}" {
+		t.Fatalf("span name = %q", name)
+	}
+	found := false
+	for _, attr := range spans[0].Attributes() {
+		found = found || attr == semconv.HTTPRoute("/api/v1/users/{id}")
+	}
+	if !found {
+		t.Fatalf("attributes = %v, want the route", spans[0].Attributes())
+	}
+}
+
+func TestMiddlewareContinuesTheCallersTrace(t *testing.T) {
+	rec := record(t)
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+spanID+"-01")
+	newRouter().ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != traceID {
+		t.Fatalf("trace = %s, want %s", got, traceID)
+	}
+	if got := spans[0].Parent().SpanID().String(); got != spanID {
+		t.Fatalf("parent = %s, want %s", got, spanID)
+	}
+}
+
+func TestMiddlewareSkipsPaths(t *testing.T) {
+	rec := record(t)
+	newRouter().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if spans := rec.Ended(); len(spans) != 0 {
+		t.Fatalf("recorded %d spans for a skipped path", len(spans))
+	}
+}
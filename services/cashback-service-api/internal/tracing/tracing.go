@@ -0,0 +1,31 @@
+// Package tracing sets up OpenTelemetry tracing for the service.
+//
+// Traces follow a cashback across the platform: the W3C trace context travels
+// in HTTP and gRPC headers, in the outbox row next to the event payload, and
+// in the headers of the NATS message published from it.
+package tracing
+
+import (
+	"context"
+
+	"github.com/cashback-platform/pkg/tracing"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"go.uber.org/fx"
+)
+
+const serviceName = "cashback-service-api"
+
+// Setup installs the shared tracer setup for TRACING_EXPORTER and flushes the
+// spans still batched when the service stops.
+func Setup(lc fx.Lifecycle, cfg config.Tracing) error {
+	shutdown, err := tracing.Setup(serviceName, tracing.Config(cfg))
+	if err != nil {
+		return err
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return shutdown(ctx)
+		},
+	})
+	return nil
+}
@@ -1,4 +1,6 @@
 // Package errorhandler provides utilities for handling HTTP errors consistently.
+// Errors are rendered as RFC 7807 application/problem+json documents whose
+// code and status come from a registry of known errors.
 package errorhandler
 
 import (
@@ -8,15 +10,22 @@ import (
 
 // Common HTTP errors
 var (
-	ErrBadRequest          = NewHTTPError(http.StatusBadRequest, "bad request")
-	ErrUnauthorized        = NewHTTPError(http.StatusUnauthorized, "unauthorized")
-	ErrForbidden           = NewHTTPError(http.StatusForbidden, "forbidden")
-	ErrNotFound            = NewHTTPError(http.StatusNotFound, "not found")
-	ErrConflict            = NewHTTPError(http.StatusConflict, "conflict")
-	ErrUnprocessableEntity = NewHTTPError(http.StatusUnprocessableEntity, "unprocessable entity")
-	ErrInternalServer      = NewHTTPError(http.StatusInternalServerError, "internal server error")
+	ErrBadRequest           = NewHTTPError(http.StatusBadRequest, "bad request")
+	ErrUnauthorized         = NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	ErrForbidden            = NewHTTPError(http.StatusForbidden, "forbidden")
+	ErrNotFound             = NewHTTPError(http.StatusNotFound, "not found")
+	ErrMethodNotAllowed     = NewHTTPError(http.StatusMethodNotAllowed, "method not allowed")
+	ErrConflict             = NewHTTPError(http.StatusConflict, "conflict")
+	ErrUnsupportedMediaType = NewHTTPError(http.StatusUnsupportedMediaType, "content type must be application/json")
+	ErrUnprocessableEntity  = NewHTTPError(http.StatusUnprocessableEntity, "unprocessable entity")
+	ErrInternalServer       = NewHTTPError(http.StatusInternalServerError, "internal server error")
+
+	ErrInvalidPayload = NewHTTPError(http.StatusBadRequest, "invalid payload")
 )
 
+// ErrInvalidUUID reports a path or body field that is not a UUID.
+var ErrInvalidUUID = errors.New("must be a UUID")
+
 // HTTPError represents an error that has an associated HTTP status code.
 type HTTPError struct {
 	Code    int
@@ -51,31 +60,3 @@ func WrapHTTPError(code int, message string, err error) *HTTPError {
 		Err:     err,
 	}
 }
-
-// Render writes the HTTP error response to the ResponseWriter.
-// It determines the status code and message based on the error type.
-func Render(w http.ResponseWriter, err error) {
-	var httpErr *HTTPError
-	if errors.As(err, &httpErr) {
-		http.Error(w, httpErr.Message, httpErr.Code)
-		return
-	}
-
-	// Default to internal server error for unknown errors
-	http.Error(w, "internal server error", http.StatusInternalServerError)
-}
-
-// RenderWithCode writes the HTTP error response with a specific status code.
-func RenderWithCode(w http.ResponseWriter, code int, message string) {
-	http.Error(w, message, code)
-}
-
-// CodeFromError extracts the HTTP status code from an error.
-// Returns 500 if the error is not an HTTPError.
-func CodeFromError(err error) int {
-	var httpErr *HTTPError
-	if errors.As(err, &httpErr) {
-		return httpErr.Code
-	}
-	return http.StatusInternalServerError
-}
@@ -0,0 +1,101 @@
+package errorhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cashback-platform/pkg/logger"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// ContentType is the media type of problem documents.
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem document. Code is the stable,
+// machine-readable identifier of the problem; Type stays about:blank, so
+// Title is always the text of Status.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem builds the problem for err. Validation errors become a 400 with
+// one entry per field, registered errors get their registered code and
+// status, and anything else is an internal error whose details are not
+// exposed.
+func NewProblem(r *http.Request, err error) Problem {
+	problem := resolve(err)
+	problem.Type = "about:blank"
+	problem.Title = http.StatusText(problem.Status)
+	problem.Instance = r.URL.Path
+	problem.RequestID = chimiddleware.GetReqID(r.Context())
+	return problem
+}
+
+// Render writes err as a problem document.
+func Render(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(r, err)
+	if problem.Status >= http.StatusInternalServerError {
+		logger.ErrorContext(r.Context(), "request failed", "error", err, "path", problem.Instance)
+	}
+	Write(w, problem)
+}
+
+// Write writes problem with its status and the problem+json content type.
+func Write(w http.ResponseWriter, problem Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		logger.Error("failed to encode problem response", "error", err)
+	}
+}
+
+func resolve(err error) Problem {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		fields := make([]FieldError, len(validationErr.Fields))
+		for i, f := range validationErr.Fields {
+			f.Code = CodeInvalid
+			if entry, ok := Lookup(f.err); ok {
+				f.Code = entry.Code
+			}
+			fields[i] = f
+		}
+		return Problem{
+			Status: http.StatusBadRequest,
+			Code:   CodeValidationFailed,
+			Detail: "request validation failed",
+			Errors: fields,
+		}
+	}
+
+	if entry, ok := Lookup(err); ok {
+		detail := err.Error()
+		if entry.Status >= http.StatusInternalServerError {
+			// Wrapped causes of server errors may leak internals.
+			detail = entry.Err.Error()
+		}
+		return Problem{Status: entry.Status, Code: entry.Code, Detail: detail}
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return Problem{Status: httpErr.Code, Code: CodeForStatus(httpErr.Code), Detail: httpErr.Message}
+	}
+
+	return Problem{
+		Status: http.StatusInternalServerError,
+		Code:   CodeInternal,
+		Detail: "internal server error",
+	}
+}
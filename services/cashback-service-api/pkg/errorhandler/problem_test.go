@@ -0,0 +1,115 @@
+package errorhandler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+var (
+	errOutOfStock = errors.New("out of stock")
+	errLedgerDown = errors.New("ledger unavailable")
+	errTooShort   = errors.New("must be at least 3 characters")
+)
+
+func TestMain(m *testing.M) {
+	// The default registry is shared, so register once for the package.
+	errorhandler.Register("OUT_OF_STOCK", http.StatusConflict, errOutOfStock)
+	errorhandler.Register("LEDGER_UNAVAILABLE", http.StatusServiceUnavailable, errLedgerDown)
+	errorhandler.Register("TOO_SHORT", http.StatusBadRequest, errTooShort)
+	os.Exit(m.Run())
+}
+
+func TestRender(t *testing.T) {
+	var invalid errorhandler.ValidationError
+	invalid.Add("name", errTooShort)
+	invalid.Add("id", errorhandler.ErrInvalidUUID)
+	invalid.Add("note", errors.New("unregistered"))
+	invalid.Add("ignored", nil)
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want errorhandler.Problem
+	}{
+		{"registered error", fmt.Errorf("reserve item: %w", errOutOfStock), errorhandler.Problem{
+			Status: http.StatusConflict, Code: "OUT_OF_STOCK", Detail: "reserve item: out of stock",
+		}},
+		// The cause of a server error is not shown to clients.
+		{"registered server error", fmt.Errorf("dial 10.0.0.7:5432: %w", errLedgerDown), errorhandler.Problem{
+			Status: http.StatusServiceUnavailable, Code: "LEDGER_UNAVAILABLE", Detail: "ledger unavailable",
+		}},
+		{"common HTTP error", errorhandler.ErrNotFound, errorhandler.Problem{
+			Status: http.StatusNotFound, Code: "NOT_FOUND", Detail: "not found",
+		}},
+		{"unregistered HTTP error", errorhandler.NewHTTPError(http.StatusRequestEntityTooLarge, "body too large"), errorhandler.Problem{
+			Status: http.StatusRequestEntityTooLarge, Code: "REQUEST_ENTITY_TOO_LARGE", Detail: "body too large",
+		}},
+		{"unknown error", errors.New("pq: relation missing"), errorhandler.Problem{
+			Status: http.StatusInternalServerError, Code: errorhandler.CodeInternal, Detail: "internal server error",
+		}},
+		{"validation error", invalid.Err(), errorhandler.Problem{
+			Status: http.StatusBadRequest, Code: errorhandler.CodeValidationFailed, Detail: "request validation failed",
+			Errors: []errorhandler.FieldError{
+				{Field: "name", Code: "TOO_SHORT", Message: "must be at least 3 characters"},
+				{Field: "id", Code: "INVALID_UUID", Message: "must be a UUID"},
+				{Field: "note", Code: errorhandler.CodeInvalid, Message: "unregistered"},
+			},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var rec *httptest.ResponseRecorder
+			handler := chimiddleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rec = httptest.NewRecorder()
+				errorhandler.Render(rec, r, tc.err)
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/orders?draft=true", nil)
+			req.Header.Set(chimiddleware.RequestIDHeader, "req-42")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if rec.Code != tc.want.Status || rec.Header().Get("Content-Type") != errorhandler.ContentType {
+				t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+			}
+
+			var got errorhandler.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			want := tc.want
+			want.Type = "about:blank"
+			want.Title = http.StatusText(want.Status)
+			want.Instance = "/orders"
+			want.RequestID = "req-42"
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("problem = %+v\nwant      %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	var v errorhandler.ValidationError
+	v.Add("name", nil)
+	if v.Err() != nil {
+		t.Fatal("a validation without failures must be nil")
+	}
+
+	v.Add("name", errTooShort)
+	v.Add("id", errorhandler.ErrInvalidUUID)
+	err := v.Err()
+	if err.Error() != "name: must be at least 3 characters; id: must be a UUID" {
+		t.Fatalf("message = %q", err)
+	}
+	if !errors.Is(err, errTooShort) || !errors.Is(err, errorhandler.ErrInvalidUUID) {
+		t.Fatal("field errors must be reachable through errors.Is")
+	}
+}
@@ -0,0 +1,112 @@
+package errorhandler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Codes of problems that are not tied to a single registered error.
+const (
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeInvalid          = "INVALID"
+	CodeInternal         = "INTERNAL_ERROR"
+)
+
+type (
+	// Entry is the stable code and HTTP status clients see for an error.
+	// Err is the registered error the entry was matched on.
+	Entry struct {
+		Code   string
+		Status int
+		Err    error
+	}
+
+	// Registry maps sentinel errors to entries. Errors are matched with
+	// errors.Is, in registration order, so wrapped errors resolve too.
+	Registry struct {
+		mu      sync.RWMutex
+		entries []Entry
+	}
+)
+
+var defaultRegistry = newDefaultRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register maps each of errs to code and status.
+func (r *Registry) Register(code string, status int, errs ...error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, err := range errs {
+		r.entries = append(r.entries, Entry{Code: code, Status: status, Err: err})
+	}
+}
+
+// Lookup returns the entry of the first registered error err matches.
+func (r *Registry) Lookup(err error) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.entries {
+		if errors.Is(err, e.Err) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Entries returns the registered entries in registration order.
+func (r *Registry) Entries() []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Entry(nil), r.entries...)
+}
+
+// Register maps errs to code and status in the registry used by Render.
+func Register(code string, status int, errs ...error) {
+	defaultRegistry.Register(code, status, errs...)
+}
+
+// Lookup resolves err in the registry used by Render.
+func Lookup(err error) (Entry, bool) {
+	return defaultRegistry.Lookup(err)
+}
+
+// Entries lists the registry used by Render.
+func Entries() []Entry {
+	return defaultRegistry.Entries()
+}
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, err := range []*HTTPError{
+		ErrBadRequest,
+		ErrUnauthorized,
+		ErrForbidden,
+		ErrNotFound,
+		ErrMethodNotAllowed,
+		ErrConflict,
+		ErrUnsupportedMediaType,
+		ErrUnprocessableEntity,
+		ErrInternalServer,
+	} {
+		r.Register(CodeForStatus(err.Code), err.Code, err)
+	}
+	r.Register("INVALID_PAYLOAD", http.StatusBadRequest, ErrInvalidPayload)
+	r.Register("INVALID_UUID", http.StatusBadRequest, ErrInvalidUUID)
+	return r
+}
+
+// CodeForStatus derives a code from an HTTP status, e.g. NOT_FOUND for 404.
+func CodeForStatus(status int) string {
+	if status == http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
@@ -0,0 +1,60 @@
+package errorhandler_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errGone := errors.New("gone")
+	errUnknown := errors.New("unknown")
+
+	r := errorhandler.NewRegistry()
+	r.Register("THING_NOT_FOUND", http.StatusNotFound, errNotFound)
+	r.Register("THING_GONE", http.StatusGone, errGone)
+	// A later registration never shadows an earlier one.
+	r.Register("SHADOWED", http.StatusTeapot, errNotFound)
+
+	for _, tc := range []struct {
+		name   string
+		err    error
+		code   string
+		status int
+	}{
+		{"sentinel", errNotFound, "THING_NOT_FOUND", http.StatusNotFound},
+		{"wrapped", fmt.Errorf("find thing: %w", errGone), "THING_GONE", http.StatusGone},
+		{"joined", errors.Join(errUnknown, errGone), "THING_GONE", http.StatusGone},
+		{"unregistered", errUnknown, "", 0},
+		{"nil", nil, "", 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			entry, ok := r.Lookup(tc.err)
+			if ok != (tc.code != "") || entry.Code != tc.code || entry.Status != tc.status {
+				t.Fatalf("entry = %+v, %v; want %s %d", entry, ok, tc.code, tc.status)
+			}
+		})
+	}
+
+	if entries := r.Entries(); len(entries) != 3 || entries[0].Err != errNotFound || entries[2].Code != "SHADOWED" {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	for status, want := range map[int]string{
+		http.StatusBadRequest:           "BAD_REQUEST",
+		http.StatusNotFound:             "NOT_FOUND",
+		http.StatusUnsupportedMediaType: "UNSUPPORTED_MEDIA_TYPE",
+		http.StatusTooManyRequests:      "TOO_MANY_REQUESTS",
+		http.StatusInternalServerError:  errorhandler.CodeInternal,
+	} {
+		if got := errorhandler.CodeForStatus(status); got != want {
+			t.Errorf("CodeForStatus(%d) = %s, want %s", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package errorhandler
+
+import (
+	"strings"
+)
+
+type (
+	// FieldError describes why one request field is invalid. Its code is the
+	// registry code of the underlying error.
+	FieldError struct {
+		Field   string `json:"field"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+
+		err error
+	}
+
+	// ValidationError collects the invalid fields of a request. It renders as
+	// a 400 problem listing every field.
+	ValidationError struct {
+		Fields []FieldError
+	}
+)
+
+// Invalid reports a single invalid field.
+func Invalid(field string, err error) error {
+	var v ValidationError
+	v.Add(field, err)
+	return v.Err()
+}
+
+// Add records that field failed with err. A nil err is ignored, so the result
+// of a validator can be passed straight in.
+func (e *ValidationError) Add(field string, err error) {
+	if err == nil {
+		return
+	}
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: err.Error(), err: err})
+}
+
+// Err returns e, or nil when no field failed.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Unwrap exposes the field errors to errors.Is and errors.As.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		errs[i] = f.err
+	}
+	return errs
+}
@@ -0,0 +1,73 @@
+// Package ethereum provides the Ethereum primitives the platform relies on:
+// address validation with EIP-55 checksums and personal_sign signature recovery.
+package ethereum
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// AddressLength is the length of a 0x-prefixed hex address.
+const AddressLength = 42
+
+var (
+	ErrInvalidAddress  = errors.New("address must be 0x followed by 40 hex characters")
+	ErrInvalidChecksum = errors.New("address does not match its EIP-55 checksum")
+)
+
+// ValidateAddress checks that address is a 0x-prefixed 20-byte hex address.
+// Mixed-case addresses must carry a valid EIP-55 checksum; all-lowercase and
+// all-uppercase addresses carry no checksum and are accepted as-is.
+func ValidateAddress(address string) error {
+	if len(address) != AddressLength || !strings.HasPrefix(address, "0x") {
+		return ErrInvalidAddress
+	}
+
+	digits := address[2:]
+	if _, err := hex.DecodeString(digits); err != nil {
+		return ErrInvalidAddress
+	}
+
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return nil
+	}
+	if address != ChecksumAddress(address) {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// ChecksumAddress returns the EIP-55 mixed-case encoding of a hex address.
+// The input is not validated; call ValidateAddress first.
+func ChecksumAddress(address string) string {
+	digits := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	hash := Keccak256([]byte(digits))
+
+	out := []byte(digits)
+	for i, c := range out {
+		if c < 'a' || c > 'f' {
+			continue
+		}
+		// Each hex digit is uppercased when the matching nibble of the hash is >= 8.
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// Keccak256 returns the legacy Keccak-256 digest used throughout Ethereum.
+func Keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
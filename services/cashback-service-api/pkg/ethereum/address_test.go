@@ -0,0 +1,51 @@
+package ethereum_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+)
+
+// The EIP-55 test vectors.
+var checksummed = []string{
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestChecksumAddress(t *testing.T) {
+	for _, want := range checksummed {
+		for _, in := range []string{want, strings.ToLower(want), "0x" + strings.ToUpper(want[2:])} {
+			if got := ethereum.ChecksumAddress(in); got != want {
+				t.Errorf("ChecksumAddress(%s) = %s, want %s", in, got, want)
+			}
+		}
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	valid := checksummed[0]
+	for _, tc := range []struct {
+		name    string
+		address string
+		want    error
+	}{
+		{"checksummed", valid, nil},
+		{"lowercase", strings.ToLower(valid), nil},
+		{"uppercase", "0x" + strings.ToUpper(valid[2:]), nil},
+		{"wrong checksum", strings.Replace(valid, "aA", "Aa", 1), ethereum.ErrInvalidChecksum},
+		{"no prefix", valid[2:] + "00", ethereum.ErrInvalidAddress},
+		{"too short", valid[:41], ethereum.ErrInvalidAddress},
+		{"too long", valid + "0", ethereum.ErrInvalidAddress},
+		{"not hex", "0x" + strings.Repeat("g", 40), ethereum.ErrInvalidAddress},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ethereum.ValidateAddress(tc.address); !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// signatureLength is the length of an R || S || V signature.
+const signatureLength = 65
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// PersonalMessageHash returns the EIP-191 hash that wallets sign for personal_sign.
+func PersonalMessageHash(message string) []byte {
+	prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(message))
+	return Keccak256([]byte(prefix), []byte(message))
+}
+
+// RecoverPersonalSigner returns the checksummed address that produced the
+// personal_sign signature (hex-encoded R || S || V) over message.
+func RecoverPersonalSigner(message, signature string) (string, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != signatureLength {
+		return "", ErrInvalidSignature
+	}
+
+	// Wallets encode the recovery id as 27/28; some libraries use 0/1.
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return "", ErrInvalidSignature
+	}
+
+	// secp256k1 compact form is <27 + recovery id> || R || S.
+	compact := make([]byte, signatureLength)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+
+	pub, _, err := ecdsa.RecoverCompact(compact, PersonalMessageHash(message))
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	// The address is the last 20 bytes of the hash of the uncompressed key without its 0x04 prefix.
+	hash := Keccak256(pub.SerializeUncompressed()[1:])
+	return ChecksumAddress("0x" + hex.EncodeToString(hash[12:])), nil
+}
@@ -0,0 +1,88 @@
+package ethereum_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// The address of private key 1.
+const signerAddress = "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"
+
+// personalSign signs message as a wallet's personal_sign does: R || S || V
+// over the EIP-191 hash, with V as 27 or 28.
+func personalSign(t *testing.T, message string) []byte {
+	t.Helper()
+	var scalar [32]byte
+	scalar[31] = 1
+	key := secp256k1.PrivKeyFromBytes(scalar[:])
+
+	compact := ecdsa.SignCompact(key, ethereum.PersonalMessageHash(message), false)
+	return append(compact[1:], compact[0])
+}
+
+func TestPersonalMessageHash(t *testing.T) {
+	want := "d9eba16ed0ecae432b71fe008c98cc872bb4cc214d3220a36f365326cf807d68"
+	if got := hex.EncodeToString(ethereum.PersonalMessageHash("hello world")); got != want {
+		t.Fatalf("hash = %s, want %s", got, want)
+	}
+}
+
+func TestRecoverPersonalSigner(t *testing.T) {
+	const message = "cashback.example.com wants you to sign in with your Ethereum account"
+	sig := personalSign(t, message)
+	zeroBased := append([]byte(nil), sig...)
+	zeroBased[64] -= 27
+
+	for _, tc := range []struct {
+		name      string
+		signature string
+	}{
+		{"0x-prefixed", "0x" + hex.EncodeToString(sig)},
+		{"unprefixed", hex.EncodeToString(sig)},
+		{"recovery id 0 or 1", "0x" + hex.EncodeToString(zeroBased)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			signer, err := ethereum.RecoverPersonalSigner(message, tc.signature)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signer != signerAddress {
+				t.Fatalf("signer = %s, want %s", signer, signerAddress)
+			}
+		})
+	}
+
+	// Another message recovers some other address.
+	if signer, err := ethereum.RecoverPersonalSigner(message+".", "0x"+hex.EncodeToString(sig)); err == nil && signer == signerAddress {
+		t.Fatal("the signature verified a different message")
+	}
+}
+
+func TestRecoverPersonalSignerRejectsMalformedSignatures(t *testing.T) {
+	sig := personalSign(t, "hello")
+	badV := append([]byte(nil), sig...)
+	badV[64] = 29
+
+	for _, tc := range []struct {
+		name      string
+		signature string
+	}{
+		{"empty", ""},
+		{"not hex", "0xzz"},
+		{"too short", "0x" + hex.EncodeToString(sig[:64])},
+		{"too long", "0x" + hex.EncodeToString(append(sig, 0))},
+		{"invalid recovery id", "0x" + hex.EncodeToString(badV)},
+		{"zero R and S", "0x" + hex.EncodeToString(make([]byte, 64)) + "1b"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ethereum.RecoverPersonalSigner("hello", tc.signature); !errors.Is(err, ethereum.ErrInvalidSignature) {
+				t.Fatalf("error = %v, want %v", err, ethereum.ErrInvalidSignature)
+			}
+		})
+	}
+}
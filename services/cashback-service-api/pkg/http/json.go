@@ -3,10 +3,10 @@ package http
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
 
+	"github.com/cashback-platform/pkg/logger"
 	"github.com/cashback-platform/services/cashback-service-api/pkg/apperror"
-	"github.com/cashback-platform/services/cashback-service-api/pkg/logger"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
 )
 
 func WriteJSON(w http.ResponseWriter, statusCode int, payload any) {
@@ -22,14 +22,21 @@ func WriteJSON(w http.ResponseWriter, statusCode int, payload any) {
 
 func ReadJSON(r *http.Request, payload any) error {
 	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
-		logger.Error("failed to decode JSON request", "error", err)
+		logger.ErrorContext(r.Context(), "failed to decode JSON request", "error", err)
 		return err
 	}
 	return nil
 }
 
+// WriteError writes err as a problem document with the given status. Prefer
+// errorhandler.Render, which takes the code and status from the registry.
 func WriteError(w http.ResponseWriter, statusCode int, err error) {
-	statusText := strings.ToUpper(strings.ReplaceAll(http.StatusText(statusCode), " ", "_"))
-	appErr := apperror.New(statusText, "%s", err.Error())
-	WriteJSON(w, statusCode, appErr)
+	appErr := apperror.New(errorhandler.CodeForStatus(statusCode), "%s", err.Error())
+	errorhandler.Write(w, errorhandler.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(statusCode),
+		Status: statusCode,
+		Detail: appErr.Message,
+		Code:   appErr.Code,
+	})
 }
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/openapi"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// Query parameters shared by all list endpoints.
+const (
+	LimitParam  = "limit"
+	CursorParam = "cursor"
+	SortParam   = "sort"
+)
+
+var (
+	ErrInvalidLimit  = fmt.Errorf("limit must be between 1 and %d", pagination.MaxLimit)
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("sort must be created_at or -created_at")
+	ErrInvalidTime   = errors.New("must be an RFC 3339 timestamp")
+)
+
+type (
+	// PageInfo is the pagination part of every list response.
+	PageInfo struct {
+		NextCursor string `json:"next_cursor,omitempty"`
+		HasMore    bool   `json:"has_more"`
+	}
+
+	cursorToken struct {
+		CreatedAt time.Time `json:"t"`
+		ID        uuid.UUID `json:"id"`
+	}
+)
+
+// ParsePageRequest reads limit, cursor and sort from the query string. Lists
+// are sorted by creation time, newest first (sort=-created_at) by default.
+// Invalid parameters are reported as an errorhandler.ValidationError.
+func ParsePageRequest(r *http.Request) (pagination.Request, error) {
+	query := r.URL.Query()
+	req := pagination.Request{Limit: pagination.DefaultLimit}
+
+	if v := query.Get(LimitParam); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > pagination.MaxLimit {
+			return pagination.Request{}, errorhandler.Invalid(LimitParam, ErrInvalidLimit)
+		}
+		req.Limit = limit
+	}
+
+	if v := query.Get(CursorParam); v != "" {
+		cursor, err := DecodeCursor(v)
+		if err != nil {
+			return pagination.Request{}, errorhandler.Invalid(CursorParam, err)
+		}
+		req.After = &cursor
+	}
+
+	switch query.Get(SortParam) {
+	case "", "-created_at":
+	case "created_at":
+		req.Ascending = true
+	default:
+		return pagination.Request{}, errorhandler.Invalid(SortParam, ErrInvalidSort)
+	}
+
+	return req, nil
+}
+
+// PageParameters documents limit, cursor and sort, followed by the filters of
+// a list endpoint.
+func PageParameters(filters ...openapi.Parameter) []openapi.Parameter {
+	return append([]openapi.Parameter{
+		openapi.Query(LimitParam, fmt.Sprintf("Page size, 1 to %d (default %d)", pagination.MaxLimit, pagination.DefaultLimit), "integer", ""),
+		openapi.Query(CursorParam, "next_cursor of the previous page", "string", ""),
+		openapi.Query(SortParam, "created_at, or -created_at for newest first (default)", "string", ""),
+	}, filters...)
+}
+
+// ParseTimeParam reads an RFC 3339 timestamp from the query string. It
+// returns the zero time when the parameter is absent, and an
+// errorhandler.ValidationError when it is malformed.
+func ParseTimeParam(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, errorhandler.Invalid(name, ErrInvalidTime)
+	}
+	return t, nil
+}
+
+// ParseListParam reads a comma-separated or repeated query parameter.
+func ParseListParam(r *http.Request, name string) []string {
+	var values []string
+	for _, v := range r.URL.Query()[name] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// NewPageInfo describes a page for the response, encoding its next cursor.
+func NewPageInfo(next *pagination.Cursor) PageInfo {
+	if next == nil {
+		return PageInfo{}
+	}
+	return PageInfo{NextCursor: EncodeCursor(*next), HasMore: true}
+}
+
+// EncodeCursor turns a cursor into the opaque token clients pass back.
+func EncodeCursor(c pagination.Cursor) string {
+	b, _ := json.Marshal(cursorToken{CreatedAt: c.CreatedAt, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor.
+func DecodeCursor(token string) (pagination.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return pagination.Cursor{}, ErrInvalidCursor
+	}
+
+	var c cursorToken
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return pagination.Cursor{}, ErrInvalidCursor
+	}
+	return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}, nil
+}
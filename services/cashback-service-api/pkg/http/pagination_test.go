@@ -0,0 +1,162 @@
+package http_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	httpjson "github.com/cashback-platform/services/cashback-service-api/pkg/http"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := pagination.Cursor{
+		CreatedAt: time.Date(2026, 3, 14, 15, 9, 26, 535897000, time.UTC),
+		ID:        uuid.MustParse("2f1c6b8e-1d7a-4a57-9c1e-3b2f5a6d7e8f"),
+	}
+
+	token := httpjson.EncodeCursor(want)
+	got, err := httpjson.DecodeCursor(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Fatalf("cursor = %+v, want %+v", got, want)
+	}
+
+	// Tokens travel in query strings unescaped.
+	if url.QueryEscape(token) != token {
+		t.Fatalf("token %q is not URL-safe", token)
+	}
+}
+
+func TestDecodeCursorRejectsMalformedTokens(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	for _, tc := range []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "%%%"},
+		{"padded base64", encode(`{"t":"2026-03-14T15:09:26Z","id":"2f1c6b8e-1d7a-4a57-9c1e-3b2f5a6d7e8f"}`) + "="},
+		{"not JSON", encode("created_at")},
+		{"missing id", encode(`{"t":"2026-03-14T15:09:26Z"}`)},
+		{"nil id", encode(`{"t":"2026-03-14T15:09:26Z","id":"00000000-0000-0000-0000-000000000000"}`)},
+		{"missing time", encode(`{"id":"2f1c6b8e-1d7a-4a57-9c1e-3b2f5a6d7e8f"}`)},
+		{"bad time", encode(`{"t":"yesterday","id":"2f1c6b8e-1d7a-4a57-9c1e-3b2f5a6d7e8f"}`)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := httpjson.DecodeCursor(tc.token); !errors.Is(err, httpjson.ErrInvalidCursor) {
+				t.Fatalf("error = %v, want %v", err, httpjson.ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestParsePageRequest(t *testing.T) {
+	cursor := pagination.Cursor{
+		CreatedAt: time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC),
+		ID:        uuid.MustParse("2f1c6b8e-1d7a-4a57-9c1e-3b2f5a6d7e8f"),
+	}
+	token := httpjson.EncodeCursor(cursor)
+
+	for _, tc := range []struct {
+		name  string
+		query string
+		want  pagination.Request
+		field string
+		err   error
+	}{
+		{name: "defaults", want: pagination.Request{Limit: pagination.DefaultLimit}},
+		{name: "limit", query: "limit=5", want: pagination.Request{Limit: 5}},
+		{name: "largest limit", query: "limit=100", want: pagination.Request{Limit: pagination.MaxLimit}},
+		{name: "cursor", query: "cursor=" + token, want: pagination.Request{Limit: pagination.DefaultLimit, After: &cursor}},
+		{name: "newest first", query: "sort=-created_at", want: pagination.Request{Limit: pagination.DefaultLimit}},
+		{name: "oldest first", query: "sort=created_at", want: pagination.Request{Limit: pagination.DefaultLimit, Ascending: true}},
+		{name: "zero limit", query: "limit=0", field: "limit", err: httpjson.ErrInvalidLimit},
+		{name: "limit too large", query: "limit=101", field: "limit", err: httpjson.ErrInvalidLimit},
+		{name: "limit not a number", query: "limit=ten", field: "limit", err: httpjson.ErrInvalidLimit},
+		{name: "bad cursor", query: "cursor=abc", field: "cursor", err: httpjson.ErrInvalidCursor},
+		{name: "unknown sort", query: "sort=amount", field: "sort", err: httpjson.ErrInvalidSort},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := httpjson.ParsePageRequest(httptest.NewRequest(http.MethodGet, "/cashbacks?"+tc.query, nil))
+			if tc.err != nil {
+				assertInvalid(t, err, tc.field, tc.err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Limit != tc.want.Limit || got.Ascending != tc.want.Ascending || !reflect.DeepEqual(got.After, tc.want.After) {
+				t.Fatalf("request = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeParam(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		query string
+		want  time.Time
+		err   bool
+	}{
+		{"absent", "", time.Time{}, false},
+		{"UTC", "from=2026-03-14T15:09:26Z", time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC), false},
+		{"offset", "from=2026-03-14T17:09:26%2B02:00", time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC), false},
+		{"date only", "from=2026-03-14", time.Time{}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := httpjson.ParseTimeParam(httptest.NewRequest(http.MethodGet, "/cashbacks?"+tc.query, nil), "from")
+			if tc.err {
+				assertInvalid(t, err, "from", httpjson.ErrInvalidTime)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("time = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseListParam(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/cashbacks?status=pending,+completed&status=failed&status=,", nil)
+	want := []string{"pending", "completed", "failed"}
+	if got := httpjson.ParseListParam(req, "status"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("values = %q, want %q", got, want)
+	}
+}
+
+func TestNewPageInfo(t *testing.T) {
+	if info := httpjson.NewPageInfo(nil); info.HasMore || info.NextCursor != "" {
+		t.Fatalf("last page = %+v", info)
+	}
+
+	next := pagination.Cursor{CreatedAt: time.Now().UTC(), ID: uuid.New()}
+	info := httpjson.NewPageInfo(&next)
+	if !info.HasMore || info.NextCursor != httpjson.EncodeCursor(next) {
+		t.Fatalf("page = %+v", info)
+	}
+}
+
+func assertInvalid(t *testing.T, err error, field string, want error) {
+	t.Helper()
+	var validation *errorhandler.ValidationError
+	if !errors.As(err, &validation) || len(validation.Fields) != 1 {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+	if f := validation.Fields[0]; f.Field != field || f.Message != want.Error() {
+		t.Fatalf("field %s: %s, want %s: %s", f.Field, f.Message, field, want)
+	}
+}
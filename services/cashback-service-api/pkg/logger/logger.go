@@ -1,38 +0,0 @@
-package logger
-
-import (
-	"log/slog"
-	"os"
-)
-
-var logger *slog.Logger
-
-func Init() {
-	logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-}
-
-func Info(msg string, args ...any) {
-	if logger != nil {
-		logger.Info(msg, args...)
-	}
-}
-
-func Error(msg string, args ...any) {
-	if logger != nil {
-		logger.Error(msg, args...)
-	}
-}
-
-func Debug(msg string, args ...any) {
-	if logger != nil {
-		logger.Debug(msg, args...)
-	}
-}
-
-func Warn(msg string, args ...any) {
-	if logger != nil {
-		logger.Warn(msg, args...)
-	}
-}
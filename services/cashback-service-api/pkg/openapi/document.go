@@ -0,0 +1,260 @@
+// Package openapi builds an OpenAPI 3.1 document from the operations that
+// handlers describe next to their routes, and validates request bodies
+// against it.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.1.0"
+
+const jsonContentType = "application/json"
+
+type (
+	// Operation describes one endpoint. Request and the Responses values are
+	// DTO values whose types are turned into JSON schemas; Request is nil for
+	// endpoints without a body.
+	Operation struct {
+		ID        string
+		Method    string
+		Path      string
+		Summary   string
+		Tag       string
+		Query     []Parameter
+		Request   any
+		Responses map[int]any
+	}
+
+	// Document is an OpenAPI document.
+	Document struct {
+		OpenAPI    string                `json:"openapi"`
+		Info       Info                  `json:"info"`
+		Servers    []Server              `json:"servers,omitempty"`
+		Security   []map[string][]string `json:"security,omitempty"`
+		Paths      map[string]PathItem   `json:"paths"`
+		Components Components            `json:"components"`
+
+		routes []route
+	}
+
+	Info struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+
+	Server struct {
+		URL string `json:"url"`
+	}
+
+	// PathItem holds the operations of one path, keyed by lower-case method.
+	PathItem map[string]*PathOperation
+
+	PathOperation struct {
+		OperationID string              `json:"operationId"`
+		Summary     string              `json:"summary,omitempty"`
+		Tags        []string            `json:"tags,omitempty"`
+		Parameters  []Parameter         `json:"parameters,omitempty"`
+		RequestBody *RequestBody        `json:"requestBody,omitempty"`
+		Responses   map[string]Response `json:"responses"`
+	}
+
+	Parameter struct {
+		Name        string  `json:"name"`
+		In          string  `json:"in"`
+		Description string  `json:"description,omitempty"`
+		Required    bool    `json:"required,omitempty"`
+		Schema      *Schema `json:"schema"`
+	}
+
+	RequestBody struct {
+		Required bool                 `json:"required"`
+		Content  map[string]MediaType `json:"content"`
+	}
+
+	Response struct {
+		Description string               `json:"description"`
+		Content     map[string]MediaType `json:"content,omitempty"`
+	}
+
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+
+	Components struct {
+		Schemas         map[string]*Schema        `json:"schemas"`
+		SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	}
+
+	SecurityScheme struct {
+		Type         string `json:"type"`
+		Scheme       string `json:"scheme,omitempty"`
+		BearerFormat string `json:"bearerFormat,omitempty"`
+		In           string `json:"in,omitempty"`
+		Name         string `json:"name,omitempty"`
+	}
+
+	route struct {
+		method    string
+		segments  []string
+		operation *PathOperation
+	}
+)
+
+// Query describes a query parameter of the given type and format.
+func Query(name, description, typ, format string) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      &Schema{Type: Types{typ}, Format: format},
+	}
+}
+
+// New builds a document from operations. Every error response is described
+// by the errorhandler.Problem schema. Operations must have unique IDs and
+// method and path combinations.
+func New(info Info, servers []Server, operations []Operation) (*Document, error) {
+	g := newGenerator()
+	problem := g.schema(errorhandler.Problem{})
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Servers: servers,
+		Paths:   make(map[string]PathItem),
+	}
+
+	ids := make(map[string]bool)
+	for _, op := range operations {
+		if ids[op.ID] {
+			return nil, fmt.Errorf("openapi: duplicate operation ID %q", op.ID)
+		}
+		ids[op.ID] = true
+
+		method := strings.ToLower(op.Method)
+		item := doc.Paths[op.Path]
+		if item == nil {
+			item = make(PathItem)
+			doc.Paths[op.Path] = item
+		}
+		if item[method] != nil {
+			return nil, fmt.Errorf("openapi: %s %s is described twice", op.Method, op.Path)
+		}
+
+		pathOp := &PathOperation{
+			OperationID: op.ID,
+			Summary:     op.Summary,
+			Parameters:  append(pathParameters(op.Path), op.Query...),
+			Responses: map[string]Response{
+				"default": {
+					Description: "Error",
+					Content:     map[string]MediaType{errorhandler.ContentType: {Schema: problem}},
+				},
+			},
+		}
+		if op.Tag != "" {
+			pathOp.Tags = []string{op.Tag}
+		}
+		if op.Request != nil {
+			pathOp.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{jsonContentType: {Schema: g.schema(op.Request)}},
+			}
+		}
+		for status, body := range op.Responses {
+			response := Response{Description: http.StatusText(status)}
+			if body != nil {
+				response.Content = map[string]MediaType{jsonContentType: {Schema: g.schema(body)}}
+			}
+			pathOp.Responses[fmt.Sprint(status)] = response
+		}
+
+		item[method] = pathOp
+		doc.routes = append(doc.routes, route{
+			method:    op.Method,
+			segments:  splitPath(op.Path),
+			operation: pathOp,
+		})
+	}
+
+	doc.Components.Schemas = g.schemas
+	return doc, nil
+}
+
+// Operations lists the method and path of every operation, sorted.
+func (d *Document) Operations() []string {
+	var ops []string
+	for path, item := range d.Paths {
+		for method := range item {
+			ops = append(ops, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// Find returns the operation serving method and path, or nil. Static path
+// segments win over parameters, as in the router.
+func (d *Document) Find(method, path string) *PathOperation {
+	segments := splitPath(path)
+
+	var best *PathOperation
+	bestStatic := -1
+	for _, rt := range d.routes {
+		if rt.method != method || len(rt.segments) != len(segments) {
+			continue
+		}
+		static, ok := matchSegments(rt.segments, segments)
+		if ok && static > bestStatic {
+			best, bestStatic = rt.operation, static
+		}
+	}
+	return best
+}
+
+func matchSegments(pattern, segments []string) (int, bool) {
+	static := 0
+	for i, p := range pattern {
+		if isParam(p) {
+			continue
+		}
+		if p != segments[i] {
+			return 0, false
+		}
+		static++
+	}
+	return static, true
+}
+
+// pathParameters describes the {name} parameters of path. IDs are UUIDs.
+func pathParameters(path string) []Parameter {
+	var params []Parameter
+	for _, segment := range splitPath(path) {
+		if !isParam(segment) {
+			continue
+		}
+		name := strings.Trim(segment, "{}")
+		schema := &Schema{Type: Types{"string"}}
+		if name == "id" || strings.HasSuffix(name, "_id") {
+			schema.Format = "uuid"
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	return params
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
@@ -0,0 +1,162 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const refPrefix = "#/components/schemas/"
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+type (
+	// Schema is a JSON schema. Structs become component schemas referenced
+	// through Ref, named after their package and type.
+	Schema struct {
+		Ref                  string             `json:"$ref,omitempty"`
+		Type                 Types              `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	}
+
+	// Types is the type keyword: a single type, or a list when the value may
+	// also be null.
+	Types []string
+
+	generator struct {
+		schemas map[string]*Schema
+	}
+)
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: make(map[string]*Schema)}
+}
+
+func (g *generator) schema(v any) *Schema {
+	return g.schemaFor(reflect.TypeOf(v))
+}
+
+// schemaFor maps t to a schema the way encoding/json encodes it. Struct
+// fields are required unless they are pointers or tagged omitempty; a format
+// tag sets the format of string fields.
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: Types{"string"}, Format: "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schemaFor(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{"string"}, Format: "byte"}
+		}
+		return &Schema{Type: Types{"array"}, Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.objectSchema(t)
+	}
+
+	name := path.Base(t.PkgPath()) + "." + t.Name()
+	ref := &Schema{Ref: refPrefix + name}
+	if _, ok := g.schemas[name]; ok {
+		return ref
+	}
+
+	// Reserve the name first so recursive types terminate.
+	g.schemas[name] = &Schema{}
+	g.schemas[name] = g.objectSchema(t)
+	return ref
+}
+
+func (g *generator) objectSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 Types{"object"},
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(s, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schemaFor(field.Type)
+		if format := field.Tag.Get("format"); format != "" {
+			property.Format = format
+		}
+		s.Properties[name] = property
+
+		optional := field.Type.Kind() == reflect.Pointer || strings.Contains(options, "omitempty")
+		if !optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" || len(s.Type) == 0 {
+		return s
+	}
+	copied := *s
+	copied.Type = append(Types{}, s.Type...)
+	copied.Type = append(copied.Type, "null")
+	return &copied
+}
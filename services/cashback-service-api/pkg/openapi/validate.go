@@ -0,0 +1,212 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/errorhandler"
+	"github.com/cashback-platform/services/cashback-service-api/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidType   = errors.New("invalid type")
+	ErrInvalidFormat = errors.New("invalid format")
+	ErrUnknownField  = errors.New("unknown field")
+)
+
+// Middleware rejects requests whose JSON body does not match the request
+// schema of their operation, listing every offending field. Requests to
+// paths the document does not describe pass through.
+func Middleware(doc *Document) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			op := doc.Find(r.Method, routePath(r))
+			if op == nil || op.RequestBody == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if ct := r.Header.Get("Content-Type"); ct != "" {
+				if mediaType, _, err := mime.ParseMediaType(ct); err != nil || mediaType != jsonContentType {
+					errorhandler.Render(w, r, errorhandler.ErrUnsupportedMediaType)
+					return
+				}
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				errorhandler.Render(w, r, errorhandler.ErrInvalidPayload)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if err := doc.ValidateBody(op, body); err != nil {
+				errorhandler.Render(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ValidateBody checks body against the request schema of op. Malformed JSON
+// is reported as errorhandler.ErrInvalidPayload, schema violations as an
+// errorhandler.ValidationError.
+func (d *Document) ValidateBody(op *PathOperation, body []byte) error {
+	if op.RequestBody == nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return errorhandler.ErrInvalidPayload
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errorhandler.ErrInvalidPayload
+	}
+
+	schema := d.resolve(op.RequestBody.Content[jsonContentType].Schema)
+	if _, ok := value.(map[string]any); !ok && schema.Type.has("object") {
+		return errorhandler.ErrInvalidPayload
+	}
+
+	var v errorhandler.ValidationError
+	d.validate(schema, value, "", &v)
+	return v.Err()
+}
+
+func (d *Document) validate(s *Schema, value any, field string, v *errorhandler.ValidationError) {
+	s = d.resolve(s)
+	if len(s.Type) > 0 && !s.Type.matches(value) {
+		v.Add(field, fmt.Errorf("%w: expected %s", ErrInvalidType, s.Type[0]))
+		return
+	}
+
+	switch value := value.(type) {
+	case string:
+		v.Add(field, checkFormat(s.Format, value))
+	case []any:
+		if s.Items != nil {
+			for i, item := range value {
+				d.validate(s.Items, item, field+"["+strconv.Itoa(i)+"]", v)
+			}
+		}
+	case map[string]any:
+		d.validateObject(s, value, field, v)
+	}
+}
+
+func (d *Document) validateObject(s *Schema, value map[string]any, field string, v *errorhandler.ValidationError) {
+	for _, name := range s.Required {
+		if _, ok := value[name]; !ok {
+			v.Add(join(field, name), validator.ErrRequired)
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := s.Properties[name]; ok {
+			d.validate(property, value[name], join(field, name), v)
+			continue
+		}
+		switch additional := s.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				v.Add(join(field, name), ErrUnknownField)
+			}
+		case *Schema:
+			d.validate(additional, value[name], join(field, name), v)
+		}
+	}
+}
+
+func (d *Document) resolve(s *Schema) *Schema {
+	if s.Ref == "" {
+		return s
+	}
+	if resolved, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, refPrefix)]; ok {
+		return resolved
+	}
+	return &Schema{}
+}
+
+func (t Types) has(typ string) bool {
+	for _, candidate := range t {
+		if candidate == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Types) matches(value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return t.has("null")
+	case bool:
+		return t.has("boolean")
+	case string:
+		return t.has("string")
+	case json.Number:
+		if t.has("number") {
+			return true
+		}
+		_, err := value.Int64()
+		return t.has("integer") && err == nil
+	case []any:
+		return t.has("array")
+	case map[string]any:
+		return t.has("object")
+	}
+	return false
+}
+
+func checkFormat(format, value string) error {
+	switch format {
+	case "uuid":
+		if _, err := uuid.Parse(value); err != nil {
+			return errorhandler.ErrInvalidUUID
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("%w: expected an RFC 3339 timestamp", ErrInvalidFormat)
+		}
+	}
+	return nil
+}
+
+// routePath is the request path below the router the middleware is mounted
+// on, which is what operation paths are relative to.
+func routePath(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		return rctx.RoutePath
+	}
+	return r.URL.Path
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
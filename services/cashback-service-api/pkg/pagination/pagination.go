@@ -0,0 +1,59 @@
+// Package pagination describes keyset pages over records ordered by
+// (created_at, id). Keysets stay stable while rows are inserted and cost the
+// same on every page, unlike offsets.
+package pagination
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type (
+	// Cursor is the position of a record in (created_at, id) order.
+	Cursor struct {
+		CreatedAt time.Time
+		ID        uuid.UUID
+	}
+
+	// Request asks for up to Limit records after the After cursor, newest
+	// first unless Ascending is set.
+	Request struct {
+		Limit     int
+		After     *Cursor
+		Ascending bool
+	}
+
+	// Page holds one page of records and the cursor to the next one, which
+	// is nil on the last page.
+	Page[T any] struct {
+		Items []T
+		Next  *Cursor
+	}
+)
+
+// NewPage builds a page from records fetched with a limit of limit+1, which
+// tells whether another page follows without a separate count.
+func NewPage[T any](items []T, limit int, cursor func(T) Cursor) Page[T] {
+	if len(items) <= limit {
+		return Page[T]{Items: items}
+	}
+
+	items = items[:limit]
+	next := cursor(items[limit-1])
+	return Page[T]{Items: items, Next: &next}
+}
+
+// Map converts the items of a page, keeping its cursor.
+func Map[T, U any](page Page[T], convert func(T) U) Page[U] {
+	items := make([]U, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = convert(item)
+	}
+	return Page[U]{Items: items, Next: page.Next}
+}
@@ -0,0 +1,66 @@
+package pagination_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+type record struct {
+	n         int
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+func records(n int) []record {
+	start := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	items := make([]record, n)
+	for i := range items {
+		items[i] = record{n: i, createdAt: start.Add(time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+	return items
+}
+
+func cursorOf(r record) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: r.createdAt, ID: r.id}
+}
+
+func TestNewPage(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		fetched int
+		items   int
+		more    bool
+	}{
+		{"empty", 0, 0, false},
+		{"short page", 2, 2, false},
+		{"exactly full", 3, 3, false},
+		{"one more fetched", 4, 3, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fetched := records(tc.fetched)
+			page := pagination.NewPage(fetched, 3, cursorOf)
+
+			if len(page.Items) != tc.items || (page.Next != nil) != tc.more {
+				t.Fatalf("%d items, next %v; want %d items, more %v", len(page.Items), page.Next, tc.items, tc.more)
+			}
+			// The next page starts after the last item returned, not after
+			// the extra one fetched.
+			if tc.more && *page.Next != cursorOf(fetched[tc.items-1]) {
+				t.Fatalf("next = %+v, want the cursor of item %d", *page.Next, tc.items-1)
+			}
+		})
+	}
+}
+
+func TestMapKeepsTheCursor(t *testing.T) {
+	page := pagination.NewPage(records(3), 2, cursorOf)
+	mapped := pagination.Map(page, func(r record) string { return strconv.Itoa(r.n) })
+
+	if len(mapped.Items) != 2 || mapped.Items[0] != "0" || mapped.Items[1] != "1" || mapped.Next != page.Next {
+		t.Fatalf("mapped page = %+v", mapped)
+	}
+}
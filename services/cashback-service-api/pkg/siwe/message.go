@@ -0,0 +1,130 @@
+// Package siwe builds and parses Sign-In with Ethereum (EIP-4361) messages.
+package siwe
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	version       = "1"
+	headerSuffix  = " wants you to sign in with your Ethereum account:"
+	timeFormat    = time.RFC3339
+	minNonceChars = 8
+)
+
+var ErrInvalidMessage = errors.New("invalid EIP-4361 message")
+
+// Message holds the fields of an EIP-4361 message used by the platform.
+type Message struct {
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	ChainID        int
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+}
+
+// String renders the message in the EIP-4361 text format that wallets sign.
+func (m Message) String() string {
+	var b strings.Builder
+
+	b.WriteString(m.Domain + headerSuffix + "\n")
+	b.WriteString(m.Address + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n\n")
+	}
+	b.WriteString("URI: " + m.URI + "\n")
+	b.WriteString("Version: " + version + "\n")
+	b.WriteString("Chain ID: " + strconv.Itoa(m.ChainID) + "\n")
+	b.WriteString("Nonce: " + m.Nonce + "\n")
+	b.WriteString("Issued At: " + m.IssuedAt.UTC().Format(timeFormat))
+	if !m.ExpirationTime.IsZero() {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime.UTC().Format(timeFormat))
+	}
+
+	return b.String()
+}
+
+// Parse reads an EIP-4361 message. Optional fields the platform never issues
+// (Not Before, Request ID, Resources) are rejected.
+func Parse(text string) (Message, error) {
+	lines := strings.Split(text, "\n")
+	if len(lines) < 7 || !strings.HasSuffix(lines[0], headerSuffix) {
+		return Message{}, ErrInvalidMessage
+	}
+
+	m := Message{
+		Domain:  strings.TrimSuffix(lines[0], headerSuffix),
+		Address: lines[1],
+	}
+	if m.Domain == "" || lines[2] != "" {
+		return Message{}, ErrInvalidMessage
+	}
+
+	rest := lines[3:]
+	if !strings.HasPrefix(rest[0], "URI: ") {
+		if len(rest) < 2 || rest[1] != "" {
+			return Message{}, ErrInvalidMessage
+		}
+		m.Statement = rest[0]
+		rest = rest[2:]
+	}
+
+	if err := m.parseFields(rest); err != nil {
+		return Message{}, err
+	}
+	return m, nil
+}
+
+func (m *Message) parseFields(lines []string) error {
+	fields := make(map[string]string, len(lines))
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return ErrInvalidMessage
+		}
+		fields[key] = value
+	}
+
+	if fields["Version"] != version {
+		return fmt.Errorf("%w: unsupported version", ErrInvalidMessage)
+	}
+
+	chainID, err := strconv.Atoi(fields["Chain ID"])
+	if err != nil {
+		return fmt.Errorf("%w: invalid chain ID", ErrInvalidMessage)
+	}
+
+	issuedAt, err := time.Parse(timeFormat, fields["Issued At"])
+	if err != nil {
+		return fmt.Errorf("%w: invalid issued at", ErrInvalidMessage)
+	}
+
+	var expiration time.Time
+	if value, ok := fields["Expiration Time"]; ok {
+		if expiration, err = time.Parse(timeFormat, value); err != nil {
+			return fmt.Errorf("%w: invalid expiration time", ErrInvalidMessage)
+		}
+	}
+
+	known := 5
+	if !expiration.IsZero() {
+		known++
+	}
+	if len(fields) != known || fields["URI"] == "" || len(fields["Nonce"]) < minNonceChars {
+		return ErrInvalidMessage
+	}
+
+	m.URI = fields["URI"]
+	m.ChainID = chainID
+	m.Nonce = fields["Nonce"]
+	m.IssuedAt = issuedAt
+	m.ExpirationTime = expiration
+	return nil
+}
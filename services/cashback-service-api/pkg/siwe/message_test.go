@@ -0,0 +1,97 @@
+package siwe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/siwe"
+)
+
+var issuedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func message() siwe.Message {
+	return siwe.Message{
+		Domain:         "cashback.example.com",
+		Address:        "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		Statement:      "Link this wallet to your cashback account.",
+		URI:            "https://cashback.example.com",
+		ChainID:        1,
+		Nonce:          "32891756abcdef01",
+		IssuedAt:       issuedAt,
+		ExpirationTime: issuedAt.Add(10 * time.Minute),
+	}
+}
+
+func TestStringRendersEIP4361(t *testing.T) {
+	want := "cashback.example.com wants you to sign in with your Ethereum account:\n" +
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed\n" +
+		"\n" +
+		"Link this wallet to your cashback account.\n" +
+		"\n" +
+		"URI: https://cashback.example.com\n" +
+		"Version: 1\n" +
+		"Chain ID: 1\n" +
+		"Nonce: 32891756abcdef01\n" +
+		"Issued At: 2026-03-01T12:00:00Z\n" +
+		"Expiration Time: 2026-03-01T12:10:00Z"
+	if got := message().String(); got != want {
+		t.Fatalf("message:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseReadsWhatStringWrites(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		change func(*siwe.Message)
+	}{
+		{"every field", func(*siwe.Message) {}},
+		{"no statement", func(m *siwe.Message) { m.Statement = "" }},
+		{"no expiration", func(m *siwe.Message) { m.ExpirationTime = time.Time{} }},
+		{"other time zone", func(m *siwe.Message) { m.IssuedAt = issuedAt.In(time.FixedZone("CET", 3600)) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := message()
+			tc.change(&m)
+
+			parsed, err := siwe.Parse(m.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.String() != m.String() || !parsed.IssuedAt.Equal(m.IssuedAt) {
+				t.Fatalf("parsed %+v, want %+v", parsed, m)
+			}
+		})
+	}
+}
+
+func TestParseRejectsMalformedMessages(t *testing.T) {
+	valid := message().String()
+
+	for _, tc := range []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"wrong header", strings.Replace(valid, "wants you to sign in", "asks you to sign in", 1)},
+		{"no domain", strings.TrimPrefix(valid, "cashback.example.com")},
+		{"no blank line after the address", strings.Replace(valid, "BeAed\n\n", "BeAed\n", 1)},
+		{"no blank line after the statement", strings.Replace(valid, "account.\n\n", "account.\n", 1)},
+		{"unsupported version", strings.Replace(valid, "Version: 1", "Version: 2", 1)},
+		{"invalid chain ID", strings.Replace(valid, "Chain ID: 1", "Chain ID: one", 1)},
+		{"invalid issued at", strings.Replace(valid, "Issued At: 2026-03-01T12:00:00Z", "Issued At: yesterday", 1)},
+		{"invalid expiration", strings.Replace(valid, "Expiration Time: 2026-03-01T12:10:00Z", "Expiration Time: soon", 1)},
+		{"short nonce", strings.Replace(valid, "Nonce: 32891756abcdef01", "Nonce: 1234", 1)},
+		{"empty URI", strings.Replace(valid, "URI: https://cashback.example.com", "URI: ", 1)},
+		{"field without a value", valid + "\nResources:"},
+		{"unsupported field", valid + "\nRequest ID: 42"},
+		{"not before", valid + "\nNot Before: 2026-03-01T12:00:00Z"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := siwe.Parse(tc.text); !errors.Is(err, siwe.ErrInvalidMessage) {
+				t.Fatalf("error = %v, want %v", err, siwe.ErrInvalidMessage)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package validator
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cashback-platform/services/cashback-service-api/pkg/ethereum"
+)
 
 var (
 	ErrInvalidEmail         = errors.New("invalid email")
@@ -18,12 +23,14 @@ func ValidateEmail(email string) error {
 	return nil
 }
 
+// ValidateWalletAddress rejects anything but a 0x-prefixed 20-byte hex address
+// and mixed-case addresses with a wrong EIP-55 checksum.
 func ValidateWalletAddress(address string) error {
 	if address == "" {
 		return ErrRequired
 	}
-	if len(address) < 20 {
-		return ErrInvalidWalletAddress
+	if err := ethereum.ValidateAddress(address); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidWalletAddress, err)
 	}
 	return nil
 }
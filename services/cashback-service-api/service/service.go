@@ -0,0 +1,80 @@
+// Package service assembles the cashback service from its modules. The api
+// command runs it on its own; the end-to-end harness boots it in-process next
+// to mint-consumer and blockchain-adapter.
+package service
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/cashback-service-api/cmd/api/modules"
+	"github.com/cashback-platform/services/cashback-service-api/internal/apispec"
+	"github.com/cashback-platform/services/cashback-service-api/internal/bootstrap"
+	"github.com/cashback-platform/services/cashback-service-api/internal/config"
+	"github.com/cashback-platform/services/cashback-service-api/internal/database"
+	"github.com/cashback-platform/services/cashback-service-api/internal/errorcodes"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/grpc"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging/outbox"
+	outboxrepo "github.com/cashback-platform/services/cashback-service-api/internal/infra/messaging/outbox/repository"
+	"github.com/cashback-platform/services/cashback-service-api/internal/infra/nats"
+
+	"go.uber.org/fx"
+)
+
+// Module is the whole service. Configuration is read from the environment
+// while the app is built, by fx.New.
+var Module = fx.Options(
+	bootstrap.Logger(),
+	// Infrastructure
+	bootstrap.Config,
+	bootstrap.Tracing,
+	bootstrap.Database,
+	bootstrap.Metrics,
+	fx.Provide(nats.NewNATSClient),
+	fx.Provide(grpc.NewBlockchainAdapterClient),
+	bootstrap.Idempotency,
+	bootstrap.RateLimit,
+	bootstrap.Router,
+	bootstrap.Server,
+	bootstrap.Health,
+	fx.Invoke(errorcodes.Register),
+	// Messaging (Outbox Pattern)
+	fx.Provide(outboxrepo.New),
+	fx.Provide(outbox.NewOutboxPublisher),
+	fx.Provide(func(op *outbox.OutboxPublisher) messaging.EventPublisher {
+		return op
+	}),
+	fx.Invoke(outbox.StartOutboxPublisher),
+	// Business Modules
+	modules.User,
+	modules.Merchant,
+	modules.Campaign,
+	modules.Purchase,
+	modules.Cashback,
+	// Runs after the module invokes, once every route is registered.
+	fx.Invoke(apispec.CheckRoutes),
+	// Last, so that its stop hook runs first and readiness fails before
+	// anything else shuts down.
+	fx.Invoke(bootstrap.StartDrain),
+)
+
+// Migrate applies every pending migration to the database in the environment
+// and returns how many it applied.
+func Migrate(ctx context.Context) (int, error) {
+	log := bootstrap.NewLogger(config.LoadLog())
+	db, err := database.ConnectPostgres(config.LoadDatabase(), log)
+	if err != nil {
+		return 0, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, err
+	}
+	defer sqlDB.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return 0, err
+	}
+	return migrator.Up(ctx)
+}
@@ -1,38 +1,36 @@
 package main
 
 import (
-	"github.com/cashback-platform/services/mint-consumer/internal/config"
-	"github.com/cashback-platform/services/mint-consumer/internal/consumer"
-	"github.com/cashback-platform/services/mint-consumer/internal/infra/database"
-	"github.com/cashback-platform/services/mint-consumer/internal/infra/grpc"
-	"github.com/cashback-platform/services/mint-consumer/internal/infra/nats"
-	repoMintRequest "github.com/cashback-platform/services/mint-consumer/internal/repository/mintrequest"
-	repoProcessedEvent "github.com/cashback-platform/services/mint-consumer/internal/repository/processedevent"
-	"github.com/cashback-platform/services/mint-consumer/internal/usecase"
+	"log/slog"
+	"os"
+
+	"github.com/cashback-platform/services/mint-consumer/service"
 	"go.uber.org/fx"
 )
 
 func main() {
-	fx.New(
-		// Configuration
-		fx.Provide(config.NewConfig),
-
-		// Infrastructure
-		fx.Provide(database.NewPostgresDB),
-		fx.Provide(nats.NewNATSClient),
-		fx.Provide(grpc.NewBlockchainAdapterClient),
-
-		// Repositories
-		fx.Provide(repoMintRequest.NewRepository),
-		fx.Provide(repoProcessedEvent.NewRepository),
-
-		// Usecases
-		fx.Provide(usecase.NewMintUsecase),
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(os.Args[2:]); err != nil {
+			slog.Error("migrate failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if err := replayEvents(os.Args[2:]); err != nil {
+			slog.Error("replay failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-		// Consumer
-		fx.Provide(consumer.NewCashbackConsumer),
+	if len(os.Args) > 1 && os.Args[1] == "retry" {
+		if err := retryMint(os.Args[2:]); err != nil {
+			slog.Error("retry failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-		// Start consumer
-		fx.Invoke(consumer.StartConsumer),
-	).Run()
+	fx.New(service.Module).Run()
 }
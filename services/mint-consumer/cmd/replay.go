@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/consumer"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/database"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/grpc"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/nats"
+	"github.com/cashback-platform/services/mint-consumer/internal/replay"
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+	"github.com/cashback-platform/services/mint-consumer/internal/usecase"
+	"github.com/cashback-platform/services/mint-consumer/service"
+)
+
+const replayUsage = "usage: mint-consumer replay <handler> [--from-seq N | --from-time RFC3339] [--to-seq N] [--rebuild]"
+
+type (
+	// replayTarget is a subject and the handler its events are replayed through.
+	replayTarget struct {
+		stream  string
+		subject string
+		handle  replay.Handler
+	}
+
+	// replayHandler is what `replay <name>` runs. reset, when set, empties
+	// the read model the handler builds, for --rebuild.
+	replayHandler struct {
+		targets []replayTarget
+		reset   func(ctx context.Context) error
+	}
+)
+
+// replayHandlers are the handlers replay can feed events through, built
+// from the service's usecases.
+var replayHandlers = map[string]func(mint *usecase.MintUsecase, summary *usecase.SummaryUsecase) replayHandler{
+	"cashback-approved": func(mint *usecase.MintUsecase, _ *usecase.SummaryUsecase) replayHandler {
+		return replayHandler{targets: []replayTarget{
+			{consumer.CashbackStream, "cashback.approved", mint.ProcessCashbackApproved},
+		}}
+	},
+	"cashback-expired": func(mint *usecase.MintUsecase, _ *usecase.SummaryUsecase) replayHandler {
+		return replayHandler{targets: []replayTarget{
+			{consumer.CashbackStream, "cashback.expired", mint.ProcessCashbackExpired},
+		}}
+	},
+	"user-erased": func(mint *usecase.MintUsecase, _ *usecase.SummaryUsecase) replayHandler {
+		return replayHandler{targets: []replayTarget{
+			{consumer.UserStream, "user.erased", mint.ProcessUserErased},
+		}}
+	},
+	"cashback-summary": func(_ *usecase.MintUsecase, summary *usecase.SummaryUsecase) replayHandler {
+		return replayHandler{
+			targets: []replayTarget{
+				{consumer.CashbackStream, "cashback.approved", summary.ProjectCashbackApproved},
+				{consumer.CashbackStream, "cashback.expired", summary.ProjectCashbackExpired},
+			},
+			reset: summary.Reset,
+		}
+	},
+}
+
+// replayEvents runs the replay subcommand, feeding the events a handler
+// consumes through it again:
+//
+//	replay cashback-summary --rebuild       rebuild the user cashback summary
+//	replay cashback-expired --from-seq 120  re-apply expiries from sequence 120
+//
+// Handlers run in replay mode, so nothing is minted.
+func replayEvents(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s\nhandlers: %s", replayUsage, handlerNames())
+	}
+	name := args[0]
+	newHandler, ok := replayHandlers[name]
+	if !ok {
+		return fmt.Errorf("unknown handler %q\nhandlers: %s", name, handlerNames())
+	}
+
+	var (
+		opts     replay.Options
+		fromTime string
+		rebuild  bool
+		err      error
+	)
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Uint64Var(&opts.FromSeq, "from-seq", 0, "first stream sequence to replay")
+	flags.StringVar(&fromTime, "from-time", "", "replay events stored from this time on (RFC 3339)")
+	flags.Uint64Var(&opts.ToSeq, "to-seq", 0, "last stream sequence to replay")
+	flags.BoolVar(&rebuild, "rebuild", false, "empty the handler's read model first, then replay everything")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+		return errors.New(replayUsage)
+	}
+	if fromTime != "" {
+		if opts.FromTime, err = time.Parse(time.RFC3339, fromTime); err != nil {
+			return fmt.Errorf("invalid --from-time: %w", err)
+		}
+	}
+	if rebuild && (opts.FromSeq > 0 || !opts.FromTime.IsZero() || opts.ToSeq > 0) {
+		return errors.New("--rebuild replays everything the streams keep and takes no range")
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	log := service.NewLogger(cfg)
+	db, err := database.NewPostgresDB(cfg, log)
+	if err != nil {
+		return err
+	}
+	natsClient, err := nats.NewNATSClient(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer natsClient.Close()
+
+	handler := newHandler(
+		usecase.NewMintUsecase(repository.NewMintRequestRepository(db), replayMinter{}, natsClient, cfg, log),
+		usecase.NewSummaryUsecase(repository.NewCashbackSummaryRepository(db), log),
+	)
+
+	ctx := context.Background()
+	if rebuild {
+		if handler.reset == nil {
+			return fmt.Errorf("handler %s builds no read model to rebuild", name)
+		}
+		if err := handler.reset(ctx); err != nil {
+			return fmt.Errorf("failed to reset read model: %w", err)
+		}
+		log.Info("read model reset", "handler", name)
+	}
+
+	replayer := replay.NewReplayer(natsClient.JetStream(), log)
+	failed := 0
+	for _, target := range handler.targets {
+		opts.Stream, opts.Subject = target.stream, target.subject
+		result, err := replayer.Run(ctx, opts, target.handle)
+		if err != nil {
+			return err
+		}
+		failed += result.Failed
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d events failed to replay", failed)
+	}
+	return nil
+}
+
+// replayMinter stands in for the blockchain adapter, which replays never
+// reach: nothing is minted in replay mode.
+type replayMinter struct{}
+
+func (replayMinter) MintToken(_ context.Context, _, _, _ string) (*grpc.MintResult, error) {
+	return nil, fmt.Errorf("mint token: %w", replay.ErrSuppressed)
+}
+
+func handlerNames() string {
+	names := make([]string, 0, len(replayHandlers))
+	for name := range replayHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/database"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/grpc"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/nats"
+	"github.com/cashback-platform/services/mint-consumer/internal/metrics"
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+	"github.com/cashback-platform/services/mint-consumer/internal/usecase"
+	"github.com/cashback-platform/services/mint-consumer/service"
+	"github.com/google/uuid"
+)
+
+const retryUsage = "usage: mint-consumer retry [--dry-run] <mint-request-id>"
+
+// retryMint runs the retry subcommand, which mints a failed mint request
+// again at once through the blockchain adapter, granting it one more attempt
+// if its retries are used up:
+//
+//	retry 3f6c...            mint again and print the outcome
+//	retry --dry-run 3f6c...  print the request as it would be retried
+func retryMint(args []string) error {
+	var dryRun bool
+	flags := flag.NewFlagSet("retry", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&dryRun, "dry-run", false, "print the request as it would be retried without minting")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errors.New(retryUsage)
+	}
+	id, err := uuid.Parse(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid mint request ID %q\n%s", flags.Arg(0), retryUsage)
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	log := service.NewLogger(cfg)
+	db, err := database.NewPostgresDB(cfg, log)
+	if err != nil {
+		return err
+	}
+	natsClient, err := nats.NewNATSClient(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer natsClient.Close()
+	adapter, err := grpc.NewBlockchainAdapterClient(cfg, metrics.NewGRPCClient(metrics.NewRegistry()), log)
+	if err != nil {
+		return err
+	}
+	defer adapter.Close()
+
+	mint := usecase.NewMintUsecase(repository.NewMintRequestRepository(db), adapter, natsClient, cfg, log)
+	request, err := mint.RetryMint(context.Background(), id, dryRun)
+	if err != nil {
+		return fmt.Errorf("mint request %s: %w", id, err)
+	}
+
+	fmt.Printf("mint request: %s\ncashback:     %s\nstatus:       %s\nretries:      %d/%d\n",
+		request.ID, request.CashbackID, request.Status, request.RetryCount, request.MaxRetries)
+	if request.TransactionHash != "" {
+		fmt.Printf("transaction:  %s (block %d)\n", request.TransactionHash, request.BlockNumber)
+	}
+	if request.ErrorCode != "" {
+		fmt.Printf("last error:   %s %s\n", request.ErrorCode, request.ErrorMessage)
+	}
+	if dryRun {
+		fmt.Println("dry run: nothing was minted")
+	}
+	return nil
+}
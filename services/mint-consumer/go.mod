@@ -3,9 +3,15 @@ module github.com/cashback-platform/services/mint-consumer
 go 1.25
 
 require (
+	github.com/cashback-platform/pkg v0.0.0-00010101000000-000000000000
+	github.com/cashback-platform/proto v0.0.0-00010101000000-000000000000
 	github.com/google/uuid v1.5.0
 	github.com/nats-io/nats.go v1.31.0
+	github.com/prometheus/client_golang v1.18.0
 	github.com/spf13/viper v1.18.2
+	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.46.1
+	go.opentelemetry.io/otel v1.21.0
+	go.opentelemetry.io/otel/trace v1.21.0
 	go.uber.org/fx v1.20.1
 	google.golang.org/grpc v1.60.1
 	google.golang.org/protobuf v1.32.0
@@ -13,3 +19,26 @@ require (
 	gorm.io/gorm v1.25.5
 )
 
+require (
+	github.com/cenkalti/backoff/v4 v4.2.1 // indirect
+	github.com/go-logr/logr v1.3.0 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.16.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.21.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.21.0 // indirect
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.21.0 // indirect
+	go.opentelemetry.io/otel/metric v1.21.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.21.0 // indirect
+	go.opentelemetry.io/proto/otlp v1.0.0 // indirect
+	golang.org/x/net v0.19.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20231106174013-bbf56f31fb17 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20231120223509-83a465c0220f // indirect
+)
+
+replace (
+	github.com/cashback-platform/pkg => ../../pkg
+	github.com/cashback-platform/proto => ../../proto
+)
@@ -1,15 +1,26 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 type (
 	Config struct {
 		App      AppConfig
+		Log      LogConfig
 		Database DatabaseConfig
 		NATS     NATSConfig
 		GRPC     GRPCConfig
+		Mint     MintConfig
+		Erasure  ErasureConfig
+		Metrics  MetricsConfig
+		Tracing  TracingConfig
+		Health   HealthConfig
 	}
 
 	AppConfig struct {
@@ -17,6 +28,11 @@ type (
 		Env  string
 	}
 
+	// LogConfig sets the level below which records are dropped.
+	LogConfig struct {
+		Level slog.Level
+	}
+
 	DatabaseConfig struct {
 		Host     string
 		Port     string
@@ -33,6 +49,49 @@ type (
 	GRPCConfig struct {
 		BlockchainAdapterAddress string
 	}
+
+	// MintConfig controls how approved cashback is minted. Cashback amounts
+	// are scaled by 10^TokenDecimals into token units. A failed mint is
+	// retried after RetryBackoff, doubling with each failure, up to
+	// MaxRetries attempts; due retries are looked for every RetryInterval.
+	// An attempt that has not finished after AttemptTimeout is abandoned and
+	// retried, so it must be longer than the adapter's receipt timeout.
+	MintConfig struct {
+		TokenDecimals  int
+		MaxRetries     int
+		RetryInterval  time.Duration
+		RetryBackoff   time.Duration
+		AttemptTimeout time.Duration
+	}
+
+	// ErasureConfig controls how user.erased events are handled.
+	ErasureConfig struct {
+		ScrubWalletAddresses bool
+	}
+
+	// MetricsConfig sets the port /metrics is served on.
+	MetricsConfig struct {
+		Port string
+	}
+
+	// TracingConfig selects where spans are exported: none, otlp, stdout or
+	// file.
+	TracingConfig struct {
+		Exporter     string
+		OTLPEndpoint string
+		OTLPInsecure bool
+		File         string
+		SampleRatio  float64
+	}
+
+	// HealthConfig sets the port /livez and /readyz are served on, how long
+	// each readiness check may take, and how long readiness fails before the
+	// consumer stops on shutdown.
+	HealthConfig struct {
+		Port          string
+		CheckTimeout  time.Duration
+		ShutdownDelay time.Duration
+	}
 )
 
 func NewConfig() (*Config, error) {
@@ -47,6 +106,7 @@ func NewConfig() (*Config, error) {
 	// Defaults
 	viper.SetDefault("APP_NAME", "mint-consumer")
 	viper.SetDefault("APP_ENV", "development")
+	viper.SetDefault("LOG_LEVEL", "info")
 	viper.SetDefault("DATABASE_HOST", "localhost")
 	viper.SetDefault("DATABASE_PORT", "5432")
 	viper.SetDefault("DATABASE_USER", "postgres")
@@ -55,14 +115,39 @@ func NewConfig() (*Config, error) {
 	viper.SetDefault("DATABASE_SSLMODE", "disable")
 	viper.SetDefault("NATS_URL", "nats://localhost:4222")
 	viper.SetDefault("BLOCKCHAIN_ADAPTER_GRPC_ADDRESS", "localhost:50051")
+	viper.SetDefault("MINT_TOKEN_DECIMALS", 18)
+	viper.SetDefault("MINT_MAX_RETRIES", 5)
+	viper.SetDefault("MINT_RETRY_INTERVAL", "5s")
+	viper.SetDefault("MINT_RETRY_BACKOFF", "30s")
+	viper.SetDefault("MINT_ATTEMPT_TIMEOUT", "1m")
+	viper.SetDefault("ERASURE_SCRUB_WALLET_ADDRESSES", true)
+	viper.SetDefault("METRICS_PORT", "9091")
+
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_OTLP_ENDPOINT", "localhost:4317")
+	viper.SetDefault("TRACING_OTLP_INSECURE", true)
+	viper.SetDefault("TRACING_FILE", "traces.json")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+
+	viper.SetDefault("HEALTH_PORT", "8081")
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")
+	viper.SetDefault("HEALTH_SHUTDOWN_DELAY", "0s")
 
 	_ = viper.ReadInConfig()
 
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(strings.TrimSpace(viper.GetString("LOG_LEVEL")))); err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL: %w", err)
+	}
+
 	return &Config{
 		App: AppConfig{
 			Name: viper.GetString("APP_NAME"),
 			Env:  viper.GetString("APP_ENV"),
 		},
+		Log: LogConfig{
+			Level: logLevel,
+		},
 		Database: DatabaseConfig{
 			Host:     viper.GetString("DATABASE_HOST"),
 			Port:     viper.GetString("DATABASE_PORT"),
@@ -77,5 +162,30 @@ func NewConfig() (*Config, error) {
 		GRPC: GRPCConfig{
 			BlockchainAdapterAddress: viper.GetString("BLOCKCHAIN_ADAPTER_GRPC_ADDRESS"),
 		},
+		Mint: MintConfig{
+			TokenDecimals:  viper.GetInt("MINT_TOKEN_DECIMALS"),
+			MaxRetries:     viper.GetInt("MINT_MAX_RETRIES"),
+			RetryInterval:  viper.GetDuration("MINT_RETRY_INTERVAL"),
+			RetryBackoff:   viper.GetDuration("MINT_RETRY_BACKOFF"),
+			AttemptTimeout: viper.GetDuration("MINT_ATTEMPT_TIMEOUT"),
+		},
+		Erasure: ErasureConfig{
+			ScrubWalletAddresses: viper.GetBool("ERASURE_SCRUB_WALLET_ADDRESSES"),
+		},
+		Metrics: MetricsConfig{
+			Port: viper.GetString("METRICS_PORT"),
+		},
+		Tracing: TracingConfig{
+			Exporter:     viper.GetString("TRACING_EXPORTER"),
+			OTLPEndpoint: viper.GetString("TRACING_OTLP_ENDPOINT"),
+			OTLPInsecure: viper.GetBool("TRACING_OTLP_INSECURE"),
+			File:         viper.GetString("TRACING_FILE"),
+			SampleRatio:  viper.GetFloat64("TRACING_SAMPLE_RATIO"),
+		},
+		Health: HealthConfig{
+			Port:          viper.GetString("HEALTH_PORT"),
+			CheckTimeout:  viper.GetDuration("HEALTH_CHECK_TIMEOUT"),
+			ShutdownDelay: viper.GetDuration("HEALTH_SHUTDOWN_DELAY"),
+		},
 	}, nil
 }
@@ -2,27 +2,63 @@ package consumer
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
 	"github.com/cashback-platform/services/mint-consumer/internal/infra/nats"
+	"github.com/cashback-platform/services/mint-consumer/internal/metrics"
+	"github.com/cashback-platform/services/mint-consumer/internal/tracing"
 	"github.com/cashback-platform/services/mint-consumer/internal/usecase"
 	natsgo "github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 )
 
+// Streams the consumer reads from. cashback-service-api creates them.
+const (
+	CashbackStream = "CASHBACK_EVENTS"
+	UserStream     = "USER_EVENTS"
+)
+
+var tracer = otel.Tracer("github.com/cashback-platform/services/mint-consumer/internal/consumer")
+
 type CashbackConsumer struct {
-	mintUsecase *usecase.MintUsecase
-	natsClient  *nats.NATSClient
-	done        chan struct{}
-	sub         *natsgo.Subscription
+	mintUsecase        *usecase.MintUsecase
+	summaryUsecase     *usecase.SummaryUsecase
+	natsClient         *nats.NATSClient
+	retryInterval      time.Duration
+	metrics            *metrics.Consumer
+	log                *slog.Logger
+	done               chan struct{}
+	sub                *natsgo.Subscription
+	expiredSub         *natsgo.Subscription
+	erasedSub          *natsgo.Subscription
+	summaryApprovedSub *natsgo.Subscription
+	summaryExpiredSub  *natsgo.Subscription
 }
 
-func NewCashbackConsumer(mintUsecase *usecase.MintUsecase, natsClient *nats.NATSClient) *CashbackConsumer {
+func NewCashbackConsumer(
+	mintUsecase *usecase.MintUsecase,
+	summaryUsecase *usecase.SummaryUsecase,
+	natsClient *nats.NATSClient,
+	cfg *config.Config,
+	m *metrics.Consumer,
+	log *slog.Logger,
+) *CashbackConsumer {
 	return &CashbackConsumer{
-		mintUsecase: mintUsecase,
-		natsClient:  natsClient,
-		done:        make(chan struct{}),
+		mintUsecase:    mintUsecase,
+		summaryUsecase: summaryUsecase,
+		natsClient:     natsClient,
+		retryInterval:  cfg.Mint.RetryInterval,
+		metrics:        m,
+		log:            log,
+		done:           make(chan struct{}),
 	}
 }
 
@@ -38,9 +74,9 @@ func (c *CashbackConsumer) Start(ctx context.Context) error {
 		AckWait:       30 * time.Second,
 	}
 
-	_, err := js.AddConsumer("CASHBACK_EVENTS", consumerConfig)
+	_, err := js.AddConsumer(CashbackStream, consumerConfig)
 	if err != nil && err != natsgo.ErrConsumerNameAlreadyInUse {
-		log.Printf("Warning: Failed to create consumer: %v", err)
+		c.log.Warn("failed to create consumer", "consumer", "mint-consumer", "error", err)
 	}
 
 	sub, err := js.PullSubscribe("cashback.approved", "mint-consumer")
@@ -48,16 +84,78 @@ func (c *CashbackConsumer) Start(ctx context.Context) error {
 		return err
 	}
 	c.sub = sub
+	c.metrics.Watch("mint-consumer", sub)
+
+	expiredSub, err := c.subscribe(js, CashbackStream, "cashback.expired", "mint-consumer-expiry", natsgo.DeliverAllPolicy)
+	if err != nil {
+		return err
+	}
+	c.expiredSub = expiredSub
+
+	erasedSub, err := c.subscribe(js, UserStream, "user.erased", "mint-consumer-erasure", natsgo.DeliverAllPolicy)
+	if err != nil {
+		return err
+	}
+	c.erasedSub = erasedSub
+
+	// The summary durables start at new events; history is loaded with
+	// `mint-consumer replay cashback-summary`.
+	summaryApprovedSub, err := c.subscribe(js, CashbackStream, "cashback.approved", "mint-consumer-summary-approved", natsgo.DeliverNewPolicy)
+	if err != nil {
+		return err
+	}
+	c.summaryApprovedSub = summaryApprovedSub
+
+	summaryExpiredSub, err := c.subscribe(js, CashbackStream, "cashback.expired", "mint-consumer-summary-expired", natsgo.DeliverNewPolicy)
+	if err != nil {
+		return err
+	}
+	c.summaryExpiredSub = summaryExpiredSub
 
-	log.Println("Cashback consumer started, listening for cashback.approved events")
+	c.log.Info("listening for events", "subjects", []string{"cashback.approved", "cashback.expired", "user.erased"})
 
-	go c.processMessages(ctx)
+	go c.processMessages(ctx, c.sub, "mint-consumer", c.mintUsecase.ProcessCashbackApproved)
+	go c.processMessages(ctx, c.expiredSub, "mint-consumer-expiry", c.mintUsecase.ProcessCashbackExpired)
+	go c.processMessages(ctx, c.erasedSub, "mint-consumer-erasure", c.mintUsecase.ProcessUserErased)
+	go c.processMessages(ctx, c.summaryApprovedSub, "mint-consumer-summary-approved", c.summaryUsecase.ProjectCashbackApproved)
+	go c.processMessages(ctx, c.summaryExpiredSub, "mint-consumer-summary-expired", c.summaryUsecase.ProjectCashbackExpired)
 	go c.retryLoop(ctx)
 
 	return nil
 }
 
-func (c *CashbackConsumer) processMessages(ctx context.Context) {
+// subscribe gives each secondary subject its own durable so its events are
+// not blocked behind (or redelivered with) the approval backlog. The deliver
+// policy only applies when the durable is first created.
+func (c *CashbackConsumer) subscribe(js natsgo.JetStreamContext, stream, subject, durable string, deliver natsgo.DeliverPolicy) (*natsgo.Subscription, error) {
+	consumerConfig := &natsgo.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: subject,
+		DeliverPolicy: deliver,
+		AckPolicy:     natsgo.AckExplicitPolicy,
+		MaxDeliver:    5,
+		AckWait:       30 * time.Second,
+	}
+
+	_, err := js.AddConsumer(stream, consumerConfig)
+	if err != nil && err != natsgo.ErrConsumerNameAlreadyInUse {
+		c.log.Warn("failed to create consumer", "consumer", durable, "error", err)
+	}
+
+	sub, err := js.PullSubscribe(subject, durable)
+	if err != nil {
+		return nil, err
+	}
+	c.metrics.Watch(durable, sub)
+	return sub, nil
+}
+
+func (c *CashbackConsumer) processMessages(
+	ctx context.Context,
+	sub *natsgo.Subscription,
+	durable string,
+	handle func(ctx context.Context, data []byte) error,
+) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -65,38 +163,67 @@ func (c *CashbackConsumer) processMessages(ctx context.Context) {
 		case <-c.done:
 			return
 		default:
-			msgs, err := c.sub.Fetch(10, natsgo.MaxWait(time.Second))
+			msgs, err := sub.Fetch(10, natsgo.MaxWait(time.Second))
 			if err != nil {
 				if err != natsgo.ErrTimeout {
-					log.Printf("Error fetching messages: %v", err)
+					c.log.ErrorContext(ctx, "failed to fetch messages", "consumer", durable, "error", err)
 				}
 				continue
 			}
 
 			for _, msg := range msgs {
-				c.handleMessage(ctx, msg)
+				c.handleMessage(ctx, msg, durable, handle)
 			}
 		}
 	}
 }
 
-func (c *CashbackConsumer) handleMessage(ctx context.Context, msg *natsgo.Msg) {
-	log.Printf("Processing message: %s", string(msg.Data))
+func (c *CashbackConsumer) handleMessage(
+	ctx context.Context,
+	msg *natsgo.Msg,
+	durable string,
+	handle func(ctx context.Context, data []byte) error,
+) {
+	// Continue the trace the publisher put in the message headers.
+	ctx = otel.GetTextMapPropagator().Extract(ctx, tracing.HeaderCarrier(msg.Header))
+	if id := msg.Header.Get(natsgo.MsgIdHdr); id != "" {
+		ctx = logger.WithEventID(ctx, id)
+	}
+	ctx, span := tracer.Start(ctx, msg.Subject+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("nats"),
+			semconv.MessagingDestinationName(msg.Subject),
+			attribute.String("messaging.nats.consumer", durable),
+		),
+	)
+	defer span.End()
+	if meta, err := msg.Metadata(); err == nil {
+		span.SetAttributes(attribute.Int64("messaging.nats.num_delivered", int64(meta.NumDelivered)))
+	}
 
-	if err := c.mintUsecase.ProcessCashbackApproved(ctx, msg.Data); err != nil {
-		log.Printf("Error processing message: %v", err)
+	c.log.DebugContext(ctx, "processing message", "subject", msg.Subject, "consumer", durable)
+
+	start := time.Now()
+	err := handle(ctx, msg.Data)
+	c.metrics.Handled(durable, msg, time.Since(start), err)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "handling failed")
+		c.log.ErrorContext(ctx, "failed to process message", "subject", msg.Subject, "error", err)
 		if err := msg.Nak(); err != nil {
-			log.Printf("Error NAKing message: %v", err)
+			c.log.ErrorContext(ctx, "failed to nak message", "error", err)
 		}
 	} else {
 		if err := msg.Ack(); err != nil {
-			log.Printf("Error ACKing message: %v", err)
+			c.log.ErrorContext(ctx, "failed to ack message", "error", err)
 		}
 	}
 }
 
 func (c *CashbackConsumer) retryLoop(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(c.retryInterval)
 	defer ticker.Stop()
 
 	for {
@@ -107,7 +234,7 @@ func (c *CashbackConsumer) retryLoop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			if err := c.mintUsecase.RetryFailedMints(ctx); err != nil {
-				log.Printf("Error retrying failed mints: %v", err)
+				c.log.ErrorContext(ctx, "failed to retry mints", "error", err)
 			}
 		}
 	}
@@ -115,9 +242,12 @@ func (c *CashbackConsumer) retryLoop(ctx context.Context) {
 
 func (c *CashbackConsumer) Stop() {
 	close(c.done)
-	if c.sub != nil {
-		if err := c.sub.Unsubscribe(); err != nil {
-			log.Printf("Error unsubscribing: %v", err)
+	for _, sub := range []*natsgo.Subscription{c.sub, c.expiredSub, c.erasedSub, c.summaryApprovedSub, c.summaryExpiredSub} {
+		if sub == nil {
+			continue
+		}
+		if err := sub.Unsubscribe(); err != nil {
+			c.log.Error("failed to unsubscribe", "subject", sub.Subject, "error", err)
 		}
 	}
 }
@@ -130,13 +260,13 @@ func StartConsumer(lc fx.Lifecycle, consumer *CashbackConsumer) {
 			if err := consumer.Start(ctx); err != nil {
 				return err
 			}
-			log.Println("Cashback consumer started")
+			consumer.log.Info("cashback consumer started")
 			return nil
 		},
 		OnStop: func(_ context.Context) error {
 			cancel()
 			consumer.Stop()
-			log.Println("Cashback consumer stopped")
+			consumer.log.Info("cashback consumer stopped")
 			return nil
 		},
 	})
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// CashbackSummaryEntry is one cashback in the user cashback summary read
+	// model. Entries are keyed by cashback so applying an event twice, or
+	// out of order, changes nothing: an expired cashback stays expired
+	// whether its approval is seen before or after. cashback.approved carries
+	// no time, so ApprovedAt is when the approval was first projected.
+	CashbackSummaryEntry struct {
+		CashbackID uuid.UUID `gorm:"type:uuid;primary_key"`
+		UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+		Amount     float64   `gorm:"not null"`
+		ApprovedAt *time.Time
+		ExpiredAt  *time.Time
+		UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+	}
+
+	// UserCashbackSummary totals a user's approved and expired cashback, from
+	// the user_cashback_summaries view over the entries.
+	UserCashbackSummary struct {
+		UserID         uuid.UUID
+		ApprovedCount  int64
+		ApprovedAmount float64
+		ExpiredCount   int64
+		ExpiredAmount  float64
+		LastUpdatedAt  time.Time
+	}
+
+	// CashbackApprovedEvent holds the fields of the cashback.approved event
+	// published by the cashback service that the summary and minting need.
+	CashbackApprovedEvent struct {
+		CashbackID    uuid.UUID `json:"cashback_id"`
+		UserID        uuid.UUID `json:"user_id"`
+		WalletAddress string    `json:"wallet_address"`
+		Amount        float64   `json:"amount"`
+	}
+)
+
+// TableName specifies the table name for GORM
+func (CashbackSummaryEntry) TableName() string {
+	return "cashback_summary_entries"
+}
+
+// TableName specifies the view name for GORM
+func (UserCashbackSummary) TableName() string {
+	return "user_cashback_summaries"
+}
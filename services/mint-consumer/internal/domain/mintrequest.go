@@ -1,16 +1,25 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrMintRequestNotFound  = errors.New("mint request not found")
+	ErrDuplicateMintRequest = errors.New("mint request with this ID or idempotency key already exists")
+	ErrMintNotRetryable     = errors.New("mint request is not retryable")
+)
+
 const (
 	MintRequestStatusPending    MintRequestStatus = "pending"
 	MintRequestStatusProcessing MintRequestStatus = "processing"
 	MintRequestStatusCompleted  MintRequestStatus = "completed"
 	MintRequestStatusFailed     MintRequestStatus = "failed"
+	// MintRequestStatusExpired marks requests whose cashback expired before minting
+	MintRequestStatusExpired MintRequestStatus = "expired"
 )
 
 type (
@@ -38,6 +47,22 @@ type (
 		CompletedAt     *time.Time
 	}
 
+	// CashbackExpiredEvent represents the cashback.expired event published by the cashback service
+	CashbackExpiredEvent struct {
+		CashbackID   uuid.UUID `json:"cashback_id"`
+		UserID       uuid.UUID `json:"user_id"`
+		PurchaseID   uuid.UUID `json:"purchase_id"`
+		CashbackType string    `json:"cashback_type"`
+		Amount       float64   `json:"amount"`
+		ExpiredAt    time.Time `json:"expired_at"`
+	}
+
+	// UserErasedEvent represents the user.erased event published by the cashback service
+	UserErasedEvent struct {
+		UserID   uuid.UUID `json:"user_id"`
+		ErasedAt time.Time `json:"erased_at"`
+	}
+
 	// TokenMintRequestedEvent represents the token.mint.requested domain event
 	TokenMintRequestedEvent struct {
 		EventID   uuid.UUID `json:"event_id"`
@@ -0,0 +1,34 @@
+-- Baseline schema, matching what AutoMigrate created before versioned
+-- migrations. IF NOT EXISTS lets those databases adopt this version as is.
+
+CREATE TABLE IF NOT EXISTS mint_requests (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cashback_id      UUID NOT NULL,
+    user_id          UUID NOT NULL,
+    wallet_address   VARCHAR(42) NOT NULL,
+    token_amount     VARCHAR(78) NOT NULL,
+    idempotency_key  UUID NOT NULL,
+    status           VARCHAR(50) NOT NULL DEFAULT 'pending',
+    retry_count      BIGINT NOT NULL DEFAULT 0,
+    max_retries      BIGINT NOT NULL DEFAULT 5,
+    transaction_hash VARCHAR(66),
+    block_number     BIGINT,
+    error_code       VARCHAR(100),
+    error_message    TEXT,
+    next_retry_at    TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ,
+    completed_at     TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_mint_requests_cashback_id ON mint_requests (cashback_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mint_requests_idempotency_key ON mint_requests (idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_mint_requests_status ON mint_requests (status);
+
+CREATE TABLE IF NOT EXISTS processed_events (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id     UUID NOT NULL,
+    event_type   VARCHAR(100) NOT NULL,
+    processed_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_processed_events_event_id ON processed_events (event_id);
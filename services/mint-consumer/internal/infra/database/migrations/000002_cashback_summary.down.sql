@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS user_cashback_summaries;
+DROP TABLE IF EXISTS cashback_summary_entries;
@@ -0,0 +1,24 @@
+-- User cashback summary read model, built from cashback.approved and
+-- cashback.expired. It can be rebuilt at any time with `mint-consumer replay
+-- cashback-summary --rebuild`.
+
+CREATE TABLE cashback_summary_entries (
+    cashback_id UUID PRIMARY KEY,
+    user_id     UUID NOT NULL,
+    amount      DOUBLE PRECISION NOT NULL,
+    approved_at TIMESTAMPTZ,
+    expired_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+CREATE INDEX idx_cashback_summary_entries_user_id ON cashback_summary_entries (user_id);
+
+CREATE VIEW user_cashback_summaries AS
+SELECT
+    user_id,
+    COUNT(*) FILTER (WHERE expired_at IS NULL)                    AS approved_count,
+    COALESCE(SUM(amount) FILTER (WHERE expired_at IS NULL), 0)    AS approved_amount,
+    COUNT(*) FILTER (WHERE expired_at IS NOT NULL)                AS expired_count,
+    COALESCE(SUM(amount) FILTER (WHERE expired_at IS NOT NULL), 0) AS expired_amount,
+    MAX(updated_at)                                               AS last_updated_at
+FROM cashback_summary_entries
+GROUP BY user_id;
@@ -1,17 +1,39 @@
 package database
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/cashback-platform/services/mint-consumer/internal/config"
-	"github.com/cashback-platform/services/mint-consumer/internal/domain"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
+// NewPostgresDB connects to Postgres and refuses to start unless the schema is
+// at the version this build was written for. Run the migrate subcommand to
+// apply migrations.
+func NewPostgresDB(cfg *config.Config, log *slog.Logger) (*gorm.DB, error) {
+	db, err := Connect(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		return nil, err
+	}
+
+	log.Info("database connected, schema is up to date")
+	return db, nil
+}
+
+// Connect opens the database, sending GORM's output to log: statements at
+// debug, slow and failed ones at warn.
+func Connect(cfg *config.Config, log *slog.Logger) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Database.Host,
@@ -22,26 +44,19 @@ func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
 		cfg.Database.SSLMode,
 	)
 
-	logLevel := logger.Silent
-	if cfg.App.Env == "development" {
-		logLevel = logger.Info
-	}
+	return Open(dsn, log)
+}
 
+// Open connects to the Postgres database at dsn, which is either a URL or a
+// list of key=value settings. Unique violations are reported as
+// gorm.ErrDuplicatedKey, for the repositories to map to domain errors.
+func Open(dsn string, log *slog.Logger) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
+		Logger:         newGormLogger(log),
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-
-	// Auto migrate schemas
-	if err := db.AutoMigrate(
-		&domain.MintRequest{},
-		&domain.ProcessedEvent{},
-	); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
-	}
-
-	log.Println("Database connected and migrated successfully")
 	return db, nil
 }
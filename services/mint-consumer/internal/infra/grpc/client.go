@@ -3,17 +3,24 @@ package grpc
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
+	tokenpb "github.com/cashback-platform/proto/token"
 	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/metrics"
+	"github.com/cashback-platform/services/mint-consumer/internal/replay"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 type (
-	// MintResult represents the result of a mint operation
+	// MintResult represents the result of a mint operation. A successful mint
+	// is Confirmed once its transaction is mined; until then it was only
+	// submitted and the same mint must be asked for again.
 	MintResult struct {
 		Success         bool
+		Confirmed       bool
 		TransactionHash string
 		BlockNumber     int64
 		ErrorCode       string
@@ -23,36 +30,58 @@ type (
 
 	BlockchainAdapterClient struct {
 		conn    *grpc.ClientConn
+		client  tokenpb.TokenServiceClient
 		address string
+		log     *slog.Logger
 	}
 )
 
-func NewBlockchainAdapterClient(cfg *config.Config) (*BlockchainAdapterClient, error) {
+func NewBlockchainAdapterClient(cfg *config.Config, m *metrics.GRPCClient, log *slog.Logger) (*BlockchainAdapterClient, error) {
 	conn, err := grpc.Dial(
 		cfg.GRPC.BlockchainAdapterAddress,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(m.UnaryInterceptor()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to blockchain adapter: %w", err)
 	}
 
-	log.Printf("Connected to blockchain adapter at %s", cfg.GRPC.BlockchainAdapterAddress)
+	log.Info("connected to blockchain adapter", "address", cfg.GRPC.BlockchainAdapterAddress)
 	return &BlockchainAdapterClient{
 		conn:    conn,
+		client:  tokenpb.NewTokenServiceClient(conn),
 		address: cfg.GRPC.BlockchainAdapterAddress,
+		log:     log,
 	}, nil
 }
 
-func (*BlockchainAdapterClient) MintToken(_ context.Context, idempotencyKey, walletAddress, tokenAmount string) (*MintResult, error) {
-	// TODO: Use generated gRPC client from proto files
-	// For now, return a mock successful response
-	log.Printf("Minting token: idempotencyKey=%s, wallet=%s, amount=%s", idempotencyKey, walletAddress, tokenAmount)
+// MintToken mints tokenAmount to walletAddress. It mints nothing in replay
+// mode and returns replay.ErrSuppressed instead.
+func (c *BlockchainAdapterClient) MintToken(ctx context.Context, idempotencyKey, walletAddress, tokenAmount string) (*MintResult, error) {
+	if replay.Active(ctx) {
+		c.log.DebugContext(ctx, "mint suppressed in replay mode", "idempotency_key", idempotencyKey)
+		return nil, fmt.Errorf("mint token: %w", replay.ErrSuppressed)
+	}
+
+	c.log.DebugContext(ctx, "minting token", "idempotency_key", idempotencyKey, "wallet", walletAddress, "amount", tokenAmount)
+	resp, err := c.client.MintToken(ctx, &tokenpb.MintTokenRequest{
+		IdempotencyKey: idempotencyKey,
+		WalletAddress:  walletAddress,
+		TokenAmount:    tokenAmount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mint token: %w", err)
+	}
 
-	// Simulated successful mint
 	return &MintResult{
-		Success:         true,
-		TransactionHash: fmt.Sprintf("0x%s", idempotencyKey[:32]),
-		BlockNumber:     12345678,
+		Success:         resp.GetSuccess(),
+		Confirmed:       resp.GetStatus() == tokenpb.MintStatus_MINT_STATUS_CONFIRMED,
+		TransactionHash: resp.GetTransactionHash(),
+		BlockNumber:     resp.GetBlockNumber(),
+		ErrorCode:       resp.GetError().GetCode(),
+		ErrorMessage:    resp.GetError().GetMessage(),
+		Retryable:       resp.GetError().GetRetryable(),
 	}, nil
 }
 
@@ -0,0 +1,75 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	tokenpb "github.com/cashback-platform/proto/token"
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/grpc"
+	"github.com/cashback-platform/services/mint-consumer/internal/metrics"
+	"github.com/cashback-platform/services/mint-consumer/internal/replay"
+	grpclib "google.golang.org/grpc"
+)
+
+// adapter is a blockchain adapter that confirms every mint it is asked for.
+type adapter struct {
+	tokenpb.UnimplementedTokenServiceServer
+	mints atomic.Int32
+}
+
+func (a *adapter) MintToken(context.Context, *tokenpb.MintTokenRequest) (*tokenpb.MintTokenResponse, error) {
+	a.mints.Add(1)
+	return &tokenpb.MintTokenResponse{
+		Success:         true,
+		TransactionHash: "0xabc",
+		BlockNumber:     7,
+		Status:          tokenpb.MintStatus_MINT_STATUS_CONFIRMED,
+	}, nil
+}
+
+func newClient(t *testing.T) (*grpc.BlockchainAdapterClient, *adapter) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpclib.NewServer()
+	a := &adapter{}
+	tokenpb.RegisterTokenServiceServer(server, a)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	cfg := &config.Config{GRPC: config.GRPCConfig{BlockchainAdapterAddress: lis.Addr().String()}}
+	client, err := grpc.NewBlockchainAdapterClient(cfg, metrics.NewGRPCClient(metrics.NewRegistry()), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client, a
+}
+
+func TestMintTokenIsSuppressedInReplayMode(t *testing.T) {
+	client, a := newClient(t)
+
+	result, err := client.MintToken(replay.WithMode(context.Background()), "key-1", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "1000")
+	if !errors.Is(err, replay.ErrSuppressed) || result != nil {
+		t.Fatalf("replayed mint = %+v, %v; want %v", result, err, replay.ErrSuppressed)
+	}
+	if a.mints.Load() != 0 {
+		t.Fatalf("the adapter minted %d times in replay mode", a.mints.Load())
+	}
+
+	result, err = client.MintToken(context.Background(), "key-1", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success || !result.Confirmed || a.mints.Load() != 1 {
+		t.Fatalf("live mint = %+v after %d adapter calls", result, a.mints.Load())
+	}
+}
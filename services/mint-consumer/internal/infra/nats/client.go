@@ -1,11 +1,14 @@
 package nats
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/tracing"
 	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
 )
 
 type NATSClient struct {
@@ -13,7 +16,7 @@ type NATSClient struct {
 	js   nats.JetStreamContext
 }
 
-func NewNATSClient(cfg *config.Config) (*NATSClient, error) {
+func NewNATSClient(cfg *config.Config, log *slog.Logger) (*NATSClient, error) {
 	conn, err := nats.Connect(cfg.NATS.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
@@ -25,15 +28,21 @@ func NewNATSClient(cfg *config.Config) (*NATSClient, error) {
 		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
 	}
 
-	log.Println("NATS connected successfully")
+	log.Info("NATS connected", "url", cfg.NATS.URL)
 	return &NATSClient{
 		conn: conn,
 		js:   js,
 	}, nil
 }
 
-func (c *NATSClient) Publish(subject string, data []byte) error {
-	_, err := c.js.Publish(subject, data)
+// Publish sends data to subject, passing the trace of ctx on in the message
+// headers.
+func (c *NATSClient) Publish(ctx context.Context, subject string, data []byte) error {
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	otel.GetTextMapPropagator().Inject(ctx, tracing.HeaderCarrier(msg.Header))
+
+	_, err := c.js.PublishMsg(msg)
 	return err
 }
 
@@ -0,0 +1,26 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// CheckConnection fails unless the connection to the server is up.
+func (c *NATSClient) CheckConnection(_ context.Context) error {
+	if status := c.conn.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("connection is %s", status)
+	}
+	return nil
+}
+
+// CheckStreams fails unless every one of the named streams exists.
+func (c *NATSClient) CheckStreams(ctx context.Context, names ...string) error {
+	for _, name := range names {
+		if _, err := c.js.StreamInfo(name, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("stream %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Results of handling a consumed message.
+const (
+	ResultAck = "ack"
+	ResultNak = "nak"
+)
+
+// Consumer records JetStream message handling and, on every scrape, the
+// state of each watched durable consumer as the server reports it.
+type Consumer struct {
+	messages     *prometheus.CounterVec
+	redeliveries *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+
+	mu      sync.Mutex
+	watched map[string]*natsgo.Subscription
+
+	lag         *prometheus.Desc
+	ackPending  *prometheus.Desc
+	redelivered *prometheus.Desc
+
+	log *slog.Logger
+}
+
+func NewConsumer(reg *Registry, log *slog.Logger) *Consumer {
+	m := &Consumer{
+		log: log,
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "messages_total",
+			Help:      "Messages handled, by durable consumer, subject and result (ack, nak).",
+		}, []string{"consumer", "subject", "result"}),
+		redeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "redeliveries_total",
+			Help:      "Messages received again after a nak or an ack timeout, by durable consumer and subject.",
+		}, []string{"consumer", "subject"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "handling_seconds",
+			Help:      "Time to handle a message, by durable consumer and subject.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"consumer", "subject"}),
+		watched: make(map[string]*natsgo.Subscription),
+		lag: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "consumer", "lag_messages"),
+			"Messages in the stream not yet delivered to the durable consumer.",
+			[]string{"consumer"}, nil,
+		),
+		ackPending: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "consumer", "ack_pending_messages"),
+			"Messages delivered to the durable consumer and not yet acknowledged.",
+			[]string{"consumer"}, nil,
+		),
+		redelivered: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "consumer", "redelivered_messages"),
+			"Unacknowledged messages that have been delivered more than once.",
+			[]string{"consumer"}, nil,
+		),
+	}
+	reg.MustRegister(m.messages, m.redeliveries, m.duration, m)
+	return m
+}
+
+// Watch reports the state of the durable consumer behind sub on every scrape.
+func (m *Consumer) Watch(durable string, sub *natsgo.Subscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watched[durable] = sub
+}
+
+// Handled records a message handled in d; err is the handler's error.
+func (m *Consumer) Handled(durable string, msg *natsgo.Msg, d time.Duration, err error) {
+	result := ResultAck
+	if err != nil {
+		result = ResultNak
+	}
+	m.messages.WithLabelValues(durable, msg.Subject, result).Inc()
+	m.duration.WithLabelValues(durable, msg.Subject).Observe(d.Seconds())
+
+	if meta, err := msg.Metadata(); err == nil && meta.NumDelivered > 1 {
+		m.redeliveries.WithLabelValues(durable, msg.Subject).Inc()
+	}
+}
+
+func (m *Consumer) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.lag
+	ch <- m.ackPending
+	ch <- m.redelivered
+}
+
+func (m *Consumer) Collect(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for durable, sub := range m.watched {
+		info, err := sub.ConsumerInfo()
+		if err != nil {
+			m.log.Error("failed to fetch consumer info", "consumer", durable, "error", err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(m.lag, prometheus.GaugeValue, float64(info.NumPending), durable)
+		ch <- prometheus.MustNewConstMetric(m.ackPending, prometheus.GaugeValue, float64(info.NumAckPending), durable)
+		ch <- prometheus.MustNewConstMetric(m.redelivered, prometheus.GaugeValue, float64(info.NumRedelivered), durable)
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCClient records the calls made to other services over gRPC.
+type GRPCClient struct {
+	handled  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewGRPCClient(reg *Registry) *GRPCClient {
+	m := &GRPCClient{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_client",
+			Name:      "handled_total",
+			Help:      "gRPC calls completed, by service, method and status code.",
+		}, []string{"grpc_service", "grpc_method", "grpc_code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_client",
+			Name:      "handling_seconds",
+			Help:      "Time until gRPC calls completed, by service and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"grpc_service", "grpc_method"}),
+	}
+	reg.MustRegister(m.handled, m.duration)
+	return m
+}
+
+func (m *GRPCClient) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		service, name := splitMethod(method)
+		m.handled.WithLabelValues(service, name, status.Code(err).String()).Inc()
+		m.duration.WithLabelValues(service, name).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// splitMethod splits "/package.Service/Method" into service and method.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", fullMethod
+	}
+	return service, method
+}
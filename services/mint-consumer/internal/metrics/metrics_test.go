@@ -0,0 +1,110 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/domain"
+	"github.com/cashback-platform/services/mint-consumer/internal/metrics"
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+	"github.com/google/uuid"
+	natsgo "github.com/nats-io/nats.go"
+)
+
+func discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestConsumerCountsHandledMessages(t *testing.T) {
+	reg := metrics.NewRegistry()
+	consumer := metrics.NewConsumer(reg, discard())
+
+	msg := &natsgo.Msg{Subject: "cashback.approved"}
+	consumer.Handled("mint-consumer", msg, 10*time.Millisecond, nil)
+	consumer.Handled("mint-consumer", msg, 10*time.Millisecond, nil)
+	consumer.Handled("mint-consumer", msg, 10*time.Millisecond, errors.New("adapter unavailable"))
+
+	assertScraped(t, reg,
+		`cashback_consumer_messages_total{consumer="mint-consumer",result="ack",service="mint-consumer",subject="cashback.approved"} 2`,
+		`cashback_consumer_messages_total{consumer="mint-consumer",result="nak",service="mint-consumer",subject="cashback.approved"} 1`,
+		`cashback_consumer_handling_seconds_count{consumer="mint-consumer",service="mint-consumer",subject="cashback.approved"} 3`,
+	)
+}
+
+func TestMintRequestsAreCountedOnScrape(t *testing.T) {
+	repo := repository.NewMemoryMintRequestRepository()
+	reg := metrics.NewRegistry()
+	metrics.RegisterMintRequests(reg, repo, discard())
+
+	for _, r := range []struct {
+		status    domain.MintRequestStatus
+		errorCode string
+	}{
+		{domain.MintRequestStatusPending, ""},
+		{domain.MintRequestStatusFailed, "REVERTED"},
+		{domain.MintRequestStatusFailed, "REVERTED"},
+	} {
+		request := &domain.MintRequest{
+			CashbackID:     uuid.New(),
+			UserID:         uuid.New(),
+			WalletAddress:  "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			TokenAmount:    "1000000000000000000",
+			IdempotencyKey: uuid.New(),
+			Status:         r.status,
+			ErrorCode:      r.errorCode,
+		}
+		if err := repo.Create(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertScraped(t, reg,
+		`cashback_mint_requests{error_code="",service="mint-consumer",status="pending"} 1`,
+		`cashback_mint_requests{error_code="REVERTED",service="mint-consumer",status="failed"} 2`,
+	)
+}
+
+func TestMintRequestsAreLeftOutWhenTheCountFails(t *testing.T) {
+	reg := metrics.NewRegistry()
+	metrics.RegisterMintRequests(reg, failingRepository{}, discard())
+
+	if body := scrape(t, reg); strings.Contains(body, "cashback_mint_requests{") {
+		t.Fatalf("reported mint requests the count failed for:\n%s", body)
+	}
+}
+
+func assertScraped(t *testing.T, reg *metrics.Registry, lines ...string) {
+	t.Helper()
+	body := scrape(t, reg)
+	for _, line := range lines {
+		if !strings.Contains(body, line) {
+			t.Errorf("missing %s", line)
+		}
+	}
+}
+
+// scrape returns the registry's metrics in the text exposition format.
+func scrape(t *testing.T, reg *metrics.Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape returned %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+type failingRepository struct {
+	repository.MintRequestRepository
+}
+
+func (failingRepository) CountByStatus(context.Context) ([]repository.StatusCount, error) {
+	return nil, errors.New("connection refused")
+}
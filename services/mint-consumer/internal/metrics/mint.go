@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// countTimeout bounds the count query run on every scrape.
+const countTimeout = 2 * time.Second
+
+// mintRequestCollector reports the mint requests in the database by status
+// and error code. The count is taken on every scrape, so it stays right
+// whichever replica or job changed a request.
+type mintRequestCollector struct {
+	repo repository.MintRequestRepository
+	desc *prometheus.Desc
+	log  *slog.Logger
+}
+
+func RegisterMintRequests(reg *Registry, repo repository.MintRequestRepository, log *slog.Logger) {
+	reg.MustRegister(&mintRequestCollector{
+		repo: repo,
+		log:  log,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "mint", "requests"),
+			"Mint requests, by status and error code.",
+			[]string{"status", "error_code"}, nil,
+		),
+	})
+}
+
+func (c *mintRequestCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *mintRequestCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+	defer cancel()
+
+	counts, err := c.repo.CountByStatus(ctx)
+	if err != nil {
+		c.log.ErrorContext(ctx, "failed to count mint requests", "error", err)
+		return
+	}
+	for _, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count.Count), string(count.Status), count.ErrorCode)
+	}
+}
@@ -0,0 +1,24 @@
+package replay
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSuppressed is returned by side-effecting calls made in replay mode, such
+// as minting tokens. Handlers treat it like the call not having happened.
+var ErrSuppressed = errors.New("suppressed in replay mode")
+
+type modeKey struct{}
+
+// WithMode marks ctx as replaying history.
+func WithMode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, modeKey{}, true)
+}
+
+// Active reports whether ctx is replaying history. Calls with effects outside
+// this service's database must check it and return ErrSuppressed.
+func Active(ctx context.Context) bool {
+	active, _ := ctx.Value(modeKey{}).(bool)
+	return active
+}
@@ -0,0 +1,168 @@
+// Package replay feeds events kept in a JetStream stream through a handler
+// again, to rebuild read models or backfill a new consumer. It reads through
+// an ephemeral ordered consumer, so the durables the service consumes with
+// are never touched, and runs handlers in replay mode so they do not repeat
+// side effects such as minting.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/nats-io/nats.go"
+)
+
+// idleTimeout is how long a replay waits for the next event before deciding
+// no more events match its subject.
+const idleTimeout = 5 * time.Second
+
+// errIdle ends a replay whose subject has no more events.
+var errIdle = errors.New("no more events")
+
+type (
+	// Handler handles the data of one event, like the consumer's handlers.
+	Handler func(ctx context.Context, data []byte) error
+
+	// Options select the events to replay. Without FromSeq or FromTime every
+	// event the stream still keeps is replayed.
+	Options struct {
+		Stream   string
+		Subject  string
+		FromSeq  uint64
+		FromTime time.Time
+		// ToSeq is the last stream sequence to replay; 0 stops at the last
+		// event in the stream when the replay starts.
+		ToSeq uint64
+	}
+
+	// Result summarises a replay. FirstSeq and LastSeq are the stream
+	// sequences of the first and last event replayed, 0 when none was.
+	Result struct {
+		Stream   string `json:"stream"`
+		Subject  string `json:"subject"`
+		FirstSeq uint64 `json:"first_seq"`
+		LastSeq  uint64 `json:"last_seq"`
+		Handled  int    `json:"handled"`
+		Failed   int    `json:"failed"`
+	}
+
+	Replayer struct {
+		js  nats.JetStreamContext
+		log *slog.Logger
+	}
+)
+
+func NewReplayer(js nats.JetStreamContext, log *slog.Logger) *Replayer {
+	return &Replayer{js: js, log: log}
+}
+
+// Run replays the events selected by opts through handle, in stream order.
+// A failing event is logged and counted, and the replay goes on.
+func (r *Replayer) Run(ctx context.Context, opts Options, handle Handler) (Result, error) {
+	if opts.FromSeq > 0 && !opts.FromTime.IsZero() {
+		return Result{}, errors.New("replay from a sequence or a time, not both")
+	}
+
+	result := Result{Stream: opts.Stream, Subject: opts.Subject}
+	info, err := r.js.StreamInfo(opts.Stream, nats.Context(ctx))
+	if err != nil {
+		return result, fmt.Errorf("stream %s: %w", opts.Stream, err)
+	}
+	stop := info.State.LastSeq
+	if opts.ToSeq > 0 && opts.ToSeq < stop {
+		stop = opts.ToSeq
+	}
+	if info.State.Msgs == 0 || stop < info.State.FirstSeq || opts.FromSeq > stop {
+		return result, nil
+	}
+
+	subOpts := []nats.SubOpt{nats.BindStream(opts.Stream), nats.OrderedConsumer()}
+	switch {
+	case opts.FromSeq > 0:
+		subOpts = append(subOpts, nats.StartSequence(opts.FromSeq))
+	case !opts.FromTime.IsZero():
+		subOpts = append(subOpts, nats.StartTime(opts.FromTime))
+	default:
+		subOpts = append(subOpts, nats.DeliverAll())
+	}
+	sub, err := r.js.SubscribeSync(opts.Subject, subOpts...)
+	if err != nil {
+		return result, fmt.Errorf("failed to create replay consumer: %w", err)
+	}
+	defer func() {
+		if err := sub.Unsubscribe(); err != nil {
+			r.log.Warn("failed to remove replay consumer", "error", err)
+		}
+	}()
+
+	ctx = logger.WithAttrs(WithMode(ctx), slog.Bool("replay", true))
+	r.log.InfoContext(ctx, "replay started", "stream", opts.Stream, "subject", opts.Subject, "to_seq", stop)
+
+	for {
+		msg, err := r.next(ctx, sub)
+		if errors.Is(err, errIdle) {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		meta, err := msg.Metadata()
+		if err != nil {
+			return result, fmt.Errorf("failed to read event metadata: %w", err)
+		}
+		seq := meta.Sequence.Stream
+		if seq > stop {
+			break
+		}
+		if result.FirstSeq == 0 {
+			result.FirstSeq = seq
+		}
+		result.LastSeq = seq
+
+		msgCtx := ctx
+		if id := msg.Header.Get(nats.MsgIdHdr); id != "" {
+			msgCtx = logger.WithEventID(ctx, id)
+		}
+		if err := handle(msgCtx, msg.Data); err != nil {
+			result.Failed++
+			r.log.WarnContext(msgCtx, "failed to replay event", "subject", msg.Subject, "stream_seq", seq, "error", err)
+		} else {
+			result.Handled++
+		}
+
+		if seq == stop || meta.NumPending == 0 {
+			break
+		}
+	}
+
+	r.log.InfoContext(ctx, "replay finished",
+		"stream", opts.Stream,
+		"subject", opts.Subject,
+		"first_seq", result.FirstSeq,
+		"last_seq", result.LastSeq,
+		"handled", result.Handled,
+		"failed", result.Failed,
+	)
+	return result, nil
+}
+
+// next waits up to idleTimeout for the next event, returning errIdle when
+// none came.
+func (r *Replayer) next(ctx context.Context, sub *nats.Subscription) (*nats.Msg, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, idleTimeout)
+	defer cancel()
+
+	msg, err := sub.NextMsgWithContext(waitCtx)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil && waitCtx.Err() != nil {
+		return nil, errIdle
+	}
+	return msg, err
+}
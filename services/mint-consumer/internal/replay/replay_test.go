@@ -0,0 +1,50 @@
+package replay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/replay"
+)
+
+type key struct{}
+
+func TestMode(t *testing.T) {
+	if replay.Active(context.Background()) {
+		t.Fatal("a plain context is in replay mode")
+	}
+
+	ctx := replay.WithMode(context.Background())
+	if !replay.Active(ctx) {
+		t.Fatal("WithMode did not enter replay mode")
+	}
+
+	// Handlers derive contexts for timeouts and logging attributes; the mode
+	// must reach the calls they make.
+	timeout, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	for name, derived := range map[string]context.Context{
+		"timeout":  timeout,
+		"value":    context.WithValue(ctx, key{}, "cashback"),
+		"detached": context.WithoutCancel(ctx),
+	} {
+		if !replay.Active(derived) {
+			t.Errorf("a %s context derived in replay mode left it", name)
+		}
+	}
+}
+
+func TestRunRejectsBothStartPositions(t *testing.T) {
+	// The options are checked before the stream is looked up.
+	r := replay.NewReplayer(nil, nil)
+	_, err := r.Run(context.Background(), replay.Options{
+		Stream:   "CASHBACK",
+		Subject:  "cashback.approved",
+		FromSeq:  10,
+		FromTime: time.Now(),
+	}, func(context.Context, []byte) error { return nil })
+	if err == nil {
+		t.Fatal("a replay from both a sequence and a time was started")
+	}
+}
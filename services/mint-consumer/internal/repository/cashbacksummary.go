@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	CashbackSummaryRepository interface {
+		RecordApproved(ctx context.Context, cashbackID, userID uuid.UUID, amount float64, at time.Time) error
+		RecordExpired(ctx context.Context, cashbackID, userID uuid.UUID, amount float64, at time.Time) error
+		GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserCashbackSummary, error)
+		Reset(ctx context.Context) error
+	}
+
+	cashbackSummaryRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewCashbackSummaryRepository(db *gorm.DB) CashbackSummaryRepository {
+	return &cashbackSummaryRepository{db: db}
+}
+
+// RecordApproved adds the cashback to the summary. An entry already expired
+// stays expired.
+func (r *cashbackSummaryRepository) RecordApproved(ctx context.Context, cashbackID, userID uuid.UUID, amount float64, at time.Time) error {
+	return r.upsert(ctx, domain.CashbackSummaryEntry{
+		CashbackID: cashbackID,
+		UserID:     userID,
+		Amount:     amount,
+		ApprovedAt: &at,
+	}, "approved_at")
+}
+
+// RecordExpired marks the cashback expired, adding it if its approval has
+// not been seen.
+func (r *cashbackSummaryRepository) RecordExpired(ctx context.Context, cashbackID, userID uuid.UUID, amount float64, at time.Time) error {
+	return r.upsert(ctx, domain.CashbackSummaryEntry{
+		CashbackID: cashbackID,
+		UserID:     userID,
+		Amount:     amount,
+		ExpiredAt:  &at,
+	}, "expired_at")
+}
+
+// upsert inserts entry or sets column on the existing one, keeping the first
+// time it was set.
+func (r *cashbackSummaryRepository) upsert(ctx context.Context, entry domain.CashbackSummaryEntry, column string) error {
+	entry.UpdatedAt = time.Now().UTC()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "cashback_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "user_id"}, Value: entry.UserID},
+			{Column: clause.Column{Name: "amount"}, Value: entry.Amount},
+			{Column: clause.Column{Name: column}, Value: gorm.Expr("COALESCE(cashback_summary_entries." + column + ", EXCLUDED." + column + ")")},
+			{Column: clause.Column{Name: "updated_at"}, Value: entry.UpdatedAt},
+		},
+	}).Create(&entry).Error
+}
+
+func (r *cashbackSummaryRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserCashbackSummary, error) {
+	var summary domain.UserCashbackSummary
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Take(&summary).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &domain.UserCashbackSummary{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Reset empties the summary before it is rebuilt.
+func (r *cashbackSummaryRepository) Reset(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec("TRUNCATE TABLE cashback_summary_entries").Error
+}
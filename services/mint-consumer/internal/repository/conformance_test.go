@@ -0,0 +1,487 @@
+package repository_test
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/domain"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/database/databasetest"
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+	"github.com/google/uuid"
+)
+
+// The same suite runs against the in-memory repository and the Postgres one,
+// so the two cannot drift apart. The Postgres run needs TEST_DATABASE_URL and
+// is skipped without it.
+
+func TestMemoryMintRequestRepository(t *testing.T) {
+	testMintRequestRepository(t, func(t *testing.T) repository.MintRequestRepository {
+		return repository.NewMemoryMintRequestRepository()
+	})
+}
+
+func TestPostgresMintRequestRepository(t *testing.T) {
+	testMintRequestRepository(t, func(t *testing.T) repository.MintRequestRepository {
+		return repository.NewMintRequestRepository(databasetest.New(t))
+	})
+}
+
+func testMintRequestRepository(t *testing.T, newRepo func(t *testing.T) repository.MintRequestRepository) {
+	ctx := context.Background()
+
+	t.Run("create fills in defaults", func(t *testing.T) {
+		repo := newRepo(t)
+
+		request := newMintRequest(uuid.New())
+		mustCreate(t, repo, request)
+		if request.ID == uuid.Nil || request.Status != domain.MintRequestStatusPending ||
+			request.MaxRetries != 5 || request.CreatedAt.IsZero() {
+			t.Fatalf("created request has ID %s, status %q, max retries %d, created at %s",
+				request.ID, request.Status, request.MaxRetries, request.CreatedAt)
+		}
+
+		got := mustGet(t, repo, request.ID)
+		if got.CashbackID != request.CashbackID || got.IdempotencyKey != request.IdempotencyKey ||
+			got.WalletAddress != request.WalletAddress || got.Status != domain.MintRequestStatusPending || got.RetryCount != 0 {
+			t.Fatalf("stored %+v, want %+v", got, request)
+		}
+	})
+
+	t.Run("missing requests are not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetByID(ctx, uuid.New())
+		assertError(t, err, domain.ErrMintRequestNotFound)
+		_, err = repo.GetByCashbackID(ctx, uuid.New())
+		assertError(t, err, domain.ErrMintRequestNotFound)
+		_, err = repo.GetByIdempotencyKey(ctx, uuid.New())
+		assertError(t, err, domain.ErrMintRequestNotFound)
+	})
+
+	t.Run("idempotency keys are unique", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := newMintRequest(uuid.New())
+		mustCreate(t, repo, first)
+		second := newMintRequest(uuid.New())
+		second.IdempotencyKey = first.IdempotencyKey
+		assertError(t, repo.Create(ctx, second), domain.ErrDuplicateMintRequest)
+
+		got, err := repo.GetByIdempotencyKey(ctx, first.IdempotencyKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != first.ID {
+			t.Fatalf("idempotency key maps to %s, want %s", got.ID, first.ID)
+		}
+
+		// Update is held to the same constraint.
+		third := newMintRequest(uuid.New())
+		mustCreate(t, repo, third)
+		third.IdempotencyKey = first.IdempotencyKey
+		assertError(t, repo.Update(ctx, third), domain.ErrDuplicateMintRequest)
+	})
+
+	t.Run("the request of a cashback is the one with the lowest ID", func(t *testing.T) {
+		repo := newRepo(t)
+
+		cashbackID := uuid.New()
+		first, second := newMintRequest(cashbackID), newMintRequest(cashbackID)
+		mustCreate(t, repo, first)
+		mustCreate(t, repo, second)
+
+		got, err := repo.GetByCashbackID(ctx, cashbackID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := first.ID
+		if second.ID.String() < want.String() {
+			want = second.ID
+		}
+		if got.ID != want {
+			t.Fatalf("request of cashback is %s, want %s", got.ID, want)
+		}
+	})
+
+	t.Run("update saves every field", func(t *testing.T) {
+		repo := newRepo(t)
+
+		request := newMintRequest(uuid.New())
+		mustCreate(t, repo, request)
+		request.Status = domain.MintRequestStatusProcessing
+		request.TokenAmount = "42"
+		if err := repo.Update(ctx, request); err != nil {
+			t.Fatal(err)
+		}
+
+		got := mustGet(t, repo, request.ID)
+		if got.Status != domain.MintRequestStatusProcessing || got.TokenAmount != "42" {
+			t.Fatalf("updated request is %+v", got)
+		}
+	})
+
+	t.Run("status changes", func(t *testing.T) {
+		repo := newRepo(t)
+
+		request := newMintRequest(uuid.New())
+		mustCreate(t, repo, request)
+
+		if err := repo.UpdateStatus(ctx, request.ID, domain.MintRequestStatusProcessing); err != nil {
+			t.Fatal(err)
+		}
+		if got := mustGet(t, repo, request.ID); got.Status != domain.MintRequestStatusProcessing {
+			t.Fatalf("status = %q, want processing", got.Status)
+		}
+
+		retryAt := time.Now().Add(time.Minute)
+		mustChange(t)(repo.MarkFailed(ctx, request.ID, "RPC_ERROR", "node unreachable", &retryAt))
+		got := mustGet(t, repo, request.ID)
+		if got.Status != domain.MintRequestStatusFailed || got.ErrorCode != "RPC_ERROR" ||
+			got.ErrorMessage != "node unreachable" || got.RetryCount != 1 || got.NextRetryAt == nil {
+			t.Fatalf("failed request is %+v", got)
+		}
+
+		mustChange(t)(repo.Claim(ctx, got, time.Now().Add(time.Minute)))
+		mustChange(t)(repo.MarkFailed(ctx, request.ID, "RPC_ERROR", "node unreachable", nil))
+		got = mustGet(t, repo, request.ID)
+		if got.RetryCount != 2 || got.NextRetryAt != nil {
+			t.Fatalf("failed again, retry count is %d and next retry at %v", got.RetryCount, got.NextRetryAt)
+		}
+
+		mustChange(t)(repo.Claim(ctx, got, time.Now().Add(time.Minute)))
+		mustChange(t)(repo.Defer(ctx, request.ID, "REPLAY_DEFERRED", "suppressed", retryAt))
+		got = mustGet(t, repo, request.ID)
+		if got.Status != domain.MintRequestStatusFailed || got.ErrorCode != "REPLAY_DEFERRED" ||
+			got.RetryCount != 2 || got.NextRetryAt == nil || got.NextRetryAt.Sub(retryAt).Abs() > time.Millisecond {
+			t.Fatalf("deferred request is %+v", got)
+		}
+
+		mustChange(t)(repo.Claim(ctx, got, time.Now().Add(time.Minute)))
+		mustChange(t)(repo.MarkCompleted(ctx, request.ID, "0xabc", 42))
+		got = mustGet(t, repo, request.ID)
+		if got.Status != domain.MintRequestStatusCompleted || got.TransactionHash != "0xabc" ||
+			got.BlockNumber != 42 || got.CompletedAt == nil {
+			t.Fatalf("completed request is %+v", got)
+		}
+
+		// Only a processing request has an attempt to record.
+		for name, changed := range map[string]func() (bool, error){
+			"completed": func() (bool, error) { return repo.MarkCompleted(ctx, request.ID, "0xdef", 43) },
+			"failed":    func() (bool, error) { return repo.MarkFailed(ctx, request.ID, "RPC_ERROR", "", nil) },
+			"deferred":  func() (bool, error) { return repo.Defer(ctx, request.ID, "REPLAY_DEFERRED", "", retryAt) },
+		} {
+			if ok, err := changed(); err != nil || ok {
+				t.Fatalf("a completed request was %s again: %v", name, err)
+			}
+		}
+		if got := mustGet(t, repo, request.ID); got.Status != domain.MintRequestStatusCompleted || got.TransactionHash != "0xabc" {
+			t.Fatalf("completed request became %+v", got)
+		}
+	})
+
+	t.Run("a request is claimed once per attempt", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Now()
+		lease := now.Add(time.Minute)
+
+		withStatus := func(status domain.MintRequestStatus, retryAt time.Time) *domain.MintRequest {
+			request := newMintRequest(uuid.New())
+			request.Status = status
+			request.NextRetryAt = &retryAt
+			mustCreate(t, repo, request)
+			return mustGet(t, repo, request.ID)
+		}
+
+		for _, tc := range []struct {
+			name    string
+			request *domain.MintRequest
+			claimed bool
+		}{
+			{"pending", withStatus(domain.MintRequestStatusPending, now), true},
+			{"failed", withStatus(domain.MintRequestStatusFailed, now.Add(time.Hour)), true},
+			{"abandoned", withStatus(domain.MintRequestStatusProcessing, now.Add(-time.Minute)), true},
+			{"still minting", withStatus(domain.MintRequestStatusProcessing, now.Add(time.Minute)), false},
+			{"completed", withStatus(domain.MintRequestStatusCompleted, now), false},
+			{"expired", withStatus(domain.MintRequestStatusExpired, now), false},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				read := *tc.request
+				read.MaxRetries = 9
+				claimed, err := repo.Claim(ctx, &read, lease)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if claimed != tc.claimed {
+					t.Fatalf("claimed = %v, want %v", claimed, tc.claimed)
+				}
+				got := mustGet(t, repo, tc.request.ID)
+				if !tc.claimed {
+					if got.Status != tc.request.Status {
+						t.Fatalf("status = %q, want %q", got.Status, tc.request.Status)
+					}
+					return
+				}
+				if got.Status != domain.MintRequestStatusProcessing || got.MaxRetries != 9 || got.NextRetryAt == nil || got.NextRetryAt.Before(now) {
+					t.Fatalf("claimed request is %+v", got)
+				}
+
+				// A second consumer that read the request before the claim
+				// loses.
+				if again, err := repo.Claim(ctx, tc.request, lease); err != nil || again {
+					t.Fatalf("claimed twice: %v", err)
+				}
+			})
+		}
+
+		// A request that failed again since it was read is not claimed with
+		// the stale copy.
+		stale := withStatus(domain.MintRequestStatusFailed, now)
+		mustChange(t)(repo.Claim(ctx, stale, lease))
+		mustChange(t)(repo.MarkFailed(ctx, stale.ID, "RPC_ERROR", "node unreachable", &now))
+		if claimed, err := repo.Claim(ctx, stale, lease); err != nil || claimed {
+			t.Fatalf("claimed with a stale retry count: %v", err)
+		}
+
+		// Expiring wins over a claim made from before it.
+		expiring := withStatus(domain.MintRequestStatusFailed, now)
+		if _, err := repo.ExpireByCashbackID(ctx, expiring.CashbackID); err != nil {
+			t.Fatal(err)
+		}
+		if claimed, err := repo.Claim(ctx, expiring, lease); err != nil || claimed {
+			t.Fatalf("claimed an expired request: %v", err)
+		}
+	})
+
+	t.Run("pending retries are due, retryable and oldest first", func(t *testing.T) {
+		repo := newRepo(t)
+
+		now := time.Now()
+		withStatus := func(status domain.MintRequestStatus, retryAt time.Time, retryCount int) *domain.MintRequest {
+			request := newMintRequest(uuid.New())
+			request.Status = status
+			request.NextRetryAt = &retryAt
+			request.RetryCount = retryCount
+			mustCreate(t, repo, request)
+			return request
+		}
+		failed := func(retryAt time.Time, retryCount int) *domain.MintRequest {
+			return withStatus(domain.MintRequestStatusFailed, retryAt, retryCount)
+		}
+		later := failed(now.Add(-time.Minute), 1)
+		earliest := failed(now.Add(-time.Hour), 2)
+		latest := failed(now.Add(-time.Second), 0)
+		abandoned := withStatus(domain.MintRequestStatusProcessing, now.Add(-30*time.Minute), 0)
+		failed(now.Add(time.Hour), 0)                                           // not due
+		failed(now.Add(-2*time.Hour), 5)                                        // out of retries
+		withStatus(domain.MintRequestStatusProcessing, now.Add(time.Minute), 0) // still minting
+		withStatus(domain.MintRequestStatusCompleted, now.Add(-3*time.Hour), 1) // minted
+		mustCreate(t, repo, newMintRequest(uuid.New()))                         // pending
+
+		requests, err := repo.GetPendingRetries(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, requests, earliest.ID, abandoned.ID, later.ID, latest.ID)
+
+		requests, err = repo.GetPendingRetries(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, requests, earliest.ID, abandoned.ID)
+	})
+
+	t.Run("expiring stops pending and failed requests only", func(t *testing.T) {
+		repo := newRepo(t)
+
+		pending := newMintRequest(uuid.New())
+		mustCreate(t, repo, pending)
+		expired, err := repo.ExpireByCashbackID(ctx, pending.CashbackID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !expired {
+			t.Fatal("pending request was not expired")
+		}
+		if got := mustGet(t, repo, pending.ID); got.Status != domain.MintRequestStatusExpired {
+			t.Fatalf("status = %q, want expired", got.Status)
+		}
+
+		processing := newMintRequest(uuid.New())
+		processing.Status = domain.MintRequestStatusProcessing
+		mustCreate(t, repo, processing)
+		for _, cashbackID := range []uuid.UUID{processing.CashbackID, uuid.New()} {
+			expired, err := repo.ExpireByCashbackID(ctx, cashbackID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expired {
+				t.Fatalf("expired the request of cashback %s", cashbackID)
+			}
+		}
+		if got := mustGet(t, repo, processing.ID); got.Status != domain.MintRequestStatusProcessing {
+			t.Fatalf("status = %q, want processing", got.Status)
+		}
+	})
+
+	t.Run("scrubbing blanks the wallets of finished requests", func(t *testing.T) {
+		repo := newRepo(t)
+
+		userID := uuid.New()
+		request := func(userID uuid.UUID, status domain.MintRequestStatus, retryCount int) *domain.MintRequest {
+			request := newMintRequest(uuid.New())
+			request.UserID = userID
+			request.Status = status
+			request.RetryCount = retryCount
+			mustCreate(t, repo, request)
+			return request
+		}
+		scrubbed := []*domain.MintRequest{
+			request(userID, domain.MintRequestStatusCompleted, 0),
+			request(userID, domain.MintRequestStatusExpired, 0),
+			request(userID, domain.MintRequestStatusFailed, 5),
+		}
+		kept := []*domain.MintRequest{
+			request(userID, domain.MintRequestStatusFailed, 1),
+			request(userID, domain.MintRequestStatusPending, 0),
+			request(userID, domain.MintRequestStatusProcessing, 0),
+			request(uuid.New(), domain.MintRequestStatusCompleted, 0),
+		}
+
+		n, err := repo.ScrubWalletAddresses(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(len(scrubbed)) {
+			t.Fatalf("scrubbed %d requests, want %d", n, len(scrubbed))
+		}
+		for _, request := range scrubbed {
+			if got := mustGet(t, repo, request.ID); got.WalletAddress != "" {
+				t.Fatalf("%s request kept its wallet", request.Status)
+			}
+		}
+		for _, request := range kept {
+			if got := mustGet(t, repo, request.ID); got.WalletAddress != request.WalletAddress {
+				t.Fatalf("%s request lost its wallet", request.Status)
+			}
+		}
+
+		if n, err := repo.ScrubWalletAddresses(ctx, userID); err != nil || n != 0 {
+			t.Fatalf("scrubbing again scrubbed %d requests, error %v", n, err)
+		}
+	})
+
+	t.Run("counts by status and error code", func(t *testing.T) {
+		repo := newRepo(t)
+
+		for _, r := range []struct {
+			status    domain.MintRequestStatus
+			errorCode string
+		}{
+			{domain.MintRequestStatusPending, ""},
+			{domain.MintRequestStatusPending, ""},
+			{domain.MintRequestStatusFailed, "RPC_ERROR"},
+			{domain.MintRequestStatusFailed, "REVERTED"},
+			{domain.MintRequestStatusFailed, "REVERTED"},
+		} {
+			request := newMintRequest(uuid.New())
+			request.Status = r.status
+			request.ErrorCode = r.errorCode
+			mustCreate(t, repo, request)
+		}
+
+		counts, err := repo.CountByStatus(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.SortFunc(counts, func(a, b repository.StatusCount) int {
+			if a.Status != b.Status {
+				return cmp.Compare(a.Status, b.Status)
+			}
+			return cmp.Compare(a.ErrorCode, b.ErrorCode)
+		})
+		want := []repository.StatusCount{
+			{Status: domain.MintRequestStatusFailed, ErrorCode: "REVERTED", Count: 2},
+			{Status: domain.MintRequestStatusFailed, ErrorCode: "RPC_ERROR", Count: 1},
+			{Status: domain.MintRequestStatusPending, ErrorCode: "", Count: 2},
+		}
+		if !slices.Equal(counts, want) {
+			t.Fatalf("counts = %+v, want %+v", counts, want)
+		}
+	})
+
+	t.Run("reads return copies", func(t *testing.T) {
+		repo := newRepo(t)
+
+		request := newMintRequest(uuid.New())
+		mustCreate(t, repo, request)
+		request.Status = domain.MintRequestStatusFailed
+
+		got := mustGet(t, repo, request.ID)
+		got.Status = domain.MintRequestStatusCompleted
+		if got := mustGet(t, repo, request.ID); got.Status != domain.MintRequestStatusPending {
+			t.Fatalf("status = %q after changing copies, want pending", got.Status)
+		}
+	})
+}
+
+func newMintRequest(cashbackID uuid.UUID) *domain.MintRequest {
+	return &domain.MintRequest{
+		CashbackID:     cashbackID,
+		UserID:         uuid.New(),
+		WalletAddress:  "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		TokenAmount:    "1000000000000000000",
+		IdempotencyKey: uuid.New(),
+	}
+}
+
+func mustCreate(t *testing.T, repo repository.MintRequestRepository, request *domain.MintRequest) {
+	t.Helper()
+	if err := repo.Create(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustGet(t *testing.T, repo repository.MintRequestRepository, id uuid.UUID) *domain.MintRequest {
+	t.Helper()
+	request, err := repo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+func assertIDs(t *testing.T, requests []domain.MintRequest, want ...uuid.UUID) {
+	t.Helper()
+	got := make([]uuid.UUID, len(requests))
+	for i, request := range requests {
+		got[i] = request.ID
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("requests %v, want %v", got, want)
+	}
+}
+
+// mustChange fails the test unless a conditional update changed the request.
+func mustChange(t *testing.T) func(bool, error) {
+	t.Helper()
+	return func(changed bool, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !changed {
+			t.Fatal("the request was not changed")
+		}
+	}
+}
+
+func assertError(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+}
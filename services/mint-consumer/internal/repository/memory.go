@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/domain"
+	"github.com/google/uuid"
+)
+
+// defaultMaxRetries is the column default of mint_requests.max_retries.
+const defaultMaxRetries = 5
+
+type memoryMintRequestRepository struct {
+	mu       sync.RWMutex
+	requests map[uuid.UUID]*domain.MintRequest
+}
+
+// NewMemoryMintRequestRepository returns a MintRequestRepository that keeps
+// mint requests in memory, for tests that do not need a database. Like the
+// table, it fills in the ID, status, retry limit and timestamps left empty and
+// refuses a second request with the same ID or idempotency key.
+func NewMemoryMintRequestRepository() MintRequestRepository {
+	return &memoryMintRequestRepository{requests: make(map[uuid.UUID]*domain.MintRequest)}
+}
+
+func (r *memoryMintRequestRepository) Create(_ context.Context, request *domain.MintRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.requests[request.ID]; ok {
+		return domain.ErrDuplicateMintRequest
+	}
+	return r.insert(request)
+}
+
+func (r *memoryMintRequestRepository) GetByID(_ context.Context, id uuid.UUID) (*domain.MintRequest, error) {
+	return r.first(func(request *domain.MintRequest) bool { return request.ID == id })
+}
+
+func (r *memoryMintRequestRepository) GetByCashbackID(_ context.Context, cashbackID uuid.UUID) (*domain.MintRequest, error) {
+	return r.first(func(request *domain.MintRequest) bool { return request.CashbackID == cashbackID })
+}
+
+func (r *memoryMintRequestRepository) GetByIdempotencyKey(_ context.Context, key uuid.UUID) (*domain.MintRequest, error) {
+	return r.first(func(request *domain.MintRequest) bool { return request.IdempotencyKey == key })
+}
+
+// Update saves request whole, inserting it when there is no request with its
+// ID, as GORM's Save does.
+func (r *memoryMintRequestRepository) Update(_ context.Context, request *domain.MintRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.requests[request.ID]; !ok {
+		return r.insert(request)
+	}
+	if r.keyTaken(request) {
+		return domain.ErrDuplicateMintRequest
+	}
+	request.UpdatedAt = time.Now()
+	r.requests[request.ID] = cloneMintRequest(request)
+	return nil
+}
+
+func (r *memoryMintRequestRepository) UpdateStatus(_ context.Context, id uuid.UUID, status domain.MintRequestStatus) error {
+	r.update(func(request *domain.MintRequest) bool { return request.ID == id }, func(request *domain.MintRequest) {
+		request.Status = status
+	})
+	return nil
+}
+
+func (r *memoryMintRequestRepository) GetPendingRetries(_ context.Context, limit int) ([]domain.MintRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	requests := []domain.MintRequest{}
+	for _, request := range r.requests {
+		retryable := request.Status == domain.MintRequestStatusFailed && request.RetryCount < request.MaxRetries ||
+			request.Status == domain.MintRequestStatusProcessing
+		if retryable && request.NextRetryAt != nil && !request.NextRetryAt.After(now) {
+			requests = append(requests, *cloneMintRequest(request))
+		}
+	}
+	slices.SortFunc(requests, func(a, b domain.MintRequest) int {
+		if c := a.NextRetryAt.Compare(*b.NextRetryAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	// A negative limit means no limit, as in GORM.
+	if limit >= 0 && len(requests) > limit {
+		requests = requests[:limit]
+	}
+	return requests, nil
+}
+
+func (r *memoryMintRequestRepository) Claim(_ context.Context, claimed *domain.MintRequest, leaseEnd time.Time) (bool, error) {
+	now := time.Now()
+	updated := r.update(func(request *domain.MintRequest) bool {
+		if request.ID != claimed.ID || request.Status != claimed.Status || request.RetryCount != claimed.RetryCount {
+			return false
+		}
+		switch request.Status {
+		case domain.MintRequestStatusPending, domain.MintRequestStatusFailed:
+			return true
+		case domain.MintRequestStatusProcessing:
+			return request.NextRetryAt != nil && !request.NextRetryAt.After(now)
+		default:
+			return false
+		}
+	}, func(request *domain.MintRequest) {
+		request.Status = domain.MintRequestStatusProcessing
+		request.NextRetryAt = &leaseEnd
+		request.MaxRetries = claimed.MaxRetries
+	})
+	return updated > 0, nil
+}
+
+func (r *memoryMintRequestRepository) MarkCompleted(_ context.Context, id uuid.UUID, txHash string, blockNumber int64) (bool, error) {
+	now := time.Now().UTC()
+	updated := r.update(processing(id), func(request *domain.MintRequest) {
+		request.Status = domain.MintRequestStatusCompleted
+		request.TransactionHash = txHash
+		request.BlockNumber = blockNumber
+		request.CompletedAt = &now
+	})
+	return updated > 0, nil
+}
+
+func (r *memoryMintRequestRepository) MarkFailed(_ context.Context, id uuid.UUID, errorCode, errorMessage string, nextRetryAt *time.Time) (bool, error) {
+	updated := r.update(processing(id), func(request *domain.MintRequest) {
+		request.Status = domain.MintRequestStatusFailed
+		request.ErrorCode = errorCode
+		request.ErrorMessage = errorMessage
+		request.NextRetryAt = cloneTime(nextRetryAt)
+		request.RetryCount++
+	})
+	return updated > 0, nil
+}
+
+func (r *memoryMintRequestRepository) Defer(_ context.Context, id uuid.UUID, errorCode, errorMessage string, retryAt time.Time) (bool, error) {
+	updated := r.update(processing(id), func(request *domain.MintRequest) {
+		request.Status = domain.MintRequestStatusFailed
+		request.ErrorCode = errorCode
+		request.ErrorMessage = errorMessage
+		request.NextRetryAt = &retryAt
+	})
+	return updated > 0, nil
+}
+
+func (r *memoryMintRequestRepository) ExpireByCashbackID(_ context.Context, cashbackID uuid.UUID) (bool, error) {
+	expired := r.update(func(request *domain.MintRequest) bool {
+		return request.CashbackID == cashbackID &&
+			(request.Status == domain.MintRequestStatusPending || request.Status == domain.MintRequestStatusFailed)
+	}, func(request *domain.MintRequest) {
+		request.Status = domain.MintRequestStatusExpired
+	})
+	return expired > 0, nil
+}
+
+func (r *memoryMintRequestRepository) ScrubWalletAddresses(_ context.Context, userID uuid.UUID) (int64, error) {
+	scrubbed := r.update(func(request *domain.MintRequest) bool {
+		if request.UserID != userID || request.WalletAddress == "" {
+			return false
+		}
+		switch request.Status {
+		case domain.MintRequestStatusCompleted, domain.MintRequestStatusExpired:
+			return true
+		case domain.MintRequestStatusFailed:
+			return request.RetryCount >= request.MaxRetries
+		default:
+			return false
+		}
+	}, func(request *domain.MintRequest) {
+		request.WalletAddress = ""
+	})
+	return scrubbed, nil
+}
+
+func (r *memoryMintRequestRepository) CountByStatus(_ context.Context) ([]StatusCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type group struct {
+		status    domain.MintRequestStatus
+		errorCode string
+	}
+	counts := make(map[group]int64)
+	for _, request := range r.requests {
+		counts[group{request.Status, request.ErrorCode}]++
+	}
+
+	result := make([]StatusCount, 0, len(counts))
+	for g, count := range counts {
+		result = append(result, StatusCount{Status: g.status, ErrorCode: g.errorCode, Count: count})
+	}
+	return result, nil
+}
+
+// insert stores a copy of request, filling in what the column defaults would.
+// The caller holds the lock and has checked the ID is free.
+func (r *memoryMintRequestRepository) insert(request *domain.MintRequest) error {
+	if r.keyTaken(request) {
+		return domain.ErrDuplicateMintRequest
+	}
+
+	if request.ID == uuid.Nil {
+		request.ID = uuid.New()
+	}
+	if request.Status == "" {
+		request.Status = domain.MintRequestStatusPending
+	}
+	if request.MaxRetries == 0 {
+		request.MaxRetries = defaultMaxRetries
+	}
+	now := time.Now()
+	if request.CreatedAt.IsZero() {
+		request.CreatedAt = now
+	}
+	if request.UpdatedAt.IsZero() {
+		request.UpdatedAt = now
+	}
+	r.requests[request.ID] = cloneMintRequest(request)
+	return nil
+}
+
+// keyTaken reports whether a request other than request has its idempotency key.
+func (r *memoryMintRequestRepository) keyTaken(request *domain.MintRequest) bool {
+	for _, other := range r.requests {
+		if other.ID != request.ID && other.IdempotencyKey == request.IdempotencyKey {
+			return true
+		}
+	}
+	return false
+}
+
+// first returns a copy of the matching request with the lowest ID, the one
+// Postgres returns for First.
+func (r *memoryMintRequestRepository) first(match func(*domain.MintRequest) bool) (*domain.MintRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *domain.MintRequest
+	for _, request := range r.requests {
+		if match(request) && (found == nil || bytes.Compare(request.ID[:], found.ID[:]) < 0) {
+			found = request
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrMintRequestNotFound
+	}
+	return cloneMintRequest(found), nil
+}
+
+// update applies fn to every matching request and returns how many there were.
+func (r *memoryMintRequestRepository) update(match func(*domain.MintRequest) bool, fn func(*domain.MintRequest)) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var updated int64
+	now := time.Now()
+	for _, request := range r.requests {
+		if match(request) {
+			fn(request)
+			request.UpdatedAt = now
+			updated++
+		}
+	}
+	return updated
+}
+
+// processing matches the request with id while it is processing.
+func processing(id uuid.UUID) func(*domain.MintRequest) bool {
+	return func(request *domain.MintRequest) bool {
+		return request.ID == id && request.Status == domain.MintRequestStatusProcessing
+	}
+}
+
+func cloneMintRequest(request *domain.MintRequest) *domain.MintRequest {
+	clone := *request
+	clone.NextRetryAt = cloneTime(request.NextRetryAt)
+	clone.CompletedAt = cloneTime(request.CompletedAt)
+	return &clone
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/cashback-platform/services/mint-consumer/internal/domain"
@@ -18,8 +19,20 @@ type (
 		Update(ctx context.Context, request *domain.MintRequest) error
 		UpdateStatus(ctx context.Context, id uuid.UUID, status domain.MintRequestStatus) error
 		GetPendingRetries(ctx context.Context, limit int) ([]domain.MintRequest, error)
-		MarkCompleted(ctx context.Context, id uuid.UUID, txHash string, blockNumber int64) error
-		MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string, nextRetryAt *time.Time) error
+		Claim(ctx context.Context, request *domain.MintRequest, leaseEnd time.Time) (bool, error)
+		MarkCompleted(ctx context.Context, id uuid.UUID, txHash string, blockNumber int64) (bool, error)
+		MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string, nextRetryAt *time.Time) (bool, error)
+		Defer(ctx context.Context, id uuid.UUID, errorCode, errorMessage string, retryAt time.Time) (bool, error)
+		ExpireByCashbackID(ctx context.Context, cashbackID uuid.UUID) (bool, error)
+		ScrubWalletAddresses(ctx context.Context, userID uuid.UUID) (int64, error)
+		CountByStatus(ctx context.Context) ([]StatusCount, error)
+	}
+
+	// StatusCount is the number of mint requests with a status and error code.
+	StatusCount struct {
+		Status    domain.MintRequestStatus
+		ErrorCode string
+		Count     int64
 	}
 
 	mintRequestRepository struct {
@@ -32,13 +45,13 @@ func NewMintRequestRepository(db *gorm.DB) MintRequestRepository {
 }
 
 func (r *mintRequestRepository) Create(ctx context.Context, request *domain.MintRequest) error {
-	return r.db.WithContext(ctx).Create(request).Error
+	return mintRequestError(r.db.WithContext(ctx).Create(request).Error)
 }
 
 func (r *mintRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MintRequest, error) {
 	var request domain.MintRequest
 	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&request).Error; err != nil {
-		return nil, err
+		return nil, mintRequestError(err)
 	}
 	return &request, nil
 }
@@ -46,7 +59,7 @@ func (r *mintRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 func (r *mintRequestRepository) GetByCashbackID(ctx context.Context, cashbackID uuid.UUID) (*domain.MintRequest, error) {
 	var request domain.MintRequest
 	if err := r.db.WithContext(ctx).Where("cashback_id = ?", cashbackID).First(&request).Error; err != nil {
-		return nil, err
+		return nil, mintRequestError(err)
 	}
 	return &request, nil
 }
@@ -54,46 +67,145 @@ func (r *mintRequestRepository) GetByCashbackID(ctx context.Context, cashbackID
 func (r *mintRequestRepository) GetByIdempotencyKey(ctx context.Context, key uuid.UUID) (*domain.MintRequest, error) {
 	var request domain.MintRequest
 	if err := r.db.WithContext(ctx).Where("idempotency_key = ?", key).First(&request).Error; err != nil {
-		return nil, err
+		return nil, mintRequestError(err)
 	}
 	return &request, nil
 }
 
 func (r *mintRequestRepository) Update(ctx context.Context, request *domain.MintRequest) error {
-	return r.db.WithContext(ctx).Save(request).Error
+	return mintRequestError(r.db.WithContext(ctx).Save(request).Error)
 }
 
 func (r *mintRequestRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.MintRequestStatus) error {
 	return r.db.WithContext(ctx).Model(&domain.MintRequest{}).Where("id = ?", id).Update("status", status).Error
 }
 
+// GetPendingRetries returns the failed requests due for a retry, and the
+// processing requests whose attempt ran past its next_retry_at, which were
+// abandoned by a consumer that stopped mid-mint.
 func (r *mintRequestRepository) GetPendingRetries(ctx context.Context, limit int) ([]domain.MintRequest, error) {
 	var requests []domain.MintRequest
 	now := time.Now().UTC()
 	err := r.db.WithContext(ctx).
-		Where("status = ? AND next_retry_at <= ? AND retry_count < max_retries", domain.MintRequestStatusFailed, now).
+		Where("next_retry_at <= ?", now).
+		Where(r.db.Where("status = ? AND retry_count < max_retries", domain.MintRequestStatusFailed).
+			Or("status = ?", domain.MintRequestStatusProcessing)).
 		Order("next_retry_at ASC").
 		Limit(limit).
 		Find(&requests).Error
 	return requests, err
 }
 
-func (r *mintRequestRepository) MarkCompleted(ctx context.Context, id uuid.UUID, txHash string, blockNumber int64) error {
+// Claim moves request to processing until leaseEnd, with request.MaxRetries
+// as its retry limit, if it is still as it was read: pending, failed with the
+// same retry count, or processing with a lease that ran out. It returns false
+// when another consumer claimed, finished or expired the request first.
+func (r *mintRequestRepository) Claim(ctx context.Context, request *domain.MintRequest, leaseEnd time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.MintRequest{}).
+		Where("id = ? AND status = ? AND retry_count = ?", request.ID, request.Status, request.RetryCount).
+		Where(r.db.Where("status IN ?", []domain.MintRequestStatus{
+			domain.MintRequestStatusPending,
+			domain.MintRequestStatusFailed,
+		}).Or("status = ? AND next_retry_at <= ?", domain.MintRequestStatusProcessing, time.Now().UTC())).
+		Updates(map[string]any{
+			"status":        domain.MintRequestStatusProcessing,
+			"next_retry_at": leaseEnd,
+			"max_retries":   request.MaxRetries,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkCompleted records the mint of a processing request. It returns false
+// when the request is no longer processing.
+func (r *mintRequestRepository) MarkCompleted(ctx context.Context, id uuid.UUID, txHash string, blockNumber int64) (bool, error) {
 	now := time.Now().UTC()
-	return r.db.WithContext(ctx).Model(&domain.MintRequest{}).Where("id = ?", id).Updates(map[string]any{
-		"status":           domain.MintRequestStatusCompleted,
-		"transaction_hash": txHash,
-		"block_number":     blockNumber,
-		"completed_at":     &now,
-	}).Error
-}
-
-func (r *mintRequestRepository) MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string, nextRetryAt *time.Time) error {
-	return r.db.WithContext(ctx).Model(&domain.MintRequest{}).Where("id = ?", id).Updates(map[string]any{
-		"status":        domain.MintRequestStatusFailed,
-		"error_code":    errorCode,
-		"error_message": errorMessage,
-		"next_retry_at": nextRetryAt,
-		"retry_count":   gorm.Expr("retry_count + 1"),
-	}).Error
+	result := r.db.WithContext(ctx).Model(&domain.MintRequest{}).
+		Where("id = ? AND status = ?", id, domain.MintRequestStatusProcessing).
+		Updates(map[string]any{
+			"status":           domain.MintRequestStatusCompleted,
+			"transaction_hash": txHash,
+			"block_number":     blockNumber,
+			"completed_at":     &now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkFailed records a failed attempt of a processing request, counting it
+// against the retries. It returns false when the request is no longer
+// processing.
+func (r *mintRequestRepository) MarkFailed(ctx context.Context, id uuid.UUID, errorCode, errorMessage string, nextRetryAt *time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.MintRequest{}).
+		Where("id = ? AND status = ?", id, domain.MintRequestStatusProcessing).
+		Updates(map[string]any{
+			"status":        domain.MintRequestStatusFailed,
+			"error_code":    errorCode,
+			"error_message": errorMessage,
+			"next_retry_at": nextRetryAt,
+			"retry_count":   gorm.Expr("retry_count + 1"),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Defer hands a processing request back as failed and due at retryAt without
+// counting an attempt. It returns false when the request is no longer
+// processing.
+func (r *mintRequestRepository) Defer(ctx context.Context, id uuid.UUID, errorCode, errorMessage string, retryAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.MintRequest{}).
+		Where("id = ? AND status = ?", id, domain.MintRequestStatusProcessing).
+		Updates(map[string]any{
+			"status":        domain.MintRequestStatusFailed,
+			"error_code":    errorCode,
+			"error_message": errorMessage,
+			"next_retry_at": retryAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ExpireByCashbackID stops a pending or failed mint request from being retried.
+// Returns false if there is no such request, e.g. it is in flight or already completed.
+func (r *mintRequestRepository) ExpireByCashbackID(ctx context.Context, cashbackID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.MintRequest{}).
+		Where("cashback_id = ? AND status IN ?", cashbackID, []domain.MintRequestStatus{
+			domain.MintRequestStatusPending,
+			domain.MintRequestStatusFailed,
+		}).
+		Update("status", domain.MintRequestStatusExpired)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ScrubWalletAddresses blanks the wallet address copied onto the user's
+// finished mint requests. Requests that may still be minted keep theirs.
+// Returns the number of requests scrubbed.
+func (r *mintRequestRepository) ScrubWalletAddresses(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&domain.MintRequest{}).
+		Where("user_id = ? AND wallet_address <> ''", userID).
+		Where(r.db.Where("status IN ?", []domain.MintRequestStatus{
+			domain.MintRequestStatusCompleted,
+			domain.MintRequestStatusExpired,
+		}).Or("status = ? AND retry_count >= max_retries", domain.MintRequestStatusFailed)).
+		Update("wallet_address", "")
+	return result.RowsAffected, result.Error
+}
+
+// CountByStatus counts mint requests by status and error code.
+func (r *mintRequestRepository) CountByStatus(ctx context.Context) ([]StatusCount, error) {
+	var counts []StatusCount
+	err := r.db.WithContext(ctx).Model(&domain.MintRequest{}).
+		Select("status, COALESCE(error_code, '') AS error_code, COUNT(*) AS count").
+		Group("status, COALESCE(error_code, '')").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// mintRequestError maps the errors GORM reports for a missing or conflicting
+// row to their domain errors.
+func mintRequestError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.ErrMintRequestNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return domain.ErrDuplicateMintRequest
+	default:
+		return err
+	}
 }
@@ -0,0 +1,22 @@
+package tracing
+
+import "github.com/nats-io/nats.go"
+
+// HeaderCarrier reads and writes the trace context in NATS message headers.
+type HeaderCarrier nats.Header
+
+func (c HeaderCarrier) Get(key string) string {
+	return nats.Header(c).Get(key)
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	nats.Header(c).Set(key, value)
+}
+
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
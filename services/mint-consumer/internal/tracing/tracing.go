@@ -0,0 +1,29 @@
+// Package tracing sets up OpenTelemetry tracing for the service. Handling a
+// message continues the trace found in its NATS headers, and calls to the
+// blockchain adapter pass it on in the gRPC metadata.
+package tracing
+
+import (
+	"context"
+
+	"github.com/cashback-platform/pkg/tracing"
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"go.uber.org/fx"
+)
+
+const serviceName = "mint-consumer"
+
+// Setup installs the shared tracer setup for TRACING_EXPORTER and flushes the
+// spans still batched when the service stops.
+func Setup(lc fx.Lifecycle, cfg *config.Config) error {
+	shutdown, err := tracing.Setup(serviceName, tracing.Config(cfg.Tracing))
+	if err != nil {
+		return err
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return shutdown(ctx)
+		},
+	})
+	return nil
+}
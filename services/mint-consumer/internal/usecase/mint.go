@@ -1,19 +1,397 @@
 package usecase
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"time"
 
-type MintUsecase struct{}
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/domain"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/grpc"
+	"github.com/cashback-platform/services/mint-consumer/internal/replay"
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+	"github.com/google/uuid"
+)
 
-func NewMintUsecase() *MintUsecase {
-	return &MintUsecase{}
+const (
+	SubjectTokenMinted     = "token.minted"
+	SubjectTokenMintFailed = "token.mint.failed"
+)
+
+// Error codes recorded on mint requests for failures the adapter did not
+// report itself.
+const (
+	ErrorCodeAdapterUnavailable = "ADAPTER_UNAVAILABLE"
+	ErrorCodeNotConfirmed       = "NOT_CONFIRMED"
+	ErrorCodeReplayDeferred     = "REPLAY_DEFERRED"
+	ErrorCodeNoWallet           = "NO_WALLET"
+	ErrorCodeInvalidAmount      = "INVALID_AMOUNT"
+)
+
+// retryBatchSize is the most mint requests retried on one tick of the retry
+// loop.
+const retryBatchSize = 50
+
+// errNotClaimed reports that another consumer claimed, finished or expired a
+// mint request before this one could claim it.
+var errNotClaimed = errors.New("mint request claimed elsewhere")
+
+// idempotencyNamespace derives the idempotency key of a mint from its
+// cashback ID, so the adapter mints each cashback once however many mint
+// requests end up being made for it.
+var idempotencyNamespace = uuid.MustParse("ffad7179-22a5-4c55-8c83-1f07419ad621")
+
+type (
+	// Minter mints tokens through the blockchain adapter.
+	Minter interface {
+		MintToken(ctx context.Context, idempotencyKey, walletAddress, tokenAmount string) (*grpc.MintResult, error)
+	}
+
+	// EventPublisher publishes events to NATS.
+	EventPublisher interface {
+		Publish(ctx context.Context, subject string, data []byte) error
+	}
+
+	MintUsecase struct {
+		mintRequestRepo repository.MintRequestRepository
+		minter          Minter
+		publisher       EventPublisher
+		tokenDecimals   int
+		maxRetries      int
+		retryBackoff    time.Duration
+		attemptTimeout  time.Duration
+		scrubOnErasure  bool
+		log             *slog.Logger
+	}
+)
+
+func NewMintUsecase(
+	mintRequestRepo repository.MintRequestRepository,
+	minter Minter,
+	publisher EventPublisher,
+	cfg *config.Config,
+	log *slog.Logger,
+) *MintUsecase {
+	return &MintUsecase{
+		mintRequestRepo: mintRequestRepo,
+		minter:          minter,
+		publisher:       publisher,
+		tokenDecimals:   cfg.Mint.TokenDecimals,
+		maxRetries:      cfg.Mint.MaxRetries,
+		retryBackoff:    cfg.Mint.RetryBackoff,
+		attemptTimeout:  cfg.Mint.AttemptTimeout,
+		scrubOnErasure:  cfg.Erasure.ScrubWalletAddresses,
+		log:             log,
+	}
+}
+
+// ProcessCashbackApproved records a mint request for approved cashback and
+// mints it. Redelivered events mint a request that was recorded but never
+// attempted, and publish token.minted again for a completed one in case it
+// was lost; requests that failed are left to the retry loop. In replay mode
+// new requests are recorded and left for the live consumer to mint. Cashback
+// without a wallet, or worth less than a token unit, can never be minted; it
+// is recorded as failed for good, so the cashback fails and can expire.
+func (u MintUsecase) ProcessCashbackApproved(ctx context.Context, data []byte) error {
+	var event domain.CashbackApprovedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode cashback.approved event: %w", err)
+	}
+	ctx = logger.WithCashbackID(ctx, event.CashbackID.String())
+
+	if event.WalletAddress == "" {
+		return u.reject(ctx, event, ErrorCodeNoWallet, "approved cashback has no wallet to mint to")
+	}
+	amount, err := tokenAmount(event.Amount, u.tokenDecimals)
+	if err != nil {
+		return u.reject(ctx, event, ErrorCodeInvalidAmount, fmt.Sprintf("amount %v cannot be minted: %v", event.Amount, err))
+	}
+
+	request, err := u.mintRequestRepo.GetByCashbackID(ctx, event.CashbackID)
+	switch {
+	case errors.Is(err, domain.ErrMintRequestNotFound):
+		request = &domain.MintRequest{
+			ID:             uuid.New(),
+			CashbackID:     event.CashbackID,
+			UserID:         event.UserID,
+			WalletAddress:  event.WalletAddress,
+			TokenAmount:    amount,
+			IdempotencyKey: uuid.NewSHA1(idempotencyNamespace, event.CashbackID[:]),
+			Status:         domain.MintRequestStatusPending,
+			MaxRetries:     u.maxRetries,
+		}
+		if err := u.mintRequestRepo.Create(ctx, request); err != nil {
+			if errors.Is(err, domain.ErrDuplicateMintRequest) {
+				// Another delivery of the event got there first.
+				return nil
+			}
+			return err
+		}
+	case err != nil:
+		return err
+	case request.Status == domain.MintRequestStatusCompleted && !replay.Active(ctx):
+		return u.publish(ctx, SubjectTokenMinted, domain.NewTokenMintedEvent(request))
+	case request.Status != domain.MintRequestStatusPending:
+		return nil
+	}
+
+	if err := u.mint(ctx, request); !errors.Is(err, errNotClaimed) {
+		return err
+	}
+	return nil
+}
+
+// reject records the mint request of approved cashback that can never be
+// minted as failed without a retry, and publishes token.mint.failed. A
+// redelivered event publishes the failure again in case it was lost.
+func (u MintUsecase) reject(ctx context.Context, event domain.CashbackApprovedEvent, code, message string) error {
+	request := &domain.MintRequest{
+		ID:             uuid.New(),
+		CashbackID:     event.CashbackID,
+		UserID:         event.UserID,
+		WalletAddress:  event.WalletAddress,
+		IdempotencyKey: uuid.NewSHA1(idempotencyNamespace, event.CashbackID[:]),
+		Status:         domain.MintRequestStatusFailed,
+		ErrorCode:      code,
+		ErrorMessage:   message,
+	}
+	err := u.mintRequestRepo.Create(ctx, request)
+	if errors.Is(err, domain.ErrDuplicateMintRequest) {
+		request, err = u.mintRequestRepo.GetByCashbackID(ctx, event.CashbackID)
+		if err != nil {
+			return err
+		}
+		if request.Status != domain.MintRequestStatusFailed || request.NextRetryAt != nil {
+			return nil
+		}
+	} else if err != nil {
+		return err
+	}
+
+	u.log.WarnContext(ctx, "approved cashback cannot be minted", "mint_request_id", request.ID, "error_code", code, "error", message)
+	return u.publish(ctx, SubjectTokenMintFailed, domain.NewTokenMintFailedEvent(request))
+}
+
+// ProcessCashbackExpired stops retrying the mint request of expired cashback.
+func (u MintUsecase) ProcessCashbackExpired(ctx context.Context, data []byte) error {
+	var event domain.CashbackExpiredEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode cashback.expired event: %w", err)
+	}
+	ctx = logger.WithCashbackID(ctx, event.CashbackID.String())
+
+	expired, err := u.mintRequestRepo.ExpireByCashbackID(ctx, event.CashbackID)
+	if err != nil {
+		return err
+	}
+	if expired {
+		u.log.InfoContext(ctx, "mint request expired")
+	}
+	return nil
+}
+
+// ProcessUserErased scrubs the wallet addresses copied onto an erased user's
+// mint requests when ERASURE_SCRUB_WALLET_ADDRESSES requires it. Requests are
+// kept, linked by user ID, so mint history stays auditable.
+func (u MintUsecase) ProcessUserErased(ctx context.Context, data []byte) error {
+	var event domain.UserErasedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode user.erased event: %w", err)
+	}
+
+	if !u.scrubOnErasure {
+		return nil
+	}
+
+	scrubbed, err := u.mintRequestRepo.ScrubWalletAddresses(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	if scrubbed > 0 {
+		u.log.InfoContext(ctx, "scrubbed wallet addresses of erased user", "user_id", event.UserID, "mint_requests", scrubbed)
+	}
+	return nil
 }
 
-func (MintUsecase) ProcessCashbackApproved(_ context.Context, _ []byte) error {
-	// TODO: Implementar lógica de processamento
+// RetryFailedMints mints again the requests due for a retry. A request that
+// fails again is logged and the others are still retried.
+func (u MintUsecase) RetryFailedMints(ctx context.Context) error {
+	requests, err := u.mintRequestRepo.GetPendingRetries(ctx, retryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range requests {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		request := &requests[i]
+		requestCtx := logger.WithCashbackID(ctx, request.CashbackID.String())
+		u.log.InfoContext(requestCtx, "retrying mint", "mint_request_id", request.ID, "retry_count", request.RetryCount, "error_code", request.ErrorCode)
+		err := u.mint(requestCtx, request)
+		switch {
+		case errors.Is(err, errNotClaimed):
+			u.log.DebugContext(requestCtx, "mint retried by another consumer", "mint_request_id", request.ID)
+		case err != nil:
+			u.log.ErrorContext(requestCtx, "failed to retry mint", "mint_request_id", request.ID, "error", err)
+		}
+	}
 	return nil
 }
 
-func (MintUsecase) RetryFailedMints(_ context.Context) error {
-	// TODO: Implementar lógica de retry
+// RetryMint mints a failed request again at once, granting it one more
+// attempt when its retries are used up. With dryRun nothing is written or
+// minted, and the request is returned as it would be retried.
+func (u MintUsecase) RetryMint(ctx context.Context, id uuid.UUID, dryRun bool) (*domain.MintRequest, error) {
+	request, err := u.mintRequestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != domain.MintRequestStatusFailed {
+		return nil, fmt.Errorf("%w: status is %s", domain.ErrMintNotRetryable, request.Status)
+	}
+	if request.WalletAddress == "" || request.TokenAmount == "" {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMintNotRetryable, request.ErrorCode)
+	}
+	if request.RetryCount >= request.MaxRetries {
+		request.MaxRetries = request.RetryCount + 1
+	}
+	if dryRun {
+		return request, nil
+	}
+
+	ctx = logger.WithCashbackID(ctx, request.CashbackID.String())
+	u.log.InfoContext(ctx, "retrying mint on request", "mint_request_id", request.ID, "retry_count", request.RetryCount, "error_code", request.ErrorCode)
+	if err := u.mint(ctx, request); err != nil {
+		if errors.Is(err, errNotClaimed) {
+			return nil, fmt.Errorf("%w: another consumer is minting it", domain.ErrMintNotRetryable)
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// mint claims request and asks the adapter to mint it, recording the
+// outcome. The claim moves the request to processing with its next retry set
+// to when the attempt times out, so a request abandoned by a consumer that
+// stopped mid-mint is retried. It returns errNotClaimed when another consumer
+// got to the request first.
+func (u MintUsecase) mint(ctx context.Context, request *domain.MintRequest) error {
+	leaseEnd := time.Now().UTC().Add(u.attemptTimeout)
+	claimed, err := u.mintRequestRepo.Claim(ctx, request, leaseEnd)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return errNotClaimed
+	}
+	request.Status = domain.MintRequestStatusProcessing
+	request.NextRetryAt = &leaseEnd
+
+	attemptCtx, cancel := context.WithTimeout(ctx, u.attemptTimeout)
+	result, err := u.minter.MintToken(attemptCtx, request.IdempotencyKey.String(), request.WalletAddress, request.TokenAmount)
+	cancel()
+	switch {
+	case errors.Is(err, replay.ErrSuppressed):
+		// The request is left for the live consumer's retry loop to mint.
+		now := time.Now().UTC()
+		if _, err := u.mintRequestRepo.Defer(ctx, request.ID, ErrorCodeReplayDeferred, err.Error(), now); err != nil {
+			return err
+		}
+		request.Status = domain.MintRequestStatusFailed
+		request.ErrorCode = ErrorCodeReplayDeferred
+		request.ErrorMessage = err.Error()
+		request.NextRetryAt = &now
+		return nil
+	case err != nil:
+		return u.fail(ctx, request, ErrorCodeAdapterUnavailable, err.Error(), true)
+	case !result.Success:
+		return u.fail(ctx, request, result.ErrorCode, result.ErrorMessage, result.Retryable)
+	case !result.Confirmed:
+		// The adapter stopped waiting for the receipt. Asking again with the
+		// same idempotency key checks on the same transaction.
+		return u.fail(ctx, request, ErrorCodeNotConfirmed, fmt.Sprintf("transaction %s is not mined yet", result.TransactionHash), true)
+	}
+
+	completed, err := u.mintRequestRepo.MarkCompleted(ctx, request.ID, result.TransactionHash, result.BlockNumber)
+	if err != nil {
+		return err
+	}
+	if !completed {
+		// The lease ran out and another consumer took the request over; it
+		// records and announces the mint of the same transaction.
+		u.log.WarnContext(ctx, "mint request taken over before it was completed", "mint_request_id", request.ID, "transaction_hash", result.TransactionHash)
+		return errNotClaimed
+	}
+	request.Status = domain.MintRequestStatusCompleted
+	request.TransactionHash = result.TransactionHash
+	request.BlockNumber = result.BlockNumber
+	u.log.InfoContext(ctx, "tokens minted", "mint_request_id", request.ID, "transaction_hash", result.TransactionHash, "block_number", result.BlockNumber)
+
+	return u.publish(ctx, SubjectTokenMinted, domain.NewTokenMintedEvent(request))
+}
+
+// fail records a failed attempt. Retryable failures are retried after the
+// retry backoff, doubled for each earlier failure, until the request is out
+// of retries.
+func (u MintUsecase) fail(ctx context.Context, request *domain.MintRequest, code, message string, retryable bool) error {
+	var nextRetryAt *time.Time
+	if retryable && request.RetryCount+1 < request.MaxRetries {
+		at := time.Now().UTC().Add(u.retryBackoff << request.RetryCount)
+		nextRetryAt = &at
+	}
+	failed, err := u.mintRequestRepo.MarkFailed(ctx, request.ID, code, message, nextRetryAt)
+	if err != nil {
+		return err
+	}
+	if !failed {
+		u.log.WarnContext(ctx, "mint request taken over before its failure was recorded", "mint_request_id", request.ID, "error_code", code)
+		return errNotClaimed
+	}
+	request.Status = domain.MintRequestStatusFailed
+	request.ErrorCode = code
+	request.ErrorMessage = message
+	request.NextRetryAt = nextRetryAt
+	request.RetryCount++
+	u.log.WarnContext(ctx, "mint failed",
+		"mint_request_id", request.ID,
+		"error_code", code,
+		"error", message,
+		"retry_count", request.RetryCount,
+		"next_retry_at", nextRetryAt,
+	)
+
+	return u.publish(ctx, SubjectTokenMintFailed, domain.NewTokenMintFailedEvent(request))
+}
+
+func (u MintUsecase) publish(ctx context.Context, subject string, event any) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := u.publisher.Publish(ctx, subject, data); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", subject, err)
+	}
 	return nil
 }
+
+// tokenAmount converts a cashback amount to token units, the integer the
+// token contract counts in. Fractions of a unit are dropped.
+func tokenAmount(amount float64, decimals int) (string, error) {
+	value, ok := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	if !ok {
+		return "", fmt.Errorf("invalid amount %v", amount)
+	}
+	value.Mul(value, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	units := new(big.Int).Quo(value.Num(), value.Denom())
+	if units.Sign() <= 0 {
+		return "", errors.New("amount is not positive")
+	}
+	return units.String(), nil
+}
@@ -0,0 +1,553 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/domain"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/grpc"
+	"github.com/cashback-platform/services/mint-consumer/internal/replay"
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+	"github.com/cashback-platform/services/mint-consumer/internal/usecase"
+	"github.com/google/uuid"
+)
+
+const wallet = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+// minter answers mints with the queued results, then with confirmed mints.
+// Like the adapter client, it mints nothing in replay mode.
+type minter struct {
+	mu      sync.Mutex
+	results []mintResult
+	calls   []string
+}
+
+type mintResult struct {
+	result *grpc.MintResult
+	err    error
+}
+
+func (m *minter) MintToken(ctx context.Context, idempotencyKey, _, tokenAmount string) (*grpc.MintResult, error) {
+	if replay.Active(ctx) {
+		return nil, replay.ErrSuppressed
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, idempotencyKey+" "+tokenAmount)
+	if len(m.results) > 0 {
+		next := m.results[0]
+		m.results = m.results[1:]
+		return next.result, next.err
+	}
+	return &grpc.MintResult{Success: true, Confirmed: true, TransactionHash: "0xabc", BlockNumber: 7}, nil
+}
+
+type publisher struct {
+	mu       sync.Mutex
+	subjects []string
+}
+
+func (p *publisher) Publish(_ context.Context, subject string, _ []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subjects = append(p.subjects, subject)
+	return nil
+}
+
+type fixture struct {
+	requests  repository.MintRequestRepository
+	minter    *minter
+	publisher *publisher
+	usecase   *usecase.MintUsecase
+}
+
+func newFixture(results ...mintResult) *fixture {
+	f := &fixture{
+		requests:  repository.NewMemoryMintRequestRepository(),
+		minter:    &minter{results: results},
+		publisher: &publisher{},
+	}
+	cfg := &config.Config{Mint: config.MintConfig{
+		TokenDecimals:  18,
+		MaxRetries:     3,
+		RetryBackoff:   time.Minute,
+		AttemptTimeout: time.Minute,
+	}}
+	f.usecase = usecase.NewMintUsecase(f.requests, f.minter, f.publisher, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return f
+}
+
+func approved(t *testing.T, cashbackID uuid.UUID, amount float64) []byte {
+	t.Helper()
+	data, err := json.Marshal(domain.CashbackApprovedEvent{
+		CashbackID:    cashbackID,
+		UserID:        uuid.New(),
+		WalletAddress: wallet,
+		Amount:        amount,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func (f *fixture) request(t *testing.T, cashbackID uuid.UUID) *domain.MintRequest {
+	t.Helper()
+	request, err := f.requests.GetByCashbackID(context.Background(), cashbackID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+// due makes the retry of the request of cashbackID due now.
+func (f *fixture) due(t *testing.T, cashbackID uuid.UUID) {
+	t.Helper()
+	request := f.request(t, cashbackID)
+	now := time.Now().Add(-time.Second)
+	request.NextRetryAt = &now
+	if err := f.requests.Update(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessCashbackApprovedMintsTheAmountInTokenUnits(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture()
+	cashbackID := uuid.New()
+
+	if err := f.usecase.ProcessCashbackApproved(ctx, approved(t, cashbackID, 12.5)); err != nil {
+		t.Fatal(err)
+	}
+
+	request := f.request(t, cashbackID)
+	if request.Status != domain.MintRequestStatusCompleted || request.TransactionHash != "0xabc" || request.BlockNumber != 7 {
+		t.Fatalf("request is %s with transaction %q in block %d", request.Status, request.TransactionHash, request.BlockNumber)
+	}
+	if request.TokenAmount != "12500000000000000000" {
+		t.Fatalf("minted %s token units, want 12500000000000000000", request.TokenAmount)
+	}
+	if len(f.minter.calls) != 1 || len(f.publisher.subjects) != 1 || f.publisher.subjects[0] != usecase.SubjectTokenMinted {
+		t.Fatalf("minted %v, published %v", f.minter.calls, f.publisher.subjects)
+	}
+}
+
+func TestProcessCashbackApprovedMintsEachCashbackOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture()
+	cashbackID := uuid.New()
+	event := approved(t, cashbackID, 5)
+
+	for range 2 {
+		if err := f.usecase.ProcessCashbackApproved(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(f.minter.calls) != 1 {
+		t.Fatalf("minted %d times, want once", len(f.minter.calls))
+	}
+	// The redelivery publishes token.minted again, in case it was lost.
+	if want := []string{usecase.SubjectTokenMinted, usecase.SubjectTokenMinted}; len(f.publisher.subjects) != len(want) {
+		t.Fatalf("published %v, want %v", f.publisher.subjects, want)
+	}
+
+	// The idempotency key is derived from the cashback, so a request lost
+	// and recorded again mints through the same adapter transaction.
+	other := newFixture()
+	if err := other.usecase.ProcessCashbackApproved(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	if f.minter.calls[0] != other.minter.calls[0] {
+		t.Fatalf("mints %q and %q of one cashback differ", f.minter.calls[0], other.minter.calls[0])
+	}
+}
+
+func TestProcessCashbackApprovedFailsCashbackItCannotMint(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		event domain.CashbackApprovedEvent
+		code  string
+	}{
+		{"no wallet", domain.CashbackApprovedEvent{CashbackID: uuid.New(), Amount: 5}, usecase.ErrorCodeNoWallet},
+		{"zero amount", domain.CashbackApprovedEvent{CashbackID: uuid.New(), WalletAddress: wallet}, usecase.ErrorCodeInvalidAmount},
+		{"less than a token unit", domain.CashbackApprovedEvent{CashbackID: uuid.New(), WalletAddress: wallet, Amount: 1e-19}, usecase.ErrorCodeInvalidAmount},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newFixture()
+			data, err := json.Marshal(tc.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// A redelivered event announces the failure again, in case the
+			// first announcement was lost.
+			for range 2 {
+				if err := f.usecase.ProcessCashbackApproved(ctx, data); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(f.minter.calls) != 0 {
+				t.Fatalf("minted %v", f.minter.calls)
+			}
+			request := f.request(t, tc.event.CashbackID)
+			if request.Status != domain.MintRequestStatusFailed || request.ErrorCode != tc.code || request.NextRetryAt != nil {
+				t.Fatalf("request is %+v, want failed for good with %s", request, tc.code)
+			}
+			if len(f.publisher.subjects) != 2 || f.publisher.subjects[0] != usecase.SubjectTokenMintFailed || f.publisher.subjects[1] != usecase.SubjectTokenMintFailed {
+				t.Fatalf("published %v, want token.mint.failed twice", f.publisher.subjects)
+			}
+
+			// Nothing a retry could mint.
+			if _, err := f.usecase.RetryMint(ctx, request.ID, false); !errors.Is(err, domain.ErrMintNotRetryable) {
+				t.Fatalf("retrying it: %v, want ErrMintNotRetryable", err)
+			}
+			if err := f.usecase.RetryFailedMints(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if len(f.minter.calls) != 0 {
+				t.Fatalf("minted %v", f.minter.calls)
+			}
+		})
+	}
+}
+
+func TestFailedMintsAreRetriedUntilMinted(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(
+		mintResult{err: errors.New("connection refused")},
+		mintResult{result: &grpc.MintResult{ErrorCode: "NOT_BROADCAST", ErrorMessage: "node down", Retryable: true}},
+	)
+	cashbackID := uuid.New()
+
+	if err := f.usecase.ProcessCashbackApproved(ctx, approved(t, cashbackID, 5)); err != nil {
+		t.Fatal(err)
+	}
+	request := f.request(t, cashbackID)
+	if request.Status != domain.MintRequestStatusFailed || request.ErrorCode != usecase.ErrorCodeAdapterUnavailable ||
+		request.RetryCount != 1 || request.NextRetryAt == nil {
+		t.Fatalf("request after the adapter was down: %+v", request)
+	}
+	if backoff := time.Until(*request.NextRetryAt); backoff < 50*time.Second || backoff > time.Minute {
+		t.Fatalf("first retry in %s, want the retry backoff", backoff)
+	}
+
+	// Not due yet.
+	if err := f.usecase.RetryFailedMints(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.minter.calls) != 1 {
+		t.Fatalf("retried %d times before the retry was due", len(f.minter.calls)-1)
+	}
+
+	f.due(t, cashbackID)
+	if err := f.usecase.RetryFailedMints(ctx); err != nil {
+		t.Fatal(err)
+	}
+	request = f.request(t, cashbackID)
+	if request.ErrorCode != "NOT_BROADCAST" || request.RetryCount != 2 {
+		t.Fatalf("request after the chain failed: %+v", request)
+	}
+	if backoff := time.Until(*request.NextRetryAt); backoff < 110*time.Second || backoff > 2*time.Minute {
+		t.Fatalf("second retry in %s, want twice the retry backoff", backoff)
+	}
+
+	f.due(t, cashbackID)
+	if err := f.usecase.RetryFailedMints(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if request = f.request(t, cashbackID); request.Status != domain.MintRequestStatusCompleted {
+		t.Fatalf("request after the chain recovered is %s", request.Status)
+	}
+	if len(f.minter.calls) != 3 || f.minter.calls[0] != f.minter.calls[2] {
+		t.Fatalf("mints %v, want three with one idempotency key", f.minter.calls)
+	}
+	want := []string{usecase.SubjectTokenMintFailed, usecase.SubjectTokenMintFailed, usecase.SubjectTokenMinted}
+	if len(f.publisher.subjects) != len(want) {
+		t.Fatalf("published %v, want %v", f.publisher.subjects, want)
+	}
+	for i := range want {
+		if f.publisher.subjects[i] != want[i] {
+			t.Fatalf("published %v, want %v", f.publisher.subjects, want)
+		}
+	}
+}
+
+func TestMintFailuresRecordTheAdapterOutcome(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		results   []mintResult
+		wantCode  string
+		wantRetry bool
+	}{
+		{
+			name:     "reverted",
+			results:  []mintResult{{result: &grpc.MintResult{ErrorCode: "TRANSACTION_REVERTED", ErrorMessage: "reverted in block 3"}}},
+			wantCode: "TRANSACTION_REVERTED",
+		},
+		{
+			name:      "not mined yet",
+			results:   []mintResult{{result: &grpc.MintResult{Success: true, TransactionHash: "0xabc"}}},
+			wantCode:  usecase.ErrorCodeNotConfirmed,
+			wantRetry: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(tc.results...)
+			cashbackID := uuid.New()
+			if err := f.usecase.ProcessCashbackApproved(context.Background(), approved(t, cashbackID, 5)); err != nil {
+				t.Fatal(err)
+			}
+			request := f.request(t, cashbackID)
+			if request.Status != domain.MintRequestStatusFailed || request.ErrorCode != tc.wantCode {
+				t.Fatalf("request is %s with code %q, want failed with %q", request.Status, request.ErrorCode, tc.wantCode)
+			}
+			if (request.NextRetryAt != nil) != tc.wantRetry {
+				t.Fatalf("next retry at %v, want a retry %t", request.NextRetryAt, tc.wantRetry)
+			}
+		})
+	}
+}
+
+func TestMintsStopWhenRetriesRunOut(t *testing.T) {
+	ctx := context.Background()
+	down := mintResult{err: errors.New("connection refused")}
+	f := newFixture(down, down, down)
+	cashbackID := uuid.New()
+
+	if err := f.usecase.ProcessCashbackApproved(ctx, approved(t, cashbackID, 5)); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		f.due(t, cashbackID)
+		if err := f.usecase.RetryFailedMints(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := f.request(t, cashbackID)
+	if request.RetryCount != 3 || request.NextRetryAt != nil {
+		t.Fatalf("after %d failures the next retry is at %v", request.RetryCount, request.NextRetryAt)
+	}
+	if err := f.usecase.RetryFailedMints(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.minter.calls) != 3 {
+		t.Fatalf("minted %d times, want 3", len(f.minter.calls))
+	}
+}
+
+func TestAbandonedMintsAreRetried(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture()
+	cashbackID := uuid.New()
+
+	// A consumer stopped mid-mint, leaving the request processing.
+	past := time.Now().Add(-time.Second)
+	request := &domain.MintRequest{
+		ID:             uuid.New(),
+		CashbackID:     cashbackID,
+		UserID:         uuid.New(),
+		WalletAddress:  wallet,
+		TokenAmount:    "5000000000000000000",
+		IdempotencyKey: uuid.New(),
+		Status:         domain.MintRequestStatusProcessing,
+		MaxRetries:     3,
+		NextRetryAt:    &past,
+	}
+	if err := f.requests.Create(ctx, request); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.usecase.RetryFailedMints(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.request(t, cashbackID); got.Status != domain.MintRequestStatusCompleted {
+		t.Fatalf("abandoned request is %s, want completed", got.Status)
+	}
+}
+
+func TestConcurrentRetriesMintOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(mintResult{err: errors.New("connection refused")})
+	cashbackID := uuid.New()
+
+	if err := f.usecase.ProcessCashbackApproved(ctx, approved(t, cashbackID, 5)); err != nil {
+		t.Fatal(err)
+	}
+	f.due(t, cashbackID)
+
+	// Every consumer sees the failed request as due; only one may mint it.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f.usecase.RetryFailedMints(ctx); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(f.minter.calls) != 2 {
+		t.Fatalf("minted %d times, want the failed attempt and one retry", len(f.minter.calls))
+	}
+	if want := []string{usecase.SubjectTokenMintFailed, usecase.SubjectTokenMinted}; len(f.publisher.subjects) != len(want) ||
+		f.publisher.subjects[1] != want[1] {
+		t.Fatalf("published %v, want %v", f.publisher.subjects, want)
+	}
+	if got := f.request(t, cashbackID); got.Status != domain.MintRequestStatusCompleted {
+		t.Fatalf("request is %s, want completed", got.Status)
+	}
+}
+
+func TestReplayedApprovalsAreLeftForTheLiveConsumer(t *testing.T) {
+	f := newFixture()
+	cashbackID := uuid.New()
+
+	if err := f.usecase.ProcessCashbackApproved(replay.WithMode(context.Background()), approved(t, cashbackID, 5)); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.minter.calls) != 0 {
+		t.Fatalf("replay minted %v", f.minter.calls)
+	}
+
+	request := f.request(t, cashbackID)
+	if request.Status != domain.MintRequestStatusFailed || request.ErrorCode != usecase.ErrorCodeReplayDeferred ||
+		request.RetryCount != 0 || request.NextRetryAt == nil || request.NextRetryAt.After(time.Now()) {
+		t.Fatalf("replayed request is %+v, want failed and due without a retry counted", request)
+	}
+	if len(f.publisher.subjects) != 0 {
+		t.Fatalf("replay published %v", f.publisher.subjects)
+	}
+
+	if err := f.usecase.RetryFailedMints(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.request(t, cashbackID); got.Status != domain.MintRequestStatusCompleted {
+		t.Fatalf("deferred request is %s after the live retry, want completed", got.Status)
+	}
+}
+
+func TestReplayedApprovalsHaveNoSideEffects(t *testing.T) {
+	ctx := context.Background()
+	down := mintResult{err: errors.New("connection refused")}
+	f := newFixture(down)
+	minted, failed := uuid.New(), uuid.New()
+
+	for _, cashbackID := range []uuid.UUID{failed, minted} {
+		if err := f.usecase.ProcessCashbackApproved(ctx, approved(t, cashbackID, 5)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := f.request(t, failed)
+	published := len(f.publisher.subjects)
+
+	// Live redeliveries publish token.minted again; replays must not, nor
+	// touch requests the live consumer is retrying.
+	for _, cashbackID := range []uuid.UUID{failed, minted} {
+		if err := f.usecase.ProcessCashbackApproved(replay.WithMode(ctx), approved(t, cashbackID, 5)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(f.minter.calls) != 2 || len(f.publisher.subjects) != published {
+		t.Fatalf("minted %v, published %v", f.minter.calls, f.publisher.subjects[published:])
+	}
+	if after := f.request(t, failed); after.Status != domain.MintRequestStatusFailed || after.ErrorCode != before.ErrorCode ||
+		after.RetryCount != before.RetryCount || !after.NextRetryAt.Equal(*before.NextRetryAt) {
+		t.Fatalf("replay changed the failed request from %+v to %+v", before, after)
+	}
+}
+
+func TestRetryMintMintsAnExhaustedRequestAgain(t *testing.T) {
+	ctx := context.Background()
+	down := mintResult{err: errors.New("connection refused")}
+	f := newFixture(down, down, down)
+	cashbackID := uuid.New()
+
+	if err := f.usecase.ProcessCashbackApproved(ctx, approved(t, cashbackID, 5)); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		f.due(t, cashbackID)
+		if err := f.usecase.RetryFailedMints(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exhausted := f.request(t, cashbackID)
+
+	dryRun, err := f.usecase.RetryMint(ctx, exhausted.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dryRun.MaxRetries != 4 || len(f.minter.calls) != 3 {
+		t.Fatalf("dry run grants %d retries and minted %d times", dryRun.MaxRetries, len(f.minter.calls))
+	}
+	if got := f.request(t, cashbackID); got.MaxRetries != 3 || got.Status != domain.MintRequestStatusFailed {
+		t.Fatalf("dry run changed the request to %+v", got)
+	}
+
+	retried, err := f.usecase.RetryMint(ctx, exhausted.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.Status != domain.MintRequestStatusCompleted || len(f.minter.calls) != 4 {
+		t.Fatalf("retried request is %s after %d mints", retried.Status, len(f.minter.calls))
+	}
+	got := f.request(t, cashbackID)
+	if got.Status != domain.MintRequestStatusCompleted || got.TransactionHash != "0xabc" || got.MaxRetries != 4 {
+		t.Fatalf("stored request is %+v", got)
+	}
+	if f.minter.calls[3] != f.minter.calls[0] {
+		t.Fatalf("retry minted %q, the first attempt %q", f.minter.calls[3], f.minter.calls[0])
+	}
+}
+
+func TestRetryMintRefusesRequestsThatAreNotFailed(t *testing.T) {
+	ctx := context.Background()
+	for _, status := range []domain.MintRequestStatus{
+		domain.MintRequestStatusPending,
+		domain.MintRequestStatusProcessing,
+		domain.MintRequestStatusCompleted,
+		domain.MintRequestStatusExpired,
+	} {
+		t.Run(string(status), func(t *testing.T) {
+			f := newFixture()
+			request := &domain.MintRequest{
+				ID:             uuid.New(),
+				CashbackID:     uuid.New(),
+				UserID:         uuid.New(),
+				WalletAddress:  wallet,
+				TokenAmount:    "1",
+				IdempotencyKey: uuid.New(),
+				Status:         status,
+				MaxRetries:     3,
+			}
+			if err := f.requests.Create(ctx, request); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := f.usecase.RetryMint(ctx, request.ID, false); !errors.Is(err, domain.ErrMintNotRetryable) {
+				t.Fatalf("retrying a %s request: %v, want ErrMintNotRetryable", status, err)
+			}
+			if len(f.minter.calls) != 0 {
+				t.Fatalf("minted %v", f.minter.calls)
+			}
+		})
+	}
+
+	f := newFixture()
+	if _, err := f.usecase.RetryMint(ctx, uuid.New(), false); !errors.Is(err, domain.ErrMintRequestNotFound) {
+		t.Fatalf("retrying a missing request: %v", err)
+	}
+}
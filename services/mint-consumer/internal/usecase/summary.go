@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/mint-consumer/internal/domain"
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+)
+
+// SummaryUsecase projects cashback events into the user cashback summary.
+// Projecting an event has no effect outside the summary, so its handlers run
+// the same live and in replay.
+type SummaryUsecase struct {
+	summaryRepo repository.CashbackSummaryRepository
+	log         *slog.Logger
+}
+
+func NewSummaryUsecase(summaryRepo repository.CashbackSummaryRepository, log *slog.Logger) *SummaryUsecase {
+	return &SummaryUsecase{
+		summaryRepo: summaryRepo,
+		log:         log,
+	}
+}
+
+// ProjectCashbackApproved adds approved cashback to its user's summary.
+func (u SummaryUsecase) ProjectCashbackApproved(ctx context.Context, data []byte) error {
+	var event domain.CashbackApprovedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode cashback.approved event: %w", err)
+	}
+	ctx = logger.WithCashbackID(ctx, event.CashbackID.String())
+
+	if err := u.summaryRepo.RecordApproved(ctx, event.CashbackID, event.UserID, event.Amount, time.Now().UTC()); err != nil {
+		return err
+	}
+	u.log.DebugContext(ctx, "cashback summary updated", "event", "cashback.approved")
+	return nil
+}
+
+// ProjectCashbackExpired moves expired cashback to the expired totals.
+func (u SummaryUsecase) ProjectCashbackExpired(ctx context.Context, data []byte) error {
+	var event domain.CashbackExpiredEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to decode cashback.expired event: %w", err)
+	}
+	ctx = logger.WithCashbackID(ctx, event.CashbackID.String())
+
+	if err := u.summaryRepo.RecordExpired(ctx, event.CashbackID, event.UserID, event.Amount, event.ExpiredAt); err != nil {
+		return err
+	}
+	u.log.DebugContext(ctx, "cashback summary updated", "event", "cashback.expired")
+	return nil
+}
+
+// Reset empties the summary so a replay can rebuild it from scratch.
+func (u SummaryUsecase) Reset(ctx context.Context) error {
+	return u.summaryRepo.Reset(ctx)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cashback-platform/pkg/health"
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/consumer"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/grpc"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/nats"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// newHealthChecker bounds each readiness check by HEALTH_CHECK_TIMEOUT.
+func newHealthChecker(cfg *config.Config, log *slog.Logger) *health.Checker {
+	return health.NewChecker(cfg.Health.CheckTimeout, log)
+}
+
+// registerHealthChecks adds a readiness check for each dependency of the
+// consumer.
+func registerHealthChecks(checker *health.Checker, db *gorm.DB, natsClient *nats.NATSClient, adapter *grpc.BlockchainAdapterClient) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	checker.Add("postgres", sqlDB.PingContext)
+	checker.Add("nats", natsClient.CheckConnection)
+	checker.Add("jetstream", func(ctx context.Context) error {
+		return natsClient.CheckStreams(ctx, consumer.CashbackStream, consumer.UserStream)
+	})
+	checker.Add("blockchain_adapter", adapter.CheckChannel)
+	return nil
+}
+
+// startHealthServer serves the probes on HEALTH_PORT, next to the metrics
+// server, since the consumer has no HTTP API of its own. On shutdown,
+// readiness fails first and stays failing for HEALTH_SHUTDOWN_DELAY before the
+// server stops. Stop hooks run in reverse, so this must be the last invoke for
+// that to happen before the consumer stops fetching.
+func startHealthServer(lc fx.Lifecycle, checker *health.Checker, cfg *config.Config, log *slog.Logger) {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.Health.Port),
+		Handler:           checker.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				log.Info("health server starting", "port", cfg.Health.Port)
+				if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Error("health server failed", "error", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			checker.DrainFor(ctx, cfg.Health.ShutdownDelay)
+			return server.Shutdown(ctx)
+		},
+	})
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/cashback-platform/pkg/logger"
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+)
+
+const serviceName = "mint-consumer"
+
+// NewLogger builds the logger at LOG_LEVEL and installs it as the slog and log
+// default, so output from libraries lands in the same stream.
+func NewLogger(cfg *config.Config) *slog.Logger {
+	l := logger.New(os.Stdout, cfg.Log.Level, serviceName)
+	slog.SetDefault(l)
+	return l
+}
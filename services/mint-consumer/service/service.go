@@ -0,0 +1,94 @@
+// Package service assembles mint-consumer. The binary runs it on its own; the
+// end-to-end harness boots it in-process next to the other services.
+package service
+
+import (
+	"context"
+
+	"github.com/cashback-platform/services/mint-consumer/internal/config"
+	"github.com/cashback-platform/services/mint-consumer/internal/consumer"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/database"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/grpc"
+	"github.com/cashback-platform/services/mint-consumer/internal/infra/nats"
+	"github.com/cashback-platform/services/mint-consumer/internal/metrics"
+	"github.com/cashback-platform/services/mint-consumer/internal/repository"
+	repoMintRequest "github.com/cashback-platform/services/mint-consumer/internal/repository/mintrequest"
+	repoProcessedEvent "github.com/cashback-platform/services/mint-consumer/internal/repository/processedevent"
+	"github.com/cashback-platform/services/mint-consumer/internal/tracing"
+	"github.com/cashback-platform/services/mint-consumer/internal/usecase"
+	"go.uber.org/fx"
+)
+
+// Module is the whole consumer. Configuration is read from the environment
+// while the app is built, by fx.New.
+var Module = fx.Options(
+	// Configuration
+	fx.Provide(config.NewConfig),
+
+	// Logging
+	fx.Provide(NewLogger),
+
+	// Tracing
+	fx.Invoke(tracing.Setup),
+
+	// Metrics
+	fx.Provide(metrics.NewRegistry),
+	fx.Provide(metrics.NewGRPCClient),
+	fx.Provide(metrics.NewConsumer),
+	fx.Invoke(metrics.RegisterMintRequests),
+	fx.Invoke(metrics.StartServer),
+
+	// Infrastructure
+	fx.Provide(database.NewPostgresDB),
+	fx.Provide(nats.NewNATSClient),
+	fx.Provide(grpc.NewBlockchainAdapterClient),
+
+	// Repositories
+	fx.Provide(repoMintRequest.NewRepository),
+	fx.Provide(repoProcessedEvent.NewRepository),
+	fx.Provide(repository.NewMintRequestRepository),
+	fx.Provide(repository.NewCashbackSummaryRepository),
+
+	// Usecases
+	fx.Provide(
+		func(client *grpc.BlockchainAdapterClient) usecase.Minter { return client },
+		func(client *nats.NATSClient) usecase.EventPublisher { return client },
+	),
+	fx.Provide(usecase.NewMintUsecase),
+	fx.Provide(usecase.NewSummaryUsecase),
+
+	// Consumer
+	fx.Provide(consumer.NewCashbackConsumer),
+
+	// Start consumer
+	fx.Invoke(consumer.StartConsumer),
+
+	// Health, last so readiness fails before anything stops
+	fx.Provide(newHealthChecker),
+	fx.Invoke(registerHealthChecks),
+	fx.Invoke(startHealthServer),
+)
+
+// Migrate applies every pending migration to the database in the environment
+// and returns how many it applied.
+func Migrate(ctx context.Context) (int, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return 0, err
+	}
+	db, err := database.Connect(cfg, NewLogger(cfg))
+	if err != nil {
+		return 0, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, err
+	}
+	defer sqlDB.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return 0, err
+	}
+	return migrator.Up(ctx)
+}